@@ -29,24 +29,27 @@ func NewAnthropicProvider(config *model.AIProviderConfig) AIProvider {
 
 	// Initialize Anthropic SDK client
 	// NewClient doesn't return an error - it reads from environment or uses provided options
-	client := anthropic.NewClient(
-		option.WithAPIKey(config.APIKey),
-	)
+	opts := []option.RequestOption{option.WithAPIKey(config.APIKey)}
+	// gitcomm retries transient errors itself (retry_attempts): one try per attempt
+	if config.RetryAttempts > 1 {
+		opts = append(opts, option.WithMaxRetries(0))
+	}
+	// A custom endpoint is the base URL of a compatible gateway or proxy
+	if config.Endpoint != "" {
+		opts = append(opts, option.WithBaseURL(config.Endpoint))
+	}
+	client := anthropic.NewClient(opts...)
 
 	return &AnthropicProvider{
 		config:    config,
 		client:    client,
-		generator: prompt.NewUnifiedPromptGenerator(),
+		generator: prompt.NewUnifiedPromptGeneratorWithLayout(config.Prompt),
 		validator: conventional.NewValidator(),
 	}
 }
 
 // GenerateCommitMessage generates a commit message using Anthropic
 func (p *AnthropicProvider) GenerateCommitMessage(ctx context.Context, repoState *model.RepositoryState) (string, error) {
-	if p.config.APIKey == "" {
-		return "", fmt.Errorf("%w: Anthropic API key not configured", utils.ErrAIProviderUnavailable)
-	}
-
 	// Generate unified system and user messages
 	systemMsg, err := p.generator.GenerateSystemMessage(p.validator)
 	if err != nil {
@@ -58,13 +61,13 @@ func (p *AnthropicProvider) GenerateCommitMessage(ctx context.Context, repoState
 		return "", fmt.Errorf("failed to generate user message: %w", err)
 	}
 
-	// Anthropic doesn't support system messages, so prepend system to user message
-	combinedMsg := systemMsg + "\n\n" + userMsg
+	return CompleteRecorded(ctx, p, systemMsg, userMsg)
+}
 
-	// Prepare model
-	modelName := p.config.Model
-	if modelName == "" {
-		modelName = "claude-3-opus-20240229"
+// Complete sends a system and user message to Anthropic and returns the answer
+func (p *AnthropicProvider) Complete(ctx context.Context, systemMsg, userMsg string) (string, error) {
+	if p.config.APIKey == "" {
+		return "", fmt.Errorf("%w: Anthropic API key not configured", utils.ErrAIProviderUnavailable)
 	}
 
 	maxTokens := p.config.MaxTokens
@@ -74,19 +77,8 @@ func (p *AnthropicProvider) GenerateCommitMessage(ctx context.Context, repoState
 
 	// Create message request using SDK
 	req := anthropic.MessageNewParams{
-		Model: anthropic.Model(modelName),
-		Messages: []anthropic.MessageParam{
-			{
-				Role: anthropic.MessageParamRoleUser,
-				Content: []anthropic.ContentBlockParamUnion{
-					{
-						OfText: &anthropic.TextBlockParam{
-							Text: combinedMsg,
-						},
-					},
-				},
-			},
-		},
+		Model:     p.model(),
+		Messages:  messages(systemMsg, userMsg),
 		MaxTokens: int64(maxTokens),
 	}
 
@@ -113,6 +105,56 @@ func (p *AnthropicProvider) GenerateCommitMessage(ctx context.Context, repoState
 	return content, nil
 }
 
+// CountTokens counts the tokens of the commit message request for repoState with
+// Anthropic's token counting endpoint, exactly as the model tokenizes it
+func (p *AnthropicProvider) CountTokens(ctx context.Context, repoState *model.RepositoryState) (int, error) {
+	if p.config.APIKey == "" {
+		return 0, fmt.Errorf("%w: Anthropic API key not configured", utils.ErrAIProviderUnavailable)
+	}
+	systemMsg, err := p.generator.GenerateSystemMessage(p.validator)
+	if err != nil {
+		return 0, fmt.Errorf("failed to generate system message: %w", err)
+	}
+	userMsg, err := p.generator.GenerateUserMessage(repoState)
+	if err != nil {
+		return 0, fmt.Errorf("failed to generate user message: %w", err)
+	}
+
+	count, err := p.client.Messages.CountTokens(ctx, anthropic.MessageCountTokensParams{
+		Model:    p.model(),
+		Messages: messages(systemMsg, userMsg),
+	})
+	if err != nil {
+		return 0, p.mapSDKError(err)
+	}
+	return int(count.InputTokens), nil
+}
+
+// model returns the configured model, or the default one
+func (p *AnthropicProvider) model() anthropic.Model {
+	if p.config.Model == "" {
+		return "claude-3-opus-20240229"
+	}
+	return anthropic.Model(p.config.Model)
+}
+
+// messages returns the messages of a request: Anthropic doesn't support system messages,
+// so the system message is prepended to the user message
+func messages(systemMsg, userMsg string) []anthropic.MessageParam {
+	return []anthropic.MessageParam{
+		{
+			Role: anthropic.MessageParamRoleUser,
+			Content: []anthropic.ContentBlockParamUnion{
+				{
+					OfText: &anthropic.TextBlockParam{
+						Text: systemMsg + "\n\n" + userMsg,
+					},
+				},
+			},
+		},
+	}
+}
+
 // mapSDKError maps SDK-specific errors to existing error types
 func (p *AnthropicProvider) mapSDKError(err error) error {
 	// Check for authentication errors
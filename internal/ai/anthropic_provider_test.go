@@ -2,7 +2,11 @@ package ai
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -227,3 +231,74 @@ func TestAnthropicProvider_ValidateConfig(t *testing.T) {
 		})
 	}
 }
+
+// TestAnthropicProvider_CountTokens tests counting tokens with the count_tokens endpoint
+func TestAnthropicProvider_CountTokens(t *testing.T) {
+	var body struct {
+		Model    string `json:"model"`
+		Messages []struct {
+			Content []struct {
+				Text string `json:"text"`
+			} `json:"content"`
+		} `json:"messages"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/messages/count_tokens" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"input_tokens":1234}`))
+	}))
+	defer server.Close()
+
+	state := &model.RepositoryState{
+		StagedFiles: []model.FileChange{
+			{Path: "api.go", Status: "modified", Diff: "+func NewEndpoint() {}"},
+		},
+	}
+	provider := NewAnthropicProvider(&model.AIProviderConfig{
+		Name:     "anthropic",
+		APIKey:   "sk-ant-test",
+		Endpoint: server.URL,
+	})
+	counter, ok := provider.(TokenCounter)
+	if !ok {
+		t.Fatal("Anthropic provider should implement TokenCounter")
+	}
+	count, err := counter.CountTokens(context.Background(), state)
+	if err != nil {
+		t.Fatalf("CountTokens() error = %v", err)
+	}
+	if count != 1234 {
+		t.Errorf("CountTokens() = %d, want 1234", count)
+	}
+	if body.Model != "claude-3-opus-20240229" {
+		t.Errorf("request model = %q, want the default model", body.Model)
+	}
+	if len(body.Messages) != 1 || len(body.Messages[0].Content) != 1 || !strings.Contains(body.Messages[0].Content[0].Text, "api.go") {
+		t.Errorf("request should hold the commit message request, got %+v", body.Messages)
+	}
+
+	counter = NewAnthropicProvider(&model.AIProviderConfig{Name: "anthropic", Endpoint: server.URL}).(TokenCounter)
+	_, err = counter.CountTokens(context.Background(), state)
+	if !utils.IsError(err, utils.ErrAIProviderUnavailable) {
+		t.Errorf("CountTokens() without API key error = %v, want ErrAIProviderUnavailable", err)
+	}
+}
+
+// TestCountsTokens tests that token counting is found behind the limits, retries and circuit breaker
+func TestCountsTokens(t *testing.T) {
+	config := &model.AIProviderConfig{Name: "counts-tokens", APIKey: "test", RetryAttempts: 2, MaxConcurrent: 1, CircuitThreshold: 1, CircuitCooldown: time.Minute}
+	wrap := func(provider AIProvider) AIProvider {
+		return WithCircuitBreaker(WithRetry(WithLimits(provider, config), config), config)
+	}
+	if !CountsTokens(wrap(NewAnthropicProvider(config))) {
+		t.Error("wrapped Anthropic provider should count tokens")
+	}
+	if CountsTokens(wrap(NewOpenAIProvider(config))) {
+		t.Error("wrapped OpenAI provider should not count tokens")
+	}
+}
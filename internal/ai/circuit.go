@@ -0,0 +1,160 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/utils"
+)
+
+var (
+	breakersMu sync.Mutex
+	// breakers are shared by every provider instance of the process with the same
+	// credentials (see credentialsOf) and settings, so the failures of one workflow step
+	// spare the next ones the wait. Settings changed by a config reload start from a closed
+	// circuit.
+	breakers = make(map[breakerKey]*CircuitBreaker)
+)
+
+// breakerKey identifies the circuit breaker of a provider
+type breakerKey struct {
+	credentials credentials
+	threshold   int
+	cooldown    time.Duration
+}
+
+// CircuitBreaker stops calling a provider after threshold consecutive failures: the circuit
+// opens and calls fail immediately until cooldown has elapsed. The next call is then tried;
+// a success closes the circuit, a failure opens it again for another cooldown.
+type CircuitBreaker struct {
+	name      string
+	threshold int
+	cooldown  time.Duration
+
+	mu       sync.Mutex
+	failures int       // consecutive failures
+	openedAt time.Time // when the circuit last opened
+	lastErr  error     // last failure, reported while the circuit is open
+	now      func() time.Time
+}
+
+// NewCircuitBreaker creates a circuit breaker for the provider name opening after
+// threshold consecutive failures for cooldown
+func NewCircuitBreaker(name string, threshold int, cooldown time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		name:      name,
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       time.Now,
+	}
+}
+
+// Allow returns an ErrCircuitOpen error while the circuit is open, nil when a call may be made
+func (b *CircuitBreaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failures < b.threshold {
+		return nil
+	}
+	remaining := b.openedAt.Add(b.cooldown).Sub(b.now())
+	if remaining <= 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %s failed %d times in a row, next attempt in %s: %w",
+		utils.ErrCircuitOpen, b.name, b.failures, remaining.Round(time.Second), b.lastErr)
+}
+
+// Record updates the circuit with the outcome of a call. Calls cancelled by the user
+// are not failures of the provider.
+func (b *CircuitBreaker) Record(err error) {
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err == nil {
+		b.failures = 0
+		b.lastErr = nil
+		return
+	}
+	b.failures++
+	b.lastErr = err
+	if b.failures >= b.threshold {
+		b.openedAt = b.now()
+		utils.Logger.Debug().Str("provider", b.name).Int("failures", b.failures).Dur("cooldown", b.cooldown).Msg("Provider circuit opened")
+	}
+}
+
+// sharedBreaker returns the process-wide circuit breaker of a provider, or nil when disabled
+func sharedBreaker(config *model.AIProviderConfig) *CircuitBreaker {
+	if config.CircuitThreshold <= 0 || config.CircuitCooldown <= 0 {
+		return nil
+	}
+	breakersMu.Lock()
+	defer breakersMu.Unlock()
+
+	key := breakerKey{credentials: credentialsOf(config), threshold: config.CircuitThreshold, cooldown: config.CircuitCooldown}
+	if b, ok := breakers[key]; ok {
+		return b
+	}
+	b := NewCircuitBreaker(config.Name, config.CircuitThreshold, config.CircuitCooldown)
+	breakers[key] = b
+	return b
+}
+
+// breakerProvider applies a CircuitBreaker to every call of the wrapped provider
+type breakerProvider struct {
+	provider AIProvider
+	breaker  *CircuitBreaker
+}
+
+// WithCircuitBreaker wraps provider with the circuit breaker of config (circuit_threshold,
+// circuit_cooldown). Without a threshold the provider is returned unchanged.
+func WithCircuitBreaker(provider AIProvider, config *model.AIProviderConfig) AIProvider {
+	breaker := sharedBreaker(config)
+	if breaker == nil {
+		return provider
+	}
+	return &breakerProvider{provider: provider, breaker: breaker}
+}
+
+// GenerateCommitMessage generates a commit message unless the circuit is open
+func (p *breakerProvider) GenerateCommitMessage(ctx context.Context, repoState *model.RepositoryState) (string, error) {
+	if err := p.breaker.Allow(); err != nil {
+		return "", err
+	}
+	message, err := p.provider.GenerateCommitMessage(ctx, repoState)
+	p.breaker.Record(err)
+	return message, err
+}
+
+// Complete sends the messages unless the circuit is open
+func (p *breakerProvider) Complete(ctx context.Context, systemMsg, userMsg string) (string, error) {
+	if err := p.breaker.Allow(); err != nil {
+		return "", err
+	}
+	answer, err := p.provider.Complete(ctx, systemMsg, userMsg)
+	p.breaker.Record(err)
+	return answer, err
+}
+
+// CountTokens counts the tokens of the request unless the circuit is open
+func (p *breakerProvider) CountTokens(ctx context.Context, repoState *model.RepositoryState) (int, error) {
+	if err := p.breaker.Allow(); err != nil {
+		return 0, err
+	}
+	tokens, err := countTokens(ctx, p.provider, repoState)
+	p.breaker.Record(err)
+	return tokens, err
+}
+
+// Unwrap returns the provider behind the circuit breaker
+func (p *breakerProvider) Unwrap() AIProvider {
+	return p.provider
+}
@@ -0,0 +1,109 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/utils"
+	"github.com/golgoth31/gitcomm/test/mocks"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	timeout := errors.New("timeout")
+
+	calls := 0
+	provider := &mocks.MockAIProvider{CompleteFunc: func(ctx context.Context, systemMsg, userMsg string) (string, error) {
+		calls++
+		if userMsg == "fail" {
+			return "", timeout
+		}
+		return "ok", nil
+	}}
+	breaker := NewCircuitBreaker("flaky", 2, time.Minute)
+	breaker.now = func() time.Time { return now }
+	wrapped := &breakerProvider{provider: provider, breaker: breaker}
+
+	steps := []struct {
+		name      string
+		userMsg   string
+		advance   time.Duration
+		wantCalls int
+		wantErr   error
+	}{
+		{"first failure", "fail", 0, 1, timeout},
+		{"success resets the count", "ok", 0, 2, nil},
+		{"failure", "fail", 0, 3, timeout},
+		{"threshold reached", "fail", 0, 4, timeout},
+		{"open circuit skips the provider", "ok", 30 * time.Second, 4, utils.ErrCircuitOpen},
+		{"open circuit reports the last failure", "ok", 0, 4, timeout},
+		{"trial after the cooldown fails", "fail", 30 * time.Second, 5, timeout},
+		{"circuit open again", "ok", 59 * time.Second, 5, utils.ErrCircuitOpen},
+		{"trial after the cooldown succeeds", "ok", time.Second, 6, nil},
+		{"circuit closed", "ok", 0, 7, nil},
+	}
+	for _, step := range steps {
+		now = now.Add(step.advance)
+		_, err := wrapped.Complete(context.Background(), "system", step.userMsg)
+		if (step.wantErr == nil && err != nil) || !errors.Is(err, step.wantErr) {
+			t.Errorf("%s: Complete() error = %v, want %v", step.name, err, step.wantErr)
+		}
+		if calls != step.wantCalls {
+			t.Errorf("%s: provider called %d times, want %d", step.name, calls, step.wantCalls)
+		}
+	}
+}
+
+func TestCircuitBreaker_IgnoresCancellation(t *testing.T) {
+	breaker := NewCircuitBreaker("cancelled", 1, time.Minute)
+	breaker.Record(context.Canceled)
+	if err := breaker.Allow(); err != nil {
+		t.Errorf("Allow() error = %v after a cancelled call, want nil", err)
+	}
+	breaker.Record(context.DeadlineExceeded)
+	if err := breaker.Allow(); !errors.Is(err, utils.ErrCircuitOpen) {
+		t.Errorf("Allow() error = %v after a timeout, want ErrCircuitOpen", err)
+	}
+}
+
+func TestWithCircuitBreaker(t *testing.T) {
+	provider := &mocks.MockAIProvider{}
+
+	if got := WithCircuitBreaker(provider, &model.AIProviderConfig{Name: "no-breaker"}); got != AIProvider(provider) {
+		t.Error("WithCircuitBreaker() wrapped a provider without threshold")
+	}
+
+	config := &model.AIProviderConfig{Name: "shared-breaker", CircuitThreshold: 3, CircuitCooldown: time.Minute}
+	first, ok := WithCircuitBreaker(provider, config).(*breakerProvider)
+	if !ok {
+		t.Fatal("WithCircuitBreaker() did not wrap a provider with a threshold")
+	}
+	second := WithCircuitBreaker(provider, config).(*breakerProvider)
+	if first.breaker != second.breaker {
+		t.Error("providers with the same name do not share their circuit breaker")
+	}
+
+	// A config reload changing the settings applies to the next provider, with a closed circuit
+	for range 3 {
+		first.breaker.Record(errors.New("unavailable"))
+	}
+	if first.breaker.Allow() == nil {
+		t.Fatal("circuit should be open after 3 failures")
+	}
+	reloaded := WithCircuitBreaker(provider, &model.AIProviderConfig{Name: "shared-breaker", CircuitThreshold: 5, CircuitCooldown: time.Minute}).(*breakerProvider)
+	if reloaded.breaker.threshold != 5 {
+		t.Errorf("changed threshold was ignored: threshold = %d, want 5", reloaded.breaker.threshold)
+	}
+	if err := reloaded.breaker.Allow(); err != nil {
+		t.Errorf("reloaded circuit should be closed, got %v", err)
+	}
+
+	// The failures of one account do not open the circuit of another
+	work := WithCircuitBreaker(provider, &model.AIProviderConfig{Name: "shared-breaker", CircuitThreshold: 3, CircuitCooldown: time.Minute, Account: "work", APIKey: "sk-work"}).(*breakerProvider)
+	if err := work.breaker.Allow(); err != nil {
+		t.Errorf("circuit of another account should be closed, got %v", err)
+	}
+}
@@ -0,0 +1,47 @@
+package ai
+
+import (
+	"context"
+	"sync"
+)
+
+// Exchange is a commit message request sent to a provider and its raw answer
+type Exchange struct {
+	System   string `json:"system"`
+	User     string `json:"user"`
+	Response string `json:"response"`
+}
+
+// ExchangeLog collects the exchanges made with a context returned by WithExchangeLog
+type ExchangeLog struct {
+	mu        sync.Mutex
+	exchanges []Exchange
+}
+
+// Exchanges returns the exchanges recorded so far, oldest first
+func (l *ExchangeLog) Exchanges() []Exchange {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]Exchange(nil), l.exchanges...)
+}
+
+// exchangeLogKey is the context key of the exchange log
+type exchangeLogKey struct{}
+
+// WithExchangeLog returns a context recording in log the successful commit message
+// requests made by GenerateCommitMessage and CompleteRecorded
+func WithExchangeLog(ctx context.Context, log *ExchangeLog) context.Context {
+	return context.WithValue(ctx, exchangeLogKey{}, log)
+}
+
+// CompleteRecorded sends a request made for the commit message to provider, recording it
+// in the exchange log of ctx when it succeeds
+func CompleteRecorded(ctx context.Context, provider AIProvider, systemMsg, userMsg string) (string, error) {
+	answer, err := provider.Complete(ctx, systemMsg, userMsg)
+	if log, ok := ctx.Value(exchangeLogKey{}).(*ExchangeLog); ok && err == nil {
+		log.mu.Lock()
+		log.exchanges = append(log.exchanges, Exchange{System: systemMsg, User: userMsg, Response: answer})
+		log.mu.Unlock()
+	}
+	return answer, err
+}
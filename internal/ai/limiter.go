@@ -0,0 +1,191 @@
+package ai
+
+import (
+	"context"
+	"crypto/sha256"
+	"sync"
+	"time"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/utils"
+)
+
+// rateWindow is the window of the requests-per-minute limit
+const rateWindow = time.Minute
+
+var (
+	limitersMu sync.Mutex
+	// limiters are shared by every provider instance of the process with the same
+	// credentials (see credentialsOf) and limits, so parallel batch work on a shared API key
+	// respects a single budget, and limits changed by a config reload apply to the next request
+	limiters = make(map[limiterKey]*Limiter)
+)
+
+// limiterKey identifies the limiter of a provider
+type limiterKey struct {
+	credentials       credentials
+	maxConcurrent     int
+	requestsPerMinute int
+}
+
+// Limiter caps the number of concurrent provider calls and the number of calls per minute.
+// A zero limit disables the corresponding check.
+type Limiter struct {
+	slots chan struct{}
+
+	mu         sync.Mutex
+	perMinute  int
+	recent     []time.Time // start times of the calls in the current window, oldest first
+	now        func() time.Time
+	afterDelay func(time.Duration) <-chan time.Time
+}
+
+// NewLimiter creates a limiter allowing maxConcurrent calls at once and requestsPerMinute calls per minute
+func NewLimiter(maxConcurrent, requestsPerMinute int) *Limiter {
+	l := &Limiter{
+		perMinute:  requestsPerMinute,
+		now:        time.Now,
+		afterDelay: time.After,
+	}
+	if maxConcurrent > 0 {
+		l.slots = make(chan struct{}, maxConcurrent)
+	}
+	return l
+}
+
+// Acquire blocks until a call is allowed or ctx is done. The returned function releases the call.
+func (l *Limiter) Acquire(ctx context.Context) (func(), error) {
+	if l.slots != nil {
+		select {
+		case l.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	release := func() {
+		if l.slots != nil {
+			<-l.slots
+		}
+	}
+
+	for {
+		wait := l.reserve()
+		if wait <= 0 {
+			return release, nil
+		}
+		utils.Logger.Debug().Dur("wait", wait).Msg("Provider rate limit reached, waiting")
+		select {
+		case <-l.afterDelay(wait):
+		case <-ctx.Done():
+			release()
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// reserve records a call and returns 0 when the per-minute budget allows it,
+// or the time until the oldest call of the window expires
+func (l *Limiter) reserve() time.Duration {
+	if l.perMinute <= 0 {
+		return 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	for len(l.recent) > 0 && now.Sub(l.recent[0]) >= rateWindow {
+		l.recent = l.recent[1:]
+	}
+	if len(l.recent) >= l.perMinute {
+		return l.recent[0].Add(rateWindow).Sub(now)
+	}
+	l.recent = append(l.recent, now)
+	return 0
+}
+
+// credentials identifies the account a provider bills its calls to: accounts of the same
+// provider do not share their budget nor their circuit breaker
+type credentials struct {
+	name     string
+	account  string
+	endpoint string
+	apiKey   [sha256.Size]byte // hash of the API key, so the registries hold no key
+}
+
+// credentialsOf returns the credentials of config
+func credentialsOf(config *model.AIProviderConfig) credentials {
+	return credentials{
+		name:     config.Name,
+		account:  config.Account,
+		endpoint: config.Endpoint,
+		apiKey:   sha256.Sum256([]byte(config.APIKey)),
+	}
+}
+
+// sharedLimiter returns the process-wide limiter of a provider, or nil when it has no limits
+func sharedLimiter(config *model.AIProviderConfig) *Limiter {
+	if config.MaxConcurrent <= 0 && config.RequestsPerMinute <= 0 {
+		return nil
+	}
+	limitersMu.Lock()
+	defer limitersMu.Unlock()
+
+	key := limiterKey{credentials: credentialsOf(config), maxConcurrent: config.MaxConcurrent, requestsPerMinute: config.RequestsPerMinute}
+	if l, ok := limiters[key]; ok {
+		return l
+	}
+	l := NewLimiter(config.MaxConcurrent, config.RequestsPerMinute)
+	limiters[key] = l
+	return l
+}
+
+// limitedProvider applies a Limiter to every call of the wrapped provider
+type limitedProvider struct {
+	provider AIProvider
+	limiter  *Limiter
+}
+
+// WithLimits wraps provider with the concurrency and rate limits of config
+// (max_concurrent, requests_per_minute). Without limits the provider is returned unchanged.
+func WithLimits(provider AIProvider, config *model.AIProviderConfig) AIProvider {
+	limiter := sharedLimiter(config)
+	if limiter == nil {
+		return provider
+	}
+	return &limitedProvider{provider: provider, limiter: limiter}
+}
+
+// GenerateCommitMessage generates a commit message once the limiter allows the call
+func (p *limitedProvider) GenerateCommitMessage(ctx context.Context, repoState *model.RepositoryState) (string, error) {
+	release, err := p.limiter.Acquire(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer release()
+	return p.provider.GenerateCommitMessage(ctx, repoState)
+}
+
+// Complete sends the messages once the limiter allows the call
+func (p *limitedProvider) Complete(ctx context.Context, systemMsg, userMsg string) (string, error) {
+	release, err := p.limiter.Acquire(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer release()
+	return p.provider.Complete(ctx, systemMsg, userMsg)
+}
+
+// CountTokens counts the tokens of the request once the limiter allows the call
+func (p *limitedProvider) CountTokens(ctx context.Context, repoState *model.RepositoryState) (int, error) {
+	release, err := p.limiter.Acquire(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer release()
+	return countTokens(ctx, p.provider, repoState)
+}
+
+// Unwrap returns the limited provider
+func (p *limitedProvider) Unwrap() AIProvider {
+	return p.provider
+}
@@ -0,0 +1,127 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/test/mocks"
+)
+
+func TestLimiter_MaxConcurrent(t *testing.T) {
+	limiter := NewLimiter(2, 0)
+
+	var running, peak atomic.Int32
+	var wg sync.WaitGroup
+	for range 6 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			release, err := limiter.Acquire(context.Background())
+			if err != nil {
+				t.Errorf("Acquire() error = %v", err)
+				return
+			}
+			defer release()
+
+			n := running.Add(1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			running.Add(-1)
+		}()
+	}
+	wg.Wait()
+
+	if got := peak.Load(); got > 2 {
+		t.Errorf("peak concurrent calls = %d, want at most 2", got)
+	}
+}
+
+func TestLimiter_RequestsPerMinute(t *testing.T) {
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	now := start
+	var waits []time.Duration
+
+	limiter := NewLimiter(0, 2)
+	limiter.now = func() time.Time { return now }
+	limiter.afterDelay = func(d time.Duration) <-chan time.Time {
+		waits = append(waits, d)
+		now = now.Add(d)
+		ch := make(chan time.Time, 1)
+		ch <- now
+		return ch
+	}
+
+	for range 3 {
+		release, err := limiter.Acquire(context.Background())
+		if err != nil {
+			t.Fatalf("Acquire() error = %v", err)
+		}
+		release()
+		now = now.Add(10 * time.Second)
+	}
+
+	// The third call waits until the first one leaves the one-minute window
+	if len(waits) != 1 || waits[0] != 40*time.Second {
+		t.Errorf("waits = %v, want [40s]", waits)
+	}
+}
+
+func TestLimiter_AcquireCancelled(t *testing.T) {
+	limiter := NewLimiter(1, 0)
+	release, err := limiter.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+	defer release()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := limiter.Acquire(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Acquire() error = %v, want context.Canceled", err)
+	}
+}
+
+func TestWithLimits(t *testing.T) {
+	provider := &mocks.MockAIProvider{}
+
+	if got := WithLimits(provider, &model.AIProviderConfig{Name: "unlimited"}); got != AIProvider(provider) {
+		t.Error("WithLimits() wrapped a provider without limits")
+	}
+
+	config := &model.AIProviderConfig{Name: "shared-key", MaxConcurrent: 1}
+	first, ok := WithLimits(provider, config).(*limitedProvider)
+	if !ok {
+		t.Fatal("WithLimits() did not wrap a provider with limits")
+	}
+	second := WithLimits(provider, config).(*limitedProvider)
+	if first.limiter != second.limiter {
+		t.Error("providers with the same name do not share their limiter")
+	}
+
+	// A config reload changing the limits applies to the next provider
+	reloaded := WithLimits(provider, &model.AIProviderConfig{Name: "shared-key", MaxConcurrent: 1, RequestsPerMinute: 10}).(*limitedProvider)
+	if reloaded.limiter == first.limiter || reloaded.limiter.perMinute != 10 {
+		t.Errorf("changed limits were ignored: requests per minute = %d, want 10", reloaded.limiter.perMinute)
+	}
+
+	// Each account of the provider has its own budget
+	for _, other := range []*model.AIProviderConfig{
+		{Name: "shared-key", MaxConcurrent: 1, Account: "work", APIKey: "sk-work"},
+		{Name: "shared-key", MaxConcurrent: 1, APIKey: "sk-other"},
+		{Name: "shared-key", MaxConcurrent: 1, Endpoint: "https://eu.example.org"},
+	} {
+		if WithLimits(provider, other).(*limitedProvider).limiter == first.limiter {
+			t.Errorf("provider with other credentials %+v shares the limiter", other)
+		}
+	}
+}
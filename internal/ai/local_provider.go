@@ -37,17 +37,13 @@ func NewLocalProvider(config *model.AIProviderConfig) AIProvider {
 	return &LocalProvider{
 		config:    config,
 		client:    &http.Client{Timeout: timeout},
-		generator: prompt.NewUnifiedPromptGenerator(),
+		generator: prompt.NewUnifiedPromptGeneratorWithLayout(config.Prompt),
 		validator: conventional.NewValidator(),
 	}
 }
 
 // GenerateCommitMessage generates a commit message using a local model
 func (p *LocalProvider) GenerateCommitMessage(ctx context.Context, repoState *model.RepositoryState) (string, error) {
-	if p.config.Endpoint == "" {
-		return "", fmt.Errorf("%w: local provider endpoint not configured", utils.ErrAIProviderUnavailable)
-	}
-
 	// Generate unified system and user messages
 	systemMsg, err := p.generator.GenerateSystemMessage(p.validator)
 	if err != nil {
@@ -59,6 +55,15 @@ func (p *LocalProvider) GenerateCommitMessage(ctx context.Context, repoState *mo
 		return "", fmt.Errorf("failed to generate user message: %w", err)
 	}
 
+	return CompleteRecorded(ctx, p, systemMsg, userMsg)
+}
+
+// Complete sends a system and user message to the local model and returns the answer
+func (p *LocalProvider) Complete(ctx context.Context, systemMsg, userMsg string) (string, error) {
+	if p.config.Endpoint == "" {
+		return "", fmt.Errorf("%w: local provider endpoint not configured", utils.ErrAIProviderUnavailable)
+	}
+
 	// Prepare request (OpenAI-compatible format for local models)
 	requestBody := map[string]interface{}{
 		"model": p.config.Model,
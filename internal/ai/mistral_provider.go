@@ -27,35 +27,35 @@ func NewMistralProvider(config *model.AIProviderConfig) AIProvider {
 		utils.Logger.Debug().Msg("Mistral API key not provided")
 	}
 
-	// Initialize Mistral SDK client
-	// Use custom endpoint constructor when endpoint is configured (e.g., for testing or self-hosted)
-	var client *mistral.MistralClient
+	// Initialize Mistral SDK client, with the configured timeout for custom endpoints
+	// (e.g., for testing or self-hosted)
+	endpoint, retries, timeout := mistral.Endpoint, mistral.DefaultMaxRetries, mistral.DefaultTimeout
 	if config.Endpoint != "" {
-		timeout := config.Timeout
+		endpoint = config.Endpoint
+		timeout = config.Timeout
 		if timeout == 0 {
 			timeout = 30 * time.Second
 		}
 		// Use 1 retry for custom endpoints (self-hosted, testing) to avoid
 		// excessive retries against non-standard servers
-		client = mistral.NewMistralClient(config.APIKey, config.Endpoint, 1, timeout)
-	} else {
-		client = mistral.NewMistralClientDefault(config.APIKey)
+		retries = 1
 	}
+	// gitcomm retries transient errors itself (retry_attempts): one try per attempt
+	if config.RetryAttempts > 1 {
+		retries = 1
+	}
+	client := mistral.NewMistralClient(config.APIKey, endpoint, retries, timeout)
 
 	return &MistralProvider{
 		config:    config,
 		client:    client,
-		generator: prompt.NewUnifiedPromptGenerator(),
+		generator: prompt.NewUnifiedPromptGeneratorWithLayout(config.Prompt),
 		validator: conventional.NewValidator(),
 	}
 }
 
 // GenerateCommitMessage generates a commit message using Mistral AI
 func (p *MistralProvider) GenerateCommitMessage(ctx context.Context, repoState *model.RepositoryState) (string, error) {
-	if p.config.APIKey == "" {
-		return "", fmt.Errorf("%w: Mistral API key not configured", utils.ErrAIProviderUnavailable)
-	}
-
 	// Generate unified system and user messages
 	systemMsg, err := p.generator.GenerateSystemMessage(p.validator)
 	if err != nil {
@@ -67,6 +67,15 @@ func (p *MistralProvider) GenerateCommitMessage(ctx context.Context, repoState *
 		return "", fmt.Errorf("failed to generate user message: %w", err)
 	}
 
+	return CompleteRecorded(ctx, p, systemMsg, userMsg)
+}
+
+// Complete sends a system and user message to Mistral AI and returns the answer
+func (p *MistralProvider) Complete(ctx context.Context, systemMsg, userMsg string) (string, error) {
+	if p.config.APIKey == "" {
+		return "", fmt.Errorf("%w: Mistral API key not configured", utils.ErrAIProviderUnavailable)
+	}
+
 	// Prepare model
 	modelName := p.config.Model
 	if modelName == "" {
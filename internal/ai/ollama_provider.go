@@ -0,0 +1,254 @@
+package ai
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/utils"
+	"github.com/golgoth31/gitcomm/pkg/ai/prompt"
+	"github.com/golgoth31/gitcomm/pkg/conventional"
+)
+
+// DefaultOllamaEndpoint is the address of a local Ollama server
+const DefaultOllamaEndpoint = "http://localhost:11434"
+
+// OllamaProvider implements AIProvider for models served by Ollama, through its native
+// /api/chat endpoint
+type OllamaProvider struct {
+	config    *model.AIProviderConfig
+	endpoint  string
+	timeout   time.Duration // longest wait for the server, between two chunks of an answer
+	client    *http.Client
+	generator prompt.PromptGenerator
+	validator conventional.MessageValidator
+}
+
+// NewOllamaProvider creates a new Ollama provider (endpoint default: DefaultOllamaEndpoint)
+func NewOllamaProvider(config *model.AIProviderConfig) *OllamaProvider {
+	timeout := config.Timeout
+	if timeout == 0 {
+		timeout = 30 * time.Second
+	}
+
+	return &OllamaProvider{
+		config:    config,
+		endpoint:  strings.TrimSuffix(cmp.Or(config.Endpoint, DefaultOllamaEndpoint), "/"),
+		timeout:   timeout,
+		client:    &http.Client{},
+		generator: prompt.NewUnifiedPromptGeneratorWithLayout(config.Prompt),
+		validator: conventional.NewValidator(),
+	}
+}
+
+// GenerateCommitMessage generates a commit message using an Ollama model
+func (p *OllamaProvider) GenerateCommitMessage(ctx context.Context, repoState *model.RepositoryState) (string, error) {
+	// Generate unified system and user messages
+	systemMsg, err := p.generator.GenerateSystemMessage(p.validator)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate system message: %w", err)
+	}
+
+	userMsg, err := p.generator.GenerateUserMessage(repoState)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate user message: %w", err)
+	}
+
+	return CompleteRecorded(ctx, p, systemMsg, userMsg)
+}
+
+// Complete sends a system and user message to the Ollama model and returns the answer,
+// read from the streamed response as it is generated and reported to the progress
+// callback of ctx (see WithProgress). The timeout bounds the wait for each chunk, not the
+// whole answer, so a long answer of a slow local model is not cut.
+func (p *OllamaProvider) Complete(ctx context.Context, systemMsg, userMsg string) (string, error) {
+	if p.config.Model == "" {
+		return "", fmt.Errorf("%w: ollama model not configured%s", utils.ErrAIProviderUnavailable, p.installedModelsHint(ctx))
+	}
+
+	maxTokens := cmp.Or(p.config.MaxTokens, 500)
+	options := map[string]int{"num_predict": maxTokens}
+	if numCtx := p.numCtx(maxTokens); numCtx > 0 {
+		options["num_ctx"] = numCtx
+	}
+	requestBody := map[string]interface{}{
+		"model": p.config.Model,
+		"messages": []map[string]string{
+			{
+				"role":    "system",
+				"content": systemMsg,
+			},
+			{
+				"role":    "user",
+				"content": userMsg,
+			},
+		},
+		"stream":  true,
+		"options": options,
+	}
+	if keepAlive := p.keepAlive(); keepAlive != nil {
+		requestBody["keep_alive"] = keepAlive
+	}
+
+	jsonData, err := json.Marshal(requestBody)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	idleErr := fmt.Errorf("timeout: no answer from ollama for %s", p.timeout)
+	streamCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	idle := time.AfterFunc(p.timeout, func() { cancel(idleErr) })
+	defer idle.Stop()
+
+	req, err := http.NewRequestWithContext(streamCtx, "POST", p.endpoint+"/api/chat", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		if context.Cause(streamCtx) == idleErr {
+			err = idleErr
+		}
+		return "", fmt.Errorf("%w: %v", utils.ErrAIProviderUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		hint := ""
+		if resp.StatusCode == http.StatusNotFound {
+			hint = fmt.Sprintf(" (pull the model with: ollama pull %s)", p.config.Model)
+		}
+		return "", fmt.Errorf("%w: API returned status %d: %s%s", utils.ErrAIProviderUnavailable, resp.StatusCode, strings.TrimSpace(string(body)), hint)
+	}
+
+	answer, err := readOllamaStream(resp.Body, func(received string) {
+		idle.Reset(p.timeout)
+		reportProgress(ctx, received)
+	})
+	if err != nil && context.Cause(streamCtx) == idleErr {
+		return "", fmt.Errorf("%w: %v", utils.ErrAIProviderUnavailable, idleErr)
+	}
+	return answer, err
+}
+
+// readOllamaStream concatenates the message chunks of a streamed /api/chat response,
+// one JSON object per line until the one marked done, calling onChunk with the answer
+// received so far after each chunk
+func readOllamaStream(body io.Reader, onChunk func(received string)) (string, error) {
+	var answer strings.Builder
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var chunk struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+			Done  bool   `json:"done"`
+			Error string `json:"error"`
+		}
+		if err := json.Unmarshal(line, &chunk); err != nil {
+			return "", fmt.Errorf("failed to decode response: %w", err)
+		}
+		if chunk.Error != "" {
+			return "", fmt.Errorf("%w: %s", utils.ErrAIProviderUnavailable, chunk.Error)
+		}
+		answer.WriteString(chunk.Message.Content)
+		onChunk(answer.String())
+		if chunk.Done {
+			return answer.String(), nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("%w: %v", utils.ErrAIProviderUnavailable, err)
+	}
+	return "", fmt.Errorf("%w: response ended before completion", utils.ErrAIProviderUnavailable)
+}
+
+// ListModels returns the names of the models installed on the Ollama server
+func (p *OllamaProvider) ListModels(ctx context.Context) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", p.endpoint+"/api/tags", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", utils.ErrAIProviderUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("%w: API returned status %d: %s", utils.ErrAIProviderUnavailable, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var response struct {
+		Models []struct {
+			Name string `json:"name"`
+		} `json:"models"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	names := make([]string, len(response.Models))
+	for i, m := range response.Models {
+		names[i] = m.Name
+	}
+	return names, nil
+}
+
+// installedModelsHint lists the installed models for the missing model error, when the
+// server can tell
+func (p *OllamaProvider) installedModelsHint(ctx context.Context) string {
+	names, err := p.ListModels(ctx)
+	if err != nil || len(names) == 0 {
+		return ""
+	}
+	return " (installed: " + strings.Join(names, ", ") + ")"
+}
+
+// numCtx returns the context window to request: Ollama defaults to a small window and
+// silently truncates longer prompts, so it is sized after the prompt budget when not set
+func (p *OllamaProvider) numCtx(maxTokens int) int {
+	if p.config.NumCtx > 0 {
+		return p.config.NumCtx
+	}
+	if p.config.Prompt.Budget > 0 {
+		return p.config.Prompt.Budget + maxTokens
+	}
+	return 0
+}
+
+// keepAlive returns the keep_alive value to send, nil for the server default. Ollama reads
+// numbers as seconds and strings as durations.
+func (p *OllamaProvider) keepAlive() interface{} {
+	if p.config.KeepAlive == "" {
+		return nil
+	}
+	if seconds, err := strconv.Atoi(p.config.KeepAlive); err == nil {
+		return seconds
+	}
+	return p.config.KeepAlive
+}
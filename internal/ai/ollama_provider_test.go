@@ -0,0 +1,204 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/utils"
+)
+
+func TestOllamaProvider_Complete(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      model.AIProviderConfig
+		status      int
+		response    string
+		want        string
+		wantErr     error
+		wantErrText string
+		wantOptions map[string]int
+		wantKeep    interface{}
+	}{
+		{
+			name:   "streamed answer",
+			config: model.AIProviderConfig{Model: "llama3.2", NumCtx: 8192, KeepAlive: "10m"},
+			status: http.StatusOK,
+			response: `{"message":{"role":"assistant","content":"feat: add "},"done":false}` + "\n" +
+				`{"message":{"role":"assistant","content":"ollama"},"done":false}` + "\n" +
+				`{"message":{"role":"assistant","content":""},"done":true}` + "\n",
+			want:        "feat: add ollama",
+			wantOptions: map[string]int{"num_predict": 500, "num_ctx": 8192},
+			wantKeep:    "10m",
+		},
+		{
+			name:        "context sized after the prompt budget",
+			config:      model.AIProviderConfig{Model: "llama3.2", MaxTokens: 300, KeepAlive: "-1", Prompt: model.PromptLayout{Budget: 4000}},
+			status:      http.StatusOK,
+			response:    `{"message":{"content":"fix: done"},"done":true}` + "\n",
+			want:        "fix: done",
+			wantOptions: map[string]int{"num_predict": 300, "num_ctx": 4300},
+			wantKeep:    float64(-1),
+		},
+		{
+			name:        "missing model",
+			config:      model.AIProviderConfig{Model: "mistral"},
+			status:      http.StatusNotFound,
+			response:    `{"error":"model \"mistral\" not found"}`,
+			wantErr:     utils.ErrAIProviderUnavailable,
+			wantErrText: "ollama pull mistral",
+		},
+		{
+			name:     "error in the stream",
+			config:   model.AIProviderConfig{Model: "llama3.2"},
+			status:   http.StatusOK,
+			response: `{"error":"out of memory"}` + "\n",
+			wantErr:  utils.ErrAIProviderUnavailable,
+		},
+		{
+			name:     "interrupted stream",
+			config:   model.AIProviderConfig{Model: "llama3.2"},
+			status:   http.StatusOK,
+			response: `{"message":{"content":"feat:"},"done":false}` + "\n",
+			wantErr:  utils.ErrAIProviderUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var request struct {
+				Model     string         `json:"model"`
+				Stream    bool           `json:"stream"`
+				Options   map[string]int `json:"options"`
+				KeepAlive interface{}    `json:"keep_alive"`
+			}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/api/chat" {
+					t.Errorf("request path = %s, want /api/chat", r.URL.Path)
+				}
+				if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+					t.Errorf("failed to decode request: %v", err)
+				}
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			config := tt.config
+			config.Endpoint = server.URL + "/"
+			got, err := NewOllamaProvider(&config).Complete(context.Background(), "system", "user")
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) || !strings.Contains(err.Error(), tt.wantErrText) {
+					t.Errorf("Complete() error = %v, want %v containing %q", err, tt.wantErr, tt.wantErrText)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Complete() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Complete() = %q, want %q", got, tt.want)
+			}
+			if !request.Stream || request.Model != config.Model {
+				t.Errorf("request model = %q, stream = %v, want %q streamed", request.Model, request.Stream, config.Model)
+			}
+			if len(request.Options) != len(tt.wantOptions) {
+				t.Errorf("request options = %v, want %v", request.Options, tt.wantOptions)
+			}
+			for key, want := range tt.wantOptions {
+				if request.Options[key] != want {
+					t.Errorf("request option %s = %d, want %d", key, request.Options[key], want)
+				}
+			}
+			if request.KeepAlive != tt.wantKeep {
+				t.Errorf("request keep_alive = %v, want %v", request.KeepAlive, tt.wantKeep)
+			}
+		})
+	}
+}
+
+func TestOllamaProvider_Complete_IdleTimeout(t *testing.T) {
+	chunks := []string{"feat: ", "stream ", "slowly"}
+	tests := []struct {
+		name    string
+		gap     time.Duration
+		wantErr bool
+	}{
+		// The whole answer takes longer than the timeout, each chunk comes in time
+		{name: "slow answer", gap: 100 * time.Millisecond},
+		{name: "stalled server", gap: time.Second, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for i, content := range chunks {
+					select {
+					case <-time.After(tt.gap):
+					case <-r.Context().Done():
+						return
+					}
+					done := i == len(chunks)-1
+					_, _ = fmt.Fprintf(w, `{"message":{"content":%q},"done":%t}`+"\n", content, done)
+					w.(http.Flusher).Flush()
+				}
+			}))
+			defer server.Close()
+
+			config := model.AIProviderConfig{Model: "llama3.2", Endpoint: server.URL, Timeout: 250 * time.Millisecond}
+			var progress []string
+			ctx := WithProgress(context.Background(), func(received string) { progress = append(progress, received) })
+			got, err := NewOllamaProvider(&config).Complete(ctx, "system", "user")
+			if tt.wantErr {
+				if !errors.Is(err, utils.ErrAIProviderUnavailable) || !IsTransient(err) {
+					t.Errorf("Complete() error = %v, want a transient %v", err, utils.ErrAIProviderUnavailable)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Complete() error = %v", err)
+			}
+			if got != "feat: stream slowly" {
+				t.Errorf("Complete() = %q, want %q", got, "feat: stream slowly")
+			}
+			want := []string{"feat: ", "feat: stream ", "feat: stream slowly"}
+			if !slices.Equal(progress, want) {
+				t.Errorf("progress = %q, want %q", progress, want)
+			}
+		})
+	}
+}
+
+func TestOllamaProvider_ListModels(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/tags" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`{"models":[{"name":"llama3.2:latest"},{"name":"qwen2.5-coder:7b"}]}`))
+	}))
+	defer server.Close()
+
+	provider := NewOllamaProvider(&model.AIProviderConfig{Endpoint: server.URL})
+	names, err := provider.ListModels(context.Background())
+	if err != nil {
+		t.Fatalf("ListModels() error = %v", err)
+	}
+	if want := []string{"llama3.2:latest", "qwen2.5-coder:7b"}; !slices.Equal(names, want) {
+		t.Errorf("ListModels() = %v, want %v", names, want)
+	}
+
+	// Without a model, the error lists the installed ones
+	_, err = provider.Complete(context.Background(), "system", "user")
+	if !errors.Is(err, utils.ErrAIProviderUnavailable) || !strings.Contains(err.Error(), "qwen2.5-coder:7b") {
+		t.Errorf("Complete() error = %v, want the installed models", err)
+	}
+}
@@ -31,24 +31,27 @@ func NewOpenAIProvider(config *model.AIProviderConfig) AIProvider {
 
 	// Initialize OpenAI SDK v3 client
 	// NewClient doesn't return an error - it reads from environment or uses provided options
-	client := openai.NewClient(
-		option.WithAPIKey(config.APIKey),
-	)
+	opts := []option.RequestOption{option.WithAPIKey(config.APIKey)}
+	// gitcomm retries transient errors itself (retry_attempts): one try per attempt
+	if config.RetryAttempts > 1 {
+		opts = append(opts, option.WithMaxRetries(0))
+	}
+	// A custom endpoint is the base URL of a compatible gateway or proxy
+	if config.Endpoint != "" {
+		opts = append(opts, option.WithBaseURL(config.Endpoint))
+	}
+	client := openai.NewClient(opts...)
 
 	return &OpenAIProvider{
 		config:    config,
 		client:    client,
-		generator: prompt.NewUnifiedPromptGenerator(),
+		generator: prompt.NewUnifiedPromptGeneratorWithLayout(config.Prompt),
 		validator: conventional.NewValidator(),
 	}
 }
 
 // GenerateCommitMessage generates a commit message using OpenAI Responses API
 func (p *OpenAIProvider) GenerateCommitMessage(ctx context.Context, repoState *model.RepositoryState) (string, error) {
-	if p.config.APIKey == "" {
-		return "", fmt.Errorf("%w: OpenAI API key not configured", utils.ErrAIProviderUnavailable)
-	}
-
 	// Generate unified system and user messages
 	systemMsg, err := p.generator.GenerateSystemMessage(p.validator)
 	if err != nil {
@@ -60,6 +63,15 @@ func (p *OpenAIProvider) GenerateCommitMessage(ctx context.Context, repoState *m
 		return "", fmt.Errorf("failed to generate user message: %w", err)
 	}
 
+	return CompleteRecorded(ctx, p, systemMsg, userMsg)
+}
+
+// Complete sends a system and user message to OpenAI and returns the answer
+func (p *OpenAIProvider) Complete(ctx context.Context, systemMsg, userMsg string) (string, error) {
+	if p.config.APIKey == "" {
+		return "", fmt.Errorf("%w: OpenAI API key not configured", utils.ErrAIProviderUnavailable)
+	}
+
 	// Prepare model
 	modelName := p.config.Model
 	if modelName == "" {
@@ -0,0 +1,19 @@
+package ai
+
+import "context"
+
+// progressKey is the context key of the progress callback
+type progressKey struct{}
+
+// WithProgress returns a context whose streaming provider requests report the answer
+// received so far to fn, as it is generated
+func WithProgress(ctx context.Context, fn func(received string)) context.Context {
+	return context.WithValue(ctx, progressKey{}, fn)
+}
+
+// reportProgress reports the answer received so far to the progress callback of ctx
+func reportProgress(ctx context.Context, received string) {
+	if fn, ok := ctx.Value(progressKey{}).(func(string)); ok {
+		fn(received)
+	}
+}
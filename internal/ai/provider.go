@@ -2,12 +2,48 @@ package ai
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/utils"
 )
 
 // AIProvider defines the interface for AI providers that generate commit messages
 type AIProvider interface {
 	// GenerateCommitMessage generates a commit message based on repository state
 	GenerateCommitMessage(ctx context.Context, repoState *model.RepositoryState) (string, error)
+
+	// Complete sends a system and user message to the model and returns its raw answer
+	Complete(ctx context.Context, systemMsg, userMsg string) (string, error)
+}
+
+// TokenCounter is implemented by the providers counting the tokens of a commit message
+// request themselves, with the tokenizer of their model
+type TokenCounter interface {
+	// CountTokens returns the tokens of the request GenerateCommitMessage sends for repoState
+	CountTokens(ctx context.Context, repoState *model.RepositoryState) (int, error)
+}
+
+// CountsTokens reports whether provider, under its limits, retries and circuit breaker,
+// counts tokens itself: only then its CountTokens may be called
+func CountsTokens(provider AIProvider) bool {
+	for {
+		wrapper, ok := provider.(interface{ Unwrap() AIProvider })
+		if !ok {
+			break
+		}
+		provider = wrapper.Unwrap()
+	}
+	_, ok := provider.(TokenCounter)
+	return ok
+}
+
+// countTokens counts the tokens of the request with the provider wrapped by a limit, retry
+// or circuit breaker
+func countTokens(ctx context.Context, provider AIProvider, repoState *model.RepositoryState) (int, error) {
+	counter, ok := provider.(TokenCounter)
+	if !ok {
+		return 0, fmt.Errorf("%w: the provider does not count tokens", utils.ErrAIProviderUnavailable)
+	}
+	return counter.CountTokens(ctx, repoState)
 }
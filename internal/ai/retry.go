@@ -0,0 +1,143 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/utils"
+)
+
+// transientStatus matches the rate limit and server error statuses as providers report
+// them: "API returned status 503" (local, ollama), "(HTTP Error 503)" (mistral) and
+// "https://...": 503 Service Unavailable" (OpenAI and Anthropic SDKs)
+var transientStatus = regexp.MustCompile(`(?:status:? |HTTP Error |": )(?:429|5\d\d)\b`)
+
+// IsTransient reports whether a provider call failing with err may succeed when tried
+// again: rate limits, server errors and timeouts. Cancellations and open circuits are not.
+func IsTransient(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, utils.ErrCircuitOpen) {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	// Providers report their errors as text (see mapSDKError)
+	message := err.Error()
+	lower := strings.ToLower(message)
+	return strings.Contains(lower, "rate limit") ||
+		strings.Contains(lower, "timeout") ||
+		transientStatus.MatchString(message)
+}
+
+// retryTimer waits between two attempts; replaced in tests so retries do not depend on
+// the scheduling of the machine
+var retryTimer = time.After
+
+// SetRetryTimer replaces the timer waiting between the attempts of the providers created
+// from now on, and returns a function restoring the previous one
+func SetRetryTimer(after func(time.Duration) <-chan time.Time) (restore func()) {
+	previous := retryTimer
+	retryTimer = after
+	return func() { retryTimer = previous }
+}
+
+// retryProvider tries the calls of the wrapped provider again while they fail with a
+// transient error, waiting longer after each attempt
+type retryProvider struct {
+	provider   AIProvider
+	attempts   int
+	backoff    time.Duration
+	maxBackoff time.Duration
+	jitter     float64
+	random     func() float64 // in [0, 1)
+	afterDelay func(time.Duration) <-chan time.Time
+}
+
+// WithRetry wraps provider with the retry policy of config (retry_attempts, retry_backoff,
+// retry_max_backoff, retry_jitter). With a single attempt the provider is returned unchanged.
+func WithRetry(provider AIProvider, config *model.AIProviderConfig) AIProvider {
+	if config.RetryAttempts <= 1 {
+		return provider
+	}
+	return &retryProvider{
+		provider:   provider,
+		attempts:   config.RetryAttempts,
+		backoff:    config.RetryBackoff,
+		maxBackoff: config.RetryMaxBackoff,
+		jitter:     config.RetryJitter,
+		random:     rand.Float64,
+		afterDelay: retryTimer,
+	}
+}
+
+// GenerateCommitMessage generates a commit message, trying again on transient errors
+func (p *retryProvider) GenerateCommitMessage(ctx context.Context, repoState *model.RepositoryState) (string, error) {
+	return p.retry(ctx, func() (string, error) {
+		return p.provider.GenerateCommitMessage(ctx, repoState)
+	})
+}
+
+// Complete sends the messages, trying again on transient errors
+func (p *retryProvider) Complete(ctx context.Context, systemMsg, userMsg string) (string, error) {
+	return p.retry(ctx, func() (string, error) {
+		return p.provider.Complete(ctx, systemMsg, userMsg)
+	})
+}
+
+// CountTokens counts the tokens of the request, trying again on transient errors
+func (p *retryProvider) CountTokens(ctx context.Context, repoState *model.RepositoryState) (int, error) {
+	var tokens int
+	_, err := p.retry(ctx, func() (string, error) {
+		var err error
+		tokens, err = countTokens(ctx, p.provider, repoState)
+		return "", err
+	})
+	return tokens, err
+}
+
+// Unwrap returns the retried provider
+func (p *retryProvider) Unwrap() AIProvider {
+	return p.provider
+}
+
+// retry calls call until it succeeds, fails with a permanent error, the attempts are
+// exhausted or ctx is done. The last error is returned.
+func (p *retryProvider) retry(ctx context.Context, call func() (string, error)) (string, error) {
+	for attempt := 1; ; attempt++ {
+		answer, err := call()
+		if err == nil || attempt >= p.attempts || !IsTransient(err) || ctx.Err() != nil {
+			return answer, err
+		}
+
+		delay := p.delay(attempt)
+		utils.Logger.Debug().Err(err).Int("attempt", attempt).Dur("delay", delay).Msg("Transient provider error, retrying")
+		select {
+		case <-p.afterDelay(delay):
+		case <-ctx.Done():
+			return "", err
+		}
+	}
+}
+
+// delay returns the wait after the given failed attempt: the backoff doubled for each
+// previous attempt, capped by the maximum backoff, then randomized by the jitter
+func (p *retryProvider) delay(attempt int) time.Duration {
+	delay := p.backoff
+	for i := 1; i < attempt && (p.maxBackoff <= 0 || delay < p.maxBackoff); i++ {
+		delay *= 2
+	}
+	if p.maxBackoff > 0 {
+		delay = min(delay, p.maxBackoff)
+	}
+	return time.Duration(float64(delay) * (1 + p.jitter*(2*p.random()-1)))
+}
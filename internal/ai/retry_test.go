@@ -0,0 +1,124 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/utils"
+	"github.com/golgoth31/gitcomm/test/mocks"
+)
+
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"rate limit", fmt.Errorf("%w: rate limit exceeded", utils.ErrAIProviderUnavailable), true},
+		{"timeout", fmt.Errorf("%w: timeout", utils.ErrAIProviderUnavailable), true},
+		{"deadline", fmt.Errorf("request: %w", context.DeadlineExceeded), true},
+		{"local server error", fmt.Errorf("%w: API returned status 503: overloaded", utils.ErrAIProviderUnavailable), true},
+		{"mistral server error", fmt.Errorf("%w: (HTTP Error 502) bad gateway", utils.ErrAIProviderUnavailable), true},
+		{"sdk server error", fmt.Errorf("%w: POST \"https://api.openai.com/v1/responses\": 500 Internal Server Error", utils.ErrAIProviderUnavailable), true},
+		{"invalid key", fmt.Errorf("%w: API key invalid", utils.ErrAIProviderUnavailable), false},
+		{"model not found", fmt.Errorf("%w: API returned status 404: model not found", utils.ErrAIProviderUnavailable), false},
+		{"cancelled", fmt.Errorf("request: %w", context.Canceled), false},
+		{"open circuit", fmt.Errorf("%w: openai failed 3 times in a row: timeout", utils.ErrCircuitOpen), false},
+		{"nil", nil, false},
+	}
+	for _, tt := range tests {
+		if got := IsTransient(tt.err); got != tt.want {
+			t.Errorf("%s: IsTransient(%v) = %v, want %v", tt.name, tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestRetryProvider(t *testing.T) {
+	rateLimited := fmt.Errorf("%w: rate limit exceeded", utils.ErrAIProviderUnavailable)
+	invalidKey := fmt.Errorf("%w: API key invalid", utils.ErrAIProviderUnavailable)
+
+	tests := []struct {
+		name       string
+		failures   []error // errors of the first calls, then success
+		wantCalls  int
+		wantErr    error
+		wantDelays []time.Duration
+	}{
+		{"success", nil, 1, nil, nil},
+		{"transient then success", []error{rateLimited, rateLimited}, 3, nil, []time.Duration{time.Second, 2 * time.Second}},
+		{"attempts exhausted", []error{rateLimited, rateLimited, rateLimited, rateLimited}, 4, rateLimited, []time.Duration{time.Second, 2 * time.Second, 3 * time.Second}},
+		{"permanent error", []error{invalidKey}, 1, invalidKey, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			provider := &mocks.MockAIProvider{CompleteFunc: func(ctx context.Context, systemMsg, userMsg string) (string, error) {
+				calls++
+				if calls <= len(tt.failures) {
+					return "", tt.failures[calls-1]
+				}
+				return "ok", nil
+			}}
+			var delays []time.Duration
+			wrapped := WithRetry(provider, &model.AIProviderConfig{RetryAttempts: 4, RetryBackoff: time.Second, RetryMaxBackoff: 3 * time.Second}).(*retryProvider)
+			wrapped.afterDelay = func(d time.Duration) <-chan time.Time {
+				delays = append(delays, d)
+				return time.After(0)
+			}
+
+			_, err := wrapped.Complete(context.Background(), "system", "user")
+			if (tt.wantErr == nil && err != nil) || !errors.Is(err, tt.wantErr) {
+				t.Errorf("Complete() error = %v, want %v", err, tt.wantErr)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("provider called %d times, want %d", calls, tt.wantCalls)
+			}
+			if fmt.Sprint(delays) != fmt.Sprint(tt.wantDelays) {
+				t.Errorf("delays = %v, want %v", delays, tt.wantDelays)
+			}
+		})
+	}
+}
+
+func TestRetryProvider_StopsWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	timeout := fmt.Errorf("%w: timeout", utils.ErrAIProviderUnavailable)
+	calls := 0
+	provider := &mocks.MockAIProvider{CompleteFunc: func(ctx context.Context, systemMsg, userMsg string) (string, error) {
+		calls++
+		cancel()
+		return "", timeout
+	}}
+	wrapped := WithRetry(provider, &model.AIProviderConfig{RetryAttempts: 3, RetryBackoff: time.Hour})
+
+	if _, err := wrapped.Complete(ctx, "system", "user"); !errors.Is(err, timeout) || calls != 1 {
+		t.Errorf("Complete() = %v after %d calls, want the timeout after 1 call", err, calls)
+	}
+}
+
+func TestRetryProvider_Jitter(t *testing.T) {
+	p := &retryProvider{backoff: time.Second, maxBackoff: time.Minute, jitter: 0.5}
+	for _, tt := range []struct {
+		random float64
+		want   time.Duration
+	}{
+		{0, 2 * time.Second},
+		{0.5, 4 * time.Second},
+		{0.75, 5 * time.Second},
+	} {
+		p.random = func() float64 { return tt.random }
+		if got := p.delay(3); got != tt.want {
+			t.Errorf("delay(3) with random %v = %s, want %s", tt.random, got, tt.want)
+		}
+	}
+
+	if got := WithRetry(&mocks.MockAIProvider{}, &model.AIProviderConfig{RetryAttempts: 1}); got == nil {
+		t.Fatal("WithRetry() = nil")
+	} else if _, wrapped := got.(*retryProvider); wrapped {
+		t.Error("WithRetry() wrapped a provider with a single attempt")
+	}
+}
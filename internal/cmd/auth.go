@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"bufio"
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/x/term"
+	"github.com/golgoth31/gitcomm/internal/config"
+	"github.com/golgoth31/gitcomm/internal/keyring"
+	"github.com/golgoth31/gitcomm/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var authAccount string
+
+// authCmd manages the provider API keys stored in the OS keychain
+var authCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "Store provider API keys in the OS keychain",
+	Long: `Store provider API keys in the OS keychain (macOS Keychain, Windows Credential
+Manager, or the Secret Service through secret-tool on Linux) instead of the
+config file or environment variables.
+
+A key stored for openai is read by the config value:
+
+  ai:
+    providers:
+      openai:
+        api_key: keyring:openai
+
+Examples:
+  gitcomm auth login openai
+  echo "$ANTHROPIC_API_KEY" | gitcomm auth login anthropic
+  gitcomm auth logout openai`,
+}
+
+// authLoginCmd stores the API key of a provider
+var authLoginCmd = &cobra.Command{
+	Use:   "login <provider>",
+	Short: "Store the API key of a provider in the keychain",
+	Long: `Store the API key of a provider in the OS keychain and point the provider's
+api_key setting at it (keyring:<account>), replacing a key written in clear.
+The key is asked without echo on a terminal, or read from the first line of
+stdin when it is piped.
+
+Examples:
+  gitcomm auth login openai
+  gitcomm auth login openai --account openai-work
+  pass show openai | gitcomm auth login openai`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		provider := args[0]
+		account := cmp.Or(authAccount, provider)
+
+		secret, err := readSecret("API key for " + provider)
+		if err != nil {
+			ui.PrintError("failed to read the API key", err)
+			os.Exit(1)
+		}
+		if err := keyring.Set(account, secret); err != nil {
+			ui.PrintError("failed to store the API key", err)
+			os.Exit(1)
+		}
+		fmt.Fprintf(ui.Out(), "✓ API key stored in the keychain as %s\n", keyring.Reference(account))
+
+		path := resolveConfigPath()
+		key := "ai.providers." + provider + ".api_key"
+		if current, _, err := config.GetValue(path, key); err == nil && current == keyring.Reference(account) {
+			return
+		}
+		if err := config.SetValue(path, key, keyring.Reference(account)); err != nil {
+			ui.PrintError("failed to update configuration", err)
+			os.Exit(1)
+		}
+		fmt.Fprintf(ui.Out(), "✓ %s = %s in %s\n", key, keyring.Reference(account), path)
+	},
+}
+
+// authLogoutCmd removes the API key of a provider
+var authLogoutCmd = &cobra.Command{
+	Use:   "logout <provider>",
+	Short: "Remove the API key of a provider from the keychain",
+	Long: `Remove the API key of a provider from the OS keychain. The api_key setting
+is left as is: point it at another key with gitcomm config set.
+
+Examples:
+  gitcomm auth logout openai
+  gitcomm auth logout openai --account openai-work`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		provider := args[0]
+		account := cmp.Or(authAccount, provider)
+
+		err := keyring.Delete(account)
+		switch {
+		case errors.Is(err, keyring.ErrNotFound):
+			fmt.Fprintf(ui.Out(), "No API key stored as %s\n", keyring.Reference(account))
+			return
+		case err != nil:
+			ui.PrintError("failed to remove the API key", err)
+			os.Exit(1)
+		}
+		fmt.Fprintf(ui.Out(), "✓ %s removed from the keychain\n", keyring.Reference(account))
+
+		key := "ai.providers." + provider + ".api_key"
+		if current, _, err := config.GetValue(resolveConfigPath(), key); err == nil && current == keyring.Reference(account) {
+			fmt.Fprintf(ui.Out(), "%s still reads %s: run gitcomm auth login %s or gitcomm config set %s\n", key, current, provider, key)
+		}
+	},
+}
+
+// readSecret asks for a secret without echo on a terminal, or reads the first line of
+// stdin when it is piped
+func readSecret(prompt string) (string, error) {
+	var secret string
+	if ui.Interactive() {
+		fmt.Fprintf(os.Stderr, "%s: ", prompt)
+		input, err := term.ReadPassword(os.Stdin.Fd())
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return "", err
+		}
+		secret = string(input)
+	} else {
+		line, err := bufio.NewReader(ui.Stdin()).ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return "", err
+		}
+		secret = line
+	}
+	secret = strings.TrimSpace(secret)
+	if secret == "" {
+		return "", fmt.Errorf("no API key given")
+	}
+	return secret, nil
+}
+
+func init() {
+	authCmd.PersistentFlags().StringVar(&authAccount, "account", "", "Keychain account of the key (default: the provider name)")
+	authCmd.AddCommand(authLoginCmd, authLogoutCmd)
+	rootCmd.AddCommand(authCmd)
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/golgoth31/gitcomm/internal/config"
+	"github.com/golgoth31/gitcomm/internal/repository"
+	"github.com/golgoth31/gitcomm/internal/service"
+	"github.com/golgoth31/gitcomm/internal/ui"
+	"github.com/golgoth31/gitcomm/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+var changelogAll bool
+
+// changelogCmd prints the release notes of the commits since the last release
+var changelogCmd = &cobra.Command{
+	Use:   "changelog",
+	Short: "Print the changelog of the commits since the last release",
+	Long: `Print the release notes of the commits since the last release tag as markdown,
+grouped like semantic-release's conventional-changelog preset: breaking changes,
+features, bug fixes, performance improvements and reverts. The notes are titled
+with the tag next-version computes and today's date (dates configuration), or
+"Unreleased" when no commit calls for a release.
+
+Other commits (docs, chore, refactor...) are only listed with --all. When no
+commit is listed, nothing is printed on stdout.
+
+Examples:
+  gitcomm changelog
+  gitcomm changelog --all > RELEASE_NOTES.md`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.LoadConfig(configPath)
+		if err != nil {
+			utils.Logger.Debug().Err(err).Msg("Failed to load configuration, continuing with defaults")
+			cfg = &config.Config{Release: config.ReleaseConfig{TagPrefix: config.DefaultTagPrefix}}
+		}
+		dates, err := cfg.DateFormatter()
+		if err != nil {
+			ui.PrintError("invalid dates configuration", err)
+			os.Exit(1)
+		}
+
+		gitRepo, err := repository.NewGitRepository("", true, noRTK)
+		if err != nil {
+			ui.PrintError("failed to initialize git repository", err)
+			os.Exit(1)
+		}
+		exitIfDisabled(context.Background(), gitRepo)
+
+		plan, err := service.NewReleaseService(gitRepo, cfg).Plan(context.Background())
+		if err != nil {
+			ui.PrintError("failed to analyze commits", err)
+			os.Exit(1)
+		}
+
+		fmt.Fprintln(os.Stderr, plan)
+		changelog := plan.Changelog(dates.FormatLayout(time.Now(), time.DateOnly), changelogAll)
+		if changelog == "" {
+			return
+		}
+		// The changelog is the result: paged on a terminal, on stdout for scripts
+		if ui.SeparateStreams() {
+			fmt.Print(changelog)
+			return
+		}
+		if err := ui.PrintPaged(changelog); err != nil {
+			utils.Logger.Debug().Err(err).Msg("Failed to display changelog")
+		}
+	},
+}
+
+func init() {
+	changelogCmd.Flags().BoolVar(&changelogAll, "all", false, "List the commits that are not features, fixes or performance improvements too")
+	rootCmd.AddCommand(changelogCmd)
+}
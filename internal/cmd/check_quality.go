@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/golgoth31/gitcomm/internal/config"
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/repository"
+	"github.com/golgoth31/gitcomm/internal/service"
+	"github.com/golgoth31/gitcomm/internal/ui"
+	"github.com/golgoth31/gitcomm/internal/utils"
+	"github.com/golgoth31/gitcomm/pkg/ai/prompt"
+	"github.com/spf13/cobra"
+)
+
+var (
+	qualityLimit int
+	qualityJobs  int
+)
+
+// checkQualityCmd grades existing commit messages against their diffs
+var checkQualityCmd = &cobra.Command{
+	Use:   "check-quality [range]",
+	Short: "Score existing commit messages against their diffs",
+	Long: `Ask the AI provider to grade the messages of existing commits: does the
+message describe the change, and which important files does it leave out?
+A score report is printed, which helps comparing prompt and model settings.
+
+The range is any git log revision range (default: HEAD). Merge commits are
+skipped and at most --limit commits are evaluated, newest first. With --jobs,
+several commits are evaluated at once; the provider's max_concurrent and
+requests_per_minute settings still cap the calls made with a shared API key.
+
+Examples:
+  gitcomm check-quality main..HEAD
+  gitcomm check-quality --limit 5
+  gitcomm check-quality --limit 100 --jobs 4
+  gitcomm check-quality v1.2.0..v1.3.0 --provider anthropic`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		revisionRange := "HEAD"
+		if len(args) == 1 {
+			revisionRange = args[0]
+		}
+		if qualityLimit <= 0 {
+			ui.PrintError("invalid options", fmt.Errorf("--limit must be positive"))
+			os.Exit(1)
+		}
+		if qualityJobs <= 0 {
+			ui.PrintError("invalid options", fmt.Errorf("--jobs must be positive"))
+			os.Exit(1)
+		}
+
+		cfg, err := config.LoadConfig(configPath)
+		if err != nil {
+			utils.Logger.Debug().Err(err).Msg("Failed to load configuration, continuing with defaults")
+			cfg = &config.Config{}
+		}
+
+		gitRepo, err := repository.NewGitRepository("", true, noRTK)
+		if err != nil {
+			ui.PrintError("failed to initialize git repository", err)
+			os.Exit(1)
+		}
+		exitIfDisabled(context.Background(), gitRepo)
+
+		options := &model.CommitOptions{AIProvider: provider, Account: account}
+		results, err := service.NewQualityService(gitRepo, options, cfg).Evaluate(context.Background(), revisionRange, qualityLimit, qualityJobs)
+		if len(results) > 0 {
+			if err := ui.PrintPaged("\n" + formatQualityReport(results, ui.TerminalWidth())); err != nil {
+				utils.Logger.Debug().Err(err).Msg("Failed to display quality report")
+			}
+		}
+		if err != nil {
+			ui.PrintError("quality check failed", err)
+			os.Exit(1)
+		}
+		if len(results) == 0 {
+			fmt.Fprintln(ui.Out(), "No commits to evaluate")
+		}
+	},
+}
+
+// formatQualityReport returns one line per commit followed by the summary, for a terminal
+// of width columns
+func formatQualityReport(results []service.QualityResult, width int) string {
+	var sb strings.Builder
+	for _, result := range results {
+		if result.Report == nil {
+			line := fmt.Sprintf("%s   -/%d  %-9s  %s", result.Commit.ShortHash(), prompt.MaxQualityScore, "error", result.Commit.Subject())
+			fmt.Fprintln(&sb, ui.TruncateEnd(line, width))
+			fmt.Fprintf(&sb, "         %v\n", result.Err)
+			continue
+		}
+
+		report := result.Report
+		line := fmt.Sprintf("%s  %2d/%d  %-9s  %s", result.Commit.ShortHash(), report.Score, prompt.MaxQualityScore, report.Describes, result.Commit.Subject())
+		fmt.Fprintln(&sb, ui.TruncateEnd(line, width))
+		if len(report.Missing) > 0 {
+			missing := make([]string, len(report.Missing))
+			for i, path := range report.Missing {
+				missing[i] = ui.TruncatePath(path, width-len("         missing: "))
+			}
+			fmt.Fprintf(&sb, "         missing: %s\n", strings.Join(missing, ", "))
+		}
+		if report.Notes != "" {
+			fmt.Fprintf(&sb, "         %s\n", report.Notes)
+		}
+	}
+
+	summary := service.SummarizeQuality(results)
+	fmt.Fprintf(&sb, "\n%d commit(s) evaluated", summary.Evaluated)
+	if summary.Evaluated > 0 {
+		fmt.Fprintf(&sb, ", average score %.1f/%d", summary.AverageScore, prompt.MaxQualityScore)
+	}
+	sb.WriteString("\n")
+	if summary.Evaluated > 0 {
+		fmt.Fprintf(&sb, "  describes the change: %d yes, %d partially, %d no\n",
+			summary.Describes["yes"], summary.Describes["partially"], summary.Describes["no"])
+		fmt.Fprintf(&sb, "  missing important files: %d commit(s)\n", summary.WithMissingFiles)
+	}
+	if summary.Failed > 0 {
+		fmt.Fprintf(&sb, "  not evaluated: %d commit(s)\n", summary.Failed)
+	}
+	return sb.String()
+}
+
+func init() {
+	checkQualityCmd.Flags().IntVar(&qualityLimit, "limit", 20, "Maximum number of commits to evaluate")
+	checkQualityCmd.Flags().IntVar(&qualityJobs, "jobs", 1, "Number of commits evaluated at once")
+	rootCmd.AddCommand(checkQualityCmd)
+}
@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// commitCmd runs the commit workflow, like gitcomm without a subcommand
+var commitCmd = &cobra.Command{
+	Use:   "commit [-- <pathspec>...]",
+	Short: "Create a commit with a Conventional Commits message",
+	Long: `Create a commit with a Conventional Commits message, written manually or
+generated by the AI provider. This is what gitcomm does without a subcommand,
+spelled out for scripts and aliases.
+
+Examples:
+  # Auto-stage files and create commit
+  gitcomm commit -a
+
+  # Use a specific provider and plain prompts
+  gitcomm commit --provider anthropic --plain
+
+  # Only stage and commit the changes under src/api/ and docs/
+  gitcomm commit -- src/api/ docs/`,
+	Args: pathspecArgs,
+	Run:  runCommand,
+}
+
+func init() {
+	addCommitFlags(commitCmd.Flags())
+	rootCmd.AddCommand(commitCmd)
+}
@@ -0,0 +1,224 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/golgoth31/gitcomm/internal/config"
+	"github.com/golgoth31/gitcomm/internal/keyring"
+	"github.com/golgoth31/gitcomm/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var configListKeys bool
+
+// errEditDiscarded reports an edit of the config file left invalid and discarded
+var errEditDiscarded = errors.New("changes discarded")
+
+// configCmd groups the commands reading and writing the config file
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Read and write the configuration file",
+	Long: `Read and write the configuration file (~/.gitcomm/config.yaml, or --config)
+without editing YAML by hand. Keys are written with dots, provider keys with the
+provider name: ai.providers.openai.model.
+
+Every write is validated first: unknown keys, values of the wrong type and
+configurations that would break gitcomm are rejected and the file is left as it
+was. Comments in the file are kept.
+
+Examples:
+  gitcomm config set ai.default_provider openai
+  gitcomm config set ai.providers.openai.api_key '${OPENAI_API_KEY}'
+  gitcomm config set commit.scopes api,cli,docs
+  gitcomm config get ai.providers.openai.model
+  gitcomm config unset commit.stats_footer
+  gitcomm config list --keys
+  gitcomm config edit`,
+}
+
+// configGetCmd prints the value of a key
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print the value of a key as written in the config file",
+	Long: `Print the value of a key as written in the config file, environment variable
+placeholders included. A section (such as ai.providers.openai) is printed as YAML.
+Exits with status 1 when the key is not set.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		value, ok, err := config.GetValue(resolveConfigPath(), args[0])
+		if err != nil {
+			ui.PrintError("failed to read configuration", err)
+			os.Exit(1)
+		}
+		if !ok {
+			fmt.Fprintf(os.Stderr, "%s is not set\n", args[0])
+			os.Exit(1)
+		}
+		fmt.Println(value)
+	},
+}
+
+// configSetCmd sets the value of a key
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Set the value of a key",
+	Long: `Set the value of a key, creating the config file and its sections as needed.
+Booleans are true or false, durations are written like 30s or 2m, lists are
+comma-separated (a,b,c) or in YAML flow style ([a, b]), maps in YAML flow style
+({name: value}).`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := config.SetValue(resolveConfigPath(), args[0], args[1]); err != nil {
+			ui.PrintError("invalid configuration", err)
+			os.Exit(1)
+		}
+		fmt.Fprintf(ui.Out(), "✓ %s set\n", args[0])
+	},
+}
+
+// configUnsetCmd removes a key
+var configUnsetCmd = &cobra.Command{
+	Use:   "unset <key>",
+	Short: "Remove a key or a section, restoring its default",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		removed, err := config.UnsetValue(resolveConfigPath(), args[0])
+		if err != nil {
+			ui.PrintError("invalid configuration", err)
+			os.Exit(1)
+		}
+		if !removed {
+			fmt.Fprintf(ui.Out(), "%s is not set\n", args[0])
+			return
+		}
+		fmt.Fprintf(ui.Out(), "✓ %s unset\n", args[0])
+	},
+}
+
+// configListCmd lists the keys set in the config file, or every known key
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the keys set in the config file",
+	Long: `List the keys set in the config file with their values. Secrets (API keys,
+tokens, passwords) are masked unless they are environment variable placeholders.
+With --keys, list every key gitcomm knows with the type of its value.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if configListKeys {
+			for _, key := range config.Keys {
+				fmt.Printf("%-40s %s\n", strings.Replace(key.Name, "*", "<provider>", 1), key.Kind)
+			}
+			return
+		}
+
+		settings, err := config.ListValues(resolveConfigPath())
+		if err != nil {
+			ui.PrintError("failed to read configuration", err)
+			os.Exit(1)
+		}
+		for _, setting := range settings {
+			fmt.Printf("%s=%s\n", setting.Key, maskSecret(setting))
+		}
+	},
+}
+
+// configEditCmd opens the config file in the editor
+var configEditCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Open the config file in $EDITOR and validate it on save",
+	Long: `Open a copy of the config file in $VISUAL or $EDITOR (default: vi). When the
+editor exits, the copy is validated before it replaces the config file; an
+invalid configuration can be edited again or discarded.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := editConfig(resolveConfigPath()); err != nil {
+			if !errors.Is(err, errEditDiscarded) {
+				ui.PrintError("failed to edit configuration", err)
+			}
+			os.Exit(1)
+		}
+	},
+}
+
+// resolveConfigPath returns the path of the config file, exiting when it cannot be found
+func resolveConfigPath() string {
+	path, err := config.ResolvePath(configPath)
+	if err != nil {
+		ui.PrintError("failed to locate configuration", err)
+		os.Exit(1)
+	}
+	return path
+}
+
+// maskSecret returns the value of setting, masked when it is a secret written in clear
+func maskSecret(setting config.Setting) string {
+	if !setting.Secret || setting.Value == "" || strings.HasPrefix(setting.Value, "${") || strings.HasPrefix(setting.Value, keyring.Prefix) {
+		return setting.Value
+	}
+	return "********"
+}
+
+// editConfig edits a copy of the config file at path until it is valid or the edit is
+// discarded, then replaces the file with it
+func editConfig(path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	// The copy is private: the config file may hold secrets
+	file, err := os.CreateTemp("", "gitcomm-config-*.yaml")
+	if err != nil {
+		return fmt.Errorf("failed to create a copy of the config file: %w", err)
+	}
+	defer os.Remove(file.Name())
+	_, err = file.Write(content)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create a copy of the config file: %w", err)
+	}
+
+	reader := bufio.NewReader(ui.Stdin())
+	for {
+		if err := ui.OpenEditor(file.Name()); err != nil {
+			return err
+		}
+		edited, err := os.ReadFile(file.Name())
+		if err != nil {
+			return fmt.Errorf("failed to read the edited config file: %w", err)
+		}
+		if bytes.Equal(edited, content) {
+			fmt.Fprintln(ui.Out(), "No changes")
+			return nil
+		}
+
+		if unknown, err := config.UnknownKeys(edited); err == nil && len(unknown) > 0 {
+			fmt.Fprintf(os.Stderr, "Warning: unknown keys, ignored by gitcomm: %s\n", strings.Join(unknown, ", "))
+		}
+		err = config.WriteFile(path, edited)
+		if err == nil {
+			fmt.Fprintf(ui.Out(), "✓ %s saved\n", path)
+			return nil
+		}
+
+		ui.PrintError("invalid configuration", err)
+		again, promptErr := ui.PromptConfirm(reader, "Edit again?", !ui.NonInteractive())
+		if promptErr != nil || !again {
+			fmt.Fprintln(ui.Out(), "Changes discarded: the config file was not modified")
+			return errEditDiscarded
+		}
+	}
+}
+
+func init() {
+	configListCmd.Flags().BoolVar(&configListKeys, "keys", false, "List every configuration key with the type of its value")
+	configCmd.AddCommand(configGetCmd, configSetCmd, configUnsetCmd, configListCmd, configEditCmd)
+	rootCmd.AddCommand(configCmd)
+}
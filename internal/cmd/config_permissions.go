@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+
+	"github.com/golgoth31/gitcomm/internal/config"
+	"github.com/golgoth31/gitcomm/internal/ui"
+	"github.com/golgoth31/gitcomm/internal/utils"
+)
+
+// warnConfigExposure warns when the config file holding secrets is readable by other users
+// or synced to the cloud, and offers to restrict its permissions. Created config files are
+// already private; this covers files written by hand or copied around.
+func warnConfigExposure(cfg *config.Config) {
+	if !cfg.Security.CheckPermissions || !cfg.File.HasSecrets {
+		return
+	}
+	exposure, err := config.CheckFileExposure(cfg.File.Path, cfg.Security.SyncedDirs)
+	if err != nil {
+		utils.Logger.Debug().Err(err).Msg("Failed to check config file permissions")
+		return
+	}
+	if !exposure.Exposed() {
+		return
+	}
+
+	for _, warning := range exposure.Warnings() {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+	}
+	if !exposure.OthersCanRead {
+		return
+	}
+	if ui.NonInteractive() {
+		fmt.Fprintf(os.Stderr, "Run chmod 600 %s to make it private.\n", exposure.Path)
+		return
+	}
+
+	restrict, err := ui.PromptConfirm(bufio.NewReader(ui.Stdin()), "Make the config file private (chmod 600)?", true)
+	if err != nil || !restrict {
+		return
+	}
+	if err := config.RestrictPermissions(exposure.Path); err != nil {
+		ui.PrintError("failed to secure the config file", err)
+	}
+}
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/golgoth31/gitcomm/internal/repository"
+	"github.com/golgoth31/gitcomm/internal/service"
+	"github.com/golgoth31/gitcomm/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var dcoCheckCount int
+
+// dcoCmd groups Developer Certificate of Origin commands
+var dcoCmd = &cobra.Command{
+	Use:   "dco",
+	Short: "Developer Certificate of Origin helpers",
+	Long: `Commands for projects that require the Developer Certificate of Origin (DCO).
+
+Use the --dco flag (or commit.dco: true in the config file) on the main command
+to enforce a Signed-off-by line matching the author on new commits.`,
+}
+
+// dcoCheckCmd verifies Signed-off-by trailers on existing commits
+var dcoCheckCmd = &cobra.Command{
+	Use:   "check [revision]",
+	Short: "Verify that recent commits are signed off by their author",
+	Long: `Verify that the last N non-merge commits carry a Signed-off-by line
+matching the commit author. Exits with status 1 if any commit fails.
+
+Examples:
+  # Check the last 10 commits on HEAD
+  gitcomm dco check
+
+  # Check the commits of a branch not yet in main
+  gitcomm dco check main..HEAD -n 0`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		revision := ""
+		if len(args) == 1 {
+			revision = args[0]
+		}
+
+		gitRepo, err := repository.NewGitRepository("", true, noRTK)
+		if err != nil {
+			ui.PrintError("failed to initialize git repository", err)
+			os.Exit(1)
+		}
+		exitIfDisabled(context.Background(), gitRepo)
+
+		results, err := service.NewDCOService(gitRepo).Check(context.Background(), revision, dcoCheckCount)
+		if err != nil {
+			ui.PrintError("DCO check failed", err)
+			os.Exit(1)
+		}
+
+		width := ui.TerminalWidth()
+		failed := 0
+		for _, result := range results {
+			if result.Valid {
+				fmt.Fprintln(ui.Out(), ui.TruncateEnd(fmt.Sprintf("✓ %s %s", result.Commit.ShortHash(), result.Commit.Subject()), width))
+				continue
+			}
+			failed++
+			// Keep the reason visible: shorten the subject only
+			line := fmt.Sprintf("✗ %s ", result.Commit.ShortHash())
+			subjectWidth := width - len(line) - len(result.Reason) - 2
+			fmt.Fprintf(ui.Out(), "%s%s: %s\n", line, ui.TruncateEnd(result.Commit.Subject(), max(subjectWidth, 10)), result.Reason)
+		}
+
+		if failed > 0 {
+			fmt.Fprintf(os.Stderr, "%d of %d commits fail the DCO check\n", failed, len(results))
+			os.Exit(1)
+		}
+		fmt.Fprintf(ui.Out(), "All %d commits are signed off by their author\n", len(results))
+	},
+}
+
+func init() {
+	dcoCheckCmd.Flags().IntVarP(&dcoCheckCount, "count", "n", 10, "Number of commits to check (0 for all)")
+	dcoCmd.AddCommand(dcoCheckCmd)
+	rootCmd.AddCommand(dcoCmd)
+}
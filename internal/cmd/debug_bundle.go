@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/golgoth31/gitcomm/internal/config"
+	"github.com/golgoth31/gitcomm/internal/diagnostics"
+	"github.com/golgoth31/gitcomm/internal/repository"
+	"github.com/golgoth31/gitcomm/internal/ui"
+	"github.com/golgoth31/gitcomm/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+var debugBundleOutput string
+
+// debugBundleCmd collects troubleshooting information for bug reports
+var debugBundleCmd = &cobra.Command{
+	Use:   "debug-bundle",
+	Short: "Collect sanitized troubleshooting information for a bug report",
+	Long: `Collect version, OS and git details, the configuration file with secrets
+redacted, repository stats (no file names or contents) and the most recent
+errors into a .tar.gz archive you can attach to an issue.
+
+API keys, passwords, tokens and email addresses are redacted; environment
+variables are only reported as set or unset. Review the archive before sharing it.
+
+Examples:
+  gitcomm debug-bundle
+  gitcomm debug-bundle -o /tmp/gitcomm-debug.tar.gz`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		path, err := config.ResolvePath(configPath)
+		if err != nil {
+			ui.PrintError("failed to locate configuration", err)
+			os.Exit(1)
+		}
+
+		// Outside a repository the bundle is still useful
+		var gitRepo repository.GitRepository
+		if repo, err := repository.NewGitRepository("", true, noRTK); err == nil {
+			gitRepo = repo
+		} else {
+			utils.Logger.Debug().Err(err).Msg("No git repository for debug bundle")
+		}
+
+		output := debugBundleOutput
+		if output == "" {
+			output = fmt.Sprintf("gitcomm-debug-%s.tar.gz", time.Now().Format("20060102-150405"))
+		}
+
+		file, err := os.OpenFile(output, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+		if err != nil {
+			ui.PrintError("failed to create debug bundle", err)
+			os.Exit(1)
+		}
+		files := diagnostics.Collect(context.Background(), path, gitRepo)
+		dir := strings.TrimSuffix(strings.TrimSuffix(filepath.Base(output), ".gz"), ".tar")
+		if err := diagnostics.WriteTarball(file, dir, files); err != nil {
+			file.Close()
+			ui.PrintError("failed to write debug bundle", err)
+			os.Exit(1)
+		}
+		if err := file.Close(); err != nil {
+			ui.PrintError("failed to write debug bundle", err)
+			os.Exit(1)
+		}
+
+		fmt.Fprintf(ui.Out(), "✓ Debug bundle written to %s\n", output)
+		fmt.Fprintln(ui.Out(), "Review its content before attaching it to an issue.")
+	},
+}
+
+func init() {
+	debugBundleCmd.Flags().StringVarP(&debugBundleOutput, "output", "o", "", "Archive path (default: gitcomm-debug-<timestamp>.tar.gz)")
+	rootCmd.AddCommand(debugBundleCmd)
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/golgoth31/gitcomm/internal/service"
+	"github.com/golgoth31/gitcomm/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var doctorOffline bool
+
+// doctorCmd checks the environment gitcomm runs in
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check git, the signing key, the config file and the AI provider",
+	Long: `Check the environment gitcomm runs in and print what to fix:
+
+  - git is installed in a supported version
+  - the current directory is in a git repository
+  - user.name and user.email are set
+  - the signing key, if any, can be read and used
+  - the config file is valid
+  - the AI provider answers a tiny test request (skipped with --offline)
+  - the terminal can draw the interactive prompts
+
+The command exits with status 1 when a check fails; warnings only limit
+what gitcomm can do.
+
+Examples:
+  gitcomm doctor
+  gitcomm doctor --offline
+  gitcomm doctor --provider anthropic`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		checks := service.NewDoctorService("", configPath, provider, account, doctorOffline).Run(context.Background())
+		if printDoctorReport(checks) {
+			os.Exit(1)
+		}
+	},
+}
+
+// printDoctorReport prints one line per check, with the fix of warnings and failures,
+// then the counts; it reports whether a check failed
+func printDoctorReport(checks []service.DoctorCheck) bool {
+	width := ui.TerminalWidth()
+	var warnings, failures int
+	for _, check := range checks {
+		mark := "✓"
+		switch check.Status {
+		case service.CheckWarn:
+			mark = "!"
+			warnings++
+		case service.CheckFail:
+			mark = "✗"
+			failures++
+		case service.CheckSkip:
+			mark = "-"
+		}
+		fmt.Fprintln(ui.Out(), ui.TruncateEnd(fmt.Sprintf("%s %-15s %s", mark, check.Name, check.Detail), width))
+		if check.Fix != "" {
+			fmt.Fprintf(ui.Out(), "  %-15s Fix: %s\n", "", check.Fix)
+		}
+	}
+
+	fmt.Fprintf(ui.Out(), "\n%d check(s): %d failed, %d warning(s)\n", len(checks), failures, warnings)
+	return failures > 0
+}
+
+func init() {
+	doctorCmd.Flags().BoolVar(&doctorOffline, "offline", false, "Do not send the test request to the AI provider")
+	rootCmd.AddCommand(doctorCmd)
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/golgoth31/gitcomm/internal/config"
+	"github.com/golgoth31/gitcomm/internal/service"
+	"github.com/golgoth31/gitcomm/internal/store"
+	"github.com/golgoth31/gitcomm/internal/ui"
+	"github.com/golgoth31/gitcomm/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+var historySince string
+
+// historyCmd lists the commits generated across repositories
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "List the commits gitcomm generated across repositories",
+	Long: `List the commits created with an AI generated message in any repository, newest
+first, with the provider and model that wrote them, followed by the requests
+sent to each provider model and their estimated tokens.
+
+Nothing is recorded until history is enabled; commits and usage are then kept
+in ~/.gitcomm/gitcomm.db:
+
+  history:
+    enabled: true
+
+Examples:
+  gitcomm history
+  gitcomm history --since 1d
+  gitcomm history --since 2026-01-01`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		now := time.Now()
+		since, err := service.ParseSince(historySince, now)
+		if err != nil {
+			ui.PrintError("invalid options", err)
+			os.Exit(1)
+		}
+
+		cfg, err := config.LoadConfig(configPath)
+		if err != nil {
+			utils.Logger.Debug().Err(err).Msg("Failed to load configuration, continuing with defaults")
+			cfg = &config.Config{}
+		}
+		dates, err := cfg.DateFormatter()
+		if err != nil {
+			ui.PrintError("invalid dates configuration", err)
+			os.Exit(1)
+		}
+		if !cfg.History.Enabled {
+			fmt.Fprintln(os.Stderr, "history.enabled is not set: new commits are not recorded")
+		}
+
+		ctx := context.Background()
+		path, err := store.DefaultPath()
+		if err != nil {
+			ui.PrintError("failed to locate the history", err)
+			os.Exit(1)
+		}
+		st, err := store.Open(ctx, path)
+		if err != nil {
+			ui.PrintError("failed to open the history", err)
+			os.Exit(1)
+		}
+		defer st.Close()
+
+		commits, err := st.Commits(ctx, since)
+		if err != nil {
+			ui.PrintError("failed to read the history", err)
+			os.Exit(1)
+		}
+		totals, err := st.UsageTotals(ctx, since)
+		if err != nil {
+			ui.PrintError("failed to read the history", err)
+			os.Exit(1)
+		}
+		ui.PrintHistory(commits, totals, since, dates)
+	},
+}
+
+// openHistory opens the store recording the generated commits and provider usage when
+// history.enabled is set. Returns nil when disabled or unavailable: recording never fails
+// a command.
+func openHistory(ctx context.Context, cfg *config.Config) store.Store {
+	if !cfg.History.Enabled {
+		return nil
+	}
+	// A nil *store.SQLite must not become a non-nil Store
+	st := openStore(ctx)
+	if st == nil {
+		return nil
+	}
+	return st
+}
+
+// openStore opens the gitcomm database, nil when it is unavailable
+func openStore(ctx context.Context) *store.SQLite {
+	path, err := store.DefaultPath()
+	if err != nil {
+		utils.Logger.Debug().Err(err).Msg("Store unavailable")
+		return nil
+	}
+	st, err := store.Open(ctx, path)
+	if err != nil {
+		utils.Logger.Debug().Err(err).Msg("Store unavailable")
+		return nil
+	}
+	return st
+}
+
+func init() {
+	historyCmd.Flags().StringVar(&historySince, "since", "1w", "Start of the period: a duration back from now (12h, 3d, 2w) or a date (2006-01-02)")
+	rootCmd.AddCommand(historyCmd)
+}
@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/golgoth31/gitcomm/internal/config"
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/repository"
+	"github.com/golgoth31/gitcomm/internal/service"
+	"github.com/golgoth31/gitcomm/internal/ui"
+	"github.com/golgoth31/gitcomm/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+var hookForce bool
+
+// hookCmd groups the git hook integration commands
+var hookCmd = &cobra.Command{
+	Use:   "hook",
+	Short: "Draft the messages of plain git commit with a git hook",
+	Long: `Use gitcomm as a prepare-commit-msg git hook: git commit opens the editor with
+a message generated from the staged changes, ready to be edited or accepted.
+
+Examples:
+  gitcomm hook install
+  git commit
+  gitcomm hook uninstall`,
+}
+
+// hookPrepareCmd is run by git as the prepare-commit-msg hook
+var hookPrepareCmd = &cobra.Command{
+	Use:   "prepare-commit-msg <message-file> [source] [commit]",
+	Short: "Write a generated message into the message file of git commit",
+	Long: `Generate a message for the staged changes and write it into the message file
+git passes to the prepare-commit-msg hook, above the comments git put there.
+Nothing is done when the message is given (-m, -F), and for merges, squashes
+and amends.
+
+The hook never blocks the commit: when the message cannot be generated (no AI
+provider, provider failure, gitcomm disabled in the repository), the reason is
+printed and git commit goes on with an empty message.
+
+Run by the hook installed with gitcomm hook install; in a hook manager, call:
+  gitcomm hook prepare-commit-msg "$1" "$2" "$3"`,
+	Args: cobra.RangeArgs(1, 3),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		source := ""
+		if len(args) > 1 {
+			source = args[1]
+		}
+
+		// git runs hooks without stdin: nothing can be asked, and git owns stdout
+		ui.SetNonInteractive(true)
+		ui.SetSeparateStreams(true)
+
+		if err := prepareCommitMessage(ctx, args[0], source); err != nil {
+			if errors.Is(err, utils.ErrNoChanges) || errors.Is(err, utils.ErrRepositoryDisabled) {
+				utils.Logger.Debug().Err(err).Msg("No commit message drafted")
+				return
+			}
+			fmt.Fprintf(os.Stderr, "gitcomm: no commit message drafted: %v\n", err)
+		}
+	},
+}
+
+// prepareCommitMessage drafts the message of git commit in msgFile
+func prepareCommitMessage(ctx context.Context, msgFile, source string) error {
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		utils.Logger.Debug().Err(err).Msg("Failed to load configuration, continuing with defaults")
+		cfg = &config.Config{}
+	}
+
+	gitRepo, err := repository.NewGitRepository("", true, noRTK)
+	if err != nil {
+		return err
+	}
+	if err := service.CheckEnabled(ctx, gitRepo); err != nil {
+		return err
+	}
+
+	options := &model.CommitOptions{
+		AIProvider:   provider,
+		Account:      account,
+		SummaryFirst: summaryFirst,
+	}
+	warnConfigExposure(cfg)
+	commitService := service.NewCommitService(gitRepo, options, cfg)
+	if history := openHistory(ctx, cfg); history != nil {
+		defer history.Close()
+		commitService.SetStore(history)
+	}
+	_, err = commitService.PrepareCommitMessage(ctx, msgFile, source)
+	return err
+}
+
+// hookInstallCmd installs the prepare-commit-msg hook
+var hookInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Install the prepare-commit-msg hook in the current repository",
+	Long: `Install a prepare-commit-msg hook running gitcomm hook prepare-commit-msg, in
+the hooks directory of the repository (core.hooksPath when set). An existing
+hook not installed by gitcomm is only replaced with --force.
+
+Examples:
+  gitcomm hook install
+  gitcomm hook install --force`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+		gitRepo, err := repository.NewGitRepository("", true, noRTK)
+		if err != nil {
+			ui.PrintError("failed to initialize git repository", err)
+			os.Exit(1)
+		}
+		path, err := service.InstallHook(ctx, gitRepo, hookForce)
+		if err != nil {
+			ui.PrintError("failed to install the hook", err)
+			os.Exit(1)
+		}
+		fmt.Fprintf(ui.Out(), "✓ Hook installed: %s\n", path)
+		fmt.Fprintln(ui.Out(), "  git commit now opens the editor with a generated message")
+	},
+}
+
+// hookUninstallCmd removes the prepare-commit-msg hook
+var hookUninstallCmd = &cobra.Command{
+	Use:   "uninstall",
+	Short: "Remove the prepare-commit-msg hook installed by gitcomm",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+		gitRepo, err := repository.NewGitRepository("", true, noRTK)
+		if err != nil {
+			ui.PrintError("failed to initialize git repository", err)
+			os.Exit(1)
+		}
+		path, err := service.UninstallHook(ctx, gitRepo)
+		if err != nil {
+			ui.PrintError("failed to remove the hook", err)
+			os.Exit(1)
+		}
+		fmt.Fprintf(ui.Out(), "✓ Hook removed: %s\n", path)
+	},
+}
+
+func init() {
+	hookPrepareCmd.Flags().BoolVar(&noRTK, "no-rtk", false, "Disable rtk proxy and use git directly")
+	hookPrepareCmd.Flags().BoolVar(&summaryFirst, "summary-first", false, "Send the file list first and only the diffs the AI provider asks for (for huge changes)")
+	hookInstallCmd.Flags().BoolVar(&hookForce, "force", false, "Replace a prepare-commit-msg hook not installed by gitcomm")
+	hookCmd.AddCommand(hookPrepareCmd, hookInstallCmd, hookUninstallCmd)
+	rootCmd.AddCommand(hookCmd)
+}
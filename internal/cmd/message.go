@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/golgoth31/gitcomm/internal/config"
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/repository"
+	"github.com/golgoth31/gitcomm/internal/service"
+	"github.com/golgoth31/gitcomm/internal/telemetry"
+	"github.com/golgoth31/gitcomm/internal/ui"
+	"github.com/golgoth31/gitcomm/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+// messageCmd prints a commit message for the staged changes without committing
+var messageCmd = &cobra.Command{
+	Use:   "message",
+	Short: "Print a commit message for the staged changes without committing",
+	Long: `Generate a commit message for the staged changes (with AI, or from manual input
+with --skip-ai) and print it to stdout. Nothing is staged or committed: prompts and
+messages go to stderr so the output can be piped into git or other tools.
+
+Examples:
+  # Commit the staged changes with a generated message
+  gitcomm message | git commit -s -F -
+
+  # Without any prompt, failing instead of asking
+  git commit -m "$(gitcomm message --yes)"`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		cfg, err := config.LoadConfig(configPath)
+		if err != nil {
+			utils.Logger.Debug().Err(err).Msg("Failed to load configuration, continuing with defaults")
+			cfg = &config.Config{}
+		}
+
+		gitRepo, err := repository.NewGitRepository("", true, noRTK)
+		if err != nil {
+			ui.PrintError("failed to initialize git repository", err)
+			os.Exit(1)
+		}
+		exitIfDisabled(ctx, gitRepo)
+
+		options := &model.CommitOptions{
+			AIProvider:     provider,
+			Account:        account,
+			SkipAI:         skipAI,
+			SessionContext: sessionContext,
+			SummaryFirst:   summaryFirst,
+		}
+
+		// Only the message goes to stdout: the workflow output is sent to stderr
+		ui.SetSeparateStreams(true)
+		warnConfigExposure(cfg)
+		flushTraces := startTracing(ctx, cfg)
+		workflowCtx, span := telemetry.Start(ctx, "gitcomm message")
+		commitService := service.NewCommitService(gitRepo, options, cfg)
+		if history := openHistory(ctx, cfg); history != nil {
+			defer history.Close()
+			commitService.SetStore(history)
+		}
+		message, err := commitService.GenerateMessage(workflowCtx)
+		telemetry.End(span, err)
+		flushTraces()
+
+		if err != nil {
+			if errors.Is(err, utils.ErrNoChanges) {
+				fmt.Fprintln(os.Stderr, "No staged changes: stage the changes to describe first.")
+				os.Exit(1)
+			}
+			ui.PrintError("message generation failed", err)
+			os.Exit(1)
+		}
+		fmt.Println(message)
+	},
+}
+
+func init() {
+	messageCmd.Flags().BoolVar(&skipAI, "skip-ai", false, "Skip AI generation and proceed directly to manual input")
+	messageCmd.Flags().BoolVar(&noRTK, "no-rtk", false, "Disable rtk proxy and use git directly")
+	messageCmd.Flags().BoolVar(&sessionContext, "session-context", false, "Include your previous commit on the same files in the AI prompt")
+	messageCmd.Flags().BoolVar(&summaryFirst, "summary-first", false, "Send the file list first and only the diffs the AI provider asks for (for huge changes)")
+	rootCmd.AddCommand(messageCmd)
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/golgoth31/gitcomm/internal/ai"
+	"github.com/golgoth31/gitcomm/internal/config"
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/ui"
+	"github.com/golgoth31/gitcomm/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+// modelsCmd lists the models installed on the Ollama server
+var modelsCmd = &cobra.Command{
+	Use:   "models",
+	Short: "List the models installed on the Ollama server",
+	Long: `List the models installed on the Ollama server of the ollama provider
+(ai.providers.ollama.endpoint, default: http://localhost:11434). The configured
+model is marked with "*"; set it with ai.providers.ollama.model.
+
+Examples:
+  gitcomm models`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		providerConfig := &model.AIProviderConfig{Name: "ollama"}
+		if cfg, err := config.LoadConfig(configPath); err != nil {
+			utils.Logger.Debug().Err(err).Msg("Failed to load configuration, continuing with defaults")
+		} else if configured, err := cfg.GetProviderConfig("ollama"); err == nil {
+			providerConfig = configured
+		}
+
+		names, err := ai.NewOllamaProvider(providerConfig).ListModels(ctx)
+		if err != nil {
+			ui.PrintError("failed to list the Ollama models", err)
+			os.Exit(1)
+		}
+		if len(names) == 0 {
+			fmt.Fprintln(ui.Out(), "No model installed, pull one with: ollama pull <model>")
+			return
+		}
+		for _, name := range names {
+			marker := " "
+			if name == providerConfig.Model {
+				marker = "*"
+			}
+			fmt.Printf("%s %s\n", marker, name)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(modelsCmd)
+}
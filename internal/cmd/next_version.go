@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/golgoth31/gitcomm/internal/config"
+	"github.com/golgoth31/gitcomm/internal/repository"
+	"github.com/golgoth31/gitcomm/internal/service"
+	"github.com/golgoth31/gitcomm/internal/ui"
+	"github.com/golgoth31/gitcomm/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+var nextVersionBare bool
+
+// nextVersionCmd prints the version the commits since the last release call for
+var nextVersionCmd = &cobra.Command{
+	Use:   "next-version",
+	Short: "Print the next semantic version from the commits since the last release",
+	Long: `Analyze the commits since the last release tag like semantic-release does
+(feat: minor, fix and perf: patch, BREAKING CHANGE footer or "!" after the type:
+major, reverts: patch) and print the tag of the next version, e.g. v1.4.0.
+
+Release tags are named release.tag_prefix (default "v") followed by the version.
+Without a release tag, the first release is 1.0.0. When no commit calls for a
+release, nothing is printed on stdout.
+
+Examples:
+  gitcomm next-version
+  VERSION=$(gitcomm next-version --bare)`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.LoadConfig(configPath)
+		if err != nil {
+			utils.Logger.Debug().Err(err).Msg("Failed to load configuration, continuing with defaults")
+			cfg = &config.Config{Release: config.ReleaseConfig{TagPrefix: config.DefaultTagPrefix}}
+		}
+
+		gitRepo, err := repository.NewGitRepository("", true, noRTK)
+		if err != nil {
+			ui.PrintError("failed to initialize git repository", err)
+			os.Exit(1)
+		}
+		exitIfDisabled(context.Background(), gitRepo)
+
+		plan, err := service.NewReleaseService(gitRepo, cfg).Plan(context.Background())
+		if err != nil {
+			ui.PrintError("failed to analyze commits", err)
+			os.Exit(1)
+		}
+
+		fmt.Fprintln(os.Stderr, plan)
+		if !plan.HasRelease() {
+			return
+		}
+		if nextVersionBare {
+			fmt.Println(plan.Next)
+			return
+		}
+		fmt.Println(plan.NextTag)
+	},
+}
+
+func init() {
+	nextVersionCmd.Flags().BoolVar(&nextVersionBare, "bare", false, "Print the version without the tag prefix")
+	rootCmd.AddCommand(nextVersionCmd)
+}
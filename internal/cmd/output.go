@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/golgoth31/gitcomm/internal/config"
+	"github.com/golgoth31/gitcomm/internal/ui"
+	"github.com/golgoth31/gitcomm/internal/utils"
+)
+
+// applyOutputStreams applies output.separate_streams before any command runs, so the
+// decorations of the commands that do not load the configuration (auth, config, doctor...)
+// follow it too. The key is read as written in the file, which is not created when missing.
+func applyOutputStreams() {
+	path, err := config.ResolvePath(configPath)
+	if err != nil {
+		return
+	}
+	value, ok, err := config.GetValue(path, "output.separate_streams")
+	if err != nil {
+		utils.Logger.Debug().Err(err).Msg("Failed to read output.separate_streams")
+		return
+	}
+	if !ok {
+		return
+	}
+	separate, err := strconv.ParseBool(value)
+	if err != nil {
+		return
+	}
+	ui.SetSeparateStreams(separate)
+}
+
+// separateStreams sends the output of the workflow (prompts, progress, decorations) to
+// stderr when output.separate_streams is set, so that `sha=$(gitcomm -a --yes)` gets the
+// results only. It returns the function printing the results on stdout, one per line,
+// which does nothing when the streams are not separated: the workflow already showed them.
+func separateStreams(cfg *config.Config) (printResults func(results ...string)) {
+	if !cfg.Output.SeparateStreams {
+		return func(...string) {}
+	}
+	ui.SetSeparateStreams(true)
+	return func(results ...string) {
+		for _, result := range results {
+			fmt.Fprintln(os.Stdout, result)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golgoth31/gitcomm/internal/config"
+	"github.com/golgoth31/gitcomm/internal/ui"
+)
+
+func TestSeparateStreams(t *testing.T) {
+	tests := []struct {
+		name       string
+		separate   bool
+		wantStdout string
+		wantStderr string
+	}{
+		{"separated", true, "0123abcd\n", "✓ Commit created successfully\n"},
+		{"not separated", false, "✓ Commit created successfully\n", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdout, stderr := capture(t, func() {
+				printResults := separateStreams(&config.Config{Output: config.OutputConfig{SeparateStreams: tt.separate}})
+				defer ui.SetSeparateStreams(false)
+				fmt.Fprintln(ui.Out(), "✓ Commit created successfully")
+				printResults("0123abcd")
+			})
+			if stdout != tt.wantStdout || stderr != tt.wantStderr {
+				t.Errorf("stdout = %q, stderr = %q, want %q and %q", stdout, stderr, tt.wantStdout, tt.wantStderr)
+			}
+		})
+	}
+}
+
+// capture runs fn with os.Stdout and os.Stderr redirected, and returns what it wrote
+func capture(t *testing.T, fn func()) (stdout, stderr string) {
+	t.Helper()
+	outR, outW, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	errR, errW, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	savedOut, savedErr := os.Stdout, os.Stderr
+	os.Stdout, os.Stderr = outW, errW
+	fn()
+	os.Stdout, os.Stderr = savedOut, savedErr
+	_ = outW.Close()
+	_ = errW.Close()
+
+	out, _ := io.ReadAll(outR)
+	errOut, _ := io.ReadAll(errR)
+	return string(out), string(errOut)
+}
+
+func TestApplyOutputStreams(t *testing.T) {
+	dir := t.TempDir()
+	defer ui.SetSeparateStreams(false)
+	saved := configPath
+	defer func() { configPath = saved }()
+
+	configPath = filepath.Join(dir, "missing.yaml")
+	applyOutputStreams()
+	if ui.SeparateStreams() {
+		t.Error("SeparateStreams() = true without a config file, want false")
+	}
+	if _, err := os.Stat(configPath); !os.IsNotExist(err) {
+		t.Errorf("config file created: stat error = %v", err)
+	}
+
+	configPath = filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(configPath, []byte("output:\n  separate_streams: true\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	applyOutputStreams()
+	if !ui.SeparateStreams() {
+		t.Error("SeparateStreams() = false with output.separate_streams: true")
+	}
+	stdout, stderr := capture(t, func() { fmt.Fprintln(ui.Out(), "✓ API key stored") })
+	if stdout != "" || stderr != "✓ API key stored\n" {
+		t.Errorf("stdout = %q, stderr = %q, want the decoration on stderr", stdout, stderr)
+	}
+}
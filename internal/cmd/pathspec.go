@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/golgoth31/gitcomm/internal/repository"
+	"github.com/golgoth31/gitcomm/pkg/git/gitdir"
+	"github.com/spf13/cobra"
+)
+
+// pathspecArgs accepts pathspecs after "--" only (gitcomm -- src/api/ docs/), so that a
+// mistyped subcommand is still reported as such
+func pathspecArgs(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 || cmd.ArgsLenAtDash() == 0 {
+		return nil
+	}
+	if cmd.HasSubCommands() {
+		message := fmt.Sprintf("unknown command %q for %q", args[0], cmd.CommandPath())
+		if suggestions := cmd.SuggestionsFor(args[0]); len(suggestions) > 0 {
+			message += "\n\nDid you mean this?\n\t" + strings.Join(suggestions, "\n\t")
+		}
+		return errors.New(message)
+	}
+	return fmt.Errorf("pathspecs must follow \"--\": %s -- %s", cmd.CommandPath(), strings.Join(args, " "))
+}
+
+// resolvePathspecs makes the pathspecs given from the current directory relative to the
+// worktree root
+func resolvePathspecs(pathspecs []string) ([]string, error) {
+	if len(pathspecs) == 0 {
+		return nil, nil
+	}
+	dir, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current directory: %w", err)
+	}
+	location, err := gitdir.Find(dir)
+	if err != nil {
+		return nil, err
+	}
+	if location.Bare() {
+		return nil, fmt.Errorf("pathspecs need a worktree, %s is a bare repository", location.GitDir)
+	}
+	return repository.ResolvePathspecs(location.WorkTree, dir, pathspecs)
+}
+
+// validatePathspecOptions checks that pathspecs, which limit a git commit of the current
+// branch, are not combined with flags committing another way
+func validatePathspecOptions(pathspecs []string, pick, patchOnly bool, branch string) error {
+	if len(pathspecs) == 0 {
+		return nil
+	}
+	switch {
+	case pick:
+		return fmt.Errorf("pathspecs cannot be combined with --interactive")
+	case branch != "":
+		return fmt.Errorf("pathspecs cannot be combined with --branch")
+	case patchOnly:
+		return fmt.Errorf("pathspecs cannot be combined with --patch-only")
+	}
+	return nil
+}
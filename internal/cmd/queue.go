@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/golgoth31/gitcomm/internal/config"
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/repository"
+	"github.com/golgoth31/gitcomm/internal/service"
+	"github.com/golgoth31/gitcomm/internal/ui"
+	"github.com/golgoth31/gitcomm/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+// queueCmd groups commands for commits queued while the AI provider was unreachable
+var queueCmd = &cobra.Command{
+	Use:   "queue",
+	Short: "Manage commits queued with a placeholder message",
+	Long: `When the AI provider is unreachable, gitcomm offers to commit the staged
+changes with a placeholder message and queue the commit. Once connectivity
+returns, "gitcomm queue flush" generates the real messages and rewords the
+placeholder commits that have not been pushed yet.`,
+}
+
+// queueListCmd lists queued commits
+var queueListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List queued commits",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		gitRepo, err := repository.NewGitRepository("", true, noRTK)
+		if err != nil {
+			ui.PrintError("failed to initialize git repository", err)
+			os.Exit(1)
+		}
+		exitIfDisabled(context.Background(), gitRepo)
+
+		cfg, err := config.LoadConfig(configPath)
+		if err != nil {
+			utils.Logger.Debug().Err(err).Msg("Failed to load configuration, continuing with defaults")
+			cfg = &config.Config{}
+		}
+		dates, err := cfg.DateFormatter()
+		if err != nil {
+			ui.PrintError("invalid dates configuration", err)
+			os.Exit(1)
+		}
+
+		entries, err := service.NewQueueService(gitRepo, nil, cfg).List(context.Background())
+		if err != nil {
+			ui.PrintError("failed to read commit queue", err)
+			os.Exit(1)
+		}
+		if len(entries) == 0 {
+			fmt.Fprintln(ui.Out(), "No queued commits")
+			return
+		}
+		var sb strings.Builder
+		for _, entry := range entries {
+			info := model.CommitInfo{Hash: entry.Hash}
+			fmt.Fprintf(&sb, "%s  %s  %s\n", info.ShortHash(), dates.Format(entry.QueuedAt), entry.Branch)
+		}
+		if err := ui.PrintPaged(sb.String()); err != nil {
+			utils.Logger.Debug().Err(err).Msg("Failed to display commit queue")
+		}
+	},
+}
+
+// queueFlushCmd regenerates messages of queued commits
+var queueFlushCmd = &cobra.Command{
+	Use:   "flush",
+	Short: "Generate messages for queued commits and reword them",
+	Long: `Generate a message with the AI provider for each queued commit on the current
+branch, ask for confirmation, then reword the accepted commits. Commits after
+them are recreated unchanged. Commits already pushed are dropped from the
+queue and must be reworded manually. If the provider fails again, the commits
+accepted so far are still reworded and the others stay queued.
+
+The reflog entry restoring the previous history is printed before the rewrite.
+With --yes, --confirm must give the branch name or the confirmation token given
+in the error.
+
+Examples:
+  gitcomm queue flush
+  gitcomm queue flush --provider anthropic
+  gitcomm queue flush --yes --confirm main`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.LoadConfig(configPath)
+		if err != nil {
+			utils.Logger.Debug().Err(err).Msg("Failed to load configuration, continuing with defaults")
+			cfg = &config.Config{}
+		}
+
+		identity, err := resolveSignoffIdentity("", cfg)
+		if err != nil {
+			ui.PrintError("invalid options", err)
+			os.Exit(1)
+		}
+
+		gitRepo, err := repository.NewGitRepository("", false, noRTK)
+		if err != nil {
+			ui.PrintError("failed to initialize git repository", err)
+			os.Exit(1)
+		}
+		exitIfDisabled(context.Background(), gitRepo)
+
+		options := &model.CommitOptions{
+			SignoffIdentity: identity,
+			DCO:             cfg.Commit.DCO,
+			AIProvider:      provider,
+			Account:         account,
+		}
+		count, err := service.NewQueueService(gitRepo, options, cfg).Flush(context.Background(), confirmRewrite)
+		if err != nil {
+			if count > 0 {
+				fmt.Fprintf(ui.Out(), "✓ %d queued commit(s) reworded, the others stay queued\n", count)
+			}
+			ui.PrintError("queue flush failed", err)
+			os.Exit(1)
+		}
+		fmt.Fprintf(ui.Out(), "✓ %d queued commit(s) reworded\n", count)
+	},
+}
+
+func init() {
+	queueCmd.AddCommand(queueListCmd)
+	queueFlushCmd.Flags().StringVar(&confirmRewrite, "confirm", "", "Branch name or confirmation token required with --yes")
+	queueCmd.AddCommand(queueFlushCmd)
+	rootCmd.AddCommand(queueCmd)
+}
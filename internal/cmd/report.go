@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/golgoth31/gitcomm/internal/config"
+	"github.com/golgoth31/gitcomm/internal/service"
+	"github.com/golgoth31/gitcomm/internal/ui"
+	"github.com/golgoth31/gitcomm/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+var reportSince string
+
+// reportCmd summarizes the commits of the git user across repositories
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Summarize your recent commits across repositories",
+	Long: `Summarize the commits you made on any local branch of the repositories of
+report.repositories (the current repository when none is configured): the
+number of commits by type, the repositories touched, the active days and the
+current streak, and the average message score, rated offline against the
+Conventional Commits rules. Handy for standups and self-review.
+
+Your commits are the ones authored with the user.email of each repository.
+Repositories can be listed by path or glob pattern:
+
+  report:
+    repositories:
+      - ~/src/gitcomm
+      - ~/work/*
+
+Examples:
+  gitcomm report
+  gitcomm report --since 1d
+  gitcomm report --since 2026-01-01`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		now := time.Now()
+		since, err := service.ParseSince(reportSince, now)
+		if err != nil {
+			ui.PrintError("invalid options", err)
+			os.Exit(1)
+		}
+
+		cfg, err := config.LoadConfig(configPath)
+		if err != nil {
+			utils.Logger.Debug().Err(err).Msg("Failed to load configuration, continuing with defaults")
+			cfg = &config.Config{}
+		}
+		dates, err := cfg.DateFormatter()
+		if err != nil {
+			ui.PrintError("invalid dates configuration", err)
+			os.Exit(1)
+		}
+		repositories, err := cfg.ReportRepositories()
+		if err != nil {
+			ui.PrintError("invalid report configuration", err)
+			os.Exit(1)
+		}
+
+		report := service.NewReportService(cfg).Build(context.Background(), repositories, since, now)
+		ui.PrintActivityReport(report, dates)
+	},
+}
+
+func init() {
+	reportCmd.Flags().StringVar(&reportSince, "since", "1w", "Start of the period: a duration back from now (12h, 3d, 2w) or a date (2006-01-02)")
+	rootCmd.AddCommand(reportCmd)
+}
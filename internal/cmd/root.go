@@ -6,8 +6,10 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
@@ -15,8 +17,11 @@ import (
 	"github.com/golgoth31/gitcomm/internal/model"
 	"github.com/golgoth31/gitcomm/internal/repository"
 	"github.com/golgoth31/gitcomm/internal/service"
+	"github.com/golgoth31/gitcomm/internal/telemetry"
+	"github.com/golgoth31/gitcomm/internal/ui"
 	"github.com/golgoth31/gitcomm/internal/utils"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 var (
@@ -24,14 +29,38 @@ var (
 	addAll     bool
 	noSignoff  bool
 	noSign     bool
+	noVerify   bool
 	noRTK      bool
 	provider   string
+	account    string
 	skipAI     bool
 	configPath string
+	plain      bool
+	assumeYes  bool // --yes and --non-interactive
+
+	signoffIdentity string
+	dcoMode         bool
+	targetBranch    string
+	exportPatchDir  string
+	saveExchangeDir string
+	patchOnly       bool
+	sendEmail       bool
+	fixupRevision   string
+	sessionContext  bool
+	summaryFirst    bool
+	pushAfter       bool
+	dryRun          bool
+	interactive     bool
+	amend           bool
+	force           bool
+	messages        []string
+	messageFile     string
+	copyMessage     bool
+	newBranch       bool
 )
 
 var rootCmd = &cobra.Command{
-	Use:   "gitcomm",
+	Use:   "gitcomm [-- <pathspec>...]",
 	Short: "Automate git commit message creation with Conventional Commits",
 	Long: `gitcomm is a CLI tool that helps you create properly formatted
 commit messages following the Conventional Commits specification.
@@ -44,6 +73,12 @@ Examples:
   # Auto-stage files and create commit
   gitcomm -a
 
+  # Choose the files and hunks to commit
+  gitcomm -i
+
+  # Only stage and commit the changes under src/api/ and docs/
+  gitcomm -- src/api/ docs/
+
   # Create commit without signoff
   gitcomm -s
 
@@ -53,14 +88,48 @@ Examples:
   # Skip AI and use manual input
   gitcomm --skip-ai
 
+  # Checkpoint staged work on another branch, staying on the current one
+  gitcomm --branch wip/checkpoint
+
+  # Fix up an earlier commit, to be squashed with git rebase --autosquash
+  gitcomm --fixup HEAD~2
+
+  # Add the staged changes to the last commit and reword it
+  gitcomm --amend
+
+  # Push after committing (to your fork in triangular workflows)
+  gitcomm --push
+
+  # Preview the message and files without committing
+  gitcomm -a --dry-run
+
+  # Commit with a given message, validated, signed off and signed, without prompts
+  gitcomm -m "fix(api): handle empty responses"
+  generate-message | gitcomm -F -
+
 For more information, visit: https://github.com/golgoth31/gitcomm`,
-	Run: runCommand,
+	Args:             pathspecArgs,
+	PersistentPreRun: applyGlobalFlags,
+	Run:              runCommand,
 }
 
-func runCommand(cmd *cobra.Command, args []string) {
-	// Initialize logger
+// applyGlobalFlags applies the persistent flags shared by every command
+func applyGlobalFlags(cmd *cobra.Command, args []string) {
 	utils.InitLogger(debug)
+	ui.SetPlain(plain)
+	ui.SetNonInteractive(assumeYes)
+	applyOutputStreams()
+}
+
+// exitIfDisabled stops the command when the repository opted out of gitcomm
+func exitIfDisabled(ctx context.Context, gitRepo repository.GitRepository) {
+	if err := service.CheckEnabled(ctx, gitRepo); err != nil {
+		ui.PrintError("refusing to run", err)
+		os.Exit(1)
+	}
+}
 
+func runCommand(cmd *cobra.Command, args []string) {
 	// Create context with cancellation for signal handling
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -75,13 +144,19 @@ func runCommand(cmd *cobra.Command, args []string) {
 		utils.Logger.Debug().Err(err).Msg("Failed to load configuration, continuing with defaults")
 		cfg = &config.Config{}
 	}
+	printResults := separateStreams(cfg)
+	warnConfigExposure(cfg)
+
+	// Look for a newer release while the workflow runs (opt-in, rate-limited)
+	updateNotice := startUpdateCheck(ctx, cfg)
 
 	// Initialize git repository early (needed for restoration)
 	gitRepo, err := repository.NewGitRepository("", noSign, noRTK)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: failed to initialize git repository: %s\n", repository.FormatErrorForDisplay(err))
+		ui.PrintError("failed to initialize git repository", err)
 		os.Exit(1)
 	}
+	exitIfDisabled(ctx, gitRepo)
 
 	// Display backend info
 	if gitRepo.UsesRTK() {
@@ -90,23 +165,120 @@ func runCommand(cmd *cobra.Command, args []string) {
 		fmt.Fprintln(os.Stderr, "Using git directly")
 	}
 
+	identity, dco, err := resolveSignoffOptions(signoffIdentity, dcoMode, noSignoff, cfg)
+	if err != nil {
+		ui.PrintError("invalid options", err)
+		os.Exit(1)
+	}
+
+	if err := validatePatchOptions(exportPatchDir, targetBranch, patchOnly, sendEmail, pushAfter); err != nil {
+		ui.PrintError("invalid options", err)
+		os.Exit(1)
+	}
+
+	// A supplied message commits without any prompt, like git commit -m
+	message, err := suppliedMessage(messages, messageFile, os.Stdin)
+	if err != nil {
+		ui.PrintError("invalid options", err)
+		os.Exit(1)
+	}
+	if err := validateMessageOptions(message, fixupRevision, interactive); err != nil {
+		ui.PrintError("invalid options", err)
+		os.Exit(1)
+	}
+	if message != "" {
+		ui.SetNonInteractive(true)
+	}
+
+	if err := validateStagingOptions(interactive, addAll, ui.NonInteractive()); err != nil {
+		ui.PrintError("invalid options", err)
+		os.Exit(1)
+	}
+
+	if err := validateAmendOptions(amend, force, fixupRevision, targetBranch, patchOnly); err != nil {
+		ui.PrintError("invalid options", err)
+		os.Exit(1)
+	}
+
+	if err := validateCopyOptions(copyMessage, dryRun, amend, pushAfter, exportPatchDir != "" || sendEmail, targetBranch); err != nil {
+		ui.PrintError("invalid options", err)
+		os.Exit(1)
+	}
+
+	if err := validateNewBranchOptions(newBranch, amend, patchOnly, copyMessage, targetBranch, fixupRevision); err != nil {
+		ui.PrintError("invalid options", err)
+		os.Exit(1)
+	}
+
+	// Pathspecs after "--" limit the commit to the matching paths, like git commit -- <paths>
+	pathspecs, err := resolvePathspecs(args)
+	if err != nil {
+		ui.PrintError("invalid pathspecs", err)
+		os.Exit(1)
+	}
+	if err := validatePathspecOptions(pathspecs, interactive, patchOnly, targetBranch); err != nil {
+		ui.PrintError("invalid options", err)
+		os.Exit(1)
+	}
+
 	// Create commit options
 	options := &model.CommitOptions{
-		AutoStage:  addAll,
-		NoSignoff:  noSignoff,
-		AIProvider: provider,
-		SkipAI:     skipAI,
+		AutoStage:       addAll,
+		Interactive:     interactive,
+		NoSignoff:       noSignoff,
+		SignoffIdentity: identity,
+		DCO:             dco,
+		Branch:          targetBranch,
+		ExportPatchDir:  exportPatchDir,
+		SaveExchangeDir: saveExchangeDir,
+		NoVerify:        noVerify,
+		PatchOnly:       patchOnly,
+		SendEmail:       sendEmail,
+		Fixup:           fixupRevision,
+		Amend:           amend,
+		Force:           force,
+		Push:            pushAfter,
+		SessionContext:  sessionContext,
+		SummaryFirst:    summaryFirst,
+		AIProvider:      provider,
+		Account:         account,
+		SkipAI:          skipAI,
+		DryRun:          dryRun,
+		Copy:            copyMessage,
+		NewBranch:       newBranch,
+		Message:         message,
+		Pathspecs:       pathspecs,
 	}
 
 	// Log CLI options
 	utils.Logger.Debug().
 		Bool("auto_stage", options.AutoStage).
+		Bool("interactive", options.Interactive).
 		Bool("no_signoff", options.NoSignoff).
+		Str("signoff_identity", signoffIdentity).
+		Bool("dco", dco).
+		Str("branch", targetBranch).
+		Str("export_patch", exportPatchDir).
+		Str("save_exchange", saveExchangeDir).
+		Bool("patch_only", patchOnly).
+		Bool("send_email", sendEmail).
+		Str("fixup", fixupRevision).
+		Bool("amend", amend).
+		Bool("force", force).
+		Bool("push", pushAfter).
 		Bool("no_sign", noSign).
+		Bool("no_verify", noVerify).
 		Bool("no_rtk", noRTK).
 		Bool("uses_rtk", gitRepo.UsesRTK()).
+		Bool("session_context", sessionContext).
+		Bool("summary_first", summaryFirst).
 		Str("ai_provider", options.AIProvider).
 		Bool("skip_ai", options.SkipAI).
+		Bool("dry_run", options.DryRun).
+		Bool("copy", options.Copy).
+		Bool("new_branch", options.NewBranch).
+		Bool("message_supplied", message != "").
+		Strs("pathspecs", pathspecs).
 		Msg("CLI options")
 
 	// Channel to signal restoration completion
@@ -117,27 +289,34 @@ func runCommand(cmd *cobra.Command, args []string) {
 
 	// Set restoration completion channel
 	commitService.SetRestoreDoneChannel(restoreDone)
+	if history := openHistory(ctx, cfg); history != nil {
+		defer history.Close()
+		commitService.SetStore(history)
+	}
 
 	// Handle signals in a goroutine
-	signalReceived := false
 	go func() {
 		sig := <-sigChan
-		if signalReceived {
-			// Ignore subsequent signals (multiple Ctrl+C handling)
-			utils.Logger.Debug().Msg("Ignoring subsequent interrupt signal")
-			return
-		}
-		signalReceived = true
 		utils.Logger.Debug().Str("signal", sig.String()).Msg("Received interrupt signal")
-		cancel() // Cancel context to stop ongoing operations
-		os.Exit(0)
+		// Cancel context to stop ongoing operations: running git commands are
+		// interrupted and the workflow returns, restoring the staging state
+		cancel()
+
+		// A second signal forces exit if the workflow does not stop
+		<-sigChan
+		utils.Logger.Debug().Msg("Received second interrupt signal - exiting immediately")
+		os.Exit(130)
 	}()
 
 	// Execute commit workflow
+	flushTraces := startTracing(ctx, cfg)
+	workflowCtx, span := telemetry.Start(ctx, "gitcomm commit")
 	var commitErr error
-	if err := commitService.CreateCommit(ctx); err != nil {
+	if err := commitService.CreateCommit(workflowCtx); err != nil {
 		commitErr = err
 	}
+	telemetry.End(span, commitErr)
+	flushTraces()
 
 	// Check if error is due to context cancellation (Ctrl+C)
 	if ctx.Err() == context.Canceled {
@@ -153,21 +332,209 @@ func runCommand(cmd *cobra.Command, args []string) {
 		case <-time.After(5 * time.Second):
 			// Overall timeout exceeded
 			utils.Logger.Debug().Msg("Overall timeout exceeded - exiting")
-			fmt.Printf("Warning: Restoration did not complete in time.\n")
+			fmt.Fprintln(ui.Out(), "Warning: Restoration did not complete in time.")
 		}
 
-		close(restoreDone)
+		// restoreDone is closed by the commit service once restoration ran
 		os.Exit(130) // Exit code for SIGINT
 	}
 
+	printUpdateNotice(updateNotice)
+
 	if commitErr != nil {
 		if commitErr == utils.ErrNoChanges {
-			fmt.Println("No changes to commit.")
+			fmt.Fprintln(ui.Out(), "No changes to commit.")
 			return
 		}
-		fmt.Fprintf(os.Stderr, "Error: commit failed: %s\n", repository.FormatErrorForDisplay(commitErr))
+		ui.PrintError("commit failed", commitErr)
 		os.Exit(1)
 	}
+	printResults(commitService.Results()...)
+}
+
+// resolveSignoffIdentity returns the sign-off identity from the flag or the config file.
+// A nil identity means Signed-off-by uses the git user identity.
+func resolveSignoffIdentity(flagValue string, cfg *config.Config) (*model.Identity, error) {
+	value := flagValue
+	if value == "" {
+		value = cfg.Commit.SignoffIdentity
+	}
+	if value == "" {
+		return nil, nil
+	}
+
+	identity, err := model.ParseIdentity(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sign-off identity: %w", err)
+	}
+	return identity, nil
+}
+
+// resolveSignoffOptions returns the sign-off identity (the flag takes precedence over the
+// config file) and whether DCO mode is on (flag or commit.dco), rejecting the
+// combinations that would produce a commit failing the DCO check
+func resolveSignoffOptions(flagIdentity string, dcoFlag, noSignoff bool, cfg *config.Config) (*model.Identity, bool, error) {
+	identity, err := resolveSignoffIdentity(flagIdentity, cfg)
+	if err != nil {
+		return nil, false, err
+	}
+	// DCO mode requires a sign-off that matches the author
+	dco := dcoFlag || cfg.Commit.DCO
+	if err := validateDCOOptions(dco, noSignoff, identity); err != nil {
+		return nil, false, err
+	}
+	return identity, dco, nil
+}
+
+// validateDCOOptions rejects options that would produce a commit failing the DCO check
+func validateDCOOptions(dco, noSignoff bool, identity *model.Identity) error {
+	if !dco {
+		return nil
+	}
+	if noSignoff {
+		return fmt.Errorf("--no-signoff cannot be used in DCO mode")
+	}
+	if identity != nil {
+		return fmt.Errorf("sign-off identity override cannot be used in DCO mode (Signed-off-by must match the author)")
+	}
+	return nil
+}
+
+// validatePatchOptions checks that patch export flags are consistent
+func validatePatchOptions(dir, branch string, patchOnly, sendEmail, push bool) error {
+	if patchOnly && dir == "" && !sendEmail {
+		return fmt.Errorf("--patch-only requires --export-patch <dir> or --send-email")
+	}
+	if patchOnly && branch != "" {
+		return fmt.Errorf("--patch-only cannot be combined with --branch")
+	}
+	if patchOnly && push {
+		return fmt.Errorf("--patch-only cannot be combined with --push")
+	}
+	return nil
+}
+
+// suppliedMessage returns the commit message given with --message, whose values are
+// joined as paragraphs like git does, or read from --file ("-" for stdin). Returns ""
+// when no message is supplied.
+func suppliedMessage(messages []string, file string, stdin io.Reader) (string, error) {
+	switch {
+	case len(messages) > 0 && file != "":
+		return "", fmt.Errorf("--message cannot be combined with --file")
+	case len(messages) > 0:
+		message := strings.TrimSpace(strings.Join(messages, "\n\n"))
+		if message == "" {
+			return "", fmt.Errorf("--message is empty")
+		}
+		return message, nil
+	case file == "":
+		return "", nil
+	}
+
+	var content []byte
+	var err error
+	if file == "-" {
+		content, err = io.ReadAll(stdin)
+	} else {
+		content, err = os.ReadFile(file)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read commit message: %w", err)
+	}
+	message := strings.TrimSpace(strings.ReplaceAll(string(content), "\r\n", "\n"))
+	if message == "" {
+		return "", fmt.Errorf("commit message from %s is empty", file)
+	}
+	return message, nil
+}
+
+// validateMessageOptions checks that a supplied message is consistent with the other flags
+func validateMessageOptions(message, fixup string, pick bool) error {
+	if message == "" {
+		return nil
+	}
+	if fixup != "" {
+		return fmt.Errorf("--message and --file cannot be combined with --fixup")
+	}
+	if pick {
+		return fmt.Errorf("--message and --file cannot be combined with --interactive")
+	}
+	return nil
+}
+
+// validateStagingOptions checks that interactive staging can run
+func validateStagingOptions(pick, addAll, nonInteractive bool) error {
+	if !pick {
+		return nil
+	}
+	if addAll {
+		return fmt.Errorf("--interactive cannot be combined with --add-all")
+	}
+	if nonInteractive {
+		return fmt.Errorf("--interactive cannot be combined with --yes: %w", utils.ErrNonInteractive)
+	}
+	return nil
+}
+
+// validateAmendOptions checks that --amend and --force are consistent with the other flags
+func validateAmendOptions(amend, force bool, fixup, branch string, patchOnly bool) error {
+	if !amend {
+		if force {
+			return fmt.Errorf("--force requires --amend")
+		}
+		return nil
+	}
+	switch {
+	case fixup != "":
+		return fmt.Errorf("--amend cannot be combined with --fixup")
+	case branch != "":
+		return fmt.Errorf("--amend cannot be combined with --branch")
+	case patchOnly:
+		return fmt.Errorf("--amend cannot be combined with --patch-only")
+	}
+	return nil
+}
+
+// validateCopyOptions checks that --copy, which creates no commit, is not combined with
+// flags acting on the commit
+func validateCopyOptions(copyOnly, dryRun, amend, push, exportPatch bool, branch string) error {
+	if !copyOnly {
+		return nil
+	}
+	switch {
+	case dryRun:
+		return fmt.Errorf("--copy cannot be combined with --dry-run")
+	case amend:
+		return fmt.Errorf("--copy cannot be combined with --amend")
+	case branch != "":
+		return fmt.Errorf("--copy cannot be combined with --branch")
+	case exportPatch:
+		return fmt.Errorf("--copy cannot be combined with --export-patch or --send-email")
+	case push:
+		return fmt.Errorf("--copy cannot be combined with --push")
+	}
+	return nil
+}
+
+// validateNewBranchOptions checks that --new-branch, which commits on the current
+// branch after switching to a new one, is not combined with flags choosing another commit
+func validateNewBranchOptions(newBranch, amend, patchOnly, copyOnly bool, branch, fixup string) error {
+	if !newBranch {
+		return nil
+	}
+	switch {
+	case branch != "":
+		return fmt.Errorf("--new-branch cannot be combined with --branch")
+	case amend:
+		return fmt.Errorf("--new-branch cannot be combined with --amend")
+	case fixup != "":
+		return fmt.Errorf("--new-branch cannot be combined with --fixup")
+	case patchOnly:
+		return fmt.Errorf("--new-branch cannot be combined with --patch-only")
+	case copyOnly:
+		return fmt.Errorf("--new-branch cannot be combined with --copy")
+	}
+	return nil
 }
 
 func Execute() {
@@ -179,11 +546,41 @@ func Execute() {
 
 func init() {
 	rootCmd.PersistentFlags().BoolVarP(&debug, "debug", "d", false, "Enable debug logging (raw text format, no timestamps)")
-	rootCmd.Flags().BoolVarP(&addAll, "add-all", "a", false, "Automatically stage all unstaged files")
-	rootCmd.Flags().BoolVarP(&noSignoff, "no-signoff", "s", false, "Disable commit signoff")
-	rootCmd.Flags().BoolVar(&noSign, "no-sign", false, "Disable commit signing")
-	rootCmd.Flags().BoolVar(&noRTK, "no-rtk", false, "Disable rtk proxy and use git directly")
-	rootCmd.Flags().StringVar(&provider, "provider", "", "Override default AI provider")
-	rootCmd.Flags().BoolVar(&skipAI, "skip-ai", false, "Skip AI generation and proceed directly to manual input")
-	rootCmd.Flags().StringVar(&configPath, "config", "", "Path to configuration file (default: ~/.gitcomm/config.yaml)")
+	rootCmd.PersistentFlags().BoolVar(&debug, "verbose", false, "Same as --debug")
+	rootCmd.PersistentFlags().StringVar(&configPath, "config", "", "Path to configuration file (default: ~/.gitcomm/config.yaml)")
+	rootCmd.PersistentFlags().StringVar(&provider, "provider", "", "Override default AI provider")
+	rootCmd.PersistentFlags().StringVar(&account, "account", "", "Use this account of the AI provider (ai.providers.<name>.accounts)")
+	rootCmd.PersistentFlags().BoolVar(&plain, "plain", false, "Ask plain line-based questions instead of full-screen prompts")
+	rootCmd.PersistentFlags().BoolVarP(&assumeYes, "yes", "y", false, "Never prompt: accept the AI message and default answers (for scripts and CI)")
+	rootCmd.PersistentFlags().BoolVar(&assumeYes, "non-interactive", false, "Same as --yes")
+	addCommitFlags(rootCmd.Flags())
+}
+
+// addCommitFlags registers the flags of the commit workflow, shared by gitcomm and gitcomm commit
+func addCommitFlags(flags *pflag.FlagSet) {
+	flags.BoolVarP(&addAll, "add-all", "a", false, "Automatically stage all unstaged files")
+	flags.BoolVarP(&interactive, "interactive", "i", false, "Choose the files (and hunks) to stage instead of auto-staging")
+	flags.BoolVarP(&noSignoff, "no-signoff", "s", false, "Disable commit signoff")
+	flags.StringVar(&signoffIdentity, "signoff-identity", "", "Identity used for Signed-off-by instead of the author (\"Name <email>\")")
+	flags.BoolVar(&dcoMode, "dco", false, "Enforce a Signed-off-by line matching the author (Developer Certificate of Origin)")
+	flags.StringVar(&targetBranch, "branch", "", "Create the commit on this branch (new or existing) without switching the worktree")
+	flags.StringVar(&exportPatchDir, "export-patch", "", "Also write a format-patch style file of the commit into this directory")
+	flags.StringVar(&saveExchangeDir, "save-exchange", "", "Write the AI prompt (redacted) and raw answer of the commit into this directory, named after the commit hash")
+	flags.BoolVar(&patchOnly, "patch-only", false, "Only export the patch (with --export-patch), do not commit")
+	flags.BoolVar(&sendEmail, "send-email", false, "Send the commit as a patch over SMTP (see email section of the config file)")
+	flags.BoolVar(&amend, "amend", false, "Replace the last commit, starting from its message and changes")
+	flags.BoolVar(&force, "force", false, "Allow --amend on a commit that was already pushed")
+	flags.StringVar(&fixupRevision, "fixup", "", "Create a \"fixup!\" commit for this revision (for git rebase --autosquash)")
+	flags.BoolVar(&pushAfter, "push", false, "Push the branch after committing (to the push remote of fork workflows)")
+	flags.BoolVar(&noSign, "no-sign", false, "Disable commit signing")
+	flags.BoolVarP(&noVerify, "no-verify", "n", false, "Skip the pre-commit and commit-msg hooks")
+	flags.BoolVar(&noRTK, "no-rtk", false, "Disable rtk proxy and use git directly")
+	flags.BoolVar(&sessionContext, "session-context", false, "Include your previous commit on the same files in the AI prompt")
+	flags.BoolVar(&summaryFirst, "summary-first", false, "Send the file list first and only the diffs the AI provider asks for (for huge changes)")
+	flags.BoolVar(&skipAI, "skip-ai", false, "Skip AI generation and proceed directly to manual input")
+	flags.BoolVar(&dryRun, "dry-run", false, "Print the message and files of the commit without committing or changing the index")
+	flags.BoolVar(&copyMessage, "copy", false, "Copy the final message to the clipboard instead of committing (changes stay staged)")
+	flags.BoolVar(&newBranch, "new-branch", false, "Create a branch named after the final message and switch to it before committing")
+	flags.StringArrayVarP(&messages, "message", "m", nil, "Commit with this message, without AI or prompts (repeat for more paragraphs)")
+	flags.StringVarP(&messageFile, "file", "F", "", "Commit with the message of this file (\"-\" for stdin), without AI or prompts")
 }
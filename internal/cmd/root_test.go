@@ -0,0 +1,415 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/golgoth31/gitcomm/internal/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func TestGlobalFlags_InheritedBySubcommands(t *testing.T) {
+	globals := []string{"config", "provider", "debug", "verbose", "plain", "yes", "non-interactive"}
+
+	for _, sub := range rootCmd.Commands() {
+		for _, name := range globals {
+			// A local flag with the same name would shadow the global one
+			if sub.LocalNonPersistentFlags().Lookup(name) != nil {
+				t.Errorf("%s redeclares global flag --%s", sub.CommandPath(), name)
+			}
+			if sub.InheritedFlags().Lookup(name) == nil {
+				t.Errorf("%s does not inherit global flag --%s", sub.CommandPath(), name)
+			}
+		}
+	}
+}
+
+func TestCommitCmd_SameFlagsAsRoot(t *testing.T) {
+	rootFlags := map[string]string{}
+	rootCmd.LocalNonPersistentFlags().VisitAll(func(f *pflag.Flag) {
+		rootFlags[f.Name] = f.Shorthand
+	})
+
+	commitFlags := map[string]string{}
+	commitCmd.LocalNonPersistentFlags().VisitAll(func(f *pflag.Flag) {
+		commitFlags[f.Name] = f.Shorthand
+	})
+
+	for name, shorthand := range rootFlags {
+		got, ok := commitFlags[name]
+		if !ok {
+			t.Errorf("gitcomm commit lacks --%s", name)
+			continue
+		}
+		if got != shorthand {
+			t.Errorf("gitcomm commit --%s shorthand = %q, want %q", name, got, shorthand)
+		}
+	}
+	if len(commitFlags) != len(rootFlags) {
+		t.Errorf("gitcomm commit has %d flags, gitcomm has %d", len(commitFlags), len(rootFlags))
+	}
+}
+
+func TestGlobalFlags_Parse(t *testing.T) {
+	t.Cleanup(func() {
+		configPath, provider, debug, plain, assumeYes = "", "", false, false, false
+	})
+
+	cmd, _, err := rootCmd.Find([]string{"commit"})
+	if err != nil {
+		t.Fatalf("Find(commit) error = %v", err)
+	}
+	if err := cmd.ParseFlags([]string{"--config", "/tmp/gitcomm.yaml", "--provider", "anthropic", "--verbose", "--plain", "-y", "-a"}); err != nil {
+		t.Fatalf("ParseFlags() error = %v", err)
+	}
+	t.Cleanup(func() { addAll = false })
+
+	if configPath != "/tmp/gitcomm.yaml" || provider != "anthropic" || !debug || !plain || !assumeYes || !addAll {
+		t.Errorf("parsed config=%q provider=%q debug=%v plain=%v yes=%v add-all=%v",
+			configPath, provider, debug, plain, assumeYes, addAll)
+	}
+}
+
+func TestValidateStagingOptions(t *testing.T) {
+	tests := []struct {
+		name           string
+		interactive    bool
+		addAll         bool
+		nonInteractive bool
+		wantErr        bool
+	}{
+		{"auto-staging", false, true, true, false},
+		{"interactive", true, false, false, false},
+		{"interactive with add-all", true, true, false, true},
+		{"interactive with yes", true, false, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateStagingOptions(tt.interactive, tt.addAll, tt.nonInteractive)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateStagingOptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestResolveSignoffOptions(t *testing.T) {
+	tests := []struct {
+		name         string
+		flagIdentity string
+		dcoFlag      bool
+		noSignoff    bool
+		configDCO    bool
+		configIdent  string
+		wantIdentity string
+		wantDCO      bool
+		wantErr      bool
+	}{
+		{name: "defaults"},
+		{name: "identity from config", configIdent: "Jane <jane@corp.example>", wantIdentity: "Jane <jane@corp.example>"},
+		{name: "flag over config", flagIdentity: "Bot <bot@example.com>", configIdent: "Jane <jane@corp.example>", wantIdentity: "Bot <bot@example.com>"},
+		{name: "DCO from config", configDCO: true, wantDCO: true},
+		{name: "DCO from flag", dcoFlag: true, wantDCO: true},
+		{name: "DCO without sign-off", configDCO: true, noSignoff: true, wantErr: true},
+		{name: "DCO with identity override", configDCO: true, configIdent: "Jane <jane@corp.example>", wantErr: true},
+		{name: "invalid identity", flagIdentity: "jane", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{}
+			cfg.Commit.DCO = tt.configDCO
+			cfg.Commit.SignoffIdentity = tt.configIdent
+
+			identity, dco, err := resolveSignoffOptions(tt.flagIdentity, tt.dcoFlag, tt.noSignoff, cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveSignoffOptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			got := ""
+			if identity != nil {
+				got = identity.String()
+			}
+			if got != tt.wantIdentity {
+				t.Errorf("identity = %q, want %q", got, tt.wantIdentity)
+			}
+			if dco != tt.wantDCO {
+				t.Errorf("dco = %v, want %v", dco, tt.wantDCO)
+			}
+		})
+	}
+}
+
+func TestValidateAmendOptions(t *testing.T) {
+	tests := []struct {
+		name      string
+		amend     bool
+		force     bool
+		fixup     string
+		branch    string
+		patchOnly bool
+		wantErr   bool
+	}{
+		{"no amend", false, false, "HEAD~1", "wip", true, false},
+		{"amend", true, false, "", "", false, false},
+		{"amend published commit", true, true, "", "", false, false},
+		{"force without amend", false, true, "", "", false, true},
+		{"amend with fixup", true, false, "HEAD~1", "", false, true},
+		{"amend on another branch", true, false, "", "wip", false, true},
+		{"amend with patch-only", true, false, "", "", true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateAmendOptions(tt.amend, tt.force, tt.fixup, tt.branch, tt.patchOnly)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateAmendOptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidatePatchOptions(t *testing.T) {
+	tests := []struct {
+		name      string
+		dir       string
+		branch    string
+		patchOnly bool
+		sendEmail bool
+		push      bool
+		wantErr   bool
+	}{
+		{"export with commit", "patches", "", false, false, true, false},
+		{"patch only to a directory", "patches", "", true, false, false, false},
+		{"patch only by email", "", "", true, true, false, false},
+		{"patch only without output", "", "", true, false, false, true},
+		{"patch only on another branch", "patches", "wip", true, false, false, true},
+		{"patch only with push", "patches", "", true, false, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validatePatchOptions(tt.dir, tt.branch, tt.patchOnly, tt.sendEmail, tt.push)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validatePatchOptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSuppliedMessage(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "message.txt")
+	if err := os.WriteFile(file, []byte("fix(api): handle empty responses\r\n\r\nBody\r\n"), 0600); err != nil {
+		t.Fatalf("Failed to write message: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		messages []string
+		file     string
+		stdin    string
+		want     string
+		wantErr  bool
+	}{
+		{name: "none"},
+		{name: "message", messages: []string{"fix(api): handle empty responses"}, want: "fix(api): handle empty responses"},
+		{name: "paragraphs", messages: []string{"fix(api): handle empty responses", "Body"}, want: "fix(api): handle empty responses\n\nBody"},
+		{name: "file", file: file, want: "fix(api): handle empty responses\n\nBody"},
+		{name: "stdin", file: "-", stdin: "feat: add api\n", want: "feat: add api"},
+		{name: "empty message", messages: []string{" "}, wantErr: true},
+		{name: "empty stdin", file: "-", wantErr: true},
+		{name: "missing file", file: filepath.Join(t.TempDir(), "missing.txt"), wantErr: true},
+		{name: "message and file", messages: []string{"feat: add api"}, file: file, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := suppliedMessage(tt.messages, tt.file, strings.NewReader(tt.stdin))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("suppliedMessage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("suppliedMessage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateMessageOptions(t *testing.T) {
+	tests := []struct {
+		name        string
+		message     string
+		fixup       string
+		interactive bool
+		wantErr     bool
+	}{
+		{"no message", "", "HEAD~1", true, false},
+		{"message", "feat: add api", "", false, false},
+		{"message with fixup", "feat: add api", "HEAD~1", false, true},
+		{"message with interactive staging", "feat: add api", "", true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateMessageOptions(tt.message, tt.fixup, tt.interactive)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateMessageOptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateCopyOptions(t *testing.T) {
+	tests := []struct {
+		name        string
+		copyOnly    bool
+		dryRun      bool
+		amend       bool
+		push        bool
+		exportPatch bool
+		branch      string
+		wantErr     bool
+	}{
+		{name: "no copy", dryRun: true, amend: true, branch: "topic"},
+		{name: "copy", copyOnly: true},
+		{name: "copy with dry run", copyOnly: true, dryRun: true, wantErr: true},
+		{name: "copy with amend", copyOnly: true, amend: true, wantErr: true},
+		{name: "copy with push", copyOnly: true, push: true, wantErr: true},
+		{name: "copy with patch export", copyOnly: true, exportPatch: true, wantErr: true},
+		{name: "copy with branch", copyOnly: true, branch: "topic", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateCopyOptions(tt.copyOnly, tt.dryRun, tt.amend, tt.push, tt.exportPatch, tt.branch)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateCopyOptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateNewBranchOptions(t *testing.T) {
+	tests := []struct {
+		name      string
+		newBranch bool
+		amend     bool
+		patchOnly bool
+		copyOnly  bool
+		branch    string
+		fixup     string
+		wantErr   bool
+	}{
+		{name: "no new branch", amend: true, branch: "topic"},
+		{name: "new branch", newBranch: true},
+		{name: "new branch with target branch", newBranch: true, branch: "topic", wantErr: true},
+		{name: "new branch with amend", newBranch: true, amend: true, wantErr: true},
+		{name: "new branch with fixup", newBranch: true, fixup: "HEAD~1", wantErr: true},
+		{name: "new branch with patch only", newBranch: true, patchOnly: true, wantErr: true},
+		{name: "new branch with copy", newBranch: true, copyOnly: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateNewBranchOptions(tt.newBranch, tt.amend, tt.patchOnly, tt.copyOnly, tt.branch, tt.fixup)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateNewBranchOptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPathspecArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		command string
+		args    []string
+		wantErr string
+	}{
+		{name: "no args", command: "gitcomm"},
+		{name: "pathspecs after dash", command: "gitcomm", args: []string{"--", "src/api/", "docs/"}},
+		{name: "pathspecs after dash on commit", command: "commit", args: []string{"--", "src/"}},
+		{name: "mistyped subcommand", command: "gitcomm", args: []string{"reprot"}, wantErr: `unknown command "reprot"`},
+		{name: "pathspecs without dash on commit", command: "commit", args: []string{"src/"}, wantErr: `must follow "--"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := &cobra.Command{Use: "gitcomm"}
+			root.AddCommand(&cobra.Command{Use: "report", Run: func(*cobra.Command, []string) {}})
+			command := root
+			if tt.command == "commit" {
+				command = &cobra.Command{Use: "commit"}
+				root.AddCommand(command)
+			}
+			if err := command.ParseFlags(tt.args); err != nil {
+				t.Fatalf("ParseFlags() error = %v", err)
+			}
+
+			err := pathspecArgs(command, command.Flags().Args())
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("pathspecArgs() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("pathspecArgs() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidatePathspecOptions(t *testing.T) {
+	tests := []struct {
+		name      string
+		pathspecs []string
+		pick      bool
+		patchOnly bool
+		branch    string
+		wantErr   bool
+	}{
+		{name: "no pathspecs", pick: true, patchOnly: true, branch: "wip"},
+		{name: "pathspecs", pathspecs: []string{"src/"}},
+		{name: "pathspecs with interactive", pathspecs: []string{"src/"}, pick: true, wantErr: true},
+		{name: "pathspecs with patch only", pathspecs: []string{"src/"}, patchOnly: true, wantErr: true},
+		{name: "pathspecs on another branch", pathspecs: []string{"src/"}, branch: "wip", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validatePathspecOptions(tt.pathspecs, tt.pick, tt.patchOnly, tt.branch)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validatePathspecOptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMaskSecret(t *testing.T) {
+	tests := []struct {
+		name    string
+		setting config.Setting
+		want    string
+	}{
+		{name: "not a secret", setting: config.Setting{Key: "ai.providers.openai.model", Value: "gpt-4o"}, want: "gpt-4o"},
+		{name: "secret", setting: config.Setting{Key: "ai.providers.openai.api_key", Value: "sk-secret", Secret: true}, want: "********"},
+		{name: "placeholder", setting: config.Setting{Key: "ai.providers.openai.api_key", Value: "${OPENAI_API_KEY}", Secret: true}, want: "${OPENAI_API_KEY}"},
+		{name: "empty secret", setting: config.Setting{Key: "issues.github_token", Secret: true}, want: ""},
+		{name: "keychain reference", setting: config.Setting{Key: "ai.providers.openai.api_key", Value: "keyring:openai", Secret: true}, want: "keyring:openai"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := maskSecret(tt.setting); got != tt.want {
+				t.Errorf("maskSecret() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
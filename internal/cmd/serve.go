@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/golgoth31/gitcomm/internal/config"
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/repository"
+	"github.com/golgoth31/gitcomm/internal/server"
+	"github.com/golgoth31/gitcomm/internal/service"
+	"github.com/golgoth31/gitcomm/internal/ui"
+	"github.com/golgoth31/gitcomm/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+// serveTokenEnv holds the bearer token of `gitcomm serve`, so editors can set it
+// without it showing in the process list
+const serveTokenEnv = "GITCOMM_SERVE_TOKEN"
+
+var (
+	serveAddr  string
+	serveToken string
+)
+
+// serveCmd serves the local HTTP API used by editor integrations
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve a local HTTP API for editor integrations",
+	Long: `Serve a small HTTP/JSON API on the loopback interface, so editor extensions
+(VS Code, JetBrains...) can use gitcomm without shelling out and answering prompts:
+
+  GET  /v1/state     branch and changed files (?diffs=true adds the diffs)
+  POST /v1/message   generate a message for the staged changes: {"provider": "..."}
+  POST /v1/commit    commit the changes: {"message": "...", "all": false}
+                     (without a message, it is generated as with --yes)
+
+Requests must carry "Authorization: Bearer <token>". The token is read from
+--token or $GITCOMM_SERVE_TOKEN; otherwise a random one is printed on startup.
+The API never prompts: anything needing a confirmation fails instead.
+
+Examples:
+  # Serve on the default address with a token chosen by the editor
+  GITCOMM_SERVE_TOKEN=secret gitcomm serve
+
+  # Generate a message from another terminal
+  curl -H "Authorization: Bearer secret" -X POST http://127.0.0.1:7410/v1/message`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		if err := validateServeAddr(serveAddr); err != nil {
+			ui.PrintError("invalid listen address", err)
+			os.Exit(1)
+		}
+
+		cfg, err := config.LoadConfig(configPath)
+		if err != nil {
+			utils.Logger.Debug().Err(err).Msg("Failed to load configuration, continuing with defaults")
+			cfg = &config.Config{}
+		}
+
+		gitRepo, err := repository.NewGitRepository("", noSign, noRTK)
+		if err != nil {
+			ui.PrintError("failed to initialize git repository", err)
+			os.Exit(1)
+		}
+		exitIfDisabled(ctx, gitRepo)
+
+		identity, dco, err := resolveSignoffOptions(signoffIdentity, dcoMode, noSignoff, cfg)
+		if err != nil {
+			ui.PrintError("invalid options", err)
+			os.Exit(1)
+		}
+
+		token := serveToken
+		if token == "" {
+			token = os.Getenv(serveTokenEnv)
+		}
+		if token == "" {
+			if token, err = randomToken(); err != nil {
+				ui.PrintError("failed to generate a token", err)
+				os.Exit(1)
+			}
+			fmt.Fprintf(os.Stderr, "Token: %s\n", token)
+		}
+
+		// Requests never prompt, and the workflow output must not reach the terminal as
+		// if it were a response
+		ui.SetNonInteractive(true)
+		ui.SetSeparateStreams(true)
+		warnConfigExposure(cfg)
+
+		options := model.CommitOptions{
+			AIProvider:      provider,
+			Account:         account,
+			NoSignoff:       noSignoff,
+			SignoffIdentity: identity,
+			DCO:             dco,
+		}
+		api := server.NewServer(gitRepo, cfg, options, token)
+		if history := openHistory(ctx, cfg); history != nil {
+			defer history.Close()
+			api.SetStore(history)
+		}
+		watchConfig(ctx, cfg, api)
+		prefetcher := service.NewStatePrefetcher(gitRepo, 0)
+		api.SetPrefetcher(prefetcher)
+		go func() {
+			if err := prefetcher.Run(ctx); err != nil {
+				utils.Logger.Debug().Err(err).Msg("Failed to prefetch the repository state")
+			}
+		}()
+		httpServer := &http.Server{
+			Addr:              serveAddr,
+			Handler:           api.Handler(),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+
+		go func() {
+			<-ctx.Done()
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			if err := httpServer.Shutdown(shutdownCtx); err != nil {
+				utils.Logger.Debug().Err(err).Msg("Failed to shut down the API server")
+			}
+		}()
+
+		flushTraces := startTracing(ctx, cfg)
+		fmt.Fprintf(os.Stderr, "Serving the gitcomm API on http://%s\n", serveAddr)
+		err = httpServer.ListenAndServe()
+		flushTraces()
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			ui.PrintError("API server failed", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// watchConfig reloads the configuration of api when its file changes, until ctx is
+// cancelled. An invalid change is reported and the previous configuration kept.
+func watchConfig(ctx context.Context, cfg *config.Config, api *server.Server) {
+	watcher, err := config.NewWatcher(configPath, cfg)
+	if err != nil {
+		utils.Logger.Debug().Err(err).Msg("Failed to watch the config file, changes need a restart")
+		return
+	}
+	watcher.OnReload(func(cfg *config.Config) {
+		api.SetConfig(cfg)
+		fmt.Fprintln(os.Stderr, "Configuration reloaded")
+	})
+	watcher.OnError(func(err error) {
+		fmt.Fprintf(os.Stderr, "Warning: configuration change ignored: %v\n", err)
+	})
+	go func() {
+		if err := watcher.Run(ctx); err != nil {
+			utils.Logger.Debug().Err(err).Msg("Failed to watch the config file, changes need a restart")
+		}
+	}()
+}
+
+// validateServeAddr refuses to listen anywhere but on the loopback interface: the API
+// commits on behalf of the user
+func validateServeAddr(addr string) error {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("%q: %w", addr, err)
+	}
+	if host == "localhost" {
+		return nil
+	}
+	if ip := net.ParseIP(host); ip == nil || !ip.IsLoopback() {
+		return fmt.Errorf("%q is not a loopback address", addr)
+	}
+	return nil
+}
+
+// randomToken returns a random bearer token
+func randomToken() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func init() {
+	serveCmd.Flags().StringVar(&serveAddr, "addr", "127.0.0.1:7410", "Loopback address to listen on")
+	serveCmd.Flags().StringVar(&serveToken, "token", "", "Bearer token of the requests (default: $GITCOMM_SERVE_TOKEN, or a random token)")
+	serveCmd.Flags().BoolVarP(&noSignoff, "no-signoff", "s", false, "Disable commit signoff")
+	serveCmd.Flags().StringVar(&signoffIdentity, "signoff-identity", "", "Identity used for Signed-off-by instead of the author (\"Name <email>\")")
+	serveCmd.Flags().BoolVar(&dcoMode, "dco", false, "Enforce a Signed-off-by line matching the author (Developer Certificate of Origin)")
+	serveCmd.Flags().BoolVar(&noSign, "no-sign", false, "Disable commit signing")
+	serveCmd.Flags().BoolVar(&noRTK, "no-rtk", false, "Disable rtk proxy and use git directly")
+	rootCmd.AddCommand(serveCmd)
+}
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/golgoth31/gitcomm/internal/config"
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/repository"
+	"github.com/golgoth31/gitcomm/internal/service"
+	"github.com/golgoth31/gitcomm/internal/telemetry"
+	"github.com/golgoth31/gitcomm/internal/ui"
+	"github.com/golgoth31/gitcomm/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+// aiGroups asks the AI provider to group the files of gitcomm split
+var aiGroups bool
+
+// splitCmd commits the staged changes as several commits
+var splitCmd = &cobra.Command{
+	Use:   "split",
+	Short: "Commit the staged changes as several commits",
+	Long: `Propose a grouping of the staged files into several commits, let you edit it,
+then create the commits one after the other, each with its own message (with AI, or from
+manual input with --skip-ai).
+
+Files are grouped by directory, with build files first and documentation last; with
+--ai-groups the AI provider proposes the groups. In the plan, each "commit <name>" line
+starts a commit followed by its files: reorder, rename or merge the blocks and move files
+between them. Files left out of the plan stay staged, as do the files of the remaining
+commits when one fails.
+
+Examples:
+  # Review the proposed commits, then create them
+  gitcomm split
+
+  # Let the AI provider group the files
+  gitcomm split --ai-groups`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		cfg, err := config.LoadConfig(configPath)
+		if err != nil {
+			utils.Logger.Debug().Err(err).Msg("Failed to load configuration, continuing with defaults")
+			cfg = &config.Config{}
+		}
+		printResults := separateStreams(cfg)
+		warnConfigExposure(cfg)
+
+		identity, dco, err := resolveSignoffOptions(signoffIdentity, dcoMode, noSignoff, cfg)
+		if err != nil {
+			ui.PrintError("invalid options", err)
+			os.Exit(1)
+		}
+
+		gitRepo, err := repository.NewGitRepository("", noSign, noRTK)
+		if err != nil {
+			ui.PrintError("failed to initialize git repository", err)
+			os.Exit(1)
+		}
+		exitIfDisabled(ctx, gitRepo)
+
+		options := &model.CommitOptions{
+			AIProvider:      provider,
+			Account:         account,
+			SkipAI:          skipAI,
+			NoSignoff:       noSignoff,
+			SignoffIdentity: identity,
+			DCO:             dco,
+			NoVerify:        noVerify,
+			SessionContext:  sessionContext,
+		}
+
+		flushTraces := startTracing(ctx, cfg)
+		workflowCtx, span := telemetry.Start(ctx, "gitcomm split")
+		splitService := service.NewSplitService(gitRepo, options, cfg)
+		err = splitService.Split(workflowCtx, aiGroups)
+		telemetry.End(span, err)
+		flushTraces()
+		// The commits created before a failure are results too
+		printResults(splitService.Results()...)
+
+		if err != nil {
+			if errors.Is(err, utils.ErrNoChanges) {
+				fmt.Fprintln(os.Stderr, "No staged changes: stage the changes to split first.")
+				os.Exit(1)
+			}
+			ui.PrintError("split failed", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	splitCmd.Flags().BoolVar(&aiGroups, "ai-groups", false, "Ask the AI provider to group the files into commits")
+	splitCmd.Flags().BoolVar(&skipAI, "skip-ai", false, "Skip AI generation and proceed directly to manual input")
+	splitCmd.Flags().BoolVarP(&noSignoff, "no-signoff", "s", false, "Disable commit signoff")
+	splitCmd.Flags().StringVar(&signoffIdentity, "signoff-identity", "", "Identity used for Signed-off-by instead of the author (\"Name <email>\")")
+	splitCmd.Flags().BoolVar(&dcoMode, "dco", false, "Enforce a Signed-off-by line matching the author (Developer Certificate of Origin)")
+	splitCmd.Flags().BoolVar(&noSign, "no-sign", false, "Disable commit signing")
+	splitCmd.Flags().BoolVarP(&noVerify, "no-verify", "n", false, "Skip the pre-commit and commit-msg hooks")
+	splitCmd.Flags().BoolVar(&noRTK, "no-rtk", false, "Disable rtk proxy and use git directly")
+	splitCmd.Flags().BoolVar(&sessionContext, "session-context", false, "Include your previous commit on the same files in the AI prompt")
+	rootCmd.AddCommand(splitCmd)
+}
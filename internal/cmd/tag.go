@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/golgoth31/gitcomm/internal/config"
+	"github.com/golgoth31/gitcomm/internal/repository"
+	"github.com/golgoth31/gitcomm/internal/service"
+	"github.com/golgoth31/gitcomm/internal/ui"
+	"github.com/golgoth31/gitcomm/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+// tagCmd creates the annotated tag of the next release
+var tagCmd = &cobra.Command{
+	Use:   "tag",
+	Short: "Tag the next semantic version from the commits since the last release",
+	Long: `Analyze the commits since the last release tag like next-version does (feat: minor,
+fix and perf: patch, BREAKING CHANGE footer or "!" after the type: major), then create
+the annotated tag of the next version on HEAD after confirmation.
+
+The tag annotation lists the subjects of the released commits, dated with the dates
+configuration. Like commits, the tag is signed with the configured SSH or OpenPGP key;
+when signing fails, no tag is created unless --no-sign is given. When no commit calls
+for a release, no tag is created. Push the
+tag with git push --follow-tags or git push origin <tag>.
+
+Examples:
+  gitcomm tag
+  gitcomm tag --yes --no-sign`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		cfg, err := config.LoadConfig(configPath)
+		if err != nil {
+			utils.Logger.Debug().Err(err).Msg("Failed to load configuration, continuing with defaults")
+			cfg = &config.Config{Release: config.ReleaseConfig{TagPrefix: config.DefaultTagPrefix}}
+		}
+		printResults := separateStreams(cfg)
+		dates, err := cfg.DateFormatter()
+		if err != nil {
+			ui.PrintError("invalid dates configuration", err)
+			os.Exit(1)
+		}
+
+		gitRepo, err := repository.NewGitRepository("", noSign, noRTK)
+		if err != nil {
+			ui.PrintError("failed to initialize git repository", err)
+			os.Exit(1)
+		}
+		exitIfDisabled(ctx, gitRepo)
+
+		plan, signed, err := service.NewReleaseService(gitRepo, cfg).Tag(ctx, dates.FormatLayout(time.Now(), time.DateOnly))
+		if errors.Is(err, repository.ErrGitSigningFailed) {
+			err = fmt.Errorf("%w (use --no-sign to create an unsigned tag)", err)
+		}
+		if err != nil {
+			ui.PrintError("failed to tag the release", err)
+			os.Exit(1)
+		}
+
+		fmt.Fprintln(os.Stderr, plan)
+		if !plan.HasRelease() {
+			return
+		}
+		if signed {
+			fmt.Fprintf(ui.Out(), "✓ Created signed tag %s\n", plan.NextTag)
+		} else {
+			fmt.Fprintf(ui.Out(), "✓ Created tag %s\n", plan.NextTag)
+		}
+		printResults(plan.NextTag)
+	},
+}
+
+func init() {
+	tagCmd.Flags().BoolVar(&noSign, "no-sign", false, "Disable tag signing")
+	tagCmd.Flags().BoolVar(&noRTK, "no-rtk", false, "Disable rtk proxy and use git directly")
+	rootCmd.AddCommand(tagCmd)
+}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/golgoth31/gitcomm/internal/ui"
+	"github.com/golgoth31/gitcomm/pkg/tokenization"
+	"github.com/spf13/cobra"
+)
+
+// vocabularyTimeout bounds the download of each tiktoken vocabulary
+const vocabularyTimeout = 2 * time.Minute
+
+var tokensDownload bool
+
+// tokensCmd estimates the token cost of files or stdin
+var tokensCmd = &cobra.Command{
+	Use:   "tokens <path|->...",
+	Short: "Estimate how many AI tokens a file or diff costs",
+	Long: `Estimate the number of AI tokens of files, or of stdin when the path is "-",
+so you can check how expensive a change is before staging it. The tokenizer
+follows --provider (openai, anthropic, mistral, or a provider and model such as
+openai/gpt-4; default: openai). OpenAI counts are exact once the tiktoken
+vocabularies are downloaded with --download into the tokenizer directory
+($GITCOMM_TOKENIZER_DIR, default: the user cache directory). Anthropic and
+Mistral counts are always estimates.
+
+Examples:
+  # Download the OpenAI vocabularies for exact counts
+  gitcomm tokens --download
+
+  # Estimate a single file
+  gitcomm tokens internal/cmd/root.go
+
+  # Estimate the current unstaged diff with the Anthropic tokenizer
+  git diff | gitcomm tokens - --provider anthropic`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if tokensDownload {
+			return nil
+		}
+		return cobra.MinimumNArgs(1)(cmd, args)
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		if tokensDownload {
+			if err := downloadVocabularies(); err != nil {
+				ui.PrintError("vocabulary download failed", err)
+				os.Exit(1)
+			}
+			if len(args) == 0 {
+				return
+			}
+		}
+
+		calc := tokenization.ForTokenizer(cmp.Or(provider, tokenization.DefaultProvider))
+		if !tokenization.IsExact(calc) {
+			fmt.Fprintln(os.Stderr, "Token counts are estimated")
+		}
+		pathWidth := ui.TerminalWidth() - 10
+		total := 0
+		for _, path := range args {
+			var count int
+			var err error
+			if path == "-" {
+				count, err = tokenization.CountReaderWith(calc, os.Stdin)
+			} else {
+				count, err = tokenization.CountFileWith(calc, path)
+			}
+			if err != nil {
+				ui.PrintError("token estimation failed", err)
+				os.Exit(1)
+			}
+
+			total += count
+			fmt.Printf("%8d  %s\n", count, ui.TruncatePath(path, pathWidth))
+		}
+
+		if len(args) > 1 {
+			fmt.Printf("%8d  total\n", total)
+		}
+	},
+}
+
+// downloadVocabularies downloads the tiktoken vocabularies of the OpenAI models into the
+// tokenizer directory
+func downloadVocabularies() error {
+	client := &http.Client{Timeout: vocabularyTimeout}
+	var errs []error
+	for _, encoding := range tokenization.Encodings() {
+		path, err := tokenization.DownloadVocabulary(context.Background(), client, tokenization.VocabularyURL, encoding)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		fmt.Printf("Downloaded %s to %s\n", encoding, path)
+	}
+	return errors.Join(errs...)
+}
+
+func init() {
+	tokensCmd.Flags().BoolVar(&tokensDownload, "download", false, "Download the OpenAI tiktoken vocabularies (checksum verified) for exact counts")
+	rootCmd.AddCommand(tokensCmd)
+}
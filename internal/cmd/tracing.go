@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/golgoth31/gitcomm/internal/config"
+	"github.com/golgoth31/gitcomm/internal/telemetry"
+	"github.com/golgoth31/gitcomm/internal/utils"
+	"github.com/golgoth31/gitcomm/internal/version"
+)
+
+// startTracing exports the workflow spans to the configured OTLP endpoint, if any. The
+// returned function flushes the spans; tracing failures never fail the command.
+func startTracing(ctx context.Context, cfg *config.Config) func() {
+	shutdown, err := telemetry.Setup(ctx, telemetry.Options{
+		Endpoint:       cfg.Tracing.Endpoint,
+		Headers:        cfg.Tracing.Headers,
+		ServiceVersion: version.Version(),
+	})
+	if err != nil {
+		utils.Logger.Debug().Err(err).Msg("Failed to set up tracing, continuing without")
+		return func() {}
+	}
+
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), telemetry.ShutdownTimeout)
+		defer cancel()
+		if err := shutdown(ctx); err != nil {
+			utils.Logger.Debug().Err(err).Msg("Failed to export traces")
+		}
+	}
+}
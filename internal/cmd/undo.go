@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/golgoth31/gitcomm/internal/repository"
+	"github.com/golgoth31/gitcomm/internal/service"
+	"github.com/golgoth31/gitcomm/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	undoSoft bool
+	undoHard bool
+
+	// confirmRewrite confirms a history rewrite with --yes: the branch name or the token
+	// printed when it is missing
+	confirmRewrite string
+)
+
+// undoCmd removes the last commit of the current branch
+var undoCmd = &cobra.Command{
+	Use:   "undo",
+	Short: "Undo the last commit",
+	Long: `Remove the last commit of the current branch after confirmation.
+
+By default (--soft) its changes stay staged, ready to be committed again. With --hard
+they are discarded, together with every uncommitted change in the worktree.
+
+Commits already on a remote-tracking branch are refused unless --force is given, as
+undoing them rewrites published history. Merge commits are refused.
+
+The reflog entry restoring the commit is printed before anything changes. With
+--yes, --hard also requires --confirm with the branch name or the confirmation
+token given in the error, as the uncommitted changes cannot be recovered.
+
+Examples:
+  # Undo the last commit, keeping its changes staged
+  gitcomm undo
+
+  # Drop the last commit and all uncommitted changes
+  gitcomm undo --hard
+
+  # Same, from a script
+  gitcomm undo --hard --yes --confirm main`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		gitRepo, err := repository.NewGitRepository("", true, noRTK)
+		if err != nil {
+			ui.PrintError("failed to initialize git repository", err)
+			os.Exit(1)
+		}
+		exitIfDisabled(ctx, gitRepo)
+
+		undone, err := service.NewUndoService(gitRepo).Undo(ctx, undoHard, force, confirmRewrite)
+		if err != nil {
+			ui.PrintError("undo failed", err)
+			os.Exit(1)
+		}
+
+		if undoHard {
+			fmt.Fprintf(ui.Out(), "✓ Commit %s undone, its changes discarded\n", undone.ShortHash())
+			return
+		}
+		fmt.Fprintf(ui.Out(), "✓ Commit %s undone, its changes are staged\n", undone.ShortHash())
+	},
+}
+
+func init() {
+	undoCmd.Flags().BoolVar(&undoSoft, "soft", false, "Keep the changes of the commit staged (default)")
+	undoCmd.Flags().BoolVar(&undoHard, "hard", false, "Discard the changes of the commit and all uncommitted changes")
+	undoCmd.Flags().BoolVar(&force, "force", false, "Undo the commit even if it was pushed")
+	undoCmd.Flags().StringVar(&confirmRewrite, "confirm", "", "Branch name or confirmation token required by --hard with --yes")
+	undoCmd.Flags().BoolVar(&noRTK, "no-rtk", false, "Disable rtk proxy and use git directly")
+	undoCmd.MarkFlagsMutuallyExclusive("soft", "hard")
+	rootCmd.AddCommand(undoCmd)
+}
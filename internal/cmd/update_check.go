@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/golgoth31/gitcomm/internal/config"
+	"github.com/golgoth31/gitcomm/internal/update"
+	"github.com/golgoth31/gitcomm/internal/utils"
+	"github.com/golgoth31/gitcomm/internal/version"
+)
+
+// startUpdateCheck looks for a newer release in the background.
+// The returned channel receives the notice (nil when up to date or disabled) and is then closed.
+func startUpdateCheck(ctx context.Context, cfg *config.Config) <-chan *update.Notice {
+	result := make(chan *update.Notice, 1)
+
+	if reason := update.Disabled(cfg.Update.Check, version.Version()); reason != "" {
+		utils.Logger.Debug().Str("reason", reason).Msg("Skipping update check")
+		close(result)
+		return result
+	}
+
+	go func() {
+		defer close(result)
+		checker, err := update.NewChecker(version.Version(), cfg.Update.Interval)
+		if err != nil {
+			utils.Logger.Debug().Err(err).Msg("Update check unavailable")
+			return
+		}
+		// The last lookup is cached in the gitcomm database, or in its own file without it
+		if st := openStore(ctx); st != nil {
+			defer st.Close()
+			checker.Cache = st
+		}
+		notice, err := checker.Check(ctx)
+		if err != nil {
+			utils.Logger.Debug().Err(err).Msg("Update check failed")
+			return
+		}
+		result <- notice
+	}()
+
+	return result
+}
+
+// printUpdateNotice prints the upgrade hint if the background check already finished.
+// It never waits for the check, so a slow network does not delay the CLI.
+func printUpdateNotice(result <-chan *update.Notice) {
+	select {
+	case notice := <-result:
+		if notice != nil {
+			fmt.Fprintln(os.Stderr, notice.String())
+		}
+	default:
+		utils.Logger.Debug().Msg("Update check still running, skipping notice")
+	}
+}
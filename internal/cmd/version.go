@@ -4,27 +4,170 @@ Copyright © 2025 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
+	"cmp"
+	"context"
+	"encoding/json"
 	"fmt"
+	"os"
+	"slices"
+	"strings"
 
+	"github.com/golgoth31/gitcomm/internal/config"
+	"github.com/golgoth31/gitcomm/internal/ui"
+	"github.com/golgoth31/gitcomm/internal/update"
+	"github.com/golgoth31/gitcomm/internal/utils"
 	"github.com/golgoth31/gitcomm/internal/version"
 	"github.com/spf13/cobra"
 )
 
+var (
+	checkUpdate        bool
+	versionJSON        bool
+	versionDiagnostics bool
+)
+
+// versionReport is the output of gitcomm version --json
+type versionReport struct {
+	Build  version.Info  `json:"build"`
+	Config *configReport `json:"config,omitempty"`
+}
+
+// configReport describes the configuration in use, without secrets
+type configReport struct {
+	Path            string   `json:"path"`
+	Found           bool     `json:"found"`
+	Error           string   `json:"error,omitempty"`
+	DefaultProvider string   `json:"default_provider"`
+	Model           string   `json:"model"`
+	Providers       []string `json:"providers"`
+}
+
 // versionCmd represents the version command
 var versionCmd = &cobra.Command{
 	Use:   "version",
-	Short: "A brief description of your command",
-	Long: `A longer description that spans multiple lines and likely contains examples
-and usage of using your command. For example:
+	Short: "Print version and build information",
+	Long: `Print the gitcomm version and build information (commit, commit time,
+branch, release tool, binary checksum, Go version and platform).
+
+With --diagnostics, the resolved configuration file and the default provider
+and model are included: this is what support needs to reproduce an issue.
+No secret is printed.
 
-Cobra is a CLI library for Go that empowers applications.
-This application is a tool to generate the needed files
-to quickly create a Cobra application.`,
+Examples:
+  gitcomm version
+  gitcomm version --json --diagnostics
+  gitcomm version --check`,
+	Args: cobra.NoArgs,
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println(version.BuildDetails())
+		report := versionReport{Build: version.Details()}
+		if versionDiagnostics {
+			report.Config = inspectConfig(configPath)
+		}
+
+		if versionJSON {
+			out, err := json.MarshalIndent(report, "", "  ")
+			if err != nil {
+				ui.PrintError("failed to encode version", err)
+				os.Exit(1)
+			}
+			fmt.Println(string(out))
+		} else {
+			fmt.Println(version.BuildDetails())
+			if report.Config != nil {
+				printConfigReport(report.Config)
+			}
+		}
+
+		if checkUpdate {
+			if err := runVersionCheck(cmd.Context()); err != nil {
+				ui.PrintError("update check failed", err)
+				os.Exit(1)
+			}
+		}
 	},
 }
 
+// inspectConfig resolves and loads the configuration the main command would use
+func inspectConfig(path string) *configReport {
+	report := &configReport{Providers: []string{}}
+
+	resolved, err := config.ResolvePath(path)
+	if err != nil {
+		report.Error = err.Error()
+		return report
+	}
+	report.Path = resolved
+
+	// A missing file is reported as is, not created like the main command does
+	cfg := &config.Config{}
+	if _, err := os.Stat(resolved); err == nil {
+		report.Found = true
+		if cfg, err = config.LoadConfig(resolved); err != nil {
+			utils.Logger.Debug().Err(err).Msg("Failed to load configuration for version diagnostics")
+			report.Error = err.Error()
+			return report
+		}
+	}
+
+	// Same default as commit generation
+	report.DefaultProvider = cmp.Or(cfg.AI.DefaultProvider, "openai")
+	if providerConfig, ok := cfg.AI.Providers[report.DefaultProvider]; ok {
+		report.Model = providerConfig.Model
+	}
+	for name := range cfg.AI.Providers {
+		report.Providers = append(report.Providers, name)
+	}
+	slices.Sort(report.Providers)
+	return report
+}
+
+// printConfigReport prints the configuration diagnostics after the build details
+func printConfigReport(report *configReport) {
+	found := "not found"
+	if report.Found {
+		found = "found"
+	}
+	fmt.Printf("Config file       : %s (%s)\n", report.Path, found)
+	if report.Error != "" {
+		fmt.Printf("Config error      : %s\n", report.Error)
+		return
+	}
+	fmt.Printf("Default provider  : %s\n", report.DefaultProvider)
+	fmt.Printf("Model             : %s\n", cmp.Or(report.Model, "(provider default)"))
+	fmt.Printf("Providers         : %s\n", cmp.Or(strings.Join(report.Providers, ", "), "(none)"))
+}
+
+// runVersionCheck queries the latest release now, ignoring the rate limit and update.check
+func runVersionCheck(ctx context.Context) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if !update.IsRelease(version.Version()) {
+		return fmt.Errorf("development build %q cannot be compared with releases", version.Version())
+	}
+	checker, err := update.NewChecker(version.Version(), 0)
+	if err != nil {
+		return err
+	}
+	notice, err := checker.Check(ctx)
+	if err != nil {
+		return err
+	}
+	if notice == nil {
+		fmt.Println("gitcomm is up to date.")
+		return nil
+	}
+	fmt.Println(notice.String())
+	return nil
+}
+
 func init() {
+	versionCmd.Flags().BoolVar(&checkUpdate, "check", false, "Check whether a newer release is available")
+	versionCmd.Flags().BoolVar(&versionJSON, "json", false, "Print the information as JSON")
+	versionCmd.Flags().BoolVar(&versionDiagnostics, "diagnostics", false, "Include the resolved configuration file, default provider and model")
 	rootCmd.AddCommand(versionCmd)
+
+	// gitcomm --version prints the short version
+	rootCmd.Version = cmp.Or(version.Version(), "dev")
+	rootCmd.SetVersionTemplate("gitcomm {{.Version}}\n")
 }
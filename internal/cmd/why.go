@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/golgoth31/gitcomm/internal/config"
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/repository"
+	"github.com/golgoth31/gitcomm/internal/service"
+	"github.com/golgoth31/gitcomm/internal/ui"
+	"github.com/golgoth31/gitcomm/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+var (
+	whyFile    string
+	whyCommit  string
+	whyOffline bool
+)
+
+// whyCmd explains why a commit message fails validation
+var whyCmd = &cobra.Command{
+	Use:   "why [message...]",
+	Short: "Explain why a commit message fails validation, with corrected examples",
+	Long: `Check a commit message against the rules gitcomm enforces and explain each
+broken rule: what is wrong in the message, why the rule exists, and the
+message corrected. The corrected message is written by the AI provider, keeping
+your wording; with --offline, or when the provider fails, it is derived locally
+(closest type, shortened subject and body, cleaned scope).
+
+The message is given as arguments (joined as paragraphs, like git commit -m),
+read from --file ("-" for stdin), or taken from an existing commit with
+--commit. The command exits with status 1 when the message breaks a rule.
+
+Examples:
+  gitcomm why "feature(api/v2): Added the new endpoint."
+  gitcomm why --commit HEAD
+  gitcomm why --file .git/COMMIT_EDITMSG --offline`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+
+		cfg, err := config.LoadConfig(configPath)
+		if err != nil {
+			utils.Logger.Debug().Err(err).Msg("Failed to load configuration, continuing with defaults")
+			cfg = &config.Config{}
+		}
+
+		text, err := suppliedMessage(args, whyFile, os.Stdin)
+		if err == nil && whyCommit != "" {
+			if text != "" {
+				err = fmt.Errorf("--commit cannot be combined with a message or --file")
+			} else {
+				text, err = commitMessageText(ctx, whyCommit)
+			}
+		}
+		if err == nil && text == "" {
+			err = fmt.Errorf("give the message as arguments, with --file or with --commit")
+		}
+		if err != nil {
+			ui.PrintError("invalid options", err)
+			os.Exit(1)
+		}
+
+		options := &model.CommitOptions{AIProvider: provider, Account: account}
+		report, err := service.NewCommitService(nil, options, cfg).ExplainMessage(ctx, text, whyOffline)
+		if err != nil {
+			ui.PrintError("failed to explain the message", err)
+			os.Exit(1)
+		}
+		if printWhyReport(report) {
+			os.Exit(1)
+		}
+	},
+}
+
+// commitMessageText returns the message of revision
+func commitMessageText(ctx context.Context, revision string) (string, error) {
+	gitRepo, err := repository.NewGitRepository("", true, noRTK)
+	if err != nil {
+		return "", err
+	}
+	commits, err := gitRepo.ListCommits(ctx, revision, 1)
+	if err != nil {
+		return "", err
+	}
+	if len(commits) == 0 {
+		return "", fmt.Errorf("no commit %s", revision)
+	}
+	return commits[0].Message, nil
+}
+
+// printWhyReport prints the broken rules with their explanation, then the corrected
+// message; it reports whether a rule is broken
+func printWhyReport(report *service.WhyReport) bool {
+	if len(report.Explanations) == 0 {
+		fmt.Println("✓ The message follows every rule")
+		return false
+	}
+
+	for _, explanation := range report.Explanations {
+		fmt.Printf("✗ %s: %s\n", explanation.Field, explanation.Problem)
+		fmt.Printf("  Rule:    %s\n", explanation.Rule)
+		if explanation.Why != "" {
+			fmt.Printf("  Why:     %s\n", explanation.Why)
+		}
+		if explanation.Example != "" {
+			fmt.Printf("  Example: %s\n", explanation.Example)
+		}
+		fmt.Println()
+	}
+
+	source := "corrected offline"
+	if report.FromAI {
+		source = "corrected by the AI provider"
+	}
+	fmt.Printf("Corrected message (%s):\n\n%s\n", source, report.Corrected)
+	return true
+}
+
+func init() {
+	whyCmd.Flags().StringVarP(&whyFile, "file", "F", "", "Read the message from this file (\"-\" for stdin)")
+	whyCmd.Flags().StringVar(&whyCommit, "commit", "", "Explain the message of this commit")
+	whyCmd.Flags().BoolVar(&whyOffline, "offline", false, "Correct the message locally, without the AI provider")
+	whyCmd.Flags().BoolVar(&noRTK, "no-rtk", false, "Disable rtk proxy and use git directly")
+	rootCmd.AddCommand(whyCmd)
+}
@@ -0,0 +1,110 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/golgoth31/gitcomm/internal/utils"
+)
+
+// AccountRule selects a provider account for the repositories under a path
+type AccountRule struct {
+	// Path is a directory, "~/" for the home directory, which may hold glob patterns
+	// (~/clients/*): the rule matches the directories it names and everything below them
+	Path string
+	// Account is the account name, as in ai.providers.<name>.accounts
+	Account string
+}
+
+// SelectAccount returns the account of the first ai.account_rules entry matching dir (the
+// current directory when ""), else ai.account; "" selects the providers' own keys
+func (c *Config) SelectAccount(dir string) string {
+	if dir == "" {
+		if wd, err := os.Getwd(); err == nil {
+			dir = wd
+		}
+	}
+	for _, rule := range c.AI.AccountRules {
+		if matchesPath(rule.Path, dir) {
+			utils.Logger.Debug().Str("path", rule.Path).Str("account", rule.Account).Msg("Account rule matched")
+			return rule.Account
+		}
+	}
+	return c.AI.Account
+}
+
+// ProviderAccount returns the account of provider name used by GetAccountProviderConfig:
+// account when set, else the account selected by SelectAccount for providers with
+// accounts ("": the provider's own key)
+func (c *Config) ProviderAccount(name, account string) string {
+	if account != "" || len(c.AI.Providers[name].Accounts) == 0 {
+		return account
+	}
+	return c.SelectAccount("")
+}
+
+// matchesPath reports whether dir is a directory named by pattern, or below one
+func matchesPath(pattern, dir string) bool {
+	if pattern == "" || dir == "" {
+		return false
+	}
+	pattern, err := expandHome(pattern)
+	if err != nil {
+		return false
+	}
+	pattern = filepath.Clean(pattern)
+
+	for current := filepath.Clean(dir); ; current = filepath.Dir(current) {
+		if matched, err := filepath.Match(pattern, current); err == nil && matched {
+			return true
+		}
+		if parent := filepath.Dir(current); parent == current {
+			return false
+		}
+	}
+}
+
+// expandHome replaces a leading "~" of path with the home directory
+func expandHome(path string) (string, error) {
+	rest, ok := strings.CutPrefix(path, "~")
+	if !ok || (rest != "" && rest[0] != '/' && rest[0] != filepath.Separator) {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return home + rest, nil
+}
+
+// validateAccounts checks the accounts of the providers and the rules selecting them
+func (c *Config) validateAccounts() []error {
+	var errs []error
+	known := map[string]bool{}
+	for name, provider := range c.AI.Providers {
+		for account, credentials := range provider.Accounts {
+			known[account] = true
+			if credentials.APIKey != "" && credentials.APIKeyCommand != "" {
+				errs = append(errs, fmt.Errorf("ai.providers.%s.accounts.%s: set api_key or api_key_command, not both", name, account))
+			}
+		}
+	}
+
+	if c.AI.Account != "" && !known[c.AI.Account] {
+		errs = append(errs, fmt.Errorf("ai.account %q is not an account of any provider (ai.providers.<name>.accounts)", c.AI.Account))
+	}
+	for i, rule := range c.AI.AccountRules {
+		switch {
+		case rule.Path == "" || rule.Account == "":
+			errs = append(errs, fmt.Errorf("ai.account_rules[%d]: path and account are required", i))
+		case !known[rule.Account]:
+			errs = append(errs, fmt.Errorf("ai.account_rules[%d]: %q is not an account of any provider", i, rule.Account))
+		}
+		if _, err := filepath.Match(rule.Path, ""); err != nil {
+			errs = append(errs, fmt.Errorf("ai.account_rules[%d]: invalid path pattern %q: %w", i, rule.Path, err))
+		}
+	}
+	return errs
+}
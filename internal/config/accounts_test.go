@@ -0,0 +1,149 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+)
+
+func TestGetAccountProviderConfig(t *testing.T) {
+	work := t.TempDir()
+	repo := filepath.Join(work, "api")
+	if err := os.Mkdir(repo, 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(repo)
+
+	providers := map[string]model.AIProviderConfig{
+		"openai": {Name: "openai", APIKey: "sk-own", Accounts: map[string]model.ProviderAccount{
+			"work":     {APIKey: "sk-work"},
+			"personal": {APIKeyCommand: "printf sk-personal"},
+		}},
+		"ollama": {Name: "ollama"},
+	}
+
+	tests := []struct {
+		name        string
+		provider    string
+		account     string
+		defaultAcct string
+		rules       []AccountRule
+		wantKey     string
+		wantAccount string
+		wantErr     string
+	}{
+		{name: "own key", provider: "openai", wantKey: "sk-own"},
+		{name: "flag", provider: "openai", account: "personal", wantKey: "sk-personal", wantAccount: "personal"},
+		{name: "default account", provider: "openai", defaultAcct: "personal", wantKey: "sk-personal", wantAccount: "personal"},
+		{
+			name: "rule matching the repository", provider: "openai", defaultAcct: "personal",
+			rules:   []AccountRule{{Path: filepath.Join(work, "other"), Account: "personal"}, {Path: work, Account: "work"}},
+			wantKey: "sk-work", wantAccount: "work",
+		},
+		{
+			name: "flag over rules", provider: "openai", account: "personal",
+			rules:   []AccountRule{{Path: work, Account: "work"}},
+			wantKey: "sk-personal", wantAccount: "personal",
+		},
+		{name: "provider without accounts ignores the rules", provider: "ollama", rules: []AccountRule{{Path: work, Account: "work"}}},
+		{name: "unknown account", provider: "openai", account: "wrok", wantErr: `provider openai has no account "wrok"`},
+		{name: "account of a provider without accounts", provider: "ollama", account: "work", wantErr: `provider ollama has no account "work"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{}
+			cfg.AI.Providers = providers
+			cfg.AI.Account = tt.defaultAcct
+			cfg.AI.AccountRules = tt.rules
+
+			provider, err := cfg.GetAccountProviderConfig(tt.provider, tt.account)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("GetAccountProviderConfig() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetAccountProviderConfig() error = %v", err)
+			}
+			if provider.APIKey != tt.wantKey || provider.Account != tt.wantAccount {
+				t.Errorf("key = %q, account = %q, want %q and %q", provider.APIKey, provider.Account, tt.wantKey, tt.wantAccount)
+			}
+		})
+	}
+}
+
+func TestMatchesPath(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip("no home directory")
+	}
+
+	tests := []struct {
+		pattern string
+		dir     string
+		want    bool
+	}{
+		{"/src/work", "/src/work", true},
+		{"/src/work", "/src/work/api/internal", true},
+		{"/src/work/", "/src/work/api", true},
+		{"/src/work", "/src/work2", false},
+		{"/src/work", "/src", false},
+		{"/src/clients/*", "/src/clients/acme/api", true},
+		{"/src/clients/*", "/src/clients", false},
+		{"~/work", filepath.Join(home, "work", "api"), true},
+		{"~work", filepath.Join(home, "work"), false},
+		{"", "/src/work", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.dir, func(t *testing.T) {
+			if got := matchesPath(tt.pattern, tt.dir); got != tt.want {
+				t.Errorf("matchesPath(%q, %q) = %v, want %v", tt.pattern, tt.dir, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_Accounts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := `ai:
+  account: personal
+  account_rules:
+    - path: ~/work
+      account: work
+  providers:
+    openai:
+      api_key: sk-own
+      accounts:
+        work:
+          api_key: sk-work
+        personal:
+          api_key_command: pass show openai
+`
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if cfg.AI.Account != "personal" {
+		t.Errorf("Account = %q, want personal", cfg.AI.Account)
+	}
+	if len(cfg.AI.AccountRules) != 1 || cfg.AI.AccountRules[0] != (AccountRule{Path: "~/work", Account: "work"}) {
+		t.Errorf("AccountRules = %+v", cfg.AI.AccountRules)
+	}
+	accounts := cfg.AI.Providers["openai"].Accounts
+	if accounts["work"].APIKey != "sk-work" || accounts["personal"].APIKeyCommand != "pass show openai" || len(accounts) != 2 {
+		t.Errorf("Accounts = %+v", accounts)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
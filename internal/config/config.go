@@ -8,23 +8,300 @@ import (
 	"strings"
 	"time"
 
+	"github.com/golgoth31/gitcomm/internal/keyring"
 	"github.com/golgoth31/gitcomm/internal/model"
 	"github.com/golgoth31/gitcomm/internal/utils"
+	"github.com/golgoth31/gitcomm/pkg/datefmt"
 	"github.com/spf13/viper"
 )
 
 // T015: Placeholder regex pattern compiled once for reuse
 var placeholderRegex = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
 
+// DefaultSessionWindow is how far back the author's previous commit is looked up for session context
+const DefaultSessionWindow = 8 * time.Hour
+
+// DefaultCircuitThreshold is the number of consecutive provider failures after which the
+// provider is skipped for DefaultCircuitCooldown
+const DefaultCircuitThreshold = 3
+
+// DefaultCircuitCooldown is how long a provider is skipped after repeated failures
+const DefaultCircuitCooldown = time.Minute
+
+// DefaultRetryAttempts is the number of attempts of a provider request failing with a
+// transient error (rate limit, server error, timeout)
+const DefaultRetryAttempts = 3
+
+// DefaultRetryBackoff is the delay before the first retry of a provider request
+const DefaultRetryBackoff = time.Second
+
+// DefaultRetryMaxBackoff caps the delay between two attempts of a provider request
+const DefaultRetryMaxBackoff = 30 * time.Second
+
+// DefaultRetryJitter randomizes the delays between attempts, so parallel runs spread out
+const DefaultRetryJitter = 0.2
+
+// DefaultContextBudget is the estimated number of tokens the prompt sections are packed in
+const DefaultContextBudget = 16000
+
+// DefaultMaxDiffSize is the character count above which a file diff is truncated
+const DefaultMaxDiffSize = 5000
+
+// DefaultStatsFooterTemplate renders the metrics footers added when commit.stats_footer is enabled
+const DefaultStatsFooterTemplate = `Lines-Added: {{.LinesAdded}}
+Lines-Removed: {{.LinesRemoved}}
+Files-Changed: {{.FilesChanged}}`
+
+// DefaultAITrailerKey is the trailer crediting the AI model, added when commit.ai_trailer is enabled
+const DefaultAITrailerKey = "Assisted-by"
+
+// DefaultBranchTemplate names the branches created by --new-branch, e.g. feat/auth-add-login
+const DefaultBranchTemplate = `{{.Type}}/{{with .Scope}}{{.}}-{{end}}{{.Subject}}`
+
+// DefaultTicketPatterns find Jira style ticket keys (PROJ-123) in branch names
+var DefaultTicketPatterns = []string{`[A-Z][A-Z0-9]+-[0-9]+`}
+
+// DefaultTagPrefix is the prefix of release tags, as in semantic-release's "v${version}"
+const DefaultTagPrefix = "v"
+
+// DefaultUpdateInterval is the minimum time between two update checks
+const DefaultUpdateInterval = 24 * time.Hour
+
+// Modes of commit.upstream_check, run when the current branch is behind its upstream
+const (
+	UpstreamCheckOff    = "off"
+	UpstreamCheckWarn   = "warn"   // print a warning (default)
+	UpstreamCheckPrompt = "prompt" // ask whether to commit anyway, no by default
+)
+
 // Config represents the application configuration
 type Config struct {
-	AI AIConfig
+	AI       AIConfig
+	Commit   CommitConfig
+	Email    EmailConfig
+	Update   UpdateConfig
+	Output   OutputConfig
+	Report   ReportConfig
+	History  HistoryConfig
+	Push     PushConfig
+	Issues   IssuesConfig
+	Dates    DatesConfig
+	Hooks    HooksConfig
+	Timer    TimerConfig
+	Release  ReleaseConfig
+	Security SecurityConfig
+	Tracing  TracingConfig
+
+	// File is the config file the configuration was loaded from
+	File FileSource
+}
+
+// FileSource describes the loaded config file
+type FileSource struct {
+	// Path is the config file path
+	Path string
+	// HasSecrets is true when API keys, tokens or passwords are written in the file itself
+	// (not substituted from ${ENV_VAR} placeholders or environment variables)
+	HasSecrets bool
 }
 
 // AIConfig represents AI provider configuration
 type AIConfig struct {
 	DefaultProvider string
 	Providers       map[string]model.AIProviderConfig
+	// PostProcessors is the ordered list of post-processors applied to raw AI output before parsing
+	PostProcessors []string
+	// SessionContext adds the author's previous commit on the same files to the prompt
+	SessionContext bool
+	// SessionWindow limits how far back the previous commit is looked up (default: 8h)
+	SessionWindow time.Duration
+	// Privacy limits what is sent to providers: full-diff (default), filenames-only or stats-only.
+	// A repository can override it with git config gitcomm.privacy.
+	Privacy string
+	// Context sets the token budget, order and priority of the prompt sections; providers
+	// can override the budget with context_budget
+	Context model.PromptLayout
+	// Account is the provider account used when no account rule matches (see SelectAccount)
+	Account string
+	// AccountRules select the provider account by repository path, first match wins
+	AccountRules []AccountRule
+}
+
+// CommitConfig represents commit creation configuration
+type CommitConfig struct {
+	// SignoffIdentity is the "Name <email>" identity used for Signed-off-by (default: git user)
+	SignoffIdentity string
+
+	// DCO enforces a Signed-off-by line matching the author on every commit
+	DCO bool
+
+	// Scopes are the project scopes offered first in the scope selection
+	Scopes []string
+
+	// StatsFooter appends metrics footers computed from the staged diff
+	StatsFooter bool
+
+	// StatsFooterTemplate is the text/template rendering the metrics footers
+	// (fields: LinesAdded, LinesRemoved, FilesChanged; default: DefaultStatsFooterTemplate)
+	StatsFooterTemplate string
+
+	// AITrailer appends a trailer naming the provider and model to the commits whose message
+	// was generated with AI, e.g. "Assisted-by: gitcomm/openai/gpt-4o"
+	AITrailer bool
+
+	// AITrailerKey is the key of the AI trailer (default: DefaultAITrailerKey)
+	AITrailerKey string
+
+	// BranchTickets extracts a ticket reference from the branch name, shares it with the AI
+	// and adds a "Refs: <ticket>" footer to the commit
+	BranchTickets bool
+
+	// TicketPatterns are the regular expressions finding the ticket in the branch name, the
+	// first match wins; with a capture group, the group is the ticket (default: DefaultTicketPatterns)
+	TicketPatterns []string
+
+	// HeaderFormat is the layout of the commit header, with the {type}, {scope}, {ticket}
+	// and {subject} placeholders, e.g. "[{ticket}] {type}({scope}): {subject}" (default:
+	// model.DefaultHeaderFormat). A {ticket} placeholder is filled with the branch ticket.
+	HeaderFormat string
+
+	// CheckGenerated warns when a file and the file generated from it (e.g. .ts and .js,
+	// .proto and .pb.go) are both staged, and offers to unstage the generated one (default: true)
+	CheckGenerated bool
+
+	// BranchTemplate is the text/template naming the branches created by --new-branch
+	// (fields: Type, Scope, Subject, Ticket, slugified; default: DefaultBranchTemplate)
+	BranchTemplate string
+
+	// WorkspaceSummary prints the repository, branch, file counts and last commit age before
+	// any prompt (default: true)
+	WorkspaceSummary bool
+
+	// UpstreamCheck tells what to do when the current branch is behind its upstream: off,
+	// warn (default) or prompt (see UpstreamCheckOff)
+	UpstreamCheck string
+
+	// UpstreamFetch fetches the upstream before comparing it with the branch
+	UpstreamFetch bool
+}
+
+// PushConfig represents the push-after-commit configuration
+type PushConfig struct {
+	// CompareURL prints the pull/merge request creation URL after pushing (GitHub and GitLab)
+	CompareURL bool
+}
+
+// IssuesConfig represents the verification of issue references in footers
+type IssuesConfig struct {
+	// Verify checks that referenced issues (#123, PROJ-123) exist and are open before committing
+	Verify bool
+	// GitHubToken and GitLabToken authenticate lookups on private repositories
+	GitHubToken string
+	GitLabToken string
+	// JiraURL enables PROJ-123 lookups on this Jira instance
+	JiraURL   string
+	JiraUser  string
+	JiraToken string
+	// JiraProjects are the projects whose keys are Jira references (e.g. PROJ, OPS); no
+	// key is looked up without them
+	JiraProjects []string
+}
+
+// DatesConfig represents how timestamps are displayed, so stamps are consistent across machines
+type DatesConfig struct {
+	// Format is the Go time layout (default: datefmt.DefaultLayout)
+	Format string
+	// Timezone is an IANA time zone such as "UTC" or "Europe/Paris" (default: local time)
+	Timezone string
+	// Locale selects the month and weekday names (default: en)
+	Locale string
+}
+
+// HooksConfig represents the user scripts run at the phases of the commit workflow.
+// Each script is a shell command line receiving a JSON description on stdin.
+type HooksConfig struct {
+	// PreGenerate and PreCommit scripts run before AI generation and before the commit;
+	// a failing script stops the workflow
+	PreGenerate []string
+	PreCommit   []string
+	// PostGenerate and PostCommit scripts run after them; failures are only reported
+	PostGenerate []string
+	PostCommit   []string
+	// Timeout bounds each script (default: hooks.DefaultTimeout)
+	Timeout time.Duration
+}
+
+// TimerConfig represents the time tracker integration adding a Time-spent footer
+type TimerConfig struct {
+	// Tracker is watson, timewarrior or toggl (disabled when empty)
+	Tracker string
+	// AfterCommit is what happens to the running timer once committed: none (default), annotate or stop
+	AfterCommit string
+	// TogglToken is the Toggl Track API token
+	TogglToken string
+}
+
+// ReleaseConfig represents how releases are versioned and tagged
+type ReleaseConfig struct {
+	// TagPrefix is prepended to versions in tag names (default: DefaultTagPrefix, may be empty)
+	TagPrefix string
+}
+
+// TracingConfig represents the OpenTelemetry tracing of the workflow
+type TracingConfig struct {
+	// Endpoint is the OTLP/HTTP collector URL spans are exported to (disabled when empty,
+	// unless the OTEL_EXPORTER_OTLP_ENDPOINT variables are set)
+	Endpoint string
+	// Headers are sent with each export, e.g. an authentication token
+	Headers map[string]string
+}
+
+// DateFormatter returns the formatter for the dates settings
+func (c *Config) DateFormatter() (*datefmt.Formatter, error) {
+	return datefmt.NewFormatter(c.Dates.Format, c.Dates.Timezone, c.Dates.Locale)
+}
+
+// UpdateConfig represents the startup update check configuration
+type UpdateConfig struct {
+	// Check enables the update check (opt-in, skipped on CI)
+	Check bool
+	// Interval is the minimum time between two checks (default: 24h)
+	Interval time.Duration
+}
+
+// OutputConfig represents how the output is split between stdout and stderr
+type OutputConfig struct {
+	// SeparateStreams sends the prompts, progress and decorations to stderr, leaving on
+	// stdout only the results: the hash of the commits created, or the message of a dry run
+	SeparateStreams bool
+}
+
+// ReportConfig represents the settings of gitcomm report
+type ReportConfig struct {
+	// Repositories are the repositories summarized by gitcomm report: paths or glob
+	// patterns, "~" meaning the home directory (default: the current repository)
+	Repositories []string
+}
+
+// HistoryConfig represents the recording of the generated commits and provider usage in
+// ~/.gitcomm/gitcomm.db, listed by gitcomm history
+type HistoryConfig struct {
+	// Enabled records the commits created with an AI generated message and the tokens
+	// of every provider request (opt-in)
+	Enabled bool
+}
+
+// EmailConfig represents SMTP settings for sending exported patches.
+// Recipients can be overridden per repository with git config sendemail.to / sendemail.cc.
+type EmailConfig struct {
+	SMTPServer     string
+	SMTPPort       int
+	SMTPUser       string
+	SMTPPassword   string
+	SMTPEncryption string
+	From           string
+	To             []string
+	Cc             []string
 }
 
 // LoadConfig loads configuration from file or environment variables
@@ -32,12 +309,9 @@ func LoadConfig(configPath string) (*Config, error) {
 	v := viper.New()
 
 	// Set default config path
-	if configPath == "" {
-		homeDir, err := os.UserHomeDir()
-		if err != nil {
-			return nil, fmt.Errorf("failed to get home directory: %w", err)
-		}
-		configPath = filepath.Join(homeDir, ".gitcomm", "config.yaml")
+	configPath, err := ResolvePath(configPath)
+	if err != nil {
+		return nil, err
 	}
 
 	// T013: Validate path is not a directory
@@ -100,18 +374,260 @@ func LoadConfig(configPath string) (*Config, error) {
 		AI: AIConfig{
 			DefaultProvider: v.GetString("ai.default_provider"),
 			Providers:       make(map[string]model.AIProviderConfig),
+			PostProcessors:  v.GetStringSlice("ai.post_processors"),
+			SessionContext:  v.GetBool("ai.session_context"),
+			SessionWindow:   DefaultSessionWindow,
+			Privacy:         v.GetString("ai.privacy"),
+			Context: model.PromptLayout{
+				Budget:       DefaultContextBudget,
+				Order:        v.GetStringSlice("ai.context.order"),
+				Priority:     v.GetStringSlice("ai.context.priority"),
+				SummaryFirst: v.GetString("ai.context.summary_first"),
+				Diff: model.DiffLimits{
+					Context: v.GetInt("ai.context.diff_context"),
+					MaxSize: DefaultMaxDiffSize,
+				},
+			},
+			Account: v.GetString("ai.account"),
+		},
+		Commit: CommitConfig{
+			SignoffIdentity:     v.GetString("commit.signoff_identity"),
+			DCO:                 v.GetBool("commit.dco"),
+			Scopes:              v.GetStringSlice("commit.scopes"),
+			StatsFooter:         v.GetBool("commit.stats_footer"),
+			StatsFooterTemplate: DefaultStatsFooterTemplate,
+			AITrailer:           v.GetBool("commit.ai_trailer"),
+			AITrailerKey:        DefaultAITrailerKey,
+			BranchTickets:       v.GetBool("commit.branch_tickets"),
+			TicketPatterns:      v.GetStringSlice("commit.ticket_patterns"),
+			HeaderFormat:        v.GetString("commit.header_format"),
+			BranchTemplate:      DefaultBranchTemplate,
+			CheckGenerated:      true,
+			WorkspaceSummary:    true,
+			UpstreamCheck:       UpstreamCheckWarn,
+			UpstreamFetch:       v.GetBool("commit.upstream_fetch"),
+		},
+		Email: EmailConfig{
+			SMTPServer:     v.GetString("email.smtp_server"),
+			SMTPPort:       v.GetInt("email.smtp_port"),
+			SMTPUser:       v.GetString("email.smtp_user"),
+			SMTPPassword:   v.GetString("email.smtp_password"),
+			SMTPEncryption: v.GetString("email.smtp_encryption"),
+			From:           v.GetString("email.from"),
+			To:             v.GetStringSlice("email.to"),
+			Cc:             v.GetStringSlice("email.cc"),
+		},
+		Push: PushConfig{
+			CompareURL: v.GetBool("push.compare_url"),
+		},
+		Issues: IssuesConfig{
+			Verify:       v.GetBool("issues.verify"),
+			GitHubToken:  v.GetString("issues.github_token"),
+			GitLabToken:  v.GetString("issues.gitlab_token"),
+			JiraURL:      v.GetString("issues.jira.url"),
+			JiraUser:     v.GetString("issues.jira.user"),
+			JiraToken:    v.GetString("issues.jira.token"),
+			JiraProjects: v.GetStringSlice("issues.jira.projects"),
+		},
+		Dates: DatesConfig{
+			Format:   v.GetString("dates.format"),
+			Timezone: v.GetString("dates.timezone"),
+			Locale:   v.GetString("dates.locale"),
+		},
+		Hooks: HooksConfig{
+			PreGenerate:  v.GetStringSlice("hooks.pre_generate"),
+			PreCommit:    v.GetStringSlice("hooks.pre_commit"),
+			PostGenerate: v.GetStringSlice("hooks.post_generate"),
+			PostCommit:   v.GetStringSlice("hooks.post_commit"),
+		},
+		Timer: TimerConfig{
+			Tracker:     v.GetString("timer.tracker"),
+			AfterCommit: v.GetString("timer.after_commit"),
+			TogglToken:  v.GetString("timer.toggl_token"),
+		},
+		Release: ReleaseConfig{
+			TagPrefix: DefaultTagPrefix,
+		},
+		Update: UpdateConfig{
+			Check:    v.GetBool("update.check"),
+			Interval: DefaultUpdateInterval,
+		},
+		Output: OutputConfig{
+			SeparateStreams: v.GetBool("output.separate_streams"),
+		},
+		Report: ReportConfig{
+			Repositories: v.GetStringSlice("report.repositories"),
+		},
+		History: HistoryConfig{
+			Enabled: v.GetBool("history.enabled"),
+		},
+		Security: SecurityConfig{
+			CheckPermissions: true,
+			SyncedDirs:       v.GetStringSlice("security.synced_dirs"),
+		},
+		Tracing: TracingConfig{
+			Endpoint: v.GetString("tracing.endpoint"),
+			Headers:  v.GetStringMapString("tracing.headers"),
+		},
+		File: FileSource{
+			Path: configPath,
 		},
 	}
 
+	if v.IsSet("commit.check_generated") {
+		config.Commit.CheckGenerated = v.GetBool("commit.check_generated")
+	}
+	if v.IsSet("commit.workspace_summary") {
+		config.Commit.WorkspaceSummary = v.GetBool("commit.workspace_summary")
+	}
+	if mode := v.GetString("commit.upstream_check"); mode != "" {
+		config.Commit.UpstreamCheck = mode
+	}
+	if v.IsSet("security.check_permissions") {
+		config.Security.CheckPermissions = v.GetBool("security.check_permissions")
+	}
+	if len(config.Security.SyncedDirs) == 0 {
+		config.Security.SyncedDirs = DefaultSyncedDirPatterns
+	}
+
+	if windowStr := v.GetString("ai.session_window"); windowStr != "" {
+		if window, err := time.ParseDuration(windowStr); err == nil {
+			config.AI.SessionWindow = window
+		} else {
+			utils.Logger.Debug().Err(err).Str("value", windowStr).Msg("Invalid ai.session_window, using default")
+		}
+	}
+
+	// An explicitly empty prefix names tags after the bare version
+	if v.IsSet("release.tag_prefix") {
+		config.Release.TagPrefix = v.GetString("release.tag_prefix")
+	}
+
+	if tmpl := v.GetString("commit.stats_footer_template"); tmpl != "" {
+		config.Commit.StatsFooterTemplate = tmpl
+	}
+	if key := v.GetString("commit.ai_trailer_key"); key != "" {
+		config.Commit.AITrailerKey = key
+	}
+	if tmpl := v.GetString("commit.branch_template"); tmpl != "" {
+		config.Commit.BranchTemplate = tmpl
+	}
+	if len(config.Commit.TicketPatterns) == 0 {
+		config.Commit.TicketPatterns = DefaultTicketPatterns
+	}
+
+	if timeoutStr := v.GetString("hooks.timeout"); timeoutStr != "" {
+		if timeout, err := time.ParseDuration(timeoutStr); err == nil {
+			config.Hooks.Timeout = timeout
+		} else {
+			utils.Logger.Debug().Err(err).Str("value", timeoutStr).Msg("Invalid hooks.timeout, using default")
+		}
+	}
+
+	if intervalStr := v.GetString("update.interval"); intervalStr != "" {
+		if interval, err := time.ParseDuration(intervalStr); err == nil {
+			config.Update.Interval = interval
+		} else {
+			utils.Logger.Debug().Err(err).Str("value", intervalStr).Msg("Invalid update.interval, using default")
+		}
+	}
+
+	// An explicit 0 budget sends every section
+	if v.IsSet("ai.context.budget") {
+		config.AI.Context.Budget = v.GetInt("ai.context.budget")
+	}
+	// An explicit 0 size never truncates the diffs
+	if v.IsSet("ai.context.max_diff_size") {
+		config.AI.Context.Diff.MaxSize = v.GetInt("ai.context.max_diff_size")
+	}
+	// The model writes the headers in the layout the validator expects
+	config.AI.Context.HeaderFormat = config.Commit.HeaderFormat
+
+	if err := v.UnmarshalKey("ai.account_rules", &config.AI.AccountRules); err != nil {
+		utils.Logger.Debug().Err(err).Msg("Invalid ai.account_rules, ignored")
+	}
+
 	// Load provider configurations
 	providers := v.GetStringMap("ai.providers")
 	for name := range providers {
 		providerConfig := model.AIProviderConfig{
-			Name:     name,
-			APIKey:   v.GetString(fmt.Sprintf("ai.providers.%s.api_key", name)),
-			Model:    v.GetString(fmt.Sprintf("ai.providers.%s.model", name)),
-			Endpoint: v.GetString(fmt.Sprintf("ai.providers.%s.endpoint", name)),
-			Timeout:  30 * time.Second,
+			Name:          name,
+			APIKey:        v.GetString(fmt.Sprintf("ai.providers.%s.api_key", name)),
+			APIKeyCommand: v.GetString(fmt.Sprintf("ai.providers.%s.api_key_command", name)),
+			Model:         v.GetString(fmt.Sprintf("ai.providers.%s.model", name)),
+			Endpoint:      v.GetString(fmt.Sprintf("ai.providers.%s.endpoint", name)),
+			Timeout:       30 * time.Second,
+
+			MaxConcurrent:     v.GetInt(fmt.Sprintf("ai.providers.%s.max_concurrent", name)),
+			RequestsPerMinute: v.GetInt(fmt.Sprintf("ai.providers.%s.requests_per_minute", name)),
+			NumCtx:            v.GetInt(fmt.Sprintf("ai.providers.%s.num_ctx", name)),
+			KeepAlive:         v.GetString(fmt.Sprintf("ai.providers.%s.keep_alive", name)),
+			CircuitThreshold:  DefaultCircuitThreshold,
+			CircuitCooldown:   DefaultCircuitCooldown,
+			RetryAttempts:     DefaultRetryAttempts,
+			RetryBackoff:      DefaultRetryBackoff,
+			RetryMaxBackoff:   DefaultRetryMaxBackoff,
+			RetryJitter:       DefaultRetryJitter,
+			Prompt:            config.AI.Context,
+		}
+
+		for account := range v.GetStringMap(fmt.Sprintf("ai.providers.%s.accounts", name)) {
+			if providerConfig.Accounts == nil {
+				providerConfig.Accounts = make(map[string]model.ProviderAccount)
+			}
+			providerConfig.Accounts[account] = model.ProviderAccount{
+				APIKey:        v.GetString(fmt.Sprintf("ai.providers.%s.accounts.%s.api_key", name, account)),
+				APIKeyCommand: v.GetString(fmt.Sprintf("ai.providers.%s.accounts.%s.api_key_command", name, account)),
+			}
+		}
+
+		if key := fmt.Sprintf("ai.providers.%s.context_budget", name); v.IsSet(key) {
+			providerConfig.Prompt.Budget = v.GetInt(key)
+		}
+		// Prompt tokens are counted with the tokenizer of the provider's model, unless the
+		// provider serves another model family (e.g. an OpenAI-compatible local server)
+		providerConfig.Prompt.Tokenizer = name + "/" + providerConfig.Model
+		if tokenizer := v.GetString(fmt.Sprintf("ai.providers.%s.tokenizer", name)); tokenizer != "" {
+			providerConfig.Prompt.Tokenizer = tokenizer
+		}
+		if key := fmt.Sprintf("ai.providers.%s.diff_context", name); v.IsSet(key) {
+			providerConfig.Prompt.Diff.Context = v.GetInt(key)
+		}
+		if key := fmt.Sprintf("ai.providers.%s.max_diff_size", name); v.IsSet(key) {
+			providerConfig.Prompt.Diff.MaxSize = v.GetInt(key)
+		}
+
+		// An explicit 0 threshold disables the circuit breaker
+		if key := fmt.Sprintf("ai.providers.%s.circuit_threshold", name); v.IsSet(key) {
+			providerConfig.CircuitThreshold = v.GetInt(key)
+		}
+		if cooldownStr := v.GetString(fmt.Sprintf("ai.providers.%s.circuit_cooldown", name)); cooldownStr != "" {
+			if cooldown, err := time.ParseDuration(cooldownStr); err == nil {
+				providerConfig.CircuitCooldown = cooldown
+			} else {
+				utils.Logger.Debug().Err(err).Str("value", cooldownStr).Msg("Invalid circuit_cooldown, using default")
+			}
+		}
+
+		if key := fmt.Sprintf("ai.providers.%s.retry_attempts", name); v.IsSet(key) {
+			providerConfig.RetryAttempts = v.GetInt(key)
+		}
+		if backoffStr := v.GetString(fmt.Sprintf("ai.providers.%s.retry_backoff", name)); backoffStr != "" {
+			if backoff, err := time.ParseDuration(backoffStr); err == nil {
+				providerConfig.RetryBackoff = backoff
+			} else {
+				utils.Logger.Debug().Err(err).Str("value", backoffStr).Msg("Invalid retry_backoff, using default")
+			}
+		}
+		if backoffStr := v.GetString(fmt.Sprintf("ai.providers.%s.retry_max_backoff", name)); backoffStr != "" {
+			if backoff, err := time.ParseDuration(backoffStr); err == nil {
+				providerConfig.RetryMaxBackoff = backoff
+			} else {
+				utils.Logger.Debug().Err(err).Str("value", backoffStr).Msg("Invalid retry_max_backoff, using default")
+			}
+		}
+		if key := fmt.Sprintf("ai.providers.%s.retry_jitter", name); v.IsSet(key) {
+			providerConfig.RetryJitter = v.GetFloat64(key)
 		}
 
 		// Override timeout if specified
@@ -124,11 +640,66 @@ func LoadConfig(configPath string) (*Config, error) {
 		config.AI.Providers[name] = providerConfig
 	}
 
+	config.File.HasSecrets = containsSecrets(string(content), config.secrets())
+
 	return config, nil
 }
 
-// GetProviderConfig returns the configuration for a specific provider
+// secrets returns the configured API keys, tokens and passwords
+func (c *Config) secrets() []string {
+	secrets := []string{
+		c.Email.SMTPPassword,
+		c.Issues.GitHubToken,
+		c.Issues.GitLabToken,
+		c.Issues.JiraToken,
+		c.Timer.TogglToken,
+	}
+	for _, provider := range c.AI.Providers {
+		// A keychain reference is not the secret itself
+		if _, ok := keyring.ParseReference(provider.APIKey); !ok {
+			secrets = append(secrets, provider.APIKey)
+		}
+	}
+	for _, header := range c.Tracing.Headers {
+		secrets = append(secrets, header)
+	}
+	return secrets
+}
+
+// containsSecrets reports whether any of secrets is written as is in the raw file content.
+// Secrets substituted from placeholders or read from the environment are not in the file.
+func containsSecrets(content string, secrets []string) bool {
+	for _, secret := range secrets {
+		if secret != "" && strings.Contains(content, secret) {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolvePath returns configPath, or the default ~/.gitcomm/config.yaml when empty
+func ResolvePath(configPath string) (string, error) {
+	if configPath != "" {
+		return configPath, nil
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".gitcomm", "config.yaml"), nil
+}
+
+// GetProviderConfig returns the configuration for a specific provider, with the credentials
+// of the account selected for the current directory (see GetAccountProviderConfig)
 func (c *Config) GetProviderConfig(name string) (*model.AIProviderConfig, error) {
+	return c.GetAccountProviderConfig(name, "")
+}
+
+// GetAccountProviderConfig returns the configuration for a specific provider with the
+// credentials of account, or of the account selected by SelectAccount when "" (providers
+// without accounts then use their own key). An API key written keyring:<account> is read
+// from the OS keychain, and the api_key_command is run (once per process) to get the key.
+func (c *Config) GetAccountProviderConfig(name, account string) (*model.AIProviderConfig, error) {
 	if name == "" {
 		name = c.AI.DefaultProvider
 	}
@@ -138,6 +709,36 @@ func (c *Config) GetProviderConfig(name string) (*model.AIProviderConfig, error)
 		return nil, fmt.Errorf("provider %s not configured", name)
 	}
 
+	key := "ai.providers." + name
+	if account = c.ProviderAccount(name, account); account != "" {
+		credentials, ok := provider.Accounts[account]
+		if !ok {
+			return nil, fmt.Errorf("provider %s has no account %q (%s.accounts)", name, account, key)
+		}
+		provider.APIKey = credentials.APIKey
+		provider.APIKeyCommand = credentials.APIKeyCommand
+		provider.Account = account
+		key += ".accounts." + account
+	}
+
+	if provider.APIKeyCommand != "" {
+		apiKey, err := runKeyCommand(provider.APIKeyCommand)
+		if err != nil {
+			return nil, fmt.Errorf("%s.api_key_command: %w", key, err)
+		}
+		provider.APIKey = apiKey
+		return &provider, nil
+	}
+
+	apiKey, err := keyring.Resolve(provider.APIKey)
+	if err != nil {
+		if account != "" {
+			return nil, fmt.Errorf("%s.api_key: %w", key, err)
+		}
+		return nil, fmt.Errorf("%s.api_key: %w (store it with gitcomm auth login %s)", key, err, name)
+	}
+	provider.APIKey = apiKey
+
 	return &provider, nil
 }
 
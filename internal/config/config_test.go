@@ -1,12 +1,17 @@
 package config
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/golgoth31/gitcomm/internal/keyring"
+	"github.com/golgoth31/gitcomm/internal/model"
 	"github.com/golgoth31/gitcomm/internal/utils"
 )
 
@@ -543,3 +548,480 @@ func TestEmptyStringValueHandling(t *testing.T) {
 		t.Fatalf("Expected %s, got %s", expected, result)
 	}
 }
+
+func TestLoadConfig_CommitAndPostProcessorSettings(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	content := `ai:
+  post_processors:
+    - trim-code-fences
+    - remove-trailing-period
+commit:
+  signoff_identity: "Jane Doe <jane@corp.example.com>"
+  dco: true
+`
+	if err := os.WriteFile(configPath, []byte(content), 0600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	cfg, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+
+	wantProcessors := []string{"trim-code-fences", "remove-trailing-period"}
+	if strings.Join(cfg.AI.PostProcessors, ",") != strings.Join(wantProcessors, ",") {
+		t.Errorf("PostProcessors = %v, want %v", cfg.AI.PostProcessors, wantProcessors)
+	}
+	if cfg.Commit.SignoffIdentity != "Jane Doe <jane@corp.example.com>" {
+		t.Errorf("SignoffIdentity = %q", cfg.Commit.SignoffIdentity)
+	}
+	if !cfg.Commit.DCO {
+		t.Error("DCO = false, want true")
+	}
+	if cfg.Commit.StatsFooter {
+		t.Error("StatsFooter = true, want disabled by default")
+	}
+	if cfg.Commit.StatsFooterTemplate != DefaultStatsFooterTemplate {
+		t.Errorf("StatsFooterTemplate = %q, want default template", cfg.Commit.StatsFooterTemplate)
+	}
+	if cfg.Commit.AITrailer || cfg.Commit.AITrailerKey != DefaultAITrailerKey {
+		t.Errorf("AITrailer = %t, AITrailerKey = %q, want disabled with key %s by default", cfg.Commit.AITrailer, cfg.Commit.AITrailerKey, DefaultAITrailerKey)
+	}
+	if !cfg.Commit.CheckGenerated {
+		t.Error("CheckGenerated = false, want enabled by default")
+	}
+}
+
+func TestLoadConfig_IssuesSettings(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	content := `issues:
+  verify: true
+  github_token: gh-token
+  jira:
+    url: https://company.atlassian.net
+    user: jane@example.com
+    token: jira-token
+    projects: [PROJ, OPS]
+`
+	if err := os.WriteFile(configPath, []byte(content), 0600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	cfg, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+
+	if !cfg.Issues.Verify {
+		t.Error("Issues.Verify = false, want true")
+	}
+	if cfg.Issues.GitHubToken != "gh-token" || cfg.Issues.GitLabToken != "" {
+		t.Errorf("forge tokens = %q, %q", cfg.Issues.GitHubToken, cfg.Issues.GitLabToken)
+	}
+	if cfg.Issues.JiraURL != "https://company.atlassian.net" || cfg.Issues.JiraUser != "jane@example.com" || cfg.Issues.JiraToken != "jira-token" {
+		t.Errorf("Jira settings = %+v", cfg.Issues)
+	}
+	if strings.Join(cfg.Issues.JiraProjects, ",") != "PROJ,OPS" {
+		t.Errorf("JiraProjects = %v, want [PROJ OPS]", cfg.Issues.JiraProjects)
+	}
+}
+
+func TestLoadConfig_HooksSettings(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	content := `hooks:
+  pre_commit: ["make lint"]
+  post_commit:
+    - ~/bin/track-time.sh
+    - notify-send "commit created"
+  timeout: 5s
+`
+	if err := os.WriteFile(configPath, []byte(content), 0600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	cfg, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+
+	if strings.Join(cfg.Hooks.PreCommit, "|") != "make lint" {
+		t.Errorf("Hooks.PreCommit = %v, want [make lint]", cfg.Hooks.PreCommit)
+	}
+	if strings.Join(cfg.Hooks.PostCommit, "|") != `~/bin/track-time.sh|notify-send "commit created"` {
+		t.Errorf("Hooks.PostCommit = %v", cfg.Hooks.PostCommit)
+	}
+	if len(cfg.Hooks.PreGenerate) != 0 || len(cfg.Hooks.PostGenerate) != 0 {
+		t.Errorf("generate hooks = %v, %v, want none", cfg.Hooks.PreGenerate, cfg.Hooks.PostGenerate)
+	}
+	if cfg.Hooks.Timeout != 5*time.Second {
+		t.Errorf("Hooks.Timeout = %v, want 5s", cfg.Hooks.Timeout)
+	}
+}
+
+func TestLoadConfig_ReleaseTagPrefix(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"default", "", DefaultTagPrefix},
+		{"custom", "release:\n  tag_prefix: release-\n", "release-"},
+		{"bare versions", "release:\n  tag_prefix: \"\"\n", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(configPath, []byte(tt.content), 0600); err != nil {
+				t.Fatalf("Failed to write config: %v", err)
+			}
+
+			cfg, err := LoadConfig(configPath)
+			if err != nil {
+				t.Fatalf("LoadConfig() error = %v", err)
+			}
+			if cfg.Release.TagPrefix != tt.want {
+				t.Errorf("Release.TagPrefix = %q, want %q", cfg.Release.TagPrefix, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_CircuitBreaker(t *testing.T) {
+	tests := []struct {
+		name          string
+		content       string
+		wantThreshold int
+		wantCooldown  time.Duration
+	}{
+		{"default", "ai:\n  providers:\n    openai:\n      model: gpt-4\n", DefaultCircuitThreshold, DefaultCircuitCooldown},
+		{"custom", "ai:\n  providers:\n    openai:\n      circuit_threshold: 5\n      circuit_cooldown: 30s\n", 5, 30 * time.Second},
+		{"disabled", "ai:\n  providers:\n    openai:\n      circuit_threshold: 0\n", 0, DefaultCircuitCooldown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(configPath, []byte(tt.content), 0600); err != nil {
+				t.Fatalf("Failed to write config: %v", err)
+			}
+
+			cfg, err := LoadConfig(configPath)
+			if err != nil {
+				t.Fatalf("LoadConfig() error = %v", err)
+			}
+			provider := cfg.AI.Providers["openai"]
+			if provider.CircuitThreshold != tt.wantThreshold || provider.CircuitCooldown != tt.wantCooldown {
+				t.Errorf("circuit = %d failures, %s cooldown, want %d, %s",
+					provider.CircuitThreshold, provider.CircuitCooldown, tt.wantThreshold, tt.wantCooldown)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_Retry(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    model.AIProviderConfig
+	}{
+		{
+			name:    "default",
+			content: "ai:\n  providers:\n    openai:\n      model: gpt-4\n",
+			want:    model.AIProviderConfig{RetryAttempts: DefaultRetryAttempts, RetryBackoff: DefaultRetryBackoff, RetryMaxBackoff: DefaultRetryMaxBackoff, RetryJitter: DefaultRetryJitter},
+		},
+		{
+			name:    "custom",
+			content: "ai:\n  providers:\n    openai:\n      retry_attempts: 5\n      retry_backoff: 500ms\n      retry_max_backoff: 10s\n      retry_jitter: 0\n",
+			want:    model.AIProviderConfig{RetryAttempts: 5, RetryBackoff: 500 * time.Millisecond, RetryMaxBackoff: 10 * time.Second},
+		},
+		{
+			name:    "disabled",
+			content: "ai:\n  providers:\n    openai:\n      retry_attempts: 1\n",
+			want:    model.AIProviderConfig{RetryAttempts: 1, RetryBackoff: DefaultRetryBackoff, RetryMaxBackoff: DefaultRetryMaxBackoff, RetryJitter: DefaultRetryJitter},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(configPath, []byte(tt.content), 0600); err != nil {
+				t.Fatalf("Failed to write config: %v", err)
+			}
+
+			cfg, err := LoadConfig(configPath)
+			if err != nil {
+				t.Fatalf("LoadConfig() error = %v", err)
+			}
+			got := cfg.AI.Providers["openai"]
+			if got.RetryAttempts != tt.want.RetryAttempts || got.RetryBackoff != tt.want.RetryBackoff ||
+				got.RetryMaxBackoff != tt.want.RetryMaxBackoff || got.RetryJitter != tt.want.RetryJitter {
+				t.Errorf("retry = %d attempts, %s backoff, %s max, %v jitter, want %d, %s, %s, %v",
+					got.RetryAttempts, got.RetryBackoff, got.RetryMaxBackoff, got.RetryJitter,
+					tt.want.RetryAttempts, tt.want.RetryBackoff, tt.want.RetryMaxBackoff, tt.want.RetryJitter)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_OllamaOptions(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	content := "ai:\n  providers:\n    ollama:\n      model: llama3.2\n      num_ctx: 8192\n      keep_alive: 10m\n"
+	if err := os.WriteFile(configPath, []byte(content), 0600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	cfg, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	provider := cfg.AI.Providers["ollama"]
+	if provider.NumCtx != 8192 || provider.KeepAlive != "10m" {
+		t.Errorf("ollama options = num_ctx %d, keep_alive %q, want 8192, 10m", provider.NumCtx, provider.KeepAlive)
+	}
+}
+
+func TestLoadConfig_Context(t *testing.T) {
+	tests := []struct {
+		name         string
+		content      string
+		wantBudget   int
+		wantOrder    []string
+		wantPriority []string
+	}{
+		{"default", "ai:\n  providers:\n    openai:\n      model: gpt-4\n", DefaultContextBudget, nil, nil},
+		{
+			"global layout",
+			"ai:\n  context:\n    budget: 4000\n    order: [staged_diffs, branch]\n    priority: [branch]\n  providers:\n    openai:\n      model: gpt-4\n",
+			4000, []string{"staged_diffs", "branch"}, []string{"branch"},
+		},
+		{"provider budget", "ai:\n  context:\n    budget: 4000\n  providers:\n    openai:\n      context_budget: 0\n", 0, nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(configPath, []byte(tt.content), 0600); err != nil {
+				t.Fatalf("Failed to write config: %v", err)
+			}
+
+			cfg, err := LoadConfig(configPath)
+			if err != nil {
+				t.Fatalf("LoadConfig() error = %v", err)
+			}
+			layout := cfg.AI.Providers["openai"].Prompt
+			if layout.Budget != tt.wantBudget {
+				t.Errorf("budget = %d, want %d", layout.Budget, tt.wantBudget)
+			}
+			if !slices.Equal(layout.Order, tt.wantOrder) {
+				t.Errorf("order = %v, want %v", layout.Order, tt.wantOrder)
+			}
+			if !slices.Equal(layout.Priority, tt.wantPriority) {
+				t.Errorf("priority = %v, want %v", layout.Priority, tt.wantPriority)
+			}
+			if err := cfg.Validate(); err != nil {
+				t.Errorf("Validate() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_DiffLimits(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    model.DiffLimits
+		wantErr bool
+	}{
+		{"default", "ai:\n  providers:\n    openai:\n      model: gpt-4\n", model.DiffLimits{MaxSize: DefaultMaxDiffSize}, false},
+		{"global", "ai:\n  context:\n    diff_context: 3\n    max_diff_size: 20000\n  providers:\n    openai:\n      model: gpt-4\n", model.DiffLimits{Context: 3, MaxSize: 20000}, false},
+		{"provider override", "ai:\n  context:\n    diff_context: 3\n  providers:\n    openai:\n      diff_context: 1\n      max_diff_size: 0\n", model.DiffLimits{Context: 1}, false},
+		{"negative", "ai:\n  providers:\n    openai:\n      diff_context: -1\n", model.DiffLimits{Context: -1, MaxSize: DefaultMaxDiffSize}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(configPath, []byte(tt.content), 0600); err != nil {
+				t.Fatalf("Failed to write config: %v", err)
+			}
+
+			cfg, err := LoadConfig(configPath)
+			if err != nil {
+				t.Fatalf("LoadConfig() error = %v", err)
+			}
+			if got := cfg.AI.Providers["openai"].Prompt.Diff; got != tt.want {
+				t.Errorf("diff limits = %+v, want %+v", got, tt.want)
+			}
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_Tokenizer(t *testing.T) {
+	content := "ai:\n  providers:\n    openai:\n      model: gpt-4\n    local:\n      endpoint: http://localhost:8080/v1\n      tokenizer: openai/gpt-4o\n"
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configPath, []byte(content), 0600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	cfg, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if got := cfg.AI.Providers["openai"].Prompt.Tokenizer; got != "openai/gpt-4" {
+		t.Errorf("openai tokenizer = %q, want openai/gpt-4", got)
+	}
+	if got := cfg.AI.Providers["local"].Prompt.Tokenizer; got != "openai/gpt-4o" {
+		t.Errorf("local tokenizer = %q, want the configured openai/gpt-4o", got)
+	}
+}
+
+func TestLoadConfig_SecretsInFile(t *testing.T) {
+	t.Setenv("GITCOMM_TEST_KEY", "sk-from-env")
+
+	tests := []struct {
+		name        string
+		content     string
+		wantSecrets bool
+		wantCheck   bool
+	}{
+		{"no secrets", "ai:\n  default_provider: local\n", false, true},
+		{"literal API key", "ai:\n  providers:\n    openai:\n      api_key: sk-literal\n", true, true},
+		{"placeholder API key", "ai:\n  providers:\n    openai:\n      api_key: ${GITCOMM_TEST_KEY}\n", false, true},
+		{"keychain API key", "ai:\n  providers:\n    openai:\n      api_key: keyring:openai\n", false, true},
+		{"literal token", "issues:\n  github_token: ghp_literal\n", true, true},
+		{"check disabled", "security:\n  check_permissions: false\nissues:\n  github_token: ghp_literal\n", true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(configPath, []byte(tt.content), 0600); err != nil {
+				t.Fatalf("Failed to write config: %v", err)
+			}
+
+			cfg, err := LoadConfig(configPath)
+			if err != nil {
+				t.Fatalf("LoadConfig() error = %v", err)
+			}
+			if cfg.File.Path != configPath {
+				t.Errorf("File.Path = %q, want %q", cfg.File.Path, configPath)
+			}
+			if cfg.File.HasSecrets != tt.wantSecrets {
+				t.Errorf("File.HasSecrets = %v, want %v", cfg.File.HasSecrets, tt.wantSecrets)
+			}
+			if cfg.Security.CheckPermissions != tt.wantCheck {
+				t.Errorf("Security.CheckPermissions = %v, want %v", cfg.Security.CheckPermissions, tt.wantCheck)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_BranchTickets(t *testing.T) {
+	tests := []struct {
+		name         string
+		content      string
+		wantEnabled  bool
+		wantPatterns []string
+	}{
+		{
+			name:         "default",
+			content:      "commit:\n  dco: true\n",
+			wantPatterns: DefaultTicketPatterns,
+		},
+		{
+			name:         "custom patterns",
+			content:      "commit:\n  branch_tickets: true\n  ticket_patterns: ['#?(\\d+)-', 'GH-\\d+']\n",
+			wantEnabled:  true,
+			wantPatterns: []string{`#?(\d+)-`, `GH-\d+`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(configPath, []byte(tt.content), 0600); err != nil {
+				t.Fatalf("Failed to write config: %v", err)
+			}
+
+			cfg, err := LoadConfig(configPath)
+			if err != nil {
+				t.Fatalf("LoadConfig() error = %v", err)
+			}
+			if cfg.Commit.BranchTickets != tt.wantEnabled || !slices.Equal(cfg.Commit.TicketPatterns, tt.wantPatterns) {
+				t.Errorf("branch tickets = %v %q, want %v %q", cfg.Commit.BranchTickets, cfg.Commit.TicketPatterns, tt.wantEnabled, tt.wantPatterns)
+			}
+		})
+	}
+}
+
+func TestGetProviderConfig_Keyring(t *testing.T) {
+	defer keyring.Use(keyring.Memory{"openai": "sk-from-keychain"})()
+
+	cfg := &Config{}
+	cfg.AI.Providers = map[string]model.AIProviderConfig{
+		"openai":    {Name: "openai", APIKey: "keyring:openai"},
+		"anthropic": {Name: "anthropic", APIKey: "keyring:anthropic"},
+		"mistral":   {Name: "mistral", APIKey: "sk-clear"},
+	}
+
+	provider, err := cfg.GetProviderConfig("openai")
+	if err != nil {
+		t.Fatalf("GetProviderConfig(openai) error = %v", err)
+	}
+	if provider.APIKey != "sk-from-keychain" {
+		t.Errorf("APIKey = %q, want the keychain secret", provider.APIKey)
+	}
+	if cfg.AI.Providers["openai"].APIKey != "keyring:openai" {
+		t.Errorf("configuration changed to %q, want the reference kept", cfg.AI.Providers["openai"].APIKey)
+	}
+
+	if provider, err := cfg.GetProviderConfig("mistral"); err != nil || provider.APIKey != "sk-clear" {
+		t.Errorf("GetProviderConfig(mistral) = %+v, %v, want the key as written", provider, err)
+	}
+
+	_, err = cfg.GetProviderConfig("anthropic")
+	if !errors.Is(err, keyring.ErrNotFound) || !strings.Contains(err.Error(), "gitcomm auth login anthropic") {
+		t.Errorf("GetProviderConfig(anthropic) error = %v, want ErrNotFound with the login hint", err)
+	}
+}
+
+func TestGetProviderConfig_KeyCommand(t *testing.T) {
+	counter := filepath.Join(t.TempDir(), "runs")
+	cfg := &Config{}
+	cfg.AI.Providers = map[string]model.AIProviderConfig{
+		"openai":    {Name: "openai", APIKeyCommand: "echo run >> " + counter + "; printf '  sk-from-command\\n'"},
+		"anthropic": {Name: "anthropic", APIKeyCommand: "echo vault is sealed >&2; exit 2"},
+		"mistral":   {Name: "mistral", APIKeyCommand: "true"},
+	}
+
+	for range 2 {
+		provider, err := cfg.GetProviderConfig("openai")
+		if err != nil {
+			t.Fatalf("GetProviderConfig(openai) error = %v", err)
+		}
+		if provider.APIKey != "sk-from-command" {
+			t.Errorf("APIKey = %q, want the trimmed command output", provider.APIKey)
+		}
+	}
+	runs, err := os.ReadFile(counter)
+	if err != nil {
+		t.Fatalf("failed to read the run counter: %v", err)
+	}
+	if n := strings.Count(string(runs), "run"); n != 1 {
+		t.Errorf("command ran %d times, want once per process", n)
+	}
+
+	_, err = cfg.GetProviderConfig("anthropic")
+	if err == nil || !strings.Contains(err.Error(), "ai.providers.anthropic.api_key_command") || !strings.Contains(err.Error(), "vault is sealed") {
+		t.Errorf("GetProviderConfig(anthropic) error = %v, want the setting and the command's stderr", err)
+	}
+
+	_, err = cfg.GetProviderConfig("mistral")
+	if err == nil || !strings.Contains(err.Error(), "printed no key") {
+		t.Errorf("GetProviderConfig(mistral) error = %v, want an empty output error", err)
+	}
+}
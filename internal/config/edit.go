@@ -0,0 +1,353 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// Setting is a key set in the config file with its value as written, placeholders
+// included; lists and maps are written in YAML flow style
+type Setting struct {
+	Key    string
+	Value  string
+	Secret bool
+}
+
+// GetValue returns the value of key in the config file at path, false when it is not
+// set. A section is returned as YAML.
+func GetValue(path, name string) (string, bool, error) {
+	if _, err := LookupKey(name); err != nil && !isSection(name) {
+		return "", false, err
+	}
+	root, err := readDocument(path)
+	if err != nil {
+		return "", false, err
+	}
+	node := findNode(root.Content[0], strings.Split(name, "."))
+	if node == nil {
+		return "", false, nil
+	}
+	if node.Kind == yaml.ScalarNode {
+		return node.Value, true, nil
+	}
+	out, err := marshalNode(node)
+	if err != nil {
+		return "", false, err
+	}
+	return strings.TrimSuffix(string(out), "\n"), true, nil
+}
+
+// SetValue sets key to value in the config file at path, creating the file and the
+// sections as needed. The comments of the file are kept, and nothing is written when the
+// resulting configuration is invalid.
+func SetValue(path, name, value string) error {
+	key, err := LookupKey(name)
+	if err != nil {
+		return err
+	}
+	node, err := key.ParseValue(value)
+	if err != nil {
+		return err
+	}
+	root, err := readDocument(path)
+	if err != nil {
+		return err
+	}
+	if err := setNode(root.Content[0], strings.Split(name, "."), node); err != nil {
+		return err
+	}
+	return writeDocument(path, root)
+}
+
+// UnsetValue removes key, or a whole section, from the config file at path, reporting
+// whether it was set. Sections left empty are removed too.
+func UnsetValue(path, name string) (bool, error) {
+	if _, err := LookupKey(name); err != nil && !isSection(name) {
+		return false, err
+	}
+	root, err := readDocument(path)
+	if err != nil {
+		return false, err
+	}
+	if !removeNode(root.Content[0], strings.Split(name, ".")) {
+		return false, nil
+	}
+	return true, writeDocument(path, root)
+}
+
+// ListValues returns the keys set in the config file at path, in file order. Keys
+// gitcomm does not know are listed too.
+func ListValues(path string) ([]Setting, error) {
+	root, err := readDocument(path)
+	if err != nil {
+		return nil, err
+	}
+	var settings []Setting
+	err = walkValues(root.Content[0], nil, func(name string, node *yaml.Node) error {
+		setting := Setting{Key: name, Value: node.Value}
+		if key, err := LookupKey(name); err == nil {
+			setting.Secret = key.Secret
+		}
+		if node.Kind != yaml.ScalarNode {
+			flow := *node
+			flow.Style = yaml.FlowStyle
+			out, err := marshalNode(&flow)
+			if err != nil {
+				return err
+			}
+			setting.Value = strings.TrimSuffix(string(out), "\n")
+		}
+		settings = append(settings, setting)
+		return nil
+	})
+	return settings, err
+}
+
+// UnknownKeys returns the keys of content that gitcomm does not know, usually typos
+func UnknownKeys(content []byte) ([]string, error) {
+	root, err := parseDocument(content)
+	if err != nil {
+		return nil, err
+	}
+	var unknown []string
+	err = walkValues(root.Content[0], nil, func(name string, _ *yaml.Node) error {
+		if _, err := LookupKey(name); err != nil {
+			unknown = append(unknown, name)
+		}
+		return nil
+	})
+	return unknown, err
+}
+
+// WriteFile replaces the config file at path with content if it is a valid configuration:
+// well-formed YAML, values of the right type and a configuration passing Validate. The
+// file keeps its permissions, and a new file is private.
+func WriteFile(path string, content []byte) error {
+	root, err := parseDocument(content)
+	if err != nil {
+		return err
+	}
+	if err := checkValues(root); err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create config directory %s: %w", dir, err)
+	}
+	file, err := os.CreateTemp(dir, ".config-*.yaml")
+	if err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	defer os.Remove(file.Name())
+	if _, err := file.Write(content); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+
+	cfg, err := LoadConfig(file.Name())
+	if err != nil {
+		return err
+	}
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+
+	if info, err := os.Stat(path); err == nil {
+		if err := os.Chmod(file.Name(), info.Mode().Perm()); err != nil {
+			return fmt.Errorf("failed to write config file: %w", err)
+		}
+	}
+	if err := os.Rename(file.Name(), path); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	return nil
+}
+
+// CheckFile checks the values of the config file at path against the type of their key,
+// returning the keys gitcomm does not know. A missing file is valid.
+func CheckFile(path string) ([]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	root, err := parseDocument(content)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkValues(root); err != nil {
+		return nil, err
+	}
+	return UnknownKeys(content)
+}
+
+// checkValues checks the value of each known key of root
+func checkValues(root *yaml.Node) error {
+	var errs []error
+	_ = walkValues(root.Content[0], nil, func(name string, node *yaml.Node) error {
+		if key, err := LookupKey(name); err == nil {
+			if err := key.CheckValue(node); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return nil
+	})
+	return errors.Join(errs...)
+}
+
+// readDocument parses the config file at path; a missing or empty file is an empty mapping
+func readDocument(path string) (*yaml.Node, error) {
+	content, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	return parseDocument(content)
+}
+
+// parseDocument parses a config file, which must be a YAML mapping
+func parseDocument(content []byte) (*yaml.Node, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(content, &root); err != nil {
+		return nil, fmt.Errorf("invalid YAML: %w", err)
+	}
+	if root.Kind == 0 {
+		root = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	if root.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("invalid configuration: the file must be a YAML mapping")
+	}
+	return &root, nil
+}
+
+// writeDocument writes root to the config file at path with WriteFile
+func writeDocument(path string, root *yaml.Node) error {
+	out, err := marshalNode(root)
+	if err != nil {
+		return err
+	}
+	return WriteFile(path, out)
+}
+
+// marshalNode returns node as YAML, indented like the README examples
+func marshalNode(node *yaml.Node) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(node); err != nil {
+		return nil, fmt.Errorf("failed to encode configuration: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode configuration: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// findNode returns the value at path in mapping, nil when it is not set
+func findNode(mapping *yaml.Node, path []string) *yaml.Node {
+	for _, segment := range path {
+		if mapping.Kind != yaml.MappingNode {
+			return nil
+		}
+		var next *yaml.Node
+		for i := 0; i+1 < len(mapping.Content); i += 2 {
+			if mapping.Content[i].Value == segment {
+				next = mapping.Content[i+1]
+				break
+			}
+		}
+		if next == nil {
+			return nil
+		}
+		mapping = next
+	}
+	return mapping
+}
+
+// setNode sets the value at path in mapping, creating the missing sections
+func setNode(mapping *yaml.Node, path []string, value *yaml.Node) error {
+	for depth, segment := range path {
+		last := depth == len(path)-1
+		var next *yaml.Node
+		for i := 0; i+1 < len(mapping.Content); i += 2 {
+			if mapping.Content[i].Value == segment {
+				if last {
+					// Keep the comments written next to the value
+					value.LineComment = mapping.Content[i+1].LineComment
+					mapping.Content[i+1] = value
+					return nil
+				}
+				next = mapping.Content[i+1]
+				break
+			}
+		}
+		if last {
+			mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: segment}, value)
+			return nil
+		}
+		if next == nil {
+			next = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: segment}, next)
+		}
+		if next.Kind != yaml.MappingNode {
+			return fmt.Errorf("%s is not a section", strings.Join(path[:depth+1], "."))
+		}
+		mapping = next
+	}
+	return nil
+}
+
+// removeNode removes the value at path from mapping and the sections it leaves empty,
+// reporting whether it was set
+func removeNode(mapping *yaml.Node, path []string) bool {
+	if mapping.Kind != yaml.MappingNode {
+		return false
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value != path[0] {
+			continue
+		}
+		value := mapping.Content[i+1]
+		if len(path) > 1 {
+			if !removeNode(value, path[1:]) {
+				return false
+			}
+			if len(value.Content) > 0 {
+				return true
+			}
+		}
+		mapping.Content = append(mapping.Content[:i], mapping.Content[i+2:]...)
+		return true
+	}
+	return false
+}
+
+// walkValues calls fn with the dotted name of each value under mapping, in file order:
+// scalars, lists, and the maps of map keys (tracing.headers)
+func walkValues(mapping *yaml.Node, parent []string, fn func(name string, node *yaml.Node) error) error {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		path := append(append([]string(nil), parent...), mapping.Content[i].Value)
+		name := strings.Join(path, ".")
+		value := mapping.Content[i+1]
+		if value.Kind == yaml.MappingNode {
+			if key, err := LookupKey(name); err != nil || key.Kind != KindMap {
+				if err := walkValues(value, path, fn); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if err := fn(name, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,275 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+const editFixture = `# gitcomm configuration
+ai:
+  default_provider: openai # the main one
+  providers:
+    openai:
+      api_key: sk-secret
+      model: gpt-4o
+commit:
+  scopes: [api, cli]
+`
+
+// writeFixture writes the config file of a test and returns its path
+func writeFixture(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	return path
+}
+
+func TestSetValue(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		value   string
+		want    []string // parts of the written file
+		wantErr string   // empty when the file is written
+	}{
+		{
+			name:  "replaces a value and keeps the comments",
+			key:   "ai.providers.openai.model",
+			value: "gpt-5",
+			want:  []string{"# gitcomm configuration", "default_provider: openai # the main one", "model: gpt-5"},
+		},
+		{
+			name:  "creates the sections",
+			key:   "ai.providers.ollama.num_ctx",
+			value: "8192",
+			want:  []string{"    ollama:\n      num_ctx: 8192"},
+		},
+		{
+			name:  "adds a top-level section",
+			key:   "hooks.timeout",
+			value: "45s",
+			want:  []string{"hooks:\n  timeout: 45s"},
+		},
+		{name: "unknown key", key: "commit.dcoo", value: "true", wantErr: "did you mean commit.dco?"},
+		{name: "invalid value", key: "commit.dco", value: "yes", wantErr: "want true or false"},
+		{name: "invalid configuration", key: "ai.default_provider", value: "mistral", wantErr: "not configured under ai.providers"},
+		{name: "invalid template", key: "commit.branch_template", value: "{{.Type", wantErr: "commit.branch_template"},
+		{name: "invalid trailer key", key: "commit.ai_trailer_key", value: "Assisted by", wantErr: "commit.ai_trailer_key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeFixture(t, editFixture)
+			err := SetValue(path, tt.key, tt.value)
+
+			content, readErr := os.ReadFile(path)
+			if readErr != nil {
+				t.Fatalf("Failed to read config file: %v", readErr)
+			}
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("SetValue() error = %v, want %q", err, tt.wantErr)
+				}
+				if string(content) != editFixture {
+					t.Errorf("config file modified by an invalid write:\n%s", content)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SetValue() error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(string(content), want) {
+					t.Errorf("config file does not contain %q:\n%s", want, content)
+				}
+			}
+			if value, ok, err := GetValue(path, tt.key); err != nil || !ok || value != tt.value {
+				t.Errorf("GetValue(%q) = %q, %t, %v, want %q", tt.key, value, ok, err, tt.value)
+			}
+		})
+	}
+}
+
+func TestSetValue_NewFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gitcomm", "config.yaml")
+	if err := SetValue(path, "commit.scopes", "api,cli"); err != nil {
+		t.Fatalf("SetValue() error = %v", err)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read config file: %v", err)
+	}
+	if want := "commit:\n  scopes:\n    - api\n    - cli\n"; string(content) != want {
+		t.Errorf("config file = %q, want %q", content, want)
+	}
+	if runtime.GOOS != "windows" {
+		if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0600 {
+			t.Errorf("new config file mode = %v, %v, want 0600", info.Mode().Perm(), err)
+		}
+	}
+}
+
+func TestUnsetValue(t *testing.T) {
+	tests := []struct {
+		name        string
+		key         string
+		wantRemoved bool
+		excludes    []string
+		wantErr     string // empty when the file is written
+	}{
+		{name: "key", key: "ai.providers.openai.model", wantRemoved: true, excludes: []string{"model:"}},
+		{name: "empty sections are removed", key: "commit.scopes", wantRemoved: true, excludes: []string{"commit:"}},
+		{name: "section", key: "commit", wantRemoved: true, excludes: []string{"scopes:"}},
+		{name: "not set", key: "commit.dco", wantRemoved: false},
+		{name: "invalid configuration", key: "ai.providers.openai", wantErr: "not configured under ai.providers"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeFixture(t, editFixture)
+			removed, err := UnsetValue(path, tt.key)
+			content, readErr := os.ReadFile(path)
+			if readErr != nil {
+				t.Fatalf("Failed to read config file: %v", readErr)
+			}
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("UnsetValue() error = %v, want %q", err, tt.wantErr)
+				}
+				if string(content) != editFixture {
+					t.Errorf("config file modified by an invalid write:\n%s", content)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UnsetValue() error = %v", err)
+			}
+			if removed != tt.wantRemoved {
+				t.Errorf("UnsetValue() = %t, want %t", removed, tt.wantRemoved)
+			}
+			for _, exclude := range tt.excludes {
+				if strings.Contains(string(content), exclude) {
+					t.Errorf("config file still contains %q:\n%s", exclude, content)
+				}
+			}
+		})
+	}
+}
+
+func TestListValues(t *testing.T) {
+	path := writeFixture(t, editFixture+"tracing:\n  headers:\n    x-api-key: secret\nlegacy: true\n")
+	settings, err := ListValues(path)
+	if err != nil {
+		t.Fatalf("ListValues() error = %v", err)
+	}
+	want := []Setting{
+		{Key: "ai.default_provider", Value: "openai"},
+		{Key: "ai.providers.openai.api_key", Value: "sk-secret", Secret: true},
+		{Key: "ai.providers.openai.model", Value: "gpt-4o"},
+		{Key: "commit.scopes", Value: "[api, cli]"},
+		{Key: "tracing.headers", Value: "{x-api-key: secret}", Secret: true},
+		{Key: "legacy", Value: "true"},
+	}
+	if !reflect.DeepEqual(settings, want) {
+		t.Errorf("ListValues() = %+v, want %+v", settings, want)
+	}
+}
+
+func TestWriteFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string // empty when the file is written
+	}{
+		{name: "valid", content: "commit:\n  dco: true\n"},
+		{name: "invalid YAML", content: "commit: [dco\n", wantErr: "invalid YAML"},
+		{name: "not a mapping", content: "- commit\n", wantErr: "must be a YAML mapping"},
+		{name: "wrong type", content: "commit:\n  scopes: api\n", wantErr: "commit.scopes must be a list"},
+		{name: "invalid duration", content: "hooks:\n  timeout: soon\n", wantErr: "hooks.timeout"},
+		{name: "invalid configuration", content: "ai:\n  privacy: secret\n", wantErr: "ai.privacy"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeFixture(t, editFixture)
+			err := WriteFile(path, []byte(tt.content))
+			content, _ := os.ReadFile(path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("WriteFile() error = %v, want %q", err, tt.wantErr)
+				}
+				if string(content) != editFixture {
+					t.Errorf("config file modified by an invalid write:\n%s", content)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("WriteFile() error = %v", err)
+			}
+			if string(content) != tt.content {
+				t.Errorf("config file = %q, want %q", content, tt.content)
+			}
+			// No temporary file is left behind
+			if entries, _ := os.ReadDir(filepath.Dir(path)); len(entries) != 1 {
+				t.Errorf("config directory has %d entries, want 1", len(entries))
+			}
+		})
+	}
+}
+
+func TestUnknownKeys(t *testing.T) {
+	unknown, err := UnknownKeys([]byte(editFixture + "commit_scopes: [api]\nai_privacy: full_diff\n"))
+	if err != nil {
+		t.Fatalf("UnknownKeys() error = %v", err)
+	}
+	if want := []string{"commit_scopes", "ai_privacy"}; !reflect.DeepEqual(unknown, want) {
+		t.Errorf("UnknownKeys() = %v, want %v", unknown, want)
+	}
+}
+
+func TestCheckFile(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name        string
+		content     string
+		wantUnknown []string
+		wantErr     string
+	}{
+		{name: "valid", content: editFixture},
+		{name: "unknown keys", content: "commit:\n  dco: true\n  scope: [api]\n", wantUnknown: []string{"commit.scope"}},
+		{name: "invalid value", content: "commit:\n  dco: maybe\n", wantErr: `commit.dco: invalid value "maybe"`},
+		{name: "not a mapping", content: "- commit\n", wantErr: "must be a YAML mapping"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, strings.ReplaceAll(tt.name, " ", "-")+".yaml")
+			if err := os.WriteFile(path, []byte(tt.content), 0600); err != nil {
+				t.Fatal(err)
+			}
+			unknown, err := CheckFile(path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("CheckFile() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CheckFile() error = %v", err)
+			}
+			if !reflect.DeepEqual(unknown, tt.wantUnknown) {
+				t.Errorf("CheckFile() unknown = %v, want %v", unknown, tt.wantUnknown)
+			}
+		})
+	}
+
+	if unknown, err := CheckFile(filepath.Join(dir, "missing.yaml")); err != nil || unknown != nil {
+		t.Errorf("CheckFile(missing) = %v, %v, want nothing", unknown, err)
+	}
+}
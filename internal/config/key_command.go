@@ -0,0 +1,55 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// keyCommandTimeout bounds an api_key_command, which may wait for the user to unlock a
+// password manager
+const keyCommandTimeout = time.Minute
+
+var (
+	keyCommandMu    sync.Mutex
+	keyCommandCache = make(map[string]string) // command -> key, for the lifetime of the process
+)
+
+// runKeyCommand returns the API key printed by command (an api_key_command such as
+// "op read op://vault/openai/key"), running it once per process
+func runKeyCommand(command string) (string, error) {
+	keyCommandMu.Lock()
+	defer keyCommandMu.Unlock()
+	if key, ok := keyCommandCache[command]; ok {
+		return key, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), keyCommandTimeout)
+	defer cancel()
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return "", fmt.Errorf("command %q timed out after %s", command, keyCommandTimeout)
+		}
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return "", fmt.Errorf("command %q failed: %w: %s", command, err, message)
+		}
+		return "", fmt.Errorf("command %q failed: %w", command, err)
+	}
+
+	// Password managers print the secret on one line
+	key := strings.TrimSpace(stdout.String())
+	if key == "" {
+		return "", fmt.Errorf("command %q printed no key", command)
+	}
+	keyCommandCache[command] = key
+	return key, nil
+}
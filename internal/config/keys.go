@@ -0,0 +1,315 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// KeyKind is the type of the value of a configuration key
+type KeyKind string
+
+// Key kinds
+const (
+	KindString   KeyKind = "string"
+	KindBool     KeyKind = "bool"
+	KindInt      KeyKind = "int"
+	KindNumber   KeyKind = "number"
+	KindDuration KeyKind = "duration"
+	KindList     KeyKind = "list"
+	KindMap      KeyKind = "map"
+)
+
+// Key is a configuration key, as written in the config file. In the names of provider
+// keys, "*" stands for the provider name.
+type Key struct {
+	Name   string
+	Kind   KeyKind
+	Secret bool // API keys, tokens and passwords
+}
+
+// Keys are the configuration keys, in the order of the README
+var Keys = []Key{
+	{Name: "ai.default_provider", Kind: KindString},
+	{Name: "ai.providers.*.api_key", Kind: KindString, Secret: true},
+	{Name: "ai.providers.*.api_key_command", Kind: KindString},
+	{Name: "ai.providers.*.accounts.*.api_key", Kind: KindString, Secret: true},
+	{Name: "ai.providers.*.accounts.*.api_key_command", Kind: KindString},
+	{Name: "ai.providers.*.model", Kind: KindString},
+	{Name: "ai.providers.*.endpoint", Kind: KindString},
+	{Name: "ai.providers.*.timeout", Kind: KindDuration},
+	{Name: "ai.providers.*.max_concurrent", Kind: KindInt},
+	{Name: "ai.providers.*.requests_per_minute", Kind: KindInt},
+	{Name: "ai.providers.*.num_ctx", Kind: KindInt},
+	{Name: "ai.providers.*.keep_alive", Kind: KindString},
+	{Name: "ai.providers.*.context_budget", Kind: KindInt},
+	{Name: "ai.providers.*.diff_context", Kind: KindInt},
+	{Name: "ai.providers.*.max_diff_size", Kind: KindInt},
+	{Name: "ai.providers.*.tokenizer", Kind: KindString},
+	{Name: "ai.providers.*.circuit_threshold", Kind: KindInt},
+	{Name: "ai.providers.*.circuit_cooldown", Kind: KindDuration},
+	{Name: "ai.providers.*.retry_attempts", Kind: KindInt},
+	{Name: "ai.providers.*.retry_backoff", Kind: KindDuration},
+	{Name: "ai.providers.*.retry_max_backoff", Kind: KindDuration},
+	{Name: "ai.providers.*.retry_jitter", Kind: KindNumber},
+	{Name: "ai.account", Kind: KindString},
+	{Name: "ai.account_rules", Kind: KindList},
+	{Name: "ai.post_processors", Kind: KindList},
+	{Name: "ai.session_context", Kind: KindBool},
+	{Name: "ai.session_window", Kind: KindDuration},
+	{Name: "ai.privacy", Kind: KindString},
+	{Name: "ai.context.budget", Kind: KindInt},
+	{Name: "ai.context.order", Kind: KindList},
+	{Name: "ai.context.priority", Kind: KindList},
+	{Name: "ai.context.summary_first", Kind: KindString},
+	{Name: "ai.context.diff_context", Kind: KindInt},
+	{Name: "ai.context.max_diff_size", Kind: KindInt},
+	{Name: "commit.signoff_identity", Kind: KindString},
+	{Name: "commit.dco", Kind: KindBool},
+	{Name: "commit.scopes", Kind: KindList},
+	{Name: "commit.stats_footer", Kind: KindBool},
+	{Name: "commit.stats_footer_template", Kind: KindString},
+	{Name: "commit.ai_trailer", Kind: KindBool},
+	{Name: "commit.ai_trailer_key", Kind: KindString},
+	{Name: "commit.branch_tickets", Kind: KindBool},
+	{Name: "commit.ticket_patterns", Kind: KindList},
+	{Name: "commit.header_format", Kind: KindString},
+	{Name: "commit.branch_template", Kind: KindString},
+	{Name: "commit.check_generated", Kind: KindBool},
+	{Name: "commit.workspace_summary", Kind: KindBool},
+	{Name: "commit.upstream_check", Kind: KindString},
+	{Name: "commit.upstream_fetch", Kind: KindBool},
+	{Name: "email.smtp_server", Kind: KindString},
+	{Name: "email.smtp_port", Kind: KindInt},
+	{Name: "email.smtp_user", Kind: KindString},
+	{Name: "email.smtp_password", Kind: KindString, Secret: true},
+	{Name: "email.smtp_encryption", Kind: KindString},
+	{Name: "email.from", Kind: KindString},
+	{Name: "email.to", Kind: KindList},
+	{Name: "email.cc", Kind: KindList},
+	{Name: "push.compare_url", Kind: KindBool},
+	{Name: "issues.verify", Kind: KindBool},
+	{Name: "issues.github_token", Kind: KindString, Secret: true},
+	{Name: "issues.gitlab_token", Kind: KindString, Secret: true},
+	{Name: "issues.jira.url", Kind: KindString},
+	{Name: "issues.jira.user", Kind: KindString},
+	{Name: "issues.jira.token", Kind: KindString, Secret: true},
+	{Name: "issues.jira.projects", Kind: KindList},
+	{Name: "output.separate_streams", Kind: KindBool},
+	{Name: "report.repositories", Kind: KindList},
+	{Name: "history.enabled", Kind: KindBool},
+	{Name: "dates.format", Kind: KindString},
+	{Name: "dates.timezone", Kind: KindString},
+	{Name: "dates.locale", Kind: KindString},
+	{Name: "hooks.pre_generate", Kind: KindList},
+	{Name: "hooks.post_generate", Kind: KindList},
+	{Name: "hooks.pre_commit", Kind: KindList},
+	{Name: "hooks.post_commit", Kind: KindList},
+	{Name: "hooks.timeout", Kind: KindDuration},
+	{Name: "timer.tracker", Kind: KindString},
+	{Name: "timer.after_commit", Kind: KindString},
+	{Name: "timer.toggl_token", Kind: KindString, Secret: true},
+	{Name: "release.tag_prefix", Kind: KindString},
+	{Name: "update.check", Kind: KindBool},
+	{Name: "update.interval", Kind: KindDuration},
+	{Name: "security.check_permissions", Kind: KindBool},
+	{Name: "security.synced_dirs", Kind: KindList},
+	{Name: "tracing.endpoint", Kind: KindString},
+	{Name: "tracing.headers", Kind: KindMap, Secret: true},
+}
+
+// LookupKey returns the key named name (e.g. ai.providers.openai.model, whose Name is
+// then the provider key itself), or an error suggesting the closest key
+func LookupKey(name string) (Key, error) {
+	for _, key := range Keys {
+		if keyMatches(key.Name, name) {
+			key.Name = name
+			return key, nil
+		}
+	}
+	if isSection(name) {
+		return Key{}, fmt.Errorf("%s is a section, not a key: name one of its keys", name)
+	}
+	if suggestion := closestKey(name); suggestion != "" {
+		return Key{}, fmt.Errorf("unknown key %q, did you mean %s?", name, suggestion)
+	}
+	return Key{}, fmt.Errorf("unknown key %q (see gitcomm config list --keys)", name)
+}
+
+// keyMatches reports whether name is the key pattern, "*" matching any provider name
+func keyMatches(pattern, name string) bool {
+	patternSegments, segments := strings.Split(pattern, "."), strings.Split(name, ".")
+	if len(patternSegments) != len(segments) {
+		return false
+	}
+	for i, segment := range patternSegments {
+		if segments[i] == "" || (segment != "*" && segment != segments[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// isSection reports whether name is a parent of some keys, such as ai or ai.providers.openai
+func isSection(name string) bool {
+	segments := strings.Split(name, ".")
+	for _, key := range Keys {
+		patternSegments := strings.Split(key.Name, ".")
+		if len(patternSegments) > len(segments) && keyMatches(strings.Join(patternSegments[:len(segments)], "."), name) {
+			return true
+		}
+	}
+	return false
+}
+
+// closestKey returns the key that name most likely misspells: the key with the same last
+// segment (dco for commit.dco), else the nearest by edit distance. Provider keys are
+// suggested with the provider name of name.
+func closestKey(name string) string {
+	segments := strings.Split(name, ".")
+	candidates := make([]string, len(Keys))
+	for i, key := range Keys {
+		candidates[i] = key.Name
+		if len(segments) > 2 && strings.HasPrefix(key.Name, "ai.providers.*.") {
+			candidates[i] = strings.Replace(key.Name, "*", segments[2], 1)
+		}
+	}
+
+	last := segments[len(segments)-1]
+	for _, candidate := range candidates {
+		if strings.HasSuffix(candidate, "."+last) {
+			return candidate
+		}
+	}
+
+	best, bestDistance := "", 4
+	for _, candidate := range candidates {
+		if distance := editDistance(name, candidate); distance < bestDistance {
+			best, bestDistance = candidate, distance
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current := make([]int, len(b)+1)
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous = current
+	}
+	return previous[len(b)]
+}
+
+// ParseValue converts a value given on the command line to the YAML node of the key:
+// true/false for booleans, comma-separated or [a, b] lists, {name: value} maps
+func (k Key) ParseValue(value string) (*yaml.Node, error) {
+	switch k.Kind {
+	case KindList:
+		if strings.HasPrefix(strings.TrimSpace(value), "[") {
+			var node yaml.Node
+			if err := yaml.Unmarshal([]byte(value), &node); err != nil || node.Content[0].Kind != yaml.SequenceNode {
+				return nil, fmt.Errorf("%s: invalid list %q", k.Name, value)
+			}
+			return node.Content[0], nil
+		}
+		node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for item := range strings.SplitSeq(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: item})
+			}
+		}
+		return node, nil
+	case KindMap:
+		var node yaml.Node
+		if err := yaml.Unmarshal([]byte(value), &node); err != nil || node.Kind == 0 || node.Content[0].Kind != yaml.MappingNode {
+			return nil, fmt.Errorf("%s: invalid map %q, want {name: value, ...}", k.Name, value)
+		}
+		return node.Content[0], nil
+	}
+
+	node := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+	if isPlaceholder(value) {
+		// Environment variable placeholders are substituted when loading
+		return node, nil
+	}
+	if err := k.checkScalar(value); err != nil {
+		return nil, err
+	}
+	switch k.Kind {
+	case KindBool:
+		enabled, _ := strconv.ParseBool(value)
+		node.Tag, node.Value = "!!bool", strconv.FormatBool(enabled)
+	case KindInt:
+		node.Tag = "!!int"
+	case KindNumber:
+		node.Tag = "!!float"
+	}
+	return node, nil
+}
+
+// CheckValue checks the YAML node of the key read from the config file
+func (k Key) CheckValue(node *yaml.Node) error {
+	switch k.Kind {
+	case KindList:
+		if node.Kind != yaml.SequenceNode {
+			return fmt.Errorf("%s must be a list", k.Name)
+		}
+		return nil
+	case KindMap:
+		if node.Kind != yaml.MappingNode {
+			return fmt.Errorf("%s must be a map", k.Name)
+		}
+		return nil
+	}
+	if node.Kind != yaml.ScalarNode {
+		return fmt.Errorf("%s must be a %s", k.Name, k.Kind)
+	}
+	if isPlaceholder(node.Value) {
+		return nil
+	}
+	return k.checkScalar(node.Value)
+}
+
+// checkScalar checks a value of a boolean, integer, number or duration key
+func (k Key) checkScalar(value string) error {
+	var err error
+	want := ""
+	switch k.Kind {
+	case KindBool:
+		_, err = strconv.ParseBool(value)
+		want = "true or false"
+	case KindInt:
+		_, err = strconv.Atoi(value)
+		want = "a whole number"
+	case KindNumber:
+		_, err = strconv.ParseFloat(value, 64)
+		want = "a number"
+	case KindDuration:
+		_, err = time.ParseDuration(value)
+		want = "a duration such as 30s or 2m"
+	}
+	if err != nil {
+		return fmt.Errorf("%s: invalid value %q, want %s", k.Name, value, want)
+	}
+	return nil
+}
+
+// isPlaceholder reports whether value is only a ${ENV_VAR} placeholder
+func isPlaceholder(value string) bool {
+	return strings.HasPrefix(value, "${") && strings.HasSuffix(value, "}") && strings.Count(value, "${") == 1
+}
@@ -0,0 +1,96 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLookupKey(t *testing.T) {
+	tests := []struct {
+		name       string
+		key        string
+		wantKind   KeyKind
+		wantSecret bool
+		wantErr    string // empty when the key exists
+	}{
+		{name: "key", key: "commit.dco", wantKind: KindBool},
+		{name: "secret", key: "ai.providers.openai.api_key", wantKind: KindString, wantSecret: true},
+		{name: "tracing headers", key: "tracing.headers", wantKind: KindMap, wantSecret: true},
+		{name: "provider key", key: "ai.providers.openai.timeout", wantKind: KindDuration},
+		{name: "section", key: "ai.providers.openai", wantErr: "is a section"},
+		{name: "misspelled key", key: "commit.dcoo", wantErr: "did you mean commit.dco?"},
+		{name: "missing section", key: "dco", wantErr: "did you mean commit.dco?"},
+		{name: "misspelled provider key", key: "ai.providers.ollama.modle", wantErr: "did you mean ai.providers.ollama.model?"},
+		{name: "unknown key", key: "something.else.entirely", wantErr: "config list --keys"},
+		{name: "empty provider name", key: "ai.providers..model", wantErr: "unknown key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := LookupKey(tt.key)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("LookupKey(%q) error = %v, want %q", tt.key, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LookupKey(%q) error = %v", tt.key, err)
+			}
+			if key.Name != tt.key || key.Kind != tt.wantKind || key.Secret != tt.wantSecret {
+				t.Errorf("LookupKey(%q) = %+v, want kind %s, secret %t", tt.key, key, tt.wantKind, tt.wantSecret)
+			}
+		})
+	}
+}
+
+func TestKey_ParseValue(t *testing.T) {
+	tests := []struct {
+		name  string
+		key   string
+		value string
+		want  string // the YAML written, empty when the value is invalid
+	}{
+		{name: "bool", key: "commit.dco", value: "true", want: "true"},
+		{name: "bool is normalized", key: "commit.dco", value: "1", want: "true"},
+		{name: "invalid bool", key: "commit.dco", value: "yes"},
+		{name: "int", key: "email.smtp_port", value: "587", want: "587"},
+		{name: "invalid int", key: "email.smtp_port", value: "smtp"},
+		{name: "number", key: "ai.providers.openai.retry_jitter", value: "0.5", want: "0.5"},
+		{name: "duration", key: "hooks.timeout", value: "2m", want: "2m"},
+		{name: "invalid duration", key: "hooks.timeout", value: "2 minutes"},
+		{name: "placeholder", key: "email.smtp_port", value: "${SMTP_PORT}", want: "${SMTP_PORT}"},
+		{name: "string that looks like a bool", key: "release.tag_prefix", value: "true", want: `"true"`},
+		{name: "comma-separated list", key: "commit.scopes", value: "api, cli", want: "- api\n- cli"},
+		{name: "flow list", key: "commit.scopes", value: "[api, cli]", want: "[api, cli]"},
+		{name: "invalid list", key: "commit.scopes", value: "[api"},
+		{name: "map", key: "tracing.headers", value: "{x-api-key: secret}", want: "{x-api-key: secret}"},
+		{name: "invalid map", key: "tracing.headers", value: "x-api-key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := LookupKey(tt.key)
+			if err != nil {
+				t.Fatalf("LookupKey(%q) error = %v", tt.key, err)
+			}
+			node, err := key.ParseValue(tt.value)
+			if tt.want == "" {
+				if err == nil {
+					t.Fatalf("ParseValue(%q) = %v, want an error", tt.value, node)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseValue(%q) error = %v", tt.value, err)
+			}
+			out, err := marshalNode(node)
+			if err != nil {
+				t.Fatalf("marshalNode() error = %v", err)
+			}
+			if got := strings.TrimSuffix(string(out), "\n"); got != tt.want {
+				t.Errorf("ParseValue(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
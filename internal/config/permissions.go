@@ -0,0 +1,121 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// DefaultSyncedDirPatterns match the folders of common file sync clients, whose content is
+// copied to a cloud service (and often to other devices)
+var DefaultSyncedDirPatterns = []string{
+	"Dropbox",
+	"Dropbox (*)",
+	"OneDrive",
+	"OneDrive - *",
+	"Google Drive",
+	"GoogleDrive*",
+	"iCloud Drive",
+	"Mobile Documents",
+	"CloudStorage",
+	"Nextcloud",
+	"ownCloud",
+	"pCloudDrive",
+	"Box",
+	"Box Sync",
+	"MEGA",
+}
+
+// SecurityConfig represents the checks of the config file holding API keys and tokens
+type SecurityConfig struct {
+	// CheckPermissions warns when the config file is readable by other users or synced to
+	// the cloud (default: true)
+	CheckPermissions bool
+	// SyncedDirs are glob patterns matched against each directory of the config file path
+	// (or the whole directory path) to detect cloud-synced folders (default: DefaultSyncedDirPatterns)
+	SyncedDirs []string
+}
+
+// FileExposure describes how the config file may leak its secrets
+type FileExposure struct {
+	// Path is the config file path
+	Path string
+	// Mode is the file permission bits
+	Mode os.FileMode
+	// OthersCanRead is true when the group or other users can read the file
+	OthersCanRead bool
+	// SyncedDir is the cloud-synced directory containing the file ("" if none)
+	SyncedDir string
+}
+
+// Exposed reports whether the file is readable by others or synced to the cloud
+func (e *FileExposure) Exposed() bool {
+	return e.OthersCanRead || e.SyncedDir != ""
+}
+
+// Warnings describes each exposure in one line
+func (e *FileExposure) Warnings() []string {
+	var warnings []string
+	if e.OthersCanRead {
+		warnings = append(warnings, fmt.Sprintf("%s contains API keys and is readable by other users (mode %04o)", e.Path, e.Mode.Perm()))
+	}
+	if e.SyncedDir != "" {
+		warnings = append(warnings, fmt.Sprintf("%s contains API keys and is inside the cloud-synced folder %s: "+
+			"move it out (--config) or use ${ENV_VAR} placeholders for the keys", e.Path, e.SyncedDir))
+	}
+	return warnings
+}
+
+// CheckFileExposure checks the permissions and location of the config file at path.
+// syncedDirs are the patterns of cloud-synced folders (DefaultSyncedDirPatterns if nil).
+func CheckFileExposure(path string, syncedDirs []string) (*FileExposure, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve config path: %w", err)
+	}
+	info, err := os.Stat(absPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check config file: %w", err)
+	}
+
+	exposure := &FileExposure{Path: absPath, Mode: info.Mode().Perm()}
+	// Windows permissions are ACLs, not reflected in the mode bits
+	if runtime.GOOS != "windows" {
+		exposure.OthersCanRead = info.Mode().Perm()&0044 != 0
+	}
+	if syncedDirs == nil {
+		syncedDirs = DefaultSyncedDirPatterns
+	}
+	exposure.SyncedDir = findSyncedDir(filepath.Dir(absPath), syncedDirs)
+	return exposure, nil
+}
+
+// RestrictPermissions makes the config file at path readable and writable by its owner only
+func RestrictPermissions(path string) error {
+	if err := os.Chmod(path, 0600); err != nil {
+		return fmt.Errorf("failed to restrict config file permissions: %w", err)
+	}
+	return nil
+}
+
+// findSyncedDir returns the closest ancestor of dir (or dir itself) whose name or full path
+// matches one of patterns, or "" if none does
+func findSyncedDir(dir string, patterns []string) string {
+	for current := dir; ; current = filepath.Dir(current) {
+		name := filepath.Base(current)
+		for _, pattern := range patterns {
+			target := name
+			if strings.ContainsRune(pattern, filepath.Separator) {
+				target = current
+			}
+			if matched, _ := filepath.Match(pattern, target); matched {
+				return current
+			}
+		}
+		if parent := filepath.Dir(current); parent == current {
+			return ""
+		}
+	}
+}
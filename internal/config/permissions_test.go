@@ -0,0 +1,91 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestCheckFileExposure(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("permission bits are not meaningful on Windows")
+	}
+
+	tests := []struct {
+		name          string
+		dir           string
+		mode          os.FileMode
+		patterns      []string
+		wantReadable  bool
+		wantSyncedDir string
+	}{
+		{"private", ".gitcomm", 0600, nil, false, ""},
+		{"world-readable", ".gitcomm", 0644, nil, true, ""},
+		{"group-readable", ".gitcomm", 0640, nil, true, ""},
+		{"in Dropbox", "Dropbox/config", 0600, nil, false, "Dropbox"},
+		{"in OneDrive for Business", "OneDrive - Contoso/gitcomm", 0600, nil, false, "OneDrive - Contoso"},
+		{"custom pattern", "sync/gitcomm", 0600, []string{"sync"}, false, "sync"},
+		{"custom patterns replace defaults", "Dropbox/gitcomm", 0600, []string{"sync"}, false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := t.TempDir()
+			dir := filepath.Join(home, tt.dir)
+			if err := os.MkdirAll(dir, 0700); err != nil {
+				t.Fatalf("Failed to create dir: %v", err)
+			}
+			path := filepath.Join(dir, "config.yaml")
+			if err := os.WriteFile(path, []byte("ai: {}\n"), tt.mode); err != nil {
+				t.Fatalf("Failed to write config: %v", err)
+			}
+			if err := os.Chmod(path, tt.mode); err != nil {
+				t.Fatalf("Failed to chmod config: %v", err)
+			}
+
+			exposure, err := CheckFileExposure(path, tt.patterns)
+			if err != nil {
+				t.Fatalf("CheckFileExposure() error = %v", err)
+			}
+			if exposure.OthersCanRead != tt.wantReadable {
+				t.Errorf("OthersCanRead = %v, want %v", exposure.OthersCanRead, tt.wantReadable)
+			}
+			wantSyncedDir := ""
+			if tt.wantSyncedDir != "" {
+				wantSyncedDir = filepath.Join(home, tt.wantSyncedDir)
+			}
+			if exposure.SyncedDir != wantSyncedDir {
+				t.Errorf("SyncedDir = %q, want %q", exposure.SyncedDir, wantSyncedDir)
+			}
+			if got := len(exposure.Warnings()); got == 0 && exposure.Exposed() || got > 0 && !exposure.Exposed() {
+				t.Errorf("Warnings() = %v with Exposed() = %v", exposure.Warnings(), exposure.Exposed())
+			}
+		})
+	}
+}
+
+func TestRestrictPermissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("permission bits are not meaningful on Windows")
+	}
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("ai: {}\n"), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	if err := os.Chmod(path, 0644); err != nil {
+		t.Fatalf("Failed to chmod config: %v", err)
+	}
+
+	if err := RestrictPermissions(path); err != nil {
+		t.Fatalf("RestrictPermissions() error = %v", err)
+	}
+	exposure, err := CheckFileExposure(path, []string{})
+	if err != nil {
+		t.Fatalf("CheckFileExposure() error = %v", err)
+	}
+	if exposure.Exposed() {
+		t.Errorf("config file still exposed after RestrictPermissions(): %v", exposure.Warnings())
+	}
+}
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/golgoth31/gitcomm/pkg/git/gitdir"
+)
+
+// ReportRepositories returns the directories of report.repositories, with "~" and glob
+// patterns expanded, in order and without duplicates; nil when none is configured.
+// Directories matched by a pattern are kept only when they hold a git repository.
+func (c *Config) ReportRepositories() ([]string, error) {
+	var dirs []string
+	seen := map[string]bool{}
+	for _, entry := range c.Report.Repositories {
+		path, err := expandHome(strings.TrimSpace(entry))
+		if err != nil {
+			return nil, fmt.Errorf("report.repositories: %w", err)
+		}
+		if path == "" {
+			continue
+		}
+
+		matches := []string{path}
+		if isGlob(path) {
+			if matches, err = filepath.Glob(path); err != nil {
+				return nil, fmt.Errorf("report.repositories: %q: %w", entry, err)
+			}
+			matches = filterRepositories(matches)
+		}
+		for _, dir := range matches {
+			dir = filepath.Clean(dir)
+			if !seen[dir] {
+				seen[dir] = true
+				dirs = append(dirs, dir)
+			}
+		}
+	}
+	return dirs, nil
+}
+
+// isGlob reports whether path holds glob metacharacters
+func isGlob(path string) bool {
+	return strings.ContainsAny(path, "*?[")
+}
+
+// filterRepositories keeps the directories holding a .git directory or file (worktrees),
+// and the bare repositories
+func filterRepositories(dirs []string) []string {
+	var repositories []string
+	for _, dir := range dirs {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil || gitdir.IsGitDir(dir) {
+			repositories = append(repositories, dir)
+		}
+	}
+	return repositories
+}
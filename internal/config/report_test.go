@@ -0,0 +1,63 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestReportRepositories(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"work/api/.git", "work/web/.git", "work/notes", "src/gitcomm/.git"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatalf("failed to create %s: %v", dir, err)
+		}
+	}
+	// a worktree has a .git file
+	if err := os.MkdirAll(filepath.Join(root, "work", "tree"), 0755); err != nil {
+		t.Fatalf("failed to create worktree: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "work", "tree", ".git"), []byte("gitdir: ../api/.git\n"), 0644); err != nil {
+		t.Fatalf("failed to write .git file: %v", err)
+	}
+	t.Setenv("HOME", root)
+
+	tests := []struct {
+		name         string
+		repositories []string
+		want         []string
+		wantErr      bool
+	}{
+		{name: "none"},
+		{
+			name:         "paths and patterns",
+			repositories: []string{"~/src/gitcomm", filepath.Join(root, "work", "*")},
+			want: []string{
+				filepath.Join(root, "src", "gitcomm"),
+				filepath.Join(root, "work", "api"),
+				filepath.Join(root, "work", "tree"),
+				filepath.Join(root, "work", "web"),
+			},
+		},
+		{
+			name:         "duplicates and explicit non-repositories kept once",
+			repositories: []string{"~/work/notes", "~/work/api/", "~/work/a*", ""},
+			want:         []string{filepath.Join(root, "work", "notes"), filepath.Join(root, "work", "api")},
+		},
+		{name: "bad pattern", repositories: []string{"~/work/[a"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{Report: ReportConfig{Repositories: tt.repositories}}
+			got, err := cfg.ReportRepositories()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ReportRepositories() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReportRepositories() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
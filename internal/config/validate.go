@@ -0,0 +1,173 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"text/template"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/timer"
+	"github.com/golgoth31/gitcomm/pkg/ai/postprocess"
+	"github.com/golgoth31/gitcomm/pkg/ai/prompt"
+)
+
+// Validate checks the configuration for errors that would break AI generation or commit creation.
+// All problems are reported at once.
+func (c *Config) Validate() error {
+	var errs []error
+
+	if c.AI.DefaultProvider != "" {
+		if _, ok := c.AI.Providers[c.AI.DefaultProvider]; !ok {
+			errs = append(errs, fmt.Errorf("ai.default_provider %q is not configured under ai.providers", c.AI.DefaultProvider))
+		}
+	}
+
+	for name, provider := range c.AI.Providers {
+		if provider.APIKey != "" && provider.APIKeyCommand != "" {
+			errs = append(errs, fmt.Errorf("ai.providers.%s: set api_key or api_key_command, not both", name))
+		}
+		if provider.Timeout <= 0 {
+			errs = append(errs, fmt.Errorf("ai.providers.%s.timeout must be positive", name))
+		}
+		if provider.MaxConcurrent < 0 {
+			errs = append(errs, fmt.Errorf("ai.providers.%s.max_concurrent must be positive", name))
+		}
+		if provider.RequestsPerMinute < 0 {
+			errs = append(errs, fmt.Errorf("ai.providers.%s.requests_per_minute must be positive", name))
+		}
+		if provider.CircuitThreshold < 0 {
+			errs = append(errs, fmt.Errorf("ai.providers.%s.circuit_threshold must be positive", name))
+		}
+		if provider.CircuitCooldown < 0 {
+			errs = append(errs, fmt.Errorf("ai.providers.%s.circuit_cooldown must be positive", name))
+		}
+		if provider.RetryAttempts < 0 {
+			errs = append(errs, fmt.Errorf("ai.providers.%s.retry_attempts must be positive", name))
+		}
+		if provider.RetryBackoff < 0 || provider.RetryMaxBackoff < 0 {
+			errs = append(errs, fmt.Errorf("ai.providers.%s.retry_backoff and retry_max_backoff must be positive", name))
+		}
+		if provider.RetryJitter < 0 || provider.RetryJitter > 1 {
+			errs = append(errs, fmt.Errorf("ai.providers.%s.retry_jitter must be between 0 and 1", name))
+		}
+		if provider.NumCtx < 0 {
+			errs = append(errs, fmt.Errorf("ai.providers.%s.num_ctx must be positive", name))
+		}
+		if provider.Prompt.Budget < 0 {
+			errs = append(errs, fmt.Errorf("ai.providers.%s.context_budget must be positive", name))
+		}
+		if provider.Prompt.Diff.Context < 0 || provider.Prompt.Diff.MaxSize < 0 {
+			errs = append(errs, fmt.Errorf("ai.providers.%s.diff_context and max_diff_size must be positive", name))
+		}
+	}
+
+	errs = append(errs, c.validateAccounts()...)
+
+	if _, err := postprocess.NewPipeline(c.AI.PostProcessors); err != nil {
+		errs = append(errs, fmt.Errorf("ai.post_processors: %w", err))
+	}
+
+	if _, err := model.ParsePrivacyLevel(c.AI.Privacy); err != nil {
+		errs = append(errs, fmt.Errorf("ai.privacy: %w", err))
+	}
+
+	if c.AI.Context.Budget < 0 {
+		errs = append(errs, fmt.Errorf("ai.context.budget must be positive"))
+	}
+	if c.AI.Context.Diff.Context < 0 || c.AI.Context.Diff.MaxSize < 0 {
+		errs = append(errs, fmt.Errorf("ai.context.diff_context and ai.context.max_diff_size must be positive"))
+	}
+	if err := prompt.ValidateSections(c.AI.Context.Order); err != nil {
+		errs = append(errs, fmt.Errorf("ai.context.order: %w", err))
+	}
+	if err := prompt.ValidateSections(c.AI.Context.Priority); err != nil {
+		errs = append(errs, fmt.Errorf("ai.context.priority: %w", err))
+	}
+	if err := prompt.ValidateSummaryFirst(c.AI.Context.SummaryFirst); err != nil {
+		errs = append(errs, fmt.Errorf("ai.context.summary_first: %w", err))
+	}
+
+	if c.AI.SessionWindow < 0 {
+		errs = append(errs, fmt.Errorf("ai.session_window must be positive"))
+	}
+
+	if c.Commit.StatsFooter {
+		if _, err := template.New("stats_footer").Parse(c.Commit.StatsFooterTemplate); err != nil {
+			errs = append(errs, fmt.Errorf("commit.stats_footer_template: %w", err))
+		}
+	}
+
+	if c.Commit.AITrailerKey != "" && !(model.Trailer{Key: c.Commit.AITrailerKey, Value: "gitcomm"}).Valid() {
+		errs = append(errs, fmt.Errorf("commit.ai_trailer_key %q must be letters, digits and hyphens", c.Commit.AITrailerKey))
+	}
+
+	if _, err := template.New("branch").Parse(c.Commit.BranchTemplate); err != nil {
+		errs = append(errs, fmt.Errorf("commit.branch_template: %w", err))
+	}
+
+	for _, pattern := range c.Commit.TicketPatterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			errs = append(errs, fmt.Errorf("commit.ticket_patterns: %w", err))
+		}
+	}
+
+	switch c.Commit.UpstreamCheck {
+	case "", UpstreamCheckOff, UpstreamCheckWarn, UpstreamCheckPrompt:
+	default:
+		errs = append(errs, fmt.Errorf("commit.upstream_check must be off, warn or prompt, got %q", c.Commit.UpstreamCheck))
+	}
+
+	if c.Commit.HeaderFormat != "" {
+		if _, err := model.ParseHeaderLayout(c.Commit.HeaderFormat); err != nil {
+			errs = append(errs, fmt.Errorf("commit.header_format: %w", err))
+		}
+	}
+
+	if _, err := c.DateFormatter(); err != nil {
+		errs = append(errs, fmt.Errorf("dates: %w", err))
+	}
+
+	for _, pattern := range c.Report.Repositories {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			errs = append(errs, fmt.Errorf("report.repositories: %q: %w", pattern, err))
+		}
+	}
+
+	if c.Update.Interval < 0 {
+		errs = append(errs, fmt.Errorf("update.interval must be positive"))
+	}
+
+	if c.Issues.JiraURL != "" {
+		if u, err := url.Parse(c.Issues.JiraURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, fmt.Errorf("issues.jira.url must be an http(s) URL"))
+		}
+	}
+
+	if c.Tracing.Endpoint != "" {
+		if u, err := url.Parse(c.Tracing.Endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, fmt.Errorf("tracing.endpoint must be an http(s) URL"))
+		}
+	}
+
+	if c.Timer.Tracker != "" {
+		if _, err := timer.NewTracker(timer.Config{Tracker: c.Timer.Tracker, TogglToken: c.Timer.TogglToken}); err != nil {
+			errs = append(errs, fmt.Errorf("timer.tracker: %w", err))
+		}
+	}
+	if err := timer.ValidateAction(c.Timer.AfterCommit); err != nil {
+		errs = append(errs, fmt.Errorf("timer.after_commit: %w", err))
+	}
+
+	if c.Commit.SignoffIdentity != "" {
+		if _, err := model.ParseIdentity(c.Commit.SignoffIdentity); err != nil {
+			errs = append(errs, fmt.Errorf("commit.signoff_identity: %w", err))
+		} else if c.Commit.DCO {
+			errs = append(errs, fmt.Errorf("commit.signoff_identity cannot be used with commit.dco"))
+		}
+	}
+
+	return errors.Join(errs...)
+}
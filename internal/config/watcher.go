@@ -0,0 +1,135 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/golgoth31/gitcomm/internal/utils"
+)
+
+// reloadDebounce groups the burst of events editors emit when saving a file
+const reloadDebounce = 100 * time.Millisecond
+
+// Watcher keeps a configuration up to date with its file for long-running modes (serve, watch).
+// A changed file is loaded and validated first; on failure the previous configuration is kept.
+type Watcher struct {
+	path string
+
+	mu       sync.RWMutex
+	current  *Config
+	onReload func(*Config)
+	onError  func(error)
+}
+
+// NewWatcher creates a watcher for the config file at path (default path if empty),
+// starting from an already loaded configuration
+func NewWatcher(path string, initial *Config) (*Watcher, error) {
+	resolved, err := ResolvePath(path)
+	if err != nil {
+		return nil, err
+	}
+	if initial == nil {
+		return nil, fmt.Errorf("initial configuration is required")
+	}
+
+	return &Watcher{
+		path:    resolved,
+		current: initial,
+	}, nil
+}
+
+// Config returns the current configuration (safe for concurrent use)
+func (w *Watcher) Config() *Config {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.current
+}
+
+// OnReload registers a callback invoked with the new configuration after a successful reload
+func (w *Watcher) OnReload(fn func(*Config)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.onReload = fn
+}
+
+// OnError registers a callback invoked when a changed file fails to load or validate
+func (w *Watcher) OnError(fn func(error)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.onError = fn
+}
+
+// Run watches the config file until ctx is cancelled.
+// The parent directory is watched because editors often replace files by renaming.
+func (w *Watcher) Run(ctx context.Context) error {
+	fsWatcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create config watcher: %w", err)
+	}
+	defer fsWatcher.Close()
+
+	if err := fsWatcher.Add(filepath.Dir(w.path)); err != nil {
+		return fmt.Errorf("failed to watch config directory: %w", err)
+	}
+	utils.Logger.Debug().Str("path", w.path).Msg("Watching config file for changes")
+
+	var debounce <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case event, ok := <-fsWatcher.Events:
+			if !ok {
+				return nil
+			}
+			if filepath.Clean(event.Name) != filepath.Clean(w.path) ||
+				!event.Has(fsnotify.Write) && !event.Has(fsnotify.Create) {
+				continue
+			}
+			debounce = time.After(reloadDebounce)
+
+		case err, ok := <-fsWatcher.Errors:
+			if !ok {
+				return nil
+			}
+			utils.Logger.Debug().Err(err).Msg("Config watcher error")
+
+		case <-debounce:
+			debounce = nil
+			w.reload()
+		}
+	}
+}
+
+// reload loads and validates the config file, swapping it in only if valid
+func (w *Watcher) reload() {
+	cfg, err := LoadConfig(w.path)
+	if err == nil {
+		err = cfg.Validate()
+	}
+
+	w.mu.Lock()
+	onReload, onError := w.onReload, w.onError
+	if err == nil {
+		w.current = cfg
+	}
+	w.mu.Unlock()
+
+	if err != nil {
+		utils.Logger.Warn().Err(err).Str("path", w.path).Msg("Invalid config change ignored, keeping previous configuration")
+		if onError != nil {
+			onError(err)
+		}
+		return
+	}
+
+	utils.Logger.Debug().Str("path", w.path).Msg("Config reloaded")
+	if onReload != nil {
+		onReload(cfg)
+	}
+}
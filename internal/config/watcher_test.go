@@ -0,0 +1,245 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWatcher_ReloadsValidConfigAndKeepsOldOnFailure(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfig := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(configPath, []byte(content), 0600); err != nil {
+			t.Fatalf("Failed to write config: %v", err)
+		}
+	}
+
+	writeConfig("ai:\n  default_provider: openai\n  providers:\n    openai:\n      model: gpt-4\n")
+	initial, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+
+	watcher, err := NewWatcher(configPath, initial)
+	if err != nil {
+		t.Fatalf("NewWatcher() error = %v", err)
+	}
+
+	reloaded := make(chan *Config, 1)
+	failed := make(chan error, 1)
+	watcher.OnReload(func(cfg *Config) { reloaded <- cfg })
+	watcher.OnError(func(err error) { failed <- err })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		if err := watcher.Run(ctx); err != nil {
+			t.Errorf("Run() error = %v", err)
+		}
+	}()
+	time.Sleep(100 * time.Millisecond) // Let the watcher register
+
+	// Valid change is applied
+	writeConfig("ai:\n  default_provider: openai\n  providers:\n    openai:\n      model: gpt-4o\n")
+	select {
+	case cfg := <-reloaded:
+		if cfg.AI.Providers["openai"].Model != "gpt-4o" {
+			t.Errorf("reloaded model = %q, want gpt-4o", cfg.AI.Providers["openai"].Model)
+		}
+	case err := <-failed:
+		t.Fatalf("unexpected reload error: %v", err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for reload")
+	}
+
+	// Invalid change is rejected and the previous config kept
+	writeConfig("ai:\n  default_provider: anthropic\n  providers:\n    openai:\n      model: gpt-5\n")
+	select {
+	case <-failed:
+	case cfg := <-reloaded:
+		t.Fatalf("invalid config was applied: %+v", cfg.AI)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for validation error")
+	}
+
+	if got := watcher.Config().AI.Providers["openai"].Model; got != "gpt-4o" {
+		t.Errorf("current model = %q, want previous value gpt-4o", got)
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+	}{
+		{
+			name:    "empty config",
+			content: "",
+		},
+		{
+			name:    "default provider configured",
+			content: "ai:\n  default_provider: openai\n  providers:\n    openai:\n      model: gpt-4\n",
+		},
+		{
+			name:    "default provider missing",
+			content: "ai:\n  default_provider: mistral\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid timeout",
+			content: "ai:\n  providers:\n    openai:\n      timeout: 0s\n",
+			wantErr: true,
+		},
+		{
+			name:    "negative rate limit",
+			content: "ai:\n  providers:\n    openai:\n      requests_per_minute: -1\n",
+			wantErr: true,
+		},
+		{
+			name:    "api key and api key command",
+			content: "ai:\n  providers:\n    openai:\n      api_key: sk-literal\n      api_key_command: op read op://vault/openai/key\n",
+			wantErr: true,
+		},
+		{
+			name:    "accounts selected by rules",
+			content: "ai:\n  account: personal\n  account_rules:\n    - path: ~/work\n      account: work\n  providers:\n    openai:\n      accounts:\n        work:\n          api_key: keyring:openai-work\n        personal:\n          api_key_command: pass show openai\n",
+		},
+		{
+			name:    "rule with an unknown account",
+			content: "ai:\n  account_rules:\n    - path: ~/work\n      account: wrok\n  providers:\n    openai:\n      accounts:\n        work:\n          api_key: sk-work\n",
+			wantErr: true,
+		},
+		{
+			name:    "unknown default account",
+			content: "ai:\n  account: work\n  providers:\n    openai:\n      api_key: sk-own\n",
+			wantErr: true,
+		},
+		{
+			name:    "retry jitter above 1",
+			content: "ai:\n  providers:\n    openai:\n      retry_jitter: 1.5\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid ticket pattern",
+			content: "commit:\n  ticket_patterns: ['([A-Z]+']\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid branch template",
+			content: "commit:\n  branch_template: '{{.Type'\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid header format",
+			content: "commit:\n  header_format: '[{ticket}] {subject}'\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid report repository pattern",
+			content: "report:\n  repositories: ['~/src/[a']\n",
+			wantErr: true,
+		},
+		{
+			name:    "unknown upstream check mode",
+			content: "commit:\n  upstream_check: block\n",
+			wantErr: true,
+		},
+		{
+			name:    "upstream check prompt",
+			content: "commit:\n  upstream_check: prompt\n  upstream_fetch: true\n",
+		},
+		{
+			name:    "unknown post-processor",
+			content: "ai:\n  post_processors: [make-it-better]\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid sign-off identity",
+			content: "commit:\n  signoff_identity: nobody\n",
+			wantErr: true,
+		},
+		{
+			name:    "sign-off identity with dco",
+			content: "commit:\n  signoff_identity: Jane Doe <jane@example.com>\n  dco: true\n",
+			wantErr: true,
+		},
+		{
+			name:    "unknown privacy level",
+			content: "ai:\n  privacy: secret\n",
+			wantErr: true,
+		},
+		{
+			name:    "unknown prompt section",
+			content: "ai:\n  context:\n    order: [staged_diffs, readme]\n",
+			wantErr: true,
+		},
+		{
+			name:    "unknown summary-first mode",
+			content: "ai:\n  context:\n    summary_first: sometimes\n",
+			wantErr: true,
+		},
+		{
+			name:    "negative context budget",
+			content: "ai:\n  context:\n    budget: -1\n",
+			wantErr: true,
+		},
+		{
+			name:    "unknown time zone",
+			content: "dates:\n  timezone: Europe/Atlantis\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid jira url",
+			content: "issues:\n  jira:\n    url: company.atlassian.net\n",
+			wantErr: true,
+		},
+		{
+			name:    "tracing endpoint",
+			content: "tracing:\n  endpoint: http://localhost:4318\n  headers:\n    authorization: Bearer token\n",
+		},
+		{
+			name:    "invalid tracing endpoint",
+			content: "tracing:\n  endpoint: localhost:4318\n",
+			wantErr: true,
+		},
+		{
+			name:    "timer tracker",
+			content: "timer:\n  tracker: timewarrior\n  after_commit: stop\n",
+		},
+		{
+			name:    "unknown timer tracker",
+			content: "timer:\n  tracker: harvest\n",
+			wantErr: true,
+		},
+		{
+			name:    "toggl without token",
+			content: "timer:\n  tracker: toggl\n",
+			wantErr: true,
+		},
+		{
+			name:    "unknown timer action",
+			content: "timer:\n  tracker: watson\n  after_commit: pause\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(configPath, []byte(tt.content), 0600); err != nil {
+				t.Fatalf("Failed to write config: %v", err)
+			}
+			cfg, err := LoadConfig(configPath)
+			if err != nil {
+				t.Fatalf("LoadConfig() error = %v", err)
+			}
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
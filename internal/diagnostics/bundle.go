@@ -0,0 +1,164 @@
+package diagnostics
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/golgoth31/gitcomm/internal/repository"
+	"github.com/golgoth31/gitcomm/internal/version"
+)
+
+// errorLogBundleLines is the number of error log lines included in a bundle
+const errorLogBundleLines = 50
+
+// envVars are the environment variables whose presence (never value) is reported
+var envVars = []string{
+	"OPENAI_API_KEY", "ANTHROPIC_API_KEY", "MISTRAL_API_KEY",
+	"GIT_AUTHOR_NAME", "GIT_AUTHOR_EMAIL", "GIT_COMMITTER_NAME", "GIT_COMMITTER_EMAIL",
+	"PAGER", "TERM", "NO_COLOR", "CI",
+}
+
+// File is a file of the debug bundle
+type File struct {
+	Name    string
+	Content string
+}
+
+// Collect gathers sanitized troubleshooting information: build and system details,
+// the configuration file with secrets redacted, repository stats (if gitRepo is not nil)
+// and the most recent error log lines. Sections that cannot be collected describe why.
+func Collect(ctx context.Context, configPath string, gitRepo repository.GitRepository) []File {
+	return []File{
+		{Name: "system.txt", Content: systemInfo()},
+		{Name: "config.yaml", Content: configInfo(configPath)},
+		{Name: "repository.txt", Content: repositoryInfo(ctx, gitRepo)},
+		{Name: "errors.log", Content: errorLogInfo()},
+	}
+}
+
+// systemInfo describes the gitcomm build, the OS, git and the relevant environment
+func systemInfo() string {
+	var sb strings.Builder
+	sb.WriteString(strings.TrimSpace(version.BuildDetails()))
+	sb.WriteString("\n\n")
+	fmt.Fprintf(&sb, "OS/Arch           : %s/%s\n", runtime.GOOS, runtime.GOARCH)
+
+	gitVersion := "not found"
+	if out, err := exec.Command("git", "--version").Output(); err == nil {
+		gitVersion = strings.TrimSpace(string(out))
+	}
+	fmt.Fprintf(&sb, "Git               : %s\n", gitVersion)
+
+	rtk := "not found"
+	if path, err := exec.LookPath("rtk"); err == nil {
+		rtk = path
+	}
+	fmt.Fprintf(&sb, "rtk               : %s\n", rtk)
+
+	sb.WriteString("\nEnvironment (values are not collected):\n")
+	for _, name := range envVars {
+		state := "unset"
+		if _, ok := os.LookupEnv(name); ok {
+			state = "set"
+		}
+		fmt.Fprintf(&sb, "  %-20s %s\n", name, state)
+	}
+	return sb.String()
+}
+
+// configInfo returns the redacted configuration file
+func configInfo(configPath string) string {
+	content, err := os.ReadFile(configPath)
+	if err != nil {
+		return fmt.Sprintf("# configuration not available: %v\n", err)
+	}
+	return fmt.Sprintf("# %s (secrets redacted)\n%s", configPath, Redact(string(content)))
+}
+
+// repositoryInfo describes the repository state without file names or contents
+func repositoryInfo(ctx context.Context, gitRepo repository.GitRepository) string {
+	if gitRepo == nil {
+		return "not run inside a git repository\n"
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Uses rtk          : %t\n", gitRepo.UsesRTK())
+
+	state, err := gitRepo.GetRepositoryState(ctx)
+	if err != nil {
+		fmt.Fprintf(&sb, "State             : unavailable (%s)\n", Redact(err.Error()))
+		return sb.String()
+	}
+
+	branch := state.Branch
+	if branch == "" {
+		branch = "(detached HEAD)"
+	}
+	fmt.Fprintf(&sb, "Branch            : %s\n", branch)
+	if tracking := state.TrackingStatus(); tracking != "" {
+		fmt.Fprintf(&sb, "Tracking          : %s\n", tracking)
+	}
+	fmt.Fprintf(&sb, "Staged files      : %d\n", len(state.StagedFiles))
+	fmt.Fprintf(&sb, "Unstaged files    : %d\n", len(state.UnstagedFiles))
+
+	diffSize := len(state.RawDiff)
+	for _, file := range state.StagedFiles {
+		diffSize += len(file.Diff)
+	}
+	fmt.Fprintf(&sb, "Staged diff size  : %d bytes\n", diffSize)
+	return sb.String()
+}
+
+// errorLogInfo returns the most recent redacted error log lines
+func errorLogInfo() string {
+	path, err := ErrorLogPath()
+	if err != nil {
+		return fmt.Sprintf("error log not available: %v\n", err)
+	}
+	lines, err := ReadLastLines(path, errorLogBundleLines)
+	if err != nil {
+		return fmt.Sprintf("error log not available: %v\n", err)
+	}
+	if len(lines) == 0 {
+		return "no errors recorded\n"
+	}
+	return Redact(strings.Join(lines, "\n")) + "\n"
+}
+
+// WriteTarball writes files as a gzip-compressed tar archive under a top-level directory
+func WriteTarball(w io.Writer, dir string, files []File) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	now := time.Now()
+	for _, file := range files {
+		header := &tar.Header{
+			Name:    dir + "/" + file.Name,
+			Mode:    0600,
+			Size:    int64(len(file.Content)),
+			ModTime: now,
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file.Name, err)
+		}
+		if _, err := io.WriteString(tw, file.Content); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file.Name, err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to finish archive: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("failed to finish archive: %w", err)
+	}
+	return nil
+}
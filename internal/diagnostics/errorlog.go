@@ -0,0 +1,79 @@
+// Package diagnostics collects troubleshooting information for bug reports.
+package diagnostics
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// errorLogName is the error log file name in the gitcomm home directory (~/.gitcomm)
+const errorLogName = "errors.log"
+
+// maxErrorLogLines is the number of most recent lines kept in the error log
+const maxErrorLogLines = 200
+
+// ErrorLogPath returns the path of the error log (~/.gitcomm/errors.log)
+func ErrorLogPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".gitcomm", errorLogName), nil
+}
+
+// RecordError appends a timestamped error line to the error log, keeping only the most
+// recent lines. It is best effort: failures are ignored so error reporting never fails.
+func RecordError(context string, err error) {
+	if err == nil {
+		return
+	}
+	path, pathErr := ErrorLogPath()
+	if pathErr != nil {
+		return
+	}
+	line := fmt.Sprintf("%s %s: %s", time.Now().UTC().Format(time.RFC3339), context, strings.ReplaceAll(err.Error(), "\n", " "))
+	_ = appendLine(path, line, maxErrorLogLines)
+}
+
+// appendLine appends line to the file at path, truncating it to the last maxLines lines
+func appendLine(path, line string, maxLines int) error {
+	lines, err := ReadLastLines(path, maxLines-1)
+	if err != nil {
+		return err
+	}
+	lines = append(lines, line)
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0600)
+}
+
+// ReadLastLines returns the last n lines of the file at path (none if it does not exist)
+func ReadLastLines(path string, n int) ([]string, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+		if len(lines) > n {
+			lines = lines[1:]
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return lines, nil
+}
@@ -0,0 +1,134 @@
+// Package hooks runs user scripts at the phases of the commit workflow (pre-generate,
+// post-generate, pre-commit, post-commit), passing them a JSON description on stdin.
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"time"
+)
+
+// DefaultTimeout bounds each hook script when no timeout is configured
+const DefaultTimeout = 30 * time.Second
+
+// Event is a phase of the commit workflow
+type Event string
+
+const (
+	// PreGenerate runs before the AI provider is asked for a message
+	PreGenerate Event = "pre-generate"
+	// PostGenerate runs once the AI message is received and parsed
+	PostGenerate Event = "post-generate"
+	// PreCommit runs before the commit is created; a failure aborts the commit
+	PreCommit Event = "pre-commit"
+	// PostCommit runs after the commit attempt, with its result
+	PostCommit Event = "post-commit"
+)
+
+// Blocking reports whether a failing script of the event stops the workflow.
+// Only pre- events can veto; post- events are notifications.
+func (e Event) Blocking() bool {
+	return e == PreGenerate || e == PreCommit
+}
+
+// Payload is the JSON document written on the stdin of hook scripts
+type Payload struct {
+	Event   Event   `json:"event"`
+	State   *State  `json:"state,omitempty"`
+	Message string  `json:"message,omitempty"`
+	Result  *Result `json:"result,omitempty"`
+}
+
+// State describes the changes being committed (paths and statuses, no content)
+type State struct {
+	Branch string `json:"branch,omitempty"`
+	Files  []File `json:"files"`
+}
+
+// File is a staged file
+type File struct {
+	Path   string `json:"path"`
+	Status string `json:"status"`
+}
+
+// Result is the outcome of the commit, sent to post-commit scripts
+type Result struct {
+	Success bool   `json:"success"`
+	Commit  string `json:"commit,omitempty"`
+	Branch  string `json:"branch,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Runner runs the scripts configured for each event
+type Runner struct {
+	scripts map[Event][]string
+	timeout time.Duration
+	output  io.Writer
+}
+
+// NewRunner creates a runner for scripts, by event. Scripts are shell command lines
+// run in the current directory; timeout bounds each one (default: DefaultTimeout).
+func NewRunner(scripts map[Event][]string, timeout time.Duration) *Runner {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &Runner{scripts: scripts, timeout: timeout, output: os.Stderr}
+}
+
+// Has reports whether scripts are configured for event
+func (r *Runner) Has(event Event) bool {
+	return r != nil && len(r.scripts[event]) > 0
+}
+
+// Run runs the scripts of payload.Event in order with payload on stdin. Their output
+// goes to stderr. Scripts of blocking events stop at the first failure, which is
+// returned; failures of other events are all run and returned joined.
+func (r *Runner) Run(ctx context.Context, payload Payload) error {
+	if !r.Has(payload.Event) {
+		return nil
+	}
+
+	input, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s hook input: %w", payload.Event, err)
+	}
+
+	var errs []error
+	for _, script := range r.scripts[payload.Event] {
+		if err := r.runScript(ctx, payload.Event, script, input); err != nil {
+			if payload.Event.Blocking() {
+				return err
+			}
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// runScript runs one script with input on stdin
+func (r *Runner) runScript(ctx context.Context, event Event, script string, input []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", script)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = r.output
+	cmd.Stderr = r.output
+	cmd.Env = append(os.Environ(), "GITCOMM_HOOK="+string(event))
+	// Background processes started by the script must not keep gitcomm waiting
+	cmd.WaitDelay = time.Second
+
+	if err := cmd.Run(); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("%s hook %q timed out after %s", event, script, r.timeout)
+		}
+		return fmt.Errorf("%s hook %q failed: %w", event, script, err)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunner_Run_PassesPayloadOnStdin(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "payload.json")
+	r := NewRunner(map[Event][]string{
+		PostCommit: {`cat > "` + out + `"`},
+	}, 0)
+
+	payload := Payload{
+		Event:   PostCommit,
+		State:   &State{Branch: "main", Files: []File{{Path: "README.md", Status: "modified"}}},
+		Message: "docs: describe hooks",
+		Result:  &Result{Success: true, Commit: "0123abcd"},
+	}
+	if err := r.Run(context.Background(), payload); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("hook did not write its input: %v", err)
+	}
+	var got Payload
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("hook input is not JSON: %v (%s)", err, data)
+	}
+	if got.Event != PostCommit || got.Message != payload.Message || got.Result == nil || got.Result.Commit != "0123abcd" ||
+		got.State == nil || len(got.State.Files) != 1 {
+		t.Errorf("hook input = %s", data)
+	}
+}
+
+func TestRunner_Run_Failures(t *testing.T) {
+	tests := []struct {
+		name    string
+		event   Event
+		scripts []string
+		wantErr string
+		wantOut string
+	}{
+		{"no scripts", PreCommit, nil, "", ""},
+		{"success", PreCommit, []string{`echo "$GITCOMM_HOOK"`}, "", "pre-commit\n"},
+		{"blocking event stops at first failure", PreCommit, []string{"exit 3", "echo after"}, `pre-commit hook "exit 3" failed`, ""},
+		{"notification event runs all scripts", PostGenerate, []string{"exit 3", "echo after"}, `post-generate hook "exit 3" failed`, "after\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output bytes.Buffer
+			r := NewRunner(map[Event][]string{tt.event: tt.scripts}, 0)
+			r.output = &output
+
+			err := r.Run(context.Background(), Payload{Event: tt.event})
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("Run() error = %v, want nil", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("Run() error = %v, want %q", err, tt.wantErr)
+			}
+			if output.String() != tt.wantOut {
+				t.Errorf("hook output = %q, want %q", output.String(), tt.wantOut)
+			}
+		})
+	}
+}
+
+func TestRunner_Run_Timeout(t *testing.T) {
+	r := NewRunner(map[Event][]string{PreGenerate: {"exec sleep 5"}}, 50*time.Millisecond)
+
+	err := r.Run(context.Background(), Payload{Event: PreGenerate})
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("Run() error = %v, want timeout", err)
+	}
+}
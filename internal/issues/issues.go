@@ -0,0 +1,262 @@
+// Package issues verifies issue references (#123, PROJ-123) against GitHub, GitLab and Jira.
+package issues
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/golgoth31/gitcomm/pkg/git/remote"
+)
+
+// requestTimeout bounds each issue lookup
+const requestTimeout = 5 * time.Second
+
+// ErrNotFound is returned when the referenced issue does not exist
+var ErrNotFound = errors.New("issue not found")
+
+var (
+	// forgeRefRegex matches "#123" not preceded by a word character or "/" (URL anchors)
+	forgeRefRegex = regexp.MustCompile(`(?:^|[^\w/&])#(\d+)\b`)
+
+	// jiraRefRegex matches Jira keys such as "PROJ-123"
+	jiraRefRegex = regexp.MustCompile(`\b([A-Z][A-Z0-9_]+-\d+)\b`)
+)
+
+// Kind identifies the tracker of a reference
+type Kind string
+
+// Supported trackers
+const (
+	KindForge Kind = "forge" // GitHub or GitLab issue of the origin repository
+	KindJira  Kind = "jira"
+)
+
+// Reference is an issue reference found in a commit message
+type Reference struct {
+	Kind Kind
+	// ID is the issue number ("123") or Jira key ("PROJ-123")
+	ID string
+}
+
+// String returns the reference as written in the message
+func (r Reference) String() string {
+	if r.Kind == KindForge {
+		return "#" + r.ID
+	}
+	return r.ID
+}
+
+// Issue is the state of a referenced issue
+type Issue struct {
+	Title string
+	Open  bool
+}
+
+// Config holds the tracker settings
+type Config struct {
+	// Repository is the origin repository, used for #123 references (nil disables them)
+	Repository *remote.Repository
+	// GitHubToken and GitLabToken authenticate forge lookups (optional for public repositories)
+	GitHubToken string
+	GitLabToken string
+	// JiraURL is the Jira base URL (e.g. https://company.atlassian.net); empty disables Jira lookups
+	JiraURL   string
+	JiraUser  string
+	JiraToken string
+	// JiraProjects are the projects whose keys are Jira references (none if empty)
+	JiraProjects []string
+}
+
+// ParseReferences returns the issue references in text, in order and without duplicates.
+// Jira keys are only returned for the given projects: without projects, tokens such as
+// "UTF-8" or "SHA-256" cannot be told from issue keys.
+func ParseReferences(text string, jiraProjects []string) []Reference {
+	var refs []Reference
+	seen := make(map[Reference]bool)
+	add := func(ref Reference) {
+		if !seen[ref] {
+			seen[ref] = true
+			refs = append(refs, ref)
+		}
+	}
+
+	for _, m := range forgeRefRegex.FindAllStringSubmatch(text, -1) {
+		add(Reference{Kind: KindForge, ID: m[1]})
+	}
+	for _, m := range jiraRefRegex.FindAllStringSubmatch(text, -1) {
+		project, _, _ := strings.Cut(m[1], "-")
+		if containsFold(jiraProjects, project) {
+			add(Reference{Kind: KindJira, ID: m[1]})
+		}
+	}
+	return refs
+}
+
+// Verifier looks up referenced issues
+type Verifier struct {
+	config Config
+	client *http.Client
+	// apiURL overrides the forge API base URL (tests)
+	apiURL string
+}
+
+// NewVerifier creates a verifier for the given trackers
+func NewVerifier(config Config) *Verifier {
+	return &Verifier{config: config, client: &http.Client{Timeout: requestTimeout}}
+}
+
+// Supports reports whether a tracker is configured for the reference kind
+func (v *Verifier) Supports(kind Kind) bool {
+	switch kind {
+	case KindForge:
+		return v.config.Repository != nil && v.forgeAPI() != ""
+	case KindJira:
+		return v.config.JiraURL != ""
+	}
+	return false
+}
+
+// Lookup returns the referenced issue, or ErrNotFound if it does not exist
+func (v *Verifier) Lookup(ctx context.Context, ref Reference) (*Issue, error) {
+	switch {
+	case !v.Supports(ref.Kind):
+		return nil, fmt.Errorf("no tracker configured for %s", ref)
+	case ref.Kind == KindJira:
+		return v.lookupJira(ctx, ref.ID)
+	case strings.Contains(v.config.Repository.Host, "gitlab"):
+		return v.lookupGitLab(ctx, ref.ID)
+	default:
+		return v.lookupGitHub(ctx, ref.ID)
+	}
+}
+
+// forgeAPI returns the REST API base URL of the origin repository host, or "" if unsupported
+func (v *Verifier) forgeAPI() string {
+	if v.apiURL != "" {
+		return v.apiURL
+	}
+	host := v.config.Repository.Host
+	switch {
+	case host == "github.com":
+		return "https://api.github.com"
+	case strings.Contains(host, "gitlab"):
+		return "https://" + host + "/api/v4"
+	case strings.Contains(host, "github"):
+		// GitHub Enterprise Server
+		return "https://" + host + "/api/v3"
+	}
+	return ""
+}
+
+// lookupGitHub reads a GitHub issue (pull requests share the numbering and are accepted)
+func (v *Verifier) lookupGitHub(ctx context.Context, number string) (*Issue, error) {
+	endpoint := fmt.Sprintf("%s/repos/%s/issues/%s", v.forgeAPI(), v.config.Repository.Path, number)
+	headers := map[string]string{"Accept": "application/vnd.github+json"}
+	if v.config.GitHubToken != "" {
+		headers["Authorization"] = "Bearer " + v.config.GitHubToken
+	}
+
+	var issue struct {
+		Title string `json:"title"`
+		State string `json:"state"`
+	}
+	if err := v.getJSON(ctx, endpoint, headers, &issue); err != nil {
+		return nil, err
+	}
+	return &Issue{Title: issue.Title, Open: issue.State == "open"}, nil
+}
+
+// lookupGitLab reads a GitLab issue by its project-level ID
+func (v *Verifier) lookupGitLab(ctx context.Context, iid string) (*Issue, error) {
+	endpoint := fmt.Sprintf("%s/projects/%s/issues/%s", v.forgeAPI(), url.PathEscape(v.config.Repository.Path), iid)
+	headers := map[string]string{}
+	if v.config.GitLabToken != "" {
+		headers["PRIVATE-TOKEN"] = v.config.GitLabToken
+	}
+
+	var issue struct {
+		Title string `json:"title"`
+		State string `json:"state"`
+	}
+	if err := v.getJSON(ctx, endpoint, headers, &issue); err != nil {
+		return nil, err
+	}
+	return &Issue{Title: issue.Title, Open: issue.State == "opened"}, nil
+}
+
+// lookupJira reads a Jira issue; issues in the "done" status category are closed
+func (v *Verifier) lookupJira(ctx context.Context, key string) (*Issue, error) {
+	endpoint := fmt.Sprintf("%s/rest/api/2/issue/%s?fields=summary,status", strings.TrimSuffix(v.config.JiraURL, "/"), url.PathEscape(key))
+	headers := map[string]string{"Accept": "application/json"}
+
+	var issue struct {
+		Fields struct {
+			Summary string `json:"summary"`
+			Status  struct {
+				StatusCategory struct {
+					Key string `json:"key"`
+				} `json:"statusCategory"`
+			} `json:"status"`
+		} `json:"fields"`
+	}
+	if err := v.getJSONAuth(ctx, endpoint, headers, v.config.JiraUser, v.config.JiraToken, &issue); err != nil {
+		return nil, err
+	}
+	return &Issue{Title: issue.Fields.Summary, Open: issue.Fields.Status.StatusCategory.Key != "done"}, nil
+}
+
+// getJSON performs a GET request and decodes the JSON answer; 404 yields ErrNotFound
+func (v *Verifier) getJSON(ctx context.Context, endpoint string, headers map[string]string, out any) error {
+	return v.getJSONAuth(ctx, endpoint, headers, "", "", out)
+}
+
+// getJSONAuth is getJSON with optional basic authentication
+func (v *Verifier) getJSONAuth(ctx context.Context, endpoint string, headers map[string]string, user, password string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create issue request: %w", err)
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	if user != "" || password != "" {
+		req.SetBasicAuth(user, password)
+	}
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to query issue tracker: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+	case resp.StatusCode != http.StatusOK:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("issue tracker returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode issue: %w", err)
+	}
+	return nil
+}
+
+// containsFold reports whether values contains value, ignoring case
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,159 @@
+package issues
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/golgoth31/gitcomm/pkg/git/remote"
+)
+
+func TestParseReferences(t *testing.T) {
+	tests := []struct {
+		name         string
+		text         string
+		jiraProjects []string
+		want         []Reference
+	}{
+		{
+			name:         "forge and jira references",
+			text:         "Closes #123\nRefs: PROJ-42",
+			jiraProjects: []string{"PROJ"},
+			want:         []Reference{{Kind: KindForge, ID: "123"}, {Kind: KindJira, ID: "PROJ-42"}},
+		},
+		{
+			name: "no jira keys without projects",
+			text: "Refs: PROJ-42\nEncoding: UTF-8, SHA-256",
+			want: nil,
+		},
+		{
+			name: "duplicates are reported once",
+			text: "Fixes #7, #8\nRefs #7",
+			want: []Reference{{Kind: KindForge, ID: "7"}, {Kind: KindForge, ID: "8"}},
+		},
+		{
+			name: "URL anchors and HTML entities are ignored",
+			text: "See https://example.com/docs/#12 and &#39;",
+			want: nil,
+		},
+		{
+			name:         "jira keys filtered by project",
+			text:         "Refs: PROJ-1, UTF-8, ops-2, OPS-3",
+			jiraProjects: []string{"proj", "OPS"},
+			want:         []Reference{{Kind: KindJira, ID: "PROJ-1"}, {Kind: KindJira, ID: "OPS-3"}},
+		},
+		{
+			name: "no references",
+			text: "Signed-off-by: Jane Doe <jane@example.com>",
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseReferences(tt.text, tt.jiraProjects)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseReferences() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifier_Supports(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+		kind   Kind
+		want   bool
+	}{
+		{"github", Config{Repository: &remote.Repository{Host: "github.com", Path: "o/r"}}, KindForge, true},
+		{"gitlab self-hosted", Config{Repository: &remote.Repository{Host: "gitlab.example.com", Path: "g/r"}}, KindForge, true},
+		{"unknown forge", Config{Repository: &remote.Repository{Host: "git.example.com", Path: "o/r"}}, KindForge, false},
+		{"no repository", Config{}, KindForge, false},
+		{"jira configured", Config{JiraURL: "https://jira.example.com"}, KindJira, true},
+		{"jira not configured", Config{}, KindJira, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewVerifier(tt.config).Supports(tt.kind); got != tt.want {
+				t.Errorf("Supports(%s) = %v, want %v", tt.kind, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifier_Lookup(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/owner/repo/issues/1":
+			if r.Header.Get("Authorization") != "Bearer gh-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(`{"title":"Crash on start","state":"open"}`))
+		case "/repos/owner/repo/issues/2":
+			_, _ = w.Write([]byte(`{"title":"Old bug","state":"closed"}`))
+		case "/projects/group/sub/repo/issues/3", "/projects/group%2Fsub%2Frepo/issues/3":
+			if r.Header.Get("PRIVATE-TOKEN") != "gl-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(`{"title":"Slow CI","state":"opened"}`))
+		case "/rest/api/2/issue/PROJ-4":
+			if user, pass, ok := r.BasicAuth(); !ok || user != "jane" || pass != "jira-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(`{"fields":{"summary":"Release","status":{"statusCategory":{"key":"done"}}}}`))
+		case "/repos/owner/repo/issues/5":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	github := NewVerifier(Config{Repository: &remote.Repository{Host: "github.com", Path: "owner/repo"}, GitHubToken: "gh-token"})
+	github.apiURL = server.URL
+	gitlab := NewVerifier(Config{Repository: &remote.Repository{Host: "gitlab.com", Path: "group/sub/repo"}, GitLabToken: "gl-token"})
+	gitlab.apiURL = server.URL
+	jira := NewVerifier(Config{JiraURL: server.URL + "/", JiraUser: "jane", JiraToken: "jira-token"})
+
+	tests := []struct {
+		name     string
+		verifier *Verifier
+		ref      Reference
+		want     *Issue
+		wantErr  error
+	}{
+		{"github open", github, Reference{Kind: KindForge, ID: "1"}, &Issue{Title: "Crash on start", Open: true}, nil},
+		{"github closed", github, Reference{Kind: KindForge, ID: "2"}, &Issue{Title: "Old bug", Open: false}, nil},
+		{"github missing", github, Reference{Kind: KindForge, ID: "99"}, nil, ErrNotFound},
+		{"gitlab open", gitlab, Reference{Kind: KindForge, ID: "3"}, &Issue{Title: "Slow CI", Open: true}, nil},
+		{"jira done", jira, Reference{Kind: KindJira, ID: "PROJ-4"}, &Issue{Title: "Release", Open: false}, nil},
+		{"jira missing", jira, Reference{Kind: KindJira, ID: "PROJ-5"}, nil, ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.verifier.Lookup(context.Background(), tt.ref)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Lookup() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Lookup() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("server error", func(t *testing.T) {
+		_, err := github.Lookup(context.Background(), Reference{Kind: KindForge, ID: "5"})
+		if err == nil || errors.Is(err, ErrNotFound) {
+			t.Errorf("Lookup() error = %v, want a tracker error", err)
+		}
+	})
+}
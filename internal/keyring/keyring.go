@@ -0,0 +1,162 @@
+// Package keyring stores secrets such as provider API keys in the keychain of the operating
+// system: the macOS Keychain, the Windows Credential Manager, or the Secret Service
+// (GNOME Keyring, KWallet) through libsecret's secret-tool on Linux and BSD.
+package keyring
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Service is the service name the secrets of gitcomm are stored under; the account is
+// the name given after the "keyring:" prefix of a config value
+const Service = "gitcomm"
+
+// Prefix marks a config value read from the keychain, e.g. api_key: keyring:openai
+const Prefix = "keyring:"
+
+var (
+	// ErrNotFound indicates that the keychain holds no secret for the account
+	ErrNotFound = errors.New("secret not found in the keychain")
+
+	// ErrUnsupported indicates that no keychain is available on this system
+	ErrUnsupported = errors.New("no keychain available")
+)
+
+// Store is a keychain backend
+type Store interface {
+	Get(account string) (string, error)
+	Set(account, secret string) error
+	Delete(account string) error
+}
+
+var (
+	mu    sync.Mutex
+	store Store = systemStore{}
+)
+
+// Use replaces the keychain with s, for tests, and returns a function restoring it
+func Use(s Store) (restore func()) {
+	mu.Lock()
+	defer mu.Unlock()
+	previous := store
+	store = s
+	return func() {
+		mu.Lock()
+		defer mu.Unlock()
+		store = previous
+	}
+}
+
+// current returns the keychain in use
+func current() Store {
+	mu.Lock()
+	defer mu.Unlock()
+	return store
+}
+
+// Get returns the secret stored for account
+func Get(account string) (string, error) {
+	if err := checkAccount(account); err != nil {
+		return "", err
+	}
+	secret, err := current().Get(account)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s%s: %w", Prefix, account, err)
+	}
+	return secret, nil
+}
+
+// Set stores secret for account, replacing the previous one
+func Set(account, secret string) error {
+	if err := checkAccount(account); err != nil {
+		return err
+	}
+	if secret == "" {
+		return fmt.Errorf("empty secret for %s%s", Prefix, account)
+	}
+	if err := current().Set(account, secret); err != nil {
+		return fmt.Errorf("failed to store %s%s: %w", Prefix, account, err)
+	}
+	return nil
+}
+
+// Delete removes the secret stored for account
+func Delete(account string) error {
+	if err := checkAccount(account); err != nil {
+		return err
+	}
+	if err := current().Delete(account); err != nil {
+		return fmt.Errorf("failed to delete %s%s: %w", Prefix, account, err)
+	}
+	return nil
+}
+
+// Reference returns the config value reading the secret of account from the keychain
+func Reference(account string) string {
+	return Prefix + account
+}
+
+// ParseReference returns the account of a "keyring:<account>" config value, false when
+// value is not a keychain reference
+func ParseReference(value string) (string, bool) {
+	account, ok := strings.CutPrefix(strings.TrimSpace(value), Prefix)
+	if !ok || account == "" {
+		return "", false
+	}
+	return account, true
+}
+
+// Resolve returns value, or the secret it references when it is a keychain reference
+func Resolve(value string) (string, error) {
+	account, ok := ParseReference(value)
+	if !ok {
+		return value, nil
+	}
+	return Get(account)
+}
+
+// checkAccount rejects account names the keychain tools cannot take as arguments
+func checkAccount(account string) error {
+	if account == "" || strings.ContainsAny(account, " \t\r\n\"'\\") {
+		return fmt.Errorf("invalid keychain account %q: use letters, digits, '-', '_' or '.'", account)
+	}
+	return nil
+}
+
+// withOutput adds the output of a failed keychain tool to err
+func withOutput(err error, out []byte) error {
+	if message := strings.TrimSpace(string(out)); message != "" {
+		return fmt.Errorf("%w: %s", err, message)
+	}
+	return err
+}
+
+// Memory is an in-memory keychain, for tests
+type Memory map[string]string
+
+// Get implements Store
+func (m Memory) Get(account string) (string, error) {
+	secret, ok := m[account]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return secret, nil
+}
+
+// Set implements Store
+func (m Memory) Set(account, secret string) error {
+	m[account] = secret
+	return nil
+}
+
+// Delete implements Store
+func (m Memory) Delete(account string) error {
+	if _, ok := m[account]; !ok {
+		return ErrNotFound
+	}
+	delete(m, account)
+	return nil
+}
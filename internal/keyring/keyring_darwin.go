@@ -0,0 +1,57 @@
+package keyring
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// securityNotFound is the exit code of the security tool when no item matches
+const securityNotFound = 44
+
+// systemStore stores the secrets as generic passwords of the login Keychain with the
+// security tool
+type systemStore struct{}
+
+// Get implements Store
+func (systemStore) Get(account string) (string, error) {
+	out, err := exec.Command("security", "find-generic-password", "-s", Service, "-a", account, "-w").Output()
+	if err != nil {
+		return "", securityError(err)
+	}
+	return strings.TrimSuffix(string(out), "\n"), nil
+}
+
+// Set implements Store. The secret is written to the interactive mode of security, hex
+// encoded, so it never shows in the process list.
+func (systemStore) Set(account, secret string) error {
+	cmd := exec.Command("security", "-i")
+	cmd.Stdin = strings.NewReader(fmt.Sprintf("add-generic-password -U -s %s -a %s -X %s\n",
+		Service, account, hex.EncodeToString([]byte(secret))))
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return withOutput(securityError(err), out)
+	}
+	return nil
+}
+
+// Delete implements Store
+func (systemStore) Delete(account string) error {
+	if err := exec.Command("security", "delete-generic-password", "-s", Service, "-a", account).Run(); err != nil {
+		return securityError(err)
+	}
+	return nil
+}
+
+// securityError converts a failure of the security tool
+func securityError(err error) error {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == securityNotFound {
+		return ErrNotFound
+	}
+	if errors.Is(err, exec.ErrNotFound) {
+		return fmt.Errorf("%w: the security tool is not installed", ErrUnsupported)
+	}
+	return err
+}
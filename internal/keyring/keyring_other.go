@@ -0,0 +1,15 @@
+//go:build !unix && !windows
+
+package keyring
+
+// systemStore reports that no keychain is supported on this system
+type systemStore struct{}
+
+// Get implements Store
+func (systemStore) Get(string) (string, error) { return "", ErrUnsupported }
+
+// Set implements Store
+func (systemStore) Set(string, string) error { return ErrUnsupported }
+
+// Delete implements Store
+func (systemStore) Delete(string) error { return ErrUnsupported }
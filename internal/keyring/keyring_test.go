@@ -0,0 +1,58 @@
+package keyring
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseReference(t *testing.T) {
+	tests := []struct {
+		value   string
+		account string
+		ok      bool
+	}{
+		{value: "keyring:openai", account: "openai", ok: true},
+		{value: " keyring:openai-work ", account: "openai-work", ok: true},
+		{value: "keyring:", ok: false},
+		{value: "sk-secret", ok: false},
+		{value: "${OPENAI_API_KEY}", ok: false},
+		{value: "", ok: false},
+	}
+
+	for _, tt := range tests {
+		account, ok := ParseReference(tt.value)
+		if account != tt.account || ok != tt.ok {
+			t.Errorf("ParseReference(%q) = %q, %v, want %q, %v", tt.value, account, ok, tt.account, tt.ok)
+		}
+	}
+}
+
+func TestStore(t *testing.T) {
+	defer Use(Memory{})()
+
+	if _, err := Get("openai"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get() before Set error = %v, want ErrNotFound", err)
+	}
+	if err := Set("openai", "sk-secret"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if got, err := Resolve(Reference("openai")); err != nil || got != "sk-secret" {
+		t.Errorf("Resolve(keyring:openai) = %q, %v, want sk-secret", got, err)
+	}
+	if got, err := Resolve("sk-clear"); err != nil || got != "sk-clear" {
+		t.Errorf("Resolve(sk-clear) = %q, %v, want the value itself", got, err)
+	}
+	if err := Delete("openai"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := Delete("openai"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("second Delete() error = %v, want ErrNotFound", err)
+	}
+
+	if err := Set("open ai", "sk-secret"); err == nil {
+		t.Error("Set() with a space in the account succeeded, want an error")
+	}
+	if err := Set("openai", ""); err == nil {
+		t.Error("Set() of an empty secret succeeded, want an error")
+	}
+}
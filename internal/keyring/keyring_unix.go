@@ -0,0 +1,65 @@
+//go:build unix && !darwin
+
+package keyring
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// systemStore stores the secrets in the Secret Service (GNOME Keyring, KWallet) with
+// libsecret's secret-tool
+type systemStore struct{}
+
+// attributes are the Secret Service attributes identifying the secret of account
+func attributes(account string) []string {
+	return []string{"service", Service, "account", account}
+}
+
+// Get implements Store
+func (systemStore) Get(account string) (string, error) {
+	out, err := exec.Command("secret-tool", append([]string{"lookup"}, attributes(account)...)...).Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) == 0 {
+			// secret-tool exits with 1 without message when nothing matches
+			return "", ErrNotFound
+		}
+		return "", secretToolError(err)
+	}
+	return strings.TrimSuffix(string(out), "\n"), nil
+}
+
+// Set implements Store. The secret is written to the standard input of secret-tool, so
+// it never shows in the process list.
+func (systemStore) Set(account, secret string) error {
+	args := append([]string{"store", "--label", Service + ": " + account}, attributes(account)...)
+	cmd := exec.Command("secret-tool", args...)
+	cmd.Stdin = strings.NewReader(secret)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return withOutput(secretToolError(err), out)
+	}
+	return nil
+}
+
+// Delete implements Store
+func (s systemStore) Delete(account string) error {
+	// secret-tool clear succeeds when nothing matches
+	if _, err := s.Get(account); err != nil {
+		return err
+	}
+	if out, err := exec.Command("secret-tool", append([]string{"clear"}, attributes(account)...)...).CombinedOutput(); err != nil {
+		return withOutput(secretToolError(err), out)
+	}
+	return nil
+}
+
+// secretToolError converts a failure to run secret-tool
+func secretToolError(err error) error {
+	if errors.Is(err, exec.ErrNotFound) {
+		return fmt.Errorf("%w: install secret-tool (libsecret-tools on Debian and Ubuntu, libsecret on Fedora and Arch)", ErrUnsupported)
+	}
+	return err
+}
@@ -0,0 +1,246 @@
+// Package mail sends exported patches over SMTP, similar to git send-email.
+package mail
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Supported values for Config.Encryption
+const (
+	EncryptionNone     = "none"
+	EncryptionStartTLS = "tls" // STARTTLS upgrade (port 587), same naming as sendemail.smtpEncryption
+	EncryptionSSL      = "ssl" // Implicit TLS (port 465)
+)
+
+// Config holds the SMTP server settings and recipients
+type Config struct {
+	// Server is the SMTP server host name
+	Server string
+
+	// Port is the SMTP server port (default: 587, or 465 for ssl)
+	Port int
+
+	// Username and Password are used for PLAIN authentication when Username is set
+	Username string
+	Password string
+
+	// Encryption is one of "tls" (STARTTLS, default), "ssl" or "none"
+	Encryption string
+
+	// From is the envelope sender and From header ("Name <email>")
+	From string
+
+	// To and Cc are the recipient lists
+	To []string
+	Cc []string
+}
+
+// Validate checks that the configuration can be used to send mail
+func (c *Config) Validate() error {
+	if c.Server == "" {
+		return fmt.Errorf("smtp server is not configured")
+	}
+	if _, err := mail.ParseAddress(c.From); err != nil {
+		return fmt.Errorf("invalid sender %q: %w", c.From, err)
+	}
+	if len(c.To) == 0 {
+		return fmt.Errorf("no recipients configured")
+	}
+	for _, addr := range append(append([]string{}, c.To...), c.Cc...) {
+		if _, err := mail.ParseAddress(addr); err != nil {
+			return fmt.Errorf("invalid recipient %q: %w", addr, err)
+		}
+	}
+	switch c.Encryption {
+	case "", EncryptionNone, EncryptionStartTLS, EncryptionSSL:
+	default:
+		return fmt.Errorf("invalid smtp encryption %q: expected tls, ssl or none", c.Encryption)
+	}
+	return nil
+}
+
+// Sender sends patch files over SMTP
+type Sender struct {
+	config Config
+}
+
+// NewSender creates a new patch sender
+func NewSender(config Config) (*Sender, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return &Sender{config: config}, nil
+}
+
+// SendPatch sends a format-patch file to the configured recipients
+func (s *Sender) SendPatch(ctx context.Context, patchPath string) error {
+	patch, err := os.ReadFile(patchPath)
+	if err != nil {
+		return fmt.Errorf("failed to read patch: %w", err)
+	}
+
+	msg, err := buildMessage(patch, s.config, time.Now())
+	if err != nil {
+		return err
+	}
+	return s.send(ctx, msg)
+}
+
+// buildMessage turns a format-patch mbox file into an RFC 5322 message.
+// Like git send-email, the patch author is kept as an in-body From line when it differs from the sender.
+func buildMessage(patch []byte, config Config, now time.Time) ([]byte, error) {
+	content := strings.ReplaceAll(string(patch), "\r\n", "\n")
+	// Drop the mbox separator line ("From <hash> Mon Sep 17 00:00:00 2001")
+	if strings.HasPrefix(content, "From ") {
+		if _, rest, ok := strings.Cut(content, "\n"); ok {
+			content = rest
+		}
+	}
+
+	msg, err := mail.ReadMessage(strings.NewReader(content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse patch headers: %w", err)
+	}
+	body := new(bytes.Buffer)
+	if _, err := body.ReadFrom(msg.Body); err != nil {
+		return nil, fmt.Errorf("failed to read patch body: %w", err)
+	}
+
+	subject := msg.Header.Get("Subject")
+	if subject == "" {
+		return nil, fmt.Errorf("patch has no Subject header")
+	}
+
+	var out bytes.Buffer
+	writeHeader := func(key, value string) {
+		fmt.Fprintf(&out, "%s: %s\r\n", key, value)
+	}
+	writeHeader("From", config.From)
+	writeHeader("To", strings.Join(config.To, ", "))
+	if len(config.Cc) > 0 {
+		writeHeader("Cc", strings.Join(config.Cc, ", "))
+	}
+	writeHeader("Subject", subject)
+	date := msg.Header.Get("Date")
+	if date == "" {
+		date = now.Format(time.RFC1123Z)
+	}
+	writeHeader("Date", date)
+	writeHeader("Message-Id", fmt.Sprintf("<%d.gitcomm@%s>", now.UnixNano(), senderDomain(config.From)))
+	writeHeader("MIME-Version", "1.0")
+	writeHeader("Content-Type", "text/plain; charset=UTF-8")
+	writeHeader("Content-Transfer-Encoding", "8bit")
+	out.WriteString("\r\n")
+
+	if author := msg.Header.Get("From"); author != "" && !sameAddress(author, config.From) {
+		out.WriteString("From: " + author + "\r\n\r\n")
+	}
+
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		out.WriteString(scanner.Text() + "\r\n")
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read patch body: %w", err)
+	}
+	return out.Bytes(), nil
+}
+
+// send delivers msg to all recipients over SMTP
+func (s *Sender) send(ctx context.Context, msg []byte) error {
+	cfg := s.config
+	port := cfg.Port
+	if port == 0 {
+		port = 587
+		if cfg.Encryption == EncryptionSSL {
+			port = 465
+		}
+	}
+	addr := net.JoinHostPort(cfg.Server, strconv.Itoa(port))
+	tlsConfig := &tls.Config{ServerName: cfg.Server}
+
+	dialer := &net.Dialer{Timeout: 30 * time.Second}
+	var conn net.Conn
+	var err error
+	if cfg.Encryption == EncryptionSSL {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: tlsConfig}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to connect to smtp server %s: %w", addr, err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, cfg.Server)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("smtp handshake failed: %w", err)
+	}
+	defer client.Close()
+
+	if cfg.Encryption == "" || cfg.Encryption == EncryptionStartTLS {
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return fmt.Errorf("smtp STARTTLS failed: %w", err)
+		}
+	}
+	if cfg.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Server)); err != nil {
+			return fmt.Errorf("smtp authentication failed: %w", err)
+		}
+	}
+
+	from, _ := mail.ParseAddress(cfg.From) // Validated in NewSender
+	if err := client.Mail(from.Address); err != nil {
+		return fmt.Errorf("smtp MAIL FROM failed: %w", err)
+	}
+	for _, rcpt := range append(append([]string{}, cfg.To...), cfg.Cc...) {
+		addr, _ := mail.ParseAddress(rcpt)
+		if err := client.Rcpt(addr.Address); err != nil {
+			return fmt.Errorf("smtp RCPT TO %s failed: %w", addr.Address, err)
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("smtp DATA failed: %w", err)
+	}
+	if _, err := w.Write(msg); err != nil {
+		return fmt.Errorf("failed to write message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("smtp server rejected message: %w", err)
+	}
+	return client.Quit()
+}
+
+// sameAddress returns true if both strings contain the same email address
+func sameAddress(a, b string) bool {
+	addrA, errA := mail.ParseAddress(a)
+	addrB, errB := mail.ParseAddress(b)
+	return errA == nil && errB == nil && strings.EqualFold(addrA.Address, addrB.Address)
+}
+
+// senderDomain returns the domain of the sender address for Message-Id generation
+func senderDomain(from string) string {
+	if addr, err := mail.ParseAddress(from); err == nil {
+		if _, domain, ok := strings.Cut(addr.Address, "@"); ok {
+			return domain
+		}
+	}
+	return "localhost"
+}
@@ -0,0 +1,184 @@
+package mail
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testPatch = `From 1234567890abcdef1234567890abcdef12345678 Mon Sep 17 00:00:00 2001
+From: Jane Doe <jane@example.com>
+Date: Fri, 16 Oct 2026 10:00:00 +0200
+Subject: [PATCH] fix: handle edge case
+
+Explain why.
+
+Signed-off-by: Jane Doe <jane@example.com>
+---
+ fix.txt | 1 +
+ 1 file changed, 1 insertion(+)
+`
+
+func TestBuildMessage(t *testing.T) {
+	tests := []struct {
+		name         string
+		from         string
+		wantInBodyTo bool
+	}{
+		{name: "sender is the author", from: "Jane Doe <jane@example.com>", wantInBodyTo: false},
+		{name: "sender differs from author", from: "Bot <bot@example.com>", wantInBodyTo: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Config{From: tt.from, To: []string{"list@example.org"}, Cc: []string{"maintainer@example.org"}}
+			msg, err := buildMessage([]byte(testPatch), cfg, time.Unix(0, 0))
+			if err != nil {
+				t.Fatalf("buildMessage() error = %v", err)
+			}
+
+			out := string(msg)
+			for _, want := range []string{
+				"From: " + tt.from + "\r\n",
+				"To: list@example.org\r\n",
+				"Cc: maintainer@example.org\r\n",
+				"Subject: [PATCH] fix: handle edge case\r\n",
+				"Date: Fri, 16 Oct 2026 10:00:00 +0200\r\n",
+				"Signed-off-by: Jane Doe <jane@example.com>\r\n",
+			} {
+				if !strings.Contains(out, want) {
+					t.Errorf("message missing %q:\n%s", want, out)
+				}
+			}
+			if strings.Contains(out, "Mon Sep 17 00:00:00 2001") {
+				t.Error("message should not contain the mbox separator line")
+			}
+
+			_, body, _ := strings.Cut(out, "\r\n\r\n")
+			hasInBodyFrom := strings.HasPrefix(body, "From: Jane Doe <jane@example.com>\r\n")
+			if hasInBodyFrom != tt.wantInBodyTo {
+				t.Errorf("in-body From = %v, want %v", hasInBodyFrom, tt.wantInBodyTo)
+			}
+		})
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	valid := Config{Server: "smtp.example.com", From: "Jane <jane@example.com>", To: []string{"list@example.org"}}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+
+	invalid := []Config{
+		{From: valid.From, To: valid.To},
+		{Server: valid.Server, From: "nobody", To: valid.To},
+		{Server: valid.Server, From: valid.From},
+		{Server: valid.Server, From: valid.From, To: []string{"not an address"}},
+		{Server: valid.Server, From: valid.From, To: valid.To, Encryption: "starttls-please"},
+	}
+	for i, cfg := range invalid {
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("case %d: Validate() expected error for %+v", i, cfg)
+		}
+	}
+}
+
+func TestSender_SendPatch(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer listener.Close()
+
+	received := make(chan []string, 1)
+	go serveFakeSMTP(t, listener, received)
+
+	patchPath := filepath.Join(t.TempDir(), "0001-fix.patch")
+	if err := os.WriteFile(patchPath, []byte(testPatch), 0644); err != nil {
+		t.Fatalf("Failed to write patch: %v", err)
+	}
+
+	port, _ := strconv.Atoi(strings.TrimPrefix(listener.Addr().String(), "127.0.0.1:"))
+	sender, err := NewSender(Config{
+		Server:     "127.0.0.1",
+		Port:       port,
+		Encryption: EncryptionNone,
+		From:       "Jane Doe <jane@example.com>",
+		To:         []string{"list@example.org"},
+		Cc:         []string{"Maintainer <maintainer@example.org>"},
+	})
+	if err != nil {
+		t.Fatalf("NewSender() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := sender.SendPatch(ctx, patchPath); err != nil {
+		t.Fatalf("SendPatch() error = %v", err)
+	}
+
+	commands := strings.Join(<-received, "\n")
+	for _, want := range []string{
+		"MAIL FROM:<jane@example.com>",
+		"RCPT TO:<list@example.org>",
+		"RCPT TO:<maintainer@example.org>",
+		"Subject: [PATCH] fix: handle edge case",
+	} {
+		if !strings.Contains(commands, want) {
+			t.Errorf("SMTP session missing %q:\n%s", want, commands)
+		}
+	}
+}
+
+// serveFakeSMTP accepts one connection and records every line received
+func serveFakeSMTP(t *testing.T, listener net.Listener, received chan<- []string) {
+	conn, err := listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	var lines []string
+	reader := bufio.NewReader(conn)
+	reply := func(s string) { conn.Write([]byte(s + "\r\n")) }
+	reply("220 localhost ESMTP")
+
+	inData := false
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			break
+		}
+		line = strings.TrimRight(line, "\r\n")
+		lines = append(lines, line)
+
+		if inData {
+			if line == "." {
+				inData = false
+				reply("250 OK")
+			}
+			continue
+		}
+
+		switch cmd := strings.ToUpper(strings.SplitN(line, " ", 2)[0]); cmd {
+		case "EHLO", "HELO":
+			reply("250 localhost")
+		case "DATA":
+			inData = true
+			reply("354 End data with <CR><LF>.<CR><LF>")
+		case "QUIT":
+			reply("221 Bye")
+			received <- lines
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+	received <- lines
+}
@@ -0,0 +1,56 @@
+package model
+
+import "time"
+
+// OtherType is the type of the commits whose message is not a Conventional Commit
+const OtherType = "other"
+
+// ActivityReport summarizes the commits of the git user over a period, across repositories
+type ActivityReport struct {
+	// Since and Until bound the period
+	Since time.Time
+	Until time.Time
+
+	// Commits is the number of commits of the period
+	Commits int
+
+	// ByType counts the commits by Conventional Commits type (OtherType for the others)
+	ByType map[string]int
+
+	// Repositories lists the repositories looked at, most commits first
+	Repositories []RepositoryActivity
+
+	// AverageScore is the mean message score, from 0 to MaxScore
+	AverageScore float64
+	MaxScore     int
+
+	// ActiveDays is the number of days with commits
+	ActiveDays int
+
+	// Streak is the number of consecutive days with commits up to today, or up to
+	// yesterday when there is no commit yet today
+	Streak int
+}
+
+// RepositoryActivity is the part of an activity report about one repository
+type RepositoryActivity struct {
+	// Path is the top-level directory of the repository
+	Path string
+
+	// Commits is the number of commits of the period in the repository
+	Commits int
+
+	// Err explains why the repository could not be read
+	Err error
+}
+
+// TouchedRepositories returns the number of repositories with commits
+func (r *ActivityReport) TouchedRepositories() int {
+	touched := 0
+	for _, repository := range r.Repositories {
+		if repository.Commits > 0 {
+			touched++
+		}
+	}
+	return touched
+}
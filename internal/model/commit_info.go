@@ -0,0 +1,58 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// signoffPrefix is the trailer key used by the Developer Certificate of Origin
+const signoffPrefix = "Signed-off-by:"
+
+// CommitInfo represents an existing commit read from the repository history
+type CommitInfo struct {
+	// Hash is the full commit hash
+	Hash string
+
+	// AuthorName is the commit author name
+	AuthorName string
+
+	// AuthorEmail is the commit author email
+	AuthorEmail string
+
+	// Date is the author date
+	Date time.Time
+
+	// Message is the raw commit message (subject, body and trailers)
+	Message string
+}
+
+// ShortHash returns the abbreviated (7 characters) commit hash
+func (c *CommitInfo) ShortHash() string {
+	if len(c.Hash) > 7 {
+		return c.Hash[:7]
+	}
+	return c.Hash
+}
+
+// Subject returns the first line of the commit message
+func (c *CommitInfo) Subject() string {
+	subject, _, _ := strings.Cut(strings.TrimSpace(c.Message), "\n")
+	return subject
+}
+
+// Signoffs returns the identities of all valid Signed-off-by trailers in the message
+func (c *CommitInfo) Signoffs() []Identity {
+	var identities []Identity
+	for _, line := range strings.Split(c.Message, "\n") {
+		value, ok := strings.CutPrefix(strings.TrimSpace(line), signoffPrefix)
+		if !ok {
+			continue
+		}
+		identity, err := ParseIdentity(value)
+		if err != nil {
+			continue // Malformed trailers never satisfy DCO
+		}
+		identities = append(identities, *identity)
+	}
+	return identities
+}
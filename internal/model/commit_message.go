@@ -1,5 +1,11 @@
 package model
 
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
 // CommitMessage represents a structured commit message conforming to Conventional Commits specification
 type CommitMessage struct {
 	// Type is the commit type (feat, fix, docs, style, refactor, test, chore, version)
@@ -14,15 +20,119 @@ type CommitMessage struct {
 	// Body is the optional detailed explanation, wrapped at 72 chars, ≤320 characters
 	Body string
 
-	// Footer is the optional footer lines (issue references, breaking changes, etc.)
+	// Breaking marks a breaking change with "!" after the type and scope of the header
+	Breaking bool
+
+	// BreakingDescription is the "BREAKING CHANGE: <description>" footer, written before
+	// the other footers; it marks a breaking change even without Breaking
+	BreakingDescription string
+
+	// Trailers are the "Key: value" footers (Refs, Time-spent...), written last so that
+	// git reads them as trailers
+	Trailers []Trailer
+
+	// Footer is the free-form footer text, written after the breaking change description
+	// and before the trailers.
+	//
+	// Deprecated: set BreakingDescription and Trailers, or SetFooter to parse a footer
+	// typed by the user; Footer only keeps the lines that are neither.
 	Footer string
 
 	// Signoff indicates whether to include "Signed-off-by" line (default: true)
 	Signoff bool
+
+	// SignoffIdentity overrides the git user identity in the "Signed-off-by" line (optional)
+	SignoffIdentity *Identity
+
+	// NoVerify skips the pre-commit and commit-msg hooks, like git commit --no-verify
+	NoVerify bool
+
+	// FootersApplied reports that the configured footers (stats, ticket, time spent, AI
+	// trailer) were added, so that a message shown before being committed gets them once
+	FootersApplied bool
+
+	// Fixup is the commit this one fixes up ("fixup! <subject>" for git rebase --autosquash), if any
+	Fixup *CommitInfo
+
+	// Ticket is the ticket reference written in the header, when Layout has a {ticket} placeholder
+	Ticket string
+
+	// Layout is the custom header layout (commit.header_format); nil for type(scope): subject
+	Layout *HeaderLayout
 }
 
 // IsEmpty returns true if the commit message has no meaningful content
-// A message is considered empty if it lacks both type and subject, or has type but no subject
+// A message is considered empty if it lacks both type and subject, or has type but no subject.
+// A fixup message is never empty: its header is derived from the target commit.
 func (m *CommitMessage) IsEmpty() bool {
+	if m.Fixup != nil {
+		return false
+	}
 	return (m.Type == "" && m.Subject == "") || (m.Type != "" && m.Subject == "")
 }
+
+// Header returns the first line of the commit message: "type(scope): subject" or the
+// custom layout, or "fixup! <target subject>" for a fixup commit
+func (m *CommitMessage) Header() string {
+	if m.Fixup != nil {
+		return "fixup! " + m.Fixup.Subject()
+	}
+	if m.Layout != nil {
+		return m.Layout.Render(m)
+	}
+	header := m.Type
+	if m.Scope != "" {
+		header = fmt.Sprintf("%s(%s)", header, m.Scope)
+	}
+	if m.Breaking {
+		header += "!"
+	}
+	return fmt.Sprintf("%s: %s", header, m.Subject)
+}
+
+// IsBreaking reports whether the message announces a breaking change, in the header or
+// in a BREAKING CHANGE footer
+func (m *CommitMessage) IsBreaking() bool {
+	return m.Breaking || m.BreakingDescription != ""
+}
+
+// FooterText returns the footer of the message: the breaking change description, the
+// free-form Footer, then the trailers, one per line
+func (m *CommitMessage) FooterText() string {
+	var lines []string
+	if m.BreakingDescription != "" {
+		lines = append(lines, BreakingChangeToken+": "+m.BreakingDescription)
+	}
+	if m.Footer != "" {
+		lines = append(lines, m.Footer)
+	}
+	for _, trailer := range m.Trailers {
+		lines = append(lines, trailer.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// SetFooter replaces the footers of the message with footer, parsed by ParseFooter
+func (m *CommitMessage) SetFooter(footer string) {
+	m.BreakingDescription, m.Trailers, m.Footer = ParseFooter(footer)
+}
+
+// AppendFooter adds footer, parsed by ParseFooter, after the footers of the message. A
+// breaking change description replaces the current one.
+func (m *CommitMessage) AppendFooter(footer string) {
+	breaking, trailers, text := ParseFooter(footer)
+	if breaking != "" {
+		m.BreakingDescription = breaking
+	}
+	m.Trailers = append(slices.Clone(m.Trailers), trailers...)
+	if text != "" && m.Footer != "" {
+		m.Footer += "\n" + text
+	} else if text != "" {
+		m.Footer = text
+	}
+}
+
+// AddTrailer adds the trailer "key: value" after the footers of the message
+func (m *CommitMessage) AddTrailer(key, value string) {
+	m.Trailers = append(slices.Clone(m.Trailers), Trailer{Key: key, Value: value})
+}
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"slices"
 	"testing"
 )
 
@@ -47,3 +48,137 @@ func TestCommitMessage_IsEmpty(t *testing.T) {
 		})
 	}
 }
+
+func TestCommitMessage_Header(t *testing.T) {
+	tests := []struct {
+		name    string
+		message CommitMessage
+		want    string
+	}{
+		{
+			name:    "type and subject",
+			message: CommitMessage{Type: "feat", Subject: "add pager"},
+			want:    "feat: add pager",
+		},
+		{
+			name:    "with scope",
+			message: CommitMessage{Type: "fix", Scope: "ui", Subject: "wrap body"},
+			want:    "fix(ui): wrap body",
+		},
+		{
+			name:    "breaking change",
+			message: CommitMessage{Type: "feat", Scope: "api", Subject: "drop v1 routes", Breaking: true},
+			want:    "feat(api)!: drop v1 routes",
+		},
+		{
+			name:    "breaking change without scope",
+			message: CommitMessage{Type: "refactor", Subject: "rename config keys", Breaking: true},
+			want:    "refactor!: rename config keys",
+		},
+		{
+			name: "fixup uses target subject",
+			message: CommitMessage{
+				Type:    "feat",
+				Subject: "ignored",
+				Fixup:   &CommitInfo{Hash: "abc", Message: "feat(ui): add pager\n\nBody"},
+			},
+			want: "fixup! feat(ui): add pager",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.message.Header(); got != tt.want {
+				t.Errorf("CommitMessage.Header() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommitMessage_FooterText(t *testing.T) {
+	message := CommitMessage{
+		Type:                "feat",
+		Subject:             "drop v1 routes",
+		BreakingDescription: "the /v1 routes are gone",
+		Footer:              "Closes #3",
+		Trailers:            []Trailer{{Key: "Refs", Value: "#12"}},
+	}
+	message.AddTrailer("Time-spent", "1h")
+	want := "BREAKING CHANGE: the /v1 routes are gone\nCloses #3\nRefs: #12\nTime-spent: 1h"
+	if got := message.FooterText(); got != want {
+		t.Errorf("FooterText() = %q, want %q", got, want)
+	}
+	if !message.IsBreaking() {
+		t.Error("IsBreaking() = false with a BREAKING CHANGE footer")
+	}
+
+	// Appending keeps the trailers last, and never modifies a copied message
+	copied := message
+	copied.AppendFooter("Fixes #4\nReviewed-by: Ann")
+	want = "BREAKING CHANGE: the /v1 routes are gone\nCloses #3\nFixes #4\nRefs: #12\nTime-spent: 1h\nReviewed-by: Ann"
+	if got := copied.FooterText(); got != want {
+		t.Errorf("FooterText() after AppendFooter() = %q, want %q", got, want)
+	}
+	if len(message.Trailers) != 2 {
+		t.Errorf("original trailers modified: %v", message.Trailers)
+	}
+
+	copied.SetFooter("Refs: #5")
+	if got := copied.FooterText(); got != "Refs: #5" {
+		t.Errorf("FooterText() after SetFooter() = %q, want %q", got, "Refs: #5")
+	}
+}
+
+func TestParseFooter(t *testing.T) {
+	tests := []struct {
+		name         string
+		footer       string
+		wantBreaking string
+		wantTrailers []Trailer
+		wantText     string
+	}{
+		{name: "empty"},
+		{
+			name:         "trailers",
+			footer:       "Refs: #12\nCo-authored-by: Ann <ann@example.com>",
+			wantTrailers: []Trailer{{Key: "Refs", Value: "#12"}, {Key: "Co-authored-by", Value: "Ann <ann@example.com>"}},
+		},
+		{
+			name:         "breaking change",
+			footer:       "BREAKING CHANGE: the /v1 routes are gone\nRefs: #12",
+			wantBreaking: "the /v1 routes are gone",
+			wantTrailers: []Trailer{{Key: "Refs", Value: "#12"}},
+		},
+		{
+			name:         "breaking change synonym continued",
+			footer:       "BREAKING-CHANGE: the /v1 routes are gone\n  use /v2 instead",
+			wantBreaking: "the /v1 routes are gone\n  use /v2 instead",
+		},
+		{
+			name:         "free-form lines kept as text",
+			footer:       "Fixes #12\nSigned off by the team\nRefs: #13",
+			wantTrailers: []Trailer{{Key: "Refs", Value: "#13"}},
+			wantText:     "Fixes #12\nSigned off by the team",
+		},
+		{
+			name:     "key without value",
+			footer:   "Refs: ",
+			wantText: "Refs: ",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			breaking, trailers, text := ParseFooter(tt.footer)
+			if breaking != tt.wantBreaking {
+				t.Errorf("ParseFooter() breaking = %q, want %q", breaking, tt.wantBreaking)
+			}
+			if !slices.Equal(trailers, tt.wantTrailers) {
+				t.Errorf("ParseFooter() trailers = %v, want %v", trailers, tt.wantTrailers)
+			}
+			if text != tt.wantText {
+				t.Errorf("ParseFooter() text = %q, want %q", text, tt.wantText)
+			}
+		})
+	}
+}
@@ -7,28 +7,111 @@ type CommitOptions struct {
 	// AutoStage automatically stages all unstaged files (-a flag)
 	AutoStage bool
 
+	// Interactive lets the user pick the files (and hunks) to stage instead of auto-staging (-i flag)
+	Interactive bool
+
 	// NoSignoff disables commit signoff (-s flag)
 	NoSignoff bool
 
+	// SignoffIdentity is the identity used for the Signed-off-by line instead of the author (optional)
+	SignoffIdentity *Identity
+
+	// DCO enforces a Signed-off-by line matching the author (Developer Certificate of Origin)
+	DCO bool
+
+	// Branch creates the commit on this branch (new or existing) without switching the worktree
+	Branch string
+
+	// ExportPatchDir writes a format-patch style file of the commit into this directory (optional)
+	ExportPatchDir string
+
+	// SaveExchangeDir writes the AI prompts of the commit, redacted, and the raw answers
+	// into this directory, in a file named after the commit hash (optional)
+	SaveExchangeDir string
+
+	// NoVerify skips the pre-commit and commit-msg hooks (--no-verify)
+	NoVerify bool
+
+	// PatchOnly exports the patch without creating a commit on any branch (requires ExportPatchDir)
+	PatchOnly bool
+
+	// SendEmail sends the exported patch over SMTP (patch goes to a temporary directory if ExportPatchDir is empty)
+	SendEmail bool
+
+	// Fixup is the revision to create a "fixup!" commit for (message is derived from it)
+	Fixup string
+
+	// Amend replaces the HEAD commit instead of creating a new one (--amend flag)
+	Amend bool
+
+	// Force allows amending a commit that was already pushed
+	Force bool
+
+	// Push pushes the branch after the commit, to the push remote of triangular (fork) workflows
+	Push bool
+
+	// SessionContext includes the author's previous commit on the same files in the AI prompt
+	SessionContext bool
+
+	// SummaryFirst asks the model which files it needs the diffs of before sending them,
+	// whatever the size of the change (ai.context.summary_first always)
+	SummaryFirst bool
+
 	// AIProvider overrides the default AI provider
 	AIProvider string
 
+	// Account selects the named credentials of the provider, overriding ai.account_rules
+	// and ai.account
+	Account string
+
 	// SkipAI skips AI generation and goes directly to manual input
 	SkipAI bool
+
+	// DryRun runs the workflow on a copy of the index and prints the message and files
+	// instead of committing
+	DryRun bool
+
+	// Copy places the final message on the system clipboard instead of committing: the
+	// changes stay staged for another client
+	Copy bool
+
+	// NewBranch creates a branch named after the final message (commit.branch_template)
+	// and switches to it before committing
+	NewBranch bool
+
+	// Message is a complete commit message supplied on the command line (-m, -F): AI and
+	// manual input are skipped, the message is still validated, signed off and signed
+	Message string
+
+	// Pathspecs limit auto-staging, the AI context and the commit to the matching paths
+	// (gitcomm -- <paths>), relative to the worktree root; other staged changes stay staged
+	Pathspecs []string
 }
 
 // AIProviderConfig represents configuration for an AI provider
 type AIProviderConfig struct {
-	// Name is the provider name (openai, anthropic, local)
+	// Name is the provider name (openai, anthropic, mistral, ollama, local)
 	Name string
 
 	// APIKey is the API key or authentication token
 	APIKey string
 
+	// APIKeyCommand is a shell command printing the API key (e.g. a password manager CLI),
+	// run instead of reading APIKey when the provider is first used
+	APIKeyCommand string
+
+	// Accounts are the named credentials of the provider (e.g. work, personal), used instead
+	// of APIKey and APIKeyCommand when selected
+	Accounts map[string]ProviderAccount
+
+	// Account is the name of the account whose credentials are in use ("": the provider's own)
+	Account string
+
 	// Model is the optional model identifier (e.g., "gpt-4", "claude-3-opus")
 	Model string
 
-	// Endpoint is the optional custom API endpoint (for local models)
+	// Endpoint is the optional custom API endpoint (for local models), or the base URL of an
+	// OpenAI or Anthropic compatible gateway
 	Endpoint string
 
 	// Timeout is the optional request timeout (default: 30s)
@@ -36,4 +119,88 @@ type AIProviderConfig struct {
 
 	// MaxTokens is the optional maximum tokens for response (default: 500)
 	MaxTokens int
+
+	// MaxConcurrent is the optional maximum number of simultaneous requests (default: unlimited)
+	MaxConcurrent int
+
+	// RequestsPerMinute is the optional maximum number of requests per minute (default: unlimited)
+	RequestsPerMinute int
+
+	// CircuitThreshold is the number of consecutive failed requests after which the provider
+	// is skipped for CircuitCooldown (0 disables the circuit breaker)
+	CircuitThreshold int
+
+	// CircuitCooldown is how long the provider is skipped once CircuitThreshold is reached
+	CircuitCooldown time.Duration
+
+	// RetryAttempts is the number of attempts of a request failing with a transient error
+	// (rate limit, server error, timeout); 0 or 1 disables retries
+	RetryAttempts int
+
+	// RetryBackoff is the delay before the first retry, doubled for each next one up to
+	// RetryMaxBackoff
+	RetryBackoff    time.Duration
+	RetryMaxBackoff time.Duration
+
+	// RetryJitter randomizes each delay by up to this fraction of it (0.2: ±20%)
+	RetryJitter float64
+
+	// NumCtx is the context window size in tokens requested from Ollama (default: the prompt
+	// budget plus MaxTokens)
+	NumCtx int
+
+	// KeepAlive is how long Ollama keeps the model loaded after a request (e.g. "10m", "-1"
+	// to keep it loaded; default: the Ollama server setting)
+	KeepAlive string
+
+	// Prompt controls which sections of the changes fit in the prompt and in which order
+	Prompt PromptLayout
+}
+
+// ProviderAccount is a named credential of a provider, so that usage is billed to the
+// right account
+type ProviderAccount struct {
+	// APIKey is the API key of the account (may be a keyring: reference)
+	APIKey string
+
+	// APIKeyCommand is a shell command printing the API key of the account
+	APIKeyCommand string
+}
+
+// PromptLayout controls how the prompt sections are packed in the context window
+type PromptLayout struct {
+	// Budget is the maximum estimated number of tokens of the prompt (0: unlimited)
+	Budget int
+
+	// Order is the order in which the sections appear in the prompt; sections missing
+	// from it are never sent
+	Order []string
+
+	// Priority lists the sections from the most to the least important: when the budget
+	// is tight, the last ones are dropped first
+	Priority []string
+
+	// HeaderFormat is the layout of the commit header the model writes (commit.header_format,
+	// see HeaderLayout; "": type(scope): subject)
+	HeaderFormat string
+
+	// SummaryFirst asks the model which files it needs the diffs of before sending them:
+	// off (default), auto (when the staged diffs exceed the budget) or always
+	SummaryFirst string
+
+	// Diff bounds the staged diff of each file
+	Diff DiffLimits
+
+	// Tokenizer counts the tokens of the prompt: "provider/model" (e.g. openai/gpt-4o),
+	// set from the provider and its model ("": the default tokenizer)
+	Tokenizer string
+}
+
+// DiffLimits bound the per-file diffs read from the repository
+type DiffLimits struct {
+	// Context is the number of unchanged lines shown around each change
+	Context int
+
+	// MaxSize is the character count above which a file diff is truncated (0: unlimited)
+	MaxSize int
 }
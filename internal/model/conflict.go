@@ -0,0 +1,109 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ConflictPreviewLines is the number of lines of each side shown per conflicting hunk
+const ConflictPreviewLines = 5
+
+// Conflict describes an unmerged file from the stages git keeps in the index during a
+// merge: the common ancestor (stage 1), the current branch (ours, stage 2) and the merged
+// branch (theirs, stage 3)
+type Conflict struct {
+	// Kind is how the sides conflict, as git status words it ("both modified", "deleted by them", ...)
+	Kind string
+
+	// Hunks are the conflicting regions, empty when a side deleted the file
+	Hunks []ConflictHunk
+
+	// Binary is true when a stage is binary: no hunks are computed
+	Binary bool
+}
+
+// ConflictHunk is one conflicting region of an unmerged file
+type ConflictHunk struct {
+	// Ours and Theirs are the first lines of each side (at most ConflictPreviewLines)
+	Ours   []string
+	Theirs []string
+
+	// OursLines and TheirsLines are the total line counts of each side
+	OursLines   int
+	TheirsLines int
+}
+
+// ConflictKind returns the kind of conflict of a porcelain status code pair, or "" when
+// the pair is not unmerged
+func ConflictKind(x, y byte) string {
+	switch string([]byte{x, y}) {
+	case "DD":
+		return "both deleted"
+	case "AU":
+		return "added by us"
+	case "UD":
+		return "deleted by them"
+	case "UA":
+		return "added by them"
+	case "DU":
+		return "deleted by us"
+	case "AA":
+		return "both added"
+	case "UU":
+		return "both modified"
+	default:
+		return ""
+	}
+}
+
+// Summary describes the conflict in a few words (e.g. "both modified, 2 conflicting hunks")
+func (c *Conflict) Summary() string {
+	switch {
+	case c.Binary:
+		return c.Kind + ", binary"
+	case len(c.Hunks) == 0:
+		return c.Kind
+	default:
+		return fmt.Sprintf("%s, %d conflicting %s", c.Kind, len(c.Hunks), plural(len(c.Hunks), "hunk", "hunks"))
+	}
+}
+
+// String summarizes the conflict for the AI prompt, with a preview of both sides of each hunk
+func (c *Conflict) String() string {
+	var sb strings.Builder
+	sb.WriteString("Conflict: " + c.Summary() + "\n")
+	for i, hunk := range c.Hunks {
+		writeConflictSide(&sb, i+1, "ours", hunk.Ours, hunk.OursLines)
+		writeConflictSide(&sb, i+1, "theirs", hunk.Theirs, hunk.TheirsLines)
+	}
+	return sb.String()
+}
+
+// Redacted returns a copy of the conflict without the content of its sides
+func (c *Conflict) Redacted() *Conflict {
+	redacted := &Conflict{Kind: c.Kind, Binary: c.Binary}
+	for _, hunk := range c.Hunks {
+		redacted.Hunks = append(redacted.Hunks, ConflictHunk{OursLines: hunk.OursLines, TheirsLines: hunk.TheirsLines})
+	}
+	return redacted
+}
+
+// writeConflictSide writes the preview of one side of a hunk, indented so it never reads
+// as diff lines
+func writeConflictSide(sb *strings.Builder, number int, side string, preview []string, total int) {
+	sb.WriteString(fmt.Sprintf("Hunk %d, %s (%d %s):\n", number, side, total, plural(total, "line", "lines")))
+	for _, line := range preview {
+		sb.WriteString("    " + line + "\n")
+	}
+	if len(preview) > 0 && total > len(preview) {
+		sb.WriteString(fmt.Sprintf("    ... %d more\n", total-len(preview)))
+	}
+}
+
+// plural returns one when n is 1, many otherwise
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
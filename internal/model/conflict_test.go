@@ -0,0 +1,50 @@
+package model
+
+import "testing"
+
+func TestConflictKind(t *testing.T) {
+	tests := []struct {
+		code string
+		want string
+	}{
+		{"UU", "both modified"},
+		{"AA", "both added"},
+		{"DU", "deleted by us"},
+		{"UD", "deleted by them"},
+		{"MM", ""},
+		{"A ", ""},
+	}
+	for _, tt := range tests {
+		if got := ConflictKind(tt.code[0], tt.code[1]); got != tt.want {
+			t.Errorf("ConflictKind(%q) = %q, want %q", tt.code, got, tt.want)
+		}
+	}
+}
+
+func TestConflict_String(t *testing.T) {
+	conflict := &Conflict{Kind: "both modified", Hunks: []ConflictHunk{
+		{Ours: []string{"- a", "b", "c", "d", "e"}, OursLines: 7, Theirs: []string{"x"}, TheirsLines: 1},
+	}}
+
+	want := "Conflict: both modified, 1 conflicting hunk\n" +
+		"Hunk 1, ours (7 lines):\n    - a\n    b\n    c\n    d\n    e\n    ... 2 more\n" +
+		"Hunk 1, theirs (1 line):\n    x\n"
+	if got := conflict.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	redacted := conflict.Redacted()
+	if got, want := redacted.String(), "Conflict: both modified, 1 conflicting hunk\nHunk 1, ours (7 lines):\nHunk 1, theirs (1 line):\n"; got != want {
+		t.Errorf("Redacted().String() = %q, want %q", got, want)
+	}
+	if len(conflict.Hunks[0].Ours) != 5 {
+		t.Error("Redacted() modified the conflict")
+	}
+
+	if got := (&Conflict{Kind: "deleted by them"}).Summary(); got != "deleted by them" {
+		t.Errorf("Summary() = %q, want %q", got, "deleted by them")
+	}
+	if got := (&Conflict{Kind: "both added", Binary: true}).Summary(); got != "both added, binary" {
+		t.Errorf("Summary() = %q, want %q", got, "both added, binary")
+	}
+}
@@ -0,0 +1,74 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ContextPlan reports how the staged changes were packed in the prompt: the files sent
+// with their full diff, and the ones summarized by their line counts to fit the budget
+type ContextPlan struct {
+	// Tokens is the number of tokens of the prompt
+	Tokens int
+
+	// Exact is set when Tokens was counted with the tokenizer of the model (or by the
+	// provider) rather than estimated
+	Exact bool
+
+	// Budget is the token budget the prompt was packed in (0: unlimited)
+	Budget int
+
+	// Included lists the files sent with their full diff, in packing order
+	Included []string
+
+	// Summarized lists the files whose diff was left out, in packing order
+	Summarized []SummarizedFile
+}
+
+// SummarizedFile is a staged file sent with its line counts only
+type SummarizedFile struct {
+	// Path is the file path relative to repository root
+	Path string
+
+	// Kind is the kind of file that ranked it low ("lock file", "generated", ...), empty
+	// for a source file
+	Kind string
+
+	// Tokens is the estimated number of tokens of its diff
+	Tokens int
+}
+
+// String returns "package-lock.json (lock file, ~4200 tokens)"
+func (f SummarizedFile) String() string {
+	if f.Kind == "" {
+		return fmt.Sprintf("%s (~%d tokens)", f.Path, f.Tokens)
+	}
+	return fmt.Sprintf("%s (%s, ~%d tokens)", f.Path, f.Kind, f.Tokens)
+}
+
+// Estimate returns the token count, against the budget when there is one, saying
+// whether it is exact or estimated
+func (p ContextPlan) Estimate() string {
+	accuracy := "estimated"
+	if p.Exact {
+		accuracy = "exact"
+	}
+	if p.Budget > 0 {
+		return fmt.Sprintf("Tokens: %d of %d (%s)", p.Tokens, p.Budget, accuracy)
+	}
+	return fmt.Sprintf("Tokens: %d (%s)", p.Tokens, accuracy)
+}
+
+// Report lists the files summarized to fit the budget, "" when every diff is sent
+func (p ContextPlan) Report() string {
+	if len(p.Summarized) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	total := len(p.Included) + len(p.Summarized)
+	sb.WriteString(fmt.Sprintf("Full diffs: %d of %d files. Sent with line counts only:\n", len(p.Included), total))
+	for _, file := range p.Summarized {
+		sb.WriteString("- " + file.String() + "\n")
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
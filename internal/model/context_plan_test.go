@@ -0,0 +1,25 @@
+package model
+
+import "testing"
+
+func TestContextPlan_Report(t *testing.T) {
+	plan := ContextPlan{Tokens: 15800, Budget: 16000, Included: []string{"handler.go", "README.md"}}
+	if got := plan.Estimate(); got != "Tokens: 15800 of 16000 (estimated)" {
+		t.Errorf("Estimate() = %q", got)
+	}
+	if got := plan.Report(); got != "" {
+		t.Errorf("Report() = %q, want empty when every diff is sent", got)
+	}
+
+	plan.Summarized = []SummarizedFile{{Path: "package-lock.json", Kind: "lock file", Tokens: 4200}, {Path: "big.go", Tokens: 9000}}
+	want := "Full diffs: 2 of 4 files. Sent with line counts only:\n- package-lock.json (lock file, ~4200 tokens)\n- big.go (~9000 tokens)"
+	if got := plan.Report(); got != want {
+		t.Errorf("Report() = %q, want %q", got, want)
+	}
+	if got := (ContextPlan{Tokens: 42}).Estimate(); got != "Tokens: 42 (estimated)" {
+		t.Errorf("Estimate() without budget = %q", got)
+	}
+	if got := (ContextPlan{Tokens: 42, Exact: true}).Estimate(); got != "Tokens: 42 (exact)" {
+		t.Errorf("Estimate() of an exact count = %q", got)
+	}
+}
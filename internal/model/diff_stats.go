@@ -0,0 +1,13 @@
+package model
+
+// DiffStats summarizes the size of a change
+type DiffStats struct {
+	// FilesChanged is the number of changed files (binary files included)
+	FilesChanged int
+
+	// LinesAdded is the number of added lines (binary files count as zero)
+	LinesAdded int
+
+	// LinesRemoved is the number of removed lines (binary files count as zero)
+	LinesRemoved int
+}
@@ -0,0 +1,11 @@
+package model
+
+// FileGroup is a set of staged files committed together when splitting changes into
+// several commits
+type FileGroup struct {
+	// Name describes the group in the split plan (e.g. "internal/ui" or "docs")
+	Name string
+
+	// Files are the paths of the files of the group
+	Files []string
+}
@@ -0,0 +1,72 @@
+package model
+
+import (
+	"regexp"
+	"strings"
+)
+
+// BreakingChangeToken is the footer token describing a breaking change
+const BreakingChangeToken = "BREAKING CHANGE"
+
+// footerLine matches a "Key: value" footer line; BREAKING CHANGE is the only key with a
+// space, and BREAKING-CHANGE its synonym
+var footerLine = regexp.MustCompile(`^(BREAKING[ -]CHANGE|[A-Za-z][A-Za-z0-9-]*): (.*)$`)
+
+// trailerKey matches the key of a trailer
+var trailerKey = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9-]*$`)
+
+// Trailer is a "Key: value" footer of a commit message, such as "Refs: #123"
+type Trailer struct {
+	Key   string
+	Value string
+}
+
+// String returns the footer line of the trailer
+func (t Trailer) String() string {
+	return t.Key + ": " + t.Value
+}
+
+// Valid reports whether the trailer can be read back from the message: a key of letters,
+// digits and hyphens, and a value
+func (t Trailer) Valid() bool {
+	return trailerKey.MatchString(t.Key) && strings.TrimSpace(t.Value) != ""
+}
+
+// ParseFooter splits footer text into the description of a BREAKING CHANGE footer, the
+// "Key: value" trailers, and the other lines, kept as text. Indented lines continue the
+// footer above them.
+func ParseFooter(footer string) (breaking string, trailers []Trailer, text string) {
+	var other []string
+	// last is the footer continued by indented lines: breaking, a trailer or text
+	last := ""
+	for _, line := range strings.Split(footer, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		if line[0] == ' ' || line[0] == '\t' {
+			switch last {
+			case "breaking":
+				breaking += "\n" + line
+				continue
+			case "trailer":
+				trailers[len(trailers)-1].Value += "\n" + line
+				continue
+			}
+		}
+
+		match := footerLine.FindStringSubmatch(line)
+		switch {
+		case match == nil || strings.TrimSpace(match[2]) == "":
+			other = append(other, line)
+			last = "text"
+		case strings.HasPrefix(match[1], "BREAKING"):
+			breaking = strings.TrimSpace(match[2])
+			last = "breaking"
+		default:
+			trailers = append(trailers, Trailer{Key: match[1], Value: strings.TrimSpace(match[2])})
+			last = "trailer"
+		}
+	}
+	return breaking, trailers, strings.Join(other, "\n")
+}
@@ -0,0 +1,152 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// DefaultHeaderFormat is the Conventional Commits header: type(scope): subject
+const DefaultHeaderFormat = "{type}({scope}): {subject}"
+
+// headerPlaceholders matches the placeholders of a header format; "({scope})" is one
+// placeholder so that its parentheses are dropped with an empty scope
+var headerPlaceholders = regexp.MustCompile(`\(\{scope\}\)|\{[a-z]*\}`)
+
+// headerPlaceholderPatterns are the regular expressions matching each placeholder
+var headerPlaceholderPatterns = map[string]string{
+	"{type}":    `(?P<type>[a-zA-Z]+)`,
+	"({scope})": `(?:\((?P<scope>[^()]+)\))?`,
+	"{scope}":   `(?P<scope>[a-zA-Z0-9_-]*)`,
+	"{ticket}":  `(?P<ticket>[^\s\[\](){}:]+)`,
+	"{subject}": `(?P<subject>\S.*?)`,
+}
+
+// HeaderLayout is the shape of the first line of commit messages (commit.header_format),
+// written with the {type}, {scope}, {ticket} and {subject} placeholders, for instance
+// "[{ticket}] {type}({scope}): {subject}". The formatter renders it, the validator and
+// the AI answer parsing match headers against it.
+type HeaderLayout struct {
+	format  string
+	pattern *regexp.Regexp
+
+	// breakingAfter is the placeholder followed by "!" in breaking change headers: the
+	// last of {type} and {scope}
+	breakingAfter string
+}
+
+// ParseHeaderLayout parses a header format. Each placeholder appears at most once, and
+// {type} and {subject} are required.
+func ParseHeaderLayout(format string) (*HeaderLayout, error) {
+	locs := headerPlaceholders.FindAllStringIndex(format, -1)
+	breakingAfter := ""
+	for _, loc := range locs {
+		if placeholder := format[loc[0]:loc[1]]; placeholder == "{type}" || strings.Contains(placeholder, "{scope}") {
+			breakingAfter = placeholder
+		}
+	}
+
+	var pattern strings.Builder
+	pattern.WriteString("^")
+	seen := make(map[string]bool)
+	last := 0
+	for _, loc := range locs {
+		placeholder := format[loc[0]:loc[1]]
+		expr, ok := headerPlaceholderPatterns[placeholder]
+		if !ok {
+			return nil, fmt.Errorf("unknown placeholder %s (want {type}, {scope}, {ticket} or {subject})", placeholder)
+		}
+		name := strings.Trim(placeholder, "()")
+		if seen[name] {
+			return nil, fmt.Errorf("placeholder %s used more than once", name)
+		}
+		seen[name] = true
+		pattern.WriteString(regexp.QuoteMeta(format[last:loc[0]]))
+		pattern.WriteString(expr)
+		if placeholder == breakingAfter {
+			pattern.WriteString(`(?P<breaking>!)?`)
+		}
+		last = loc[1]
+	}
+	pattern.WriteString(regexp.QuoteMeta(format[last:]))
+	pattern.WriteString("$")
+
+	if !seen["{type}"] || !seen["{subject}"] {
+		return nil, fmt.Errorf("header format %q must contain {type} and {subject}", format)
+	}
+	return &HeaderLayout{format: format, pattern: regexp.MustCompile(pattern.String()), breakingAfter: breakingAfter}, nil
+}
+
+// String returns the header format
+func (l *HeaderLayout) String() string {
+	if l == nil {
+		return DefaultHeaderFormat
+	}
+	return l.format
+}
+
+// HasTicket reports whether headers carry a ticket
+func (l *HeaderLayout) HasTicket() bool {
+	return l != nil && strings.Contains(l.format, "{ticket}")
+}
+
+// Render returns the header of message in this layout, with "!" after the type and scope
+// of breaking changes
+func (l *HeaderLayout) Render(message *CommitMessage) string {
+	return headerPlaceholders.ReplaceAllStringFunc(l.format, func(placeholder string) string {
+		if message.Breaking && placeholder == l.breakingAfter {
+			return renderPlaceholder(placeholder, message) + "!"
+		}
+		return renderPlaceholder(placeholder, message)
+	})
+}
+
+// renderPlaceholder returns the value of placeholder for message
+func renderPlaceholder(placeholder string, message *CommitMessage) string {
+	switch placeholder {
+	case "{type}":
+		return message.Type
+	case "({scope})":
+		if message.Scope == "" {
+			return ""
+		}
+		return "(" + message.Scope + ")"
+	case "{scope}":
+		return message.Scope
+	case "{ticket}":
+		return message.Ticket
+	default:
+		return message.Subject
+	}
+}
+
+// Template returns the format with its placeholders written as words, as shown to the
+// AI model: "[TICKET] type(scope): subject"
+func (l *HeaderLayout) Template() string {
+	return strings.NewReplacer("{type}", "type", "{scope}", "scope", "{ticket}", "TICKET", "{subject}", "subject").Replace(l.String())
+}
+
+// Match parses header in this layout, returning a message with its type, scope, ticket,
+// subject and breaking change mark, or false when header does not follow the layout
+func (l *HeaderLayout) Match(header string) (*CommitMessage, bool) {
+	match := l.pattern.FindStringSubmatch(header)
+	if match == nil {
+		return nil, false
+	}
+	message := &CommitMessage{Layout: l}
+	for i, name := range l.pattern.SubexpNames() {
+		switch name {
+		case "type":
+			message.Type = match[i]
+		case "scope":
+			message.Scope = strings.TrimSpace(match[i])
+		case "ticket":
+			message.Ticket = match[i]
+		case "subject":
+			message.Subject = strings.TrimSpace(match[i])
+		case "breaking":
+			message.Breaking = match[i] != ""
+		}
+	}
+	return message, true
+}
@@ -0,0 +1,112 @@
+package model
+
+import "testing"
+
+func TestParseHeaderLayout(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		wantErr bool
+	}{
+		{name: "default", format: DefaultHeaderFormat},
+		{name: "ticket first", format: "[{ticket}] {type}({scope}): {subject}"},
+		{name: "ticket before subject", format: "{type}({scope}): {ticket} {subject}"},
+		{name: "bare scope", format: "{type}/{scope}: {subject}"},
+		{name: "missing subject", format: "{type}({scope}):", wantErr: true},
+		{name: "missing type", format: "[{ticket}] {subject}", wantErr: true},
+		{name: "unknown placeholder", format: "{kind}: {type} {subject}", wantErr: true},
+		{name: "repeated placeholder", format: "{type}: {subject} {subject}", wantErr: true},
+		{name: "scope twice", format: "{type}({scope}) {scope}: {subject}", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseHeaderLayout(tt.format)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseHeaderLayout(%q) error = %v, wantErr %v", tt.format, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHeaderLayout_RenderAndMatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		message CommitMessage
+		header  string
+	}{
+		{
+			name:    "default",
+			format:  DefaultHeaderFormat,
+			message: CommitMessage{Type: "feat", Scope: "api", Subject: "add health endpoint"},
+			header:  "feat(api): add health endpoint",
+		},
+		{
+			name:    "default without scope",
+			format:  DefaultHeaderFormat,
+			message: CommitMessage{Type: "fix", Subject: "handle nil config"},
+			header:  "fix: handle nil config",
+		},
+		{
+			name:    "ticket first",
+			format:  "[{ticket}] {type}({scope}): {subject}",
+			message: CommitMessage{Type: "feat", Scope: "auth", Ticket: "PROJ-123", Subject: "add login"},
+			header:  "[PROJ-123] feat(auth): add login",
+		},
+		{
+			name:    "ticket first without scope",
+			format:  "[{ticket}] {type}({scope}): {subject}",
+			message: CommitMessage{Type: "docs", Ticket: "PROJ-9", Subject: "document the setup (local)"},
+			header:  "[PROJ-9] docs: document the setup (local)",
+		},
+		{
+			name:    "ticket before subject",
+			format:  "{type}({scope}): {ticket} {subject}",
+			message: CommitMessage{Type: "fix", Scope: "ui", Ticket: "OPS-7", Subject: "log out on expiry"},
+			header:  "fix(ui): OPS-7 log out on expiry",
+		},
+		{
+			name:    "breaking change",
+			format:  DefaultHeaderFormat,
+			message: CommitMessage{Type: "feat", Scope: "api", Subject: "drop v1 routes", Breaking: true},
+			header:  "feat(api)!: drop v1 routes",
+		},
+		{
+			name:    "breaking change after a bare scope",
+			format:  "[{ticket}] {type}/{scope}: {subject}",
+			message: CommitMessage{Type: "refactor", Scope: "config", Ticket: "OPS-8", Subject: "rename keys", Breaking: true},
+			header:  "[OPS-8] refactor/config!: rename keys",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			layout, err := ParseHeaderLayout(tt.format)
+			if err != nil {
+				t.Fatalf("ParseHeaderLayout() error = %v", err)
+			}
+			message := tt.message
+			message.Layout = layout
+			if got := message.Header(); got != tt.header {
+				t.Errorf("Header() = %q, want %q", got, tt.header)
+			}
+
+			parsed, ok := layout.Match(tt.header)
+			if !ok {
+				t.Fatalf("Match(%q) did not match", tt.header)
+			}
+			if parsed.Type != message.Type || parsed.Scope != message.Scope || parsed.Ticket != message.Ticket || parsed.Subject != message.Subject || parsed.Breaking != message.Breaking {
+				t.Errorf("Match() = %+v, want the fields of %+v", *parsed, message)
+			}
+		})
+	}
+
+	layout, _ := ParseHeaderLayout("[{ticket}] {type}({scope}): {subject}")
+	if _, ok := layout.Match("feat(auth): add login"); ok {
+		t.Error("Match() matched a header without its ticket")
+	}
+	if got := layout.Template(); got != "[TICKET] type(scope): subject" {
+		t.Errorf("Template() = %q", got)
+	}
+}
@@ -0,0 +1,30 @@
+package model
+
+import "strings"
+
+// Hunk is one "@@" section of a file's unified diff
+type Hunk struct {
+	// Header is the "@@ -a,b +c,d @@" line, with the optional section heading
+	Header string
+
+	// Lines are the context (" "), removed ("-") and added ("+") lines of the hunk
+	Lines []string
+}
+
+// String returns the hunk as it appears in the diff
+func (h Hunk) String() string {
+	return h.Header + "\n" + strings.Join(h.Lines, "\n") + "\n"
+}
+
+// Changes returns the number of added and removed lines
+func (h Hunk) Changes() (added, removed int) {
+	for _, line := range h.Lines {
+		switch {
+		case strings.HasPrefix(line, "+"):
+			added++
+		case strings.HasPrefix(line, "-"):
+			removed++
+		}
+	}
+	return added, removed
+}
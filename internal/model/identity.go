@@ -0,0 +1,57 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// identityRegex matches the "Name <email>" form used by Signed-off-by trailers
+var identityRegex = regexp.MustCompile(`^([^<>]+?)\s*<([^<>\s]+)>$`)
+
+// Identity represents a person identity as used in git trailers (e.g. Signed-off-by)
+type Identity struct {
+	// Name is the real name of the person
+	Name string
+
+	// Email is the email address of the person
+	Email string
+}
+
+// ParseIdentity parses and validates an identity in the DCO "Name <email>" format
+func ParseIdentity(s string) (*Identity, error) {
+	matches := identityRegex.FindStringSubmatch(strings.TrimSpace(s))
+	if matches == nil {
+		return nil, fmt.Errorf("invalid identity %q: expected format \"Name <email>\"", s)
+	}
+
+	identity := &Identity{
+		Name:  strings.TrimSpace(matches[1]),
+		Email: matches[2],
+	}
+	if err := identity.Validate(); err != nil {
+		return nil, err
+	}
+	return identity, nil
+}
+
+// Validate checks that the identity can be used in a DCO Signed-off-by trailer
+func (i *Identity) Validate() error {
+	if strings.TrimSpace(i.Name) == "" {
+		return fmt.Errorf("invalid identity: name is required")
+	}
+	if strings.ContainsAny(i.Name, "<>\n") {
+		return fmt.Errorf("invalid identity name %q: must not contain '<', '>' or newlines", i.Name)
+	}
+
+	local, domain, ok := strings.Cut(i.Email, "@")
+	if !ok || local == "" || domain == "" || strings.Contains(domain, "@") || strings.ContainsAny(i.Email, " <>\n") {
+		return fmt.Errorf("invalid identity email %q: must be a valid email address", i.Email)
+	}
+	return nil
+}
+
+// String returns the identity in "Name <email>" format
+func (i *Identity) String() string {
+	return fmt.Sprintf("%s <%s>", i.Name, i.Email)
+}
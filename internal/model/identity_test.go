@@ -0,0 +1,73 @@
+package model
+
+import (
+	"testing"
+)
+
+func TestParseIdentity(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    *Identity
+		wantErr bool
+	}{
+		{
+			name:  "valid identity",
+			input: "Jane Doe <jane.doe@corp.example.com>",
+			want:  &Identity{Name: "Jane Doe", Email: "jane.doe@corp.example.com"},
+		},
+		{
+			name:  "surrounding whitespace is trimmed",
+			input: "  Jane Doe   <jane@example.com>  ",
+			want:  &Identity{Name: "Jane Doe", Email: "jane@example.com"},
+		},
+		{
+			name:    "missing email",
+			input:   "Jane Doe",
+			wantErr: true,
+		},
+		{
+			name:    "missing name",
+			input:   "<jane@example.com>",
+			wantErr: true,
+		},
+		{
+			name:    "email without at sign",
+			input:   "Jane Doe <jane.example.com>",
+			wantErr: true,
+		},
+		{
+			name:    "email without domain",
+			input:   "Jane Doe <jane@>",
+			wantErr: true,
+		},
+		{
+			name:    "trailing text after email",
+			input:   "Jane Doe <jane@example.com> extra",
+			wantErr: true,
+		},
+		{
+			name:    "empty string",
+			input:   "",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseIdentity(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseIdentity() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if *got != *tt.want {
+				t.Errorf("ParseIdentity() = %+v, want %+v", got, tt.want)
+			}
+			if got.String() != tt.want.Name+" <"+tt.want.Email+">" {
+				t.Errorf("Identity.String() = %q", got.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PrivacyLevel controls how much of the repository state is sent to AI providers
+type PrivacyLevel string
+
+const (
+	// PrivacyFullDiff sends file paths, statuses and diffs (default)
+	PrivacyFullDiff PrivacyLevel = "full-diff"
+
+	// PrivacyFilenamesOnly sends file paths and statuses, never file content
+	PrivacyFilenamesOnly PrivacyLevel = "filenames-only"
+
+	// PrivacyStatsOnly sends change counts only: no paths and no content
+	PrivacyStatsOnly PrivacyLevel = "stats-only"
+)
+
+// ParsePrivacyLevel parses a privacy level name; "" is the default full-diff level
+func ParsePrivacyLevel(s string) (PrivacyLevel, error) {
+	switch level := PrivacyLevel(s); level {
+	case "":
+		return PrivacyFullDiff, nil
+	case PrivacyFullDiff, PrivacyFilenamesOnly, PrivacyStatsOnly:
+		return level, nil
+	default:
+		return "", fmt.Errorf("unknown privacy level %q (expected %s, %s or %s)", s, PrivacyFullDiff, PrivacyFilenamesOnly, PrivacyStatsOnly)
+	}
+}
+
+// WithPrivacy returns a copy of the state holding only what level allows to share.
+// The state itself is unchanged, so local analysis can keep using the full diffs.
+func (r *RepositoryState) WithPrivacy(level PrivacyLevel) *RepositoryState {
+	if r == nil || level == "" || level == PrivacyFullDiff {
+		return r
+	}
+
+	redacted := *r
+	redacted.Privacy = level
+	redacted.RawDiff = ""
+	redacted.StagedFiles = redactFiles(r.StagedFiles, level)
+	redacted.UnstagedFiles = redactFiles(r.UnstagedFiles, level)
+	return &redacted
+}
+
+// redactFiles replaces the diffs by their line counts, and drops the paths in stats-only mode
+func redactFiles(files []FileChange, level PrivacyLevel) []FileChange {
+	if files == nil {
+		return nil
+	}
+	redacted := make([]FileChange, len(files))
+	for i, file := range files {
+		added, removed := CountDiffLines(file.Diff)
+		redacted[i] = FileChange{
+			Path:         file.Path,
+			Status:       file.Status,
+			OldPath:      file.OldPath,
+			Similarity:   file.Similarity,
+			LinesAdded:   max(added, file.LinesAdded),
+			LinesRemoved: max(removed, file.LinesRemoved),
+		}
+		// The kind and hunk count of a conflict are kept, not the content of its sides
+		if file.Conflict != nil && level != PrivacyStatsOnly {
+			redacted[i].Conflict = file.Conflict.Redacted()
+		}
+		if level == PrivacyStatsOnly {
+			redacted[i].Path = ""
+			redacted[i].OldPath = ""
+		}
+	}
+	return redacted
+}
+
+// CountDiffLines counts the added and removed lines of a unified diff, ignoring file headers
+func CountDiffLines(diff string) (added, removed int) {
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "+++ "), strings.HasPrefix(line, "--- "):
+			continue
+		case strings.HasPrefix(line, "+"):
+			added++
+		case strings.HasPrefix(line, "-"):
+			removed++
+		}
+	}
+	return added, removed
+}
@@ -0,0 +1,97 @@
+package model
+
+import "testing"
+
+func TestParsePrivacyLevel(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    PrivacyLevel
+		wantErr bool
+	}{
+		{input: "", want: PrivacyFullDiff},
+		{input: "full-diff", want: PrivacyFullDiff},
+		{input: "filenames-only", want: PrivacyFilenamesOnly},
+		{input: "stats-only", want: PrivacyStatsOnly},
+		{input: "paranoid", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParsePrivacyLevel(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePrivacyLevel() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParsePrivacyLevel() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRepositoryState_WithPrivacy(t *testing.T) {
+	state := &RepositoryState{
+		Branch:        "feature/login",
+		RawDiff:       "+secret",
+		StagedFiles:   []FileChange{{Path: "auth/login.go", Status: "modified", Diff: "+token := x"}},
+		UnstagedFiles: []FileChange{{Path: "notes.txt", Status: "added", Diff: "+todo"}},
+	}
+
+	if got := state.WithPrivacy(PrivacyFullDiff); got != state {
+		t.Error("WithPrivacy(full-diff) should return the state unchanged")
+	}
+
+	filenames := state.WithPrivacy(PrivacyFilenamesOnly)
+	if filenames.StagedFiles[0].LinesAdded != 1 || filenames.StagedFiles[0].LinesRemoved != 0 {
+		t.Errorf("filenames-only line counts = %+v, want +1 -0", filenames.StagedFiles[0])
+	}
+	if filenames.RawDiff != "" || filenames.StagedFiles[0].Diff != "" || filenames.UnstagedFiles[0].Diff != "" {
+		t.Errorf("filenames-only state still has content: %+v", filenames)
+	}
+	if filenames.StagedFiles[0].Path != "auth/login.go" || filenames.StagedFiles[0].Status != "modified" {
+		t.Errorf("filenames-only state lost path or status: %+v", filenames.StagedFiles[0])
+	}
+	if filenames.Privacy != PrivacyFilenamesOnly || filenames.Branch != "feature/login" {
+		t.Errorf("filenames-only state = %+v", filenames)
+	}
+
+	stats := state.WithPrivacy(PrivacyStatsOnly)
+	if stats.StagedFiles[0].Path != "" || stats.StagedFiles[0].Status != "modified" || len(stats.UnstagedFiles) != 1 {
+		t.Errorf("stats-only files = %+v", stats.StagedFiles)
+	}
+
+	// The original state is untouched for local analysis
+	if state.StagedFiles[0].Diff == "" || state.RawDiff == "" || state.Privacy != "" {
+		t.Error("WithPrivacy() modified the original state")
+	}
+}
+
+func TestRepositoryState_WithPrivacy_Conflict(t *testing.T) {
+	conflict := &Conflict{Kind: "both modified", Hunks: []ConflictHunk{{Ours: []string{"token := a"}, OursLines: 1, Theirs: []string{"token := b"}, TheirsLines: 1}}}
+	state := &RepositoryState{StagedFiles: []FileChange{{Path: "auth/login.go", Status: "unmerged", Diff: conflict.String(), Conflict: conflict}}}
+
+	filenames := state.WithPrivacy(PrivacyFilenamesOnly).StagedFiles[0]
+	if filenames.Conflict == nil || filenames.Conflict.Summary() != "both modified, 1 conflicting hunk" {
+		t.Fatalf("filenames-only conflict = %+v, want its kind and hunk count", filenames.Conflict)
+	}
+	if filenames.Conflict.Hunks[0].Ours != nil || filenames.Conflict.Hunks[0].Theirs != nil {
+		t.Errorf("filenames-only conflict still has content: %+v", filenames.Conflict.Hunks)
+	}
+	if filenames.LinesAdded != 0 || filenames.LinesRemoved != 0 {
+		t.Errorf("filenames-only conflict line counts = +%d -%d, want none", filenames.LinesAdded, filenames.LinesRemoved)
+	}
+
+	if stats := state.WithPrivacy(PrivacyStatsOnly).StagedFiles[0]; stats.Conflict != nil {
+		t.Errorf("stats-only conflict = %+v, want nil", stats.Conflict)
+	}
+}
+
+func TestCountDiffLines(t *testing.T) {
+	diff := "diff --git a/x.go b/x.go\n--- a/x.go\n+++ b/x.go\n@@ -1,2 +1,3 @@\n-old\n+new\n+more\n context\n"
+	added, removed := CountDiffLines(diff)
+	if added != 2 || removed != 1 {
+		t.Errorf("CountDiffLines() = +%d -%d, want +2 -1", added, removed)
+	}
+	if added, removed := CountDiffLines(""); added != 0 || removed != 0 {
+		t.Errorf("CountDiffLines(\"\") = +%d -%d, want zero", added, removed)
+	}
+}
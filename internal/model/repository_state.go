@@ -1,5 +1,7 @@
 package model
 
+import "fmt"
+
 // RepositoryState represents the current state of the git repository for commit message generation
 type RepositoryState struct {
 	// StagedFiles is the list of staged file changes
@@ -11,6 +13,29 @@ type RepositoryState struct {
 	// RawDiff is the condensed diff output from rtk (when rtk is active).
 	// When non-empty, this replaces per-file FileChange.Diff for AI prompt generation.
 	RawDiff string
+
+	// Branch is the current branch name (empty when HEAD is detached)
+	Branch string
+
+	// Ticket is the ticket reference found in the branch name (e.g. JIRA-1234 in
+	// feature/JIRA-1234-add-login), empty when branch tickets are disabled or none matches
+	Ticket string
+
+	// Upstream is the upstream tracking branch (e.g. "origin/main", empty if none)
+	Upstream string
+
+	// Ahead and Behind are the commit counts relative to Upstream
+	Ahead  int
+	Behind int
+
+	// LastCommitSubject is the subject of the HEAD commit (empty in a new repository)
+	LastCommitSubject string
+
+	// RelatedCommit is the author's previous commit on the same files (only with session context)
+	RelatedCommit *CommitInfo
+
+	// Privacy is the level the state was reduced to before being shared ("" for the full state)
+	Privacy PrivacyLevel
 }
 
 // FileChange represents a single file change in the repository
@@ -18,11 +43,59 @@ type FileChange struct {
 	// Path is the file path relative to repository root
 	Path string
 
-	// Status is the change status (added, modified, deleted, renamed)
+	// Status is the change status (added, modified, deleted, renamed, copied, unmerged)
 	Status string
 
+	// OldPath is the path the file was renamed or copied from (empty otherwise)
+	OldPath string
+
+	// Similarity is the percentage of content kept from OldPath, 0 when unknown
+	Similarity int
+
 	// Diff is the optional unified diff content for the change
 	Diff string
+
+	// LinesAdded and LinesRemoved count the changed lines when Diff is withheld (privacy levels)
+	LinesAdded   int
+	LinesRemoved int
+
+	// Conflict describes the conflict of an unmerged file (nil otherwise)
+	Conflict *Conflict
+}
+
+// DisplayPath returns the path of the file, as "old → new" for renames and copies
+func (f FileChange) DisplayPath() string {
+	if f.OldPath == "" || f.OldPath == f.Path {
+		return f.Path
+	}
+	return f.OldPath + " → " + f.Path
+}
+
+// StatusDetail returns the status with the similarity of renames and copies, e.g.
+// "renamed, 87% similar"
+func (f FileChange) StatusDetail() string {
+	if f.OldPath == "" || f.Similarity == 0 {
+		return f.Status
+	}
+	return fmt.Sprintf("%s, %d%% similar", f.Status, f.Similarity)
+}
+
+// TrackingStatus returns a short description of the upstream tracking status
+// (e.g. "ahead 1, behind 2 of origin/main"), or "" when there is no upstream
+func (r *RepositoryState) TrackingStatus() string {
+	if r.Upstream == "" {
+		return ""
+	}
+	switch {
+	case r.Ahead > 0 && r.Behind > 0:
+		return fmt.Sprintf("ahead %d, behind %d of %s", r.Ahead, r.Behind, r.Upstream)
+	case r.Ahead > 0:
+		return fmt.Sprintf("ahead %d of %s", r.Ahead, r.Upstream)
+	case r.Behind > 0:
+		return fmt.Sprintf("behind %d of %s", r.Behind, r.Upstream)
+	default:
+		return "up to date with " + r.Upstream
+	}
 }
 
 // IsEmpty returns true if there are no staged or unstaged changes
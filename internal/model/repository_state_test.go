@@ -0,0 +1,52 @@
+package model
+
+import (
+	"testing"
+)
+
+func TestRepositoryState_TrackingStatus(t *testing.T) {
+	tests := []struct {
+		name  string
+		state RepositoryState
+		want  string
+	}{
+		{name: "no upstream", state: RepositoryState{Branch: "main", Ahead: 3}, want: ""},
+		{name: "up to date", state: RepositoryState{Upstream: "origin/main"}, want: "up to date with origin/main"},
+		{name: "ahead", state: RepositoryState{Upstream: "origin/main", Ahead: 2}, want: "ahead 2 of origin/main"},
+		{name: "behind", state: RepositoryState{Upstream: "origin/main", Behind: 1}, want: "behind 1 of origin/main"},
+		{name: "diverged", state: RepositoryState{Upstream: "origin/main", Ahead: 2, Behind: 1}, want: "ahead 2, behind 1 of origin/main"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.state.TrackingStatus(); got != tt.want {
+				t.Errorf("TrackingStatus() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFileChange_DisplayPathAndStatusDetail(t *testing.T) {
+	tests := []struct {
+		name       string
+		file       FileChange
+		wantPath   string
+		wantDetail string
+	}{
+		{name: "modified", file: FileChange{Path: "main.go", Status: "modified"}, wantPath: "main.go", wantDetail: "modified"},
+		{name: "renamed", file: FileChange{Path: "new.txt", Status: "renamed", OldPath: "old.txt", Similarity: 87}, wantPath: "old.txt → new.txt", wantDetail: "renamed, 87% similar"},
+		{name: "copied", file: FileChange{Path: "b.go", Status: "copied", OldPath: "a.go", Similarity: 100}, wantPath: "a.go → b.go", wantDetail: "copied, 100% similar"},
+		{name: "similarity unknown", file: FileChange{Path: "new.txt", Status: "renamed", OldPath: "old.txt"}, wantPath: "old.txt → new.txt", wantDetail: "renamed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.file.DisplayPath(); got != tt.wantPath {
+				t.Errorf("DisplayPath() = %q, want %q", got, tt.wantPath)
+			}
+			if got := tt.file.StatusDetail(); got != tt.wantDetail {
+				t.Errorf("StatusDetail() = %q, want %q", got, tt.wantDetail)
+			}
+		})
+	}
+}
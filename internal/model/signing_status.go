@@ -0,0 +1,32 @@
+package model
+
+// SigningStatus describes how commits are signed, as checked by gitcomm doctor
+type SigningStatus struct {
+	// Format is the signing format: "ssh" or "openpgp"
+	Format string
+
+	// Key is the signing key (user.signingkey): a public key path or "key::" literal for
+	// SSH, a key ID or fingerprint for OpenPGP
+	Key string
+
+	// Enabled is true when commits are signed (false with --no-sign)
+	Enabled bool
+
+	// Agent is true when the SSH key is loaded in ssh-agent; otherwise git signs with the
+	// private key file
+	Agent bool
+
+	// Program is the signing program set by gpg.program or gpg.ssh.program ("" for the
+	// default)
+	Program string
+
+	// AllowedSignersFile is gpg.ssh.allowedSignersFile, used by git to verify SSH signatures
+	AllowedSignersFile string
+
+	// Principals are the identities the allowed signers file trusts the SSH key for
+	Principals []string
+
+	// IdentityWarning explains why signatures would not be verified for user.email (empty
+	// when the key is trusted for it, or without an allowed signers file)
+	IdentityWarning string
+}
@@ -0,0 +1,36 @@
+package model
+
+import "time"
+
+// WorkspaceSummary is the state of the worktree shown before any prompt, so users can
+// check they are in the right repository and on the right branch
+type WorkspaceSummary struct {
+	// Root is the top-level directory of the worktree
+	Root string
+
+	// Branch is the current branch name (empty when HEAD is detached)
+	Branch string
+
+	// Upstream is the upstream tracking branch (e.g. "origin/main", empty if none)
+	Upstream string
+
+	// Ahead and Behind are the commit counts relative to Upstream
+	Ahead  int
+	Behind int
+
+	// Staged, Modified and Untracked count the files with staged changes, with unstaged
+	// changes to tracked files, and the untracked files
+	Staged    int
+	Modified  int
+	Untracked int
+
+	// LastCommit is the committer date of HEAD (zero in a new repository)
+	LastCommit time.Time
+}
+
+// TrackingStatus returns a short description of the upstream tracking status, or "" when
+// there is no upstream (see RepositoryState.TrackingStatus)
+func (w *WorkspaceSummary) TrackingStatus() string {
+	state := RepositoryState{Upstream: w.Upstream, Ahead: w.Ahead, Behind: w.Behind}
+	return state.TrackingStatus()
+}
@@ -0,0 +1,95 @@
+// Package queue stores commits created with a placeholder message while the AI provider
+// was unreachable, so their messages can be regenerated later.
+package queue
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// FileName is the queue file name, relative to the git directory
+const FileName = "gitcomm/queue.json"
+
+// Entry is a queued commit awaiting a generated message
+type Entry struct {
+	// Hash is the full hash of the placeholder commit
+	Hash string `json:"hash"`
+
+	// Branch is the branch the commit was created on
+	Branch string `json:"branch,omitempty"`
+
+	// QueuedAt is when the commit was queued
+	QueuedAt time.Time `json:"queued_at"`
+}
+
+// Store persists the queue as JSON in the git directory
+type Store struct {
+	path string
+}
+
+// NewStore creates a store for the repository whose git directory is gitDir
+func NewStore(gitDir string) *Store {
+	return &Store{path: filepath.Join(gitDir, FileName)}
+}
+
+// Path returns the queue file path
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Load returns the queued entries, oldest first (empty if there is no queue file)
+func (s *Store) Load() ([]Entry, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read commit queue: %w", err)
+	}
+
+	var entries []Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse commit queue %s: %w", s.path, err)
+	}
+	return entries, nil
+}
+
+// Save replaces the queue with entries; an empty queue removes the file
+func (s *Store) Save(entries []Entry) error {
+	if len(entries) == 0 {
+		if err := os.Remove(s.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove commit queue: %w", err)
+		}
+		return nil
+	}
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode commit queue: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create queue directory: %w", err)
+	}
+	// Write atomically so an interrupted save never truncates the queue
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write commit queue: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to write commit queue: %w", err)
+	}
+	return nil
+}
+
+// Add appends entry to the queue
+func (s *Store) Add(entry Entry) error {
+	entries, err := s.Load()
+	if err != nil {
+		return err
+	}
+	return s.Save(append(entries, entry))
+}
@@ -0,0 +1,54 @@
+package queue
+
+import (
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestStore_AddLoadSave(t *testing.T) {
+	store := NewStore(t.TempDir())
+
+	entries, err := store.Load()
+	if err != nil || len(entries) != 0 {
+		t.Fatalf("Load() on missing file = %v, %v; want empty, nil", entries, err)
+	}
+
+	first := Entry{Hash: "aaaa", Branch: "main", QueuedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)}
+	second := Entry{Hash: "bbbb", Branch: "main", QueuedAt: time.Date(2026, 1, 2, 4, 0, 0, 0, time.UTC)}
+	for _, e := range []Entry{first, second} {
+		if err := store.Add(e); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+	}
+
+	entries, err = store.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !reflect.DeepEqual(entries, []Entry{first, second}) {
+		t.Errorf("Load() = %+v, want both entries in order", entries)
+	}
+
+	if err := store.Save(nil); err != nil {
+		t.Fatalf("Save(nil) error = %v", err)
+	}
+	if _, err := os.Stat(store.Path()); !os.IsNotExist(err) {
+		t.Errorf("Save(nil) should remove the queue file, stat error = %v", err)
+	}
+}
+
+func TestStore_LoadInvalid(t *testing.T) {
+	store := NewStore(t.TempDir())
+	if err := store.Save([]Entry{{Hash: "aaaa"}}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if err := os.WriteFile(store.Path(), []byte("not json"), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	if _, err := store.Load(); err == nil {
+		t.Error("Load() with invalid content should return an error")
+	}
+}
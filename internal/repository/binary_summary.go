@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"image"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	// Image formats whose dimensions binary summaries report
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/utils"
+)
+
+// maxImageBlobSize is the size above which the dimensions of an image are not read
+const maxImageBlobSize = 8 << 20
+
+// imageExtensions are the extensions of the images whose dimensions are read
+var imageExtensions = map[string]bool{".png": true, ".jpg": true, ".jpeg": true, ".gif": true}
+
+// diffAttributes returns the diff attribute of paths from .gitattributes: "set", "unset"
+// (-diff, binary), "unspecified" or the name of a diff driver
+func (r *gitRepositoryImpl) diffAttributes(ctx context.Context, paths []string) map[string]string {
+	attributes := make(map[string]string)
+	if len(paths) == 0 {
+		return attributes
+	}
+	args := append([]string{"check-attr", "-z", "diff", "--"}, paths...)
+	out, _, err := r.runGitCommand(ctx, r.gitBin, false, args...)
+	if err != nil {
+		utils.Logger.Debug().Err(err).Msg("Failed to read diff attributes")
+		return attributes
+	}
+	// -z output is made of path, attribute and value fields
+	fields := strings.Split(out, "\x00")
+	for i := 0; i+2 < len(fields); i += 3 {
+		attributes[fields[i]] = fields[i+2]
+	}
+	return attributes
+}
+
+// isBinaryChange reports whether the change of path is summarized instead of diffed. A diff
+// attribute decides when set in .gitattributes: -diff and binary files are binary, and a
+// diff driver shows the diff git produced with its textconv, binary only when git found it
+// binary. Without attribute, the diff git produced from the staged blob and the extension
+// decide.
+func isBinaryChange(path, attribute string, binaryDiff bool) bool {
+	switch attribute {
+	case "unset":
+		return true
+	case "unspecified", "":
+		return binaryDiff || hasBinaryExtension(path)
+	default:
+		return binaryDiff
+	}
+}
+
+// binarySummary describes the change of a binary file in place of its diff: its type, the
+// sizes before and after, and the dimensions of PNG, JPEG and GIF images. oldRev and newRev
+// hold the two versions of the file, newRev "" standing for the index.
+func (r *gitRepositoryImpl) binarySummary(ctx context.Context, file model.FileChange, oldRev, newRev string) string {
+	// Renamed and copied files come from their old path
+	oldObject, newObject := oldRev+":"+cmp.Or(file.OldPath, file.Path), newRev+":"+file.Path
+	oldSize, newSize := r.blobSize(ctx, oldObject), r.blobSize(ctx, newObject)
+
+	lines := []string{
+		"file: " + file.DisplayPath() + " (binary)",
+		"type: " + binaryType(file.Path),
+		"size: " + describeChange(formatBlobSize(oldSize), formatBlobSize(newSize)),
+	}
+	if imageExtensions[strings.ToLower(filepath.Ext(file.Path))] {
+		dimensions := describeChange(r.imageDimensions(ctx, oldObject, oldSize), r.imageDimensions(ctx, newObject, newSize))
+		if dimensions != "" {
+			lines = append(lines, "dimensions: "+dimensions)
+		}
+	}
+	lines = append(lines, "changes: "+file.StatusDetail())
+	return strings.Join(lines, "\n")
+}
+
+// blobSize returns the size of object in bytes, -1 when it does not exist (added or
+// deleted files)
+func (r *gitRepositoryImpl) blobSize(ctx context.Context, object string) int64 {
+	out, _, err := r.runGitCommand(ctx, r.gitBin, false, "cat-file", "-s", object)
+	if err != nil {
+		return -1
+	}
+	size, err := strconv.ParseInt(strings.TrimSpace(out), 10, 64)
+	if err != nil {
+		return -1
+	}
+	return size
+}
+
+// imageDimensions returns the "<width>x<height>" of the image object of size bytes, ""
+// when it does not exist, is too large to be read or cannot be decoded
+func (r *gitRepositoryImpl) imageDimensions(ctx context.Context, object string, size int64) string {
+	if size < 0 || size > maxImageBlobSize {
+		return ""
+	}
+	out, _, err := r.runGitCommand(ctx, r.gitBin, false, "cat-file", "blob", object)
+	if err != nil {
+		return ""
+	}
+	config, _, err := image.DecodeConfig(strings.NewReader(out))
+	if err != nil {
+		utils.Logger.Debug().Err(err).Str("object", object).Msg("Failed to read image dimensions")
+		return ""
+	}
+	return fmt.Sprintf("%dx%d", config.Width, config.Height)
+}
+
+// binaryType names the type of a binary file from its extension, e.g. "png image"
+func binaryType(path string) string {
+	ext := strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	switch {
+	case ext == "":
+		return "binary file"
+	case imageExtensions["."+ext]:
+		return ext + " image"
+	default:
+		return ext + " file"
+	}
+}
+
+// formatBlobSize returns size in bytes, "" for a missing blob
+func formatBlobSize(size int64) string {
+	if size < 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d bytes", size)
+}
+
+// describeChange returns "old -> new", or the single value when the other is missing or
+// both are equal
+func describeChange(oldValue, newValue string) string {
+	switch {
+	case oldValue == "":
+		return newValue
+	case newValue == "":
+		return oldValue + " (removed)"
+	case oldValue == newValue:
+		return newValue
+	default:
+		return oldValue + " -> " + newValue
+	}
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/golgoth31/gitcomm/pkg/testutil"
+)
+
+// pngImage returns a blank PNG image of width x height pixels
+func pngImage(t *testing.T, width, height int) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, width, height))); err != nil {
+		t.Fatalf("failed to encode image: %v", err)
+	}
+	return buf.String()
+}
+
+func TestGetRepositoryState_SummarizesBinaryFiles(t *testing.T) {
+	fixture := testutil.NewRepoWithCommit(t, map[string]string{
+		".gitattributes": "*.docx diff=plain\n*.lock -diff\n",
+		"logo.png":       pngImage(t, 16, 16),
+		"report.docx":    "Quarterly report\n",
+		"deps.lock":      "a 1\n",
+		"old.bin":        "\x00\x01\x02",
+	})
+	fixture.Git("config", "diff.plain.textconv", "cat")
+	fixture.WriteFile("logo.png", pngImage(t, 32, 24))
+	fixture.WriteFile("icon.gif", "GIF89a\x08\x00\x04\x00\x00\x00\x00;")
+	fixture.WriteFile("report.docx", "Quarterly report\nRevenue grew.\n")
+	fixture.WriteFile("deps.lock", "a 2\n")
+	fixture.RemoveFile("old.bin")
+	fixture.Git("add", "-A")
+
+	repo, err := NewGitRepository(fixture.Dir, true, true)
+	if err != nil {
+		t.Fatalf("NewGitRepository() error = %v", err)
+	}
+	state, err := repo.GetRepositoryState(context.Background())
+	if err != nil {
+		t.Fatalf("GetRepositoryState() error = %v", err)
+	}
+	diffs := make(map[string]string)
+	for _, file := range state.StagedFiles {
+		diffs[file.Path] = file.Diff
+	}
+
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{path: "logo.png", want: []string{"file: logo.png (binary)", "type: png image", "size: ", " bytes -> ", "dimensions: 16x16 -> 32x24", "changes: modified"}},
+		{path: "icon.gif", want: []string{"type: gif image", "dimensions: 8x4", "changes: added"}},
+		{path: "old.bin", want: []string{"type: bin file", "size: 3 bytes (removed)", "changes: deleted"}},
+		// -diff files are binary whatever their content
+		{path: "deps.lock", want: []string{"file: deps.lock (binary)", "size: 4 bytes"}},
+		// A diff driver gives the text diff despite the binary extension
+		{path: "report.docx", want: []string{"+Revenue grew."}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			diff, ok := diffs[tt.path]
+			if !ok {
+				t.Fatalf("%s not staged: %v", tt.path, state.StagedFiles)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(diff, want) {
+					t.Errorf("diff of %s = %q, want it to contain %q", tt.path, diff, want)
+				}
+			}
+		})
+	}
+}
+
+func TestGetCommitState_SummarizesBinaryFiles(t *testing.T) {
+	fixture := testutil.NewRepoWithCommit(t, map[string]string{"logo.png": pngImage(t, 16, 16)})
+	commit := fixture.CommitFiles("feat: enlarge logo", map[string]string{"logo.png": pngImage(t, 64, 64)})
+
+	repo, err := NewGitRepository(fixture.Dir, true, true)
+	if err != nil {
+		t.Fatalf("NewGitRepository() error = %v", err)
+	}
+	state, err := repo.GetCommitState(context.Background(), commit)
+	if err != nil {
+		t.Fatalf("GetCommitState() error = %v", err)
+	}
+	if len(state.StagedFiles) != 1 || !strings.Contains(state.StagedFiles[0].Diff, "dimensions: 16x16 -> 64x64") {
+		t.Errorf("StagedFiles = %+v, want the logo summarized with its dimensions", state.StagedFiles)
+	}
+}
+
+func TestDescribeChange(t *testing.T) {
+	tests := []struct {
+		oldValue, newValue, want string
+	}{
+		{oldValue: "", newValue: "10 bytes", want: "10 bytes"},
+		{oldValue: "10 bytes", newValue: "", want: "10 bytes (removed)"},
+		{oldValue: "10 bytes", newValue: "10 bytes", want: "10 bytes"},
+		{oldValue: "10 bytes", newValue: "12 bytes", want: "10 bytes -> 12 bytes"},
+		{oldValue: "", newValue: "", want: ""},
+	}
+	for _, tt := range tests {
+		if got := describeChange(tt.oldValue, tt.newValue); got != tt.want {
+			t.Errorf("describeChange(%q, %q) = %q, want %q", tt.oldValue, tt.newValue, got, tt.want)
+		}
+	}
+}
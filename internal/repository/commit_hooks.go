@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/utils"
+)
+
+// Commit hooks of git commit, run by gitcomm for the commits it creates with plumbing
+// commands (--branch, --patch-only); git commit runs them itself otherwise
+const (
+	hookPreCommit        = "pre-commit"
+	hookPrepareCommitMsg = "prepare-commit-msg"
+	hookCommitMsg        = "commit-msg"
+)
+
+// HooksDir returns the absolute path of the hooks directory: core.hooksPath when set,
+// $GIT_DIR/hooks otherwise
+func (r *gitRepositoryImpl) HooksDir(ctx context.Context) (string, error) {
+	out, _, err := r.runGitCommand(ctx, r.gitBin, false, "rev-parse", "--path-format=absolute", "--git-path", "hooks")
+	if err != nil {
+		return "", fmt.Errorf("failed to locate hooks directory: %w", err)
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// runCommitHook runs the hook name of dir with args like git does: from the top of the
+// worktree, with the hook output on stderr and without stdin. A missing or non-executable
+// hook is skipped; a hook exiting with an error status aborts the commit.
+func (r *gitRepositoryImpl) runCommitHook(ctx context.Context, env []string, dir, name string, args ...string) error {
+	path := filepath.Join(dir, name)
+	info, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s hook: %w", name, err)
+	}
+	if info.IsDir() || (runtime.GOOS != "windows" && info.Mode().Perm()&0111 == 0) {
+		utils.Logger.Debug().Str("hook", path).Msg("Hook ignored: not executable")
+		return nil
+	}
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		// Hooks are shell scripts, run by the sh of Git for Windows
+		cmd = exec.CommandContext(ctx, "sh", append([]string{path}, args...)...)
+	} else {
+		cmd = exec.CommandContext(ctx, path, args...)
+	}
+	if top, err := r.WorkTreeDir(ctx); err == nil {
+		cmd.Dir = top
+	}
+	// GIT_EDITOR=: tells hooks that no editor is opened, like git commit -m
+	cmd.Env = append(r.withIndex(env), "GIT_EDITOR=:")
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctxErr := contextError(ctx, name); ctxErr != nil {
+			return ctxErr
+		}
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return fmt.Errorf("%w: %s exited with status %d", ErrGitHookFailed, name, exitErr.ExitCode())
+		}
+		return fmt.Errorf("%w: %s: %v", ErrGitHookFailed, name, err)
+	}
+	return nil
+}
+
+// hookedMessage runs the hooks git commit runs before a commit of message: pre-commit,
+// then prepare-commit-msg and commit-msg on the message file. Returns the message as the
+// hooks left it. With message.NoVerify, pre-commit and commit-msg are skipped like with
+// git commit --no-verify.
+func (r *gitRepositoryImpl) hookedMessage(ctx context.Context, env []string, message *model.CommitMessage) (string, error) {
+	raw := r.buildCommitMessage(message)
+	dir, err := r.HooksDir(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if !message.NoVerify {
+		if err := r.runCommitHook(ctx, env, dir, hookPreCommit); err != nil {
+			return "", err
+		}
+	}
+
+	// The message hooks edit the message file, git commit's .git/COMMIT_EDITMSG
+	out, _, err := r.runGitCommand(ctx, r.gitBin, false, "rev-parse", "--path-format=absolute", "--git-path", "COMMIT_EDITMSG")
+	if err != nil {
+		return "", fmt.Errorf("failed to locate message file: %w", err)
+	}
+	msgFile := strings.TrimSpace(out)
+	if err := os.WriteFile(msgFile, []byte(raw+"\n"), 0644); err != nil {
+		return "", fmt.Errorf("failed to write message file: %w", err)
+	}
+
+	if err := r.runCommitHook(ctx, env, dir, hookPrepareCommitMsg, msgFile, "message"); err != nil {
+		return "", err
+	}
+	if !message.NoVerify {
+		if err := r.runCommitHook(ctx, env, dir, hookCommitMsg, msgFile); err != nil {
+			return "", err
+		}
+	}
+
+	edited, err := os.ReadFile(msgFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read message file: %w", err)
+	}
+	// Like git commit -m, only the surrounding whitespace is cleaned up
+	raw = strings.TrimSpace(string(edited))
+	if raw == "" {
+		return "", fmt.Errorf("%w: the commit message is empty after the hooks", ErrGitHookFailed)
+	}
+	return raw, nil
+}
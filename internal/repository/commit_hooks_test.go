@@ -0,0 +1,206 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/utils"
+	"github.com/golgoth31/gitcomm/pkg/testutil"
+)
+
+// writeHook writes an executable hook script into dir
+func writeHook(t *testing.T, dir, name, script string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("failed to create hooks directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"+script+"\n"), 0755); err != nil {
+		t.Fatalf("failed to write %s hook: %v", name, err)
+	}
+}
+
+func TestCommitHooks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks are shell scripts")
+	}
+	utils.InitLogger(true)
+	ctx := context.Background()
+
+	// commit-msg appends a trailer, prepare-commit-msg records its arguments
+	messageHooks := func(dir string) {
+		writeHook(t, dir, "prepare-commit-msg", `echo "$2" > "$(git rev-parse --git-dir)/prepare-source"`)
+		writeHook(t, dir, "commit-msg", `printf '\nChange-Id: I123\n' >> "$1"`)
+	}
+
+	tests := []struct {
+		name       string
+		setup      func(r *testutil.Repo)
+		noVerify   bool
+		commit     func(repo GitRepository, message *model.CommitMessage) error
+		wantErr    bool
+		wantFooter bool
+	}{
+		{
+			name: "commit with core.hooksPath",
+			setup: func(r *testutil.Repo) {
+				messageHooks(filepath.Join(r.Dir, ".husky"))
+				r.Git("config", "core.hooksPath", ".husky")
+			},
+			commit: func(repo GitRepository, message *model.CommitMessage) error {
+				return repo.CreateCommit(ctx, message)
+			},
+			wantFooter: true,
+		},
+		{
+			name: "commit on branch",
+			setup: func(r *testutil.Repo) {
+				messageHooks(filepath.Join(r.Dir, ".git", "hooks"))
+			},
+			commit: func(repo GitRepository, message *model.CommitMessage) error {
+				return repo.CreateCommitOnBranch(ctx, message, "main")
+			},
+			wantFooter: true,
+		},
+		{
+			name: "commit on branch with core.hooksPath",
+			setup: func(r *testutil.Repo) {
+				messageHooks(filepath.Join(r.Dir, ".husky"))
+				r.Git("config", "core.hooksPath", ".husky")
+			},
+			commit: func(repo GitRepository, message *model.CommitMessage) error {
+				return repo.CreateCommitOnBranch(ctx, message, "main")
+			},
+			wantFooter: true,
+		},
+		{
+			name: "pre-commit rejects the commit on branch",
+			setup: func(r *testutil.Repo) {
+				writeHook(t, filepath.Join(r.Dir, ".git", "hooks"), "pre-commit", "echo lint failed >&2; exit 1")
+			},
+			commit: func(repo GitRepository, message *model.CommitMessage) error {
+				return repo.CreateCommitOnBranch(ctx, message, "main")
+			},
+			wantErr: true,
+		},
+		{
+			name: "pre-commit rejects the commit",
+			setup: func(r *testutil.Repo) {
+				writeHook(t, filepath.Join(r.Dir, ".git", "hooks"), "pre-commit", "echo lint failed >&2; exit 1")
+			},
+			commit: func(repo GitRepository, message *model.CommitMessage) error {
+				return repo.CreateCommit(ctx, message)
+			},
+			wantErr: true,
+		},
+		{
+			name: "no verify on branch",
+			setup: func(r *testutil.Repo) {
+				dir := filepath.Join(r.Dir, ".git", "hooks")
+				writeHook(t, dir, "pre-commit", "exit 1")
+				writeHook(t, dir, "commit-msg", "exit 1")
+			},
+			noVerify: true,
+			commit: func(repo GitRepository, message *model.CommitMessage) error {
+				return repo.CreateCommitOnBranch(ctx, message, "main")
+			},
+		},
+		{
+			name: "no verify",
+			setup: func(r *testutil.Repo) {
+				dir := filepath.Join(r.Dir, ".git", "hooks")
+				writeHook(t, dir, "pre-commit", "exit 1")
+				writeHook(t, dir, "commit-msg", "exit 1")
+			},
+			noVerify: true,
+			commit: func(repo GitRepository, message *model.CommitMessage) error {
+				return repo.CreateCommit(ctx, message)
+			},
+		},
+		{
+			name: "hook not executable",
+			setup: func(r *testutil.Repo) {
+				path := filepath.Join(r.Dir, ".git", "hooks", "pre-commit")
+				if err := os.WriteFile(path, []byte("#!/bin/sh\nexit 1\n"), 0644); err != nil {
+					t.Fatalf("failed to write hook: %v", err)
+				}
+			},
+			commit: func(repo GitRepository, message *model.CommitMessage) error {
+				return repo.CreateCommitOnBranch(ctx, message, "main")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := testutil.NewRepoWithCommit(t, map[string]string{"README.md": "# test\n"})
+			r.WriteFile("README.md", "# test\n\nmore\n")
+			r.Stage("README.md")
+			tt.setup(r)
+			head := strings.TrimSpace(r.Git("rev-parse", "HEAD"))
+
+			repo, err := NewGitRepository(r.Dir, true, true)
+			if err != nil {
+				t.Fatalf("NewGitRepository() error = %v", err)
+			}
+			message := &model.CommitMessage{Type: "docs", Subject: "extend the readme", NoVerify: tt.noVerify}
+			err = tt.commit(repo, message)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("commit error = nil, want the hook failure")
+				}
+				if got := strings.TrimSpace(r.Git("rev-parse", "HEAD")); got != head {
+					t.Errorf("HEAD moved to %s after a rejected commit", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("commit error = %v", err)
+			}
+			body := r.Git("log", "-1", "--format=%B", "main")
+			if !strings.HasPrefix(body, "docs: extend the readme") {
+				t.Errorf("commit message = %q, want the gitcomm message", body)
+			}
+			if got := strings.Contains(body, "Change-Id: I123"); got != tt.wantFooter {
+				t.Errorf("commit message = %q, commit-msg trailer = %v, want %v", body, got, tt.wantFooter)
+			}
+			if tt.wantFooter {
+				source, err := os.ReadFile(filepath.Join(r.Dir, ".git", "prepare-source"))
+				if err != nil || strings.TrimSpace(string(source)) != "message" {
+					t.Errorf("prepare-commit-msg source = %q (%v), want message", source, err)
+				}
+			}
+		})
+	}
+}
+
+func TestRunCommitHook_Error(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks are shell scripts")
+	}
+	r := testutil.NewRepoWithCommit(t, map[string]string{"README.md": "# test\n"})
+	writeHook(t, filepath.Join(r.Dir, ".git", "hooks"), "pre-commit", "exit 3")
+	repo, err := NewGitRepository(r.Dir, true, true)
+	if err != nil {
+		t.Fatalf("NewGitRepository() error = %v", err)
+	}
+
+	impl := repo.(*gitRepositoryImpl)
+	dir, err := impl.HooksDir(context.Background())
+	if err != nil {
+		t.Fatalf("HooksDir() error = %v", err)
+	}
+	err = impl.runCommitHook(context.Background(), os.Environ(), dir, hookPreCommit)
+	if !errors.Is(err, ErrGitHookFailed) || !strings.Contains(err.Error(), "status 3") {
+		t.Errorf("runCommitHook() error = %v, want ErrGitHookFailed with the exit status", err)
+	}
+	if err := impl.runCommitHook(context.Background(), os.Environ(), dir, hookCommitMsg); err != nil {
+		t.Errorf("runCommitHook(missing hook) error = %v, want nil", err)
+	}
+}
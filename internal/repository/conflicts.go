@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/utils"
+)
+
+// Conflict markers written by git merge-file, followed by the side label
+const (
+	conflictOursMarker   = "<<<<<<<"
+	conflictSplitMarker  = "======="
+	conflictTheirsMarker = ">>>>>>>"
+)
+
+// populateConflicts describes the conflicts of the unmerged files from their index
+// stages: the worktree copy holds whatever the user made of the markers so far.
+// Failures leave the conflict with its kind only.
+func (r *gitRepositoryImpl) populateConflicts(ctx context.Context, files []model.FileChange) {
+	if !hasConflicts(files) {
+		return
+	}
+	stages, err := r.unmergedStages(ctx)
+	if err != nil {
+		utils.Logger.Debug().Err(err).Msg("Failed to list unmerged stages")
+		return
+	}
+	for _, file := range files {
+		if file.Conflict == nil {
+			continue
+		}
+		if err := r.describeConflict(ctx, file.Conflict, stages[file.Path]); err != nil {
+			utils.Logger.Debug().Err(err).Str("file", file.Path).Msg("Failed to describe conflict")
+		}
+	}
+}
+
+// hasConflicts reports whether files holds an unmerged file
+func hasConflicts(files []model.FileChange) bool {
+	for _, file := range files {
+		if file.Conflict != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// unmergedStages returns the blobs of the stages of each unmerged path, indexed by path
+// then stage number (1: base, 2: ours, 3: theirs)
+func (r *gitRepositoryImpl) unmergedStages(ctx context.Context) (map[string]map[int]string, error) {
+	// Output is parsed, so always use git directly
+	out, _, err := r.runGitCommand(ctx, r.gitBin, false, "ls-files", "--unmerged", "-z")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list unmerged files: %w", err)
+	}
+	return parseUnmergedStages(out), nil
+}
+
+// parseUnmergedStages parses git ls-files --unmerged -z output ("<mode> <object> <stage>\t<path>")
+func parseUnmergedStages(output string) map[string]map[int]string {
+	stages := make(map[string]map[int]string)
+	for _, entry := range strings.Split(output, "\x00") {
+		info, path, ok := strings.Cut(entry, "\t")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(info)
+		if len(fields) != 3 || len(fields[2]) != 1 || fields[2][0] < '1' || fields[2][0] > '3' {
+			continue
+		}
+		if stages[path] == nil {
+			stages[path] = make(map[int]string)
+		}
+		stages[path][int(fields[2][0]-'0')] = fields[1]
+	}
+	return stages
+}
+
+// describeConflict merges the stages of an unmerged file again to find its conflicting
+// hunks. Nothing is compared when a side deleted the file.
+func (r *gitRepositoryImpl) describeConflict(ctx context.Context, conflict *model.Conflict, stages map[int]string) error {
+	if stages[2] == "" || stages[3] == "" {
+		return nil
+	}
+
+	// A file added on both sides has no base: it merges against an empty one
+	contents := make([][]byte, 3)
+	for i, stage := range []int{2, 1, 3} {
+		if stages[stage] == "" {
+			continue
+		}
+		out, _, err := r.runGitCommand(ctx, r.gitBin, false, "cat-file", "blob", stages[stage])
+		if err != nil {
+			return fmt.Errorf("failed to read stage %d: %w", stage, err)
+		}
+		if bytes.IndexByte([]byte(out), 0) >= 0 {
+			conflict.Binary = true
+			return nil
+		}
+		contents[i] = []byte(out)
+	}
+
+	dir, err := os.MkdirTemp("", "gitcomm-conflict-*")
+	if err != nil {
+		return fmt.Errorf("failed to create merge directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	paths := make([]string, 3)
+	for i, name := range []string{"ours", "base", "theirs"} {
+		paths[i] = filepath.Join(dir, name)
+		if err := os.WriteFile(paths[i], contents[i], 0600); err != nil {
+			return fmt.Errorf("failed to write %s stage: %w", name, err)
+		}
+	}
+
+	// git merge-file exits with the number of conflicts, negative values are errors
+	out, _, err := r.runGitCommand(ctx, r.gitBin, false, "merge-file", "-p", "-L", "ours", "-L", "base", "-L", "theirs", paths[0], paths[1], paths[2])
+	var failed *ErrGitCommandFailed
+	if err != nil && (!errors.As(err, &failed) || failed.ExitCode <= 0 || failed.ExitCode >= 128) {
+		return fmt.Errorf("failed to merge stages: %w", err)
+	}
+	conflict.Hunks = parseConflictHunks(out)
+	return nil
+}
+
+// parseConflictHunks returns the conflicting hunks of a file merged with conflict markers
+func parseConflictHunks(merged string) []model.ConflictHunk {
+	var hunks []model.ConflictHunk
+	var current *model.ConflictHunk
+	theirs := false
+	for _, line := range strings.Split(merged, "\n") {
+		switch {
+		case strings.HasPrefix(line, conflictOursMarker+" "):
+			current, theirs = &model.ConflictHunk{}, false
+		case current == nil:
+			continue
+		case line == conflictSplitMarker:
+			theirs = true
+		case strings.HasPrefix(line, conflictTheirsMarker+" "):
+			hunks = append(hunks, *current)
+			current = nil
+		case theirs:
+			current.TheirsLines++
+			if len(current.Theirs) < model.ConflictPreviewLines {
+				current.Theirs = append(current.Theirs, line)
+			}
+		default:
+			current.OursLines++
+			if len(current.Ours) < model.ConflictPreviewLines {
+				current.Ours = append(current.Ours, line)
+			}
+		}
+	}
+	return hunks
+}
+
+// conflictDiff returns the conflict as shown in place of the diff of an unmerged file,
+// without the previews when they exceed the diff size limit
+func conflictDiff(conflict *model.Conflict) string {
+	if diff := conflict.String(); len(diff) <= maxDiffSize {
+		return diff
+	}
+	return conflict.Redacted().String()
+}
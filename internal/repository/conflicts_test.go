@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/golgoth31/gitcomm/internal/utils"
+	"github.com/golgoth31/gitcomm/pkg/testutil"
+)
+
+func TestParseUnmergedStages(t *testing.T) {
+	output := "100644 1111111111111111111111111111111111111111 1\tsrc/a b.go\x00" +
+		"100644 2222222222222222222222222222222222222222 2\tsrc/a b.go\x00" +
+		"100644 3333333333333333333333333333333333333333 3\tsrc/a b.go\x00" +
+		"100644 4444444444444444444444444444444444444444 2\tgone.txt\x00"
+
+	stages := parseUnmergedStages(output)
+	if len(stages) != 2 || len(stages["src/a b.go"]) != 3 {
+		t.Fatalf("parseUnmergedStages() = %v, want 3 stages of src/a b.go and 1 of gone.txt", stages)
+	}
+	if stages["src/a b.go"][3] != "3333333333333333333333333333333333333333" {
+		t.Errorf("theirs stage = %q", stages["src/a b.go"][3])
+	}
+	if _, ok := stages["gone.txt"][3]; ok || stages["gone.txt"][2] == "" {
+		t.Errorf("gone.txt stages = %v, want ours only", stages["gone.txt"])
+	}
+}
+
+func TestParseConflictHunks(t *testing.T) {
+	merged := "package main\n" +
+		"<<<<<<< ours\none\ntwo\n=======\nuno\n>>>>>>> theirs\n" +
+		"shared\n" +
+		"<<<<<<< ours\n=======\n1\n2\n3\n4\n5\n6\n>>>>>>> theirs\n"
+
+	hunks := parseConflictHunks(merged)
+	if len(hunks) != 2 {
+		t.Fatalf("got %d hunks, want 2", len(hunks))
+	}
+	if !slices.Equal(hunks[0].Ours, []string{"one", "two"}) || !slices.Equal(hunks[0].Theirs, []string{"uno"}) {
+		t.Errorf("first hunk = %+v", hunks[0])
+	}
+	if hunks[1].OursLines != 0 || hunks[1].TheirsLines != 6 || len(hunks[1].Theirs) != 5 {
+		t.Errorf("second hunk = %+v, want 0 lines of ours and a preview of 5 of the 6 lines of theirs", hunks[1])
+	}
+
+	if hunks := parseConflictHunks("no conflict\n=======\n"); len(hunks) != 0 {
+		t.Errorf("parseConflictHunks() = %+v without markers, want none", hunks)
+	}
+}
+
+func TestGetRepositoryState_DescribesConflicts(t *testing.T) {
+	utils.InitLogger(true)
+
+	fixture := testutil.NewRepoWithCommit(t, map[string]string{"test.txt": "one\ntwo\nthree\nfour\nfive\nsix\n"})
+	fixture.Conflict("test.txt", "ONE\ntwo\nthree\nfour\nfive\nSIX\n", "uno\ntwo\nthree\nfour\nfive\nseis\n")
+	// Resolving by hand in the worktree does not change what the conflict was
+	fixture.WriteFile("test.txt", "resolved\n")
+
+	repo, err := NewGitRepository(fixture.Dir, false, false)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	state, err := repo.GetRepositoryState(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to get repository state: %v", err)
+	}
+
+	if len(state.StagedFiles) != 1 || len(state.UnstagedFiles) != 0 {
+		t.Fatalf("state files = %+v staged, %+v unstaged, want test.txt once", state.StagedFiles, state.UnstagedFiles)
+	}
+	file := state.StagedFiles[0]
+	if file.Status != "unmerged" || file.Conflict == nil || file.Conflict.Kind != "both modified" {
+		t.Fatalf("test.txt = %+v, want an unmerged file modified on both sides", file)
+	}
+	if len(file.Conflict.Hunks) != 2 {
+		t.Fatalf("got %d conflicting hunks, want 2", len(file.Conflict.Hunks))
+	}
+	if hunk := file.Conflict.Hunks[1]; !slices.Equal(hunk.Ours, []string{"SIX"}) || !slices.Equal(hunk.Theirs, []string{"seis"}) {
+		t.Errorf("second hunk = %+v, want SIX against seis", hunk)
+	}
+	if !strings.HasPrefix(file.Diff, "Conflict: both modified, 2 conflicting hunks\n") || strings.Contains(file.Diff, "resolved") {
+		t.Errorf("diff = %q, want the conflict summary from the index stages", file.Diff)
+	}
+}
+
+func TestGetRepositoryState_DeletedByThem(t *testing.T) {
+	utils.InitLogger(true)
+
+	fixture := testutil.NewRepoWithCommit(t, map[string]string{"test.txt": "content\n", "other.txt": "other\n"})
+	fixture.Git("checkout", "-q", "-b", "theirs")
+	fixture.Git("rm", "-q", "test.txt")
+	fixture.Git("commit", "-q", "-m", "remove test.txt")
+	fixture.Git("checkout", "-q", "-")
+	fixture.CommitFiles("change test.txt", map[string]string{"test.txt": "changed\n"})
+	if _, err := fixture.TryGit("merge", "--no-edit", "theirs"); err == nil {
+		t.Fatal("git merge succeeded, want a modify/delete conflict")
+	}
+
+	repo, err := NewGitRepository(fixture.Dir, false, false)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	state, err := repo.GetRepositoryState(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to get repository state: %v", err)
+	}
+
+	if len(state.StagedFiles) != 1 || state.StagedFiles[0].Conflict == nil {
+		t.Fatalf("staged files = %+v, want test.txt unmerged", state.StagedFiles)
+	}
+	if got := state.StagedFiles[0].Diff; got != "Conflict: deleted by them\n" {
+		t.Errorf("diff = %q, want %q", got, "Conflict: deleted by them\n")
+	}
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+)
+
+// diffLimits returns the diff limits set under DiffLimitsKey, or the defaults
+func diffLimits(ctx context.Context) model.DiffLimits {
+	if limits, ok := ctx.Value(DiffLimitsKey).(model.DiffLimits); ok {
+		return limits
+	}
+	return model.DiffLimits{MaxSize: maxDiffSize}
+}
+
+// unifiedArg returns the --unified option of the diffs read for the repository state
+func unifiedArg(ctx context.Context) string {
+	return fmt.Sprintf("--unified=%d", max(diffLimits(ctx).Context, 0))
+}
+
+// omittedLines returns the line standing for the lines dropped from the middle of a hunk.
+// It starts with "\", like git's "\ No newline at end of file", so it cannot be taken for
+// a line of the file.
+func omittedLines(count int) string {
+	return fmt.Sprintf("\\ %d lines omitted", count)
+}
+
+// truncateDiff fits a single-file diff in maxSize characters. The file header and every
+// hunk header are kept; small hunks are kept whole and the budget left is shared by the
+// larger ones, which keep their first and last lines around an omittedLines marker.
+// ok is false when even the headers do not fit.
+func truncateDiff(diff string, maxSize int) (truncated string, ok bool) {
+	header, hunks := parseHunks(diff)
+	if len(hunks) == 0 {
+		return "", false
+	}
+
+	budget := maxSize - len(header)
+	sizes := make([]int, len(hunks))
+	for i, hunk := range hunks {
+		budget -= len(hunk.Header) + 1 + len(omittedLines(len(hunk.Lines))) + 1
+		for _, line := range hunk.Lines {
+			sizes[i] += len(line) + 1
+		}
+	}
+	if budget < 0 {
+		return "", false
+	}
+
+	// From the smallest hunk up, each hunk gets at most an even share of the budget left
+	order := make([]int, len(hunks))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int { return sizes[a] - sizes[b] })
+	allowed := make([]int, len(hunks))
+	for n, i := range order {
+		allowed[i] = min(sizes[i], budget/(len(order)-n))
+		budget -= allowed[i]
+	}
+
+	var b strings.Builder
+	b.WriteString(header)
+	for i, hunk := range hunks {
+		b.WriteString(hunk.Header + "\n")
+		for _, line := range trimHunkLines(hunk.Lines, sizes[i], allowed[i]) {
+			b.WriteString(line + "\n")
+		}
+	}
+	return b.String(), true
+}
+
+// trimHunkLines keeps the first and last lines of a hunk fitting in size characters (size
+// being the characters of all the lines), and replaces the others with an omittedLines marker
+func trimHunkLines(lines []string, total, size int) []string {
+	if total <= size {
+		return lines
+	}
+
+	head, used := 0, 0
+	for head < len(lines) && used+len(lines[head])+1 <= size/2 {
+		used += len(lines[head]) + 1
+		head++
+	}
+	tail := len(lines)
+	for tail > head && used+len(lines[tail-1])+1 <= size {
+		used += len(lines[tail-1]) + 1
+		tail--
+	}
+
+	kept := make([]string, 0, head+1+len(lines)-tail)
+	kept = append(kept, lines[:head]...)
+	kept = append(kept, omittedLines(tail-head))
+	return append(kept, lines[tail:]...)
+}
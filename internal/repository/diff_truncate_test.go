@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/pkg/testutil"
+)
+
+// hunkLines returns count added lines numbered from first
+func hunkLines(first, count int) string {
+	var b strings.Builder
+	for i := first; i < first+count; i++ {
+		fmt.Fprintf(&b, "+line %03d\n", i)
+	}
+	return b.String()
+}
+
+func TestTruncateDiff(t *testing.T) {
+	header := "diff --git a/big.go b/big.go\nindex 1111111..2222222 100644\n--- a/big.go\n+++ b/big.go\n"
+	small := "@@ -1 +1 @@\n-old\n+new\n"
+	large := "@@ -10,0 +10,100 @@ func Big()\n" + hunkLines(1, 100)
+
+	tests := []struct {
+		name         string
+		diff         string
+		maxSize      int
+		wantOK       bool
+		wantContains []string
+		wantMissing  []string
+	}{
+		{
+			name:         "middle of the large hunk dropped",
+			diff:         header + small + large,
+			maxSize:      600,
+			wantOK:       true,
+			wantContains: []string{header, small, "@@ -10,0 +10,100 @@ func Big()\n+line 001\n", " lines omitted\n+line ", "+line 100\n"},
+			wantMissing:  []string{"+line 050\n"},
+		},
+		{
+			name:         "both hunks trimmed",
+			diff:         header + large + strings.Replace(large, "@@ -10,0 +10,100", "@@ -200,0 +300,100", 1),
+			maxSize:      700,
+			wantOK:       true,
+			wantContains: []string{"@@ -10,0 +10,100 @@", "@@ -200,0 +300,100 @@"},
+		},
+		{name: "headers do not fit", diff: header + small + large, maxSize: len(header) + 10},
+		{name: "no hunk", diff: header + "Binary files differ\n", maxSize: 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := truncateDiff(tt.diff, tt.maxSize)
+			if ok != tt.wantOK {
+				t.Fatalf("truncateDiff() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if len(got) > tt.maxSize {
+				t.Errorf("truncateDiff() length = %d, want at most %d", len(got), tt.maxSize)
+			}
+			for _, want := range tt.wantContains {
+				if !strings.Contains(got, want) {
+					t.Errorf("truncateDiff() = %q, want it to contain %q", got, want)
+				}
+			}
+			for _, missing := range tt.wantMissing {
+				if strings.Contains(got, missing) {
+					t.Errorf("truncateDiff() = %q, want %q dropped", got, missing)
+				}
+			}
+		})
+	}
+}
+
+func TestGetRepositoryState_DiffLimits(t *testing.T) {
+	var original strings.Builder
+	for i := 1; i <= 200; i++ {
+		fmt.Fprintf(&original, "line %03d\n", i)
+	}
+	fixture := testutil.NewRepoWithCommit(t, map[string]string{"big.txt": original.String()})
+	appended := strings.ReplaceAll(hunkLines(201, 1000), "+", "")
+	fixture.WriteFile("big.txt", strings.Replace(original.String(), "line 100\n", "line one hundred\n", 1)+appended)
+	fixture.Stage("big.txt")
+
+	repo, err := NewGitRepository(fixture.Dir, true, true)
+	if err != nil {
+		t.Fatalf("NewGitRepository() error = %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		limits       *model.DiffLimits
+		wantContains []string
+		wantMissing  []string
+	}{
+		{
+			name:         "defaults",
+			wantContains: []string{"+line one hundred\n", "+line 201\n", " lines omitted\n", "+line 1200\n"},
+			wantMissing:  []string{"\n line 099\n", "+line 700\n"},
+		},
+		{
+			name:         "context lines",
+			limits:       &model.DiffLimits{Context: 2, MaxSize: 5000},
+			wantContains: []string{" line 098\n line 099\n-line 100\n+line one hundred\n line 101\n line 102\n"},
+		},
+		{
+			name:         "unlimited",
+			limits:       &model.DiffLimits{},
+			wantContains: []string{"+line 700\n"},
+			wantMissing:  []string{" lines omitted"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.limits != nil {
+				ctx = context.WithValue(ctx, DiffLimitsKey, *tt.limits)
+			}
+			state, err := repo.GetRepositoryState(ctx)
+			if err != nil {
+				t.Fatalf("GetRepositoryState() error = %v", err)
+			}
+			if len(state.StagedFiles) != 1 {
+				t.Fatalf("StagedFiles = %+v, want big.txt", state.StagedFiles)
+			}
+			diff := state.StagedFiles[0].Diff
+			for _, want := range tt.wantContains {
+				if !strings.Contains(diff, want) {
+					t.Errorf("diff = %q, want it to contain %q", diff, want)
+				}
+			}
+			for _, missing := range tt.wantMissing {
+				if strings.Contains(diff, missing) {
+					t.Errorf("diff = %q, want %q left out", diff, missing)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/golgoth31/gitcomm/internal/utils"
+)
+
+// FaultsEnv enables the fault injection points of the staging and restore path, for tests
+// only. Its value is a comma-separated list of:
+//   - stage_file=N: staging the Nth file of an auto-staging fails
+//   - unstage: unstaging files (restoring the staging state) fails
+//   - restore_timeout: unstaging files times out
+const FaultsEnv = "GITCOMM_FAULTS"
+
+// ErrInjectedFault is the failure of a fault injection point
+var ErrInjectedFault = errors.New("injected fault")
+
+// faults are the fault injection points enabled by FaultsEnv
+type faults struct {
+	stageFile      int // 1-based index of the file whose staging fails, 0 for none
+	unstage        bool
+	restoreTimeout bool
+}
+
+// loadFaults returns the faults enabled by FaultsEnv; an invalid value enables none
+func loadFaults() faults {
+	spec := os.Getenv(FaultsEnv)
+	if spec == "" {
+		return faults{}
+	}
+	f, err := parseFaults(spec)
+	if err != nil {
+		utils.Logger.Debug().Err(err).Str("value", spec).Msg("Invalid " + FaultsEnv + ", no fault injected")
+		return faults{}
+	}
+	utils.Logger.Debug().Str("faults", spec).Msg("Fault injection enabled")
+	return f
+}
+
+// parseFaults parses the value of FaultsEnv
+func parseFaults(spec string) (faults, error) {
+	var f faults
+	for item := range strings.SplitSeq(spec, ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(item), "=")
+		switch name {
+		case "stage_file":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return faults{}, fmt.Errorf("stage_file wants a file number from 1, got %q", value)
+			}
+			f.stageFile = n
+		case "unstage":
+			f.unstage = true
+		case "restore_timeout":
+			f.restoreTimeout = true
+		case "":
+		default:
+			return faults{}, fmt.Errorf("unknown fault %q (want stage_file=N, unstage or restore_timeout)", name)
+		}
+	}
+	return f, nil
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golgoth31/gitcomm/internal/utils"
+	"github.com/golgoth31/gitcomm/pkg/testutil"
+)
+
+func TestParseFaults(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    faults
+		wantErr bool
+	}{
+		{spec: "stage_file=2", want: faults{stageFile: 2}},
+		{spec: "unstage", want: faults{unstage: true}},
+		{spec: "stage_file=1, restore_timeout", want: faults{stageFile: 1, restoreTimeout: true}},
+		{spec: "stage_file=0", wantErr: true},
+		{spec: "stage_file", wantErr: true},
+		{spec: "commit", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := parseFaults(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseFaults(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseFaults(%q) = %+v, want %+v", tt.spec, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFaults_StagingAndUnstaging(t *testing.T) {
+	tests := []struct {
+		name         string
+		faults       string
+		wantStageErr error // staging failure
+		wantUnstage  error // unstaging failure, the files staying staged
+	}{
+		{name: "no faults"},
+		{name: "second file fails", faults: "stage_file=2", wantStageErr: utils.ErrStagingFailed},
+		{name: "unstage fails", faults: "unstage", wantUnstage: ErrInjectedFault},
+		{name: "restore times out", faults: "restore_timeout", wantUnstage: context.DeadlineExceeded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(FaultsEnv, tt.faults)
+			fixture := testutil.NewRepoWithCommit(t, map[string]string{"a.txt": "a\n", "b.txt": "b\n", "c.txt": "c\n"})
+			for _, name := range []string{"a.txt", "b.txt", "c.txt"} {
+				fixture.WriteFile(name, name+" changed\n")
+			}
+			repo, err := NewGitRepository(fixture.Dir, true, true)
+			if err != nil {
+				t.Fatalf("NewGitRepository() error = %v", err)
+			}
+			ctx := context.Background()
+
+			result, err := repo.StageModifiedFiles(ctx)
+			if tt.wantStageErr != nil {
+				if !errors.Is(err, tt.wantStageErr) || !errors.Is(result.FailedFiles[0].Error, ErrInjectedFault) {
+					t.Fatalf("StageModifiedFiles() error = %v, want %v", err, tt.wantStageErr)
+				}
+				// The files staged before the failure are rolled back
+				if staged := fixture.Git("diff", "--cached", "--name-only"); staged != "" {
+					t.Errorf("staged files after a staging failure = %q, want none", staged)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("StageModifiedFiles() error = %v", err)
+			}
+
+			err = repo.UnstageFiles(ctx, []string{"a.txt", "b.txt", "c.txt"})
+			if tt.wantUnstage != nil {
+				if !errors.Is(err, utils.ErrRestorationFailed) || !errors.Is(err, tt.wantUnstage) {
+					t.Fatalf("UnstageFiles() error = %v, want %v", err, tt.wantUnstage)
+				}
+				if staged := fixture.Git("diff", "--cached", "--name-only"); staged != "a.txt\nb.txt\nc.txt\n" {
+					t.Errorf("staged files after a failed unstaging = %q, want all three", staged)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UnstageFiles() error = %v", err)
+			}
+			if staged := fixture.Git("diff", "--cached", "--name-only"); staged != "" {
+				t.Errorf("staged files after unstaging = %q, want none", staged)
+			}
+		})
+	}
+}
@@ -23,6 +23,9 @@ var (
 
 	// ErrGitFileNotFound indicates a file was not found in the repository
 	ErrGitFileNotFound = errors.New("file not found in git repository")
+
+	// ErrGitHookFailed indicates a commit hook (pre-commit, commit-msg...) rejected the commit
+	ErrGitHookFailed = errors.New("git hook rejected the commit")
 )
 
 // ErrGitCommandFailed is a generic error for git command failures
@@ -2,8 +2,10 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/pkg/git/remote"
 )
 
 // GitRepository defines the interface for git operations
@@ -14,9 +16,45 @@ type GitRepository interface {
 	// CreateCommit creates a git commit with the given message
 	CreateCommit(ctx context.Context, message *model.CommitMessage) error
 
+	// AmendCommit replaces the HEAD commit with the index and message, keeping its author
+	AmendCommit(ctx context.Context, message *model.CommitMessage) error
+
+	// UndoLastCommit removes the HEAD commit, keeping its changes staged (soft reset) or
+	// discarding them along with the uncommitted changes (hard reset)
+	UndoLastCommit(ctx context.Context, keepChanges bool) error
+
+	// CreateCommitOnBranch creates a commit with the staged changes on branch without switching
+	// the worktree, keeping the changes of a branch that diverged from HEAD
+	CreateCommitOnBranch(ctx context.Context, message *model.CommitMessage, branch string) error
+
+	// CreateCommitObject creates a commit object from the index on top of HEAD without updating any ref
+	CreateCommitObject(ctx context.Context, message *model.CommitMessage) (string, error)
+
+	// ExportPatch writes revision as a format-patch style file into dir and returns the file path
+	ExportPatch(ctx context.Context, revision string, dir string) (string, error)
+
 	// StageAllFiles stages all unstaged files (equivalent to git add -A)
 	StageAllFiles(ctx context.Context) error
 
+	// StageFiles stages the given files as a whole (modified, deleted or untracked)
+	StageFiles(ctx context.Context, files []string) error
+
+	// UnstagedHunks returns the hunks of the unstaged changes of a tracked file
+	UnstagedHunks(ctx context.Context, file string) ([]model.Hunk, error)
+
+	// StageHunks stages only the given hunks of file, as returned by UnstagedHunks
+	StageHunks(ctx context.Context, file string, hunks []model.Hunk) error
+
+	// SnapshotIndex writes the index as a tree object and returns its hash
+	SnapshotIndex(ctx context.Context) (string, error)
+
+	// RestoreIndex replaces the index with a tree returned by SnapshotIndex
+	RestoreIndex(ctx context.Context, tree string) error
+
+	// StageFromSnapshot resets the index to HEAD, then stages files as they are in a tree
+	// returned by SnapshotIndex
+	StageFromSnapshot(ctx context.Context, tree string, files []string) error
+
 	// CaptureStagingState captures the current staging state of the repository for restoration purposes
 	CaptureStagingState(ctx context.Context) (*model.StagingState, error)
 
@@ -29,6 +67,105 @@ type GitRepository interface {
 	// UnstageFiles unstages the specified files, restoring them to their pre-staged state
 	UnstageFiles(ctx context.Context, files []string) error
 
+	// ListCommits returns the last limit non-merge commits reachable from revision (HEAD if empty), newest first
+	ListCommits(ctx context.Context, revision string, limit int) ([]model.CommitInfo, error)
+
+	// LastAuthorCommit returns the most recent commit by the git user touching any of paths
+	// within the last since (no limit if zero), or nil if there is none
+	LastAuthorCommit(ctx context.Context, paths []string, since time.Duration) (*model.CommitInfo, error)
+
+	// AuthorCommits returns the non-merge commits by the git user on any local branch
+	// since the given time, newest first
+	AuthorCommits(ctx context.Context, since time.Time) ([]model.CommitInfo, error)
+
+	// GetCommitState returns the files changed by revision, with their diffs, in StagedFiles
+	GetCommitState(ctx context.Context, revision string) (*model.RepositoryState, error)
+
+	// StagedDiffStats returns the number of changed files and added/removed lines in the index
+	StagedDiffStats(ctx context.Context) (*model.DiffStats, error)
+
+	// RewordCommits replaces the messages of the given commits (full hash) on the current branch,
+	// recreating the commits after them. Returns the old to new hash mapping.
+	RewordCommits(ctx context.Context, messages map[string]*model.CommitMessage) (map[string]string, error)
+
+	// IsPushed reports whether commit is reachable from any remote-tracking branch
+	IsPushed(ctx context.Context, commit string) (bool, error)
+
+	// GitDir returns the absolute path of the git directory (shared by all worktrees)
+	GitDir(ctx context.Context) (string, error)
+
+	// WorkTreeDir returns the absolute path of the top-level directory of the worktree
+	WorkTreeDir(ctx context.Context) (string, error)
+
+	// HooksDir returns the absolute path of the hooks directory: core.hooksPath when set,
+	// $GIT_DIR/hooks otherwise
+	HooksDir(ctx context.Context) (string, error)
+
+	// Upstream returns the remote-tracking branch the current branch follows (e.g.
+	// "origin/main"), or "" when there is none
+	Upstream(ctx context.Context) (string, error)
+
+	// AheadBehind counts the commits of HEAD missing from upstream and of upstream missing
+	// from HEAD
+	AheadBehind(ctx context.Context, upstream string) (ahead, behind int, err error)
+
+	// FetchUpstream fetches the remote of the current branch, without asking credentials
+	FetchUpstream(ctx context.Context) error
+
+	// GetCurrentBranch returns the name of the current branch ("" when HEAD is detached)
+	GetCurrentBranch(ctx context.Context) (string, error)
+
+	// SwitchToNewBranch creates the branch name at HEAD and switches to it, keeping the
+	// index and worktree like git switch --create
+	SwitchToNewBranch(ctx context.Context, name string) error
+
+	// DefaultBranch returns the default branch of the repository: the HEAD of remote
+	// (origin if empty), else the local init.defaultBranch, main or master branch
+	DefaultBranch(ctx context.Context, remote string) (string, error)
+
+	// GetWorkspaceSummary returns the branch, tracking status, file counts and last commit
+	// date of the worktree, shown before any prompt
+	GetWorkspaceSummary(ctx context.Context) (*model.WorkspaceSummary, error)
+
+	// IndexPath returns the absolute path of the index file of the worktree
+	IndexPath(ctx context.Context) (string, error)
+
+	// CreateTag creates the annotated tag name on revision (HEAD if empty), signed with the
+	// commit signing key when configured; a tag that cannot be signed is not created.
+	// Reports whether the tag was signed.
+	CreateTag(ctx context.Context, name, revision, message string) (bool, error)
+
+	// ListTags returns the names of the tags reachable from revision (HEAD if empty)
+	ListTags(ctx context.Context, revision string) ([]string, error)
+
+	// ResolvePushTarget returns where branch is pushed, following pushRemote/pushDefault
+	// and fork (triangular) setups
+	ResolvePushTarget(ctx context.Context, branch string) (*remote.Target, error)
+
+	// Push pushes the target's local branch to its remote branch, setting the upstream if requested
+	Push(ctx context.Context, target *remote.Target) error
+
+	// GetConfigValues returns all values of a git config key (nil if unset)
+	GetConfigValues(ctx context.Context, key string) ([]string, error)
+
+	// GetIdentity returns the author identity (user.name and user.email) read from the git
+	// config files
+	GetIdentity() (name, email string)
+
+	// CheckSigning returns how commits are signed (nil when no signing key is configured),
+	// with an error when the configured key cannot sign
+	CheckSigning(ctx context.Context) (*model.SigningStatus, error)
+
+	// SigningIdentityWarning explains why signed commits would show as unverified for
+	// user.email: the SSH signing key is not trusted for it in gpg.ssh.allowedSignersFile.
+	// Empty when commits are not signed with SSH or the identity matches.
+	SigningIdentityWarning() string
+
+	// UseTemporaryIndex makes the following git commands work on a copy of the index, so
+	// staging and commit objects leave the real index untouched. The returned function
+	// switches back to the real index and removes the copy.
+	UseTemporaryIndex(ctx context.Context) (func(), error)
+
 	// UsesRTK returns true if git commands are being proxied through rtk
 	UsesRTK() bool
 }
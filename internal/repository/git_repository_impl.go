@@ -2,11 +2,15 @@ package repository
 
 import (
 	"bytes"
+	"cmp"
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -14,11 +18,18 @@ import (
 	"github.com/golgoth31/gitcomm/internal/model"
 	"github.com/golgoth31/gitcomm/internal/utils"
 	gitconfig "github.com/golgoth31/gitcomm/pkg/git/config"
+	"github.com/golgoth31/gitcomm/pkg/git/gitdir"
+	"github.com/golgoth31/gitcomm/pkg/git/remote"
 )
 
 const (
-	// maxDiffSize is the maximum character count for diff content before showing metadata only
+	// maxDiffSize is the default character count above which a file diff is truncated
 	maxDiffSize = 5000
+	// commandTimeout is the safety timeout of a single git command, so a hung git
+	// (status on a huge repository, commit on a cold filesystem) never blocks forever
+	commandTimeout = 5 * time.Minute
+	// interruptGracePeriod is how long an interrupted git command may take to exit before it is killed
+	interruptGracePeriod = 2 * time.Second
 	// minGitMajor is the minimum required git major version
 	minGitMajor = 2
 	// minGitMinor is the minimum required git minor version (for SSH signing support)
@@ -32,6 +43,14 @@ const (
 	// IncludeNewFilesKey is the context key for controlling whether new files are included in repository state
 	// This key is used to pass the addAll flag from service layer to repository layer via context
 	IncludeNewFilesKey contextKey = "includeNewFiles"
+
+	// PathspecKey is the context key of the pathspecs ([]string, relative to the worktree root)
+	// limiting auto-staging, the repository state and the commit to the matching paths
+	PathspecKey contextKey = "pathspec"
+
+	// DiffLimitsKey is the context key of the model.DiffLimits of the staged and commit diffs;
+	// without it, diffs have no context lines and are truncated above maxDiffSize characters
+	DiffLimitsKey contextKey = "diffLimits"
 )
 
 // gitRepositoryImpl implements GitRepository using external git CLI commands
@@ -42,6 +61,9 @@ type gitRepositoryImpl struct {
 	useRTK bool                    // Whether to proxy git commands through rtk
 	config *gitconfig.GitConfig    // Git configuration
 	signer *gitconfig.CommitSigner // Commit signer configuration
+	index  string                  // Temporary index file used instead of the real one (empty if none)
+	gpgKey openPGPKeyLookup        // Lazy lookup of the OpenPGP secret key, see signingEnabled
+	faults faults                  // Fault injection points enabled by GITCOMM_FAULTS (tests only)
 }
 
 // NewGitRepository creates a new GitRepository implementation using external git CLI.
@@ -83,26 +105,30 @@ func NewGitRepository(repoPath string, noSign bool, noRTK bool) (GitRepository,
 		}
 	}
 
-	// Walk up to find .git directory
-	gitPath := path
-	for {
-		gitDir := filepath.Join(gitPath, ".git")
-		if info, err := os.Stat(gitDir); err == nil && info.IsDir() {
-			path = gitPath
-			break
-		}
-		parent := filepath.Dir(gitPath)
-		if parent == gitPath {
-			return nil, utils.ErrNotGitRepository
-		}
-		gitPath = parent
+	// Find the worktree like git: .git directory or gitdir file (linked worktrees and
+	// submodules), bare repository, or GIT_DIR
+	location, err := gitdir.Find(path)
+	if errors.Is(err, gitdir.ErrNotFound) {
+		return nil, utils.ErrNotGitRepository
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate git repository: %w", err)
 	}
+	switch {
+	case os.Getenv("GIT_DIR") != "":
+		// git resolves relative GIT_DIR and GIT_WORK_TREE from the directory given with -C
+	case location.Bare():
+		path = location.GitDir
+	default:
+		path = location.WorkTree
+	}
+	utils.Logger.Debug().Str("worktree", location.WorkTree).Str("git_dir", location.GitDir).Msg("Git repository found")
 
 	// Extract git config BEFORE opening repository (FR-001, FR-002)
 	extractor := gitconfig.NewFileConfigExtractor()
 	gitConfig := extractor.Extract(path)
 
-	// Prepare commit signer if SSH signing is configured
+	// Prepare commit signer if SSH or OpenPGP signing is configured
 	signer := prepareCommitSigner(gitConfig, noSign)
 
 	return &gitRepositoryImpl{
@@ -112,6 +138,7 @@ func NewGitRepository(repoPath string, noSign bool, noRTK bool) (GitRepository,
 		useRTK: useRTK,
 		config: gitConfig,
 		signer: signer,
+		faults: loadFaults(),
 	}, nil
 }
 
@@ -161,6 +188,17 @@ func validateGitVersion(gitBin string) error {
 	return nil
 }
 
+// GitVersion returns the "git version X.Y.Z" line of the git executable found in PATH, with
+// the error keeping gitcomm from using it (ErrGitNotFound, ErrGitVersionTooOld)
+func GitVersion() (string, error) {
+	gitBin, err := exec.LookPath("git")
+	if err != nil {
+		return "", ErrGitNotFound
+	}
+	out, _ := exec.Command(gitBin, "--version").Output()
+	return strings.TrimSpace(string(out)), validateGitVersion(gitBin)
+}
+
 // execGit executes a git command, proxied through rtk when available.
 // rtk preserves raw output when porcelain/machine-readable flags are used,
 // so all commands (including status --porcelain and diff --cached) go through this path.
@@ -176,21 +214,21 @@ func (r *gitRepositoryImpl) execGit(ctx context.Context, args ...string) (string
 // with cmd.Dir set to the repo path (rtk doesn't support git's global -C flag).
 // Otherwise, -C <path> is prepended to run in the repo directory.
 func (r *gitRepositoryImpl) runGitCommand(ctx context.Context, bin string, viaRTK bool, args ...string) (string, string, error) {
-	// Handle nil context gracefully
-	if ctx == nil {
-		ctx = context.Background()
-	}
-
 	var cmd *exec.Cmd
+	var cancel context.CancelFunc
 	if viaRTK {
 		// rtk git <subcommand> <args...> — run in repo directory via cmd.Dir
 		rtkArgs := append([]string{"git"}, args...)
-		cmd = exec.CommandContext(ctx, bin, rtkArgs...)
+		ctx, cmd, cancel = newGitCmd(ctx, bin, rtkArgs...)
 		cmd.Dir = r.path
 	} else {
 		// git -C <path> <args...>
 		allArgs := append([]string{"-C", r.path}, args...)
-		cmd = exec.CommandContext(ctx, bin, allArgs...)
+		ctx, cmd, cancel = newGitCmd(ctx, bin, allArgs...)
+	}
+	defer cancel()
+	if r.index != "" {
+		cmd.Env = r.withIndex(os.Environ())
 	}
 
 	var stdout, stderr bytes.Buffer
@@ -224,6 +262,10 @@ func (r *gitRepositoryImpl) runGitCommand(ctx context.Context, bin string, viaRT
 			Str("stderr", strings.TrimSpace(stderr.String())).
 			Msg("git command failed")
 
+		if ctxErr := contextError(ctx, subcommand); ctxErr != nil {
+			return stdout.String(), stderr.String(), ctxErr
+		}
+
 		// Categorize the error
 		return stdout.String(), stderr.String(), categorizeError(subcommand, args[1:], exitCode, stderr.String())
 	}
@@ -232,6 +274,37 @@ func (r *gitRepositoryImpl) runGitCommand(ctx context.Context, bin string, viaRT
 	return stdout.String(), stderr.String(), nil
 }
 
+// newGitCmd creates a command bound to ctx (Background if nil), limited by commandTimeout.
+// On cancellation the process is interrupted rather than killed, so git can release its
+// locks (e.g. index.lock); it is killed if still running after interruptGracePeriod.
+// Returns the command context, used by contextError, and its cancel function.
+func newGitCmd(ctx context.Context, bin string, args ...string) (context.Context, *exec.Cmd, context.CancelFunc) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, commandTimeout)
+
+	cmd := exec.CommandContext(ctx, bin, args...)
+	cmd.Cancel = func() error {
+		return cmd.Process.Signal(os.Interrupt)
+	}
+	cmd.WaitDelay = interruptGracePeriod
+	return ctx, cmd, cancel
+}
+
+// contextError returns an error wrapping context.Canceled or context.DeadlineExceeded when
+// the command failed because ctx is done, or nil when the failure is git's own
+func contextError(ctx context.Context, subcommand string) error {
+	switch err := ctx.Err(); {
+	case err == nil:
+		return nil
+	case errors.Is(err, context.DeadlineExceeded):
+		return fmt.Errorf("git %s timed out: %w", subcommand, err)
+	default:
+		return fmt.Errorf("git %s interrupted: %w", subcommand, err)
+	}
+}
+
 // categorizeError parses stderr and exit code to produce a categorized error type (FR-006)
 func categorizeError(command string, args []string, exitCode int, stderr string) error {
 	stderrLower := strings.ToLower(stderr)
@@ -296,18 +369,29 @@ func parseStatus(output string) (staged []model.FileChange, unstaged []model.Fil
 		rawPath := line[3:]
 
 		// Handle renames/copies: "ORIG_PATH -> PATH"
-		filePath := rawPath
+		filePath, oldPath := rawPath, ""
 		if strings.Contains(rawPath, " -> ") {
 			parts := strings.SplitN(rawPath, " -> ", 2)
-			filePath = parts[1]
+			oldPath, filePath = parts[0], parts[1]
+		}
+
+		// Unmerged files are listed once, with the kind of conflict
+		if kind := model.ConflictKind(x, y); kind != "" {
+			staged = append(staged, model.FileChange{
+				Path:     filePath,
+				Status:   "unmerged",
+				Conflict: &model.Conflict{Kind: kind},
+			})
+			continue
 		}
 
 		// Staged files: X is not ' ', not '?', not '!'
 		if x != ' ' && x != '?' && x != '!' {
 			staged = append(staged, model.FileChange{
-				Path:   filePath,
-				Status: porcelainStatusToString(x),
-				Diff:   "",
+				Path:    filePath,
+				Status:  porcelainStatusToString(x),
+				OldPath: oldPath,
+				Diff:    "",
 			})
 		}
 
@@ -329,14 +413,16 @@ func parseStatus(output string) (staged []model.FileChange, unstaged []model.Fil
 	return staged, unstaged
 }
 
-// parseDiff parses `git diff --cached --unified=0` output into a per-file diff map.
-// Splits on "diff --git" boundaries, detects binary files, returns map[filepath]diffContent.
+// parseDiff parses `git diff --cached` output into a per-file diff map.
+// Splits on "diff --git" boundaries, detects binary files, returns map[filepath]diffContent
+// and the set of binary files, whose diff only has the headers.
 // Only used in direct git mode (not rtk, which provides condensed output via RawDiff).
-func parseDiff(output string) map[string]string {
+func parseDiff(output string) (map[string]string, map[string]bool) {
 	result := make(map[string]string)
+	binary := make(map[string]bool)
 
 	if strings.TrimSpace(output) == "" {
-		return result
+		return result, binary
 	}
 
 	// Split on "diff --git" boundaries
@@ -361,14 +447,16 @@ func parseDiff(output string) map[string]string {
 
 		// Check for binary file
 		if strings.Contains(fullChunk, "Binary files") || strings.Contains(fullChunk, "GIT binary patch") {
-			result[filePath] = "" // Binary files have empty diff
+			// Binary files are summarized, the headers are kept for renames
+			result[filePath] = fullChunk
+			binary[filePath] = true
 			continue
 		}
 
 		result[filePath] = fullChunk
 	}
 
-	return result
+	return result, binary
 }
 
 // extractPathFromDiffHeader extracts the file path from "a/<path> b/<path>" header line
@@ -381,50 +469,26 @@ func extractPathFromDiffHeader(header string) string {
 	return parts[1]
 }
 
-// isBinaryFile checks if a file is binary by reading the first 512 bytes
-// and checking for NUL bytes or known binary file extensions
-func (r *gitRepositoryImpl) isBinaryFile(filePath string) bool {
-	// Check known binary extensions first
-	ext := strings.ToLower(filepath.Ext(filePath))
-	binaryExts := map[string]bool{
-		".png": true, ".jpg": true, ".jpeg": true, ".gif": true, ".bmp": true,
-		".ico": true, ".webp": true, ".svg": true, ".tiff": true, ".tif": true,
-		".exe": true, ".dll": true, ".so": true, ".dylib": true,
-		".zip": true, ".tar": true, ".gz": true, ".bz2": true, ".xz": true,
-		".7z": true, ".rar": true, ".jar": true, ".war": true,
-		".pdf": true, ".doc": true, ".docx": true, ".xls": true, ".xlsx": true,
-		".ppt": true, ".pptx": true, ".odt": true, ".ods": true,
-		".mp3": true, ".mp4": true, ".avi": true, ".mkv": true, ".mov": true,
-		".wav": true, ".flac": true, ".ogg": true, ".webm": true,
-		".ttf": true, ".otf": true, ".woff": true, ".woff2": true, ".eot": true,
-		".class": true, ".pyc": true, ".pyo": true, ".o": true, ".a": true,
-		".wasm": true, ".bin": true, ".dat": true, ".db": true, ".sqlite": true,
-	}
-	if binaryExts[ext] {
-		return true
-	}
-
-	// Read first 512 bytes and check for NUL bytes
-	fullPath := filepath.Join(r.path, filePath)
-	file, err := os.Open(fullPath)
-	if err != nil {
-		return false
-	}
-	defer file.Close()
-
-	buffer := make([]byte, 512)
-	n, err := file.Read(buffer)
-	if err != nil {
-		return false
-	}
-
-	for i := 0; i < n; i++ {
-		if buffer[i] == 0 {
-			return true
-		}
-	}
+// binaryExtensions are the extensions of files treated as binary whatever their content
+var binaryExtensions = map[string]bool{
+	".png": true, ".jpg": true, ".jpeg": true, ".gif": true, ".bmp": true,
+	".ico": true, ".webp": true, ".svg": true, ".tiff": true, ".tif": true,
+	".exe": true, ".dll": true, ".so": true, ".dylib": true,
+	".zip": true, ".tar": true, ".gz": true, ".bz2": true, ".xz": true,
+	".7z": true, ".rar": true, ".jar": true, ".war": true,
+	".pdf": true, ".doc": true, ".docx": true, ".xls": true, ".xlsx": true,
+	".ppt": true, ".pptx": true, ".odt": true, ".ods": true,
+	".mp3": true, ".mp4": true, ".avi": true, ".mkv": true, ".mov": true,
+	".wav": true, ".flac": true, ".ogg": true, ".webm": true,
+	".ttf": true, ".otf": true, ".woff": true, ".woff2": true, ".eot": true,
+	".class": true, ".pyc": true, ".pyo": true, ".o": true, ".a": true,
+	".wasm": true, ".bin": true, ".dat": true, ".db": true, ".sqlite": true,
+}
 
-	return false
+// hasBinaryExtension reports whether filePath has a known binary file extension. Content
+// with NUL bytes is detected by git itself on the staged blob ("Binary files ... differ").
+func hasBinaryExtension(filePath string) bool {
+	return binaryExtensions[strings.ToLower(filepath.Ext(filePath))]
 }
 
 // isValidPorcelainCode returns true if the byte is a valid git porcelain v1 status code.
@@ -465,13 +529,16 @@ func porcelainStatusToString(c byte) string {
 // The diff computation is optimized for token usage:
 //   - Uses 0 lines of context (minimal token usage)
 //   - For files/diffs exceeding 5000 characters, shows only metadata (file size, line count, change summary)
-//   - Binary files have empty diff
+//   - Binary files are summarized (type, sizes, image dimensions); .gitattributes diff
+//     drivers decide, so a textconv driver gives a text diff
 //   - Errors are logged but don't stop processing (empty diff is set on error)
 //
 // Filtering behavior:
 //   - New files (added status) are excluded when includeNewFiles context value is false
 //   - Modified, deleted, renamed files are always included regardless of flag
 //   - When context value is not present, defaults to including all files (backward compatible)
+//   - Unstaged files outside the sparse-checkout cone, and ignored untracked files, are
+//     left out: they cannot be staged
 //
 // Unstaged files always have empty diff field (FR-011).
 func (r *gitRepositoryImpl) GetRepositoryState(ctx context.Context) (*model.RepositoryState, error) {
@@ -484,7 +551,7 @@ func (r *gitRepositoryImpl) GetRepositoryState(ctx context.Context) (*model.Repo
 	}
 
 	// Get status (porcelain format for structured parsing — rtk preserves this format)
-	statusOut, _, err := r.execGit(ctx, "status", "--porcelain=v1")
+	statusOut, _, err := r.execGit(ctx, append([]string{"status", "--porcelain=v1"}, pathspecArgs(ctx)...)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get status: %w", err)
 	}
@@ -494,7 +561,7 @@ func (r *gitRepositoryImpl) GetRepositoryState(ctx context.Context) (*model.Repo
 	// Apply filtering to staged files
 	state := &model.RepositoryState{
 		StagedFiles:   []model.FileChange{},
-		UnstagedFiles: unstaged,
+		UnstagedFiles: r.stageableChanges(ctx, unstaged),
 	}
 
 	for _, file := range staged {
@@ -504,11 +571,12 @@ func (r *gitRepositoryImpl) GetRepositoryState(ctx context.Context) (*model.Repo
 		}
 		state.StagedFiles = append(state.StagedFiles, file)
 	}
+	r.populateConflicts(ctx, state.StagedFiles)
 
 	if r.useRTK {
 		// With rtk: get condensed diff output and store as-is for the AI prompt.
 		// No per-file diff parsing needed — rtk produces a human/LLM-optimized format.
-		diffOut, _, err := r.execGit(ctx, "diff", "--cached")
+		diffOut, _, err := r.execGit(ctx, append([]string{"diff", "--cached"}, pathspecArgs(ctx)...)...)
 		if err != nil {
 			utils.Logger.Debug().Err(err).Msg("Failed to get staged diffs via rtk, continuing with empty diff")
 		} else {
@@ -517,26 +585,83 @@ func (r *gitRepositoryImpl) GetRepositoryState(ctx context.Context) (*model.Repo
 		}
 	} else {
 		// Without rtk: parse diffs per file from raw git output
-		diffOut, _, err := r.execGit(ctx, "diff", "--cached", "--unified=0")
+		// -C also detects renames, as well as copies, which git status does not report
+		diffOut, _, err := r.execGit(ctx, append([]string{"diff", "--cached", unifiedArg(ctx), "-C"}, pathspecArgs(ctx)...)...)
 		if err != nil {
 			utils.Logger.Debug().Err(err).Msg("Failed to get staged diffs, continuing with empty diffs")
 			diffOut = ""
 		}
 
-		diffs := parseDiff(diffOut)
+		diffs, binary := parseDiff(diffOut)
+		state.StagedFiles = applyRenames(state.StagedFiles, diffs)
+		paths := make([]string, 0, len(state.StagedFiles))
+		for _, file := range state.StagedFiles {
+			paths = append(paths, file.Path)
+		}
+		attributes := r.diffAttributes(ctx, paths)
 
 		for i, file := range state.StagedFiles {
-			if r.isBinaryFile(file.Path) {
-				state.StagedFiles[i].Diff = "" // Binary files have empty diff
+			if err := ctx.Err(); err != nil {
+				return nil, fmt.Errorf("failed to get repository state: %w", err)
+			}
+			if file.Conflict != nil {
+				// git diff --cached only reports unmerged paths, the conflict is shown instead
+				state.StagedFiles[i].Diff = conflictDiff(file.Conflict)
+			} else if isBinaryChange(file.Path, attributes[file.Path], binary[file.Path]) {
+				state.StagedFiles[i].Diff = r.binarySummary(ctx, file, "HEAD", "")
 			} else if diff, ok := diffs[file.Path]; ok {
-				state.StagedFiles[i].Diff = r.applySizeLimit(diff, file.Path, file.Status)
+				state.StagedFiles[i].Diff = r.applySizeLimit(ctx, diff, file, "HEAD", "")
 			}
 		}
 	}
 
+	// Branch context is informational: failures leave the fields empty
+	r.populateBranchInfo(ctx, state)
+
 	return state, nil
 }
 
+// populateBranchInfo fills branch, upstream tracking and last commit subject in state.
+// Uses git directly since the porcelain v2 header and log output are parsed.
+func (r *gitRepositoryImpl) populateBranchInfo(ctx context.Context, state *model.RepositoryState) {
+	statusOut, _, err := r.runGitCommand(ctx, r.gitBin, false, "status", "--porcelain=v2", "--branch", "--untracked-files=no")
+	if err != nil {
+		utils.Logger.Debug().Err(err).Msg("Failed to get branch status")
+		return
+	}
+	parseBranchHeaders(statusOut, state)
+
+	subject, _, err := r.runGitCommand(ctx, r.gitBin, false, "log", "-1", "--format=%s")
+	if err != nil {
+		utils.Logger.Debug().Err(err).Msg("No previous commit")
+		return
+	}
+	state.LastCommitSubject = strings.TrimSpace(subject)
+}
+
+// parseBranchHeaders parses the "# branch.*" headers of git status --porcelain=v2 --branch
+func parseBranchHeaders(statusOut string, state *model.RepositoryState) {
+	for _, line := range strings.Split(statusOut, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 || fields[0] != "#" {
+			continue
+		}
+		switch fields[1] {
+		case "branch.head":
+			if fields[2] != "(detached)" {
+				state.Branch = fields[2]
+			}
+		case "branch.upstream":
+			state.Upstream = fields[2]
+		case "branch.ab":
+			if len(fields) == 4 {
+				state.Ahead, _ = strconv.Atoi(strings.TrimPrefix(fields[2], "+"))
+				state.Behind, _ = strconv.Atoi(strings.TrimPrefix(fields[3], "-"))
+			}
+		}
+	}
+}
+
 // CaptureStagingState captures the current staging state of the repository for restoration purposes
 func (r *gitRepositoryImpl) CaptureStagingState(ctx context.Context) (*model.StagingState, error) {
 	statusOut, _, err := r.execGit(ctx, "status", "--porcelain=v1")
@@ -560,7 +685,712 @@ func (r *gitRepositoryImpl) CaptureStagingState(ctx context.Context) (*model.Sta
 
 // CreateCommit creates a git commit with the given message
 func (r *gitRepositoryImpl) CreateCommit(ctx context.Context, message *model.CommitMessage) error {
-	// Format commit message
+	return r.commit(ctx, message)
+}
+
+// AmendCommit replaces the HEAD commit with the index and message, keeping its author
+// like git commit --amend. The new commit is signed like CreateCommit's.
+func (r *gitRepositoryImpl) AmendCommit(ctx context.Context, message *model.CommitMessage) error {
+	return r.commit(ctx, message, "--amend")
+}
+
+// commit runs git commit with message and the extra args, signed if configured. git
+// commit runs the commit hooks (core.hooksPath included), unless message.NoVerify is set.
+func (r *gitRepositoryImpl) commit(ctx context.Context, message *model.CommitMessage, args ...string) error {
+	commitMsg := r.buildCommitMessage(message)
+	commitEnv := r.commitEnv()
+	if message.NoVerify {
+		args = append([]string{"--no-verify"}, args...)
+	}
+	// With pathspecs, only the matching paths are committed (git commit --only), other
+	// staged changes stay staged
+	args = append(args, pathspecArgs(ctx)...)
+
+	// Keys loaded in ssh-agent sign the commit git creates, like the commits built with
+	// plumbing; git runs the commit hooks (post-commit included) on the unsigned commit
+	if agentSigner := r.newAgentSigner(); agentSigner != nil {
+		defer agentSigner.Close()
+		unsignedArgs := append([]string{"commit", "-m", commitMsg}, args...)
+		if err := r.execGitWithEnv(ctx, commitEnv, unsignedArgs...); err != nil {
+			return fmt.Errorf("failed to create commit: %w", err)
+		}
+		if err := r.signRef(ctx, commitEnv, "commit", "HEAD", agentSigner); err != nil {
+			utils.Logger.Debug().Err(err).Msg("Signing with ssh-agent failed, keeping the commit unsigned")
+		}
+		return nil
+	}
+
+	// If signing is enabled, try signed commit first.
+	// Signed commits use git's -c flag which rtk doesn't support, so always use git directly.
+	if r.signingEnabled() {
+		signArgs := append(r.signingConfigArgs(), "commit", "-S", "-m", commitMsg)
+		signArgs = append(signArgs, args...)
+
+		err := r.execGitWithEnvRaw(ctx, commitEnv, signArgs...)
+		if err != nil {
+			// Check if error is signing-related; if so, retry without signing
+			if !isSigningError(err) {
+				return fmt.Errorf("failed to create signed commit: %w", err)
+			}
+			utils.Logger.Debug().Err(err).Msg("Signing failed, creating unsigned commit")
+		} else {
+			return nil // Signed commit succeeded
+		}
+	}
+
+	// Unsigned commit (or signing fallback)
+	unsignedArgs := append([]string{"commit", "-m", commitMsg}, args...)
+	if err := r.execGitWithEnv(ctx, commitEnv, unsignedArgs...); err != nil {
+		return fmt.Errorf("failed to create commit: %w", err)
+	}
+
+	return nil
+}
+
+// UndoLastCommit removes the HEAD commit from the current branch. With keepChanges, its
+// changes stay staged (git reset --soft); otherwise they are discarded along with the
+// uncommitted changes (git reset --hard). The first commit of a branch can only be undone
+// keeping its changes: the branch is then unborn again, with all files staged.
+func (r *gitRepositoryImpl) UndoLastCommit(ctx context.Context, keepChanges bool) error {
+	env := os.Environ()
+	if r.headCommit(ctx, env) == "" {
+		return fmt.Errorf("nothing to undo: no commit on HEAD")
+	}
+
+	if _, err := r.execGitWithEnvOutput(ctx, env, "rev-parse", "--verify", "--quiet", "HEAD~1^{commit}"); err != nil {
+		if !keepChanges {
+			return fmt.Errorf("cannot discard the first commit of the branch, only undo it keeping its changes")
+		}
+		if _, err := r.execGitWithEnvOutput(ctx, env, "update-ref", "-m", "gitcomm: undo", "-d", "HEAD"); err != nil {
+			return fmt.Errorf("failed to undo the first commit: %w", err)
+		}
+		return nil
+	}
+
+	mode := "--soft"
+	if !keepChanges {
+		mode = "--hard"
+	}
+	if _, err := r.execGitWithEnvOutput(ctx, env, "reset", "-q", mode, "HEAD~1"); err != nil {
+		return fmt.Errorf("failed to undo the last commit: %w", err)
+	}
+	return nil
+}
+
+// CreateCommitOnBranch creates a commit from the index on branch without switching the worktree.
+// A missing branch is created from HEAD. An existing branch gets the commit on top of its tip,
+// with only the staged changes (the index compared to HEAD) applied to its tree, so a branch
+// that diverged from HEAD keeps its own changes; staged changes conflicting with them are refused.
+// Uses plumbing (write-tree, commit-tree, update-ref), running the commit hooks like git commit.
+func (r *gitRepositoryImpl) CreateCommitOnBranch(ctx context.Context, message *model.CommitMessage, branch string) error {
+	// Plumbing output is parsed, so always use git directly (rtk may rewrite output)
+	env := r.commitEnv()
+
+	if _, err := r.execGitWithEnvOutput(ctx, env, "check-ref-format", "--branch", branch); err != nil {
+		return fmt.Errorf("invalid branch name %q: %w", branch, err)
+	}
+	ref := "refs/heads/" + branch
+
+	// The branch tip is both the parent and the expected old value for update-ref
+	oldValue := strings.Repeat("0", 40) // update-ref: ref must not exist yet
+	head := r.headCommit(ctx, env)
+	parent := head
+	if tip, err := r.execGitWithEnvOutput(ctx, env, "rev-parse", "--verify", "--quiet", ref+"^{commit}"); err == nil {
+		parent = strings.TrimSpace(tip)
+		oldValue = parent
+	}
+
+	var commitHash string
+	var err error
+	if parent == head {
+		commitHash, err = r.commitIndexTree(ctx, env, message, parent)
+	} else {
+		commitHash, err = r.commitStagedChangesOn(ctx, env, message, head, parent, branch)
+	}
+	if err != nil {
+		return err
+	}
+
+	reflogMsg := "gitcomm: commit on " + branch
+	if _, err := r.execGitWithEnvOutput(ctx, env, "update-ref", "-m", reflogMsg, ref, commitHash, oldValue); err != nil {
+		return fmt.Errorf("failed to update branch %s: %w", branch, err)
+	}
+
+	utils.Logger.Debug().Str("branch", branch).Str("commit", commitHash).Msg("Created commit on branch")
+	return nil
+}
+
+// CreateCommitObject creates a commit object from the index on top of HEAD without updating any ref.
+// Returns the commit hash; the object stays unreachable until referenced (e.g. exported as a patch).
+// The commit hooks run like for git commit.
+func (r *gitRepositoryImpl) CreateCommitObject(ctx context.Context, message *model.CommitMessage) (string, error) {
+	env := r.commitEnv()
+	return r.commitIndexTree(ctx, env, message, r.headCommit(ctx, env))
+}
+
+// CreateTag creates the annotated tag name on revision (HEAD if empty) with message as
+// annotation. Like commits, the tag is signed with the configured SSH or OpenPGP key; unlike
+// commits, a tag that cannot be signed is not created unsigned: the signing error is
+// returned, and noSign creates an unsigned tag. Reports whether the tag was signed.
+func (r *gitRepositoryImpl) CreateTag(ctx context.Context, name, revision, message string) (bool, error) {
+	if revision == "" {
+		revision = "HEAD"
+	}
+	// The tagger identity is the committer identity
+	env := r.commitEnv()
+
+	if _, err := r.execGitWithEnvOutput(ctx, env, "check-ref-format", "refs/tags/"+name); err != nil {
+		return false, fmt.Errorf("invalid tag name %q: %w", name, err)
+	}
+	if !r.signer.Enabled {
+		if err := r.execGitWithEnvRaw(ctx, env, "tag", "--annotate", "-m", message, name, revision); err != nil {
+			return false, fmt.Errorf("failed to create tag %s: %w", name, err)
+		}
+		return false, nil
+	}
+
+	// Keys loaded in ssh-agent sign the tag object, as they sign commits
+	if agentSigner := r.newAgentSigner(); agentSigner != nil {
+		defer agentSigner.Close()
+		if err := r.execGitWithEnvRaw(ctx, env, "tag", "--annotate", "-m", message, name, revision); err != nil {
+			return false, fmt.Errorf("failed to create tag %s: %w", name, err)
+		}
+		if err := r.signRef(ctx, env, "tag", "refs/tags/"+name, agentSigner); err != nil {
+			if _, deleteErr := r.execGitWithEnvOutput(ctx, env, "update-ref", "-d", "refs/tags/"+name); deleteErr != nil {
+				utils.Logger.Debug().Err(deleteErr).Str("tag", name).Msg("Failed to delete the unsigned tag")
+			}
+			return false, fmt.Errorf("%w: tag %s not created: %v", ErrGitSigningFailed, name, err)
+		}
+		return true, nil
+	}
+
+	if !r.signingEnabled() {
+		return false, fmt.Errorf("%w: tag %s not created: %v", ErrGitSigningFailed, name, r.gpgKey.err)
+	}
+	// Signed tags use git's -c flag which rtk doesn't support, so always use git directly
+	signArgs := append(r.signingConfigArgs(), "tag", "--sign", "-m", message, name, revision)
+	if err := r.execGitWithEnvRaw(ctx, env, signArgs...); err != nil {
+		if isSigningError(err) {
+			return false, fmt.Errorf("%w: tag %s not created: %v", ErrGitSigningFailed, name, err)
+		}
+		return false, fmt.Errorf("failed to create signed tag %s: %w", name, err)
+	}
+	return true, nil
+}
+
+// ExportPatch writes revision as a format-patch style mail file into dir and returns the file path
+func (r *gitRepositoryImpl) ExportPatch(ctx context.Context, revision string, dir string) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create patch directory: %w", err)
+	}
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve patch directory: %w", err)
+	}
+
+	// format-patch output is the list of written files, so always use git directly
+	out, err := r.execGitWithEnvOutput(ctx, os.Environ(), "format-patch", "-1", "--output-directory", absDir, revision)
+	if err != nil {
+		return "", fmt.Errorf("failed to export patch: %w", err)
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// ResolvePushTarget returns where branch is pushed, following pushRemote/pushDefault
+// and fork (triangular) setups
+func (r *gitRepositoryImpl) ResolvePushTarget(ctx context.Context, branch string) (*remote.Target, error) {
+	out, _, err := r.runGitCommand(ctx, r.gitBin, false, "remote")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list remotes: %w", err)
+	}
+	remotes := strings.Fields(out)
+
+	var lookupErr error
+	lookup := func(key string) string {
+		values, err := r.GetConfigValues(ctx, key)
+		if err != nil {
+			lookupErr = err
+			return ""
+		}
+		if len(values) == 0 {
+			return ""
+		}
+		// The last value wins, as for git itself
+		return values[len(values)-1]
+	}
+
+	target, err := remote.Resolve(branch, lookup, remotes)
+	if lookupErr != nil {
+		return nil, lookupErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve push target: %w", err)
+	}
+	return target, nil
+}
+
+// Push pushes the target's local branch to its remote branch, setting the upstream if requested
+func (r *gitRepositoryImpl) Push(ctx context.Context, target *remote.Target) error {
+	args := []string{"push"}
+	if target.SetUpstream {
+		args = append(args, "--set-upstream")
+	}
+	args = append(args, target.Remote, "refs/heads/"+target.LocalBranch+":refs/heads/"+target.RemoteBranch)
+
+	// Always use git directly: rtk condenses the output that reports rejected pushes
+	if _, _, err := r.runGitCommand(ctx, r.gitBin, false, args...); err != nil {
+		return fmt.Errorf("failed to push %s to %s: %w", target.LocalBranch, target.Remote, err)
+	}
+	return nil
+}
+
+// GetConfigValues returns all values of a git config key (nil if unset)
+func (r *gitRepositoryImpl) GetConfigValues(ctx context.Context, key string) ([]string, error) {
+	out, err := r.execGitWithEnvOutput(ctx, os.Environ(), "config", "--get-all", key)
+	if err != nil {
+		// git config exits with 1 when the key is not set
+		var gitErr *ErrGitCommandFailed
+		if errors.As(err, &gitErr) && gitErr.ExitCode == 1 {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read git config %s: %w", key, err)
+	}
+
+	var values []string
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			values = append(values, line)
+		}
+	}
+	return values, nil
+}
+
+// headCommit returns the HEAD commit hash, or "" for an unborn HEAD
+func (r *gitRepositoryImpl) headCommit(ctx context.Context, env []string) string {
+	head, err := r.execGitWithEnvOutput(ctx, env, "rev-parse", "--verify", "--quiet", "HEAD^{commit}")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(head)
+}
+
+// commitIndexTree runs the commit hooks, writes the index as a tree and creates a (signed if
+// configured) commit object with the given parent ("" for a root commit). Returns the new
+// commit hash.
+func (r *gitRepositoryImpl) commitIndexTree(ctx context.Context, env []string, message *model.CommitMessage, parent string) (string, error) {
+	// pre-commit may stage files (formatters), so the tree is written after it
+	raw, err := r.hookedMessage(ctx, env, message)
+	if err != nil {
+		return "", err
+	}
+
+	tree, err := r.execGitWithEnvOutput(ctx, env, "write-tree")
+	if err != nil {
+		return "", fmt.Errorf("failed to write index tree: %w", err)
+	}
+
+	return r.commitTree(ctx, env, strings.TrimSpace(tree), raw, parent)
+}
+
+// commitStagedChangesOn runs the commit hooks and creates a commit on top of tip (the tip of
+// branch) whose tree is the tree of tip with the staged changes applied: the index tree is
+// merged with tip, head being the merge base. The merge runs in a temporary index, leaving
+// the index untouched. Returns the new commit hash.
+func (r *gitRepositoryImpl) commitStagedChangesOn(ctx context.Context, env []string, message *model.CommitMessage, head, tip, branch string) (string, error) {
+	raw, err := r.hookedMessage(ctx, env, message)
+	if err != nil {
+		return "", err
+	}
+	indexTree, err := r.execGitWithEnvOutput(ctx, env, "write-tree")
+	if err != nil {
+		return "", fmt.Errorf("failed to write index tree: %w", err)
+	}
+
+	dir, err := os.MkdirTemp("", "gitcomm-index-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary index: %w", err)
+	}
+	defer os.RemoveAll(dir)
+	previous := r.index
+	r.index = filepath.Join(dir, "index")
+	defer func() { r.index = previous }()
+
+	// Before the first commit, the staged changes are relative to the empty tree
+	base := head
+	if base == "" {
+		if _, err := r.execGitWithEnvOutput(ctx, env, "read-tree", "--empty"); err != nil {
+			return "", fmt.Errorf("failed to read empty tree: %w", err)
+		}
+		if base, err = r.execGitWithEnvOutput(ctx, env, "write-tree"); err != nil {
+			return "", fmt.Errorf("failed to write empty tree: %w", err)
+		}
+		base = strings.TrimSpace(base)
+	}
+
+	mergeArgs := []string{"read-tree", "-m", "-i", "--aggressive", base, tip, strings.TrimSpace(indexTree)}
+	if _, err := r.execGitWithEnvOutput(ctx, env, mergeArgs...); err != nil {
+		return "", fmt.Errorf("failed to apply staged changes to branch %s: %w", branch, err)
+	}
+	unmerged, err := r.execGitWithEnvOutput(ctx, env, "ls-files", "--unmerged", "-z")
+	if err != nil {
+		return "", fmt.Errorf("failed to apply staged changes to branch %s: %w", branch, err)
+	}
+	if unmerged != "" {
+		return "", fmt.Errorf("staged changes conflict with branch %s: %s", branch, strings.Join(unmergedPaths(unmerged), ", "))
+	}
+
+	tree, err := r.execGitWithEnvOutput(ctx, env, "write-tree")
+	if err != nil {
+		return "", fmt.Errorf("failed to write merged tree: %w", err)
+	}
+	return r.commitTree(ctx, env, strings.TrimSpace(tree), raw, tip)
+}
+
+// unmergedPaths returns the paths listed by "git ls-files --unmerged -z", once each
+func unmergedPaths(out string) []string {
+	var paths []string
+	for _, entry := range strings.Split(out, "\x00") {
+		_, path, ok := strings.Cut(entry, "\t")
+		if ok && !slices.Contains(paths, path) {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// commitTree creates a (signed if configured) commit object for tree with the given
+// raw message and parent ("" for a root commit). Returns the new commit hash.
+func (r *gitRepositoryImpl) commitTree(ctx context.Context, env []string, tree, message, parent string) (string, error) {
+	commitArgs := []string{"commit-tree", tree, "-m", message}
+	if parent != "" {
+		commitArgs = append(commitArgs, "-p", parent)
+	}
+
+	// Keys loaded in ssh-agent sign the commit object directly, without ssh-keygen
+	if agentSigner := r.newAgentSigner(); agentSigner != nil {
+		defer agentSigner.Close()
+		unsigned, err := r.execGitWithEnvOutput(ctx, env, commitArgs...)
+		if err != nil {
+			return "", fmt.Errorf("failed to create commit: %w", err)
+		}
+		signed, err := r.signObject(ctx, env, "commit", strings.TrimSpace(unsigned), agentSigner)
+		if err != nil {
+			utils.Logger.Debug().Err(err).Msg("Signing with ssh-agent failed, creating unsigned commit")
+			return strings.TrimSpace(unsigned), nil
+		}
+		return signed, nil
+	}
+
+	var commitHash string
+	var err error
+	if r.signingEnabled() {
+		signArgs := append(r.signingConfigArgs(), commitArgs...)
+		commitHash, err = r.execGitWithEnvOutput(ctx, env, append(signArgs, "-S")...)
+		if err != nil {
+			if !isSigningError(err) {
+				return "", fmt.Errorf("failed to create signed commit: %w", err)
+			}
+			utils.Logger.Debug().Err(err).Msg("Signing failed, creating unsigned commit")
+		}
+	}
+	if commitHash == "" {
+		commitHash, err = r.execGitWithEnvOutput(ctx, env, commitArgs...)
+		if err != nil {
+			return "", fmt.Errorf("failed to create commit: %w", err)
+		}
+	}
+	return strings.TrimSpace(commitHash), nil
+}
+
+// GetCommitState returns the files changed by revision, with their diffs, as a RepositoryState
+// (in StagedFiles) so a message can be generated for an existing commit
+func (r *gitRepositoryImpl) GetCommitState(ctx context.Context, revision string) (*model.RepositoryState, error) {
+	// Output is parsed, so always use git directly
+	statusOut, _, err := r.runGitCommand(ctx, r.gitBin, false, "diff-tree", "--root", "--no-commit-id", "-r", "-C", "--name-status", revision)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files of %s: %w", revision, err)
+	}
+
+	state := &model.RepositoryState{StagedFiles: parseNameStatus(statusOut)}
+
+	diffOut, _, err := r.runGitCommand(ctx, r.gitBin, false, "diff-tree", "--root", "--no-commit-id", "-r", "-C", "-p", unifiedArg(ctx), revision)
+	if err != nil {
+		utils.Logger.Debug().Err(err).Msg("Failed to get commit diff, continuing with empty diffs")
+		diffOut = ""
+	}
+	diffs, binary := parseDiff(diffOut)
+	for i, file := range state.StagedFiles {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("failed to get state of %s: %w", revision, err)
+		}
+		if binary[file.Path] {
+			state.StagedFiles[i].Diff = r.binarySummary(ctx, file, revision+"^", revision)
+		} else if diff, ok := diffs[file.Path]; ok {
+			state.StagedFiles[i].Diff = r.applySizeLimit(ctx, diff, file, revision+"^", revision)
+		}
+	}
+
+	return state, nil
+}
+
+// StagedDiffStats returns the number of changed files and added/removed lines in the index
+func (r *gitRepositoryImpl) StagedDiffStats(ctx context.Context) (*model.DiffStats, error) {
+	// Output is parsed, so always use git directly
+	out, _, err := r.runGitCommand(ctx, r.gitBin, false, append([]string{"diff", "--cached", "--numstat"}, pathspecArgs(ctx)...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute staged diff stats: %w", err)
+	}
+	return parseNumstat(out), nil
+}
+
+// parseNumstat parses git --numstat output ("added\tremoved\tpath", "-\t-\tpath" for binary files)
+func parseNumstat(out string) *model.DiffStats {
+	stats := &model.DiffStats{}
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) < 3 {
+			continue
+		}
+		stats.FilesChanged++
+		// Binary files report "-" and count as zero lines
+		if added, err := strconv.Atoi(fields[0]); err == nil {
+			stats.LinesAdded += added
+		}
+		if removed, err := strconv.Atoi(fields[1]); err == nil {
+			stats.LinesRemoved += removed
+		}
+	}
+	return stats
+}
+
+// parseNameStatus parses git --name-status output ("M\tpath", "R100\told\tnew") into file changes
+func parseNameStatus(out string) []model.FileChange {
+	var files []model.FileChange
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Split(line, "\t")
+		if len(fields) < 2 || fields[0] == "" {
+			continue
+		}
+		file := model.FileChange{
+			Path:   fields[len(fields)-1],
+			Status: porcelainStatusToString(fields[0][0]),
+		}
+		// Renames and copies: "R087\told\tnew"
+		if len(fields) == 3 {
+			file.OldPath = fields[1]
+			file.Similarity, _ = strconv.Atoi(fields[0][1:])
+		}
+		files = append(files, file)
+	}
+	return files
+}
+
+// IsPushed reports whether commit is reachable from any remote-tracking branch
+func (r *gitRepositoryImpl) IsPushed(ctx context.Context, commit string) (bool, error) {
+	out, _, err := r.runGitCommand(ctx, r.gitBin, false, "branch", "--remotes", "--contains", commit)
+	if err != nil {
+		return false, fmt.Errorf("failed to check remote branches for %s: %w", commit, err)
+	}
+	return strings.TrimSpace(out) != "", nil
+}
+
+// GitDir returns the absolute path of the repository's git directory (shared by worktrees)
+func (r *gitRepositoryImpl) GitDir(ctx context.Context) (string, error) {
+	out, _, err := r.runGitCommand(ctx, r.gitBin, false, "rev-parse", "--path-format=absolute", "--git-common-dir")
+	if err != nil {
+		return "", fmt.Errorf("failed to locate git directory: %w", err)
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// WorkTreeDir returns the absolute path of the top-level directory of the worktree
+func (r *gitRepositoryImpl) WorkTreeDir(ctx context.Context) (string, error) {
+	out, _, err := r.runGitCommand(ctx, r.gitBin, false, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", fmt.Errorf("failed to locate worktree: %w", err)
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// IndexPath returns the absolute path of the index file of the worktree
+func (r *gitRepositoryImpl) IndexPath(ctx context.Context) (string, error) {
+	out, _, err := r.runGitCommand(ctx, r.gitBin, false, "rev-parse", "--path-format=absolute", "--git-path", "index")
+	if err != nil {
+		return "", fmt.Errorf("failed to locate index: %w", err)
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// GetCurrentBranch returns the name of the current branch ("" when HEAD is detached)
+func (r *gitRepositoryImpl) GetCurrentBranch(ctx context.Context) (string, error) {
+	out, _, err := r.runGitCommand(ctx, r.gitBin, false, "symbolic-ref", "--quiet", "--short", "HEAD")
+	var failed *ErrGitCommandFailed
+	if errors.As(err, &failed) && failed.ExitCode == 1 {
+		// --quiet: exit code 1 without message means HEAD is detached
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read current branch: %w", err)
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// DefaultBranch returns the default branch of the repository: the HEAD of remote (origin
+// if empty) when it is known, else the first local branch among init.defaultBranch, main
+// and master, else init.defaultBranch or git's built-in default, master
+func (r *gitRepositoryImpl) DefaultBranch(ctx context.Context, remote string) (string, error) {
+	remote = cmp.Or(remote, "origin")
+	out, _, err := r.runGitCommand(ctx, r.gitBin, false, "symbolic-ref", "--quiet", "--short", "refs/remotes/"+remote+"/HEAD")
+	if err == nil {
+		if branch, ok := strings.CutPrefix(strings.TrimSpace(out), remote+"/"); ok && branch != "" {
+			return branch, nil
+		}
+	}
+
+	values, err := r.GetConfigValues(ctx, "init.defaultBranch")
+	if err != nil {
+		return "", err
+	}
+	var configured string
+	if len(values) > 0 {
+		configured = values[len(values)-1]
+	}
+	for _, name := range []string{configured, "main", "master"} {
+		if name == "" {
+			continue
+		}
+		if _, _, err := r.runGitCommand(ctx, r.gitBin, false, "rev-parse", "--verify", "--quiet", "refs/heads/"+name); err == nil {
+			return name, nil
+		}
+	}
+	return cmp.Or(configured, "master"), nil
+}
+
+// SwitchToNewBranch creates the branch name at HEAD and switches to it, keeping the
+// index and worktree like git switch --create
+func (r *gitRepositoryImpl) SwitchToNewBranch(ctx context.Context, name string) error {
+	if _, _, err := r.runGitCommand(ctx, r.gitBin, false, "check-ref-format", "--branch", name); err != nil {
+		return fmt.Errorf("invalid branch name %q", name)
+	}
+	if _, _, err := r.runGitCommand(ctx, r.gitBin, false, "rev-parse", "--verify", "--quiet", "refs/heads/"+name); err == nil {
+		return fmt.Errorf("branch %s already exists", name)
+	}
+	if _, _, err := r.runGitCommand(ctx, r.gitBin, false, "switch", "--quiet", "--create", name); err != nil {
+		return fmt.Errorf("failed to create branch %s: %w", name, err)
+	}
+	return nil
+}
+
+// ListTags returns the names of the tags reachable from revision (HEAD if empty)
+func (r *gitRepositoryImpl) ListTags(ctx context.Context, revision string) ([]string, error) {
+	if revision == "" {
+		revision = "HEAD"
+	}
+	out, _, err := r.runGitCommand(ctx, r.gitBin, false, "tag", "--list", "--merged", revision)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+	return strings.Fields(out), nil
+}
+
+// RewordCommits replaces the messages of the given commits (by full hash) on the current branch.
+// Every commit from the oldest reworded one up to HEAD is recreated with the same tree,
+// author and date, then the branch is moved. The worktree and index are not touched.
+// Returns the mapping from old to new hashes of all recreated commits.
+func (r *gitRepositoryImpl) RewordCommits(ctx context.Context, messages map[string]*model.CommitMessage) (map[string]string, error) {
+	env := r.commitEnv()
+	if len(messages) == 0 {
+		return map[string]string{}, nil
+	}
+
+	branchRef, err := r.execGitWithEnvOutput(ctx, env, "symbolic-ref", "--quiet", "HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("cannot reword commits on a detached HEAD: %w", err)
+	}
+	branchRef = strings.TrimSpace(branchRef)
+	head := r.headCommit(ctx, env)
+
+	// Oldest first, with parents: "<hash> <parent>..."
+	revList, err := r.execGitWithEnvOutput(ctx, env, "rev-list", "--reverse", "--topo-order", "--parents", "HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list branch history: %w", err)
+	}
+
+	rewritten := make(map[string]string)
+	started := false
+	pending := len(messages)
+	newHead := head
+	for _, line := range strings.Split(strings.TrimSpace(revList), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		commit, parents := fields[0], fields[1:]
+		message, reword := messages[commit]
+		if !started && !reword {
+			continue
+		}
+		started = true
+
+		if len(parents) > 1 {
+			return nil, fmt.Errorf("cannot reword across merge commit %s", commit[:7])
+		}
+		parent := ""
+		if len(parents) == 1 {
+			parent = parents[0]
+			if mapped, ok := rewritten[parent]; ok {
+				parent = mapped
+			}
+		}
+
+		newHash, err := r.recreateCommit(ctx, commit, parent, message)
+		if err != nil {
+			return nil, err
+		}
+		rewritten[commit] = newHash
+		newHead = newHash
+		if reword {
+			pending--
+		}
+	}
+	if pending > 0 {
+		return nil, fmt.Errorf("%d commit(s) to reword are not on the current branch", pending)
+	}
+
+	// Only move the branch if nobody else updated it meanwhile
+	if _, err := r.execGitWithEnvOutput(ctx, env, "update-ref", "-m", "gitcomm: reword commits", branchRef, newHead, head); err != nil {
+		return nil, fmt.Errorf("failed to update %s: %w", branchRef, err)
+	}
+	return rewritten, nil
+}
+
+// recreateCommit creates a copy of commit on parent, keeping its tree, author and author date.
+// The message is replaced when message is not nil.
+func (r *gitRepositoryImpl) recreateCommit(ctx context.Context, commit, parent string, message *model.CommitMessage) (string, error) {
+	info, err := r.execGitWithEnvOutput(ctx, os.Environ(), "log", "-1", "--format=%T%x00%an%x00%ae%x00%ad%x00%B", "--date=raw", commit)
+	if err != nil {
+		return "", fmt.Errorf("failed to read commit %s: %w", commit, err)
+	}
+	fields := strings.SplitN(info, "\x00", 5)
+	if len(fields) != 5 {
+		return "", fmt.Errorf("failed to parse commit %s: unexpected format", commit)
+	}
+
+	raw := strings.TrimRight(fields[4], "\n")
+	if message != nil {
+		raw = r.buildCommitMessage(message)
+	}
+
+	env := append(r.commitEnv(),
+		"GIT_AUTHOR_NAME="+fields[1],
+		"GIT_AUTHOR_EMAIL="+fields[2],
+		"GIT_AUTHOR_DATE="+fields[3],
+	)
+	return r.commitTree(ctx, env, fields[0], raw, parent)
+}
+
+// buildCommitMessage formats the message and appends the Signed-off-by trailer if needed
+func (r *gitRepositoryImpl) buildCommitMessage(message *model.CommitMessage) string {
 	formatter := &formattingService{}
 	commitMsg := formatter.format(message)
 
@@ -568,75 +1398,222 @@ func (r *gitRepositoryImpl) CreateCommit(ctx context.Context, message *model.Com
 	if message.Signoff {
 		userName := r.config.UserName
 		userEmail := r.config.UserEmail
-		if userName != "" && userEmail != "" {
-			commitMsg += fmt.Sprintf("\n\nSigned-off-by: %s <%s>", userName, userEmail)
+		// A dedicated sign-off identity (e.g. corporate email) takes precedence over the author
+		if message.SignoffIdentity != nil {
+			userName = message.SignoffIdentity.Name
+			userEmail = message.SignoffIdentity.Email
+		}
+		signoffLine := fmt.Sprintf("Signed-off-by: %s <%s>", userName, userEmail)
+		// Skip if the message already carries this exact trailer (e.g. typed in the footer)
+		if userName != "" && userEmail != "" && !strings.Contains(commitMsg, signoffLine) {
+			commitMsg += "\n\n" + signoffLine
 		}
 	}
 
-	// Build commit command with author env vars
-	commitEnv := append(os.Environ(),
+	return commitMsg
+}
+
+// commitEnv returns the environment for commit commands with author and committer identity
+func (r *gitRepositoryImpl) commitEnv() []string {
+	return append(os.Environ(),
 		"GIT_AUTHOR_NAME="+r.config.UserName,
 		"GIT_AUTHOR_EMAIL="+r.config.UserEmail,
 		"GIT_COMMITTER_NAME="+r.config.UserName,
 		"GIT_COMMITTER_EMAIL="+r.config.UserEmail,
 	)
+}
 
-	// If signing is enabled, try signed commit first.
-	// Signed commits use git's -c flag which rtk doesn't support, so always use git directly.
-	if r.signer.Enabled {
-		signArgs := []string{
-			"-c", "gpg.format=ssh",
-			"-c", "user.signingkey=" + r.signer.PublicKeyPath,
+// signingConfigArgs returns the git -c flags enabling SSH or OpenPGP signing with the configured key
+func (r *gitRepositoryImpl) signingConfigArgs() []string {
+	if r.signer.Format == "openpgp" {
+		return []string{
+			"-c", "gpg.format=openpgp",
+			"-c", "gpg.program=" + r.signer.Program,
+			"-c", "user.signingkey=" + r.signer.KeyID,
 			"-c", "commit.gpgsign=true",
-			"commit", "-S", "-m", commitMsg,
 		}
+	}
+	args := []string{
+		"-c", "gpg.format=ssh",
+		"-c", "user.signingkey=" + r.signer.PublicKeyPath,
+		"-c", "commit.gpgsign=true",
+	}
+	if r.signer.Program != "" {
+		args = append(args, "-c", "gpg.ssh.program="+r.signer.Program)
+	}
+	return args
+}
 
-		err := r.execGitWithEnvRaw(ctx, commitEnv, signArgs...)
+// isSigningError returns true if a commit failed because of signing (unsigned retry is possible)
+func isSigningError(err error) bool {
+	errStr := err.Error()
+	return strings.Contains(errStr, "signing") ||
+		strings.Contains(errStr, "gpg") ||
+		strings.Contains(errStr, "sign")
+}
+
+// commitRecordFormat is the git log format parsed by parseCommitRecords.
+// Fields are NUL-separated and records are RS-separated so messages can contain anything.
+const commitRecordFormat = "--format=%H%x00%an%x00%ae%x00%at%x00%B%x1e"
+
+// ListCommits returns the last limit non-merge commits reachable from revision, newest first
+func (r *gitRepositoryImpl) ListCommits(ctx context.Context, revision string, limit int) ([]model.CommitInfo, error) {
+	if revision == "" {
+		revision = "HEAD"
+	}
+
+	// Always use git directly: rtk condenses log output.
+	args := []string{"log", "--no-merges", commitRecordFormat}
+	if limit > 0 {
+		args = append(args, fmt.Sprintf("--max-count=%d", limit))
+	}
+	args = append(args, revision, "--")
+
+	out, _, err := r.runGitCommand(ctx, r.gitBin, false, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list commits: %w", err)
+	}
+
+	return parseCommitRecords(out)
+}
+
+// LastAuthorCommit returns the most recent commit by the configured git user that touched
+// any of paths within the last since (no limit if zero), or nil if there is none
+func (r *gitRepositoryImpl) LastAuthorCommit(ctx context.Context, paths []string, since time.Duration) (*model.CommitInfo, error) {
+	if r.config.UserEmail == "" || len(paths) == 0 {
+		return nil, nil
+	}
+
+	// --author is a regex: match the exact email between angle brackets
+	args := []string{"log", "--no-merges", "-1", commitRecordFormat,
+		"--author=<" + regexp.QuoteMeta(r.config.UserEmail) + ">"}
+	if since > 0 {
+		args = append(args, fmt.Sprintf("--since=%d seconds ago", int(since.Seconds())))
+	}
+	args = append(args, "HEAD", "--")
+	args = append(args, paths...)
+
+	out, _, err := r.runGitCommand(ctx, r.gitBin, false, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find previous commit: %w", err)
+	}
+
+	commits, err := parseCommitRecords(out)
+	if err != nil || len(commits) == 0 {
+		return nil, err
+	}
+	return &commits[0], nil
+}
+
+// AuthorCommits returns the non-merge commits by the configured git user on any local
+// branch since the given time, newest first
+func (r *gitRepositoryImpl) AuthorCommits(ctx context.Context, since time.Time) ([]model.CommitInfo, error) {
+	if r.config.UserEmail == "" {
+		return nil, fmt.Errorf("user.email is not set: cannot tell which commits are yours")
+	}
+
+	// --author is a regex: match the exact email between angle brackets
+	args := []string{"log", "--no-merges", "--branches", commitRecordFormat,
+		"--author=<" + regexp.QuoteMeta(r.config.UserEmail) + ">",
+		"--since=" + since.Format(time.RFC3339), "--"}
+	out, _, err := r.runGitCommand(ctx, r.gitBin, false, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list your commits: %w", err)
+	}
+	return parseCommitRecords(out)
+}
+
+// parseCommitRecords parses git log output produced with commitRecordFormat
+func parseCommitRecords(out string) ([]model.CommitInfo, error) {
+	var commits []model.CommitInfo
+	for _, record := range strings.Split(out, "\x1e") {
+		record = strings.TrimLeft(record, "\n")
+		if record == "" {
+			continue
+		}
+		fields := strings.SplitN(record, "\x00", 5)
+		if len(fields) != 5 {
+			return nil, fmt.Errorf("failed to parse commit record: unexpected format")
+		}
+		seconds, err := strconv.ParseInt(fields[3], 10, 64)
 		if err != nil {
-			// Check if error is signing-related; if so, retry without signing
-			errStr := err.Error()
-			if strings.Contains(errStr, "signing") ||
-				strings.Contains(errStr, "gpg") ||
-				strings.Contains(errStr, "sign") {
-				utils.Logger.Debug().Err(err).Msg("SSH signing failed, creating unsigned commit")
-			} else {
-				return fmt.Errorf("failed to create signed commit: %w", err)
-			}
-		} else {
-			return nil // Signed commit succeeded
+			return nil, fmt.Errorf("failed to parse commit record: invalid date %q", fields[3])
 		}
+		commits = append(commits, model.CommitInfo{
+			Hash:        fields[0],
+			AuthorName:  fields[1],
+			AuthorEmail: fields[2],
+			Date:        time.Unix(seconds, 0),
+			Message:     strings.TrimRight(fields[4], "\n"),
+		})
 	}
 
-	// Unsigned commit (or signing fallback)
-	unsignedArgs := []string{"commit", "-m", commitMsg}
-	if err := r.execGitWithEnv(ctx, commitEnv, unsignedArgs...); err != nil {
-		return fmt.Errorf("failed to create commit: %w", err)
+	return commits, nil
+}
+
+// withIndex points env to the temporary index, when one is in use
+func (r *gitRepositoryImpl) withIndex(env []string) []string {
+	if r.index == "" {
+		return env
 	}
+	return append(env[:len(env):len(env)], "GIT_INDEX_FILE="+r.index)
+}
 
-	return nil
+// UseTemporaryIndex copies the index to a temporary file used by the following git commands
+func (r *gitRepositoryImpl) UseTemporaryIndex(ctx context.Context) (func(), error) {
+	out, err := r.execGitWithEnvOutput(ctx, os.Environ(), "rev-parse", "--git-path", "index")
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate index: %w", err)
+	}
+	indexPath := strings.TrimSpace(out)
+	if !filepath.IsAbs(indexPath) {
+		indexPath = filepath.Join(r.path, indexPath)
+	}
+
+	dir, err := os.MkdirTemp("", "gitcomm-index-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary index: %w", err)
+	}
+	tmpIndex := filepath.Join(dir, "index")
+
+	// A repository without any staged file yet has no index: git creates the copy when needed
+	data, err := os.ReadFile(indexPath)
+	if err == nil {
+		err = os.WriteFile(tmpIndex, data, 0600)
+	} else if errors.Is(err, os.ErrNotExist) {
+		err = nil
+	}
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, fmt.Errorf("failed to copy index: %w", err)
+	}
+
+	r.index = tmpIndex
+	utils.Logger.Debug().Str("index", tmpIndex).Msg("Using temporary index")
+	return func() {
+		r.index = ""
+		os.RemoveAll(dir)
+	}, nil
 }
 
 // execGitWithEnv executes a git command with custom environment variables.
 // Used for commit commands that need GIT_AUTHOR_NAME/EMAIL and signing config.
 // Commit commands are fire-and-forget, so they are proxied through rtk when available.
 func (r *gitRepositoryImpl) execGitWithEnv(ctx context.Context, env []string, args ...string) error {
-	// Handle nil context gracefully
-	if ctx == nil {
-		ctx = context.Background()
-	}
-
 	var cmd *exec.Cmd
+	var cancel context.CancelFunc
 	if r.useRTK {
 		// rtk git <args...> — run in repo directory via cmd.Dir
 		rtkArgs := append([]string{"git"}, args...)
-		cmd = exec.CommandContext(ctx, r.rtkBin, rtkArgs...)
+		ctx, cmd, cancel = newGitCmd(ctx, r.rtkBin, rtkArgs...)
 		cmd.Dir = r.path
 	} else {
 		// git -C <path> <args...>
 		allArgs := append([]string{"-C", r.path}, args...)
-		cmd = exec.CommandContext(ctx, r.gitBin, allArgs...)
+		ctx, cmd, cancel = newGitCmd(ctx, r.gitBin, allArgs...)
 	}
-	cmd.Env = env
+	defer cancel()
+	cmd.Env = r.withIndex(env)
 
 	var stderr bytes.Buffer
 	cmd.Stderr = &stderr
@@ -668,6 +1645,9 @@ func (r *gitRepositoryImpl) execGitWithEnv(ctx context.Context, env []string, ar
 		logEvent.Int("exit_code", exitCode).
 			Str("stderr", strings.TrimSpace(stderr.String())).
 			Msg("git command failed")
+		if ctxErr := contextError(ctx, subcommand); ctxErr != nil {
+			return ctxErr
+		}
 		return categorizeError(subcommand, args, exitCode, stderr.String())
 	}
 
@@ -678,16 +1658,20 @@ func (r *gitRepositoryImpl) execGitWithEnv(ctx context.Context, env []string, ar
 // execGitWithEnvRaw executes a git command with custom environment variables, bypassing rtk.
 // Required for signed commits which use git's -c flag (rtk doesn't support -c).
 func (r *gitRepositoryImpl) execGitWithEnvRaw(ctx context.Context, env []string, args ...string) error {
-	// Handle nil context gracefully
-	if ctx == nil {
-		ctx = context.Background()
-	}
+	_, err := r.execGitWithEnvOutput(ctx, env, args...)
+	return err
+}
 
+// execGitWithEnvOutput is execGitWithEnvRaw returning the command stdout.
+// Used for plumbing commands whose output is needed (e.g. commit-tree).
+func (r *gitRepositoryImpl) execGitWithEnvOutput(ctx context.Context, env []string, args ...string) (string, error) {
 	allArgs := append([]string{"-C", r.path}, args...)
-	cmd := exec.CommandContext(ctx, r.gitBin, allArgs...)
-	cmd.Env = env
+	ctx, cmd, cancel := newGitCmd(ctx, r.gitBin, allArgs...)
+	defer cancel()
+	cmd.Env = r.withIndex(env)
 
-	var stderr bytes.Buffer
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
 	fullCmd := cmd.String()
@@ -717,11 +1701,14 @@ func (r *gitRepositoryImpl) execGitWithEnvRaw(ctx context.Context, env []string,
 		logEvent.Int("exit_code", exitCode).
 			Str("stderr", strings.TrimSpace(stderr.String())).
 			Msg("git command failed")
-		return categorizeError(subcommand, args, exitCode, stderr.String())
+		if ctxErr := contextError(ctx, subcommand); ctxErr != nil {
+			return "", ctxErr
+		}
+		return "", categorizeError(subcommand, args, exitCode, stderr.String())
 	}
 
 	logEvent.Int("exit_code", 0).Msg("git command succeeded")
-	return nil
+	return stdout.String(), nil
 }
 
 // StageAllFiles stages all unstaged files (equivalent to git add -A)
@@ -737,8 +1724,8 @@ func (r *gitRepositoryImpl) StageAllFiles(ctx context.Context) error {
 func (r *gitRepositoryImpl) StageModifiedFiles(ctx context.Context) (*model.AutoStagingResult, error) {
 	startTime := time.Now()
 
-	// Get current status
-	statusOut, _, err := r.execGit(ctx, "status", "--porcelain=v1")
+	// Get current status, limited to the pathspecs if any
+	statusOut, _, err := r.execGit(ctx, append([]string{"status", "--porcelain=v1"}, pathspecArgs(ctx)...)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get status: %w", err)
 	}
@@ -765,6 +1752,7 @@ func (r *gitRepositoryImpl) StageModifiedFiles(ctx context.Context) (*model.Auto
 			filesToStage = append(filesToStage, rawPath)
 		}
 	}
+	filesToStage = r.stageablePaths(ctx, filesToStage, nil)
 
 	if len(filesToStage) == 0 {
 		return &model.AutoStagingResult{
@@ -778,9 +1766,19 @@ func (r *gitRepositoryImpl) StageModifiedFiles(ctx context.Context) (*model.Auto
 	var stagedFiles []string
 	var failedFiles []model.StagingFailure
 
-	for _, file := range filesToStage {
-		_, _, err := r.execGit(ctx, "add", "--", file)
-		if err != nil {
+	for i, file := range filesToStage {
+		// Abort between files on Ctrl+C or timeout, unstaging what was staged so far
+		if err := ctx.Err(); err != nil {
+			r.rollbackStaging(stagedFiles)
+			return &model.AutoStagingResult{
+				StagedFiles: []string{},
+				FailedFiles: []model.StagingFailure{},
+				Success:     false,
+				Duration:    time.Since(startTime),
+			}, fmt.Errorf("%w: interrupted: %w", utils.ErrStagingFailed, err)
+		}
+
+		if err := r.stageFile(ctx, i+1, file); err != nil {
 			failedFiles = append(failedFiles, model.StagingFailure{
 				FilePath:  file,
 				Error:     err,
@@ -817,14 +1815,15 @@ func (r *gitRepositoryImpl) StageModifiedFiles(ctx context.Context) (*model.Auto
 func (r *gitRepositoryImpl) StageAllFilesIncludingUntracked(ctx context.Context) (*model.AutoStagingResult, error) {
 	startTime := time.Now()
 
-	// Get current status
-	statusOut, _, err := r.execGit(ctx, "status", "--porcelain=v1")
+	// Get current status, limited to the pathspecs if any
+	statusOut, _, err := r.execGit(ctx, append([]string{"status", "--porcelain=v1"}, pathspecArgs(ctx)...)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get status: %w", err)
 	}
 
 	// Filter all changed files from worktree (including untracked)
 	var filesToStage []string
+	untracked := make(map[string]bool)
 	lines := strings.Split(statusOut, "\n")
 	for _, line := range lines {
 		if len(line) < 4 || line[2] != ' ' {
@@ -843,8 +1842,12 @@ func (r *gitRepositoryImpl) StageAllFilesIncludingUntracked(ctx context.Context)
 				rawPath = parts[1]
 			}
 			filesToStage = append(filesToStage, rawPath)
+			if y == '?' {
+				untracked[rawPath] = true
+			}
 		}
 	}
+	filesToStage = r.stageablePaths(ctx, filesToStage, untracked)
 
 	if len(filesToStage) == 0 {
 		return &model.AutoStagingResult{
@@ -858,9 +1861,19 @@ func (r *gitRepositoryImpl) StageAllFilesIncludingUntracked(ctx context.Context)
 	var stagedFiles []string
 	var failedFiles []model.StagingFailure
 
-	for _, file := range filesToStage {
-		_, _, err := r.execGit(ctx, "add", "--", file)
-		if err != nil {
+	for i, file := range filesToStage {
+		// Abort between files on Ctrl+C or timeout, unstaging what was staged so far
+		if err := ctx.Err(); err != nil {
+			r.rollbackStaging(stagedFiles)
+			return &model.AutoStagingResult{
+				StagedFiles: []string{},
+				FailedFiles: []model.StagingFailure{},
+				Success:     false,
+				Duration:    time.Since(startTime),
+			}, fmt.Errorf("%w: interrupted: %w", utils.ErrStagingFailed, err)
+		}
+
+		if err := r.stageFile(ctx, i+1, file); err != nil {
 			failedFiles = append(failedFiles, model.StagingFailure{
 				FilePath:  file,
 				Error:     err,
@@ -893,44 +1906,82 @@ func (r *gitRepositoryImpl) StageAllFilesIncludingUntracked(ctx context.Context)
 	}, nil
 }
 
+// rollbackStaging unstages files after an interrupted staging. It uses its own short-lived
+// context since the caller's context is already done.
+func (r *gitRepositoryImpl) rollbackStaging(files []string) {
+	if len(files) == 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), interruptGracePeriod)
+	defer cancel()
+	rollbackArgs := append([]string{"reset", "HEAD", "--"}, files...)
+	if _, _, err := r.execGit(ctx, rollbackArgs...); err != nil {
+		utils.Logger.Debug().Err(err).Msg("Failed to roll back interrupted staging")
+	}
+}
+
+// stageFile stages the nth file (from 1) of an auto-staging
+func (r *gitRepositoryImpl) stageFile(ctx context.Context, n int, file string) error {
+	if r.faults.stageFile == n {
+		return fmt.Errorf("%w: staging %s", ErrInjectedFault, file)
+	}
+	_, _, err := r.execGit(ctx, "add", "--", file)
+	return err
+}
+
 // UnstageFiles unstages the specified files, restoring them to their pre-staged state
 func (r *gitRepositoryImpl) UnstageFiles(ctx context.Context, files []string) error {
 	if len(files) == 0 {
 		return nil
 	}
 
-	// Use git reset HEAD to unstage files
+	switch {
+	case r.faults.restoreTimeout:
+		return fmt.Errorf("%w: git reset failed: %w", utils.ErrRestorationFailed, context.DeadlineExceeded)
+	case r.faults.unstage:
+		return fmt.Errorf("%w: git reset failed: %w", utils.ErrRestorationFailed, ErrInjectedFault)
+	}
+
+	// Use git reset HEAD to unstage files; a timeout stays visible to the caller
 	resetArgs := append([]string{"reset", "HEAD", "--"}, files...)
 	_, _, err := r.execGit(ctx, resetArgs...)
 	if err != nil {
-		return fmt.Errorf("%w: git reset failed: %v", utils.ErrRestorationFailed, err)
+		return fmt.Errorf("%w: git reset failed: %w", utils.ErrRestorationFailed, err)
 	}
 
 	return nil
 }
 
-// prepareCommitSigner creates a CommitSigner from GitConfig if SSH signing is configured.
+// prepareCommitSigner creates a CommitSigner from GitConfig if SSH or OpenPGP signing is configured.
 //
 // Signing is enabled when all of the following are true:
-//   - gpg.format = "ssh"
+//   - gpg.format = "ssh", or "openpgp" (git's default when unset)
 //   - user.signingkey is set
-//   - commit.gpgsign is not explicitly false
+//   - for SSH, commit.gpgsign is not explicitly false; for OpenPGP, commit.gpgsign is
+//     true, as with git commit
 //   - noSign flag is false
 //
-// Signing is delegated to git CLI, so no private key loading is needed here.
+// Signing is delegated to git CLI, so no private key loading is needed here. The OpenPGP
+// secret key is looked up on the first signature (see signingEnabled), not here.
 func prepareCommitSigner(gitConfig *gitconfig.GitConfig, noSign bool) *gitconfig.CommitSigner {
+	format := gitConfig.GPGFormat
+	if format == "" {
+		format = "openpgp"
+	}
 	signer := &gitconfig.CommitSigner{
 		PrivateKeyPath: "",
-		PublicKeyPath:  gitConfig.SigningKey,
-		Format:         gitConfig.GPGFormat,
+		PublicKeyPath:  "",
+		Format:         format,
 		Enabled:        false,
 	}
 
-	gitConfig.CommitGPGSign = true
+	// SSH keys sign unless commit.gpgsign is explicitly false; a user.signingkey alone is
+	// common for OpenPGP (tags, git commit -S), so it signs only when commit.gpgsign is set
+	gpgSign := gitConfig.CommitGPGSign || (format == "ssh" && !gitConfig.CommitGPGSignSet)
 
 	// Check if signing should be disabled by flag (highest precedence)
-	if noSign || !gitConfig.CommitGPGSign || gitConfig.GPGFormat != "ssh" {
-		utils.Logger.Debug().Bool("noSign", noSign).Bool("commitGPGSign", gitConfig.CommitGPGSign).Str("gpgFormat", gitConfig.GPGFormat).Msg("signing disabled")
+	if noSign || !gpgSign || (format != "ssh" && format != "openpgp") {
+		utils.Logger.Debug().Bool("noSign", noSign).Bool("commitGPGSign", gpgSign).Str("gpgFormat", format).Msg("signing disabled")
 		return signer
 	}
 
@@ -940,15 +1991,31 @@ func prepareCommitSigner(gitConfig *gitconfig.GitConfig, noSign bool) *gitconfig
 		return signer
 	}
 
+	if format == "openpgp" {
+		signer.Program = cmp.Or(gitConfig.GPGProgram, defaultGPGProgram)
+		signer.KeyID = gitConfig.SigningKey
+		signer.Enabled = true
+
+		utils.Logger.Debug().
+			Str("key", signer.KeyID).
+			Str("program", signer.Program).
+			Bool("enabled", signer.Enabled).
+			Msg("OpenPGP commit signing configured (delegated to git CLI)")
+		return signer
+	}
+
 	// Derive private key path from public key path (remove .pub extension)
 	privateKeyPath := strings.TrimSuffix(gitConfig.SigningKey, ".pub")
 
+	signer.PublicKeyPath = gitConfig.SigningKey
 	signer.PrivateKeyPath = privateKeyPath
+	signer.Program = gitConfig.SSHProgram
 	signer.Enabled = true
 
 	utils.Logger.Debug().
 		Str("publicKey", signer.PublicKeyPath).
 		Str("privateKey", signer.PrivateKeyPath).
+		Str("program", signer.Program).
 		Str("format", signer.Format).
 		Bool("enabled", signer.Enabled).
 		Msg("SSH commit signing configured (delegated to git CLI)")
@@ -956,31 +2023,39 @@ func prepareCommitSigner(gitConfig *gitconfig.GitConfig, noSign bool) *gitconfig
 	return signer
 }
 
-// generateMetadata generates metadata string for large files/diffs
-func (r *gitRepositoryImpl) generateMetadata(filePath string, status string) string {
-	fullPath := filepath.Join(r.path, filePath)
-	info, err := os.Stat(fullPath)
+// generateMetadata generates metadata string for large files/diffs, from the blob of the
+// file in newRev ("" for the index), or in oldRev for deleted files. The worktree is never
+// read: it may hold unstaged changes, or miss a file deleted after staging.
+func (r *gitRepositoryImpl) generateMetadata(ctx context.Context, file model.FileChange, oldRev, newRev string) string {
+	object := newRev + ":" + file.Path
+	if file.Status == "deleted" {
+		object = oldRev + ":" + file.Path
+	}
+	content, _, err := r.runGitCommand(ctx, r.gitBin, false, "cat-file", "blob", object)
 	if err != nil {
-		return fmt.Sprintf("file: %s\nsize: unknown\nlines: unknown\nchanges: %s", filePath, status)
+		return fmt.Sprintf("file: %s\nsize: unknown\nlines: unknown\nchanges: %s", file.Path, file.Status)
 	}
 
-	// Count lines if it's a text file
-	lineCount := 0
-	content, err := os.ReadFile(fullPath)
-	if err == nil {
-		lineCount = strings.Count(string(content), "\n") + 1
+	lineCount := strings.Count(content, "\n")
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		lineCount++
 	}
-
-	return fmt.Sprintf("file: %s\nsize: %d bytes\nlines: %d\nchanges: %s", filePath, info.Size(), lineCount, status)
+	return fmt.Sprintf("file: %s\nsize: %d bytes\nlines: %d\nchanges: %s", file.Path, len(content), lineCount, file.Status)
 }
 
-// applySizeLimit checks if diff exceeds 5000 characters and replaces with metadata if needed.
+// applySizeLimit truncates a diff larger than the configured size (see truncateDiff), and
+// replaces it with metadata when even its hunk headers do not fit.
 // This token optimization ensures large files/diffs don't consume excessive tokens for AI models.
-func (r *gitRepositoryImpl) applySizeLimit(diff string, filePath string, status string) string {
-	if len(diff) > maxDiffSize {
-		return r.generateMetadata(filePath, status)
+// oldRev and newRev hold the two versions of the file, newRev "" standing for the index.
+func (r *gitRepositoryImpl) applySizeLimit(ctx context.Context, diff string, file model.FileChange, oldRev, newRev string) string {
+	maxSize := diffLimits(ctx).MaxSize
+	if maxSize == 0 || len(diff) <= maxSize {
+		return diff
+	}
+	if truncated, ok := truncateDiff(diff, maxSize); ok {
+		return truncated
 	}
-	return diff
+	return r.generateMetadata(ctx, file, oldRev, newRev)
 }
 
 // formattingService is a temporary helper for formatting
@@ -990,21 +2065,16 @@ type formattingService struct{}
 func (f *formattingService) format(message *model.CommitMessage) string {
 	var parts []string
 
-	header := message.Type
-	if message.Scope != "" {
-		header = fmt.Sprintf("%s(%s)", header, message.Scope)
-	}
-	header = fmt.Sprintf("%s: %s", header, message.Subject)
-	parts = append(parts, header)
+	parts = append(parts, message.Header())
 
 	if message.Body != "" {
 		parts = append(parts, "")
 		parts = append(parts, message.Body)
 	}
 
-	if message.Footer != "" {
+	if footer := message.FooterText(); footer != "" {
 		parts = append(parts, "")
-		parts = append(parts, message.Footer)
+		parts = append(parts, footer)
 	}
 
 	return strings.Join(parts, "\n")
@@ -2,14 +2,18 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/golgoth31/gitcomm/internal/model"
 	"github.com/golgoth31/gitcomm/internal/utils"
+	"github.com/golgoth31/gitcomm/pkg/testutil"
 )
 
 func TestNewGitRepository_ExtractsConfigBeforeOpening(t *testing.T) {
@@ -17,24 +21,8 @@ func TestNewGitRepository_ExtractsConfigBeforeOpening(t *testing.T) {
 	utils.InitLogger(true)
 
 	// Create temporary directory with .git/config
-	tmpDir := t.TempDir()
-
-	// Initialize git repository
-	cmd := exec.Command("git", "init", tmpDir)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to initialize git repository: %v", err)
-	}
-
-	// Configure git user in .git/config
-	cmd = exec.Command("git", "-C", tmpDir, "config", "user.name", "Test User")
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to configure git user.name: %v", err)
-	}
-
-	cmd = exec.Command("git", "-C", tmpDir, "config", "user.email", "test@example.com")
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to configure git user.email: %v", err)
-	}
+	fixture := testutil.NewRepo(t)
+	tmpDir := fixture.Dir
 
 	// Create repository - should extract config before opening
 	repo, err := NewGitRepository(tmpDir, false, false)
@@ -62,29 +50,64 @@ func TestNewGitRepository_ExtractsConfigBeforeOpening(t *testing.T) {
 	}
 }
 
-func TestCreateCommit_UsesExtractedConfigForAuthor(t *testing.T) {
-	// Setup: Initialize logger
+func TestNewGitRepository_LinkedWorktreeAndBareRepository(t *testing.T) {
 	utils.InitLogger(true)
+	ctx := context.Background()
 
-	// Create temporary directory with .git/config
-	tmpDir := t.TempDir()
+	main := testutil.NewRepoWithCommit(t, map[string]string{"README.md": "# test\n"})
+	linked := filepath.Join(t.TempDir(), "linked")
+	main.Git("worktree", "add", "-q", linked)
 
-	// Initialize git repository
-	cmd := exec.Command("git", "init", tmpDir)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to initialize git repository: %v", err)
+	// A linked worktree has a .git file; its config is the one of the main repository
+	repo, err := NewGitRepository(linked, true, true)
+	if err != nil {
+		t.Fatalf("NewGitRepository() in a linked worktree error = %v", err)
+	}
+	if email := repo.(*gitRepositoryImpl).config.UserEmail; email != testutil.UserEmail {
+		t.Errorf("UserEmail = %q, want %q from the main repository config", email, testutil.UserEmail)
+	}
+	if err := os.WriteFile(filepath.Join(linked, "notes.txt"), []byte("notes\n"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	if _, err := repo.StageAllFilesIncludingUntracked(ctx); err != nil {
+		t.Fatalf("StageAllFilesIncludingUntracked() error = %v", err)
+	}
+	if err := repo.CreateCommit(ctx, &model.CommitMessage{Type: "docs", Subject: "add notes"}); err != nil {
+		t.Fatalf("CreateCommit() in a linked worktree error = %v", err)
+	}
+	if got := main.Git("log", "-1", "--format=%s|%ae", "linked"); got != "docs: add notes|"+testutil.UserEmail+"\n" {
+		t.Errorf("linked branch tip = %q, want the commit with the repository identity", got)
 	}
 
-	// Configure git user in .git/config
-	cmd = exec.Command("git", "-C", tmpDir, "config", "user.name", "Commit Author")
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to configure git user.name: %v", err)
+	// A bare repository has no worktree, but its history can be read
+	bare := filepath.Join(t.TempDir(), "bare.git")
+	main.Git("clone", "-q", "--bare", main.Dir, bare)
+	repo, err = NewGitRepository(bare, true, true)
+	if err != nil {
+		t.Fatalf("NewGitRepository() in a bare repository error = %v", err)
+	}
+	commits, err := repo.ListCommits(ctx, "HEAD", 0)
+	if err != nil || len(commits) != 1 || commits[0].Subject() != "initial commit" {
+		t.Errorf("ListCommits() in a bare repository = %+v, %v, want the initial commit", commits, err)
 	}
 
-	cmd = exec.Command("git", "-C", tmpDir, "config", "user.email", "author@example.com")
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to configure git user.email: %v", err)
+	if _, err := NewGitRepository(t.TempDir(), true, true); !errors.Is(err, utils.ErrNotGitRepository) {
+		t.Errorf("NewGitRepository() outside a repository error = %v, want ErrNotGitRepository", err)
 	}
+}
+
+func TestCreateCommit_UsesExtractedConfigForAuthor(t *testing.T) {
+	// Setup: Initialize logger
+	utils.InitLogger(true)
+
+	// Create temporary directory with .git/config
+	fixture := testutil.NewRepo(t)
+	tmpDir := fixture.Dir
+
+	// Configure git user in .git/config
+	fixture.Git("config", "user.name", "Commit Author")
+
+	fixture.Git("config", "user.email", "author@example.com")
 
 	// Create repository
 	repo, err := NewGitRepository(tmpDir, false, false)
@@ -99,10 +122,7 @@ func TestCreateCommit_UsesExtractedConfigForAuthor(t *testing.T) {
 	}
 
 	// Stage the file
-	cmd = exec.Command("git", "-C", tmpDir, "add", testFile)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to stage file: %v", err)
-	}
+	fixture.Git("add", testFile)
 
 	// Create commit
 	commitMsg := &model.CommitMessage{
@@ -114,11 +134,7 @@ func TestCreateCommit_UsesExtractedConfigForAuthor(t *testing.T) {
 	}
 
 	// Verify commit author using git log
-	cmd = exec.Command("git", "-C", tmpDir, "log", "-1", "--format=%an <%ae>")
-	output, err := cmd.Output()
-	if err != nil {
-		t.Fatalf("Failed to get commit author: %v", err)
-	}
+	output := fixture.Git("log", "-1", "--format=%an <%ae>")
 
 	author := string(output)
 	expectedAuthor := "Commit Author <author@example.com>"
@@ -127,25 +143,61 @@ func TestCreateCommit_UsesExtractedConfigForAuthor(t *testing.T) {
 	}
 }
 
+func TestCreateCommit_UsesSignoffIdentityOverride(t *testing.T) {
+	utils.InitLogger(true)
+
+	fixture := testutil.NewRepo(t)
+	tmpDir := fixture.Dir
+	fixture.Git("config", "user.name", "Commit Author")
+	fixture.Git("config", "user.email", "author@example.com")
+
+	repo, err := NewGitRepository(tmpDir, true, true)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+
+	testFile := filepath.Join(tmpDir, "test.txt")
+	if err := os.WriteFile(testFile, []byte("test content"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	fixture.Git("add", testFile)
+
+	commitMsg := &model.CommitMessage{
+		Type:            "test",
+		Subject:         "test commit",
+		Signoff:         true,
+		SignoffIdentity: &model.Identity{Name: "Jane Doe", Email: "jane.doe@corp.example.com"},
+	}
+	if err := repo.CreateCommit(context.Background(), commitMsg); err != nil {
+		t.Fatalf("Failed to create commit: %v", err)
+	}
+
+	output := fixture.Git("log", "-1", "--format=%an <%ae>%n%B")
+
+	log := string(output)
+	if !strings.HasPrefix(log, "Commit Author <author@example.com>\n") {
+		t.Errorf("Expected author to be unchanged, got:\n%s", log)
+	}
+	if !strings.Contains(log, "Signed-off-by: Jane Doe <jane.doe@corp.example.com>") {
+		t.Errorf("Expected sign-off identity override, got:\n%s", log)
+	}
+	if strings.Contains(log, "Signed-off-by: Commit Author") {
+		t.Errorf("Expected author not to be used for sign-off, got:\n%s", log)
+	}
+}
+
 func TestCreateCommit_UsesDefaultsWhenConfigMissing(t *testing.T) {
 	// Setup: Initialize logger
 	utils.InitLogger(true)
 
 	// Create temporary directory with .git but no config
-	tmpDir := t.TempDir()
-
-	// Initialize git repository
-	cmd := exec.Command("git", "init", tmpDir)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to initialize git repository: %v", err)
-	}
+	fixture := testutil.NewRepo(t)
+	tmpDir := fixture.Dir
 
 	// Don't configure git user - should use defaults
 	// Remove any user config that git init might have created
-	cmd = exec.Command("git", "-C", tmpDir, "config", "--unset", "user.name")
-	cmd.Run() // Ignore error if not set
-	cmd = exec.Command("git", "-C", tmpDir, "config", "--unset", "user.email")
-	cmd.Run() // Ignore error if not set
+	fixture.TryGit("config", "--unset", "user.name")  // Ignore error if not set
+	fixture.TryGit("config", "--unset", "user.email") // Ignore error if not set
 
 	// Save and clear HOME to prevent reading global config
 	originalHome := os.Getenv("HOME")
@@ -167,10 +219,7 @@ func TestCreateCommit_UsesDefaultsWhenConfigMissing(t *testing.T) {
 	}
 
 	// Stage the file
-	cmd = exec.Command("git", "-C", tmpDir, "add", testFile)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to stage file: %v", err)
-	}
+	fixture.Git("add", testFile)
 
 	// Create commit
 	commitMsg := &model.CommitMessage{
@@ -182,11 +231,7 @@ func TestCreateCommit_UsesDefaultsWhenConfigMissing(t *testing.T) {
 	}
 
 	// Verify commit author uses defaults
-	cmd = exec.Command("git", "-C", tmpDir, "log", "-1", "--format=%an <%ae>")
-	output, err := cmd.Output()
-	if err != nil {
-		t.Fatalf("Failed to get commit author: %v", err)
-	}
+	output := fixture.Git("log", "-1", "--format=%an <%ae>")
 
 	author := string(output)
 	expectedAuthor := "gitcomm <gitcomm@local>"
@@ -200,13 +245,8 @@ func TestGetRepositoryState_PopulatesDiffForStagedFiles(t *testing.T) {
 	utils.InitLogger(true)
 
 	// Create temporary directory
-	tmpDir := t.TempDir()
-
-	// Initialize git repository
-	cmd := exec.Command("git", "init", tmpDir)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to initialize git repository: %v", err)
-	}
+	fixture := testutil.NewRepo(t)
+	tmpDir := fixture.Dir
 
 	// Create initial file and commit
 	testFile := filepath.Join(tmpDir, "test.txt")
@@ -214,25 +254,16 @@ func TestGetRepositoryState_PopulatesDiffForStagedFiles(t *testing.T) {
 		t.Fatalf("Failed to create test file: %v", err)
 	}
 
-	cmd = exec.Command("git", "-C", tmpDir, "add", testFile)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to stage file: %v", err)
-	}
+	fixture.Git("add", testFile)
 
-	cmd = exec.Command("git", "-C", tmpDir, "commit", "-m", "initial")
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to create commit: %v", err)
-	}
+	fixture.Git("commit", "-m", "initial")
 
 	// Modify and stage file
 	if err := os.WriteFile(testFile, []byte("modified\n"), 0644); err != nil {
 		t.Fatalf("Failed to modify file: %v", err)
 	}
 
-	cmd = exec.Command("git", "-C", tmpDir, "add", testFile)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to stage modified file: %v", err)
-	}
+	fixture.Git("add", testFile)
 
 	// Get repository state (noRTK=true: this test verifies per-file Diff parsing)
 	repo, err := NewGitRepository(tmpDir, false, true)
@@ -260,13 +291,8 @@ func TestGetRepositoryState_LeavesDiffEmptyForUnstagedFiles(t *testing.T) {
 	utils.InitLogger(true)
 
 	// Create temporary directory
-	tmpDir := t.TempDir()
-
-	// Initialize git repository
-	cmd := exec.Command("git", "init", tmpDir)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to initialize git repository: %v", err)
-	}
+	fixture := testutil.NewRepo(t)
+	tmpDir := fixture.Dir
 
 	// Create file and commit
 	testFile := filepath.Join(tmpDir, "test.txt")
@@ -274,15 +300,9 @@ func TestGetRepositoryState_LeavesDiffEmptyForUnstagedFiles(t *testing.T) {
 		t.Fatalf("Failed to create test file: %v", err)
 	}
 
-	cmd = exec.Command("git", "-C", tmpDir, "add", testFile)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to stage file: %v", err)
-	}
+	fixture.Git("add", testFile)
 
-	cmd = exec.Command("git", "-C", tmpDir, "commit", "-m", "initial")
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to create commit: %v", err)
-	}
+	fixture.Git("commit", "-m", "initial")
 
 	// Modify file but don't stage (unstaged change)
 	if err := os.WriteFile(testFile, []byte("unstaged change\n"), 0644); err != nil {
@@ -315,13 +335,8 @@ func TestGetRepositoryState_DiffComputationAccuracy(t *testing.T) {
 	utils.InitLogger(true)
 
 	// Create temporary directory
-	tmpDir := t.TempDir()
-
-	// Initialize git repository
-	cmd := exec.Command("git", "init", tmpDir)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to initialize git repository: %v", err)
-	}
+	fixture := testutil.NewRepo(t)
+	tmpDir := fixture.Dir
 
 	// Create file with specific content and commit
 	testFile := filepath.Join(tmpDir, "test.txt")
@@ -330,15 +345,9 @@ func TestGetRepositoryState_DiffComputationAccuracy(t *testing.T) {
 		t.Fatalf("Failed to create test file: %v", err)
 	}
 
-	cmd = exec.Command("git", "-C", tmpDir, "add", testFile)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to stage file: %v", err)
-	}
+	fixture.Git("add", testFile)
 
-	cmd = exec.Command("git", "-C", tmpDir, "commit", "-m", "initial")
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to create commit: %v", err)
-	}
+	fixture.Git("commit", "-m", "initial")
 
 	// Make specific changes: modify line 2, add line 4
 	modifiedContent := "line1\nline2_modified\nline3\nline4_new\n"
@@ -346,10 +355,7 @@ func TestGetRepositoryState_DiffComputationAccuracy(t *testing.T) {
 		t.Fatalf("Failed to modify file: %v", err)
 	}
 
-	cmd = exec.Command("git", "-C", tmpDir, "add", testFile)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to stage modified file: %v", err)
-	}
+	fixture.Git("add", testFile)
 
 	// Get repository state (noRTK=true: this test verifies per-file Diff parsing)
 	repo, err := NewGitRepository(tmpDir, false, true)
@@ -384,13 +390,8 @@ func TestGetRepositoryState_LargeDiffShowsMetadataOnly(t *testing.T) {
 	utils.InitLogger(true)
 
 	// Create temporary directory
-	tmpDir := t.TempDir()
-
-	// Initialize git repository
-	cmd := exec.Command("git", "init", tmpDir)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to initialize git repository: %v", err)
-	}
+	fixture := testutil.NewRepo(t)
+	tmpDir := fixture.Dir
 
 	// Create initial file with small content and commit
 	testFile := filepath.Join(tmpDir, "large.txt")
@@ -399,15 +400,9 @@ func TestGetRepositoryState_LargeDiffShowsMetadataOnly(t *testing.T) {
 		t.Fatalf("Failed to create test file: %v", err)
 	}
 
-	cmd = exec.Command("git", "-C", tmpDir, "add", testFile)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to stage file: %v", err)
-	}
+	fixture.Git("add", testFile)
 
-	cmd = exec.Command("git", "-C", tmpDir, "commit", "-m", "initial")
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to create commit: %v", err)
-	}
+	fixture.Git("commit", "-m", "initial")
 
 	// Create large content (>5000 chars) and stage
 	largeContent := strings.Repeat("line with content\n", 400) // ~6000+ chars
@@ -415,10 +410,7 @@ func TestGetRepositoryState_LargeDiffShowsMetadataOnly(t *testing.T) {
 		t.Fatalf("Failed to write large content: %v", err)
 	}
 
-	cmd = exec.Command("git", "-C", tmpDir, "add", testFile)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to stage large file: %v", err)
-	}
+	fixture.Git("add", testFile)
 
 	// Get repository state (noRTK=true: this test verifies per-file Diff size limiting)
 	repo, err := NewGitRepository(tmpDir, false, true)
@@ -454,13 +446,8 @@ func TestGetRepositoryState_LargeNewFileShowsMetadataOnly(t *testing.T) {
 	utils.InitLogger(true)
 
 	// Create temporary directory
-	tmpDir := t.TempDir()
-
-	// Initialize git repository
-	cmd := exec.Command("git", "init", tmpDir)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to initialize git repository: %v", err)
-	}
+	fixture := testutil.NewRepo(t)
+	tmpDir := fixture.Dir
 
 	// Create large new file (>5000 chars) and stage
 	testFile := filepath.Join(tmpDir, "large_new.txt")
@@ -469,10 +456,7 @@ func TestGetRepositoryState_LargeNewFileShowsMetadataOnly(t *testing.T) {
 		t.Fatalf("Failed to create large file: %v", err)
 	}
 
-	cmd = exec.Command("git", "-C", tmpDir, "add", testFile)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to stage large file: %v", err)
-	}
+	fixture.Git("add", testFile)
 
 	// Get repository state (noRTK=true: this test verifies per-file Diff size limiting)
 	repo, err := NewGitRepository(tmpDir, false, true)
@@ -508,13 +492,8 @@ func TestGetRepositoryState_HandlesFileReadErrors(t *testing.T) {
 	utils.InitLogger(true)
 
 	// Create temporary directory
-	tmpDir := t.TempDir()
-
-	// Initialize git repository
-	cmd := exec.Command("git", "init", tmpDir)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to initialize git repository: %v", err)
-	}
+	fixture := testutil.NewRepo(t)
+	tmpDir := fixture.Dir
 
 	// Create file and commit
 	testFile := filepath.Join(tmpDir, "test.txt")
@@ -522,25 +501,16 @@ func TestGetRepositoryState_HandlesFileReadErrors(t *testing.T) {
 		t.Fatalf("Failed to create test file: %v", err)
 	}
 
-	cmd = exec.Command("git", "-C", tmpDir, "add", testFile)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to stage file: %v", err)
-	}
+	fixture.Git("add", testFile)
 
-	cmd = exec.Command("git", "-C", tmpDir, "commit", "-m", "initial")
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to create commit: %v", err)
-	}
+	fixture.Git("commit", "-m", "initial")
 
 	// Modify and stage file
 	if err := os.WriteFile(testFile, []byte("modified\n"), 0644); err != nil {
 		t.Fatalf("Failed to modify file: %v", err)
 	}
 
-	cmd = exec.Command("git", "-C", tmpDir, "add", testFile)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to stage modified file: %v", err)
-	}
+	fixture.Git("add", testFile)
 
 	// Remove file to cause read error (file exists in index but not in worktree)
 	if err := os.Remove(testFile); err != nil {
@@ -572,13 +542,8 @@ func TestGetRepositoryState_HandlesDiffComputationFailures(t *testing.T) {
 	utils.InitLogger(true)
 
 	// Create temporary directory
-	tmpDir := t.TempDir()
-
-	// Initialize git repository
-	cmd := exec.Command("git", "init", tmpDir)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to initialize git repository: %v", err)
-	}
+	fixture := testutil.NewRepo(t)
+	tmpDir := fixture.Dir
 
 	// Create file and commit
 	testFile := filepath.Join(tmpDir, "test.txt")
@@ -586,15 +551,9 @@ func TestGetRepositoryState_HandlesDiffComputationFailures(t *testing.T) {
 		t.Fatalf("Failed to create test file: %v", err)
 	}
 
-	cmd = exec.Command("git", "-C", tmpDir, "add", testFile)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to stage file: %v", err)
-	}
+	fixture.Git("add", testFile)
 
-	cmd = exec.Command("git", "-C", tmpDir, "commit", "-m", "initial")
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to create commit: %v", err)
-	}
+	fixture.Git("commit", "-m", "initial")
 
 	// Get repository state
 	repo, err := NewGitRepository(tmpDir, false, false)
@@ -618,83 +577,12 @@ func TestGetRepositoryState_HandlesUnmergedFiles(t *testing.T) {
 	// Setup: Initialize logger
 	utils.InitLogger(true)
 
-	// Create temporary directory
-	tmpDir := t.TempDir()
-
-	// Initialize git repository
-	cmd := exec.Command("git", "init", tmpDir)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to initialize git repository: %v", err)
-	}
-
-	// Configure git user
-	cmd = exec.Command("git", "-C", tmpDir, "config", "user.name", "Test User")
-	cmd.Run()
-	cmd = exec.Command("git", "-C", tmpDir, "config", "user.email", "test@example.com")
-	cmd.Run()
-
-	// Create file and commit
-	testFile := filepath.Join(tmpDir, "test.txt")
-	if err := os.WriteFile(testFile, []byte("content\n"), 0644); err != nil {
-		t.Fatalf("Failed to create test file: %v", err)
-	}
-
-	cmd = exec.Command("git", "-C", tmpDir, "add", testFile)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to stage file: %v", err)
-	}
-
-	cmd = exec.Command("git", "-C", tmpDir, "commit", "-m", "initial")
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to create commit: %v", err)
-	}
-
-	// Create a branch and make conflicting changes
-	cmd = exec.Command("git", "-C", tmpDir, "checkout", "-b", "feature")
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to create branch: %v", err)
-	}
-
-	if err := os.WriteFile(testFile, []byte("feature change\n"), 0644); err != nil {
-		t.Fatalf("Failed to modify file: %v", err)
-	}
-
-	cmd = exec.Command("git", "-C", tmpDir, "add", testFile)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to stage file: %v", err)
-	}
-
-	cmd = exec.Command("git", "-C", tmpDir, "commit", "-m", "feature commit")
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to create commit: %v", err)
-	}
-
-	// Switch back to main and make conflicting change
-	cmd = exec.Command("git", "-C", tmpDir, "checkout", "main")
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to checkout main: %v", err)
-	}
-
-	if err := os.WriteFile(testFile, []byte("main change\n"), 0644); err != nil {
-		t.Fatalf("Failed to modify file: %v", err)
-	}
-
-	cmd = exec.Command("git", "-C", tmpDir, "add", testFile)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to stage file: %v", err)
-	}
-
-	cmd = exec.Command("git", "-C", tmpDir, "commit", "-m", "main commit")
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to create commit: %v", err)
-	}
-
-	// Merge feature branch (will create conflict)
-	cmd = exec.Command("git", "-C", tmpDir, "merge", "feature", "--no-edit")
-	cmd.Run() // Ignore error - merge will fail with conflict
+	// Merge a branch with a conflicting change
+	fixture := testutil.NewRepoWithCommit(t, map[string]string{"test.txt": "content\n"})
+	fixture.Conflict("test.txt", "main change\n", "feature change\n")
 
 	// Get repository state - should handle unmerged files gracefully
-	repo, err := NewGitRepository(tmpDir, false, false)
+	repo, err := NewGitRepository(fixture.Dir, false, false)
 	if err != nil {
 		t.Fatalf("Failed to create repository: %v", err)
 	}
@@ -718,43 +606,13 @@ func TestGetRepositoryState_HandlesUnmergedFiles(t *testing.T) {
 func TestGetRepositoryState_ExcludesNewFilesWhenAddAllFalse(t *testing.T) {
 	utils.InitLogger(true)
 
-	tmpDir := t.TempDir()
-
-	// Initialize git repository
-	cmd := exec.Command("git", "init", tmpDir)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to initialize git repository: %v", err)
-	}
-
-	// Create initial file and commit
-	existingFile := filepath.Join(tmpDir, "existing.txt")
-	if err := os.WriteFile(existingFile, []byte("initial\n"), 0644); err != nil {
-		t.Fatalf("Failed to create existing file: %v", err)
-	}
-
-	cmd = exec.Command("git", "-C", tmpDir, "add", existingFile)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to stage existing file: %v", err)
-	}
-
-	cmd = exec.Command("git", "-C", tmpDir, "commit", "-m", "initial")
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to create commit: %v", err)
-	}
-
-	// Create new file and stage it
-	newFile := filepath.Join(tmpDir, "newfile.txt")
-	if err := os.WriteFile(newFile, []byte("new content\n"), 0644); err != nil {
-		t.Fatalf("Failed to create new file: %v", err)
-	}
-
-	cmd = exec.Command("git", "-C", tmpDir, "add", newFile)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to stage new file: %v", err)
-	}
+	// Create initial file and commit, then stage a new file
+	fixture := testutil.NewRepoWithCommit(t, map[string]string{"existing.txt": "initial\n"})
+	fixture.WriteFile("newfile.txt", "new content\n")
+	fixture.Stage("newfile.txt")
 
 	// Get repository state with includeNewFiles = false
-	repo, err := NewGitRepository(tmpDir, false, false)
+	repo, err := NewGitRepository(fixture.Dir, false, false)
 	if err != nil {
 		t.Fatalf("Failed to create repository: %v", err)
 	}
@@ -784,42 +642,13 @@ func TestGetRepositoryState_ExcludesNewFilesWhenAddAllFalse(t *testing.T) {
 func TestGetRepositoryState_IncludesModifiedFilesWhenAddAllFalse(t *testing.T) {
 	utils.InitLogger(true)
 
-	tmpDir := t.TempDir()
-
-	// Initialize git repository
-	cmd := exec.Command("git", "init", tmpDir)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to initialize git repository: %v", err)
-	}
-
-	// Create and commit initial file
-	testFile := filepath.Join(tmpDir, "test.txt")
-	if err := os.WriteFile(testFile, []byte("initial\n"), 0644); err != nil {
-		t.Fatalf("Failed to create test file: %v", err)
-	}
-
-	cmd = exec.Command("git", "-C", tmpDir, "add", testFile)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to stage file: %v", err)
-	}
-
-	cmd = exec.Command("git", "-C", tmpDir, "commit", "-m", "initial")
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to create commit: %v", err)
-	}
-
-	// Modify and stage file
-	if err := os.WriteFile(testFile, []byte("modified\n"), 0644); err != nil {
-		t.Fatalf("Failed to modify file: %v", err)
-	}
-
-	cmd = exec.Command("git", "-C", tmpDir, "add", testFile)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to stage modified file: %v", err)
-	}
+	// Commit a file, then modify and stage it
+	fixture := testutil.NewRepoWithCommit(t, map[string]string{"test.txt": "initial\n"})
+	fixture.WriteFile("test.txt", "modified\n")
+	fixture.Stage("test.txt")
 
 	// Get repository state with includeNewFiles = false
-	repo, err := NewGitRepository(tmpDir, false, false)
+	repo, err := NewGitRepository(fixture.Dir, false, false)
 	if err != nil {
 		t.Fatalf("Failed to create repository: %v", err)
 	}
@@ -849,42 +678,13 @@ func TestGetRepositoryState_IncludesModifiedFilesWhenAddAllFalse(t *testing.T) {
 func TestGetRepositoryState_IncludesDeletedFilesWhenAddAllFalse(t *testing.T) {
 	utils.InitLogger(true)
 
-	tmpDir := t.TempDir()
-
-	// Initialize git repository
-	cmd := exec.Command("git", "init", tmpDir)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to initialize git repository: %v", err)
-	}
-
-	// Create and commit file
-	testFile := filepath.Join(tmpDir, "test.txt")
-	if err := os.WriteFile(testFile, []byte("content\n"), 0644); err != nil {
-		t.Fatalf("Failed to create test file: %v", err)
-	}
-
-	cmd = exec.Command("git", "-C", tmpDir, "add", testFile)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to stage file: %v", err)
-	}
-
-	cmd = exec.Command("git", "-C", tmpDir, "commit", "-m", "initial")
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to create commit: %v", err)
-	}
-
-	// Delete and stage file
-	if err := os.Remove(testFile); err != nil {
-		t.Fatalf("Failed to delete file: %v", err)
-	}
-
-	cmd = exec.Command("git", "-C", tmpDir, "add", testFile)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to stage deleted file: %v", err)
-	}
+	// Commit a file, then delete it and stage the deletion
+	fixture := testutil.NewRepoWithCommit(t, map[string]string{"test.txt": "content\n"})
+	fixture.RemoveFile("test.txt")
+	fixture.Stage("test.txt")
 
 	// Get repository state with includeNewFiles = false
-	repo, err := NewGitRepository(tmpDir, false, false)
+	repo, err := NewGitRepository(fixture.Dir, false, false)
 	if err != nil {
 		t.Fatalf("Failed to create repository: %v", err)
 	}
@@ -917,40 +717,12 @@ func TestGetRepositoryState_IncludesDeletedFilesWhenAddAllFalse(t *testing.T) {
 func TestGetRepositoryState_IncludesRenamedFilesWhenAddAllFalse(t *testing.T) {
 	utils.InitLogger(true)
 
-	tmpDir := t.TempDir()
-
-	// Initialize git repository
-	cmd := exec.Command("git", "init", tmpDir)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to initialize git repository: %v", err)
-	}
-
-	// Create and commit file with substantial content so git detects rename
-	oldFile := filepath.Join(tmpDir, "old.txt")
-	content := strings.Repeat("content line\n", 10) // Enough content for git to detect rename
-	if err := os.WriteFile(oldFile, []byte(content), 0644); err != nil {
-		t.Fatalf("Failed to create old file: %v", err)
-	}
-
-	cmd = exec.Command("git", "-C", tmpDir, "add", oldFile)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to stage file: %v", err)
-	}
-
-	cmd = exec.Command("git", "-C", tmpDir, "commit", "-m", "initial")
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to create commit: %v", err)
-	}
-
-	// Rename file (git mv) - git should detect this as a rename
-	newFile := filepath.Join(tmpDir, "new.txt")
-	cmd = exec.Command("git", "-C", tmpDir, "mv", oldFile, newFile)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to rename file: %v", err)
-	}
+	// Commit a file with substantial content so git detects the rename, then git mv it
+	fixture := testutil.NewRepoWithCommit(t, map[string]string{"old.txt": strings.Repeat("content line\n", 10)})
+	fixture.Rename("old.txt", "new.txt")
 
 	// Get repository state with includeNewFiles = false
-	repo, err := NewGitRepository(tmpDir, false, false)
+	repo, err := NewGitRepository(fixture.Dir, false, false)
 	if err != nil {
 		t.Fatalf("Failed to create repository: %v", err)
 	}
@@ -994,27 +766,13 @@ func TestGetRepositoryState_IncludesRenamedFilesWhenAddAllFalse(t *testing.T) {
 func TestGetRepositoryState_ExcludesManuallyStagedNewFiles(t *testing.T) {
 	utils.InitLogger(true)
 
-	tmpDir := t.TempDir()
-
-	// Initialize git repository
-	cmd := exec.Command("git", "init", tmpDir)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to initialize git repository: %v", err)
-	}
-
 	// Create new file and manually stage it (simulating user running git add before gitcomm)
-	newFile := filepath.Join(tmpDir, "manual.txt")
-	if err := os.WriteFile(newFile, []byte("manual content\n"), 0644); err != nil {
-		t.Fatalf("Failed to create new file: %v", err)
-	}
-
-	cmd = exec.Command("git", "-C", tmpDir, "add", newFile)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to manually stage new file: %v", err)
-	}
+	fixture := testutil.NewRepo(t)
+	fixture.WriteFile("manual.txt", "manual content\n")
+	fixture.Stage("manual.txt")
 
 	// Get repository state with includeNewFiles = false
-	repo, err := NewGitRepository(tmpDir, false, false)
+	repo, err := NewGitRepository(fixture.Dir, false, false)
 	if err != nil {
 		t.Fatalf("Failed to create repository: %v", err)
 	}
@@ -1044,28 +802,13 @@ func TestGetRepositoryState_ExcludesManuallyStagedNewFiles(t *testing.T) {
 func TestGetRepositoryState_ExcludesBinaryNewFiles(t *testing.T) {
 	utils.InitLogger(true)
 
-	tmpDir := t.TempDir()
-
-	// Initialize git repository
-	cmd := exec.Command("git", "init", tmpDir)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to initialize git repository: %v", err)
-	}
-
 	// Create binary file (PNG header) and stage it
-	binaryFile := filepath.Join(tmpDir, "image.png")
-	binaryContent := []byte{0x89, 0x50, 0x4E, 0x47, 0x0D, 0x0A, 0x1A, 0x0A} // PNG header
-	if err := os.WriteFile(binaryFile, binaryContent, 0644); err != nil {
-		t.Fatalf("Failed to create binary file: %v", err)
-	}
-
-	cmd = exec.Command("git", "-C", tmpDir, "add", binaryFile)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to stage binary file: %v", err)
-	}
+	fixture := testutil.NewRepo(t)
+	fixture.WriteFile("image.png", string([]byte{0x89, 0x50, 0x4E, 0x47, 0x0D, 0x0A, 0x1A, 0x0A}))
+	fixture.Stage("image.png")
 
 	// Get repository state with includeNewFiles = false
-	repo, err := NewGitRepository(tmpDir, false, false)
+	repo, err := NewGitRepository(fixture.Dir, false, false)
 	if err != nil {
 		t.Fatalf("Failed to create repository: %v", err)
 	}
@@ -1095,27 +838,13 @@ func TestGetRepositoryState_ExcludesBinaryNewFiles(t *testing.T) {
 func TestGetRepositoryState_IncludesNewFilesWhenAddAllTrue(t *testing.T) {
 	utils.InitLogger(true)
 
-	tmpDir := t.TempDir()
-
-	// Initialize git repository
-	cmd := exec.Command("git", "init", tmpDir)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to initialize git repository: %v", err)
-	}
-
 	// Create new file and stage it
-	newFile := filepath.Join(tmpDir, "newfile.txt")
-	if err := os.WriteFile(newFile, []byte("new content\n"), 0644); err != nil {
-		t.Fatalf("Failed to create new file: %v", err)
-	}
-
-	cmd = exec.Command("git", "-C", tmpDir, "add", newFile)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to stage new file: %v", err)
-	}
+	fixture := testutil.NewRepo(t)
+	fixture.WriteFile("newfile.txt", "new content\n")
+	fixture.Stage("newfile.txt")
 
 	// Get repository state with includeNewFiles = true
-	repo, err := NewGitRepository(tmpDir, false, false)
+	repo, err := NewGitRepository(fixture.Dir, false, false)
 	if err != nil {
 		t.Fatalf("Failed to create repository: %v", err)
 	}
@@ -1174,25 +903,11 @@ func TestUsesRTK_WithoutRTK(t *testing.T) {
 func TestNewGitRepository_NoRTKFlag(t *testing.T) {
 	utils.InitLogger(true)
 
-	tmpDir := t.TempDir()
-
-	// Initialize git repository
-	cmd := exec.Command("git", "init", tmpDir)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to initialize git repository: %v", err)
-	}
+	fixture := testutil.NewRepo(t)
+	tmpDir := fixture.Dir
 
 	// Create initial commit so git commands work
-	cmd = exec.Command("git", "-C", tmpDir, "commit", "--allow-empty", "-m", "initial")
-	cmd.Env = append(os.Environ(),
-		"GIT_AUTHOR_NAME=Test",
-		"GIT_AUTHOR_EMAIL=test@test.com",
-		"GIT_COMMITTER_NAME=Test",
-		"GIT_COMMITTER_EMAIL=test@test.com",
-	)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to create initial commit: %v", err)
-	}
+	fixture.Commit("initial")
 
 	// Create repository with noRTK=true — should never use rtk
 	repo, err := NewGitRepository(tmpDir, false, true)
@@ -1210,13 +925,8 @@ func TestNewGitRepository_NoRTKFlag(t *testing.T) {
 func TestGetRepositoryState_DefaultBehaviorIncludesAll(t *testing.T) {
 	utils.InitLogger(true)
 
-	tmpDir := t.TempDir()
-
-	// Initialize git repository
-	cmd := exec.Command("git", "init", tmpDir)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to initialize git repository: %v", err)
-	}
+	fixture := testutil.NewRepo(t)
+	tmpDir := fixture.Dir
 
 	// Create new file and stage it
 	newFile := filepath.Join(tmpDir, "newfile.txt")
@@ -1224,10 +934,7 @@ func TestGetRepositoryState_DefaultBehaviorIncludesAll(t *testing.T) {
 		t.Fatalf("Failed to create new file: %v", err)
 	}
 
-	cmd = exec.Command("git", "-C", tmpDir, "add", newFile)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to stage new file: %v", err)
-	}
+	fixture.Git("add", newFile)
 
 	// Get repository state without context value (default behavior)
 	repo, err := NewGitRepository(tmpDir, false, false)
@@ -1253,3 +960,906 @@ func TestGetRepositoryState_DefaultBehaviorIncludesAll(t *testing.T) {
 		t.Error("Expected new file to be included by default (backward compatibility), but it was excluded")
 	}
 }
+
+func TestListCommits_ReturnsNewestFirstWithMessages(t *testing.T) {
+	utils.InitLogger(true)
+
+	fixture := testutil.NewRepo(t)
+	tmpDir := fixture.Dir
+
+	messages := []string{
+		"feat: first\n\nSigned-off-by: Jane Doe <jane@example.com>",
+		"fix: second\n\nBody with\nseveral lines",
+	}
+	for _, msg := range messages {
+		fixture.GitEnv(testutil.Identity("Jane Doe", "jane@example.com"), "commit", "--allow-empty", "-m", msg)
+	}
+
+	repo, err := NewGitRepository(tmpDir, true, true)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+
+	commits, err := repo.ListCommits(context.Background(), "", 10)
+	if err != nil {
+		t.Fatalf("ListCommits() error = %v", err)
+	}
+	if len(commits) != 2 {
+		t.Fatalf("Expected 2 commits, got %d", len(commits))
+	}
+
+	if commits[0].Message != messages[1] || commits[1].Message != messages[0] {
+		t.Errorf("Unexpected messages or order: %q, %q", commits[0].Message, commits[1].Message)
+	}
+	if commits[0].AuthorName != "Jane Doe" || commits[0].AuthorEmail != "jane@example.com" {
+		t.Errorf("Unexpected author: %s <%s>", commits[0].AuthorName, commits[0].AuthorEmail)
+	}
+	if len(commits[0].Hash) != 40 {
+		t.Errorf("Expected full hash, got %q", commits[0].Hash)
+	}
+
+	limited, err := repo.ListCommits(context.Background(), "HEAD", 1)
+	if err != nil {
+		t.Fatalf("ListCommits() with limit error = %v", err)
+	}
+	if len(limited) != 1 || limited[0].Hash != commits[0].Hash {
+		t.Errorf("Expected only the newest commit, got %d commits", len(limited))
+	}
+}
+
+func TestListTags_OnlyReachableTags(t *testing.T) {
+	utils.InitLogger(true)
+
+	fixture := testutil.NewRepo(t)
+	tmpDir := fixture.Dir
+	for _, args := range [][]string{
+		{"commit", "--allow-empty", "-m", "feat: first"},
+		{"tag", "v1.0.0"},
+		{"checkout", "-q", "-b", "other"},
+		{"commit", "--allow-empty", "-m", "feat: elsewhere"},
+		{"tag", "v2.0.0"},
+		{"checkout", "-q", "-"},
+		{"commit", "--allow-empty", "-m", "fix: second"},
+		{"tag", "-a", "v1.0.1", "-m", "v1.0.1"},
+	} {
+		fixture.Git(args...)
+	}
+
+	repo, err := NewGitRepository(tmpDir, true, true)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+
+	tags, err := repo.ListTags(context.Background(), "")
+	if err != nil {
+		t.Fatalf("ListTags() error = %v", err)
+	}
+	if strings.Join(tags, ",") != "v1.0.0,v1.0.1" {
+		t.Errorf("ListTags() = %v, want v1.0.0 and v1.0.1 (v2.0.0 is on another branch)", tags)
+	}
+}
+
+func TestGetCurrentBranch(t *testing.T) {
+	utils.InitLogger(true)
+
+	fixture := testutil.NewRepoWithCommit(t, map[string]string{"test.txt": "content\n"})
+	fixture.Git("checkout", "-q", "-b", "feature/JIRA-1234-add-login")
+	repo, err := NewGitRepository(fixture.Dir, true, true)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+
+	branch, err := repo.GetCurrentBranch(context.Background())
+	if err != nil || branch != "feature/JIRA-1234-add-login" {
+		t.Errorf("GetCurrentBranch() = %q, %v, want feature/JIRA-1234-add-login", branch, err)
+	}
+
+	fixture.Git("checkout", "-q", "--detach")
+	branch, err = repo.GetCurrentBranch(context.Background())
+	if err != nil || branch != "" {
+		t.Errorf("GetCurrentBranch() on a detached HEAD = %q, %v, want \"\"", branch, err)
+	}
+}
+
+func TestGetWorkspaceSummary(t *testing.T) {
+	utils.InitLogger(true)
+
+	fixture := testutil.NewRepoWithCommit(t, map[string]string{"staged.txt": "a\n", "modified.txt": "b\n"})
+	fixture.WriteFile("staged.txt", "a2\n")
+	fixture.Stage("staged.txt")
+	fixture.WriteFile("modified.txt", "b2\n")
+	fixture.WriteFile("untracked.txt", "c\n")
+	repo, err := NewGitRepository(fixture.Dir, true, true)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+
+	summary, err := repo.GetWorkspaceSummary(context.Background())
+	if err != nil {
+		t.Fatalf("GetWorkspaceSummary() error = %v", err)
+	}
+	if summary.Branch != testutil.DefaultBranch || summary.Upstream != "" {
+		t.Errorf("branch = %q, upstream = %q, want %s without upstream", summary.Branch, summary.Upstream, testutil.DefaultBranch)
+	}
+	if summary.Staged != 1 || summary.Modified != 1 || summary.Untracked != 1 {
+		t.Errorf("counts = %d staged, %d modified, %d untracked, want 1 of each", summary.Staged, summary.Modified, summary.Untracked)
+	}
+	if want := strings.TrimSpace(fixture.Git("log", "-1", "--format=%ct")); strconv.FormatInt(summary.LastCommit.Unix(), 10) != want {
+		t.Errorf("last commit = %v, want %s", summary.LastCommit, want)
+	}
+
+	empty := testutil.NewRepo(t)
+	emptyRepo, err := NewGitRepository(empty.Dir, true, true)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	summary, err = emptyRepo.GetWorkspaceSummary(context.Background())
+	if err != nil || !summary.LastCommit.IsZero() {
+		t.Errorf("GetWorkspaceSummary() in a new repository = %+v, %v, want no last commit", summary, err)
+	}
+}
+
+func TestCreateTag_Unsigned(t *testing.T) {
+	utils.InitLogger(true)
+
+	fixture := testutil.NewRepo(t)
+	tmpDir := fixture.Dir
+	repo, err := NewGitRepository(tmpDir, true, true)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	fixture.Commit("feat: first")
+
+	ctx := context.Background()
+	signed, err := repo.CreateTag(ctx, "v1.0.0", "", "Release v1.0.0")
+	if err != nil || signed {
+		t.Fatalf("CreateTag() = %v, %v, want an unsigned tag", signed, err)
+	}
+	if out := fixture.Git("for-each-ref", "--format=%(objecttype) %(contents:subject)", "refs/tags/v1.0.0"); strings.TrimSpace(out) != "tag Release v1.0.0" {
+		t.Errorf("tag = %q, want an annotated tag with the release message", out)
+	}
+
+	if _, err := repo.CreateTag(ctx, "bad..name", "", "Release"); err == nil {
+		t.Error("CreateTag() with an invalid name succeeded, want an error")
+	}
+}
+
+func TestLastAuthorCommit_FindsOwnCommitOnPaths(t *testing.T) {
+	utils.InitLogger(true)
+
+	fixture := testutil.NewRepo(t)
+	tmpDir := fixture.Dir
+	for _, kv := range [][2]string{{"user.name", "Jane Doe"}, {"user.email", "jane@example.com"}} {
+		fixture.Git("config", kv[0], kv[1])
+	}
+
+	commit := func(file, msg, name, email string) {
+		if err := os.WriteFile(filepath.Join(tmpDir, file), []byte(msg), 0644); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+		fixture.Git("add", file)
+		// The window of LastAuthorCommit ends now
+		fixture.GitEnv(append(testutil.Identity(name, email), testutil.Now()...), "commit", "-m", msg)
+	}
+	commit("a.go", "refactor: start extracting a", "Jane Doe", "jane@example.com")
+	commit("b.go", "feat: add b", "Jane Doe", "jane@example.com")
+	commit("a.go", "fix: tweak a", "John Roe", "john@example.com")
+
+	repo, err := NewGitRepository(tmpDir, true, true)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+
+	got, err := repo.LastAuthorCommit(context.Background(), []string{"a.go"}, time.Hour)
+	if err != nil {
+		t.Fatalf("LastAuthorCommit() error = %v", err)
+	}
+	if got == nil || got.Subject() != "refactor: start extracting a" {
+		t.Errorf("LastAuthorCommit() = %v, want own commit on a.go", got)
+	}
+
+	none, err := repo.LastAuthorCommit(context.Background(), []string{"missing.go"}, time.Hour)
+	if err != nil {
+		t.Fatalf("LastAuthorCommit() error = %v", err)
+	}
+	if none != nil {
+		t.Errorf("LastAuthorCommit() = %v, want nil for untouched path", none)
+	}
+}
+
+func TestAuthorCommits_OwnCommitsOnAllBranchesSince(t *testing.T) {
+	r := testutil.NewRepoWithCommit(t, map[string]string{"README.md": "# test\n"})
+	r.Commit("feat: add the api")
+	r.Git("commit", "-q", "--allow-empty", "-m", "fix: not mine", "--author", "John Roe <john@example.com>")
+	r.Git("switch", "-q", "-c", "feature")
+	r.Commit("feat: add the feature")
+
+	repo, err := NewGitRepository(r.Dir, true, true)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+
+	// testutil commits are one minute apart from 2024-01-01 12:00 UTC: skip the first one
+	commits, err := repo.AuthorCommits(context.Background(), time.Date(2024, time.January, 1, 12, 0, 30, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("AuthorCommits() error = %v", err)
+	}
+	var subjects []string
+	for _, commit := range commits {
+		subjects = append(subjects, commit.Subject())
+	}
+	if want := []string{"feat: add the feature", "feat: add the api"}; !slices.Equal(subjects, want) {
+		t.Errorf("AuthorCommits() subjects = %q, want %q", subjects, want)
+	}
+	if len(commits) > 0 && !commits[0].Date.Equal(time.Date(2024, time.January, 1, 12, 2, 0, 0, time.UTC)) {
+		t.Errorf("AuthorCommits() date = %v, want the author date", commits[0].Date)
+	}
+}
+
+func TestCreateCommitOnBranch_DoesNotSwitchWorktree(t *testing.T) {
+	utils.InitLogger(true)
+
+	fixture := testutil.NewRepo(t)
+	tmpDir := fixture.Dir
+	git := func(args ...string) string {
+		t.Helper()
+		return strings.TrimSpace(fixture.Git(args...))
+	}
+
+	git("config", "user.name", "Commit Author")
+	git("config", "user.email", "author@example.com")
+	git("commit", "--allow-empty", "-m", "initial")
+	currentBranch := git("symbolic-ref", "--short", "HEAD")
+	initialHead := git("rev-parse", "HEAD")
+
+	repo, err := NewGitRepository(tmpDir, true, true)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(tmpDir, "wip.txt"), []byte("wip"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	git("add", "wip.txt")
+
+	// New branch is created from HEAD
+	first := &model.CommitMessage{Type: "chore", Subject: "first checkpoint"}
+	if err := repo.CreateCommitOnBranch(context.Background(), first, "wip/checkpoint"); err != nil {
+		t.Fatalf("CreateCommitOnBranch() error = %v", err)
+	}
+	if got := git("rev-parse", "wip/checkpoint^"); got != initialHead {
+		t.Errorf("new branch parent = %s, want HEAD %s", got, initialHead)
+	}
+
+	// Existing branch gets the commit on top of its tip
+	second := &model.CommitMessage{Type: "chore", Subject: "second checkpoint"}
+	if err := repo.CreateCommitOnBranch(context.Background(), second, "wip/checkpoint"); err != nil {
+		t.Fatalf("CreateCommitOnBranch() on existing branch error = %v", err)
+	}
+	if got := git("log", "-1", "--format=%s", "wip/checkpoint"); got != "chore: second checkpoint" {
+		t.Errorf("branch tip subject = %q", got)
+	}
+	if got := git("rev-list", "--count", "wip/checkpoint"); got != "3" {
+		t.Errorf("branch commit count = %s, want 3", got)
+	}
+	if got := git("show", "wip/checkpoint:wip.txt"); got != "wip" {
+		t.Errorf("branch tree content = %q, want staged content", got)
+	}
+
+	// Worktree, HEAD and index are untouched
+	if got := git("symbolic-ref", "--short", "HEAD"); got != currentBranch {
+		t.Errorf("current branch = %s, want %s", got, currentBranch)
+	}
+	if got := git("rev-parse", "HEAD"); got != initialHead {
+		t.Errorf("HEAD moved to %s", got)
+	}
+	if got := git("diff", "--cached", "--name-only"); got != "wip.txt" {
+		t.Errorf("staged files = %q, want wip.txt still staged", got)
+	}
+
+	if err := repo.CreateCommitOnBranch(context.Background(), first, "bad..name"); err == nil {
+		t.Error("CreateCommitOnBranch() with invalid branch name should fail")
+	}
+}
+
+func TestCreateCommitOnBranch_DivergedBranch(t *testing.T) {
+	utils.InitLogger(true)
+
+	fixture := testutil.NewRepo(t)
+	tmpDir := fixture.Dir
+	git := func(args ...string) string {
+		t.Helper()
+		return strings.TrimSpace(fixture.Git(args...))
+	}
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	git("config", "user.name", "Commit Author")
+	git("config", "user.email", "author@example.com")
+	write("shared.txt", "base\n")
+	git("add", "shared.txt")
+	git("commit", "-m", "initial")
+
+	// The branch diverges from HEAD with its own file and its own version of shared.txt
+	git("branch", "wip/diverged")
+	git("checkout", "-q", "wip/diverged")
+	write("branch.txt", "branch\n")
+	write("shared.txt", "branch\n")
+	git("add", "branch.txt", "shared.txt")
+	git("commit", "-m", "branch work")
+	git("checkout", "-q", "-")
+
+	repo, err := NewGitRepository(tmpDir, true, true)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+
+	write("staged.txt", "staged\n")
+	git("add", "staged.txt")
+	message := &model.CommitMessage{Type: "chore", Subject: "checkpoint"}
+	if err := repo.CreateCommitOnBranch(context.Background(), message, "wip/diverged"); err != nil {
+		t.Fatalf("CreateCommitOnBranch() error = %v", err)
+	}
+	if got := git("ls-tree", "--name-only", "wip/diverged"); got != "branch.txt\nshared.txt\nstaged.txt" {
+		t.Errorf("branch files = %q, want its own files and the staged one", got)
+	}
+	if got := git("show", "wip/diverged:shared.txt"); got != "branch" {
+		t.Errorf("shared.txt on branch = %q, want the branch version kept", got)
+	}
+	if got := git("diff", "--cached", "--name-only"); got != "staged.txt" {
+		t.Errorf("staged files = %q, want the index untouched", got)
+	}
+
+	// A staged change to a file the branch changed too is refused
+	tip := git("rev-parse", "wip/diverged")
+	write("shared.txt", "head\n")
+	git("add", "shared.txt")
+	err = repo.CreateCommitOnBranch(context.Background(), message, "wip/diverged")
+	if err == nil || !strings.Contains(err.Error(), "shared.txt") {
+		t.Errorf("CreateCommitOnBranch() with conflicting change error = %v, want a conflict on shared.txt", err)
+	}
+	if got := git("rev-parse", "wip/diverged"); got != tip {
+		t.Errorf("branch moved to %s after a conflict", got)
+	}
+}
+
+func TestExportPatch_FromCommitObject(t *testing.T) {
+	utils.InitLogger(true)
+
+	fixture := testutil.NewRepo(t)
+	tmpDir := fixture.Dir
+	git := func(args ...string) string {
+		t.Helper()
+		return strings.TrimSpace(fixture.Git(args...))
+	}
+
+	git("config", "user.name", "Commit Author")
+	git("config", "user.email", "author@example.com")
+	git("commit", "--allow-empty", "-m", "initial")
+	initialHead := git("rev-parse", "HEAD")
+
+	repo, err := NewGitRepository(tmpDir, true, true)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(tmpDir, "fix.txt"), []byte("fixed\n"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	git("add", "fix.txt")
+
+	message := &model.CommitMessage{Type: "fix", Subject: "handle edge case", Body: "Explain why.", Signoff: true}
+	hash, err := repo.CreateCommitObject(context.Background(), message)
+	if err != nil {
+		t.Fatalf("CreateCommitObject() error = %v", err)
+	}
+	if got := git("rev-parse", "HEAD"); got != initialHead {
+		t.Errorf("HEAD moved to %s, want unchanged", got)
+	}
+
+	patchDir := filepath.Join(t.TempDir(), "patches")
+	path, err := repo.ExportPatch(context.Background(), hash, patchDir)
+	if err != nil {
+		t.Fatalf("ExportPatch() error = %v", err)
+	}
+	if filepath.Dir(path) != patchDir || !strings.HasSuffix(path, ".patch") {
+		t.Errorf("ExportPatch() path = %q, want .patch file in %s", path, patchDir)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read patch: %v", err)
+	}
+	for _, want := range []string{
+		"From: Commit Author <author@example.com>",
+		"Subject: [PATCH] fix: handle edge case",
+		"Signed-off-by: Commit Author <author@example.com>",
+		"+fixed",
+	} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("patch missing %q:\n%s", want, content)
+		}
+	}
+}
+
+func TestGetConfigValues(t *testing.T) {
+	utils.InitLogger(true)
+
+	fixture := testutil.NewRepo(t)
+	tmpDir := fixture.Dir
+	fixture.Git("config", "--add", "sendemail.to", "list@example.org")
+	fixture.Git("config", "--add", "sendemail.to", "maintainer@example.org")
+
+	repo, err := NewGitRepository(tmpDir, true, true)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+
+	values, err := repo.GetConfigValues(context.Background(), "sendemail.to")
+	if err != nil {
+		t.Fatalf("GetConfigValues() error = %v", err)
+	}
+	if strings.Join(values, ",") != "list@example.org,maintainer@example.org" {
+		t.Errorf("GetConfigValues() = %v", values)
+	}
+
+	missing, err := repo.GetConfigValues(context.Background(), "sendemail.cc")
+	if err != nil || missing != nil {
+		t.Errorf("GetConfigValues() for unset key = %v, %v; want nil, nil", missing, err)
+	}
+}
+
+func TestParseBranchHeaders(t *testing.T) {
+	tests := []struct {
+		name   string
+		status string
+		want   model.RepositoryState
+	}{
+		{
+			name:   "branch with upstream",
+			status: "# branch.oid 1234\n# branch.head main\n# branch.upstream origin/main\n# branch.ab +2 -1\n1 M. N... 100644 100644 100644 a b file.go\n",
+			want:   model.RepositoryState{Branch: "main", Upstream: "origin/main", Ahead: 2, Behind: 1},
+		},
+		{
+			name:   "branch without upstream",
+			status: "# branch.oid (initial)\n# branch.head feature/x\n",
+			want:   model.RepositoryState{Branch: "feature/x"},
+		},
+		{
+			name:   "detached HEAD",
+			status: "# branch.oid 1234\n# branch.head (detached)\n",
+			want:   model.RepositoryState{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := model.RepositoryState{}
+			parseBranchHeaders(tt.status, &got)
+			if got.Branch != tt.want.Branch || got.Upstream != tt.want.Upstream ||
+				got.Ahead != tt.want.Ahead || got.Behind != tt.want.Behind {
+				t.Errorf("parseBranchHeaders() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseNumstat(t *testing.T) {
+	out := "12\t3\tinternal/cmd/root.go\n-\t-\tdocs/logo.png\n0\t7\tREADME.md\n"
+
+	got := parseNumstat(out)
+	want := &model.DiffStats{FilesChanged: 3, LinesAdded: 12, LinesRemoved: 10}
+	if *got != *want {
+		t.Errorf("parseNumstat() = %+v, want %+v", got, want)
+	}
+
+	if empty := parseNumstat(""); *empty != (model.DiffStats{}) {
+		t.Errorf("parseNumstat(\"\") = %+v, want zero stats", empty)
+	}
+}
+
+func TestGetRepositoryState_PopulatesBranchAndLastCommit(t *testing.T) {
+	utils.InitLogger(true)
+
+	fixture := testutil.NewRepo(t)
+	tmpDir := fixture.Dir
+	fixture.Git("checkout", "-q", "-b", "trunk")
+	fixture.Commit("feat: first change")
+
+	repo, err := NewGitRepository(tmpDir, true, true)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+
+	state, err := repo.GetRepositoryState(context.Background())
+	if err != nil {
+		t.Fatalf("GetRepositoryState() error = %v", err)
+	}
+	if state.Branch != "trunk" {
+		t.Errorf("Branch = %q, want trunk", state.Branch)
+	}
+	if state.Upstream != "" {
+		t.Errorf("Upstream = %q, want empty", state.Upstream)
+	}
+	if state.LastCommitSubject != "feat: first change" {
+		t.Errorf("LastCommitSubject = %q", state.LastCommitSubject)
+	}
+}
+
+func TestRewordCommits_RewritesMessagesAndKeepsTrees(t *testing.T) {
+	utils.InitLogger(true)
+
+	fixture := testutil.NewRepo(t)
+	tmpDir := fixture.Dir
+	git := func(args ...string) string {
+		t.Helper()
+		return strings.TrimSpace(fixture.Git(args...))
+	}
+
+	git("config", "user.name", "Commit Author")
+	git("config", "user.email", "author@example.com")
+	for i, name := range []string{"a.txt", "b.txt", "c.txt"} {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(name), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+		git("add", name)
+		git("commit", "-m", []string{"feat: first", "chore: placeholder", "feat: third"}[i])
+	}
+	placeholder := git("rev-parse", "HEAD~1")
+	oldTree := git("rev-parse", "HEAD^{tree}")
+	oldHead := git("rev-parse", "HEAD")
+
+	repo, err := NewGitRepository(tmpDir, true, true)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+
+	state, err := repo.GetCommitState(context.Background(), placeholder)
+	if err != nil {
+		t.Fatalf("GetCommitState() error = %v", err)
+	}
+	if len(state.StagedFiles) != 1 || state.StagedFiles[0].Path != "b.txt" || state.StagedFiles[0].Status != "added" {
+		t.Errorf("GetCommitState() files = %+v, want added b.txt", state.StagedFiles)
+	}
+	if !strings.Contains(state.StagedFiles[0].Diff, "+b.txt") {
+		t.Errorf("GetCommitState() diff = %q, want the added content", state.StagedFiles[0].Diff)
+	}
+
+	rewritten, err := repo.RewordCommits(context.Background(), map[string]*model.CommitMessage{
+		placeholder: {Type: "feat", Scope: "b", Subject: "add b"},
+	})
+	if err != nil {
+		t.Fatalf("RewordCommits() error = %v", err)
+	}
+
+	if got := git("log", "--format=%s", "-3"); got != "feat: third\nfeat(b): add b\nfeat: first" {
+		t.Errorf("history after reword:\n%s", got)
+	}
+	if got := git("rev-parse", "HEAD^{tree}"); got != oldTree {
+		t.Errorf("HEAD tree = %s, want unchanged %s", got, oldTree)
+	}
+	if rewritten[oldHead] != git("rev-parse", "HEAD") {
+		t.Errorf("rewritten[%s] = %s, want new HEAD", oldHead, rewritten[oldHead])
+	}
+	if len(rewritten) != 2 {
+		t.Errorf("Expected 2 recreated commits, got %d", len(rewritten))
+	}
+	if got := git("status", "--porcelain"); got != "" {
+		t.Errorf("worktree should be clean, got:\n%s", got)
+	}
+
+	if _, err := repo.RewordCommits(context.Background(), map[string]*model.CommitMessage{
+		strings.Repeat("0", 40): {Type: "feat", Subject: "missing"},
+	}); err == nil {
+		t.Error("RewordCommits() with a commit not on the branch should return an error")
+	}
+}
+
+func TestPush_TriangularWorkflow(t *testing.T) {
+	utils.InitLogger(true)
+
+	fixture := testutil.NewRepo(t)
+	tmpDir := fixture.Dir
+	upstreamDir := filepath.Join(t.TempDir(), "upstream.git")
+	forkDir := filepath.Join(t.TempDir(), "fork.git")
+	git := func(dir string, args ...string) string {
+		t.Helper()
+		return strings.TrimSpace(fixture.Git(append([]string{"-C", dir}, args...)...))
+	}
+
+	git(tmpDir, "init", "--bare", upstreamDir)
+	git(tmpDir, "init", "--bare", forkDir)
+	git(tmpDir, "config", "user.name", "Commit Author")
+	git(tmpDir, "config", "user.email", "author@example.com")
+	git(tmpDir, "remote", "add", "upstream", upstreamDir)
+	git(tmpDir, "remote", "add", "fork", forkDir)
+	git(tmpDir, "config", "remote.pushDefault", "fork")
+	if err := os.WriteFile(filepath.Join(tmpDir, "a.txt"), []byte("a"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	git(tmpDir, "add", "a.txt")
+	git(tmpDir, "commit", "-m", "feat: first")
+	git(tmpDir, "push", "upstream", "main")
+	git(tmpDir, "branch", "--set-upstream-to=upstream/main")
+	head := git(tmpDir, "rev-parse", "HEAD")
+
+	repo, err := NewGitRepository(tmpDir, true, true)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+
+	target, err := repo.ResolvePushTarget(context.Background(), "main")
+	if err != nil {
+		t.Fatalf("ResolvePushTarget() error = %v", err)
+	}
+	if target.Remote != "fork" || !target.Triangular || target.BaseRemote != "upstream" || target.BaseBranch != "main" {
+		t.Errorf("ResolvePushTarget() = %+v, want triangular push to fork based on upstream/main", target)
+	}
+
+	if err := repo.Push(context.Background(), target); err != nil {
+		t.Fatalf("Push() error = %v", err)
+	}
+	if got := git(forkDir, "rev-parse", "refs/heads/main"); got != head {
+		t.Errorf("fork main = %s, want %s", got, head)
+	}
+	// The upstream tracking branch is kept
+	if got := git(tmpDir, "config", "branch.main.remote"); got != "upstream" {
+		t.Errorf("branch.main.remote = %q, want upstream", got)
+	}
+}
+
+func TestGitCommands_CancelledContext(t *testing.T) {
+	utils.InitLogger(true)
+
+	fixture := testutil.NewRepo(t)
+	tmpDir := fixture.Dir
+	if err := os.WriteFile(filepath.Join(tmpDir, "a.txt"), []byte("a"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	repo, err := NewGitRepository(tmpDir, true, true)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := repo.ListCommits(ctx, "", 1); !errors.Is(err, context.Canceled) {
+		t.Errorf("ListCommits() error = %v, want context.Canceled", err)
+	}
+
+	_, err = repo.StageAllFilesIncludingUntracked(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("StageAllFilesIncludingUntracked() error = %v, want context.Canceled", err)
+	}
+	if out := fixture.Git("diff", "--cached", "--name-only"); strings.TrimSpace(out) != "" {
+		t.Errorf("Nothing should stay staged after cancellation, got %q", out)
+	}
+}
+
+func TestContextError(t *testing.T) {
+	if err := contextError(context.Background(), "status"); err != nil {
+		t.Errorf("contextError() on live context = %v, want nil", err)
+	}
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := contextError(cancelled, "status"); !errors.Is(err, context.Canceled) {
+		t.Errorf("contextError() = %v, want context.Canceled", err)
+	}
+
+	expired, cancelExpired := context.WithTimeout(context.Background(), 0)
+	defer cancelExpired()
+	err := contextError(expired, "status")
+	if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("contextError() = %v, want a timeout wrapping context.DeadlineExceeded", err)
+	}
+}
+
+// TestUseTemporaryIndex_LeavesRealIndexUntouched verifies that staging on the temporary
+// index is invisible once switched back to the real index.
+func TestUseTemporaryIndex_LeavesRealIndexUntouched(t *testing.T) {
+	fixture := testutil.NewRepo(t)
+	tmpDir := fixture.Dir
+
+	if err := os.WriteFile(filepath.Join(tmpDir, "new.txt"), []byte("new\n"), 0644); err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+
+	repo, err := NewGitRepository(tmpDir, true, true)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	ctx := context.Background()
+
+	useRealIndex, err := repo.UseTemporaryIndex(ctx)
+	if err != nil {
+		t.Fatalf("UseTemporaryIndex() error = %v", err)
+	}
+	if _, err := repo.StageAllFilesIncludingUntracked(ctx); err != nil {
+		t.Fatalf("StageAllFilesIncludingUntracked() error = %v", err)
+	}
+	state, err := repo.GetRepositoryState(ctx)
+	if err != nil {
+		t.Fatalf("GetRepositoryState() error = %v", err)
+	}
+	if len(state.StagedFiles) != 1 {
+		t.Errorf("staged files on temporary index = %v, want new.txt", state.StagedFiles)
+	}
+
+	useRealIndex()
+	state, err = repo.GetRepositoryState(ctx)
+	if err != nil {
+		t.Fatalf("GetRepositoryState() error = %v", err)
+	}
+	if len(state.StagedFiles) != 0 {
+		t.Errorf("staged files on real index = %v, want none", state.StagedFiles)
+	}
+}
+
+// TestUndoLastCommit_FirstCommit verifies that the first commit of a branch can be undone
+// keeping its changes staged, but not discarded
+func TestUndoLastCommit_FirstCommit(t *testing.T) {
+	utils.InitLogger(true)
+
+	fixture := testutil.NewRepoWithCommit(t, map[string]string{"test.txt": "content\n"})
+	repo, err := NewGitRepository(fixture.Dir, true, true)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	ctx := context.Background()
+
+	if err := repo.UndoLastCommit(ctx, false); err == nil {
+		t.Fatal("Expected an error when discarding the first commit")
+	}
+	if err := repo.UndoLastCommit(ctx, true); err != nil {
+		t.Fatalf("UndoLastCommit() error = %v", err)
+	}
+	if _, err := fixture.TryGit("rev-parse", "--verify", "HEAD"); err == nil {
+		t.Error("Expected HEAD to be unborn after undoing the first commit")
+	}
+	if got := fixture.Git("status", "--porcelain"); got != "A  test.txt\n" {
+		t.Errorf("Expected test.txt to stay staged, got status %q", got)
+	}
+	if err := repo.UndoLastCommit(ctx, true); err == nil {
+		t.Error("Expected an error when there is no commit to undo")
+	}
+}
+
+func TestDefaultBranch(t *testing.T) {
+	utils.InitLogger(true)
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		setup  func(r *testutil.Repo)
+		remote string
+		want   string
+	}{
+		{name: "local main", want: "main"},
+		{
+			name: "local master",
+			setup: func(r *testutil.Repo) {
+				r.Git("branch", "-m", "main", "master")
+			},
+			want: "master",
+		},
+		{
+			name: "init.defaultBranch",
+			setup: func(r *testutil.Repo) {
+				r.Git("branch", "trunk")
+				r.Git("config", "init.defaultBranch", "trunk")
+			},
+			want: "trunk",
+		},
+		{
+			name: "init.defaultBranch without the branch",
+			setup: func(r *testutil.Repo) {
+				r.Git("branch", "-m", "main", "feature")
+				r.Git("config", "init.defaultBranch", "trunk")
+			},
+			want: "trunk",
+		},
+		{
+			name: "no candidate",
+			setup: func(r *testutil.Repo) {
+				r.Git("branch", "-m", "main", "feature")
+			},
+			want: "master",
+		},
+		{
+			name: "origin HEAD",
+			setup: func(r *testutil.Repo) {
+				r.Git("update-ref", "refs/remotes/origin/develop", "HEAD")
+				r.Git("symbolic-ref", "refs/remotes/origin/HEAD", "refs/remotes/origin/develop")
+			},
+			want: "develop",
+		},
+		{
+			name: "HEAD of another remote",
+			setup: func(r *testutil.Repo) {
+				r.Git("update-ref", "refs/remotes/upstream/release", "HEAD")
+				r.Git("symbolic-ref", "refs/remotes/upstream/HEAD", "refs/remotes/upstream/release")
+			},
+			remote: "upstream",
+			want:   "release",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fixture := testutil.NewRepoWithCommit(t, map[string]string{"a.txt": "a\n"})
+			// The global and system config could set init.defaultBranch
+			t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+			t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+			if tt.setup != nil {
+				tt.setup(fixture)
+			}
+
+			repo, err := NewGitRepository(fixture.Dir, true, true)
+			if err != nil {
+				t.Fatalf("Failed to create repository: %v", err)
+			}
+			got, err := repo.DefaultBranch(ctx, tt.remote)
+			if err != nil {
+				t.Fatalf("DefaultBranch() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("DefaultBranch() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// The diffs, summaries and metadata describe the staged blobs, whatever the worktree holds
+func TestGetRepositoryState_DescribesStagedContent(t *testing.T) {
+	large := strings.Repeat("staged line\n", 500)
+	fixture := testutil.NewRepoWithCommit(t, map[string]string{
+		"notes.txt": "first\n",
+		"gone.txt":  "kept\n",
+	})
+	fixture.WriteFile("notes.txt", "first\nsecond\n")
+	fixture.WriteFile("large.txt", large)
+	fixture.WriteFile("gone.txt", "kept\nstaged\n")
+	fixture.Stage("notes.txt", "large.txt", "gone.txt")
+
+	// Unstaged changes: binary content, more lines, a deleted file
+	fixture.WriteFile("notes.txt", "first\x00\x01\n")
+	fixture.WriteFile("large.txt", large+strings.Repeat("unstaged line\n", 100))
+	fixture.RemoveFile("gone.txt")
+
+	repo, err := NewGitRepository(fixture.Dir, true, true)
+	if err != nil {
+		t.Fatalf("NewGitRepository() error = %v", err)
+	}
+	// The small diffs fit, large.txt is too large for even its hunk header to fit
+	ctx := context.WithValue(context.Background(), DiffLimitsKey, model.DiffLimits{MaxSize: 130})
+	state, err := repo.GetRepositoryState(ctx)
+	if err != nil {
+		t.Fatalf("GetRepositoryState() error = %v", err)
+	}
+	diffs := make(map[string]string)
+	for _, file := range state.StagedFiles {
+		diffs[file.Path] = file.Diff
+	}
+
+	if !strings.Contains(diffs["notes.txt"], "+second") {
+		t.Errorf("notes.txt diff = %q, want the staged text diff", diffs["notes.txt"])
+	}
+	if want := "file: large.txt\nsize: 6000 bytes\nlines: 500\nchanges: added"; diffs["large.txt"] != want {
+		t.Errorf("large.txt metadata = %q, want %q", diffs["large.txt"], want)
+	}
+	if !strings.Contains(diffs["gone.txt"], "+staged") {
+		t.Errorf("gone.txt diff = %q, want the staged diff of the file deleted since", diffs["gone.txt"])
+	}
+
+	want := fixture.Git("diff", "--cached", "--unified=0", "--", "notes.txt")
+	if diffs["notes.txt"] != strings.TrimSpace(want) {
+		t.Errorf("notes.txt diff = %q, want git diff --cached %q", diffs["notes.txt"], want)
+	}
+}
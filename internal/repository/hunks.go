@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/utils"
+)
+
+// StageFiles stages the given files (modified, deleted or untracked) as a whole
+func (r *gitRepositoryImpl) StageFiles(ctx context.Context, files []string) error {
+	if len(files) == 0 {
+		return nil
+	}
+	addArgs := append([]string{"add", "--"}, files...)
+	if _, _, err := r.execGit(ctx, addArgs...); err != nil {
+		return fmt.Errorf("%w: %v", utils.ErrStagingFailed, err)
+	}
+	return nil
+}
+
+// UnstagedHunks returns the hunks of the unstaged changes of a tracked file
+func (r *gitRepositoryImpl) UnstagedHunks(ctx context.Context, file string) ([]model.Hunk, error) {
+	_, hunks, err := r.unstagedDiff(ctx, file)
+	return hunks, err
+}
+
+// StageHunks stages only the given hunks of file, as returned by UnstagedHunks
+func (r *gitRepositoryImpl) StageHunks(ctx context.Context, file string, hunks []model.Hunk) error {
+	if len(hunks) == 0 {
+		return nil
+	}
+	header, _, err := r.unstagedDiff(ctx, file)
+	if err != nil {
+		return err
+	}
+	if header == "" {
+		return fmt.Errorf("%w: %s has no unstaged changes", utils.ErrStagingFailed, file)
+	}
+
+	var patch strings.Builder
+	patch.WriteString(header)
+	for _, hunk := range hunks {
+		patch.WriteString(hunk.String())
+	}
+
+	patchFile, err := os.CreateTemp("", "gitcomm-hunks-*.patch")
+	if err != nil {
+		return fmt.Errorf("%w: %v", utils.ErrStagingFailed, err)
+	}
+	defer os.Remove(patchFile.Name())
+	if _, err := patchFile.WriteString(patch.String()); err != nil {
+		patchFile.Close()
+		return fmt.Errorf("%w: %v", utils.ErrStagingFailed, err)
+	}
+	if err := patchFile.Close(); err != nil {
+		return fmt.Errorf("%w: %v", utils.ErrStagingFailed, err)
+	}
+
+	if _, err := r.execGitWithEnvOutput(ctx, nil, "apply", "--cached", patchFile.Name()); err != nil {
+		return fmt.Errorf("%w: failed to stage hunks of %s: %v", utils.ErrStagingFailed, file, err)
+	}
+	return nil
+}
+
+// unstagedDiff returns the file header and the hunks of the worktree diff of file. The
+// diff is read from git directly (never through rtk) since it is applied back to the index.
+func (r *gitRepositoryImpl) unstagedDiff(ctx context.Context, file string) (string, []model.Hunk, error) {
+	out, err := r.execGitWithEnvOutput(ctx, nil, "diff", "--no-color", "--no-ext-diff", "--", file)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get diff of %s: %w", file, err)
+	}
+	header, hunks := parseHunks(out)
+	return header, hunks, nil
+}
+
+// parseHunks splits a single-file unified diff into its header (the lines before the first
+// "@@") and its hunks
+func parseHunks(diff string) (string, []model.Hunk) {
+	var header strings.Builder
+	var hunks []model.Hunk
+	for _, line := range strings.Split(strings.TrimSuffix(diff, "\n"), "\n") {
+		switch {
+		case strings.HasPrefix(line, "@@"):
+			hunks = append(hunks, model.Hunk{Header: line})
+		case len(hunks) > 0:
+			hunks[len(hunks)-1].Lines = append(hunks[len(hunks)-1].Lines, line)
+		case line != "":
+			header.WriteString(line + "\n")
+		}
+	}
+	return header.String(), hunks
+}
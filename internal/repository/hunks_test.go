@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/golgoth31/gitcomm/internal/utils"
+	"github.com/golgoth31/gitcomm/pkg/testutil"
+)
+
+func TestParseHunks(t *testing.T) {
+	diff := "diff --git a/f.txt b/f.txt\nindex 1111111..2222222 100644\n--- a/f.txt\n+++ b/f.txt\n" +
+		"@@ -1,3 +1,3 @@\n-one\n+ONE\n two\n three\n" +
+		"@@ -8,2 +8,3 @@ section\n eight\n nine\n+ten\n\\ No newline at end of file\n"
+
+	header, hunks := parseHunks(diff)
+	if header != "diff --git a/f.txt b/f.txt\nindex 1111111..2222222 100644\n--- a/f.txt\n+++ b/f.txt\n" {
+		t.Errorf("header = %q", header)
+	}
+	if len(hunks) != 2 {
+		t.Fatalf("got %d hunks, want 2", len(hunks))
+	}
+	if hunks[1].Header != "@@ -8,2 +8,3 @@ section" || len(hunks[1].Lines) != 4 {
+		t.Errorf("second hunk = %+v", hunks[1])
+	}
+
+	if header, hunks := parseHunks(""); header != "" || len(hunks) != 0 {
+		t.Errorf("parseHunks(\"\") = %q, %v, want nothing", header, hunks)
+	}
+}
+
+func TestStageHunks_StagesSelectedHunksOnly(t *testing.T) {
+	utils.InitLogger(true)
+
+	lines := make([]string, 20)
+	for i := range lines {
+		lines[i] = "line " + string(rune('a'+i))
+	}
+	fixture := testutil.NewRepoWithCommit(t, map[string]string{"f.txt": strings.Join(lines, "\n") + "\n"})
+	git := fixture.Git
+
+	// Two changes far enough apart to be separate hunks, and an untracked file
+	lines[1] = "line B"
+	lines[18] = "line S"
+	fixture.WriteFile("f.txt", strings.Join(lines, "\n")+"\n")
+	fixture.WriteFile("new.txt", "new\n")
+
+	repo, err := NewGitRepository(fixture.Dir, true, true)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	ctx := context.Background()
+
+	hunks, err := repo.UnstagedHunks(ctx, "f.txt")
+	if err != nil {
+		t.Fatalf("UnstagedHunks() error = %v", err)
+	}
+	if len(hunks) != 2 {
+		t.Fatalf("UnstagedHunks() returned %d hunks, want 2", len(hunks))
+	}
+
+	if err := repo.StageHunks(ctx, "f.txt", hunks[1:]); err != nil {
+		t.Fatalf("StageHunks() error = %v", err)
+	}
+	if err := repo.StageFiles(ctx, []string{"new.txt"}); err != nil {
+		t.Fatalf("StageFiles() error = %v", err)
+	}
+
+	staged := git("diff", "--cached")
+	if !strings.Contains(staged, "+line S") || strings.Contains(staged, "+line B") {
+		t.Errorf("staged diff should contain only the second hunk:\n%s", staged)
+	}
+	if !strings.Contains(staged, "new.txt") {
+		t.Errorf("staged diff should contain new.txt:\n%s", staged)
+	}
+	if unstaged := git("diff"); !strings.Contains(unstaged, "+line B") {
+		t.Errorf("first hunk should remain unstaged:\n%s", unstaged)
+	}
+
+	if err := repo.StageHunks(ctx, "missing.txt", hunks[:1]); err == nil {
+		t.Error("StageHunks() on a file without changes succeeded, want an error")
+	}
+}
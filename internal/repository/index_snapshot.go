@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/golgoth31/gitcomm/internal/utils"
+)
+
+// SnapshotIndex writes the index as a tree object and returns its hash, to stage parts
+// of it with StageFromSnapshot and bring it back with RestoreIndex
+func (r *gitRepositoryImpl) SnapshotIndex(ctx context.Context) (string, error) {
+	out, err := r.execGitWithEnvOutput(ctx, nil, "write-tree")
+	if err != nil {
+		return "", fmt.Errorf("%w: failed to snapshot the index: %v", utils.ErrStagingFailed, err)
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// RestoreIndex replaces the index with tree, a snapshot taken by SnapshotIndex.
+// Changes committed since the snapshot no longer show as staged.
+func (r *gitRepositoryImpl) RestoreIndex(ctx context.Context, tree string) error {
+	if _, err := r.execGitWithEnvOutput(ctx, nil, "read-tree", tree); err != nil {
+		return fmt.Errorf("%w: failed to restore the index: %v", utils.ErrStagingFailed, err)
+	}
+	// read-tree drops the cached file stats: refresh them so the worktree is not rescanned
+	_, _ = r.execGitWithEnvOutput(ctx, nil, "update-index", "-q", "--refresh")
+	return nil
+}
+
+// StageFromSnapshot replaces the index with HEAD (or an empty index before the first
+// commit) and stages files as they are in tree, a snapshot taken by SnapshotIndex
+func (r *gitRepositoryImpl) StageFromSnapshot(ctx context.Context, tree string, files []string) error {
+	resetArgs := []string{"read-tree", "HEAD"}
+	if _, err := r.execGitWithEnvOutput(ctx, nil, "rev-parse", "--verify", "--quiet", "HEAD^{commit}"); err != nil {
+		resetArgs = []string{"read-tree", "--empty"}
+	}
+	if _, err := r.execGitWithEnvOutput(ctx, nil, resetArgs...); err != nil {
+		return fmt.Errorf("%w: failed to reset the index: %v", utils.ErrStagingFailed, err)
+	}
+	if len(files) == 0 {
+		return nil
+	}
+
+	restoreArgs := append([]string{"restore", "--staged", "--source=" + tree, "--"}, files...)
+	if _, err := r.execGitWithEnvOutput(ctx, nil, restoreArgs...); err != nil {
+		return fmt.Errorf("%w: %v", utils.ErrStagingFailed, err)
+	}
+	return nil
+}
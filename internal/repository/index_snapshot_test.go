@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/pkg/testutil"
+)
+
+func TestStageFromSnapshot_CommitsGroupsSeparately(t *testing.T) {
+	fixture := testutil.NewRepoWithCommit(t, map[string]string{"a.txt": "a\n", "gone.txt": "gone\n"})
+	git := fixture.Git
+
+	// Staged: a modification, a deletion and a new file; then an unstaged change on top
+	fixture.WriteFile("a.txt", "a staged\n")
+	fixture.WriteFile("b.txt", "b\n")
+	fixture.RemoveFile("gone.txt")
+	fixture.Stage("a.txt", "b.txt", "gone.txt")
+	fixture.WriteFile("a.txt", "a unstaged\n")
+
+	repo, err := NewGitRepository(fixture.Dir, true, true)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	ctx := context.Background()
+
+	snapshot, err := repo.SnapshotIndex(ctx)
+	if err != nil {
+		t.Fatalf("SnapshotIndex() error = %v", err)
+	}
+
+	if err := repo.StageFromSnapshot(ctx, snapshot, []string{"b.txt", "gone.txt"}); err != nil {
+		t.Fatalf("StageFromSnapshot() error = %v", err)
+	}
+	if got := strings.Fields(git("diff", "--cached", "--name-status")); strings.Join(got, " ") != "A b.txt D gone.txt" {
+		t.Errorf("staged after StageFromSnapshot = %v, want b.txt added and gone.txt deleted", got)
+	}
+	if err := repo.CreateCommit(ctx, &model.CommitMessage{Type: "feat", Subject: "first group"}); err != nil {
+		t.Fatalf("CreateCommit() error = %v", err)
+	}
+
+	// The rest of the snapshot is staged again, without the unstaged change
+	if err := repo.RestoreIndex(ctx, snapshot); err != nil {
+		t.Fatalf("RestoreIndex() error = %v", err)
+	}
+	if got := strings.TrimSpace(git("diff", "--cached", "--name-only")); got != "a.txt" {
+		t.Errorf("staged after RestoreIndex = %q, want a.txt", got)
+	}
+	if got := git("show", ":a.txt"); got != "a staged\n" {
+		t.Errorf("staged a.txt = %q, want the staged version", got)
+	}
+	if got := git("diff", "a.txt"); !strings.Contains(got, "+a unstaged") {
+		t.Errorf("unstaged change of a.txt was lost:\n%s", got)
+	}
+}
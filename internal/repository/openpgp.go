@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golgoth31/gitcomm/internal/utils"
+)
+
+// defaultGPGProgram is the OpenPGP program used when gpg.program is not set
+const defaultGPGProgram = "gpg"
+
+// gpgLookupTimeout bounds the secret key lookup, which may wait on the gpg agent
+const gpgLookupTimeout = 5 * time.Second
+
+// findOpenPGPKey checks that the secret key for keyID is available to program (through the
+// gpg agent or the keyring), returning its fingerprint. Replaced in tests.
+var findOpenPGPKey = lookupOpenPGPKey
+
+// openPGPKeyLookup caches whether the OpenPGP secret key is available
+type openPGPKeyLookup struct {
+	once sync.Once
+	err  error
+}
+
+// signingEnabled reports whether commits and tags are signed. The OpenPGP secret key is
+// looked up on the first signature rather than when the repository is opened, as the
+// lookup may wait on the gpg agent; without it, commits are created unsigned.
+func (r *gitRepositoryImpl) signingEnabled() bool {
+	if !r.signer.Enabled || r.signer.Format != "openpgp" {
+		return r.signer.Enabled
+	}
+	r.gpgKey.once.Do(func() {
+		fingerprint, err := findOpenPGPKey(r.signer.Program, r.signer.KeyID)
+		if err != nil {
+			utils.Logger.Debug().Err(err).Msg("OpenPGP signing key not found, signing disabled")
+			r.gpgKey.err = err
+			return
+		}
+		utils.Logger.Debug().Str("key", r.signer.KeyID).Str("fingerprint", fingerprint).Msg("OpenPGP signing key found")
+	})
+	return r.gpgKey.err == nil
+}
+
+// lookupOpenPGPKey asks program for the secret key matching keyID, like git does before
+// signing with "gpg -bsau <keyID>"
+func lookupOpenPGPKey(program, keyID string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), gpgLookupTimeout)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, program, "--batch", "--with-colons", "--list-secret-keys", "--", keyID).Output()
+	if err != nil {
+		return "", fmt.Errorf("%w: no secret key %q available to %s: %v", ErrGitSigningFailed, keyID, program, err)
+	}
+	fingerprint := parseSecretKeyFingerprint(string(out))
+	if fingerprint == "" {
+		return "", fmt.Errorf("%w: no secret key %q available to %s", ErrGitSigningFailed, keyID, program)
+	}
+	return fingerprint, nil
+}
+
+// parseSecretKeyFingerprint returns the fingerprint of the first secret key in gpg
+// --with-colons output ("" if none)
+func parseSecretKeyFingerprint(out string) string {
+	inSecretKey := false
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Split(line, ":")
+		switch fields[0] {
+		case "sec":
+			inSecretKey = true
+		case "fpr":
+			if inSecretKey && len(fields) > 9 {
+				return fields[9]
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,239 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/utils"
+	gitconfig "github.com/golgoth31/gitcomm/pkg/git/config"
+	"github.com/golgoth31/gitcomm/pkg/testutil"
+)
+
+func TestParseSecretKeyFingerprint(t *testing.T) {
+	tests := []struct {
+		name string
+		out  string
+		want string
+	}{
+		{
+			name: "secret key",
+			out: "sec:u:255:22:3AA5C34371567BD2:1700000000:::u:::scESC:::+:::ed25519:::0:\n" +
+				"fpr:::::::::ABCDEF0123456789ABCDEF0123456789ABCDEF01:\n" +
+				"uid:u::::1700000000::HASH::Jane Doe <jane@example.com>::::::::::0:\n" +
+				"ssb:u:255:18:0123456789ABCDEF:1700000000::::::e:::+:::cv25519:::\n" +
+				"fpr:::::::::1111111111111111111111111111111111111111:\n",
+			want: "ABCDEF0123456789ABCDEF0123456789ABCDEF01",
+		},
+		{name: "no key", out: "", want: ""},
+		{name: "public key only", out: "pub:u:255:22:3AA5C34371567BD2::::::::\nfpr:::::::::ABCD:\n", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseSecretKeyFingerprint(tt.out); got != tt.want {
+				t.Errorf("parseSecretKeyFingerprint() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrepareCommitSigner_Formats(t *testing.T) {
+	utils.InitLogger(true)
+
+	original := findOpenPGPKey
+	defer func() { findOpenPGPKey = original }()
+	findOpenPGPKey = func(program, keyID string) (string, error) {
+		t.Errorf("prepareCommitSigner() looked up %s with %s, want no lookup before signing", keyID, program)
+		return "ABCDEF", nil
+	}
+
+	tests := []struct {
+		name        string
+		config      gitconfig.GitConfig
+		noSign      bool
+		wantEnabled bool
+		wantFormat  string
+		wantProgram string
+	}{
+		{"ssh", gitconfig.GitConfig{GPGFormat: "ssh", SigningKey: "/keys/id.pub"}, false, true, "ssh", ""},
+		{"ssh with commit.gpgsign false", gitconfig.GitConfig{GPGFormat: "ssh", SigningKey: "/keys/id.pub", CommitGPGSignSet: true}, false, false, "ssh", ""},
+		{"openpgp", gitconfig.GitConfig{GPGFormat: "openpgp", SigningKey: "3AA5C343", CommitGPGSign: true, CommitGPGSignSet: true}, false, true, "openpgp", "gpg"},
+		{"openpgp by default", gitconfig.GitConfig{SigningKey: "3AA5C343", GPGProgram: "gpg2", CommitGPGSign: true, CommitGPGSignSet: true}, false, true, "openpgp", "gpg2"},
+		{"openpgp without commit.gpgsign", gitconfig.GitConfig{SigningKey: "3AA5C343"}, false, false, "openpgp", ""},
+		{"no signing key", gitconfig.GitConfig{GPGFormat: "openpgp", CommitGPGSign: true, CommitGPGSignSet: true}, false, false, "openpgp", ""},
+		{"x509 not supported", gitconfig.GitConfig{GPGFormat: "x509", SigningKey: "key", CommitGPGSign: true, CommitGPGSignSet: true}, false, false, "x509", ""},
+		{"no-sign", gitconfig.GitConfig{SigningKey: "3AA5C343", CommitGPGSign: true, CommitGPGSignSet: true}, true, false, "openpgp", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signer := prepareCommitSigner(&tt.config, tt.noSign)
+			if signer.Enabled != tt.wantEnabled || signer.Format != tt.wantFormat {
+				t.Errorf("prepareCommitSigner() = enabled %v format %q, want %v %q", signer.Enabled, signer.Format, tt.wantEnabled, tt.wantFormat)
+			}
+			if signer.Program != tt.wantProgram {
+				t.Errorf("prepareCommitSigner() program = %q, want %q", signer.Program, tt.wantProgram)
+			}
+		})
+	}
+}
+
+func TestSigningEnabled_LooksUpOpenPGPKeyOnce(t *testing.T) {
+	utils.InitLogger(true)
+
+	original := findOpenPGPKey
+	defer func() { findOpenPGPKey = original }()
+	var lookedUp []string
+	findOpenPGPKey = func(program, keyID string) (string, error) {
+		lookedUp = append(lookedUp, program+" "+keyID)
+		if keyID == "MISSING" {
+			return "", ErrGitSigningFailed
+		}
+		return "ABCDEF", nil
+	}
+
+	tests := []struct {
+		name       string
+		signer     gitconfig.CommitSigner
+		want       bool
+		wantLookup string
+	}{
+		{"openpgp key found", gitconfig.CommitSigner{Format: "openpgp", Program: "gpg", KeyID: "3AA5C343", Enabled: true}, true, "gpg 3AA5C343"},
+		{"openpgp key not found", gitconfig.CommitSigner{Format: "openpgp", Program: "gpg", KeyID: "MISSING", Enabled: true}, false, "gpg MISSING"},
+		{"ssh", gitconfig.CommitSigner{Format: "ssh", PublicKeyPath: "/keys/id.pub", Enabled: true}, true, ""},
+		{"disabled", gitconfig.CommitSigner{Format: "openpgp", KeyID: "3AA5C343"}, false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lookedUp = nil
+			repo := &gitRepositoryImpl{signer: &tt.signer}
+			for range 2 {
+				if got := repo.signingEnabled(); got != tt.want {
+					t.Errorf("signingEnabled() = %v, want %v", got, tt.want)
+				}
+			}
+			if got := strings.Join(lookedUp, ","); got != tt.wantLookup {
+				t.Errorf("key lookup = %q, want %q", got, tt.wantLookup)
+			}
+		})
+	}
+}
+
+func TestCreateCommit_SignsWithOpenPGPKey(t *testing.T) {
+	fixture, fingerprint, repo := newOpenPGPSigningRepo(t)
+
+	if err := repo.CreateCommit(context.Background(), &model.CommitMessage{Type: "docs", Subject: "sign with gpg"}); err != nil {
+		t.Fatalf("CreateCommit() error = %v", err)
+	}
+
+	out := fixture.Git("log", "-1", "--format=%G?%n%GF")
+	lines := strings.Fields(out)
+	if len(lines) != 2 || lines[0] != "G" || lines[1] != fingerprint {
+		t.Errorf("signature status = %q, want a good signature by %s", out, fingerprint)
+	}
+}
+
+func TestCreateTag_SignsWithOpenPGPKey(t *testing.T) {
+	fixture, fingerprint, repo := newOpenPGPSigningRepo(t)
+	ctx := context.Background()
+
+	if err := repo.CreateCommit(ctx, &model.CommitMessage{Type: "feat", Subject: "first release"}); err != nil {
+		t.Fatalf("CreateCommit() error = %v", err)
+	}
+	signed, err := repo.CreateTag(ctx, "v1.0.0", "", "Release v1.0.0")
+	if err != nil {
+		t.Fatalf("CreateTag() error = %v", err)
+	}
+	if !signed {
+		t.Error("CreateTag() signed = false, want true")
+	}
+
+	out := fixture.Git("verify-tag", "--raw", "v1.0.0")
+	if !strings.Contains(out, "VALIDSIG "+fingerprint) {
+		t.Errorf("verify-tag output = %s, want a valid signature by %s", out, fingerprint)
+	}
+
+	// Existing tags are not replaced
+	if _, err := repo.CreateTag(ctx, "v1.0.0", "", "Release v1.0.0"); err == nil {
+		t.Error("CreateTag() on an existing tag succeeded, want an error")
+	}
+}
+
+func TestCreateTag_SigningFailure(t *testing.T) {
+	utils.InitLogger(true)
+
+	original := findOpenPGPKey
+	defer func() { findOpenPGPKey = original }()
+	findOpenPGPKey = func(program, keyID string) (string, error) {
+		return "", ErrGitSigningFailed
+	}
+
+	fixture := testutil.NewRepo(t)
+	fixture.Git("config", "user.signingkey", "MISSING")
+	fixture.Git("config", "commit.gpgsign", "true")
+	fixture.Git("commit", "--allow-empty", "--no-gpg-sign", "-m", "feat: first")
+
+	repo, err := NewGitRepository(fixture.Dir, false, true)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	signed, err := repo.CreateTag(context.Background(), "v1.0.0", "", "Release v1.0.0")
+	if !errors.Is(err, ErrGitSigningFailed) || signed {
+		t.Fatalf("CreateTag() = %v, %v, want ErrGitSigningFailed", signed, err)
+	}
+	if out := fixture.Git("tag", "--list"); len(out) != 0 {
+		t.Errorf("tags = %q, want no unsigned tag", out)
+	}
+}
+
+// newOpenPGPSigningRepo creates a repository with a staged README.md whose git config
+// signs with a throwaway, passphrase-less gpg key. Returns its fixture, the key
+// fingerprint and the repository. Skips the test when gpg is unavailable.
+func newOpenPGPSigningRepo(t *testing.T) (*testutil.Repo, string, GitRepository) {
+	t.Helper()
+	if _, err := exec.LookPath("gpg"); err != nil {
+		t.Skip("gpg not installed")
+	}
+	utils.InitLogger(true)
+
+	// GNUPGHOME stays short: the agent socket path is limited in length
+	gnupgHome, err := os.MkdirTemp("", "gnupg")
+	if err != nil {
+		t.Fatalf("Failed to create GNUPGHOME: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = exec.Command("gpgconf", "--homedir", gnupgHome, "--kill", "gpg-agent").Run()
+		_ = os.RemoveAll(gnupgHome)
+	})
+	t.Setenv("GNUPGHOME", gnupgHome)
+	if out, err := exec.Command("gpg", "--batch", "--passphrase", "", "--quick-gen-key",
+		"Jane Doe <jane@example.com>", "ed25519", "sign", "never").CombinedOutput(); err != nil {
+		t.Skipf("Failed to generate a gpg key: %v\n%s", err, out)
+	}
+
+	fingerprint, err := lookupOpenPGPKey("gpg", "jane@example.com")
+	if err != nil {
+		t.Fatalf("lookupOpenPGPKey() error = %v", err)
+	}
+	if _, err := lookupOpenPGPKey("gpg", "nobody@example.com"); !errors.Is(err, ErrGitSigningFailed) {
+		t.Errorf("lookupOpenPGPKey() for a missing key error = %v, want ErrGitSigningFailed", err)
+	}
+
+	fixture := testutil.NewRepo(t)
+	config := "[user]\n\tname = Jane Doe\n\temail = jane@example.com\n\tsigningkey = " + fingerprint + "\n[commit]\n\tgpgsign = true\n"
+	fixture.WriteFile(".git/config", config)
+	fixture.WriteFile("README.md", "# signed\n")
+	fixture.Stage("README.md")
+
+	repo, err := NewGitRepository(fixture.Dir, false, true)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	return fixture, fingerprint, repo
+}
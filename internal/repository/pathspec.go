@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// pathspecArgs returns the "--" separated pathspecs of ctx to append to a git command,
+// nil when the command is not limited to some paths
+func pathspecArgs(ctx context.Context) []string {
+	if ctx == nil {
+		return nil
+	}
+	pathspecs, _ := ctx.Value(PathspecKey).([]string)
+	if len(pathspecs) == 0 {
+		return nil
+	}
+	return append([]string{"--"}, pathspecs...)
+}
+
+// ResolvePathspecs makes pathspecs given from dir relative to the worktree root, where git
+// commands run. Pathspecs with magic (":(glob)src/**", ":/docs") are kept as is, and
+// paths outside the worktree are refused.
+func ResolvePathspecs(root, dir string, pathspecs []string) ([]string, error) {
+	root, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve worktree %s: %w", root, err)
+	}
+	if dir, err = filepath.EvalSymlinks(dir); err != nil {
+		return nil, fmt.Errorf("failed to resolve directory %s: %w", dir, err)
+	}
+
+	resolved := make([]string, 0, len(pathspecs))
+	for _, pathspec := range pathspecs {
+		if pathspec == "" {
+			return nil, fmt.Errorf("empty pathspec")
+		}
+		if strings.HasPrefix(pathspec, ":") {
+			resolved = append(resolved, pathspec)
+			continue
+		}
+
+		path := pathspec
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return nil, fmt.Errorf("pathspec %q is outside the repository %s", pathspec, root)
+		}
+		rel = filepath.ToSlash(rel)
+		// filepath.Join drops the trailing slash limiting a pathspec to directories
+		if strings.HasSuffix(pathspec, "/") && rel != "." {
+			rel += "/"
+		}
+		resolved = append(resolved, rel)
+	}
+	return resolved, nil
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/pkg/testutil"
+)
+
+func TestResolvePathspecs(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "src", "api"), 0755); err != nil {
+		t.Fatalf("failed to create directories: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		dir       string
+		pathspecs []string
+		want      []string
+		wantErr   bool
+	}{
+		{name: "from the root", dir: root, pathspecs: []string{"src/api/", "README.md"}, want: []string{"src/api/", "README.md"}},
+		{name: "from a subdirectory", dir: filepath.Join(root, "src"), pathspecs: []string{"api/", "../docs", "."}, want: []string{"src/api/", "docs", "src"}},
+		{name: "root directory", dir: filepath.Join(root, "src"), pathspecs: []string{"../"}, want: []string{"."}},
+		{name: "absolute path", dir: filepath.Join(root, "src"), pathspecs: []string{filepath.Join(root, "go.mod")}, want: []string{"go.mod"}},
+		{name: "magic kept as is", dir: filepath.Join(root, "src"), pathspecs: []string{":(glob)**/*.go", ":/docs"}, want: []string{":(glob)**/*.go", ":/docs"}},
+		{name: "outside the repository", dir: root, pathspecs: []string{"../other"}, wantErr: true},
+		{name: "empty", dir: root, pathspecs: []string{""}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolvePathspecs(root, tt.dir, tt.pathspecs)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResolvePathspecs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !slices.Equal(got, tt.want) {
+				t.Errorf("ResolvePathspecs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPathspecKey_LimitsStagingStateAndCommit(t *testing.T) {
+	fixture := testutil.NewRepoWithCommit(t, map[string]string{
+		"src/api/handler.go": "package api\n",
+		"src/web/app.ts":     "export {}\n",
+		"docs/guide.md":      "# Guide\n",
+	})
+	fixture.WriteFile("src/api/handler.go", "package api\n\nfunc Handle() {}\n")
+	fixture.WriteFile("src/api/health.go", "package api\n")
+	fixture.WriteFile("src/web/app.ts", "export const app = 1\n")
+	fixture.WriteFile("docs/guide.md", "# Guide\n\nUsage.\n")
+	fixture.Stage("docs/guide.md")
+
+	repo, err := NewGitRepository(fixture.Dir, true, true)
+	if err != nil {
+		t.Fatalf("NewGitRepository() error = %v", err)
+	}
+	ctx := context.WithValue(context.Background(), PathspecKey, []string{"src/api/"})
+
+	result, err := repo.StageAllFilesIncludingUntracked(ctx)
+	if err != nil {
+		t.Fatalf("StageAllFilesIncludingUntracked() error = %v", err)
+	}
+	if want := []string{"src/api/handler.go", "src/api/health.go"}; !slices.Equal(result.StagedFiles, want) {
+		t.Errorf("StagedFiles = %v, want %v", result.StagedFiles, want)
+	}
+
+	state, err := repo.GetRepositoryState(ctx)
+	if err != nil {
+		t.Fatalf("GetRepositoryState() error = %v", err)
+	}
+	var staged []string
+	for _, file := range state.StagedFiles {
+		staged = append(staged, file.Path)
+		if file.Diff == "" {
+			t.Errorf("%s has no diff", file.Path)
+		}
+	}
+	if want := []string{"src/api/handler.go", "src/api/health.go"}; !slices.Equal(staged, want) {
+		t.Errorf("StagedFiles = %v, want %v (docs/guide.md is staged but outside the pathspec)", staged, want)
+	}
+	if len(state.UnstagedFiles) != 0 {
+		t.Errorf("UnstagedFiles = %+v, want none under src/api/", state.UnstagedFiles)
+	}
+
+	stats, err := repo.StagedDiffStats(ctx)
+	if err != nil {
+		t.Fatalf("StagedDiffStats() error = %v", err)
+	}
+	if stats.FilesChanged != 2 {
+		t.Errorf("StagedDiffStats() files = %d, want 2", stats.FilesChanged)
+	}
+
+	if err := repo.CreateCommit(ctx, &model.CommitMessage{Type: "feat", Scope: "api", Subject: "add health"}); err != nil {
+		t.Fatalf("CreateCommit() error = %v", err)
+	}
+	if got := fixture.Git("show", "--name-only", "--format=", "HEAD"); got != "src/api/handler.go\nsrc/api/health.go\n" {
+		t.Errorf("committed = %q, want the files under src/api/", got)
+	}
+	// The changes outside the pathspec are left as they were
+	if got := fixture.Git("status", "--porcelain=v1"); got != "M  docs/guide.md\n M src/web/app.ts\n" {
+		t.Errorf("status = %q, want docs/guide.md staged and src/web/app.ts modified", got)
+	}
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+)
+
+// renameInfo returns the source path and similarity of a rename or copy diff, from its
+// extended header ("similarity index 87%", "rename from old.txt"); status is "renamed",
+// "copied" or "" when the diff is neither
+func renameInfo(diff string) (status, oldPath string, similarity int) {
+	for _, line := range strings.Split(diff, "\n") {
+		if strings.HasPrefix(line, "@@") || strings.HasPrefix(line, "--- ") || strings.HasPrefix(line, "Binary files") {
+			break
+		}
+		switch {
+		case strings.HasPrefix(line, "similarity index "):
+			similarity, _ = strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(line, "similarity index "), "%"))
+		case strings.HasPrefix(line, "rename from "):
+			status, oldPath = "renamed", unquotePath(strings.TrimPrefix(line, "rename from "))
+		case strings.HasPrefix(line, "copy from "):
+			status, oldPath = "copied", unquotePath(strings.TrimPrefix(line, "copy from "))
+		}
+	}
+	return status, oldPath, similarity
+}
+
+// unquotePath decodes a path git quoted for its special characters
+func unquotePath(path string) string {
+	if unquoted, err := strconv.Unquote(path); err == nil && strings.HasPrefix(path, `"`) {
+		return unquoted
+	}
+	return path
+}
+
+// applyRenames marks the files whose diff is a rename or copy with their source path and
+// similarity. The source of a rename git status listed as deleted (status.renames off) is
+// dropped, its removal being part of the rename.
+func applyRenames(files []model.FileChange, diffs map[string]string) []model.FileChange {
+	renamed := make(map[string]bool)
+	for i, file := range files {
+		status, oldPath, similarity := renameInfo(diffs[file.Path])
+		if status == "" {
+			continue
+		}
+		files[i].Status, files[i].OldPath, files[i].Similarity = status, oldPath, similarity
+		if status == "renamed" {
+			renamed[oldPath] = true
+		}
+	}
+
+	kept := files[:0]
+	for _, file := range files {
+		if file.Status == "deleted" && renamed[file.Path] {
+			continue
+		}
+		kept = append(kept, file)
+	}
+	return kept
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/pkg/testutil"
+)
+
+// numberedLines returns n lines of content, so that small edits keep files similar
+func numberedLines(n int) string {
+	var sb strings.Builder
+	for i := range n {
+		sb.WriteString("line " + strings.Repeat("x", i%7) + "\n")
+	}
+	return sb.String()
+}
+
+func TestRenameInfo(t *testing.T) {
+	tests := []struct {
+		name           string
+		diff           string
+		wantStatus     string
+		wantOldPath    string
+		wantSimilarity int
+	}{
+		{
+			name:           "rename with changes",
+			diff:           "diff --git a/old.txt b/new.txt\nsimilarity index 87%\nrename from old.txt\nrename to new.txt\nindex 1..2 100644\n--- a/old.txt\n+++ b/new.txt\n@@ -1 +1 @@\n-rename from x\n+y",
+			wantStatus:     "renamed",
+			wantOldPath:    "old.txt",
+			wantSimilarity: 87,
+		},
+		{
+			name:           "copy",
+			diff:           "diff --git a/a.go b/b.go\nsimilarity index 100%\ncopy from a.go\ncopy to b.go",
+			wantStatus:     "copied",
+			wantOldPath:    "a.go",
+			wantSimilarity: 100,
+		},
+		{
+			name:           "quoted path",
+			diff:           "diff --git \"a/caf\\303\\251.txt\" b/cafe.txt\nsimilarity index 100%\nrename from \"caf\\303\\251.txt\"\nrename to cafe.txt",
+			wantStatus:     "renamed",
+			wantOldPath:    "café.txt",
+			wantSimilarity: 100,
+		},
+		{
+			name: "modification",
+			diff: "diff --git a/a.go b/a.go\nindex 1..2 100644\n--- a/a.go\n+++ b/a.go\n@@ -1 +1 @@\n-a\n+b",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, oldPath, similarity := renameInfo(tt.diff)
+			if status != tt.wantStatus || oldPath != tt.wantOldPath || similarity != tt.wantSimilarity {
+				t.Errorf("renameInfo() = %q, %q, %d, want %q, %q, %d", status, oldPath, similarity, tt.wantStatus, tt.wantOldPath, tt.wantSimilarity)
+			}
+		})
+	}
+}
+
+func TestGetRepositoryState_DetectsRenamesAndCopies(t *testing.T) {
+	for _, statusRenames := range []string{"true", "false"} {
+		t.Run("status.renames="+statusRenames, func(t *testing.T) {
+			fixture := testutil.NewRepoWithCommit(t, map[string]string{
+				"old.txt":    numberedLines(20),
+				"handler.go": numberedLines(30),
+			})
+			fixture.Git("config", "status.renames", statusRenames)
+			fixture.Rename("old.txt", "new.txt")
+			fixture.WriteFile("new.txt", numberedLines(20)+"appended\n")
+			// Copies are found among the files changed by the commit
+			fixture.WriteFile("handler.go", numberedLines(30)+"edited\n")
+			fixture.WriteFile("handler_v2.go", numberedLines(30))
+			fixture.Git("add", "-A")
+
+			repo, err := NewGitRepository(fixture.Dir, true, true)
+			if err != nil {
+				t.Fatalf("NewGitRepository() error = %v", err)
+			}
+			state, err := repo.GetRepositoryState(context.Background())
+			if err != nil {
+				t.Fatalf("GetRepositoryState() error = %v", err)
+			}
+			files := make(map[string]model.FileChange)
+			for _, file := range state.StagedFiles {
+				files[file.Path] = file
+			}
+
+			if _, ok := files["old.txt"]; ok {
+				t.Errorf("old.txt listed apart from its rename: %+v", state.StagedFiles)
+			}
+			renamed := files["new.txt"]
+			if renamed.Status != "renamed" || renamed.OldPath != "old.txt" || renamed.Similarity < 50 || renamed.Similarity == 100 {
+				t.Errorf("new.txt = %+v, want renamed from old.txt with its similarity", renamed)
+			}
+			if !strings.Contains(renamed.Diff, "+appended") {
+				t.Errorf("new.txt diff = %q, want the content delta", renamed.Diff)
+			}
+			copied := files["handler_v2.go"]
+			if copied.Status != "copied" || copied.OldPath != "handler.go" || copied.Similarity != 100 {
+				t.Errorf("handler_v2.go = %+v, want copied from handler.go, 100%% similar", copied)
+			}
+		})
+	}
+}
+
+func TestGetCommitState_DetectsRenames(t *testing.T) {
+	fixture := testutil.NewRepoWithCommit(t, map[string]string{"old.txt": numberedLines(20)})
+	fixture.Rename("old.txt", "new.txt")
+	commit := fixture.Commit("refactor: rename old.txt")
+
+	repo, err := NewGitRepository(fixture.Dir, true, true)
+	if err != nil {
+		t.Fatalf("NewGitRepository() error = %v", err)
+	}
+	state, err := repo.GetCommitState(context.Background(), commit)
+	if err != nil {
+		t.Fatalf("GetCommitState() error = %v", err)
+	}
+	want := []model.FileChange{{Path: "new.txt", Status: "renamed", OldPath: "old.txt", Similarity: 100}}
+	if len(state.StagedFiles) != 1 {
+		t.Fatalf("StagedFiles = %+v, want %+v", state.StagedFiles, want)
+	}
+	got := state.StagedFiles[0]
+	got.Diff = ""
+	if got != want[0] {
+		t.Errorf("StagedFiles[0] = %+v, want %+v", got, want[0])
+	}
+}
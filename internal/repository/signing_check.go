@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+	gitconfig "github.com/golgoth31/gitcomm/pkg/git/config"
+	"golang.org/x/crypto/ssh"
+)
+
+// GetIdentity returns the author identity read from the git config files
+func (r *gitRepositoryImpl) GetIdentity() (name, email string) {
+	return r.config.UserName, r.config.UserEmail
+}
+
+// CheckSigning returns how commits are signed, nil when no signing key is configured,
+// with an error when the key cannot sign: an SSH public key that does not parse, an
+// OpenPGP secret key unavailable to gpg.program, or an unsupported gpg.format
+func (r *gitRepositoryImpl) CheckSigning(ctx context.Context) (*model.SigningStatus, error) {
+	if r.config.SigningKey == "" {
+		return nil, nil
+	}
+	status := &model.SigningStatus{
+		Format:  cmp.Or(r.config.GPGFormat, "openpgp"),
+		Key:     r.config.SigningKey,
+		Enabled: r.signer.Enabled,
+	}
+
+	switch status.Format {
+	case "ssh":
+		publicKey, err := gitconfig.ParseSigningKey(status.Key)
+		if err != nil {
+			return status, fmt.Errorf("%w: %v", ErrGitSigningFailed, err)
+		}
+		if signer, err := gitconfig.NewAgentSigner(status.Key); err == nil {
+			status.Agent = true
+			signer.Close()
+		}
+		status.Program = r.config.SSHProgram
+		status.AllowedSignersFile = r.config.SSHAllowedSignersFile
+		status.Principals, status.IdentityWarning = r.sshIdentity(publicKey)
+	case "openpgp":
+		status.Program = r.config.GPGProgram
+		if _, err := findOpenPGPKey(cmp.Or(r.config.GPGProgram, defaultGPGProgram), status.Key); err != nil {
+			return status, err
+		}
+	default:
+		return status, fmt.Errorf("%w: unsupported gpg.format %q (want ssh or openpgp)", ErrGitSigningFailed, status.Format)
+	}
+	return status, nil
+}
+
+// SigningIdentityWarning explains why SSH-signed commits would show as unverified for
+// user.email, "" when they are not signed with SSH or the identity matches
+func (r *gitRepositoryImpl) SigningIdentityWarning() string {
+	if r.signer == nil || !r.signer.Enabled || r.signer.Format != "ssh" {
+		return ""
+	}
+	publicKey, err := gitconfig.ParseSigningKey(r.config.SigningKey)
+	if err != nil {
+		return ""
+	}
+	_, warning := r.sshIdentity(publicKey)
+	return warning
+}
+
+// sshIdentity returns the principals gpg.ssh.allowedSignersFile trusts publicKey for, and
+// a warning when none of them is user.email. Without an allowed signers file, nothing can
+// be checked.
+func (r *gitRepositoryImpl) sshIdentity(publicKey ssh.PublicKey) ([]string, string) {
+	path := r.config.SSHAllowedSignersFile
+	if path == "" {
+		return nil, ""
+	}
+	principals, err := gitconfig.AllowedPrincipals(path, publicKey)
+	switch {
+	case err != nil:
+		return nil, fmt.Sprintf("cannot check the signing identity: %v", err)
+	case len(principals) == 0:
+		return nil, fmt.Sprintf("the SSH signing key %s is not listed in %s: commits signed with it may show as unverified", ssh.FingerprintSHA256(publicKey), path)
+	case !gitconfig.MatchPrincipal(principals, r.config.UserEmail):
+		return principals, fmt.Sprintf("the SSH signing key is trusted for %s in %s, not for user.email %s: commits signed with it may show as unverified", strings.Join(principals, ", "), path, r.config.UserEmail)
+	}
+	return principals, ""
+}
@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/golgoth31/gitcomm/internal/utils"
+	gitconfig "github.com/golgoth31/gitcomm/pkg/git/config"
+	"golang.org/x/crypto/ssh"
+)
+
+func TestCheckSigning(t *testing.T) {
+	utils.InitLogger(true)
+	t.Setenv("SSH_AUTH_SOCK", "")
+
+	original := findOpenPGPKey
+	defer func() { findOpenPGPKey = original }()
+	findOpenPGPKey = func(program, keyID string) (string, error) {
+		if keyID == "MISSING" {
+			return "", ErrGitSigningFailed
+		}
+		return "ABCDEF", nil
+	}
+
+	public, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	sshPublic, err := ssh.NewPublicKey(public)
+	if err != nil {
+		t.Fatalf("failed to convert key: %v", err)
+	}
+	sshKey := "key::" + strings.TrimSpace(string(ssh.MarshalAuthorizedKey(sshPublic)))
+
+	tests := []struct {
+		name       string
+		config     gitconfig.GitConfig
+		wantNil    bool
+		wantFormat string
+		wantErr    bool
+	}{
+		{name: "no signing key", config: gitconfig.GitConfig{GPGFormat: "ssh"}, wantNil: true},
+		{name: "ssh key", config: gitconfig.GitConfig{GPGFormat: "ssh", SigningKey: sshKey}, wantFormat: "ssh"},
+		{name: "unreadable ssh key", config: gitconfig.GitConfig{GPGFormat: "ssh", SigningKey: "/nonexistent/id.pub"}, wantFormat: "ssh", wantErr: true},
+		{name: "openpgp key", config: gitconfig.GitConfig{SigningKey: "3AA5C343"}, wantFormat: "openpgp"},
+		{name: "openpgp key not found", config: gitconfig.GitConfig{SigningKey: "MISSING"}, wantFormat: "openpgp", wantErr: true},
+		{name: "unsupported format", config: gitconfig.GitConfig{GPGFormat: "x509", SigningKey: "key"}, wantFormat: "x509", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &gitRepositoryImpl{config: &tt.config, signer: prepareCommitSigner(&tt.config, false)}
+			status, err := r.CheckSigning(context.Background())
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("CheckSigning() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrGitSigningFailed) {
+				t.Errorf("CheckSigning() error = %v, want ErrGitSigningFailed", err)
+			}
+			if tt.wantNil {
+				if status != nil {
+					t.Errorf("CheckSigning() = %+v, want nil", status)
+				}
+				return
+			}
+			if status == nil || status.Format != tt.wantFormat || status.Agent {
+				t.Errorf("CheckSigning() = %+v, want format %s without agent", status, tt.wantFormat)
+			}
+		})
+	}
+}
+
+func TestSigningIdentityWarning(t *testing.T) {
+	utils.InitLogger(true)
+
+	newKey := func() string {
+		public, _, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatalf("failed to generate key: %v", err)
+		}
+		sshPublic, err := ssh.NewPublicKey(public)
+		if err != nil {
+			t.Fatalf("failed to convert key: %v", err)
+		}
+		return strings.TrimSpace(string(ssh.MarshalAuthorizedKey(sshPublic)))
+	}
+	key, other := newKey(), newKey()
+	dir := t.TempDir()
+	allowedSigners := filepath.Join(dir, "allowed_signers")
+	content := "jane@example.com,*@corp.example " + key + "\njohn@example.com " + other + "\n"
+	if err := os.WriteFile(allowedSigners, []byte(content), 0600); err != nil {
+		t.Fatalf("Failed to write allowed signers: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		email       string
+		key         string
+		allowed     string
+		noSign      bool
+		wantWarning string
+	}{
+		{name: "trusted for user.email", email: "jane@example.com", key: key, allowed: allowedSigners},
+		{name: "matching pattern", email: "jane@corp.example", key: key, allowed: allowedSigners},
+		{name: "other principal", email: "jane@personal.example", key: key, allowed: allowedSigners, wantWarning: "trusted for jane@example.com, *@corp.example"},
+		{name: "key not listed", email: "john@example.com", key: newKey(), allowed: allowedSigners, wantWarning: "is not listed"},
+		{name: "missing allowed signers file", email: "jane@example.com", key: key, allowed: filepath.Join(dir, "missing"), wantWarning: "cannot check"},
+		{name: "no allowed signers file", email: "jane@personal.example", key: key},
+		{name: "not signing", email: "jane@personal.example", key: key, allowed: allowedSigners, noSign: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := gitconfig.GitConfig{
+				UserEmail:             tt.email,
+				SigningKey:            "key::" + tt.key,
+				GPGFormat:             "ssh",
+				SSHAllowedSignersFile: tt.allowed,
+			}
+			r := &gitRepositoryImpl{config: &config, signer: prepareCommitSigner(&config, tt.noSign)}
+			warning := r.SigningIdentityWarning()
+			if tt.wantWarning == "" && warning != "" || !strings.Contains(warning, tt.wantWarning) {
+				t.Errorf("SigningIdentityWarning() = %q, want %q", warning, tt.wantWarning)
+			}
+		})
+	}
+}
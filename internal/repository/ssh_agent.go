@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/golgoth31/gitcomm/internal/utils"
+	gitconfig "github.com/golgoth31/gitcomm/pkg/git/config"
+)
+
+// newAgentSigner returns the ssh-agent signer for the SSH signing key, or nil when SSH
+// signing is not configured, gpg.ssh.program is set (the program signs, through git) or
+// the key is not loaded in the agent. Every commit and tag gitcomm creates is signed by
+// the agent when it returns a signer.
+func (r *gitRepositoryImpl) newAgentSigner() *gitconfig.AgentSigner {
+	if !r.signer.Enabled || r.signer.Format != "ssh" || r.signer.Program != "" {
+		return nil
+	}
+	signer, err := gitconfig.NewAgentSigner(r.signer.PublicKeyPath)
+	if err != nil {
+		utils.Logger.Debug().Err(err).Msg("SSH signing key not available in ssh-agent, signing through git")
+		return nil
+	}
+	return signer
+}
+
+// signObject writes a copy of the commit or tag object hash signed by signer, as git does
+// for signed objects: commits get a gpgsig header, tags the signature appended to their
+// message. Returns the new object hash.
+func (r *gitRepositoryImpl) signObject(ctx context.Context, env []string, objectType, hash string, signer *gitconfig.AgentSigner) (string, error) {
+	raw, err := r.execGitWithEnvOutput(ctx, env, "cat-file", objectType, hash)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s %s: %w", objectType, hash, err)
+	}
+
+	signature, err := signer.Sign([]byte(raw), gitconfig.GitSignatureNamespace)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrGitSigningFailed, err)
+	}
+
+	signed := raw + string(signature)
+	if objectType == "commit" {
+		if signed, err = withSignatureHeader(raw, string(signature)); err != nil {
+			return "", err
+		}
+	}
+
+	file, err := os.CreateTemp("", "gitcomm-"+objectType+"-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to write signed %s: %w", objectType, err)
+	}
+	defer os.Remove(file.Name())
+	if _, err := file.WriteString(signed); err != nil {
+		file.Close()
+		return "", fmt.Errorf("failed to write signed %s: %w", objectType, err)
+	}
+	if err := file.Close(); err != nil {
+		return "", fmt.Errorf("failed to write signed %s: %w", objectType, err)
+	}
+
+	signedHash, err := r.execGitWithEnvOutput(ctx, env, "hash-object", "-t", objectType, "-w", file.Name())
+	if err != nil {
+		return "", fmt.Errorf("failed to write signed %s: %w", objectType, err)
+	}
+	return strings.TrimSpace(signedHash), nil
+}
+
+// signRef replaces the commit or tag object ref points to with a copy signed by signer,
+// for the objects git commit and git tag create unsigned. On failure, ref is left as is.
+func (r *gitRepositoryImpl) signRef(ctx context.Context, env []string, objectType, ref string, signer *gitconfig.AgentSigner) error {
+	unsigned, err := r.execGitWithEnvOutput(ctx, env, "rev-parse", "--verify", "--quiet", ref)
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %w", ref, err)
+	}
+	unsigned = strings.TrimSpace(unsigned)
+	signed, err := r.signObject(ctx, env, objectType, unsigned, signer)
+	if err != nil {
+		return err
+	}
+	if _, err := r.execGitWithEnvOutput(ctx, env, "update-ref", "-m", "gitcomm: sign "+objectType, ref, signed, unsigned); err != nil {
+		return fmt.Errorf("failed to update %s with the signed %s: %w", ref, objectType, err)
+	}
+	return nil
+}
+
+// withSignatureHeader adds signature to the raw commit object as a gpgsig header, after
+// the other headers, with continuation lines indented by one space
+func withSignatureHeader(raw, signature string) (string, error) {
+	headers, message, found := strings.Cut(raw, "\n\n")
+	if !found {
+		return "", fmt.Errorf("failed to sign commit: unexpected object format")
+	}
+	header := "gpgsig " + strings.ReplaceAll(strings.TrimSuffix(signature, "\n"), "\n", "\n ")
+	return headers + "\n" + header + "\n\n" + message, nil
+}
@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/utils"
+	gitconfig "github.com/golgoth31/gitcomm/pkg/git/config"
+	"github.com/golgoth31/gitcomm/pkg/testutil"
+	"golang.org/x/crypto/ssh"
+)
+
+func TestWithSignatureHeader(t *testing.T) {
+	raw := "tree 4b825dc642cb6eb9a060e54bf8d69288fbee4904\nauthor A <a@example.com> 1 +0000\n\nfeat: x\n\nbody\n"
+	signature := "-----BEGIN SSH SIGNATURE-----\nAAAA\n-----END SSH SIGNATURE-----\n"
+
+	got, err := withSignatureHeader(raw, signature)
+	if err != nil {
+		t.Fatalf("withSignatureHeader() error = %v", err)
+	}
+	want := "tree 4b825dc642cb6eb9a060e54bf8d69288fbee4904\nauthor A <a@example.com> 1 +0000\n" +
+		"gpgsig -----BEGIN SSH SIGNATURE-----\n AAAA\n -----END SSH SIGNATURE-----\n\nfeat: x\n\nbody\n"
+	if got != want {
+		t.Errorf("withSignatureHeader() = %q, want %q", got, want)
+	}
+
+	if _, err := withSignatureHeader("tree abc\n", signature); err == nil {
+		t.Error("withSignatureHeader() without a message succeeded, want an error")
+	}
+}
+
+func TestCreateCommitObject_SignsWithAgentKey(t *testing.T) {
+	git, repo := newAgentSigningRepo(t)
+
+	hash, err := repo.CreateCommitObject(context.Background(), &model.CommitMessage{Type: "docs", Subject: "sign with the agent"})
+	if err != nil {
+		t.Fatalf("CreateCommitObject() error = %v", err)
+	}
+
+	if status := git("log", "-1", "--format=%G?", hash); status != "G" {
+		t.Errorf("signature status = %q, want G", status)
+	}
+	if subject := git("log", "-1", "--format=%s", hash); subject != "docs: sign with the agent" {
+		t.Errorf("subject = %q, want the commit message", subject)
+	}
+}
+
+func TestCreateCommitAndTag_SignWithAgentKey(t *testing.T) {
+	git, repo := newAgentSigningRepo(t)
+	ctx := context.Background()
+
+	if err := repo.CreateCommit(ctx, &model.CommitMessage{Type: "docs", Subject: "sign with the agent"}); err != nil {
+		t.Fatalf("CreateCommit() error = %v", err)
+	}
+	if status := git("log", "-1", "--format=%G?%n%s"); status != "G\ndocs: sign with the agent" {
+		t.Errorf("HEAD signature status and subject = %q, want a good signature", status)
+	}
+	if staged := git("diff", "--cached", "--name-only"); staged != "" {
+		t.Errorf("staged files after commit = %q, want none", staged)
+	}
+
+	signed, err := repo.CreateTag(ctx, "v1.0.0", "", "Release v1.0.0")
+	if err != nil || !signed {
+		t.Fatalf("CreateTag() = %v, %v, want a signed tag", signed, err)
+	}
+	git("verify-tag", "v1.0.0")
+	if message := git("tag", "-l", "--format=%(contents:subject)", "v1.0.0"); message != "Release v1.0.0" {
+		t.Errorf("tag message = %q, want the annotation", message)
+	}
+}
+
+func TestNewAgentSigner_SSHProgram(t *testing.T) {
+	utils.InitLogger(true)
+	publicKey := testutil.StartSSHAgent(t)
+	signingKey := "key::" + strings.TrimSpace(string(ssh.MarshalAuthorizedKey(publicKey)))
+
+	agentRepo := &gitRepositoryImpl{signer: &gitconfig.CommitSigner{Format: "ssh", PublicKeyPath: signingKey, Enabled: true}}
+	signer := agentRepo.newAgentSigner()
+	if signer == nil {
+		t.Fatal("newAgentSigner() = nil, want the agent key")
+	}
+	signer.Close()
+
+	// gpg.ssh.program signs through git, the agent is not used
+	programRepo := &gitRepositoryImpl{signer: &gitconfig.CommitSigner{Format: "ssh", PublicKeyPath: signingKey, Program: "op-ssh-sign", Enabled: true}}
+	if signer := programRepo.newAgentSigner(); signer != nil {
+		signer.Close()
+		t.Error("newAgentSigner() with gpg.ssh.program returned a signer, want nil")
+	}
+}
+
+// newAgentSigningRepo creates a repository signing with a key of a test ssh-agent, with
+// README.md staged, and returns a git runner for it with the repository
+func newAgentSigningRepo(t *testing.T) (func(args ...string) string, GitRepository) {
+	t.Helper()
+	if _, err := exec.LookPath("ssh-keygen"); err != nil {
+		t.Skip("ssh-keygen not installed")
+	}
+	utils.InitLogger(true)
+	publicKey := testutil.StartSSHAgent(t)
+	authorized := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(publicKey)))
+
+	fixture := testutil.NewRepo(t)
+	git := func(args ...string) string {
+		t.Helper()
+		return strings.TrimSpace(fixture.Git(args...))
+	}
+
+	allowed := filepath.Join(t.TempDir(), "allowed_signers")
+	if err := os.WriteFile(allowed, []byte("jane@example.com "+authorized+"\n"), 0600); err != nil {
+		t.Fatalf("Failed to write allowed signers: %v", err)
+	}
+	git("config", "user.name", "Jane Doe")
+	git("config", "user.email", "jane@example.com")
+	// The fixture disables signing, left to the signing key here
+	git("config", "--unset", "commit.gpgsign")
+	git("config", "--unset", "tag.gpgsign")
+	git("config", "gpg.format", "ssh")
+	git("config", "user.signingkey", "key::"+authorized)
+	git("config", "gpg.ssh.allowedSignersFile", allowed)
+	git("commit", "--allow-empty", "--no-gpg-sign", "-m", "initial")
+	fixture.WriteFile("README.md", "# signed\n")
+	fixture.Stage("README.md")
+
+	repo, err := NewGitRepository(fixture.Dir, false, true)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	return git, repo
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Upstream returns the remote-tracking branch the current branch follows (e.g.
+// "origin/main"), or "" when HEAD is detached, no upstream is configured or the upstream
+// branch is gone
+func (r *gitRepositoryImpl) Upstream(ctx context.Context) (string, error) {
+	branch, err := r.GetCurrentBranch(ctx)
+	if err != nil || branch == "" {
+		return "", err
+	}
+	out, _, err := r.runGitCommand(ctx, r.gitBin, false, "rev-parse", "--abbrev-ref", "--symbolic-full-name", "@{upstream}")
+	var failed *ErrGitCommandFailed
+	if errors.As(err, &failed) {
+		// no upstream configured, or its remote-tracking branch does not exist
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve upstream of %s: %w", branch, err)
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// AheadBehind counts the commits of HEAD missing from upstream (ahead) and the commits of
+// upstream missing from HEAD (behind)
+func (r *gitRepositoryImpl) AheadBehind(ctx context.Context, upstream string) (ahead, behind int, err error) {
+	out, _, err := r.runGitCommand(ctx, r.gitBin, false, "rev-list", "--left-right", "--count", "HEAD..."+upstream, "--")
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to compare HEAD with %s: %w", upstream, err)
+	}
+	fields := strings.Fields(out)
+	if len(fields) != 2 {
+		return 0, 0, fmt.Errorf("failed to compare HEAD with %s: unexpected output %q", upstream, out)
+	}
+	if ahead, err = strconv.Atoi(fields[0]); err != nil {
+		return 0, 0, fmt.Errorf("failed to compare HEAD with %s: %w", upstream, err)
+	}
+	if behind, err = strconv.Atoi(fields[1]); err != nil {
+		return 0, 0, fmt.Errorf("failed to compare HEAD with %s: %w", upstream, err)
+	}
+	return ahead, behind, nil
+}
+
+// FetchUpstream fetches the remote of the current branch (origin by default), without
+// tags. Credentials are never asked: a remote needing them fails instead.
+func (r *gitRepositoryImpl) FetchUpstream(ctx context.Context) error {
+	env := append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	if _, err := r.execGitWithEnvOutput(ctx, env, "fetch", "--quiet", "--no-tags"); err != nil {
+		return fmt.Errorf("failed to fetch upstream: %w", err)
+	}
+	return nil
+}
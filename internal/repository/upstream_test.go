@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golgoth31/gitcomm/pkg/testutil"
+)
+
+// newTrackingRepo returns a repository whose main branch tracks origin/main, and its origin
+func newTrackingRepo(t *testing.T) (local, origin *testutil.Repo) {
+	t.Helper()
+	origin = testutil.NewRepoWithCommit(t, map[string]string{"README.md": "# test\n"})
+	local = testutil.NewRepo(t)
+	local.Git("remote", "add", "origin", origin.Dir)
+	local.Git("fetch", "-q", "origin")
+	local.Git("reset", "-q", "--hard", "origin/"+testutil.DefaultBranch)
+	local.Git("branch", "-q", "--set-upstream-to=origin/"+testutil.DefaultBranch)
+	return local, origin
+}
+
+func TestUpstream(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name  string
+		setup func(t *testing.T) *testutil.Repo
+		want  string
+	}{
+		{
+			name: "tracking branch",
+			setup: func(t *testing.T) *testutil.Repo {
+				local, _ := newTrackingRepo(t)
+				return local
+			},
+			want: "origin/" + testutil.DefaultBranch,
+		},
+		{
+			name: "no upstream",
+			setup: func(t *testing.T) *testutil.Repo {
+				return testutil.NewRepoWithCommit(t, map[string]string{"README.md": "# test\n"})
+			},
+		},
+		{
+			name: "detached HEAD",
+			setup: func(t *testing.T) *testutil.Repo {
+				local, _ := newTrackingRepo(t)
+				local.Git("checkout", "-q", "--detach")
+				return local
+			},
+		},
+		{
+			name: "upstream branch gone",
+			setup: func(t *testing.T) *testutil.Repo {
+				local, _ := newTrackingRepo(t)
+				local.Git("update-ref", "-d", "refs/remotes/origin/"+testutil.DefaultBranch)
+				return local
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, err := NewGitRepository(tt.setup(t).Dir, true, true)
+			if err != nil {
+				t.Fatalf("NewGitRepository() error = %v", err)
+			}
+			got, err := repo.Upstream(ctx)
+			if err != nil {
+				t.Fatalf("Upstream() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Upstream() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAheadBehind_FetchUpstream(t *testing.T) {
+	ctx := context.Background()
+	local, origin := newTrackingRepo(t)
+	origin.CommitFiles("feat: one", map[string]string{"one.txt": "1\n"})
+	origin.CommitFiles("feat: two", map[string]string{"two.txt": "2\n"})
+	local.CommitFiles("feat: local", map[string]string{"local.txt": "local\n"})
+
+	repo, err := NewGitRepository(local.Dir, true, true)
+	if err != nil {
+		t.Fatalf("NewGitRepository() error = %v", err)
+	}
+	upstream := "origin/" + testutil.DefaultBranch
+
+	ahead, behind, err := repo.AheadBehind(ctx, upstream)
+	if err != nil || ahead != 1 || behind != 0 {
+		t.Errorf("AheadBehind() before fetch = %d, %d, %v, want 1, 0", ahead, behind, err)
+	}
+
+	if err := repo.FetchUpstream(ctx); err != nil {
+		t.Fatalf("FetchUpstream() error = %v", err)
+	}
+	ahead, behind, err = repo.AheadBehind(ctx, upstream)
+	if err != nil || ahead != 1 || behind != 2 {
+		t.Errorf("AheadBehind() after fetch = %d, %d, %v, want 1, 2", ahead, behind, err)
+	}
+
+	if _, _, err := repo.AheadBehind(ctx, "origin/missing"); err == nil {
+		t.Error("AheadBehind() of a missing branch error = nil, want an error")
+	}
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/utils"
+)
+
+// GetWorkspaceSummary returns the branch, tracking status, file counts and last commit
+// date of the worktree. Uses git directly since the porcelain v2 output is parsed.
+func (r *gitRepositoryImpl) GetWorkspaceSummary(ctx context.Context) (*model.WorkspaceSummary, error) {
+	statusOut, _, err := r.runGitCommand(ctx, r.gitBin, false, "status", "--porcelain=v2", "--branch", "--untracked-files=normal")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get status: %w", err)
+	}
+	summary := parseWorkspaceStatus(statusOut)
+	summary.Root = r.path
+
+	// No HEAD commit in a new repository
+	date, _, err := r.runGitCommand(ctx, r.gitBin, false, "log", "-1", "--format=%ct")
+	if err != nil {
+		utils.Logger.Debug().Err(err).Msg("No previous commit")
+		return summary, nil
+	}
+	if seconds, err := strconv.ParseInt(strings.TrimSpace(date), 10, 64); err == nil {
+		summary.LastCommit = time.Unix(seconds, 0)
+	}
+	return summary, nil
+}
+
+// parseWorkspaceStatus counts the entries of git status --porcelain=v2 --branch and reads
+// its branch headers. Conflicted files count as modified.
+func parseWorkspaceStatus(statusOut string) *model.WorkspaceSummary {
+	var branch model.RepositoryState
+	parseBranchHeaders(statusOut, &branch)
+	summary := &model.WorkspaceSummary{
+		Branch:   branch.Branch,
+		Upstream: branch.Upstream,
+		Ahead:    branch.Ahead,
+		Behind:   branch.Behind,
+	}
+
+	for _, line := range strings.Split(statusOut, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		switch fields[0] {
+		case "1", "2":
+			// XY: index and worktree status, "." when unchanged
+			if xy := fields[1]; len(xy) == 2 {
+				if xy[0] != '.' {
+					summary.Staged++
+				}
+				if xy[1] != '.' {
+					summary.Modified++
+				}
+			}
+		case "u":
+			summary.Modified++
+		case "?":
+			summary.Untracked++
+		}
+	}
+	return summary
+}
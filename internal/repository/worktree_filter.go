@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/utils"
+)
+
+// stageablePaths drops the paths auto-staging must leave alone: paths outside the
+// sparse-checkout cone, which git add refuses, and untracked paths matched by a .gitignore
+// file, .git/info/exclude or core.excludesFile. git status hides ignored files already;
+// the check keeps staging right whatever produced the status output (e.g. rtk).
+// untracked holds the untracked paths among paths.
+func (r *gitRepositoryImpl) stageablePaths(ctx context.Context, paths []string, untracked map[string]bool) []string {
+	cone, sparse := r.sparseCone(ctx)
+
+	var candidates []string
+	for _, p := range paths {
+		if sparse && !inSparseCone(p, cone) {
+			utils.Logger.Debug().Str("path", p).Msg("Skipping path outside the sparse-checkout cone")
+			continue
+		}
+		candidates = append(candidates, p)
+	}
+
+	var untrackedCandidates []string
+	for _, p := range candidates {
+		if untracked[p] {
+			untrackedCandidates = append(untrackedCandidates, p)
+		}
+	}
+	ignored, err := r.ignoredPaths(ctx, untrackedCandidates)
+	if err != nil {
+		// git add skips ignored files on its own, only the file list is less accurate
+		utils.Logger.Debug().Err(err).Msg("Failed to check ignored paths")
+	}
+
+	kept := make([]string, 0, len(candidates))
+	for _, p := range candidates {
+		if ignored[p] {
+			utils.Logger.Debug().Str("path", p).Msg("Skipping ignored path")
+			continue
+		}
+		kept = append(kept, p)
+	}
+	return kept
+}
+
+// stageableChanges applies stageablePaths to worktree changes, untracked files being the
+// ones listed as added
+func (r *gitRepositoryImpl) stageableChanges(ctx context.Context, files []model.FileChange) []model.FileChange {
+	paths := make([]string, 0, len(files))
+	untracked := make(map[string]bool)
+	for _, file := range files {
+		paths = append(paths, file.Path)
+		if file.Status == "added" {
+			untracked[file.Path] = true
+		}
+	}
+
+	keep := make(map[string]bool)
+	for _, p := range r.stageablePaths(ctx, paths, untracked) {
+		keep[p] = true
+	}
+	kept := make([]model.FileChange, 0, len(files))
+	for _, file := range files {
+		if keep[file.Path] {
+			kept = append(kept, file)
+		}
+	}
+	return kept
+}
+
+// sparseCone returns the directories of the sparse-checkout cone; sparse is false when the
+// worktree is not sparse or uses non-cone patterns, which are not interpreted
+func (r *gitRepositoryImpl) sparseCone(ctx context.Context) (cone []string, sparse bool) {
+	for _, key := range []string{"core.sparseCheckout", "core.sparseCheckoutCone"} {
+		out, _, err := r.runGitCommand(ctx, r.gitBin, false, "config", "--type=bool", "--get", key)
+		if err != nil || strings.TrimSpace(out) != "true" {
+			return nil, false
+		}
+	}
+
+	out, _, err := r.runGitCommand(ctx, r.gitBin, false, "sparse-checkout", "list")
+	if err != nil {
+		utils.Logger.Debug().Err(err).Msg("Failed to read the sparse-checkout cone")
+		return nil, false
+	}
+	for _, line := range strings.Split(out, "\n") {
+		if dir := strings.Trim(strings.TrimSpace(line), "/"); dir != "" {
+			cone = append(cone, dir)
+		}
+	}
+	return cone, true
+}
+
+// inSparseCone reports whether a status path (a directory when it ends with "/") is checked
+// out by a cone: top-level files, everything under a cone directory, and the files directly
+// in the parent directories of a cone directory
+func inSparseCone(p string, cone []string) bool {
+	isDir := strings.HasSuffix(p, "/")
+	entry := strings.TrimSuffix(p, "/")
+	parent := path.Dir(entry)
+	if !isDir && parent == "." {
+		return true
+	}
+
+	for _, dir := range cone {
+		if entry == dir || strings.HasPrefix(entry, dir+"/") {
+			return true
+		}
+		if isDir && strings.HasPrefix(dir, entry+"/") {
+			return true
+		}
+		if !isDir && (parent == dir || strings.HasPrefix(dir, parent+"/")) {
+			return true
+		}
+	}
+	return false
+}
+
+// ignoredPaths returns the paths matched by an ignore rule. Tracked paths are never
+// reported, as ignore rules do not apply to them.
+func (r *gitRepositoryImpl) ignoredPaths(ctx context.Context, paths []string) (map[string]bool, error) {
+	if len(paths) == 0 {
+		return nil, nil
+	}
+	args := append([]string{"check-ignore", "--"}, paths...)
+	out, _, err := r.runGitCommand(ctx, r.gitBin, false, args...)
+	var failed *ErrGitCommandFailed
+	if errors.As(err, &failed) && failed.ExitCode == 1 {
+		// no path is ignored
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to check ignored paths: %w", err)
+	}
+
+	ignored := make(map[string]bool)
+	for _, line := range strings.Split(out, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			ignored[line] = true
+		}
+	}
+	return ignored, nil
+}
@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/golgoth31/gitcomm/pkg/testutil"
+)
+
+func TestInSparseCone(t *testing.T) {
+	cone := []string{"services/api", "docs"}
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{path: "go.mod", want: true},
+		{path: "services/api/main.go", want: true},
+		{path: "services/api/internal/handler.go", want: true},
+		{path: "services/README.md", want: true},
+		{path: "services/web/index.ts", want: false},
+		{path: "docs/guide.md", want: true},
+		{path: "docsite/index.md", want: false},
+		{path: "tools/gen.go", want: false},
+		{path: "services/", want: true},
+		{path: "services/web/", want: false},
+		{path: "services/api/new/", want: true},
+		{path: "tools/", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := inSparseCone(tt.path, cone); got != tt.want {
+				t.Errorf("inSparseCone(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStageAllFilesIncludingUntracked_RespectsIgnoreRules(t *testing.T) {
+	fixture := testutil.NewRepoWithCommit(t, map[string]string{
+		"main.go":          "package main\n",
+		"build/.gitignore": "*.o\n!keep.o\n",
+	})
+	globalExcludes := filepath.Join(t.TempDir(), "ignore")
+	if err := os.WriteFile(globalExcludes, []byte("*.swp\n"), 0600); err != nil {
+		t.Fatalf("failed to write global excludes: %v", err)
+	}
+	globalConfig := filepath.Join(t.TempDir(), "gitconfig")
+	if err := os.WriteFile(globalConfig, []byte("[core]\n\texcludesFile = "+globalExcludes+"\n"), 0600); err != nil {
+		t.Fatalf("failed to write global config: %v", err)
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", globalConfig)
+	if err := os.WriteFile(fixture.Path(".git/info/exclude"), []byte("local.env\n"), 0600); err != nil {
+		t.Fatalf("failed to write info/exclude: %v", err)
+	}
+
+	fixture.WriteFile("main.go", "package main\n\nfunc main() {}\n")
+	fixture.WriteFile("build/main.o", "binary\n")
+	fixture.WriteFile("build/keep.o", "binary\n")
+	fixture.WriteFile(".main.go.swp", "swap\n")
+	fixture.WriteFile("local.env", "TOKEN=secret\n")
+	fixture.WriteFile("notes.txt", "notes\n")
+
+	repo, err := NewGitRepository(fixture.Dir, false, true)
+	if err != nil {
+		t.Fatalf("NewGitRepository() error = %v", err)
+	}
+	impl := repo.(*gitRepositoryImpl)
+	ctx := context.Background()
+
+	// Ignored paths are dropped even when listed, tracked ones are kept
+	paths := []string{"main.go", "build/main.o", "build/keep.o", ".main.go.swp", "local.env", "notes.txt"}
+	untracked := map[string]bool{"build/main.o": true, "build/keep.o": true, ".main.go.swp": true, "local.env": true, "notes.txt": true}
+	want := []string{"main.go", "build/keep.o", "notes.txt"}
+	if got := impl.stageablePaths(ctx, paths, untracked); !slices.Equal(got, want) {
+		t.Errorf("stageablePaths() = %v, want %v", got, want)
+	}
+
+	result, err := repo.StageAllFilesIncludingUntracked(ctx)
+	if err != nil {
+		t.Fatalf("StageAllFilesIncludingUntracked() error = %v", err)
+	}
+	if !result.Success {
+		t.Fatalf("StageAllFilesIncludingUntracked() failed: %+v", result.FailedFiles)
+	}
+	if got := fixture.Git("diff", "--cached", "--name-only"); got != "build/keep.o\nmain.go\nnotes.txt\n" {
+		t.Errorf("staged = %q, want build/keep.o, main.go and notes.txt", got)
+	}
+}
+
+func TestStaging_RespectsSparseCheckoutCone(t *testing.T) {
+	fixture := testutil.NewRepoWithCommit(t, map[string]string{
+		"go.mod":               "module example\n",
+		"services/api/main.go": "package main\n",
+		"services/web/app.ts":  "export {}\n",
+	})
+	fixture.Git("sparse-checkout", "set", "--cone", "services/api")
+
+	fixture.WriteFile("go.mod", "module example\n\ngo 1.22\n")
+	fixture.WriteFile("services/api/main.go", "package main\n\nfunc main() {}\n")
+	fixture.WriteFile("services/api/health.go", "package main\n")
+	// Outside the cone: git add refuses these paths
+	fixture.WriteFile("services/web/app.ts", "export const app = 1\n")
+	fixture.WriteFile("tools/gen.go", "package tools\n")
+
+	repo, err := NewGitRepository(fixture.Dir, false, true)
+	if err != nil {
+		t.Fatalf("NewGitRepository() error = %v", err)
+	}
+	ctx := context.Background()
+
+	state, err := repo.GetRepositoryState(ctx)
+	if err != nil {
+		t.Fatalf("GetRepositoryState() error = %v", err)
+	}
+	var unstaged []string
+	for _, file := range state.UnstagedFiles {
+		unstaged = append(unstaged, file.Path)
+	}
+	slices.Sort(unstaged)
+	if want := []string{"go.mod", "services/api/health.go", "services/api/main.go"}; !slices.Equal(unstaged, want) {
+		t.Errorf("UnstagedFiles = %v, want %v", unstaged, want)
+	}
+
+	result, err := repo.StageAllFilesIncludingUntracked(ctx)
+	if err != nil {
+		t.Fatalf("StageAllFilesIncludingUntracked() error = %v", err)
+	}
+	if !result.Success {
+		t.Fatalf("StageAllFilesIncludingUntracked() failed: %+v", result.FailedFiles)
+	}
+	if got := fixture.Git("diff", "--cached", "--name-only"); got != "go.mod\nservices/api/health.go\nservices/api/main.go\n" {
+		t.Errorf("staged = %q, want the changes inside the cone", got)
+	}
+}
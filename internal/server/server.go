@@ -0,0 +1,312 @@
+// Package server exposes gitcomm to editor integrations through a small localhost
+// HTTP/JSON API: the repository state, message generation and commit creation run the
+// same workflows as the CLI, without prompts
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/golgoth31/gitcomm/internal/config"
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/repository"
+	"github.com/golgoth31/gitcomm/internal/service"
+	"github.com/golgoth31/gitcomm/internal/store"
+	"github.com/golgoth31/gitcomm/internal/telemetry"
+	"github.com/golgoth31/gitcomm/internal/utils"
+)
+
+// maxRequestSize bounds the JSON body of a request
+const maxRequestSize = 1 << 20
+
+// Server serves the API for one repository. Requests run one at a time, as they share
+// the index of the repository.
+type Server struct {
+	gitRepo    repository.GitRepository
+	config     atomic.Pointer[config.Config]
+	options    model.CommitOptions
+	token      string
+	prefetcher *service.StatePrefetcher
+	store      store.Store
+	mu         sync.Mutex
+}
+
+// NewServer creates a server for gitRepo. options are the base options of every workflow
+// (provider, account...); requests must carry token as a bearer token.
+func NewServer(gitRepo repository.GitRepository, cfg *config.Config, options model.CommitOptions, token string) *Server {
+	s := &Server{gitRepo: gitRepo, options: options, token: token}
+	s.config.Store(cfg)
+	return s
+}
+
+// SetConfig replaces the configuration of the next requests, when the config file is
+// reloaded (see config.Watcher); running requests keep the previous one
+func (s *Server) SetConfig(cfg *config.Config) {
+	s.config.Store(cfg)
+}
+
+// SetPrefetcher answers the state and message requests from the state prefetched by
+// prefetcher, which must be running (see service.StatePrefetcher.Run)
+func (s *Server) SetPrefetcher(prefetcher *service.StatePrefetcher) {
+	s.prefetcher = prefetcher
+}
+
+// SetStore records the generated commits and provider usage of the requests in st, while
+// history.enabled is set in the current configuration
+func (s *Server) SetStore(st store.Store) {
+	s.store = st
+}
+
+// File is a changed file of the repository state
+type File struct {
+	Path    string `json:"path"`
+	OldPath string `json:"old_path,omitempty"`
+	Status  string `json:"status"`
+	Diff    string `json:"diff,omitempty"`
+}
+
+// StateResponse is the answer of GET /v1/state
+type StateResponse struct {
+	Branch   string `json:"branch"`
+	Upstream string `json:"upstream,omitempty"`
+	Ahead    int    `json:"ahead"`
+	Behind   int    `json:"behind"`
+	Staged   []File `json:"staged"`
+	Unstaged []File `json:"unstaged"`
+}
+
+// MessageRequest is the body of POST /v1/message
+type MessageRequest struct {
+	// Provider overrides the AI provider of the server
+	Provider string `json:"provider,omitempty"`
+}
+
+// MessageResponse is the answer of POST /v1/message
+type MessageResponse struct {
+	Message string `json:"message"`
+}
+
+// CommitRequest is the body of POST /v1/commit
+type CommitRequest struct {
+	// Message is the commit message; without it, the message is generated by the AI
+	// provider as with `gitcomm --yes`
+	Message string `json:"message,omitempty"`
+
+	// All stages untracked files as well, like `gitcomm -a`
+	All bool `json:"all,omitempty"`
+
+	// Provider overrides the AI provider of the server
+	Provider string `json:"provider,omitempty"`
+}
+
+// CommitResponse is the answer of POST /v1/commit
+type CommitResponse struct {
+	Hash    string `json:"hash"`
+	Subject string `json:"subject"`
+}
+
+// ErrorResponse is the answer of a failed request
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// Handler returns the HTTP handler of the API
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/state", s.handleState)
+	mux.HandleFunc("POST /v1/message", s.handleMessage)
+	mux.HandleFunc("POST /v1/commit", s.handleCommit)
+	return s.guard(mux)
+}
+
+// guard rejects the requests without the bearer token, and the ones addressed to another
+// host than the loopback interface: a web page resolving its own domain to 127.0.0.1
+// (DNS rebinding) cannot reach the API
+func (s *Server) guard(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !isLoopbackHost(r.Host) {
+			writeJSON(w, http.StatusForbidden, ErrorResponse{Error: "the API only answers requests to localhost"})
+			return
+		}
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+			writeJSON(w, http.StatusUnauthorized, ErrorResponse{Error: "missing or invalid bearer token"})
+			return
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// isLoopbackHost reports whether the Host header names the loopback interface
+func isLoopbackHost(hostport string) bool {
+	host := hostport
+	if h, _, err := net.SplitHostPort(hostport); err == nil {
+		host = h
+	}
+	host = strings.Trim(host, "[]")
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+func (s *Server) handleState(w http.ResponseWriter, r *http.Request) {
+	options := s.options
+	state, err := s.commitService(&options, true).RepositoryState(r.Context())
+	if err != nil {
+		writeError(w, fmt.Errorf("failed to get repository state: %w", err))
+		return
+	}
+	diffs := r.URL.Query().Get("diffs") == "true"
+	writeJSON(w, http.StatusOK, StateResponse{
+		Branch:   state.Branch,
+		Upstream: state.Upstream,
+		Ahead:    state.Ahead,
+		Behind:   state.Behind,
+		Staged:   files(state.StagedFiles, diffs),
+		Unstaged: files(state.UnstagedFiles, diffs),
+	})
+}
+
+func (s *Server) handleMessage(w http.ResponseWriter, r *http.Request) {
+	var request MessageRequest
+	if !readJSON(w, r, &request) {
+		return
+	}
+	options := s.options
+	if request.Provider != "" {
+		options.AIProvider = request.Provider
+	}
+
+	ctx, span := telemetry.Start(r.Context(), "gitcomm serve message")
+	message, err := s.commitService(&options, true).GenerateMessage(ctx)
+	telemetry.End(span, err)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, MessageResponse{Message: message})
+}
+
+func (s *Server) handleCommit(w http.ResponseWriter, r *http.Request) {
+	var request CommitRequest
+	if !readJSON(w, r, &request) {
+		return
+	}
+	options := s.options
+	options.Message = request.Message
+	options.AutoStage = request.All
+	if request.Provider != "" {
+		options.AIProvider = request.Provider
+	}
+
+	ctx, span := telemetry.Start(r.Context(), "gitcomm serve commit")
+	before := s.head(ctx)
+	// The commit stages files first: its state is always computed
+	err := s.commitService(&options, false).CreateCommit(ctx)
+	telemetry.End(span, err)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	commit := s.headCommit(ctx)
+	if commit == nil || commit.Hash == before {
+		writeError(w, errors.New("no commit was created"))
+		return
+	}
+	writeJSON(w, http.StatusCreated, CommitResponse{Hash: commit.Hash, Subject: commit.Subject()})
+}
+
+// commitService creates the service running a request with options and the current
+// configuration, reading the prefetched state when prefetched is set
+func (s *Server) commitService(options *model.CommitOptions, prefetched bool) *service.CommitService {
+	cfg := s.config.Load()
+	commits := service.NewCommitService(s.gitRepo, options, cfg)
+	if prefetched && s.prefetcher != nil {
+		commits.SetPrefetcher(s.prefetcher)
+	}
+	if s.store != nil && cfg.History.Enabled {
+		commits.SetStore(s.store)
+	}
+	return commits
+}
+
+// head returns the hash of HEAD, "" in a repository without commits
+func (s *Server) head(ctx context.Context) string {
+	if commit := s.headCommit(ctx); commit != nil {
+		return commit.Hash
+	}
+	return ""
+}
+
+// headCommit returns the HEAD commit, nil in a repository without commits
+func (s *Server) headCommit(ctx context.Context) *model.CommitInfo {
+	commits, err := s.gitRepo.ListCommits(ctx, "HEAD", 1)
+	if err != nil || len(commits) == 0 {
+		return nil
+	}
+	return &commits[0]
+}
+
+// files converts file changes for the API, with their diffs when diffs is set
+func files(changes []model.FileChange, diffs bool) []File {
+	converted := make([]File, 0, len(changes))
+	for _, change := range changes {
+		file := File{Path: change.Path, OldPath: change.OldPath, Status: change.Status}
+		if diffs {
+			file.Diff = change.Diff
+		}
+		converted = append(converted, file)
+	}
+	return converted
+}
+
+// readJSON decodes the body of r into v; an empty body leaves v as is. It answers the
+// request and returns false when the body is invalid.
+func readJSON(w http.ResponseWriter, r *http.Request, v any) bool {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestSize))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil && !errors.Is(err, io.EOF) {
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: fmt.Sprintf("invalid request body: %v", err)})
+		return false
+	}
+	return true
+}
+
+// writeError answers with the status matching err
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, utils.ErrNoChanges):
+		status = http.StatusConflict
+	case errors.Is(err, utils.ErrInvalidFormat), errors.Is(err, utils.ErrEmptySubject):
+		status = http.StatusUnprocessableEntity
+	case errors.Is(err, utils.ErrAIProviderUnavailable), errors.Is(err, utils.ErrCircuitOpen):
+		status = http.StatusBadGateway
+	case errors.Is(err, utils.ErrBranchBehind), errors.Is(err, utils.ErrRepositoryDisabled):
+		status = http.StatusPreconditionFailed
+	}
+	utils.Logger.Debug().Err(err).Int("status", status).Msg("API request failed")
+	writeJSON(w, status, ErrorResponse{Error: err.Error()})
+}
+
+// writeJSON answers with v encoded as JSON
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		utils.Logger.Debug().Err(err).Msg("Failed to write API response")
+	}
+}
@@ -0,0 +1,302 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golgoth31/gitcomm/internal/config"
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/repository"
+	"github.com/golgoth31/gitcomm/internal/service"
+	"github.com/golgoth31/gitcomm/internal/store"
+	"github.com/golgoth31/gitcomm/internal/ui"
+	"github.com/golgoth31/gitcomm/pkg/testutil"
+)
+
+const testToken = "secret"
+
+// newTestServer serves the API for fixture, with a fake local AI provider
+func newTestServer(t *testing.T, fixture *testutil.Repo) *httptest.Server {
+	t.Helper()
+	provider := testutil.NewProviderServer(t, "local", "feat(api): add health endpoint")
+	cfg := &config.Config{}
+	cfg.AI.Providers = map[string]model.AIProviderConfig{"local": {Endpoint: provider.Endpoint()}}
+
+	gitRepo, err := repository.NewGitRepository(fixture.Dir, true, true)
+	if err != nil {
+		t.Fatalf("NewGitRepository() error = %v", err)
+	}
+	options := model.CommitOptions{AIProvider: "local", NoSignoff: true}
+	api := httptest.NewServer(NewServer(gitRepo, cfg, options, testToken).Handler())
+	t.Cleanup(api.Close)
+	return api
+}
+
+// call sends a request to the API and decodes its JSON answer into out
+func call(t *testing.T, api *httptest.Server, method, path, body string, out any) int {
+	t.Helper()
+	req, err := http.NewRequest(method, api.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("NewRequest() error = %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	resp, err := api.Client().Do(req)
+	if err != nil {
+		t.Fatalf("%s %s error = %v", method, path, err)
+	}
+	defer resp.Body.Close()
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			t.Fatalf("%s %s: invalid JSON answer: %v", method, path, err)
+		}
+	}
+	return resp.StatusCode
+}
+
+func TestGuard(t *testing.T) {
+	handler := NewServer(nil, &config.Config{}, model.CommitOptions{}, testToken).Handler()
+
+	tests := []struct {
+		name  string
+		host  string
+		token string
+		want  int
+	}{
+		{name: "no token", host: "127.0.0.1:7410", want: http.StatusUnauthorized},
+		{name: "wrong token", host: "127.0.0.1:7410", token: "guess", want: http.StatusUnauthorized},
+		{name: "foreign host", host: "attacker.example:7410", token: testToken, want: http.StatusForbidden},
+		{name: "localhost", host: "localhost:7410", token: testToken, want: http.StatusNotFound},
+		{name: "IPv6 loopback", host: "[::1]:7410", token: testToken, want: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/v1/unknown", nil)
+			req.Host = tt.host
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestState(t *testing.T) {
+	fixture := testutil.NewRepoWithCommit(t, map[string]string{"api.go": "package api\n"})
+	fixture.WriteFile("api.go", "package api\n\nfunc Health() {}\n")
+	fixture.Stage("api.go")
+	fixture.WriteFile("notes.txt", "todo\n")
+	api := newTestServer(t, fixture)
+
+	var state StateResponse
+	if status := call(t, api, http.MethodGet, "/v1/state?diffs=true", "", &state); status != http.StatusOK {
+		t.Fatalf("status = %d, want %d", status, http.StatusOK)
+	}
+	if len(state.Staged) != 1 || state.Staged[0].Path != "api.go" {
+		t.Fatalf("staged = %+v, want api.go", state.Staged)
+	}
+	if !strings.Contains(state.Staged[0].Diff, "+func Health() {}") {
+		t.Errorf("staged diff = %q, want the added function", state.Staged[0].Diff)
+	}
+
+	var withoutDiffs StateResponse
+	call(t, api, http.MethodGet, "/v1/state", "", &withoutDiffs)
+	if len(withoutDiffs.Staged) != 1 || withoutDiffs.Staged[0].Diff != "" {
+		t.Errorf("staged without ?diffs=true = %+v, want api.go without its diff", withoutDiffs.Staged)
+	}
+}
+
+func TestMessage(t *testing.T) {
+	ui.SetNonInteractive(true)
+	defer ui.SetNonInteractive(false)
+
+	fixture := testutil.NewRepoWithCommit(t, map[string]string{"api.go": "package api\n"})
+	api := newTestServer(t, fixture)
+
+	var failure ErrorResponse
+	if status := call(t, api, http.MethodPost, "/v1/message", "", &failure); status != http.StatusConflict {
+		t.Errorf("without staged changes: status = %d, want %d (%s)", status, http.StatusConflict, failure.Error)
+	}
+
+	fixture.WriteFile("api.go", "package api\n\nfunc Health() {}\n")
+	fixture.Stage("api.go")
+	var message MessageResponse
+	if status := call(t, api, http.MethodPost, "/v1/message", "{}", &message); status != http.StatusOK {
+		t.Fatalf("status = %d, want %d", status, http.StatusOK)
+	}
+	if message.Message != "feat(api): add health endpoint" {
+		t.Errorf("message = %q", message.Message)
+	}
+	if head := fixture.Git("rev-list", "--count", "HEAD"); head != "1\n" {
+		t.Errorf("commits = %q, want the initial one only", head)
+	}
+
+	if status := call(t, api, http.MethodPost, "/v1/message", `{"unknown": true}`, nil); status != http.StatusBadRequest {
+		t.Errorf("unknown field: status = %d, want %d", status, http.StatusBadRequest)
+	}
+}
+
+func TestCommit(t *testing.T) {
+	ui.SetNonInteractive(true)
+	defer ui.SetNonInteractive(false)
+
+	fixture := testutil.NewRepoWithCommit(t, map[string]string{"api.go": "package api\n"})
+	fixture.WriteFile("api.go", "package api\n\nfunc Health() {}\n")
+	fixture.Stage("api.go")
+	api := newTestServer(t, fixture)
+
+	var failure ErrorResponse
+	status := call(t, api, http.MethodPost, "/v1/commit", `{"message": "not conventional"}`, &failure)
+	if status != http.StatusUnprocessableEntity {
+		t.Errorf("invalid message: status = %d, want %d (%s)", status, http.StatusUnprocessableEntity, failure.Error)
+	}
+
+	var commit CommitResponse
+	status = call(t, api, http.MethodPost, "/v1/commit", `{"message": "fix(api): add health check"}`, &commit)
+	if status != http.StatusCreated {
+		t.Fatalf("status = %d, want %d", status, http.StatusCreated)
+	}
+	if commit.Hash != fixture.Head() || commit.Subject != "fix(api): add health check" {
+		t.Errorf("commit = %+v, want HEAD %s", commit, fixture.Head())
+	}
+
+	if status := call(t, api, http.MethodPost, "/v1/commit", `{"message": "fix: again"}`, &failure); status != http.StatusConflict {
+		t.Errorf("nothing to commit: status = %d, want %d (%s)", status, http.StatusConflict, failure.Error)
+	}
+}
+
+func TestSetConfig(t *testing.T) {
+	ui.SetNonInteractive(true)
+	defer ui.SetNonInteractive(false)
+
+	fixture := testutil.NewRepoWithCommit(t, map[string]string{"api.go": "package api\n"})
+	fixture.WriteFile("api.go", "package api\n\nfunc Health() {}\n")
+	fixture.Stage("api.go")
+	gitRepo, err := repository.NewGitRepository(fixture.Dir, true, true)
+	if err != nil {
+		t.Fatalf("NewGitRepository() error = %v", err)
+	}
+	srv := NewServer(gitRepo, &config.Config{}, model.CommitOptions{AIProvider: "local", NoSignoff: true}, testToken)
+	api := httptest.NewServer(srv.Handler())
+	t.Cleanup(api.Close)
+
+	if status := call(t, api, http.MethodPost, "/v1/message", "", nil); status == http.StatusOK {
+		t.Fatalf("without the provider configured: status = %d, want a failure", status)
+	}
+
+	provider := testutil.NewProviderServer(t, "local", "feat(api): add health endpoint")
+	reloaded := &config.Config{}
+	reloaded.AI.Providers = map[string]model.AIProviderConfig{"local": {Endpoint: provider.Endpoint()}}
+	srv.SetConfig(reloaded)
+
+	var message MessageResponse
+	if status := call(t, api, http.MethodPost, "/v1/message", "", &message); status != http.StatusOK {
+		t.Fatalf("after SetConfig: status = %d, want %d", status, http.StatusOK)
+	}
+	if message.Message != "feat(api): add health endpoint" {
+		t.Errorf("message = %q", message.Message)
+	}
+}
+
+// countingRepository counts the repository state computations
+type countingRepository struct {
+	repository.GitRepository
+	calls atomic.Int32
+}
+
+func (r *countingRepository) GetRepositoryState(ctx context.Context) (*model.RepositoryState, error) {
+	r.calls.Add(1)
+	return r.GitRepository.GetRepositoryState(ctx)
+}
+
+func TestPrefetchedState(t *testing.T) {
+	ui.SetNonInteractive(true)
+	defer ui.SetNonInteractive(false)
+
+	fixture := testutil.NewRepoWithCommit(t, map[string]string{"api.go": "package api\n"})
+	fixture.WriteFile("api.go", "package api\n\nfunc Health() {}\n")
+	fixture.Stage("api.go")
+	gitRepo, err := repository.NewGitRepository(fixture.Dir, true, true)
+	if err != nil {
+		t.Fatalf("NewGitRepository() error = %v", err)
+	}
+	counting := &countingRepository{GitRepository: gitRepo}
+	provider := testutil.NewProviderServer(t, "local", "feat(api): add health endpoint")
+	cfg := &config.Config{}
+	cfg.AI.Providers = map[string]model.AIProviderConfig{"local": {Endpoint: provider.Endpoint()}}
+	srv := NewServer(counting, cfg, model.CommitOptions{AIProvider: "local", NoSignoff: true}, testToken)
+	srv.SetPrefetcher(service.NewStatePrefetcher(counting, 0))
+	api := httptest.NewServer(srv.Handler())
+	t.Cleanup(api.Close)
+
+	var state StateResponse
+	if status := call(t, api, http.MethodGet, "/v1/state", "", &state); status != http.StatusOK {
+		t.Fatalf("state: status = %d, want %d", status, http.StatusOK)
+	}
+	var message MessageResponse
+	if status := call(t, api, http.MethodPost, "/v1/message", "", &message); status != http.StatusOK {
+		t.Fatalf("message: status = %d, want %d", status, http.StatusOK)
+	}
+	if calls := counting.calls.Load(); calls != 1 {
+		t.Errorf("state computed %d times, want once for both requests", calls)
+	}
+
+	// A change of the index is seen by the next request
+	fixture.WriteFile("notes.txt", "todo\n")
+	fixture.Stage("notes.txt")
+	call(t, api, http.MethodGet, "/v1/state", "", &state)
+	if len(state.Staged) != 2 {
+		t.Errorf("staged after git add = %+v, want api.go and notes.txt", state.Staged)
+	}
+}
+
+func TestSetStore(t *testing.T) {
+	ui.SetNonInteractive(true)
+	defer ui.SetNonInteractive(false)
+
+	fixture := testutil.NewRepoWithCommit(t, map[string]string{"api.go": "package api\n"})
+	fixture.WriteFile("api.go", "package api\n\nfunc Health() {}\n")
+	fixture.Stage("api.go")
+	gitRepo, err := repository.NewGitRepository(fixture.Dir, true, true)
+	if err != nil {
+		t.Fatalf("NewGitRepository() error = %v", err)
+	}
+	provider := testutil.NewProviderServer(t, "local", "feat(api): add health endpoint")
+	cfg := &config.Config{}
+	cfg.AI.Providers = map[string]model.AIProviderConfig{"local": {Endpoint: provider.Endpoint()}}
+	srv := NewServer(gitRepo, cfg, model.CommitOptions{AIProvider: "local", NoSignoff: true}, testToken)
+	history := store.NewMemory()
+	srv.SetStore(history)
+	api := httptest.NewServer(srv.Handler())
+	t.Cleanup(api.Close)
+
+	// Nothing is recorded until history.enabled is set
+	if status := call(t, api, http.MethodPost, "/v1/message", "", nil); status != http.StatusOK {
+		t.Fatalf("message: status = %d, want %d", status, http.StatusOK)
+	}
+	if totals, _ := history.UsageTotals(context.Background(), time.Time{}); len(totals) != 0 {
+		t.Errorf("usage recorded with history disabled: %+v", totals)
+	}
+
+	enabled := *cfg
+	enabled.History.Enabled = true
+	srv.SetConfig(&enabled)
+	var commit CommitResponse
+	if status := call(t, api, http.MethodPost, "/v1/commit", "", &commit); status != http.StatusCreated {
+		t.Fatalf("commit: status = %d, want %d", status, http.StatusCreated)
+	}
+	commits, _ := history.Commits(context.Background(), time.Time{})
+	if len(commits) != 1 || commits[0].Hash != commit.Hash {
+		t.Errorf("recorded commits = %+v, want %s", commits, commit.Hash)
+	}
+}
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"cmp"
+	"strings"
+
+	"github.com/golgoth31/gitcomm/internal/config"
+	"github.com/golgoth31/gitcomm/internal/model"
+)
+
+// withAITrailer returns a copy of message with a trailer naming the provider and model that
+// generated it (commit.ai_trailer). Messages written by hand, messages already carrying the
+// trailer and fixup commits are left unchanged.
+func (s *CommitService) withAITrailer(message *model.CommitMessage) *model.CommitMessage {
+	// fixup! messages are discarded when squashed
+	if s.config == nil || !s.config.Commit.AITrailer || s.assistedBy == "" || message.Fixup != nil {
+		return message
+	}
+	key := cmp.Or(s.config.Commit.AITrailerKey, config.DefaultAITrailerKey)
+	for _, trailer := range message.Trailers {
+		if strings.EqualFold(trailer.Key, key) {
+			return message
+		}
+	}
+
+	copied := *message
+	copied.AddTrailer(key, s.assistedBy)
+	return &copied
+}
+
+// aiAttribution returns the value of the AI trailer for the provider in use:
+// gitcomm/<provider>/<model>, or gitcomm/<provider> when the provider default model is used
+func (s *CommitService) aiAttribution() string {
+	attribution := "gitcomm/" + s.providerName()
+	if modelName := s.modelName(); modelName != "" {
+		return attribution + "/" + modelName
+	}
+	return attribution
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/golgoth31/gitcomm/internal/config"
+	"github.com/golgoth31/gitcomm/internal/model"
+)
+
+func TestWithAITrailer(t *testing.T) {
+	tests := []struct {
+		name       string
+		enabled    bool
+		key        string
+		assistedBy string
+		message    *model.CommitMessage
+		wantFooter string
+	}{
+		{
+			name:       "disabled",
+			assistedBy: "gitcomm/openai/gpt-4o",
+			message:    &model.CommitMessage{Type: "feat", Subject: "add login"},
+		},
+		{
+			name:       "AI message",
+			enabled:    true,
+			assistedBy: "gitcomm/openai/gpt-4o",
+			message:    &model.CommitMessage{Type: "feat", Subject: "add login", Footer: "Closes #3", Trailers: []model.Trailer{{Key: "Refs", Value: "#12"}}},
+			wantFooter: "Closes #3\nRefs: #12\nAssisted-by: gitcomm/openai/gpt-4o",
+		},
+		{
+			name:       "custom key",
+			enabled:    true,
+			key:        "Generated-by",
+			assistedBy: "gitcomm/ollama/llama3",
+			message:    &model.CommitMessage{Type: "fix", Subject: "handle nil config"},
+			wantFooter: "Generated-by: gitcomm/ollama/llama3",
+		},
+		{
+			name:    "written by hand",
+			enabled: true,
+			message: &model.CommitMessage{Type: "feat", Subject: "add login"},
+		},
+		{
+			name:       "trailer already present",
+			enabled:    true,
+			assistedBy: "gitcomm/openai/gpt-4o",
+			message:    &model.CommitMessage{Type: "feat", Subject: "add login", Trailers: []model.Trailer{{Key: "assisted-by", Value: "Copilot"}}},
+			wantFooter: "assisted-by: Copilot",
+		},
+		{
+			name:       "fixup",
+			enabled:    true,
+			assistedBy: "gitcomm/openai/gpt-4o",
+			message:    &model.CommitMessage{Fixup: &model.CommitInfo{Message: "fix: parser"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{Commit: config.CommitConfig{AITrailer: tt.enabled, AITrailerKey: tt.key}}
+			s := &CommitService{config: cfg, assistedBy: tt.assistedBy}
+			original := tt.message.FooterText()
+
+			got := s.withAITrailer(tt.message)
+			if got.FooterText() != tt.wantFooter {
+				t.Errorf("footer = %q, want %q", got.FooterText(), tt.wantFooter)
+			}
+			if tt.message.FooterText() != original {
+				t.Errorf("original message modified: %q", tt.message.FooterText())
+			}
+		})
+	}
+}
+
+func TestAIAttribution(t *testing.T) {
+	cfg := &config.Config{AI: config.AIConfig{
+		DefaultProvider: "anthropic",
+		Providers: map[string]model.AIProviderConfig{
+			"anthropic": {Model: "claude-3-opus"},
+			"openai":    {},
+		},
+	}}
+
+	s := &CommitService{config: cfg, options: &model.CommitOptions{}}
+	if got := s.aiAttribution(); got != "gitcomm/anthropic/claude-3-opus" {
+		t.Errorf("aiAttribution() = %q, want gitcomm/anthropic/claude-3-opus", got)
+	}
+	// The provider default model is not known before the request
+	s.options.AIProvider = "openai"
+	if got := s.aiAttribution(); got != "gitcomm/openai" {
+		t.Errorf("aiAttribution() = %q, want gitcomm/openai", got)
+	}
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/ui"
+	"github.com/golgoth31/gitcomm/internal/utils"
+)
+
+// amending returns true if the HEAD commit is replaced instead of creating a new one
+func (s *CommitService) amending() bool {
+	return s.options != nil && s.options.Amend
+}
+
+// prepareAmend checks that HEAD can be amended and returns its message, prefilled for
+// editing. Commits reachable from a remote-tracking branch are refused unless Force is set.
+func (s *CommitService) prepareAmend(ctx context.Context) (*ui.PrefilledCommitMessage, error) {
+	commits, err := s.gitRepo.ListCommits(ctx, "HEAD", 1)
+	if err != nil || len(commits) == 0 {
+		return nil, fmt.Errorf("nothing to amend: no commit on HEAD")
+	}
+	head := commits[0]
+
+	if !s.options.Force {
+		pushed, err := s.gitRepo.IsPushed(ctx, head.Hash)
+		if err != nil {
+			return nil, err
+		}
+		if pushed {
+			return nil, fmt.Errorf("%w: %s %s", utils.ErrCommitPublished, head.ShortHash(), head.Subject())
+		}
+	}
+
+	prefilled := s.parseAIMessageToPrefilled(head.Message)
+	return &prefilled, nil
+}
+
+// withAmendedChanges adds the changes of the HEAD commit to the staged changes of state,
+// so the message describes the whole amended commit
+func (s *CommitService) withAmendedChanges(ctx context.Context, state *model.RepositoryState) error {
+	previous, err := s.gitRepo.GetCommitState(s.withDiffLimits(ctx), "HEAD")
+	if err != nil {
+		return fmt.Errorf("failed to get the changes of the commit to amend: %w", err)
+	}
+	state.StagedFiles = mergeFileChanges(previous.StagedFiles, state.StagedFiles)
+	// The condensed rtk diff only covers the staged changes
+	state.RawDiff = ""
+	// The last commit is the one being replaced, not the one the message follows
+	state.LastCommitSubject = ""
+	return nil
+}
+
+// mergeFileChanges combines the changes of a commit with the changes staged on top of
+// it: diffs of files changed by both are concatenated, and the status is the one of the
+// combined change (a file added then deleted is dropped)
+func mergeFileChanges(previous, staged []model.FileChange) []model.FileChange {
+	stagedByPath := make(map[string]model.FileChange, len(staged))
+	for _, file := range staged {
+		stagedByPath[file.Path] = file
+	}
+
+	merged := make([]model.FileChange, 0, len(previous)+len(staged))
+	seen := make(map[string]bool, len(previous))
+	for _, file := range previous {
+		seen[file.Path] = true
+		next, ok := stagedByPath[file.Path]
+		if !ok {
+			merged = append(merged, file)
+			continue
+		}
+
+		combined := next
+		combined.Diff = file.Diff + next.Diff
+		combined.LinesAdded += file.LinesAdded
+		combined.LinesRemoved += file.LinesRemoved
+		switch {
+		case file.Status == "added" && next.Status == "deleted":
+			continue
+		case file.Status == "added":
+			combined.Status = "added"
+		case file.Status == "deleted" && next.Status == "added":
+			combined.Status = "modified"
+		}
+		merged = append(merged, combined)
+	}
+	for _, file := range staged {
+		if !seen[file.Path] {
+			merged = append(merged, file)
+		}
+	}
+	return merged
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/golgoth31/gitcomm/internal/config"
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/repository"
+	"github.com/golgoth31/gitcomm/internal/ui"
+	"github.com/golgoth31/gitcomm/internal/utils"
+	"github.com/golgoth31/gitcomm/pkg/testutil"
+)
+
+func TestMergeFileChanges(t *testing.T) {
+	previous := []model.FileChange{
+		{Path: "api.go", Status: "added", Diff: "+package api\n"},
+		{Path: "old.go", Status: "modified", Diff: "-a\n+b\n"},
+		{Path: "tmp.go", Status: "added", Diff: "+tmp\n"},
+		{Path: "moved.go", Status: "deleted", Diff: "-moved\n"},
+	}
+	staged := []model.FileChange{
+		{Path: "api.go", Status: "modified", Diff: "+func Health() {}\n"},
+		{Path: "tmp.go", Status: "deleted", Diff: "-tmp\n"},
+		{Path: "moved.go", Status: "added", Diff: "+moved back\n"},
+		{Path: "new.go", Status: "added", Diff: "+new\n"},
+	}
+
+	want := []model.FileChange{
+		{Path: "api.go", Status: "added", Diff: "+package api\n+func Health() {}\n"},
+		{Path: "old.go", Status: "modified", Diff: "-a\n+b\n"},
+		{Path: "moved.go", Status: "modified", Diff: "-moved\n+moved back\n"},
+		{Path: "new.go", Status: "added", Diff: "+new\n"},
+	}
+	if got := mergeFileChanges(previous, staged); !reflect.DeepEqual(got, want) {
+		t.Errorf("mergeFileChanges() = %+v, want %+v", got, want)
+	}
+}
+
+func TestCreateCommit_Amend(t *testing.T) {
+	var mu sync.Mutex
+	var prompts []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		prompts = append(prompts, string(body))
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"choices": [{"message": {"content": "feat(api): add the api and its notes"}}]}`))
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{}
+	cfg.AI.Providers = map[string]model.AIProviderConfig{"local": {Endpoint: server.URL}}
+	ui.SetNonInteractive(true)
+	defer ui.SetNonInteractive(false)
+
+	tests := []struct {
+		name      string
+		published bool
+		force     bool
+		wantErr   error
+	}{
+		{"local commit", false, false, nil},
+		{"published commit", true, false, utils.ErrCommitPublished},
+		{"published commit with force", true, true, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prompts = nil
+			fixture := initMessageRepo(t, true)
+			fixture.Stage("notes.txt")
+			fixture.Commit("feat(api): add the api")
+			if tt.published {
+				fixture.Git("update-ref", "refs/remotes/origin/main", "HEAD")
+			}
+			fixture.WriteFile("notes.txt", "health endpoint\n")
+			fixture.Stage("notes.txt")
+
+			gitRepo, err := repository.NewGitRepository(fixture.Dir, true, true)
+			if err != nil {
+				t.Fatalf("NewGitRepository() error = %v", err)
+			}
+			options := &model.CommitOptions{AIProvider: "local", Amend: true, Force: tt.force, NoSignoff: true}
+			err = NewCommitService(gitRepo, options, cfg).CreateCommit(context.Background())
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("CreateCommit() error = %v, want %v", err, tt.wantErr)
+				}
+				if got := fixture.Git("log", "--format=%s"); got != "feat(api): add the api\n" {
+					t.Errorf("history changed: %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CreateCommit() error = %v", err)
+			}
+
+			// HEAD is replaced, with both files, and keeps its author
+			if got := fixture.Git("log", "--format=%s|%an"); got != "feat(api): add the api and its notes|"+testutil.UserName+"\n" {
+				t.Errorf("history = %q, want the amended commit only", got)
+			}
+			if got := fixture.Git("show", "--format=", "--name-only", "HEAD"); got != "api.go\nnotes.txt\n" {
+				t.Errorf("amended commit files = %q, want api.go and notes.txt", got)
+			}
+			// The provider saw the changes of the amended commit too
+			if len(prompts) != 1 || !strings.Contains(prompts[0], "+package api") || !strings.Contains(prompts[0], "+health endpoint") {
+				t.Errorf("prompts = %q, want one with the diffs of api.go and notes.txt", prompts)
+			}
+			if strings.Contains(prompts[0], "Previous commit:") {
+				t.Errorf("prompt presents the amended commit as the previous one: %q", prompts[0])
+			}
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package service
+
+import (
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/ui"
+)
+
+// copyToClipboard writes to the system clipboard, replaced in tests
+var copyToClipboard = ui.CopyToClipboard
+
+// copyOnly reports whether the final message goes to the clipboard instead of a commit (--copy)
+func (s *CommitService) copyOnly() bool {
+	return s.options != nil && s.options.Copy
+}
+
+// copyMessage places the final message on the clipboard, footers included. The sign-off
+// trailer is left to the client the message is pasted into.
+func (s *CommitService) copyMessage(message *model.CommitMessage) error {
+	return copyToClipboard(s.formatter.Format(message))
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/golgoth31/gitcomm/internal/config"
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/repository"
+	"github.com/golgoth31/gitcomm/internal/ui"
+)
+
+func TestCreateCommit_Copy(t *testing.T) {
+	ui.SetNonInteractive(true)
+	defer ui.SetNonInteractive(false)
+
+	var copied []string
+	original := copyToClipboard
+	copyToClipboard = func(text string) error {
+		copied = append(copied, text)
+		return nil
+	}
+	defer func() { copyToClipboard = original }()
+
+	fixture := initMessageRepo(t, true)
+	gitRepo, err := repository.NewGitRepository(fixture.Dir, true, true)
+	if err != nil {
+		t.Fatalf("NewGitRepository() error = %v", err)
+	}
+
+	options := &model.CommitOptions{Message: "feat(api): add the api\n\nRefs: #12", Copy: true}
+	if err := NewCommitService(gitRepo, options, &config.Config{}).CreateCommit(context.Background()); err != nil {
+		t.Fatalf("CreateCommit() error = %v", err)
+	}
+
+	// The message is copied without the sign-off trailer
+	want := "feat(api): add the api\n\nRefs: #12"
+	if len(copied) != 1 || copied[0] != want {
+		t.Errorf("copied = %q, want [%q]", copied, want)
+	}
+	if _, err := fixture.TryGit("rev-parse", "HEAD"); err == nil {
+		t.Error("a commit was created with --copy")
+	}
+	if staged := fixture.Git("diff", "--cached", "--name-only"); !strings.Contains(staged, "api.go") {
+		t.Errorf("staged files = %q, want api.go left staged", staged)
+	}
+}
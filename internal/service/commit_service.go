@@ -2,6 +2,7 @@ package service
 
 import (
 	"bufio"
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
@@ -11,35 +12,61 @@ import (
 
 	"github.com/golgoth31/gitcomm/internal/ai"
 	"github.com/golgoth31/gitcomm/internal/config"
+	"github.com/golgoth31/gitcomm/internal/hooks"
+	"github.com/golgoth31/gitcomm/internal/mail"
 	"github.com/golgoth31/gitcomm/internal/model"
 	"github.com/golgoth31/gitcomm/internal/repository"
+	"github.com/golgoth31/gitcomm/internal/store"
+	"github.com/golgoth31/gitcomm/internal/telemetry"
+	"github.com/golgoth31/gitcomm/internal/timer"
 	"github.com/golgoth31/gitcomm/internal/ui"
 	"github.com/golgoth31/gitcomm/internal/utils"
-	"github.com/golgoth31/gitcomm/pkg/tokenization"
+	"github.com/golgoth31/gitcomm/pkg/ai/postprocess"
+	"github.com/golgoth31/gitcomm/pkg/ai/prompt"
+	"github.com/golgoth31/gitcomm/pkg/conventional"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // CommitService orchestrates the commit message creation workflow
 type CommitService struct {
-	gitRepo     repository.GitRepository
-	formatter   *FormattingService
-	validator   *ValidationService
-	reader      *bufio.Reader
-	options     *model.CommitOptions
-	config      *config.Config
-	restoreDone chan struct{} // Channel to signal restoration completion (optional)
+	gitRepo       repository.GitRepository
+	formatter     *FormattingService
+	validator     *ValidationService
+	reader        *bufio.Reader
+	options       *model.CommitOptions
+	config        *config.Config
+	restoreDone   chan struct{}       // Channel to signal restoration completion (optional)
+	scopes        []string            // Scope suggestions for the current commit (see scopeCandidates)
+	branch        string              // Current branch ("" when detached), pushed with the push option
+	ticket        string              // Ticket of the branch, written in headers with a {ticket} placeholder
+	createdBranch string              // Branch created by --new-branch, once switched to
+	layout        *model.HeaderLayout // Header layout of commit.header_format (nil: type(scope): subject)
+	staged        []model.FileChange  // Files of the commit, listed by dry runs
+	tracker       timer.Tracker       // Time tracker of the running timer reported in the footer
+	activeTimer   *timer.Timer        // Running timer, stopped or annotated after the commit
+	exchanges     *ai.ExchangeLog     // AI requests of the commit, saved with --save-exchange
+	assistedBy    string              // Provider and model of the AI message ("": written by hand), see withAITrailer
+	results       []string            // Results of the workflow for scripts (see Results)
+	prefetcher    *StatePrefetcher    // Source of the repository state when set (see SetPrefetcher)
+	store         store.Store         // History and usage store (nil: not recorded), see SetStore
 }
 
 // NewCommitService creates a new commit service
 func NewCommitService(gitRepo repository.GitRepository, options *model.CommitOptions, cfg *config.Config) *CommitService {
-	return &CommitService{
+	s := &CommitService{
 		gitRepo:     gitRepo,
 		formatter:   NewFormattingService(),
 		validator:   NewValidationService(),
-		reader:      bufio.NewReader(os.Stdin),
+		reader:      bufio.NewReader(ui.Stdin()),
 		options:     options,
 		config:      cfg,
+		layout:      headerLayout(cfg),
 		restoreDone: nil, // Will be set if needed
 	}
+	if options != nil && options.SaveExchangeDir != "" {
+		s.exchanges = &ai.ExchangeLog{}
+	}
+	return s
 }
 
 // SetRestoreDoneChannel sets the channel to signal restoration completion
@@ -47,9 +74,34 @@ func (s *CommitService) SetRestoreDoneChannel(ch chan struct{}) {
 	s.restoreDone = ch
 }
 
+// SetPrefetcher reads the repository state from prefetcher instead of computing it, for
+// the workflows that do not stage files first (GenerateMessage)
+func (s *CommitService) SetPrefetcher(prefetcher *StatePrefetcher) {
+	s.prefetcher = prefetcher
+}
+
 // CreateCommit orchestrates the complete commit creation workflow
 func (s *CommitService) CreateCommit(ctx context.Context) error {
 	utils.Logger.Debug().Msg("Starting commit creation workflow")
+	if s.options != nil && len(s.options.Pathspecs) > 0 {
+		ctx = context.WithValue(ctx, repository.PathspecKey, s.options.Pathspecs)
+	}
+	s.printWorkspaceSummary(ctx)
+	if err := s.checkUpstream(ctx); err != nil {
+		return err
+	}
+	if warning := s.gitRepo.SigningIdentityWarning(); warning != "" {
+		fmt.Fprintf(ui.Out(), "Warning: %s\n", warning)
+	}
+
+	// A dry run stages into a copy of the index, leaving the real one untouched
+	if s.dryRun() {
+		useRealIndex, err := s.gitRepo.UseTemporaryIndex(ctx)
+		if err != nil {
+			return err
+		}
+		defer useRealIndex()
+	}
 
 	// Capture pre-CLI staging state for restoration
 	preCLIState, err := s.gitRepo.CaptureStagingState(ctx)
@@ -57,6 +109,14 @@ func (s *CommitService) CreateCommit(ctx context.Context) error {
 		return fmt.Errorf("failed to capture staging state: %w", err)
 	}
 
+	// Amending starts from the HEAD commit message, and never rewrites published history
+	var amended *ui.PrefilledCommitMessage
+	if s.amending() {
+		if amended, err = s.prepareAmend(ctx); err != nil {
+			return err
+		}
+	}
+
 	// Set up deferred restoration on cancellation/error
 	// Use pointer so we can modify it and defer will see the updated value
 	restoreOnExit := true
@@ -86,8 +146,8 @@ func (s *CommitService) CreateCommit(ctx context.Context) error {
 				// Check if error is due to timeout
 				if errors.Is(err, context.DeadlineExceeded) {
 					utils.Logger.Debug().Err(err).Msg("Restoration timed out")
-					fmt.Printf("Warning: Restoration timed out. Repository may be in unexpected state.\n")
-					fmt.Printf("Please check git status and manually restore if needed.\n")
+					fmt.Fprintf(ui.Out(), "Warning: Restoration timed out. Repository may be in unexpected state.\n")
+					fmt.Fprintf(ui.Out(), "Please check git status and manually restore if needed.\n")
 				} else {
 					utils.Logger.Debug().Err(err).Msg("Failed to restore staging state in defer")
 				}
@@ -102,18 +162,30 @@ func (s *CommitService) CreateCommit(ctx context.Context) error {
 		}
 	}()
 
-	// Auto-stage modified files (always, before any prompts)
+	// Auto-stage modified files (always, before any prompts), unless the user picks them
 	utils.Logger.Debug().Msg("Auto-staging modified files")
 	var stagingResult *model.AutoStagingResult
+	interactiveStaging := s.options != nil && s.options.Interactive
 	useAllFiles := s.options != nil && s.options.AutoStage
 
-	if useAllFiles {
+	stageCtx, stageSpan := telemetry.Start(ctx, "stage files",
+		attribute.Bool("gitcomm.interactive", interactiveStaging),
+		attribute.Bool("gitcomm.add_all", useAllFiles))
+	if interactiveStaging {
+		// Untracked files picked by the user are part of the commit
+		stagingResult, err = s.stageInteractively(stageCtx)
+		useAllFiles = true
+	} else if useAllFiles {
 		// Stage all files including untracked when -a flag is used
-		stagingResult, err = s.gitRepo.StageAllFilesIncludingUntracked(ctx)
+		stagingResult, err = s.gitRepo.StageAllFilesIncludingUntracked(stageCtx)
 	} else {
 		// Stage only modified files
-		stagingResult, err = s.gitRepo.StageModifiedFiles(ctx)
+		stagingResult, err = s.gitRepo.StageModifiedFiles(stageCtx)
+	}
+	if stagingResult != nil {
+		stageSpan.SetAttributes(attribute.Int("gitcomm.staged_files", len(stagingResult.StagedFiles)))
 	}
+	telemetry.End(stageSpan, err)
 
 	if err != nil {
 		// Staging failed - restore state and exit
@@ -141,7 +213,7 @@ func (s *CommitService) CreateCommit(ctx context.Context) error {
 	ctx = context.WithValue(ctx, repository.IncludeNewFilesKey, useAllFiles)
 
 	// Get repository state after staging
-	state, err := s.gitRepo.GetRepositoryState(ctx)
+	state, err := s.RepositoryState(ctx)
 	if err != nil {
 		// Error getting state - restore and exit
 		if restoreErr := s.restoreStagingState(ctx, preCLIState); restoreErr != nil {
@@ -149,6 +221,32 @@ func (s *CommitService) CreateCommit(ctx context.Context) error {
 		}
 		return fmt.Errorf("failed to get repository state: %w", err)
 	}
+	// Generated files unstaged by the user leave the commit
+	unstaged, err := s.reviewGeneratedFiles(ctx, state)
+	if err != nil {
+		// User cancelled - restore state (defer will handle it)
+		return err
+	}
+	if unstaged {
+		if state, err = s.RepositoryState(ctx); err != nil {
+			return fmt.Errorf("failed to get repository state: %w", err)
+		}
+	}
+	if s.amending() {
+		if err := s.withAmendedChanges(ctx, state); err != nil {
+			return err
+		}
+	}
+
+	s.scopes = s.scopeCandidates(ctx, state)
+	s.branch = state.Branch
+	s.ticket = state.Ticket
+	s.staged = state.StagedFiles
+
+	// Nothing matches the pathspecs: there is no empty commit limited to some paths
+	if state.IsEmpty() && s.options != nil && len(s.options.Pathspecs) > 0 {
+		return utils.ErrNoChanges
+	}
 
 	// Handle empty repository state
 	if state.IsEmpty() {
@@ -163,24 +261,44 @@ func (s *CommitService) CreateCommit(ctx context.Context) error {
 		}
 	}
 
+	// A fixup commit derives its message from the target commit: no AI or manual input
+	var message *model.CommitMessage
+	if s.options != nil && s.options.Fixup != "" {
+		target, err := s.resolveFixupTarget(ctx, s.options.Fixup)
+		if err != nil {
+			return err
+		}
+		message = &model.CommitMessage{Fixup: target}
+	}
+
+	// A message supplied on the command line replaces AI and manual input
+	if message == nil && s.options != nil && s.options.Message != "" {
+		if message, err = s.parseAIMessage(s.options.Message); err != nil {
+			return fmt.Errorf("%w: %v", utils.ErrInvalidFormat, err)
+		}
+	}
+
 	// Determine if AI should be used
 	useAI := false
-	if s.options == nil || !s.options.SkipAI {
-		// Calculate token count
-		tokenCalc := tokenization.NewTokenCalculator("openai") // Default provider for calculation
-		tokenCount, err := tokenCalc.CalculateForRepositoryState(state)
+	if message == nil && (s.options == nil || !s.options.SkipAI) {
+		// Pack the state as the prompt will be, to report the files left without diff.
+		// Nothing leaves the machine before the user agrees: the count is a local estimate.
+		shared, err := s.sharedState(ctx, state)
 		if err != nil {
-			utils.Logger.Debug().Err(err).Msg("Failed to calculate tokens")
+			return err
 		}
+		plan := prompt.PlanContext(s.promptLayout(), shared)
 		// Prompt for AI usage
-		useAI, err = ui.PromptAIUsage(s.reader, tokenCount)
+		useAI, err = ui.PromptAIUsage(s.reader, plan)
 		if err != nil {
 			// User cancelled - restore state (defer will handle it)
 			return fmt.Errorf("failed to prompt for AI usage: %w", err)
 		}
+		if useAI && s.countTokens(ctx, shared, &plan) {
+			fmt.Fprintln(ui.Out(), plan.Estimate())
+		}
 	}
 
-	var message *model.CommitMessage
 	if useAI {
 		// Try AI generation
 		message, err = s.generateWithAI(ctx, state)
@@ -191,45 +309,82 @@ func (s *CommitService) CreateCommit(ctx context.Context) error {
 				restoreOnExit = false
 				return nil
 			}
+			// Without prompts there is no manual input to fall back to
+			if ui.NonInteractive() {
+				return fmt.Errorf("AI generation failed and manual input is not possible in non-interactive mode: %w", err)
+			}
 			utils.Logger.Debug().Err(err).Msg("AI generation failed, falling back to manual input")
-			fmt.Printf("Error: %v\n", err)
-			fmt.Println("Falling back to manual input...")
+			ui.PrintError("AI generation failed", err)
+
+			// Provider unreachable: offer to commit with a placeholder message reworded later
+			if errors.Is(err, utils.ErrAIProviderUnavailable) && s.canQueue() {
+				queued, err := s.queueCommit(ctx)
+				if err != nil {
+					return err
+				}
+				if queued {
+					restoreOnExit = false
+					return nil
+				}
+			}
+			fmt.Fprintln(ui.Out(), "Falling back to manual input...")
 			// Fall through to manual input
 			useAI = false
 		}
 	}
 
-	if !useAI {
-		// Prompt for commit message components manually
-		message, err = s.promptCommitMessage(nil)
+	if !useAI && message == nil {
+		// Prompt for commit message components manually (prefilled with the amended message)
+		message, err = s.promptCommitMessage(amended)
 		if err != nil {
 			// User cancelled - restore state (defer will handle it)
 			return fmt.Errorf("failed to prompt for commit message: %w", err)
 		}
 	}
 
-	// Validate message
-	valid, errors := s.validator.Validate(message)
-	if !valid {
-		fmt.Println("\nValidation errors:")
-		for _, e := range errors {
-			fmt.Printf("  - %s: %s\n", e.Field, e.Message)
+	// Offer to turn WIP/follow-up messages into a fixup of a recent commit
+	if message.Fixup == nil && !s.amending() {
+		if err := s.suggestFixup(ctx, message); err != nil {
+			// User cancelled - restore state (defer will handle it)
+			return err
 		}
-		confirm, err := ui.PromptConfirm(s.reader, "Continue anyway?", false)
-		if err != nil || !confirm {
-			// User declined - restore state (defer will handle it)
-			return utils.ErrInvalidFormat
+	}
+
+	// Validate message (fixup messages are not Conventional Commits)
+	if message.Fixup == nil {
+		valid, errors := s.validator.Validate(message)
+		if !valid {
+			printValidationErrors(message, errors)
+			confirm, err := ui.PromptConfirm(s.reader, "Continue anyway?", false)
+			if err != nil || !confirm {
+				// User declined - restore state (defer will handle it)
+				return utils.ErrInvalidFormat
+			}
 		}
 	}
 
-	// Display formatted message for review
+	// Warn about footer references to missing or closed issues
+	if err := s.verifyIssueReferences(ctx, message); err != nil {
+		// User cancelled - restore state (defer will handle it)
+		return err
+	}
+
+	// Display formatted message for review, as it will be committed
+	message = s.withFooters(ctx, message)
 	formatted := ui.DisplayCommitMessage(message)
-	fmt.Println("\n--- Commit Message ---")
-	fmt.Println(formatted)
-	fmt.Println("---")
+	if target := ui.FormatCommitTarget(state, s.targetBranch()); target != "" {
+		formatted += "\n\n" + target
+	}
+	if err := ui.PrintPaged("\n--- Commit Message ---\n" + formatted + "\n---"); err != nil {
+		utils.Logger.Debug().Err(err).Msg("Failed to display commit message preview")
+	}
 
 	// Confirm before committing
-	confirm, err := ui.PromptConfirm(s.reader, "Create commit with this message?", true)
+	question := "Create commit with this message?"
+	if s.copyOnly() {
+		question = "Copy this message to the clipboard?"
+	}
+	confirm, err := ui.PromptConfirm(s.reader, question, true)
 	if err != nil {
 		// User cancelled - restore state (defer will handle it)
 		return fmt.Errorf("failed to prompt for confirmation: %w", err)
@@ -240,14 +395,10 @@ func (s *CommitService) CreateCommit(ctx context.Context) error {
 	}
 
 	// Set signoff based on options
-	if s.options != nil {
-		message.Signoff = !s.options.NoSignoff
-	} else {
-		message.Signoff = true // Default to signoff
-	}
+	s.applySignoff(message)
 
 	// Create commit
-	if err := s.gitRepo.CreateCommit(ctx, message); err != nil {
+	if err := s.createCommit(ctx, message); err != nil {
 		// Commit failed - restore state (defer will handle it)
 		return fmt.Errorf("failed to create commit: %w", err)
 	}
@@ -256,7 +407,7 @@ func (s *CommitService) CreateCommit(ctx context.Context) error {
 	// Disable restoration since commit succeeded (defer captures by value, so we need to set before return)
 	restoreOnExit = false
 	utils.Logger.Debug().Msg("Commit created successfully")
-	fmt.Println("✓ Commit created successfully")
+	s.printCommitCreated()
 	return nil
 }
 
@@ -306,13 +457,13 @@ func (s *CommitService) restoreStagingState(ctx context.Context, preCLIState *mo
 		// Check if error is due to timeout
 		if errors.Is(err, context.DeadlineExceeded) {
 			utils.Logger.Debug().Err(err).Msg("Restoration timed out")
-			fmt.Printf("Warning: Restoration timed out. Repository may be in unexpected state.\n")
-			fmt.Printf("Please check git status and manually restore if needed.\n")
+			fmt.Fprintf(ui.Out(), "Warning: Restoration timed out. Repository may be in unexpected state.\n")
+			fmt.Fprintf(ui.Out(), "Please check git status and manually restore if needed.\n")
 			return fmt.Errorf("%w: %v", utils.ErrRestorationFailed, err)
 		}
 		utils.Logger.Debug().Err(err).Msg("Failed to restore staging state")
-		fmt.Printf("Warning: failed to restore staging state. Repository may be in unexpected state.\n")
-		fmt.Printf("Please check git status and manually restore if needed.\n")
+		fmt.Fprintf(ui.Out(), "Warning: failed to restore staging state. Repository may be in unexpected state.\n")
+		fmt.Fprintf(ui.Out(), "Please check git status and manually restore if needed.\n")
 		return fmt.Errorf("%w: %v", utils.ErrRestorationFailed, err)
 	}
 
@@ -324,29 +475,52 @@ func (s *CommitService) restoreStagingState(ctx context.Context, preCLIState *mo
 // If prefilled is not nil, the fields will be pre-filled with values from prefilled
 func (s *CommitService) promptCommitMessage(prefilled *ui.PrefilledCommitMessage) (*model.CommitMessage, error) {
 	message := &model.CommitMessage{}
+	// Without an AI message to start from, the message is written by hand
+	if prefilled == nil {
+		s.assistedBy = ""
+	}
 
 	// Prompt for type
 	defaultType := ""
 	if prefilled != nil && prefilled.Type != "" {
 		defaultType = prefilled.Type
 	}
-	commitType, err := ui.PromptCommitTypeWithPreselection(s.reader, defaultType)
-	if err != nil {
-		return nil, fmt.Errorf("failed to prompt for type: %w", err)
+	if prefilled != nil && prefilled.TypeConfirmed && defaultType != "" {
+		// Type already confirmed by local inference - collapse the selection step
+		ui.PrintConfirmedType(defaultType)
+		message.Type = defaultType
+	} else {
+		commitType, err := ui.PromptCommitTypeWithPreselection(s.reader, defaultType)
+		if err != nil {
+			return nil, fmt.Errorf("failed to prompt for type: %w", err)
+		}
+		message.Type = commitType
 	}
-	message.Type = commitType
 
 	// Prompt for scope
 	defaultScope := ""
 	if prefilled != nil {
 		defaultScope = prefilled.Scope
 	}
-	scope, err := ui.PromptScopeWithDefault(s.reader, defaultScope)
+	scope, err := ui.PromptScopeWithSuggestions(s.reader, defaultScope, s.scopes)
 	if err != nil {
 		return nil, fmt.Errorf("failed to prompt for scope: %w", err)
 	}
 	message.Scope = scope
 
+	// Prompt for the ticket written in the header by the layout (default: the branch ticket)
+	if s.layout.HasTicket() {
+		defaultTicket := s.ticket
+		if prefilled != nil && prefilled.Ticket != "" {
+			defaultTicket = prefilled.Ticket
+		}
+		ticket, err := ui.PromptTicketWithDefault(s.reader, defaultTicket)
+		if err != nil {
+			return nil, fmt.Errorf("failed to prompt for ticket: %w", err)
+		}
+		message.Ticket = ticket
+	}
+
 	// Prompt for subject (required, with validation)
 	defaultSubject := ""
 	if prefilled != nil && prefilled.Subject != "" {
@@ -381,57 +555,208 @@ func (s *CommitService) promptCommitMessage(prefilled *ui.PrefilledCommitMessage
 	if err != nil {
 		return nil, fmt.Errorf("failed to prompt for footer: %w", err)
 	}
-	message.Footer = footer
+	message.SetFooter(footer)
+	if prefilled != nil {
+		message.Breaking = prefilled.Breaking
+	}
 
-	return message, nil
+	return s.withLayout(message), nil
 }
 
 // generateWithAI generates a commit message using AI
 // This is the public entry point that calls the internal implementation with retry limit
 func (s *CommitService) generateWithAI(ctx context.Context, repoState *model.RepositoryState) (*model.CommitMessage, error) {
-	return s.generateWithAIWithRetry(ctx, repoState, 0)
+	s.addSessionContext(ctx, repoState)
+	return s.generateWithAIWithRetry(ctx, repoState, 0, nil)
 }
 
-// generateWithAIWithRetry generates a commit message using AI with retry limit tracking
-func (s *CommitService) generateWithAIWithRetry(ctx context.Context, repoState *model.RepositoryState, retryCount int) (*model.CommitMessage, error) {
-	// Prevent infinite recursion
-	const maxRetries = 3
-	if retryCount >= maxRetries {
-		fmt.Println("Maximum retry limit reached. Falling back to manual input...")
-		return s.promptCommitMessage(nil)
+// addSessionContext sets the author's previous commit on the staged files in repoState
+// when session context is enabled, so the AI can phrase the message as a continuation
+func (s *CommitService) addSessionContext(ctx context.Context, repoState *model.RepositoryState) {
+	enabled := s.options != nil && s.options.SessionContext
+	window := config.DefaultSessionWindow
+	if s.config != nil {
+		enabled = enabled || s.config.AI.SessionContext
+		if s.config.AI.SessionWindow > 0 {
+			window = s.config.AI.SessionWindow
+		}
 	}
-	// Get provider configuration
-	providerName := "openai"
-	if s.options != nil && s.options.AIProvider != "" {
-		providerName = s.options.AIProvider
-	} else if s.config != nil && s.config.AI.DefaultProvider != "" {
-		providerName = s.config.AI.DefaultProvider
+	if !enabled || repoState.RelatedCommit != nil {
+		return
 	}
 
-	providerConfig, err := s.config.GetProviderConfig(providerName)
+	paths := make([]string, 0, len(repoState.StagedFiles))
+	for _, file := range repoState.StagedFiles {
+		paths = append(paths, file.Path)
+	}
+
+	commit, err := s.gitRepo.LastAuthorCommit(ctx, paths, window)
+	if err != nil {
+		utils.Logger.Debug().Err(err).Msg("Failed to find previous commit for session context")
+		return
+	}
+	repoState.RelatedCommit = commit
+}
+
+// newAIProvider creates the AI provider selected by options or configuration (default: openai)
+func (s *CommitService) newAIProvider() (ai.AIProvider, error) {
+	provider, _, err := s.newConfiguredAIProvider()
+	return provider, err
+}
+
+// newConfiguredAIProvider implements newAIProvider, also returning the configuration of
+// the provider
+func (s *CommitService) newConfiguredAIProvider() (ai.AIProvider, *model.AIProviderConfig, error) {
+	// Get provider configuration
+	providerName := s.providerName()
+	providerConfig, err := s.config.GetAccountProviderConfig(providerName, s.accountName())
 	if err != nil {
-		return nil, fmt.Errorf("%w: %v", utils.ErrAIProviderUnavailable, err)
+		return nil, nil, fmt.Errorf("%w: %v", utils.ErrAIProviderUnavailable, err)
 	}
 
-	// Create AI provider
-	var aiProvider ai.AIProvider
+	var provider ai.AIProvider
 	switch providerName {
 	case "openai":
-		aiProvider = ai.NewOpenAIProvider(providerConfig)
+		provider = ai.NewOpenAIProvider(providerConfig)
 	case "anthropic":
-		aiProvider = ai.NewAnthropicProvider(providerConfig)
+		provider = ai.NewAnthropicProvider(providerConfig)
 	case "mistral":
-		aiProvider = ai.NewMistralProvider(providerConfig)
+		provider = ai.NewMistralProvider(providerConfig)
+	case "ollama":
+		provider = ai.NewOllamaProvider(providerConfig)
 	case "local":
-		aiProvider = ai.NewLocalProvider(providerConfig)
+		provider = ai.NewLocalProvider(providerConfig)
 	default:
-		return nil, fmt.Errorf("%w: unknown provider %s", utils.ErrAIProviderUnavailable, providerName)
+		return nil, nil, fmt.Errorf("%w: unknown provider %s", utils.ErrAIProviderUnavailable, providerName)
+	}
+	// The breaker comes first: an open circuit fails without waiting for the limits, and a
+	// request failing after all its retries counts as one failure. Each attempt waits for
+	// the limits.
+	return ai.WithCircuitBreaker(ai.WithRetry(ai.WithLimits(provider, providerConfig), providerConfig), providerConfig), providerConfig, nil
+}
+
+// tokenCountTimeout bounds the token count request made before the AI usage prompt
+const tokenCountTimeout = 5 * time.Second
+
+// countTokens replaces the estimated tokens of plan with the exact count of the provider,
+// when it counts tokens itself and an API key is configured (Anthropic), and reports
+// whether it did. repoState is sent to the provider: only call it once the user agreed to
+// share it. The request goes through the limits and circuit breaker of the provider.
+// Without prompt nobody reads the count, so no request is made. A failed count keeps the
+// estimate.
+func (s *CommitService) countTokens(ctx context.Context, repoState *model.RepositoryState, plan *model.ContextPlan) bool {
+	if ui.NonInteractive() {
+		return false
+	}
+	provider, providerConfig, err := s.newConfiguredAIProvider()
+	if err != nil || providerConfig.APIKey == "" || !ai.CountsTokens(provider) {
+		return false
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, tokenCountTimeout)
+	defer cancel()
+	tokens, err := provider.(ai.TokenCounter).CountTokens(ctx, repoState)
+	if err != nil {
+		utils.Logger.Debug().Err(err).Msg("Failed to count tokens, keeping the estimate")
+		return false
+	}
+	plan.Tokens = tokens
+	plan.Exact = true
+	return true
+}
+
+// accountName returns the provider account selected by options ("": see config.SelectAccount)
+func (s *CommitService) accountName() string {
+	if s.options == nil {
+		return ""
+	}
+	return s.options.Account
+}
+
+// providerName returns the AI provider selected by options or configuration (default: openai)
+func (s *CommitService) providerName() string {
+	if s.options != nil && s.options.AIProvider != "" {
+		return s.options.AIProvider
+	}
+	if s.config != nil && s.config.AI.DefaultProvider != "" {
+		return s.config.AI.DefaultProvider
+	}
+	return "openai"
+}
+
+// RepositoryState collects the repository state, with the diffs of the staged files read
+// with the diff limits of the selected provider
+func (s *CommitService) RepositoryState(ctx context.Context) (*model.RepositoryState, error) {
+	ctx, span := telemetry.Start(ctx, "collect repository state")
+	var state *model.RepositoryState
+	var err error
+	if s.prefetcher != nil {
+		state, err = s.prefetcher.State(s.withDiffLimits(ctx))
+	} else {
+		state, err = s.gitRepo.GetRepositoryState(s.withDiffLimits(ctx))
+	}
+	if state != nil {
+		// The commit lands on the --branch target when set
+		branch := state.Branch
+		if target := s.targetBranch(); target != "" {
+			branch = target
+		}
+		state.Ticket = s.branchTicket(branch)
+		span.SetAttributes(
+			attribute.Int("gitcomm.staged_files", len(state.StagedFiles)),
+			attribute.Int("gitcomm.unstaged_files", len(state.UnstagedFiles)))
+	}
+	telemetry.End(span, err)
+	return state, err
+}
+
+// requestAIMessage asks the AI provider for a message for repoState, then post-processes
+// and parses it. Unparseable answers are used as the subject of a feat commit.
+// The post-processed answer is returned along with the parsed message.
+func (s *CommitService) requestAIMessage(ctx context.Context, repoState *model.RepositoryState) (*model.CommitMessage, string, error) {
+	aiProvider, err := s.newAIProvider()
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Only send what the privacy level allows
+	shared, err := s.sharedState(ctx, repoState)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if err := s.runHook(ctx, hooks.Payload{Event: hooks.PreGenerate, State: hookState(repoState)}); err != nil {
+		return nil, "", err
 	}
 
 	// Generate commit message
-	aiMessage, err := aiProvider.GenerateCommitMessage(ctx, repoState)
+	generateCtx, span := telemetry.Start(ctx, "generate message",
+		attribute.String("gitcomm.ai.provider", s.providerName()),
+		attribute.String("gitcomm.ai.privacy", string(cmp.Or(shared.Privacy, model.PrivacyFullDiff))))
+	usage := s.usageLog()
+	counted := 0
+	if usage != nil {
+		generateCtx = ai.WithExchangeLog(generateCtx, usage)
+		counted = len(usage.Exchanges())
+	}
+	// On huge changes, the provider may first pick the files it needs the diffs of
+	shared = s.selectDiffs(generateCtx, aiProvider, shared)
+	// Streaming providers (ollama) show the answer as it is generated
+	report, done := ui.StreamProgress("Generating with " + s.providerName() + ":")
+	aiMessage, err := aiProvider.GenerateCommitMessage(ai.WithProgress(generateCtx, report), shared)
+	done()
+	telemetry.End(span, err)
+	if usage != nil {
+		s.recordUsage(ctx, usage.Exchanges()[counted:])
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %v", utils.ErrAIProviderUnavailable, err)
+	}
+
+	// Apply configured post-processors to the raw provider output
+	aiMessage, err = s.postProcessAIMessage(aiMessage)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %v", utils.ErrAIProviderUnavailable, err)
+		return nil, "", err
 	}
 
 	// Parse AI message into CommitMessage structure
@@ -439,10 +764,32 @@ func (s *CommitService) generateWithAIWithRetry(ctx context.Context, repoState *
 	if err != nil {
 		utils.Logger.Debug().Err(err).Msg("Failed to parse AI message")
 		// Try to use as-is
-		message = &model.CommitMessage{
+		message = s.withLayout(&model.CommitMessage{
 			Type:    "feat",
 			Subject: strings.TrimSpace(aiMessage),
-		}
+		})
+	}
+
+	if err := s.runHook(ctx, hooks.Payload{Event: hooks.PostGenerate, State: hookState(repoState), Message: s.formatter.Format(message)}); err != nil {
+		return nil, "", err
+	}
+	s.assistedBy = s.aiAttribution()
+
+	return message, aiMessage, nil
+}
+
+// generateWithAIWithRetry generates a commit message using AI with retry limit tracking.
+// previous is the rejected message this one regenerates, nil for the first one.
+func (s *CommitService) generateWithAIWithRetry(ctx context.Context, repoState *model.RepositoryState, retryCount int, previous *model.CommitMessage) (*model.CommitMessage, error) {
+	// Prevent infinite recursion
+	const maxRetries = 3
+	if retryCount >= maxRetries {
+		fmt.Fprintln(ui.Out(), "Maximum retry limit reached. Falling back to manual input...")
+		return s.promptCommitMessage(nil)
+	}
+	message, aiMessage, err := s.requestAIMessage(ctx, repoState)
+	if err != nil {
+		return nil, err
 	}
 
 	// Validate AI-generated message
@@ -454,6 +801,10 @@ func (s *CommitService) generateWithAIWithRetry(ctx context.Context, repoState *
 			errorMessages = append(errorMessages, fmt.Sprintf("%s: %s", ve.Field, ve.Message))
 		}
 
+		if ui.NonInteractive() {
+			return nil, fmt.Errorf("%w: %s", utils.ErrInvalidFormat, strings.Join(errorMessages, "; "))
+		}
+
 		// Prompt user to edit or use with warning
 		edit, err := ui.PromptAIMessageEdit(s.reader, errorMessages)
 		if err != nil {
@@ -465,10 +816,20 @@ func (s *CommitService) generateWithAIWithRetry(ctx context.Context, repoState *
 			return s.promptCommitMessage(nil)
 		}
 		// User wants to use as-is with warning
-		fmt.Println("Warning: Using message that does not fully conform to Conventional Commits format")
+		fmt.Fprintln(ui.Out(), "Warning: Using message that does not fully conform to Conventional Commits format")
 	}
 
-	// Show AI message and get user acceptance with three options
+	// Without prompts the message is accepted, then reviewed like a manual one
+	if ui.NonInteractive() {
+		return message, nil
+	}
+
+	// Show what changed since the rejected message, then the AI message with four options,
+	// with the footers it will be committed with
+	message = s.withFooters(ctx, message)
+	if previous != nil {
+		ui.PrintMessageChanges(s.formatter.Format(previous), s.formatter.Format(message))
+	}
 	acceptance, err := ui.PromptAIMessageAcceptanceOptions(s.reader, ui.DisplayCommitMessage(message))
 	if err != nil {
 		return nil, fmt.Errorf("failed to prompt for acceptance: %w", err)
@@ -478,26 +839,23 @@ func (s *CommitService) generateWithAIWithRetry(ctx context.Context, repoState *
 	case ui.AcceptAndCommit:
 		// User wants to commit immediately - create commit here
 		// Set signoff based on options
-		if s.options != nil {
-			message.Signoff = !s.options.NoSignoff
-		} else {
-			message.Signoff = true // Default to signoff
-		}
+		s.applySignoff(message)
 
 		// Create commit immediately
-		if err := s.gitRepo.CreateCommit(ctx, message); err != nil {
+		if err := s.createCommit(ctx, message); err != nil {
 			// Commit failed - handle failure with retry/edit/cancel options
 			return s.handleCommitFailure(ctx, message, err)
 		}
 
 		// Commit succeeded - return sentinel error to signal commit was already created
 		utils.Logger.Debug().Msg("Commit created successfully via AcceptAndCommit")
-		fmt.Println("✓ Commit created successfully")
+		s.printCommitCreated()
 		return message, utils.ErrCommitAlreadyCreated
 
 	case ui.AcceptAndEdit:
 		// User wants to edit - parse AI message into PrefilledCommitMessage and pre-fill prompts
 		prefilled := s.parseAIMessageToPrefilled(aiMessage)
+		prefilled.TypeConfirmed = typeMatchesInference(prefilled.Type, repoState)
 		commitMsg, err := s.promptCommitMessage(&prefilled)
 		if err != nil {
 			// Handle cancellation (restore staging state)
@@ -506,20 +864,35 @@ func (s *CommitService) generateWithAIWithRetry(ctx context.Context, repoState *
 
 		// Create commit with edited message
 		// Set signoff based on options
-		if s.options != nil {
-			commitMsg.Signoff = !s.options.NoSignoff
-		} else {
-			commitMsg.Signoff = true // Default to signoff
-		}
+		s.applySignoff(commitMsg)
 
 		// Create commit
-		if err := s.gitRepo.CreateCommit(ctx, commitMsg); err != nil {
+		if err := s.createCommit(ctx, commitMsg); err != nil {
 			return s.handleCommitFailure(ctx, commitMsg, err)
 		}
 
 		// Commit succeeded - return sentinel error to signal commit was already created
 		utils.Logger.Debug().Msg("Commit created successfully via AcceptAndEdit")
-		fmt.Println("✓ Commit created successfully")
+		s.printCommitCreated()
+		return commitMsg, utils.ErrCommitAlreadyCreated
+
+	case ui.AcceptAndEditInline:
+		// User wants to tweak the whole message at once - a single form pre-filled with it
+		commitMsg, err := s.promptMessageInline(message)
+		if err != nil {
+			// Handle cancellation (restore staging state)
+			return nil, fmt.Errorf("failed to prompt for commit message: %w", err)
+		}
+
+		// Create commit with edited message
+		s.applySignoff(commitMsg)
+		if err := s.createCommit(ctx, commitMsg); err != nil {
+			return s.handleCommitFailure(ctx, commitMsg, err)
+		}
+
+		// Commit succeeded - return sentinel error to signal commit was already created
+		utils.Logger.Debug().Msg("Commit created successfully via AcceptAndEditInline")
+		s.printCommitCreated()
 		return commitMsg, utils.ErrCommitAlreadyCreated
 
 	case ui.Reject:
@@ -531,11 +904,11 @@ func (s *CommitService) generateWithAIWithRetry(ctx context.Context, repoState *
 
 		if useNewAI {
 			// Generate new AI message (recursive call with incremented retry count)
-			newMessage, err := s.generateWithAIWithRetry(ctx, repoState, retryCount+1)
+			newMessage, err := s.generateWithAIWithRetry(ctx, repoState, retryCount+1, message)
 			if err != nil {
 				// AI generation failed - fall back to manual input with error message
-				fmt.Printf("Error generating new AI message: %v\n", err)
-				fmt.Println("Falling back to manual input...")
+				fmt.Fprintf(ui.Out(), "Error generating new AI message: %v\n", err)
+				fmt.Fprintln(ui.Out(), "Falling back to manual input...")
 				return s.promptCommitMessage(nil)
 			}
 			return newMessage, nil
@@ -553,7 +926,7 @@ func (s *CommitService) generateWithAIWithRetry(ctx context.Context, repoState *
 // handleCommitFailure handles commit failure after AcceptAndCommit by prompting user for retry/edit/cancel
 func (s *CommitService) handleCommitFailure(ctx context.Context, message *model.CommitMessage, commitErr error) (*model.CommitMessage, error) {
 	// Display error message
-	fmt.Printf("\nError creating commit: %s\n", repository.FormatErrorForDisplay(commitErr))
+	fmt.Fprintf(ui.Out(), "\nError creating commit: %s\n", repository.FormatErrorForDisplay(commitErr))
 
 	// Prompt for retry/edit/cancel
 	choice, err := ui.PromptCommitFailureChoice(s.reader)
@@ -564,13 +937,13 @@ func (s *CommitService) handleCommitFailure(ctx context.Context, message *model.
 	switch choice {
 	case ui.RetryCommit:
 		// Retry commit with same message
-		if err := s.gitRepo.CreateCommit(ctx, message); err != nil {
+		if err := s.createCommit(ctx, message); err != nil {
 			// Recursive retry (with limit to prevent infinite loop)
 			// For now, just retry once more
 			return s.handleCommitFailure(ctx, message, err)
 		}
 		utils.Logger.Debug().Msg("Commit created successfully after retry")
-		fmt.Println("✓ Commit created successfully")
+		s.printCommitCreated()
 		return message, utils.ErrCommitAlreadyCreated
 
 	case ui.EditMessage:
@@ -588,31 +961,42 @@ func (s *CommitService) handleCommitFailure(ctx context.Context, message *model.
 	}
 }
 
+// postProcessAIMessage runs the configured post-processing pipeline on raw AI output
+func (s *CommitService) postProcessAIMessage(aiMessage string) (string, error) {
+	if s.config == nil || len(s.config.AI.PostProcessors) == 0 {
+		return aiMessage, nil
+	}
+
+	pipeline, err := postprocess.NewPipeline(s.config.AI.PostProcessors)
+	if err != nil {
+		return "", fmt.Errorf("invalid ai.post_processors configuration: %w", err)
+	}
+
+	processed := pipeline.Apply(aiMessage)
+	utils.Logger.Debug().
+		Strs("post_processors", s.config.AI.PostProcessors).
+		Str("raw", aiMessage).
+		Str("processed", processed).
+		Msg("Post-processed AI message")
+	return processed, nil
+}
+
 // parseAIMessageToPrefilled converts an AI-generated message string into PrefilledCommitMessage structure
 func (s *CommitService) parseAIMessageToPrefilled(aiMessage string) ui.PrefilledCommitMessage {
 	prefilled := ui.PrefilledCommitMessage{}
 
-	lines := strings.Split(strings.TrimSpace(aiMessage), "\n")
+	lines := strings.Split(extractCommitMessage(aiMessage, s.layout), "\n")
 	if len(lines) == 0 {
 		return prefilled
 	}
 
-	// Parse header (first line): type(scope): subject
-	header := lines[0]
-	parts := strings.SplitN(header, ":", 2)
-	if len(parts) == 2 {
-		typeScope := strings.TrimSpace(parts[0])
-		prefilled.Subject = strings.TrimSpace(parts[1])
-
-		// Parse type and scope
-		if strings.Contains(typeScope, "(") && strings.Contains(typeScope, ")") {
-			openIdx := strings.Index(typeScope, "(")
-			closeIdx := strings.Index(typeScope, ")")
-			prefilled.Type = strings.TrimSpace(typeScope[:openIdx])
-			prefilled.Scope = strings.TrimSpace(typeScope[openIdx+1 : closeIdx])
-		} else {
-			prefilled.Type = strings.TrimSpace(typeScope)
-		}
+	// Parse header (first line): type(scope): subject, or the custom layout
+	if header, ok := s.parseHeader(lines[0]); ok {
+		prefilled.Type = header.Type
+		prefilled.Scope = header.Scope
+		prefilled.Subject = header.Subject
+		prefilled.Ticket = header.Ticket
+		prefilled.Breaking = header.Breaking
 	}
 
 	// Parse body and footer (if present)
@@ -647,47 +1031,333 @@ func (s *CommitService) parseAIMessageToPrefilled(aiMessage string) ui.Prefilled
 	return prefilled
 }
 
+// promptMessageInline lets the user edit the formatted message in a single form, which
+// only submits once the message parses and passes validation
+func (s *CommitService) promptMessageInline(message *model.CommitMessage) (*model.CommitMessage, error) {
+	validate := func(text string) error {
+		_, err := s.parseEditedMessage(text)
+		return err
+	}
+	edited, err := ui.PromptMessageInline(s.reader, s.formatter.Format(message), validate)
+	if err != nil {
+		return nil, err
+	}
+	return s.parseEditedMessage(edited)
+}
+
+// parseEditedMessage parses a message edited inline and validates it. The errors are
+// short enough to show under the form field, all validation errors at once.
+func (s *CommitService) parseEditedMessage(text string) (*model.CommitMessage, error) {
+	message, err := s.parseAIMessage(strings.TrimSpace(text))
+	if err != nil {
+		return nil, err
+	}
+	if valid, validationErrors := s.validator.Validate(message); !valid {
+		var errorMessages []string
+		for _, ve := range validationErrors {
+			errorMessages = append(errorMessages, fmt.Sprintf("%s: %s", ve.Field, ve.Message))
+		}
+		return nil, errors.New(strings.Join(errorMessages, "; "))
+	}
+	return message, nil
+}
+
+// typeMatchesInference returns true if the AI type matches the type inferred
+// from the staged file paths with high confidence
+func typeMatchesInference(aiType string, repoState *model.RepositoryState) bool {
+	if aiType == "" || repoState == nil {
+		return false
+	}
+
+	paths := make([]string, 0, len(repoState.StagedFiles))
+	for _, file := range repoState.StagedFiles {
+		paths = append(paths, file.Path)
+	}
+
+	inference := conventional.InferType(paths)
+	utils.Logger.Debug().
+		Str("ai_type", aiType).
+		Str("inferred_type", inference.Type).
+		Float64("confidence", inference.Confidence).
+		Msg("Commit type inference")
+	return inference.IsHighConfidence() && inference.Type == aiType
+}
+
+// createCommit creates the commit on the current branch, or on the target branch
+// (without switching the worktree) when a branch option is set.
+// When a patch directory is set (or the patch is emailed), the commit is also exported
+// as a patch; in patch-only mode a dangling commit object is exported and no branch is updated.
+// Ticket and metrics footers are appended when commit.branch_tickets and commit.stats_footer
+// are enabled, the AI trailer with commit.ai_trailer, and the branch is pushed afterwards when the push option is set.
+func (s *CommitService) createCommit(ctx context.Context, message *model.CommitMessage) error {
+	message = s.withFooters(ctx, message)
+
+	if s.dryRun() {
+		preview := ui.DisplayCommitMessage(message)
+		if s.newBranch() {
+			name, err := s.newBranchName(message)
+			if err != nil {
+				return err
+			}
+			preview += "\n\nNew branch: " + name
+		}
+		fmt.Fprint(ui.Out(), formatDryRun(preview, s.staged))
+		s.recordResult(s.formatter.Format(message))
+		return nil
+	}
+	if s.copyOnly() {
+		return s.copyMessage(message)
+	}
+
+	if err := s.runHook(ctx, s.commitHookPayload(hooks.PreCommit, message)); err != nil {
+		return err
+	}
+	if s.newBranch() {
+		if err := s.switchToNewBranch(ctx, message); err != nil {
+			return err
+		}
+	}
+
+	commitCtx, span := telemetry.Start(ctx, "create commit")
+	revision, err := s.writeCommit(commitCtx, message)
+	span.SetAttributes(attribute.String("gitcomm.revision", revision))
+	telemetry.End(span, err)
+	s.notifyCommit(ctx, message, revision, err)
+	if err != nil {
+		return err
+	}
+	s.recordCommit(ctx, revision)
+	s.recordHistory(ctx, revision)
+	s.finishTimer(ctx, message)
+	if s.options == nil {
+		return nil
+	}
+	if s.exchanges != nil {
+		// The commit exists: report failures without failing the commit
+		if err := s.saveExchange(ctx, message, revision); err != nil {
+			ui.PrintError("saving the AI exchange failed", err)
+		}
+	}
+
+	if s.options.Push && !s.options.PatchOnly {
+		// The commit exists: report push failures without failing the commit
+		if err := s.push(ctx); err != nil {
+			ui.PrintError("push failed", err)
+		}
+	}
+
+	if s.options.ExportPatchDir == "" && !s.options.SendEmail {
+		return nil
+	}
+
+	// The commit exists at this point: report export/email failures without
+	// failing the commit, which would otherwise offer to create it again
+	patchPath, err := s.exportPatch(ctx, revision)
+	if err != nil {
+		ui.PrintError("patch export failed", err)
+		return nil
+	}
+	if s.options.SendEmail {
+		if err := s.sendPatch(ctx, patchPath); err != nil {
+			ui.PrintError("sending patch failed", err)
+		}
+	}
+	return nil
+}
+
+// withFooters returns a copy of message with the configured footers added: the branch
+// ticket, the diff stats, the time spent and the AI trailer. Messages are shown with
+// their footers before the commit is confirmed; a message that already has them is
+// returned unchanged.
+func (s *CommitService) withFooters(ctx context.Context, message *model.CommitMessage) *model.CommitMessage {
+	if message.FootersApplied {
+		return message
+	}
+	message = s.withTicketFooter(ctx, message)
+	message = s.withStatsFooter(ctx, message)
+	message = s.withTimeFooter(ctx, message)
+	message = s.withAITrailer(message)
+
+	copied := *message
+	copied.FootersApplied = true
+	return &copied
+}
+
+// writeCommit creates the commit where the options ask for and returns its revision
+func (s *CommitService) writeCommit(ctx context.Context, message *model.CommitMessage) (string, error) {
+	message.NoVerify = s.options != nil && s.options.NoVerify
+	switch {
+	case s.options == nil:
+		return "HEAD", s.gitRepo.CreateCommit(ctx, message)
+	case s.options.Amend:
+		return "HEAD", s.gitRepo.AmendCommit(ctx, message)
+	case s.options.PatchOnly:
+		return s.gitRepo.CreateCommitObject(ctx, message)
+	case s.options.Branch != "":
+		return "refs/heads/" + s.options.Branch, s.gitRepo.CreateCommitOnBranch(ctx, message, s.options.Branch)
+	default:
+		return "HEAD", s.gitRepo.CreateCommit(ctx, message)
+	}
+}
+
+// exportPatch writes revision as a patch file into the configured (or a temporary) directory
+func (s *CommitService) exportPatch(ctx context.Context, revision string) (string, error) {
+	dir := s.options.ExportPatchDir
+	if dir == "" {
+		tmpDir, err := os.MkdirTemp("", "gitcomm-patch-")
+		if err != nil {
+			return "", fmt.Errorf("failed to create patch directory: %w", err)
+		}
+		dir = tmpDir
+	}
+
+	path, err := s.gitRepo.ExportPatch(ctx, revision, dir)
+	if err != nil {
+		return "", err
+	}
+	fmt.Fprintf(ui.Out(), "✓ Patch written to %s\n", path)
+	return path, nil
+}
+
+// sendPatch emails the patch using the SMTP configuration, after confirmation.
+// Recipients from git config sendemail.to / sendemail.cc override the gitcomm config.
+func (s *CommitService) sendPatch(ctx context.Context, patchPath string) error {
+	mailCfg := mail.Config{}
+	if s.config != nil {
+		email := s.config.Email
+		mailCfg = mail.Config{
+			Server:     email.SMTPServer,
+			Port:       email.SMTPPort,
+			Username:   email.SMTPUser,
+			Password:   email.SMTPPassword,
+			Encryption: email.SMTPEncryption,
+			From:       email.From,
+			To:         email.To,
+			Cc:         email.Cc,
+		}
+	}
+
+	if to, err := s.gitRepo.GetConfigValues(ctx, "sendemail.to"); err == nil && len(to) > 0 {
+		mailCfg.To = to
+	}
+	if cc, err := s.gitRepo.GetConfigValues(ctx, "sendemail.cc"); err == nil && len(cc) > 0 {
+		mailCfg.Cc = cc
+	}
+
+	sender, err := mail.NewSender(mailCfg)
+	if err != nil {
+		return fmt.Errorf("email not sent (patch kept at %s): %w", patchPath, err)
+	}
+
+	recipients := strings.Join(append(append([]string{}, mailCfg.To...), mailCfg.Cc...), ", ")
+	confirm, err := ui.PromptConfirm(s.reader, fmt.Sprintf("Send patch to %s?", recipients), true)
+	if err != nil {
+		return fmt.Errorf("failed to prompt for email confirmation: %w", err)
+	}
+	if !confirm {
+		fmt.Fprintf(ui.Out(), "Email not sent, patch kept at %s\n", patchPath)
+		return nil
+	}
+
+	if err := sender.SendPatch(ctx, patchPath); err != nil {
+		return fmt.Errorf("email not sent (patch kept at %s): %w", patchPath, err)
+	}
+	fmt.Fprintf(ui.Out(), "✓ Patch sent to %s\n", recipients)
+	return nil
+}
+
+// resolveFixupTarget returns the commit revision points to, for a fixup commit
+func (s *CommitService) resolveFixupTarget(ctx context.Context, revision string) (*model.CommitInfo, error) {
+	commits, err := s.gitRepo.ListCommits(ctx, revision, 1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve fixup target %q: %w", revision, err)
+	}
+	if len(commits) == 0 {
+		return nil, fmt.Errorf("failed to resolve fixup target %q: no commit found", revision)
+	}
+	return &commits[0], nil
+}
+
+// suggestFixup proposes a fixup commit when the message looks like WIP or closely
+// matches a recent commit subject; on confirmation the message becomes a fixup of that commit
+func (s *CommitService) suggestFixup(ctx context.Context, message *model.CommitMessage) error {
+	revision := "HEAD"
+	if branch := s.targetBranch(); branch != "" {
+		revision = "refs/heads/" + branch
+	}
+	recent, err := s.gitRepo.ListCommits(ctx, revision, fixupCandidateCount)
+	if err != nil {
+		// No history yet (or new branch): nothing to fix up
+		utils.Logger.Debug().Err(err).Msg("Failed to list recent commits for fixup detection")
+		return nil
+	}
+
+	target := detectFixupTarget(message, recent)
+	if target == nil {
+		return nil
+	}
+
+	question := fmt.Sprintf("This looks like a follow-up to %s %q. Create a fixup commit for it instead?", target.ShortHash(), target.Subject())
+	confirm, err := ui.PromptConfirm(s.reader, question, false)
+	if err != nil {
+		return fmt.Errorf("failed to prompt for fixup: %w", err)
+	}
+	if confirm {
+		message.Fixup = target
+	}
+	return nil
+}
+
+// targetBranch returns the branch the commit will be created on, if different from the current one
+func (s *CommitService) targetBranch() string {
+	if s.options == nil {
+		return ""
+	}
+	return s.options.Branch
+}
+
+// applySignoff sets the signoff flag and optional sign-off identity based on options
+func (s *CommitService) applySignoff(message *model.CommitMessage) {
+	if s.options == nil {
+		message.Signoff = true // Default to signoff
+		return
+	}
+	if s.options.DCO {
+		// DCO requires the sign-off to match the author
+		message.Signoff = true
+		message.SignoffIdentity = nil
+		return
+	}
+	message.Signoff = !s.options.NoSignoff
+	message.SignoffIdentity = s.options.SignoffIdentity
+}
+
 // commitMessageToPrefilled converts a CommitMessage to PrefilledCommitMessage
 func (s *CommitService) commitMessageToPrefilled(msg *model.CommitMessage) ui.PrefilledCommitMessage {
 	return ui.PrefilledCommitMessage{
-		Type:    msg.Type,
-		Scope:   msg.Scope,
-		Subject: msg.Subject,
-		Body:    msg.Body,
-		Footer:  msg.Footer,
+		Type:     msg.Type,
+		Scope:    msg.Scope,
+		Subject:  msg.Subject,
+		Body:     msg.Body,
+		Footer:   msg.FooterText(),
+		Ticket:   msg.Ticket,
+		Breaking: msg.Breaking,
 	}
 }
 
 // parseAIMessage attempts to parse an AI-generated message into CommitMessage structure
 func (s *CommitService) parseAIMessage(aiMessage string) (*model.CommitMessage, error) {
-	message := &model.CommitMessage{
-		Signoff: true, // Default
-	}
-
-	lines := strings.Split(strings.TrimSpace(aiMessage), "\n")
+	lines := strings.Split(extractCommitMessage(aiMessage, s.layout), "\n")
 	if len(lines) == 0 {
 		return nil, fmt.Errorf("empty message")
 	}
 
-	// Parse header (first line): type(scope): subject
-	header := lines[0]
-	parts := strings.SplitN(header, ":", 2)
-	if len(parts) != 2 {
+	// Parse header (first line): type(scope): subject, or the custom layout
+	message, ok := s.parseHeader(lines[0])
+	if !ok {
 		return nil, fmt.Errorf("invalid header format")
 	}
-
-	typeScope := strings.TrimSpace(parts[0])
-	message.Subject = strings.TrimSpace(parts[1])
-
-	// Parse type and scope
-	if strings.Contains(typeScope, "(") && strings.Contains(typeScope, ")") {
-		openIdx := strings.Index(typeScope, "(")
-		closeIdx := strings.Index(typeScope, ")")
-		message.Type = strings.TrimSpace(typeScope[:openIdx])
-		message.Scope = strings.TrimSpace(typeScope[openIdx+1 : closeIdx])
-	} else {
-		message.Type = strings.TrimSpace(typeScope)
-	}
+	message.Signoff = true // Default
 
 	// Parse body and footer (if present)
 	if len(lines) > 1 {
@@ -714,9 +1384,9 @@ func (s *CommitService) parseAIMessage(aiMessage string) (*model.CommitMessage,
 			message.Body = strings.Join(bodyLines, "\n")
 		}
 		if len(footerLines) > 0 {
-			message.Footer = strings.Join(footerLines, "\n")
+			message.SetFooter(strings.Join(footerLines, "\n"))
 		}
 	}
 
-	return message, nil
+	return s.withLayout(message), nil
 }
@@ -3,8 +3,17 @@ package service
 import (
 	"context"
 	"errors"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/golgoth31/gitcomm/internal/config"
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/repository"
+	"github.com/golgoth31/gitcomm/internal/ui"
+	"github.com/golgoth31/gitcomm/internal/utils"
+	"github.com/golgoth31/gitcomm/pkg/testutil"
 )
 
 func TestCreateTimeoutContext(t *testing.T) {
@@ -81,3 +90,231 @@ func TestIsTimeoutError(t *testing.T) {
 		})
 	}
 }
+
+func TestTypeMatchesInference(t *testing.T) {
+	docsState := &model.RepositoryState{
+		StagedFiles: []model.FileChange{{Path: "README.md"}, {Path: "docs/setup.md"}},
+	}
+	codeState := &model.RepositoryState{
+		StagedFiles: []model.FileChange{{Path: "internal/cmd/root.go"}},
+	}
+
+	tests := []struct {
+		name      string
+		aiType    string
+		repoState *model.RepositoryState
+		want      bool
+	}{
+		{name: "matching inferred type", aiType: "docs", repoState: docsState, want: true},
+		{name: "different inferred type", aiType: "feat", repoState: docsState, want: false},
+		{name: "no inference for source code", aiType: "feat", repoState: codeState, want: false},
+		{name: "empty AI type", aiType: "", repoState: docsState, want: false},
+		{name: "nil repository state", aiType: "docs", repoState: nil, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := typeMatchesInference(tt.aiType, tt.repoState); got != tt.want {
+				t.Errorf("typeMatchesInference() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateCommit_SuppliedMessage(t *testing.T) {
+	ui.SetNonInteractive(true)
+	defer ui.SetNonInteractive(false)
+
+	tests := []struct {
+		name    string
+		message string
+		want    string
+		wantErr error
+	}{
+		{
+			name:    "conventional message",
+			message: "feat(api): add the api\n\nServe the health endpoint.\n\nRefs: #12",
+			want:    "feat(api): add the api\n\nServe the health endpoint.\n\nRefs: #12\n",
+		},
+		{
+			name:    "invalid header",
+			message: "Add the api",
+			wantErr: utils.ErrInvalidFormat,
+		},
+		{
+			name:    "invalid type",
+			message: "feature: add the api",
+			wantErr: utils.ErrInvalidFormat,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fixture := initMessageRepo(t, true)
+			gitRepo, err := repository.NewGitRepository(fixture.Dir, true, true)
+			if err != nil {
+				t.Fatalf("NewGitRepository() error = %v", err)
+			}
+
+			// No provider is configured: the message must not go through AI
+			options := &model.CommitOptions{Message: tt.message, NoSignoff: true}
+			err = NewCommitService(gitRepo, options, &config.Config{}).CreateCommit(context.Background())
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("CreateCommit() error = %v, want %v", err, tt.wantErr)
+				}
+				if _, err := fixture.TryGit("rev-parse", "HEAD"); err == nil {
+					t.Error("a commit was created from an invalid message")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CreateCommit() error = %v", err)
+			}
+			if got := fixture.Git("log", "-1", "--format=%B"); got != tt.want+"\n" {
+				t.Errorf("commit message = %q, want %q", got, tt.want+"\n")
+			}
+		})
+	}
+}
+
+func TestCreateCommit_StagingFaults(t *testing.T) {
+	ui.SetNonInteractive(true)
+	defer ui.SetNonInteractive(false)
+
+	const valid, invalid = "fix: update the texts", "Update the texts"
+	tests := []struct {
+		name       string
+		faults     string // repository.FaultsEnv
+		message    string
+		wantErr    error
+		wantStaged string // the index after CreateCommit
+	}{
+		{
+			name:       "staging failure restores the index",
+			faults:     "stage_file=2",
+			message:    valid,
+			wantErr:    utils.ErrStagingFailed,
+			wantStaged: "a.txt\n",
+		},
+		{
+			name:       "aborted commit restores the index",
+			message:    invalid,
+			wantErr:    utils.ErrInvalidFormat,
+			wantStaged: "a.txt\n",
+		},
+		{
+			name:       "failed restoration leaves the files staged",
+			faults:     "unstage",
+			message:    invalid,
+			wantErr:    utils.ErrInvalidFormat,
+			wantStaged: "a.txt\nb.txt\nc.txt\n",
+		},
+		{
+			name:       "restoration timeout leaves the files staged",
+			faults:     "restore_timeout",
+			message:    invalid,
+			wantErr:    utils.ErrInvalidFormat,
+			wantStaged: "a.txt\nb.txt\nc.txt\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(repository.FaultsEnv, tt.faults)
+			// a.txt is staged by the user, b.txt and c.txt are auto-staged
+			fixture := testutil.NewRepoWithCommit(t, map[string]string{"a.txt": "a\n", "b.txt": "b\n", "c.txt": "c\n"})
+			for _, name := range []string{"a.txt", "b.txt", "c.txt"} {
+				fixture.WriteFile(name, name+" changed\n")
+			}
+			fixture.Stage("a.txt")
+			gitRepo, err := repository.NewGitRepository(fixture.Dir, true, true)
+			if err != nil {
+				t.Fatalf("NewGitRepository() error = %v", err)
+			}
+
+			options := &model.CommitOptions{Message: tt.message, NoSignoff: true}
+			err = NewCommitService(gitRepo, options, &config.Config{}).CreateCommit(context.Background())
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CreateCommit() error = %v, want %v", err, tt.wantErr)
+			}
+			if got := fixture.Git("rev-list", "--count", "HEAD"); got != "1\n" {
+				t.Errorf("HEAD has %s commits, want 1", strings.TrimSpace(got))
+			}
+			if staged := fixture.Git("diff", "--cached", "--name-only"); staged != tt.wantStaged {
+				t.Errorf("staged files = %q, want %q", staged, tt.wantStaged)
+			}
+		})
+	}
+}
+
+func TestParseEditedMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		want    *model.CommitMessage
+		wantErr string
+	}{
+		{
+			name: "edited message",
+			text: "fix(api): handle empty responses\n\nThe client retried forever.\n\nRefs: #12\n",
+			want: &model.CommitMessage{Type: "fix", Scope: "api", Subject: "handle empty responses", Body: "The client retried forever.", Trailers: []model.Trailer{{Key: "Refs", Value: "#12"}}, Signoff: true},
+		},
+		{
+			name:    "header without type",
+			text:    "handle empty responses",
+			wantErr: "invalid header format",
+		},
+		{
+			name:    "unknown type",
+			text:    "bugfix: handle empty responses",
+			wantErr: "type:",
+		},
+	}
+
+	service := NewCommitService(nil, nil, nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := service.parseEditedMessage(tt.text)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("parseEditedMessage() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseEditedMessage() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseEditedMessage() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateCommit_InvalidPrivacy(t *testing.T) {
+	ui.SetNonInteractive(true)
+	defer ui.SetNonInteractive(false)
+
+	server := testutil.NewProviderServer(t, "local", "feat: add the api")
+	fixture := initMessageRepo(t, true)
+	gitRepo, err := repository.NewGitRepository(fixture.Dir, true, true)
+	if err != nil {
+		t.Fatalf("NewGitRepository() error = %v", err)
+	}
+	cfg := &config.Config{AI: config.AIConfig{
+		Privacy:   "bogus",
+		Providers: map[string]model.AIProviderConfig{"local": {Endpoint: server.Endpoint()}},
+	}}
+
+	err = NewCommitService(gitRepo, &model.CommitOptions{AIProvider: "local"}, cfg).CreateCommit(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "ai.privacy") {
+		t.Fatalf("CreateCommit() error = %v, want the invalid ai.privacy", err)
+	}
+	if strings.Contains(err.Error(), "AI generation failed") {
+		t.Errorf("CreateCommit() should stop before the AI usage prompt, got %v", err)
+	}
+	if requests := server.Requests(); len(requests) != 0 {
+		t.Errorf("requests = %d, want none with an invalid privacy level", len(requests))
+	}
+}
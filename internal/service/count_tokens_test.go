@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golgoth31/gitcomm/internal/config"
+	"github.com/golgoth31/gitcomm/internal/model"
+)
+
+func TestCountTokens(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/messages/count_tokens" {
+			http.Error(w, `{"type":"error","error":{"type":"not_found_error","message":"not found"}}`, http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"input_tokens":321}`))
+	}))
+	defer server.Close()
+
+	state := &model.RepositoryState{
+		StagedFiles: []model.FileChange{{Path: "main.go", Status: "modified", Diff: "+func main() {}"}},
+	}
+	tests := []struct {
+		name      string
+		provider  string
+		apiKey    string
+		wantExact bool
+	}{
+		{name: "anthropic with API key", provider: "anthropic", apiKey: "sk-ant-test", wantExact: true},
+		{name: "anthropic without API key", provider: "anthropic"},
+		{name: "provider without token counting", provider: "openai", apiKey: "sk-test"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{AI: config.AIConfig{
+				DefaultProvider: tt.provider,
+				Providers: map[string]model.AIProviderConfig{
+					tt.provider: {APIKey: tt.apiKey, Endpoint: server.URL},
+				},
+			}}
+			s := &CommitService{config: cfg}
+			plan := model.ContextPlan{Tokens: 100}
+
+			if counted := s.countTokens(context.Background(), state, &plan); counted != tt.wantExact {
+				t.Errorf("countTokens() = %v, want %v", counted, tt.wantExact)
+			}
+			if plan.Exact != tt.wantExact {
+				t.Errorf("Exact = %v, want %v", plan.Exact, tt.wantExact)
+			}
+			wantTokens := 100
+			if tt.wantExact {
+				wantTokens = 321
+			}
+			if plan.Tokens != wantTokens {
+				t.Errorf("Tokens = %d, want %d", plan.Tokens, wantTokens)
+			}
+		})
+	}
+}
+
+func TestCountTokens_CircuitBreaker(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		http.Error(w, `{"type":"error","error":{"type":"invalid_request_error","message":"bad request"}}`, http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{AI: config.AIConfig{
+		DefaultProvider: "anthropic",
+		Providers: map[string]model.AIProviderConfig{
+			"anthropic": {Name: "anthropic-count-tokens", APIKey: "sk-ant-test", Endpoint: server.URL, CircuitThreshold: 1, CircuitCooldown: time.Hour},
+		},
+	}}
+	s := &CommitService{config: cfg}
+	state := &model.RepositoryState{StagedFiles: []model.FileChange{{Path: "main.go", Status: "modified"}}}
+
+	for range 2 {
+		plan := model.ContextPlan{Tokens: 100}
+		if s.countTokens(context.Background(), state, &plan) || plan.Tokens != 100 || plan.Exact {
+			t.Errorf("failed count should keep the estimate, got %+v", plan)
+		}
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("requests = %d, want 1: the open circuit should stop the second count", got)
+	}
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/repository"
+)
+
+// DCOResult is the Developer Certificate of Origin verification result for one commit
+type DCOResult struct {
+	// Commit is the verified commit
+	Commit model.CommitInfo
+
+	// Valid is true if the commit has a Signed-off-by trailer matching its author
+	Valid bool
+
+	// Reason explains why the commit is not valid (empty when valid)
+	Reason string
+}
+
+// DCOService verifies Signed-off-by trailers in the repository history
+type DCOService struct {
+	gitRepo repository.GitRepository
+}
+
+// NewDCOService creates a new DCO service
+func NewDCOService(gitRepo repository.GitRepository) *DCOService {
+	return &DCOService{
+		gitRepo: gitRepo,
+	}
+}
+
+// Check verifies the last limit commits reachable from revision (HEAD if empty)
+func (s *DCOService) Check(ctx context.Context, revision string, limit int) ([]DCOResult, error) {
+	commits, err := s.gitRepo.ListCommits(ctx, revision, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read commit history: %w", err)
+	}
+
+	results := make([]DCOResult, 0, len(commits))
+	for _, commit := range commits {
+		results = append(results, checkCommitDCO(commit))
+	}
+	return results, nil
+}
+
+// checkCommitDCO verifies that a commit is signed off by its author
+func checkCommitDCO(commit model.CommitInfo) DCOResult {
+	result := DCOResult{Commit: commit}
+
+	signoffs := commit.Signoffs()
+	if len(signoffs) == 0 {
+		result.Reason = "missing Signed-off-by"
+		return result
+	}
+
+	for _, signoff := range signoffs {
+		if signoff.Name == commit.AuthorName && strings.EqualFold(signoff.Email, commit.AuthorEmail) {
+			result.Valid = true
+			return result
+		}
+	}
+
+	result.Reason = fmt.Sprintf("Signed-off-by does not match author %s <%s>", commit.AuthorName, commit.AuthorEmail)
+	return result
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+)
+
+func TestCheckCommitDCO(t *testing.T) {
+	tests := []struct {
+		name      string
+		message   string
+		wantValid bool
+	}{
+		{
+			name:      "signed off by author",
+			message:   "feat: add dco\n\nSigned-off-by: Jane Doe <jane@example.com>",
+			wantValid: true,
+		},
+		{
+			name:      "email comparison is case-insensitive",
+			message:   "feat: add dco\n\nSigned-off-by: Jane Doe <Jane@Example.com>",
+			wantValid: true,
+		},
+		{
+			name:      "one of several sign-offs matches the author",
+			message:   "feat: add dco\n\nSigned-off-by: John Roe <john@example.com>\nSigned-off-by: Jane Doe <jane@example.com>",
+			wantValid: true,
+		},
+		{
+			name:      "missing sign-off",
+			message:   "feat: add dco",
+			wantValid: false,
+		},
+		{
+			name:      "sign-off by someone else",
+			message:   "feat: add dco\n\nSigned-off-by: John Roe <john@example.com>",
+			wantValid: false,
+		},
+		{
+			name:      "malformed sign-off",
+			message:   "feat: add dco\n\nSigned-off-by: Jane Doe",
+			wantValid: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			commit := model.CommitInfo{
+				Hash:        "0123456789abcdef",
+				AuthorName:  "Jane Doe",
+				AuthorEmail: "jane@example.com",
+				Message:     tt.message,
+			}
+
+			got := checkCommitDCO(commit)
+			if got.Valid != tt.wantValid {
+				t.Errorf("checkCommitDCO() valid = %v, want %v (reason: %q)", got.Valid, tt.wantValid, got.Reason)
+			}
+			if !got.Valid && got.Reason == "" {
+				t.Error("checkCommitDCO() expected a reason for invalid commit")
+			}
+		})
+	}
+}
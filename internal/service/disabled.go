@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/golgoth31/gitcomm/internal/repository"
+	"github.com/golgoth31/gitcomm/internal/utils"
+)
+
+// DisableFile is the marker file that disables gitcomm in a repository, at the root of
+// the worktree. Its first line, if any, is shown as the reason.
+const DisableFile = ".gitcomm-disable"
+
+// enabledGitKey is the git config key disabling gitcomm in a repository when false
+const enabledGitKey = "gitcomm.enabled"
+
+// CheckEnabled returns an ErrRepositoryDisabled error when the repository opted out of
+// gitcomm with a DisableFile marker or git config gitcomm.enabled=false. An invalid
+// gitcomm.enabled value is an error too, so a typo never enables the tool by mistake.
+func CheckEnabled(ctx context.Context, gitRepo repository.GitRepository) error {
+	if values, err := gitRepo.GetConfigValues(ctx, enabledGitKey); err != nil {
+		utils.Logger.Debug().Err(err).Msg("Failed to read git config " + enabledGitKey)
+	} else if len(values) > 0 {
+		// The last value wins, like git config --get
+		enabled, ok := parseGitBool(values[len(values)-1])
+		if !ok {
+			return fmt.Errorf("%w: invalid git config %s value %q", utils.ErrRepositoryDisabled, enabledGitKey, values[len(values)-1])
+		}
+		if !enabled {
+			return fmt.Errorf("%w: git config %s is false", utils.ErrRepositoryDisabled, enabledGitKey)
+		}
+	}
+
+	workTree, err := gitRepo.WorkTreeDir(ctx)
+	if err != nil {
+		// Bare repositories have no worktree to hold the marker
+		utils.Logger.Debug().Err(err).Msg("Failed to locate worktree for " + DisableFile)
+		return nil
+	}
+	content, err := os.ReadFile(filepath.Join(workTree, DisableFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		// An unreadable marker still disables the tool
+		return fmt.Errorf("%w: %s found: %v", utils.ErrRepositoryDisabled, DisableFile, err)
+	}
+	reason, _, _ := strings.Cut(strings.TrimSpace(string(content)), "\n")
+	if reason = strings.TrimSpace(reason); reason != "" {
+		return fmt.Errorf("%w: %s found: %s", utils.ErrRepositoryDisabled, DisableFile, reason)
+	}
+	return fmt.Errorf("%w: %s found", utils.ErrRepositoryDisabled, DisableFile)
+}
+
+// parseGitBool parses a git config boolean (true/yes/on/1, false/no/off/0)
+func parseGitBool(value string) (enabled, ok bool) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "true", "yes", "on", "1":
+		return true, true
+	case "false", "no", "off", "0", "":
+		return false, true
+	default:
+		return false, false
+	}
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/golgoth31/gitcomm/internal/repository"
+	"github.com/golgoth31/gitcomm/internal/utils"
+	"github.com/golgoth31/gitcomm/pkg/testutil"
+)
+
+func TestCheckEnabled(t *testing.T) {
+	tests := []struct {
+		name       string
+		hasMarker  bool
+		marker     string
+		gitConfig  string
+		wantErr    bool
+		wantReason string
+	}{
+		{name: "enabled by default"},
+		{name: "enabled in git config", gitConfig: "true"},
+		{name: "disabled in git config", gitConfig: "false", wantErr: true, wantReason: "gitcomm.enabled is false"},
+		{name: "git config off", gitConfig: "off", wantErr: true},
+		{name: "invalid git config", gitConfig: "maybe", wantErr: true, wantReason: `invalid git config gitcomm.enabled value "maybe"`},
+		{name: "empty marker", hasMarker: true, wantErr: true, wantReason: ".gitcomm-disable found"},
+		{name: "marker with reason", hasMarker: true, marker: "\nSensitive repository: no AI\nsecond line\n", wantErr: true, wantReason: "found: Sensitive repository: no AI"},
+		{name: "marker wins over git config", hasMarker: true, gitConfig: "true", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fixture := testutil.NewRepo(t)
+			if tt.hasMarker {
+				fixture.WriteFile(DisableFile, tt.marker)
+			}
+			if tt.gitConfig != "" {
+				fixture.Git("config", "gitcomm.enabled", tt.gitConfig)
+			}
+			// The marker is found from any directory of the worktree
+			fixture.WriteFile("sub/dir/file.txt", "content\n")
+			gitRepo, err := repository.NewGitRepository(fixture.Path("sub/dir"), true, true)
+			if err != nil {
+				t.Fatalf("NewGitRepository() error = %v", err)
+			}
+
+			err = CheckEnabled(context.Background(), gitRepo)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CheckEnabled() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, utils.ErrRepositoryDisabled) {
+				t.Errorf("CheckEnabled() error = %v, want ErrRepositoryDisabled", err)
+			}
+			if tt.wantReason != "" && !strings.Contains(err.Error(), tt.wantReason) {
+				t.Errorf("CheckEnabled() error = %q, want it to contain %q", err, tt.wantReason)
+			}
+		})
+	}
+}
@@ -0,0 +1,279 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/golgoth31/gitcomm/internal/config"
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/repository"
+	"github.com/golgoth31/gitcomm/internal/ui"
+	"github.com/golgoth31/gitcomm/internal/utils"
+)
+
+// providerCheckTimeout bounds the test request sent to the AI provider
+const providerCheckTimeout = 30 * time.Second
+
+// CheckStatus is the outcome of a doctor check
+type CheckStatus int
+
+// Check outcomes
+const (
+	CheckPass CheckStatus = iota
+	CheckWarn             // gitcomm works, with a limitation
+	CheckFail             // gitcomm cannot work as configured
+	CheckSkip             // not run, as an earlier check failed or it was disabled
+)
+
+// DoctorCheck is the result of one check of the environment
+type DoctorCheck struct {
+	// Name is what was checked
+	Name string
+
+	// Status is the outcome of the check
+	Status CheckStatus
+
+	// Detail describes what was found
+	Detail string
+
+	// Fix is the action resolving a warning or a failure (empty when passed)
+	Fix string
+}
+
+// DoctorService checks the environment gitcomm runs in: git, the repository, the git
+// identity and signing key, the config file, the AI provider and the terminal
+type DoctorService struct {
+	repoPath   string
+	configPath string
+	provider   string
+	account    string
+	offline    bool
+}
+
+// NewDoctorService creates a doctor for the repository at repoPath (the working directory
+// if empty) and the config file at configPath (the default if empty). provider and account
+// override the default provider and its account; offline skips the provider test request.
+func NewDoctorService(repoPath, configPath, provider, account string, offline bool) *DoctorService {
+	return &DoctorService{
+		repoPath:   repoPath,
+		configPath: configPath,
+		provider:   provider,
+		account:    account,
+		offline:    offline,
+	}
+}
+
+// Run runs every check, in order
+func (s *DoctorService) Run(ctx context.Context) []DoctorCheck {
+	checks := []DoctorCheck{checkGit()}
+
+	gitRepo, repoCheck := s.checkRepository(ctx, checks[0].Status == CheckFail)
+	checks = append(checks, repoCheck)
+	if gitRepo != nil {
+		checks = append(checks, checkIdentity(gitRepo), checkSigning(ctx, gitRepo))
+	} else {
+		checks = append(checks,
+			DoctorCheck{Name: "Git identity", Status: CheckSkip, Detail: "no repository"},
+			DoctorCheck{Name: "Commit signing", Status: CheckSkip, Detail: "no repository"})
+	}
+
+	cfg, configCheck := s.checkConfig()
+	checks = append(checks, configCheck)
+	checks = append(checks, s.checkProvider(ctx, cfg), checkTerminal())
+	return checks
+}
+
+// checkGit checks that git is installed in a supported version
+func checkGit() DoctorCheck {
+	check := DoctorCheck{Name: "Git"}
+	version, err := repository.GitVersion()
+	switch {
+	case errors.Is(err, repository.ErrGitNotFound):
+		check.Status, check.Detail = CheckFail, "git is not installed or not in PATH"
+		check.Fix = "Install git 2.34 or later"
+	case err != nil:
+		check.Status, check.Detail = CheckFail, err.Error()
+		check.Fix = "Upgrade git to 2.34 or later"
+	default:
+		check.Detail = version
+	}
+	return check
+}
+
+// checkRepository opens the repository, returning nil when there is none
+func (s *DoctorService) checkRepository(ctx context.Context, noGit bool) (repository.GitRepository, DoctorCheck) {
+	check := DoctorCheck{Name: "Repository"}
+	if noGit {
+		check.Status, check.Detail = CheckSkip, "git is not available"
+		return nil, check
+	}
+
+	gitRepo, err := repository.NewGitRepository(s.repoPath, false, true)
+	switch {
+	case errors.Is(err, utils.ErrNotGitRepository):
+		check.Status, check.Detail = CheckWarn, "not inside a git repository"
+		check.Fix = "Run gitcomm doctor inside the repository you commit to"
+		return nil, check
+	case err != nil:
+		check.Status, check.Detail = CheckFail, err.Error()
+		return nil, check
+	}
+
+	root, err := gitRepo.WorkTreeDir(ctx)
+	if err != nil {
+		check.Status, check.Detail = CheckFail, err.Error()
+		return nil, check
+	}
+	branch, err := gitRepo.GetCurrentBranch(ctx)
+	if err != nil || branch == "" {
+		branch = "detached HEAD"
+	}
+	check.Detail = fmt.Sprintf("%s (%s)", root, branch)
+	return gitRepo, check
+}
+
+// checkIdentity checks the author identity read from the git config files
+func checkIdentity(gitRepo repository.GitRepository) DoctorCheck {
+	check := DoctorCheck{Name: "Git identity"}
+	name, email := gitRepo.GetIdentity()
+	switch {
+	case name == "" && email == "":
+		check.Status, check.Detail = CheckWarn, "user.name and user.email are not set: commits use a default author"
+		check.Fix = `git config --global user.name "Your Name" && git config --global user.email you@example.com`
+	case name == "":
+		check.Status, check.Detail = CheckWarn, "user.name is not set: commits use a default author name"
+		check.Fix = `git config --global user.name "Your Name"`
+	case email == "":
+		check.Status, check.Detail = CheckWarn, "user.email is not set: commits use a default author email"
+		check.Fix = "git config --global user.email you@example.com"
+	default:
+		check.Detail = fmt.Sprintf("%s <%s>", name, email)
+	}
+	return check
+}
+
+// checkSigning checks that the signing key, when configured, can sign
+func checkSigning(ctx context.Context, gitRepo repository.GitRepository) DoctorCheck {
+	check := DoctorCheck{Name: "Commit signing"}
+	status, err := gitRepo.CheckSigning(ctx)
+	switch {
+	case err != nil:
+		check.Status, check.Detail = CheckFail, err.Error()
+		if status != nil && status.Format == "ssh" {
+			check.Fix = "Point user.signingkey to your SSH public key (e.g. ~/.ssh/id_ed25519.pub), or sign with --no-sign"
+		} else {
+			check.Fix = "Import the secret key in gpg or fix user.signingkey, or sign with --no-sign"
+		}
+	case status == nil:
+		check.Detail = "not configured: commits are not signed"
+	case status.IdentityWarning != "":
+		check.Status, check.Detail = CheckWarn, status.IdentityWarning
+		check.Fix = "Add \"<user.email> <public key>\" to gpg.ssh.allowedSignersFile, and the key as a signing key of the account of user.email on your forge"
+	case status.Format == "ssh" && status.Agent:
+		check.Detail = fmt.Sprintf("SSH key %s, loaded in ssh-agent", status.Key)
+	case status.Format == "ssh":
+		check.Detail = fmt.Sprintf("SSH key %s, signed by git with the private key file", status.Key)
+	default:
+		check.Detail = fmt.Sprintf("OpenPGP key %s", status.Key)
+	}
+	return check
+}
+
+// checkConfig checks, loads and validates the config file, returning nil when it is
+// invalid. Unknown keys, usually typos, are a warning.
+func (s *DoctorService) checkConfig() (*config.Config, DoctorCheck) {
+	check := DoctorCheck{Name: "Configuration"}
+	path, err := config.ResolvePath(s.configPath)
+	var unknown []string
+	if err == nil {
+		unknown, err = config.CheckFile(path)
+	}
+	var cfg *config.Config
+	if err == nil {
+		cfg, err = config.LoadConfig(path)
+	}
+	if err == nil {
+		err = cfg.Validate()
+	}
+	if err != nil {
+		check.Status, check.Detail = CheckFail, err.Error()
+		check.Fix = "Fix the config file with gitcomm config edit"
+		return nil, check
+	}
+
+	check.Detail = cfg.File.Path
+	if len(unknown) > 0 {
+		check.Status = CheckWarn
+		check.Detail += fmt.Sprintf(": unknown key(s) %s, ignored", strings.Join(unknown, ", "))
+		check.Fix = "Fix or remove the keys with gitcomm config edit (see gitcomm config list --keys)"
+	}
+	return cfg, check
+}
+
+// checkProvider sends a tiny request to the AI provider
+func (s *DoctorService) checkProvider(ctx context.Context, cfg *config.Config) DoctorCheck {
+	check := DoctorCheck{Name: "AI provider"}
+	switch {
+	case cfg == nil:
+		check.Status, check.Detail = CheckSkip, "invalid configuration"
+		return check
+	case len(cfg.AI.Providers) == 0 && s.provider == "":
+		check.Status, check.Detail = CheckWarn, "no provider configured: messages are written manually"
+		check.Fix = "gitcomm config set ai.providers.openai.api_key '${OPENAI_API_KEY}'"
+		return check
+	}
+
+	commits := NewCommitService(nil, &model.CommitOptions{AIProvider: s.provider, Account: s.account}, cfg)
+	name := commits.providerName()
+	if s.offline {
+		check.Status, check.Detail = CheckSkip, name+": not contacted (--offline)"
+		return check
+	}
+	provider, err := commits.newAIProvider()
+	if err != nil {
+		check.Status, check.Detail = CheckFail, err.Error()
+		check.Fix = fmt.Sprintf("Configure the provider under ai.providers.%s", name)
+		return check
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, providerCheckTimeout)
+	defer cancel()
+	start := time.Now()
+	if _, err := provider.Complete(ctx, "You are a connectivity check. Answer with the single word OK.", "ping"); err != nil {
+		check.Status, check.Detail = CheckFail, fmt.Sprintf("%s: %v", name, err)
+		check.Fix = fmt.Sprintf("Check the api_key, model and endpoint of ai.providers.%s, and your network", name)
+		return check
+	}
+	detail := name
+	if providerModel := cfg.AI.Providers[name].Model; providerModel != "" {
+		detail += " (" + providerModel + ")"
+	}
+	if account := cfg.ProviderAccount(name, s.account); account != "" {
+		detail += " with account " + account
+	}
+	check.Detail = fmt.Sprintf("%s answered in %s", detail, time.Since(start).Round(100*time.Millisecond))
+	return check
+}
+
+// checkTerminal checks that the interactive prompts can be drawn
+func checkTerminal() DoctorCheck {
+	check := DoctorCheck{Name: "Terminal"}
+	switch {
+	case !ui.Interactive():
+		check.Status, check.Detail = CheckWarn, "stdin or stderr is not a terminal: prompts are asked one line at a time"
+		check.Fix = "Run gitcomm in a terminal, or use --yes to skip the prompts"
+	case os.Getenv("TERM") == "dumb":
+		check.Status, check.Detail = CheckWarn, "TERM=dumb: full-screen prompts may not render"
+		check.Fix = "Set TERM (e.g. xterm-256color), or use --plain"
+	default:
+		check.Detail = fmt.Sprintf("interactive, %d columns", ui.TerminalWidth())
+		if os.Getenv("NO_COLOR") != "" {
+			check.Detail += ", colors disabled (NO_COLOR)"
+		}
+	}
+	return check
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/golgoth31/gitcomm/pkg/testutil"
+)
+
+// writeDoctorConfig writes a config file for the doctor tests and returns its path
+func writeDoctorConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	return path
+}
+
+// findCheck returns the check named name
+func findCheck(t *testing.T, checks []DoctorCheck, name string) DoctorCheck {
+	t.Helper()
+	for _, check := range checks {
+		if check.Name == name {
+			return check
+		}
+	}
+	t.Fatalf("no %q check in %+v", name, checks)
+	return DoctorCheck{}
+}
+
+func TestDoctorService_Run(t *testing.T) {
+	repo := testutil.NewRepoWithCommit(t, map[string]string{"README.md": "# test\n"})
+	server := testutil.NewProviderServer(t, "local", "ok")
+	failing := testutil.NewProviderServer(t, "local", "ok")
+	failing.FailWith(401)
+
+	providerConfig := func(endpoint string) string {
+		return "ai:\n  default_provider: local\n  providers:\n    local:\n      endpoint: " + endpoint + "\n      model: test-model\n      retry_attempts: 1\n"
+	}
+
+	tests := []struct {
+		name     string
+		repoPath string
+		config   string
+		offline  bool
+		want     map[string]CheckStatus
+		detail   map[string]string
+	}{
+		{
+			name:     "provider answers",
+			repoPath: repo.Dir,
+			config:   providerConfig(server.Endpoint()),
+			want: map[string]CheckStatus{
+				"Git": CheckPass, "Repository": CheckPass, "Git identity": CheckPass,
+				"Commit signing": CheckPass, "Configuration": CheckPass, "AI provider": CheckPass,
+			},
+			detail: map[string]string{
+				"Repository":   "(" + testutil.DefaultBranch + ")",
+				"Git identity": testutil.UserEmail,
+				"AI provider":  "local (test-model) answered",
+			},
+		},
+		{
+			name:     "provider fails",
+			repoPath: repo.Dir,
+			config:   providerConfig(failing.Endpoint()),
+			want:     map[string]CheckStatus{"Configuration": CheckPass, "AI provider": CheckFail},
+		},
+		{
+			name:     "offline",
+			repoPath: repo.Dir,
+			config:   providerConfig(failing.Endpoint()),
+			offline:  true,
+			want:     map[string]CheckStatus{"AI provider": CheckSkip},
+			detail:   map[string]string{"AI provider": "--offline"},
+		},
+		{
+			name:     "no provider",
+			repoPath: repo.Dir,
+			config:   "commit:\n  dco: true\n",
+			want:     map[string]CheckStatus{"Configuration": CheckPass, "AI provider": CheckWarn},
+		},
+		{
+			name:     "invalid config",
+			repoPath: repo.Dir,
+			config:   "commit:\n  dco: maybe\n",
+			want:     map[string]CheckStatus{"Configuration": CheckFail, "AI provider": CheckSkip},
+		},
+		{
+			name:     "unknown key",
+			repoPath: repo.Dir,
+			config:   "commit:\n  dco: true\n  scope: [api]\n",
+			want:     map[string]CheckStatus{"Configuration": CheckWarn, "AI provider": CheckWarn},
+			detail:   map[string]string{"Configuration": "commit.scope"},
+		},
+		{
+			name:     "outside a repository",
+			repoPath: t.TempDir(),
+			config:   "commit:\n  dco: true\n",
+			want: map[string]CheckStatus{
+				"Repository": CheckWarn, "Git identity": CheckSkip, "Commit signing": CheckSkip,
+				"Configuration": CheckPass,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doctor := NewDoctorService(tt.repoPath, writeDoctorConfig(t, tt.config), "", "", tt.offline)
+			checks := doctor.Run(context.Background())
+
+			for name, want := range tt.want {
+				if check := findCheck(t, checks, name); check.Status != want {
+					t.Errorf("%s: status = %v (%s), want %v", name, check.Status, check.Detail, want)
+				}
+			}
+			for name, want := range tt.detail {
+				if check := findCheck(t, checks, name); !strings.Contains(check.Detail, want) {
+					t.Errorf("%s: detail = %q, want it to contain %q", name, check.Detail, want)
+				}
+			}
+			for _, check := range checks {
+				if (check.Status == CheckWarn || check.Status == CheckFail) && check.Fix == "" {
+					t.Errorf("%s: %v without a fix", check.Name, check.Status)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/ui"
+)
+
+// dryRun reports whether the workflow only previews the commit (--dry-run)
+func (s *CommitService) dryRun() bool {
+	return s.options != nil && s.options.DryRun
+}
+
+// printCommitCreated reports the end of the workflow
+func (s *CommitService) printCommitCreated() {
+	if s.dryRun() {
+		fmt.Fprintln(ui.Out(), "✓ Dry run: no commit created, index unchanged")
+		return
+	}
+	if s.copyOnly() {
+		fmt.Fprintln(ui.Out(), "✓ Commit message copied to the clipboard: no commit created, changes left staged")
+		return
+	}
+	if s.amending() {
+		fmt.Fprintln(ui.Out(), "✓ Commit amended successfully")
+		return
+	}
+	fmt.Fprintln(ui.Out(), "✓ Commit created successfully")
+}
+
+// formatDryRun describes the commit a dry run would have created
+func formatDryRun(message string, files []model.FileChange) string {
+	var sb strings.Builder
+	sb.WriteString("\n--- Dry run: commit not created ---\n")
+	sb.WriteString(message)
+	sb.WriteString("\n\nFiles that would be committed:\n")
+	if len(files) == 0 {
+		sb.WriteString("  (none, empty commit)\n")
+	}
+	for _, file := range files {
+		sb.WriteString(fmt.Sprintf("  %-9s %s\n", file.Status, file.DisplayPath()))
+	}
+	sb.WriteString("---\n")
+	return sb.String()
+}
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+)
+
+func TestFormatDryRun(t *testing.T) {
+	tests := []struct {
+		name  string
+		files []model.FileChange
+		want  string
+	}{
+		{
+			name: "files",
+			files: []model.FileChange{
+				{Path: "internal/ui/prompts.go", Status: "modified"},
+				{Path: "docs/dry-run.md", Status: "added"},
+			},
+			want: "\n--- Dry run: commit not created ---\nfeat(ui): add dry run\n\nFiles that would be committed:\n" +
+				"  modified  internal/ui/prompts.go\n  added     docs/dry-run.md\n---\n",
+		},
+		{
+			name: "empty commit",
+			want: "\n--- Dry run: commit not created ---\nfeat(ui): add dry run\n\nFiles that would be committed:\n  (none, empty commit)\n---\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatDryRun("feat(ui): add dry run", tt.files); got != tt.want {
+				t.Errorf("formatDryRun() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
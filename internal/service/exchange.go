@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/golgoth31/gitcomm/internal/ai"
+	"github.com/golgoth31/gitcomm/internal/diagnostics"
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/ui"
+	"github.com/golgoth31/gitcomm/internal/utils"
+)
+
+// savedExchange is the file --save-exchange writes for a commit, for reviews of what is
+// sent to AI vendors and how the models answer
+type savedExchange struct {
+	Commit    string        `json:"commit"`
+	Time      time.Time     `json:"time"`
+	Provider  string        `json:"provider"`
+	Model     string        `json:"model,omitempty"`
+	Privacy   string        `json:"privacy"`
+	Message   string        `json:"message"`
+	Exchanges []ai.Exchange `json:"exchanges"`
+}
+
+// saveExchange writes the AI requests made for the commit of revision into the
+// --save-exchange directory as <hash>.json: the prompts with their secrets and email
+// addresses redacted, the raw answers, and the final message. Nothing is written when
+// the message was not generated.
+func (s *CommitService) saveExchange(ctx context.Context, message *model.CommitMessage, revision string) error {
+	exchanges := s.exchanges.Exchanges()
+	if len(exchanges) == 0 {
+		utils.Logger.Debug().Msg("No AI exchange to save")
+		return nil
+	}
+	for i := range exchanges {
+		exchanges[i].System = diagnostics.Redact(exchanges[i].System)
+		exchanges[i].User = diagnostics.Redact(exchanges[i].User)
+	}
+
+	commits, err := s.gitRepo.ListCommits(ctx, revision, 1)
+	if err != nil {
+		return err
+	}
+	if len(commits) != 1 {
+		return fmt.Errorf("commit %s not found", revision)
+	}
+	level, err := s.privacyLevel(ctx)
+	if err != nil {
+		return err
+	}
+
+	saved := savedExchange{
+		Commit:    commits[0].Hash,
+		Time:      time.Now(),
+		Provider:  s.providerName(),
+		Privacy:   string(level),
+		Message:   s.formatter.Format(message),
+		Exchanges: exchanges,
+	}
+	if s.config != nil {
+		saved.Model = s.config.AI.Providers[saved.Provider].Model
+	}
+	content, err := json.MarshalIndent(saved, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode AI exchange: %w", err)
+	}
+
+	dir := s.options.SaveExchangeDir
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create exchange directory %s: %w", dir, err)
+	}
+	path := filepath.Join(dir, saved.Commit+".json")
+	// The prompts hold the staged code: keep the file private
+	if err := os.WriteFile(path, append(content, '\n'), 0600); err != nil {
+		return fmt.Errorf("failed to write AI exchange: %w", err)
+	}
+	fmt.Fprintf(ui.Out(), "✓ AI exchange written to %s\n", path)
+	return nil
+}
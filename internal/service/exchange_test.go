@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/golgoth31/gitcomm/internal/config"
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/repository"
+	"github.com/golgoth31/gitcomm/internal/ui"
+	"github.com/golgoth31/gitcomm/pkg/testutil"
+)
+
+func TestCreateCommit_SaveExchange(t *testing.T) {
+	ui.SetNonInteractive(true)
+	defer ui.SetNonInteractive(false)
+
+	fixture := testutil.NewRepoWithCommit(t, map[string]string{"config.yaml": "name: app\n"})
+	fixture.WriteFile("config.yaml", "name: app\nowner: dev@example.com\napi_key: sk-literal\n")
+	fixture.Stage("config.yaml")
+	gitRepo, err := repository.NewGitRepository(fixture.Dir, true, true)
+	if err != nil {
+		t.Fatalf("NewGitRepository() error = %v", err)
+	}
+
+	server := testutil.NewProviderServer(t, "local", "feat(config): add the owner")
+	cfg := &config.Config{}
+	cfg.AI.DefaultProvider = "local"
+	cfg.AI.Providers = map[string]model.AIProviderConfig{"local": {Name: "local", Endpoint: server.Endpoint(), Model: "test-model", RetryAttempts: 1}}
+	dir := filepath.Join(t.TempDir(), "exchanges")
+
+	options := &model.CommitOptions{SaveExchangeDir: dir, NoSignoff: true}
+	if err := NewCommitService(gitRepo, options, cfg).CreateCommit(context.Background()); err != nil {
+		t.Fatalf("CreateCommit() error = %v", err)
+	}
+
+	head := strings.TrimSpace(fixture.Git("rev-parse", "HEAD"))
+	content, err := os.ReadFile(filepath.Join(dir, head+".json"))
+	if err != nil {
+		t.Fatalf("exchange file not written: %v", err)
+	}
+	var saved savedExchange
+	if err := json.Unmarshal(content, &saved); err != nil {
+		t.Fatalf("invalid exchange file: %v", err)
+	}
+
+	if saved.Commit != head || saved.Provider != "local" || saved.Model != "test-model" || saved.Privacy != string(model.PrivacyFullDiff) {
+		t.Errorf("saved = %+v, want commit %s of local/test-model with full-diff privacy", saved, head)
+	}
+	if !strings.HasPrefix(saved.Message, "feat(config): add the owner") {
+		t.Errorf("Message = %q, want the commit message", saved.Message)
+	}
+	if len(saved.Exchanges) != 1 {
+		t.Fatalf("got %d exchanges, want 1", len(saved.Exchanges))
+	}
+	exchange := saved.Exchanges[0]
+	if exchange.System == "" || !strings.Contains(exchange.User, "config.yaml") {
+		t.Errorf("prompt = %+v, want the system message and the staged diff", exchange)
+	}
+	if strings.Contains(exchange.User, "dev@example.com") || strings.Contains(exchange.User, "sk-literal") {
+		t.Errorf("user message not redacted:\n%s", exchange.User)
+	}
+	if exchange.Response != "feat(config): add the owner" {
+		t.Errorf("Response = %q, want the raw answer", exchange.Response)
+	}
+	if info, err := os.Stat(filepath.Join(dir, head+".json")); err == nil && info.Mode().Perm() != 0600 {
+		t.Errorf("exchange file mode = %v, want 0600", info.Mode().Perm())
+	}
+}
+
+func TestCreateCommit_SaveExchangeWithoutAI(t *testing.T) {
+	ui.SetNonInteractive(true)
+	defer ui.SetNonInteractive(false)
+
+	fixture := testutil.NewRepoWithCommit(t, map[string]string{"a.txt": "a\n"})
+	fixture.WriteFile("a.txt", "b\n")
+	fixture.Stage("a.txt")
+	gitRepo, err := repository.NewGitRepository(fixture.Dir, true, true)
+	if err != nil {
+		t.Fatalf("NewGitRepository() error = %v", err)
+	}
+
+	dir := filepath.Join(t.TempDir(), "exchanges")
+	options := &model.CommitOptions{Message: "fix: change a", SaveExchangeDir: dir, NoSignoff: true}
+	if err := NewCommitService(gitRepo, options, &config.Config{}).CreateCommit(context.Background()); err != nil {
+		t.Fatalf("CreateCommit() error = %v", err)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("exchange directory created without AI request (err = %v)", err)
+	}
+}
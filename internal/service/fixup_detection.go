@@ -0,0 +1,105 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+)
+
+// fixupSimilarityThreshold is the minimum word overlap between two subjects
+// for a new commit to be considered a follow-up of a recent one
+const fixupSimilarityThreshold = 0.8
+
+// fixupCandidateCount is the number of recent commits considered as fixup targets
+const fixupCandidateCount = 10
+
+// fixupIntentPrefixes are subject/type prefixes signalling unfinished or corrective work
+var fixupIntentPrefixes = []string{"wip", "fixup", "squash"}
+
+// detectFixupTarget returns the recent commit that message most likely fixes up, or nil.
+// A message has fixup intent when its type or subject starts with "wip", "fixup" or
+// "squash" (targeting the closest matching commit, or the most recent one), or when its
+// subject closely matches the subject of a recent commit. recent is ordered newest first.
+func detectFixupTarget(message *model.CommitMessage, recent []model.CommitInfo) *model.CommitInfo {
+	if message == nil || message.Fixup != nil || len(recent) == 0 {
+		return nil
+	}
+
+	subject := normalizeSubject(message.Subject)
+	var best *model.CommitInfo
+	bestScore := 0.0
+	for i := range recent {
+		score := subjectSimilarity(subject, normalizeSubject(recent[i].Subject()))
+		if score > bestScore {
+			best, bestScore = &recent[i], score
+		}
+	}
+	if bestScore >= fixupSimilarityThreshold {
+		return best
+	}
+
+	if hasFixupIntent(message) {
+		return &recent[0]
+	}
+	return nil
+}
+
+// hasFixupIntent reports whether the type or subject starts with a WIP/fixup marker
+func hasFixupIntent(message *model.CommitMessage) bool {
+	for _, field := range []string{message.Type, message.Subject} {
+		field = strings.ToLower(strings.TrimSpace(field))
+		for _, prefix := range fixupIntentPrefixes {
+			if field == prefix || strings.HasPrefix(field, prefix+"!") ||
+				strings.HasPrefix(field, prefix+":") || strings.HasPrefix(field, prefix+" ") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// normalizeSubject lowercases a subject and strips a Conventional Commits
+// "type(scope):" prefix and autosquash markers, so only the description is compared
+func normalizeSubject(subject string) string {
+	subject = strings.ToLower(strings.TrimSpace(subject))
+	for {
+		trimmed := strings.TrimPrefix(strings.TrimPrefix(subject, "fixup! "), "squash! ")
+		if trimmed == subject {
+			break
+		}
+		subject = trimmed
+	}
+	if conventionalHeaderRegex.MatchString(subject) {
+		_, subject, _ = strings.Cut(subject, ": ")
+	}
+	return strings.TrimSuffix(strings.TrimSpace(subject), ".")
+}
+
+// subjectSimilarity returns the Jaccard index of the word sets of a and b (0 to 1)
+func subjectSimilarity(a, b string) float64 {
+	wordsA := strings.Fields(a)
+	wordsB := strings.Fields(b)
+	if len(wordsA) == 0 || len(wordsB) == 0 {
+		return 0
+	}
+
+	set := make(map[string]bool, len(wordsA))
+	for _, w := range wordsA {
+		set[w] = true
+	}
+	union := len(set)
+	common := 0
+	seen := make(map[string]bool, len(wordsB))
+	for _, w := range wordsB {
+		if seen[w] {
+			continue
+		}
+		seen[w] = true
+		if set[w] {
+			common++
+		} else {
+			union++
+		}
+	}
+	return float64(common) / float64(union)
+}
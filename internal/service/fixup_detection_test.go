@@ -0,0 +1,104 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+)
+
+func TestDetectFixupTarget(t *testing.T) {
+	recent := []model.CommitInfo{
+		{Hash: "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", Message: "feat(ui): add pager for long previews"},
+		{Hash: "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb", Message: "fix(repository): handle detached HEAD\n\nBody"},
+	}
+
+	tests := []struct {
+		name     string
+		message  *model.CommitMessage
+		recent   []model.CommitInfo
+		wantHash string
+	}{
+		{
+			name:     "wip subject targets most recent commit",
+			message:  &model.CommitMessage{Type: "chore", Subject: "wip"},
+			recent:   recent,
+			wantHash: recent[0].Hash,
+		},
+		{
+			name:     "fixup type targets most recent commit",
+			message:  &model.CommitMessage{Type: "fixup", Subject: "typo"},
+			recent:   recent,
+			wantHash: recent[0].Hash,
+		},
+		{
+			name:     "WIP prefix is case insensitive",
+			message:  &model.CommitMessage{Type: "feat", Subject: "WIP: more work"},
+			recent:   recent,
+			wantHash: recent[0].Hash,
+		},
+		{
+			name:     "close match targets matching commit",
+			message:  &model.CommitMessage{Type: "fix", Scope: "repo", Subject: "handle detached HEAD"},
+			recent:   recent,
+			wantHash: recent[1].Hash,
+		},
+		{
+			name:     "wip with close match prefers matching commit",
+			message:  &model.CommitMessage{Type: "fix", Subject: "fixup! fix(repository): handle detached HEAD"},
+			recent:   recent,
+			wantHash: recent[1].Hash,
+		},
+		{
+			name:    "unrelated subject",
+			message: &model.CommitMessage{Type: "docs", Subject: "document pager configuration"},
+			recent:  recent,
+		},
+		{
+			name:    "word starting with wip is not intent",
+			message: &model.CommitMessage{Type: "feat", Subject: "wipe cache on logout"},
+			recent:  recent,
+		},
+		{
+			name:    "no recent commits",
+			message: &model.CommitMessage{Type: "chore", Subject: "wip"},
+		},
+		{
+			name:    "already a fixup",
+			message: &model.CommitMessage{Fixup: &recent[0]},
+			recent:  recent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := detectFixupTarget(tt.message, tt.recent)
+			if tt.wantHash == "" {
+				if got != nil {
+					t.Errorf("detectFixupTarget() = %s, want nil", got.Hash)
+				}
+				return
+			}
+			if got == nil || got.Hash != tt.wantHash {
+				t.Errorf("detectFixupTarget() = %v, want %s", got, tt.wantHash)
+			}
+		})
+	}
+}
+
+func TestSubjectSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"add pager", "add pager", 1},
+		{"add pager", "remove pager", 1.0 / 3},
+		{"", "add pager", 0},
+		{"add add pager", "add pager", 1},
+	}
+
+	for _, tt := range tests {
+		if got := subjectSimilarity(tt.a, tt.b); got != tt.want {
+			t.Errorf("subjectSimilarity(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -1,7 +1,6 @@
 package service
 
 import (
-	"fmt"
 	"strings"
 
 	"github.com/golgoth31/gitcomm/internal/model"
@@ -19,13 +18,8 @@ func NewFormattingService() *FormattingService {
 func (s *FormattingService) Format(message *model.CommitMessage) string {
 	var parts []string
 
-	// Format header: type(scope): subject
-	header := message.Type
-	if message.Scope != "" {
-		header = fmt.Sprintf("%s(%s)", header, message.Scope)
-	}
-	header = fmt.Sprintf("%s: %s", header, message.Subject)
-	parts = append(parts, header)
+	// Format header: type(scope): subject, or fixup! <subject>
+	parts = append(parts, message.Header())
 
 	// Add blank line before body if body exists
 	if message.Body != "" {
@@ -34,9 +28,9 @@ func (s *FormattingService) Format(message *model.CommitMessage) string {
 	}
 
 	// Add blank line before footer if footer exists
-	if message.Footer != "" {
+	if footer := message.FooterText(); footer != "" {
 		parts = append(parts, "")
-		parts = append(parts, message.Footer)
+		parts = append(parts, footer)
 	}
 
 	// Note: Signoff is handled separately during commit creation
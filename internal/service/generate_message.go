@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/ui"
+	"github.com/golgoth31/gitcomm/internal/utils"
+)
+
+// GenerateMessage generates a commit message for the staged changes and returns it
+// formatted, without staging files or creating a commit. The message comes from the AI
+// provider unless SkipAI is set, falling back to manual input when prompts are possible.
+func (s *CommitService) GenerateMessage(ctx context.Context) (string, error) {
+	utils.Logger.Debug().Msg("Starting message generation")
+
+	state, err := s.RepositoryState(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get repository state: %w", err)
+	}
+	// Only the staged changes end up in the commit the message is for
+	state.UnstagedFiles = nil
+	if len(state.StagedFiles) == 0 {
+		return "", utils.ErrNoChanges
+	}
+
+	message, err := s.composeMessage(ctx, state, false)
+	if err != nil {
+		return "", err
+	}
+
+	// Sign-off is left to the committing tool (git commit -s)
+	return s.formatter.Format(message), nil
+}
+
+// composeMessage produces a validated message for the staged changes in state: from the
+// AI provider unless SkipAI is set, falling back to manual input when prompts are possible.
+// footers shows the generated message with the configured footers, for a message that is
+// committed.
+func (s *CommitService) composeMessage(ctx context.Context, state *model.RepositoryState, footers bool) (*model.CommitMessage, error) {
+	s.scopes = s.scopeCandidates(ctx, state)
+	s.branch = state.Branch
+	s.ticket = state.Ticket
+	s.staged = state.StagedFiles
+
+	var message *model.CommitMessage
+	var err error
+	if s.options == nil || !s.options.SkipAI {
+		message, err = s.generateMessageWithAI(ctx, state, footers)
+		if err != nil {
+			if ui.NonInteractive() {
+				return nil, fmt.Errorf("AI generation failed and manual input is not possible in non-interactive mode: %w", err)
+			}
+			ui.PrintError("AI generation failed", err)
+			fmt.Fprintln(ui.Out(), "Falling back to manual input...")
+		}
+	}
+
+	if message == nil {
+		if ui.NonInteractive() {
+			return nil, fmt.Errorf("manual input is not possible in non-interactive mode: %w", utils.ErrNonInteractive)
+		}
+		message, err = s.promptCommitMessage(nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to prompt for commit message: %w", err)
+		}
+	}
+
+	if valid, errs := s.validator.Validate(message); !valid {
+		details := make([]string, 0, len(errs))
+		for _, e := range errs {
+			details = append(details, fmt.Sprintf("%s: %s", e.Field, e.Message))
+		}
+		if ui.NonInteractive() {
+			return nil, fmt.Errorf("%w: %s", utils.ErrInvalidFormat, strings.Join(details, "; "))
+		}
+		printValidationErrors(message, errs)
+		confirm, err := ui.PromptConfirm(s.reader, "Use it anyway?", false)
+		if err != nil || !confirm {
+			return nil, utils.ErrInvalidFormat
+		}
+	}
+	return message, nil
+}
+
+// generateMessageWithAI asks the AI provider for a message for state. Interactively,
+// the message is shown (with the configured footers when footers is set) and can be
+// edited before it is used.
+func (s *CommitService) generateMessageWithAI(ctx context.Context, state *model.RepositoryState, footers bool) (*model.CommitMessage, error) {
+	s.addSessionContext(ctx, state)
+	message, aiMessage, err := s.requestAIMessage(ctx, state)
+	if err != nil {
+		return nil, err
+	}
+	if footers {
+		message = s.withFooters(ctx, message)
+	}
+	if ui.NonInteractive() {
+		return message, nil
+	}
+
+	fmt.Fprintf(ui.Out(), "\n--- Generated Message ---\n%s\n---\n", ui.DisplayCommitMessage(message))
+	use, err := ui.PromptConfirm(s.reader, "Use this message?", true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prompt for confirmation: %w", err)
+	}
+	if use {
+		return message, nil
+	}
+
+	prefilled := s.parseAIMessageToPrefilled(aiMessage)
+	prefilled.TypeConfirmed = typeMatchesInference(prefilled.Type, state)
+	return s.promptCommitMessage(&prefilled)
+}
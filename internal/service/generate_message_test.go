@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/golgoth31/gitcomm/internal/config"
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/repository"
+	"github.com/golgoth31/gitcomm/internal/ui"
+	"github.com/golgoth31/gitcomm/internal/utils"
+	"github.com/golgoth31/gitcomm/pkg/testutil"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestGenerateMessage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"choices": [{"message": {"content": "feat(api): add health endpoint\n\nLoad balancers need a probe."}}]}`))
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{}
+	cfg.AI.Providers = map[string]model.AIProviderConfig{"local": {Endpoint: server.URL}}
+
+	ui.SetNonInteractive(true)
+	defer ui.SetNonInteractive(false)
+
+	tests := []struct {
+		name    string
+		stage   bool
+		skipAI  bool
+		want    string
+		wantErr error
+	}{
+		{"AI message for staged changes", true, false, "feat(api): add health endpoint\n\nLoad balancers need a probe.", nil},
+		{"nothing staged", false, false, "", utils.ErrNoChanges},
+		{"manual input without prompts", true, true, "", utils.ErrNonInteractive},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fixture := initMessageRepo(t, tt.stage)
+			gitRepo, err := repository.NewGitRepository(fixture.Dir, true, true)
+			if err != nil {
+				t.Fatalf("NewGitRepository() error = %v", err)
+			}
+			options := &model.CommitOptions{AIProvider: "local", SkipAI: tt.skipAI}
+
+			got, err := NewCommitService(gitRepo, options, cfg).GenerateMessage(context.Background())
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("GenerateMessage() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GenerateMessage() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("GenerateMessage() = %q, want %q", got, tt.want)
+			}
+
+			// Nothing is committed and the index is left as it was
+			if out := fixture.Git("rev-list", "--all"); out != "" {
+				t.Errorf("commits created: %q", out)
+			}
+			if out := fixture.Git("diff", "--cached", "--name-only"); out != "api.go\n" {
+				t.Errorf("staged files = %q, want api.go", out)
+			}
+		})
+	}
+}
+
+// initMessageRepo creates a repository with a changed api.go and notes.txt, staging
+// api.go when stage is set
+func initMessageRepo(t *testing.T, stage bool) *testutil.Repo {
+	t.Helper()
+	repo := testutil.NewRepo(t)
+	for _, name := range []string{"api.go", "notes.txt"} {
+		repo.WriteFile(name, "package api\n")
+	}
+	if stage {
+		repo.Stage("api.go")
+	}
+	return repo
+}
+
+func TestGenerateMessage_TracesWorkflow(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"choices": [{"message": {"content": "feat(api): add health endpoint"}}]}`))
+	}))
+	defer server.Close()
+
+	original := otel.GetTracerProvider()
+	defer otel.SetTracerProvider(original)
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	cfg := &config.Config{}
+	cfg.AI.Providers = map[string]model.AIProviderConfig{"local": {Endpoint: server.URL}}
+	ui.SetNonInteractive(true)
+	defer ui.SetNonInteractive(false)
+
+	gitRepo, err := repository.NewGitRepository(initMessageRepo(t, true).Dir, true, true)
+	if err != nil {
+		t.Fatalf("NewGitRepository() error = %v", err)
+	}
+	options := &model.CommitOptions{AIProvider: "local"}
+	if _, err := NewCommitService(gitRepo, options, cfg).GenerateMessage(context.Background()); err != nil {
+		t.Fatalf("GenerateMessage() error = %v", err)
+	}
+
+	var names []string
+	for _, span := range recorder.Ended() {
+		names = append(names, span.Name())
+		if span.Name() == "generate message" {
+			for _, attr := range span.Attributes() {
+				if attr.Key == "gitcomm.ai.provider" && attr.Value.AsString() != "local" {
+					t.Errorf("gitcomm.ai.provider = %q, want local", attr.Value.AsString())
+				}
+			}
+		}
+	}
+	if want := []string{"collect repository state", "generate message"}; !slices.Equal(names, want) {
+		t.Errorf("spans = %v, want %v", names, want)
+	}
+}
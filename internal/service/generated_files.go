@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/ui"
+	"github.com/golgoth31/gitcomm/pkg/generated"
+)
+
+// generatedPairs returns the staged files generated from other staged files
+func generatedPairs(files []model.FileChange) []generated.Pair {
+	paths := make([]string, 0, len(files))
+	for _, file := range files {
+		if file.Status != "deleted" {
+			paths = append(paths, file.Path)
+		}
+	}
+	return generated.Find(paths)
+}
+
+// reviewGeneratedFiles warns when files generated from other staged files are staged too,
+// as they are often committed by accident, and offers to unstage each of them (without
+// prompts, they stay staged). Reports whether files were unstaged.
+func (s *CommitService) reviewGeneratedFiles(ctx context.Context, state *model.RepositoryState) (bool, error) {
+	if s.config == nil || !s.config.Commit.CheckGenerated {
+		return false, nil
+	}
+	pairs := generatedPairs(state.StagedFiles)
+	if len(pairs) == 0 {
+		return false, nil
+	}
+
+	fmt.Fprintln(ui.Out(), "Warning: generated files are staged with their sources:")
+	for _, pair := range pairs {
+		fmt.Fprintf(ui.Out(), "  - %s (from %s)\n", pair.Generated, pair.Source)
+	}
+	if ui.NonInteractive() {
+		return false, nil
+	}
+
+	var unstage []string
+	for _, pair := range pairs {
+		keep, err := ui.PromptConfirm(s.reader, fmt.Sprintf("Commit %s?", pair.Generated), true)
+		if err != nil {
+			return false, fmt.Errorf("failed to prompt for generated file: %w", err)
+		}
+		if !keep {
+			unstage = append(unstage, pair.Generated)
+		}
+	}
+	if len(unstage) == 0 {
+		return false, nil
+	}
+
+	if err := s.gitRepo.UnstageFiles(ctx, unstage); err != nil {
+		return false, fmt.Errorf("failed to unstage generated files: %w", err)
+	}
+	return true, nil
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golgoth31/gitcomm/internal/config"
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/repository"
+	"github.com/golgoth31/gitcomm/internal/ui"
+	"github.com/golgoth31/gitcomm/pkg/testutil"
+)
+
+func TestReviewGeneratedFiles_KeepsFilesWithoutPrompts(t *testing.T) {
+	ui.SetNonInteractive(true)
+	defer ui.SetNonInteractive(false)
+
+	fixture := testutil.NewRepo(t)
+	fixture.WriteFile("src/app.ts", "export const a = 1\n")
+	fixture.WriteFile("dist/app.js", "exports.a = 1\n")
+	fixture.Stage("src/app.ts", "dist/app.js")
+
+	gitRepo, err := repository.NewGitRepository(fixture.Dir, true, true)
+	if err != nil {
+		t.Fatalf("NewGitRepository() error = %v", err)
+	}
+	state := &model.RepositoryState{StagedFiles: []model.FileChange{
+		{Path: "dist/app.js", Status: "added"},
+		{Path: "src/app.ts", Status: "added"},
+	}}
+
+	for _, check := range []bool{true, false} {
+		cfg := &config.Config{Commit: config.CommitConfig{CheckGenerated: check}}
+		unstaged, err := NewCommitService(gitRepo, &model.CommitOptions{}, cfg).reviewGeneratedFiles(context.Background(), state)
+		if err != nil || unstaged {
+			t.Errorf("reviewGeneratedFiles(check %v) = %v, %v, want false, nil", check, unstaged, err)
+		}
+	}
+	if got := fixture.Git("diff", "--cached", "--name-only"); got != "dist/app.js\nsrc/app.ts\n" {
+		t.Errorf("staged = %q, want both files", got)
+	}
+}
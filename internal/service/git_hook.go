@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/golgoth31/gitcomm/internal/repository"
+	"github.com/golgoth31/gitcomm/internal/utils"
+)
+
+// PrepareCommitMsgHook is the git hook gitcomm installs to draft the messages of git commit
+const PrepareCommitMsgHook = "prepare-commit-msg"
+
+// hookMarker identifies the hooks installed by gitcomm
+const hookMarker = "# Installed by gitcomm hook install"
+
+// hookScript runs gitcomm hook prepare-commit-msg, leaving the commit alone when gitcomm
+// is not in the PATH (e.g. in a GUI client)
+const hookScript = `#!/bin/sh
+` + hookMarker + `: drafts the commit message of git commit.
+# Remove it with gitcomm hook uninstall.
+command -v gitcomm >/dev/null 2>&1 || exit 0
+exec gitcomm hook prepare-commit-msg "$@"
+`
+
+// draftSources are the prepare-commit-msg sources for which gitcomm drafts a message: a
+// plain git commit ("") or one with a commit template. Messages given with -m/-F, merges,
+// squashes and -c/-C/--amend already have their message.
+var draftSources = []string{"", "template"}
+
+// InstallHook installs the prepare-commit-msg hook in the hooks directory of gitRepo
+// (core.hooksPath when set) and returns its path. A hook not installed by gitcomm is only
+// replaced when force is set.
+func InstallHook(ctx context.Context, gitRepo repository.GitRepository, force bool) (string, error) {
+	path, err := hookPath(ctx, gitRepo)
+	if err != nil {
+		return "", err
+	}
+	if !force {
+		if installed, err := installedByGitcomm(path); err != nil {
+			return "", err
+		} else if !installed {
+			return "", fmt.Errorf("%s: %w", path, utils.ErrForeignHook)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("failed to create hooks directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(hookScript), 0755); err != nil {
+		return "", fmt.Errorf("failed to write %s hook: %w", PrepareCommitMsgHook, err)
+	}
+	// WriteFile keeps the mode of an existing file
+	if err := os.Chmod(path, 0755); err != nil {
+		return "", fmt.Errorf("failed to make %s hook executable: %w", PrepareCommitMsgHook, err)
+	}
+	return path, nil
+}
+
+// UninstallHook removes the prepare-commit-msg hook installed by InstallHook and returns
+// its path; a hook not installed by gitcomm is left in place
+func UninstallHook(ctx context.Context, gitRepo repository.GitRepository) (string, error) {
+	path, err := hookPath(ctx, gitRepo)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("no %s hook in %s", PrepareCommitMsgHook, filepath.Dir(path))
+	}
+	if installed, err := installedByGitcomm(path); err != nil {
+		return "", err
+	} else if !installed {
+		return "", fmt.Errorf("%s: %w", path, utils.ErrForeignHook)
+	}
+	if err := os.Remove(path); err != nil {
+		return "", fmt.Errorf("failed to remove %s hook: %w", PrepareCommitMsgHook, err)
+	}
+	return path, nil
+}
+
+// hookPath returns the path of the prepare-commit-msg hook of gitRepo
+func hookPath(ctx context.Context, gitRepo repository.GitRepository) (string, error) {
+	dir, err := gitRepo.HooksDir(ctx)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, PrepareCommitMsgHook), nil
+}
+
+// installedByGitcomm reports whether the hook at path is missing or was installed by
+// gitcomm, i.e. whether it can be replaced
+func installedByGitcomm(path string) (bool, error) {
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read %s hook: %w", PrepareCommitMsgHook, err)
+	}
+	return strings.Contains(string(content), hookMarker), nil
+}
+
+// PrepareCommitMessage drafts the message of git commit from the prepare-commit-msg hook
+// arguments: msgFile, the message file git opens in the editor, and source, the origin
+// of its content. The generated message is written above the content git put in the
+// file (its comments, or the commit template). It reports whether a draft was written:
+// nothing is done for messages given with -m/-F, merges, squashes and amends.
+func (s *CommitService) PrepareCommitMessage(ctx context.Context, msgFile, source string) (bool, error) {
+	if !slices.Contains(draftSources, source) {
+		utils.Logger.Debug().Str("source", source).Msg("Commit message already given, no draft")
+		return false, nil
+	}
+	existing, err := os.ReadFile(msgFile)
+	if err != nil {
+		return false, fmt.Errorf("failed to read message file: %w", err)
+	}
+
+	message, err := s.GenerateMessage(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	draft := message + "\n"
+	if len(existing) > 0 {
+		if existing[0] != '\n' {
+			draft += "\n"
+		}
+		draft += string(existing)
+	}
+	if err := os.WriteFile(msgFile, []byte(draft), 0644); err != nil {
+		return false, fmt.Errorf("failed to write message file: %w", err)
+	}
+	return true, nil
+}
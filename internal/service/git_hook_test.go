@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golgoth31/gitcomm/internal/config"
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/repository"
+	"github.com/golgoth31/gitcomm/internal/ui"
+	"github.com/golgoth31/gitcomm/internal/utils"
+	"github.com/golgoth31/gitcomm/pkg/testutil"
+)
+
+func TestCommitService_PrepareCommitMessage(t *testing.T) {
+	server := testutil.NewProviderServer(t, "local", "feat(api): add health endpoint")
+	cfg := &config.Config{}
+	cfg.AI.Providers = map[string]model.AIProviderConfig{"local": {Endpoint: server.Endpoint(), RetryAttempts: 1}}
+
+	ui.SetNonInteractive(true)
+	defer ui.SetNonInteractive(false)
+
+	const comments = "\n# Please enter the commit message for your changes.\n"
+	tests := []struct {
+		name    string
+		stage   bool
+		source  string
+		content string
+		want    string
+		wantErr error
+	}{
+		{"plain git commit", true, "", comments, "feat(api): add health endpoint\n" + comments, nil},
+		{"commit template", true, "template", "Refs: #\n", "feat(api): add health endpoint\n\nRefs: #\n", nil},
+		{"message given with -m", true, "message", "fix: typo\n", "fix: typo\n", nil},
+		{"merge", true, "merge", "Merge branch 'dev'\n", "Merge branch 'dev'\n", nil},
+		{"amend", true, "commit", "fix: typo\n", "fix: typo\n", nil},
+		{"nothing staged", false, "", comments, comments, utils.ErrNoChanges},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fixture := initMessageRepo(t, tt.stage)
+			gitRepo, err := repository.NewGitRepository(fixture.Dir, true, true)
+			if err != nil {
+				t.Fatalf("NewGitRepository() error = %v", err)
+			}
+			msgFile := filepath.Join(t.TempDir(), "COMMIT_EDITMSG")
+			if err := os.WriteFile(msgFile, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			service := NewCommitService(gitRepo, &model.CommitOptions{AIProvider: "local"}, cfg)
+			drafted, err := service.PrepareCommitMessage(context.Background(), msgFile, tt.source)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("PrepareCommitMessage() error = %v, want %v", err, tt.wantErr)
+			}
+			if drafted != (tt.want != tt.content) {
+				t.Errorf("PrepareCommitMessage() drafted = %v", drafted)
+			}
+			got, err := os.ReadFile(msgFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("message file = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInstallHook(t *testing.T) {
+	fixture := testutil.NewRepo(t)
+	gitRepo, err := repository.NewGitRepository(fixture.Dir, true, true)
+	if err != nil {
+		t.Fatalf("NewGitRepository() error = %v", err)
+	}
+	ctx := context.Background()
+	hooks := filepath.Join(fixture.Dir, "custom-hooks")
+	fixture.Git("config", "core.hooksPath", hooks)
+	want := filepath.Join(hooks, PrepareCommitMsgHook)
+
+	// Installing twice replaces the hook gitcomm installed
+	for range 2 {
+		path, err := InstallHook(ctx, gitRepo, false)
+		if err != nil {
+			t.Fatalf("InstallHook() error = %v", err)
+		}
+		if path != want {
+			t.Errorf("InstallHook() = %q, want %q", path, want)
+		}
+	}
+	if info, err := os.Stat(want); err != nil || info.Mode().Perm()&0100 == 0 {
+		t.Fatalf("hook not executable: %v %v", info, err)
+	}
+
+	if _, err := UninstallHook(ctx, gitRepo); err != nil {
+		t.Fatalf("UninstallHook() error = %v", err)
+	}
+	if _, err := os.Stat(want); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("hook still present: %v", err)
+	}
+	if _, err := UninstallHook(ctx, gitRepo); err == nil {
+		t.Error("UninstallHook() without a hook succeeded")
+	}
+
+	// A hook of another tool is kept unless forced
+	if err := os.WriteFile(want, []byte("#!/bin/sh\nexit 0\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := InstallHook(ctx, gitRepo, false); !errors.Is(err, utils.ErrForeignHook) {
+		t.Errorf("InstallHook() error = %v, want ErrForeignHook", err)
+	}
+	if _, err := UninstallHook(ctx, gitRepo); !errors.Is(err, utils.ErrForeignHook) {
+		t.Errorf("UninstallHook() error = %v, want ErrForeignHook", err)
+	}
+	if _, err := InstallHook(ctx, gitRepo, true); err != nil {
+		t.Fatalf("InstallHook(force) error = %v", err)
+	}
+	if info, err := os.Stat(want); err != nil || info.Mode().Perm()&0100 == 0 {
+		t.Errorf("forced hook not executable: %v %v", info, err)
+	}
+}
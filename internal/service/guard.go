@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/repository"
+	"github.com/golgoth31/gitcomm/internal/ui"
+	"github.com/golgoth31/gitcomm/internal/utils"
+)
+
+// ConfirmationToken returns the token confirming action on head in non-interactive mode,
+// e.g. undo-1a2b3c4. It changes with HEAD, so a script cannot replay a stale confirmation.
+func ConfirmationToken(action string, head *model.CommitInfo) string {
+	return action + "-" + head.ShortHash()
+}
+
+// requireConfirmation refuses a history rewrite in non-interactive mode unless confirmation
+// is the name of the current branch or the confirmation token of action. Prompts confirm
+// the rewrite otherwise, so nothing is required.
+func requireConfirmation(ctx context.Context, gitRepo repository.GitRepository, action string, head *model.CommitInfo, confirmation string) error {
+	if !ui.NonInteractive() {
+		return nil
+	}
+	token := ConfirmationToken(action, head)
+	branch, err := gitRepo.GetCurrentBranch(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read the current branch: %w", err)
+	}
+	if confirmation != "" && (confirmation == token || confirmation == branch) {
+		return nil
+	}
+	if branch == "" {
+		return fmt.Errorf("%w: pass --confirm %s", utils.ErrConfirmationRequired, token)
+	}
+	return fmt.Errorf("%w: pass --confirm %s or --confirm %s", utils.ErrConfirmationRequired, branch, token)
+}
+
+// printRecovery prints the reflog entry restoring head once a history rewrite moved HEAD.
+// HEAD@{1} is gone when the rewrite left HEAD unborn, hence the hash as well.
+func printRecovery(mode string, head *model.CommitInfo) {
+	fmt.Fprintf(ui.Out(), "To recover the current state afterwards: git reset %s HEAD@{1} (or git reset %s %s)\n", mode, mode, head.ShortHash())
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/repository"
+	"github.com/golgoth31/gitcomm/internal/ui"
+	"github.com/golgoth31/gitcomm/internal/utils"
+	"github.com/golgoth31/gitcomm/pkg/testutil"
+)
+
+func TestRequireConfirmation(t *testing.T) {
+	tests := []struct {
+		name           string
+		interactive    bool
+		detached       bool
+		confirmation   string // "token" stands for the confirmation token of HEAD
+		wantErr        bool
+		wantSuggestion string
+	}{
+		{name: "prompts confirm interactively", interactive: true},
+		{name: "branch name", confirmation: testutil.DefaultBranch},
+		{name: "token", confirmation: "token"},
+		{name: "missing", wantErr: true, wantSuggestion: "--confirm " + testutil.DefaultBranch + " or --confirm flush-"},
+		{name: "token of another action", confirmation: "undo-", wantErr: true},
+		{name: "detached HEAD with token", detached: true, confirmation: "token"},
+		{name: "detached HEAD with empty branch name", detached: true, wantErr: true, wantSuggestion: "pass --confirm flush-"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ui.SetNonInteractive(!tt.interactive)
+			defer ui.SetNonInteractive(false)
+
+			fixture := testutil.NewRepoWithCommit(t, map[string]string{"api.go": "package api\n"})
+			head := &model.CommitInfo{Hash: fixture.Head()}
+			if tt.detached {
+				fixture.Git("checkout", "-q", "--detach")
+			}
+			gitRepo, err := repository.NewGitRepository(fixture.Dir, true, true)
+			if err != nil {
+				t.Fatalf("NewGitRepository() error = %v", err)
+			}
+
+			confirmation := tt.confirmation
+			switch confirmation {
+			case "token":
+				confirmation = ConfirmationToken("flush", head)
+			case "undo-":
+				confirmation = ConfirmationToken("undo", head)
+			}
+			err = requireConfirmation(context.Background(), gitRepo, "flush", head, confirmation)
+			if tt.wantErr {
+				if !errors.Is(err, utils.ErrConfirmationRequired) {
+					t.Fatalf("requireConfirmation() error = %v, want %v", err, utils.ErrConfirmationRequired)
+				}
+				if !strings.Contains(err.Error(), tt.wantSuggestion+head.ShortHash()) {
+					t.Errorf("requireConfirmation() error = %q, want it to suggest %q", err, tt.wantSuggestion+head.ShortHash())
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("requireConfirmation() error = %v", err)
+			}
+		})
+	}
+}
+
+// The reflog entry printed by printRecovery restores the history before a reword
+func TestRecovery_RestoresRewordedHistory(t *testing.T) {
+	fixture := testutil.NewRepoWithCommit(t, map[string]string{"api.go": "package api\n"})
+	fixture.CommitFiles("chore: queued commit awaiting message", map[string]string{"api.go": "package api\n\nfunc Health() {}\n"})
+	head := fixture.Head()
+	gitRepo, err := repository.NewGitRepository(fixture.Dir, true, true)
+	if err != nil {
+		t.Fatalf("NewGitRepository() error = %v", err)
+	}
+
+	messages := map[string]*model.CommitMessage{head: {Type: "feat", Scope: "api", Subject: "add health"}}
+	if _, err := gitRepo.RewordCommits(context.Background(), messages); err != nil {
+		t.Fatalf("RewordCommits() error = %v", err)
+	}
+	fixture.Git("reset", "-q", "--soft", "HEAD@{1}")
+	if got := fixture.Head(); got != head {
+		t.Errorf("HEAD after recovery = %s, want %s", got, head)
+	}
+}
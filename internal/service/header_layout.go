@@ -0,0 +1,64 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/golgoth31/gitcomm/internal/config"
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/utils"
+)
+
+// headerLayout returns the header layout of commit.header_format, nil for the default
+// type(scope): subject. An invalid format falls back to the default (reported by config
+// validation).
+func headerLayout(cfg *config.Config) *model.HeaderLayout {
+	if cfg == nil || cfg.Commit.HeaderFormat == "" {
+		return nil
+	}
+	layout, err := model.ParseHeaderLayout(cfg.Commit.HeaderFormat)
+	if err != nil {
+		utils.Logger.Debug().Err(err).Msg("Invalid commit.header_format, using type(scope): subject")
+		return nil
+	}
+	return layout
+}
+
+// withLayout applies the header layout to message; a header missing its ticket gets the
+// ticket of the branch
+func (s *CommitService) withLayout(message *model.CommitMessage) *model.CommitMessage {
+	message.Layout = s.layout
+	if s.layout.HasTicket() && message.Ticket == "" {
+		message.Ticket = s.ticket
+	}
+	return message
+}
+
+// parseHeader parses the first line of a message into its type, scope, ticket, subject
+// and breaking change mark: in the header layout when it matches, else as type(scope): subject
+func (s *CommitService) parseHeader(header string) (*model.CommitMessage, bool) {
+	if s.layout != nil {
+		if message, ok := s.layout.Match(header); ok {
+			return message, true
+		}
+	}
+
+	parts := strings.SplitN(header, ":", 2)
+	if len(parts) != 2 {
+		return nil, false
+	}
+	message := &model.CommitMessage{Subject: strings.TrimSpace(parts[1])}
+	typeScope := strings.TrimSpace(parts[0])
+	if trimmed, ok := strings.CutSuffix(typeScope, "!"); ok {
+		message.Breaking = true
+		typeScope = trimmed
+	}
+	if strings.Contains(typeScope, "(") && strings.Contains(typeScope, ")") {
+		openIdx := strings.Index(typeScope, "(")
+		closeIdx := strings.Index(typeScope, ")")
+		message.Type = strings.TrimSpace(typeScope[:openIdx])
+		message.Scope = strings.TrimSpace(typeScope[openIdx+1 : closeIdx])
+	} else {
+		message.Type = strings.TrimSpace(typeScope)
+	}
+	return message, true
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/golgoth31/gitcomm/internal/ai"
+	"github.com/golgoth31/gitcomm/internal/store"
+	"github.com/golgoth31/gitcomm/internal/utils"
+	"github.com/golgoth31/gitcomm/pkg/tokenization"
+)
+
+// SetStore records the commits created with an AI generated message and the tokens of
+// every provider request in st (history.enabled)
+func (s *CommitService) SetStore(st store.Store) {
+	s.store = st
+}
+
+// usageLog returns the exchange log the provider requests are counted from: the one of
+// --save-exchange, or a new one when only the store needs it (nil without either)
+func (s *CommitService) usageLog() *ai.ExchangeLog {
+	if s.exchanges != nil {
+		return s.exchanges
+	}
+	if s.store != nil {
+		return &ai.ExchangeLog{}
+	}
+	return nil
+}
+
+// recordUsage records the token counts of exchanges in the store. The store failing
+// never fails the workflow.
+func (s *CommitService) recordUsage(ctx context.Context, exchanges []ai.Exchange) {
+	if s.store == nil || len(exchanges) == 0 {
+		return
+	}
+	provider, modelName := s.providerName(), s.modelName()
+	calc := tokenization.NewTokenCalculatorForModel(provider, modelName)
+	repository := s.repositoryPath(ctx)
+	now := time.Now()
+	for _, exchange := range exchanges {
+		usage := store.Usage{
+			Repository:   repository,
+			Provider:     provider,
+			Model:        modelName,
+			PromptTokens: calc.Calculate(exchange.System) + calc.Calculate(exchange.User),
+			AnswerTokens: calc.Calculate(exchange.Response),
+			CreatedAt:    now,
+		}
+		if err := s.store.RecordUsage(ctx, usage); err != nil {
+			utils.Logger.Debug().Err(err).Msg("Failed to record provider usage")
+			return
+		}
+	}
+}
+
+// recordHistory records the commit just created at revision in the store when its message
+// was generated by the AI provider. The store failing never fails the commit.
+func (s *CommitService) recordHistory(ctx context.Context, revision string) {
+	if s.store == nil || s.assistedBy == "" {
+		return
+	}
+	commits, err := s.gitRepo.ListCommits(ctx, revision, 1)
+	if err != nil || len(commits) == 0 {
+		utils.Logger.Debug().Err(err).Str("revision", revision).Msg("Failed to resolve the created commit")
+		return
+	}
+	commit := store.Commit{
+		Repository: s.repositoryPath(ctx),
+		Hash:       commits[0].Hash,
+		Subject:    commits[0].Subject(),
+		Provider:   s.providerName(),
+		Model:      s.modelName(),
+		CreatedAt:  time.Now(),
+	}
+	if err := s.store.RecordCommit(ctx, commit); err != nil {
+		utils.Logger.Debug().Err(err).Msg("Failed to record commit history")
+	}
+}
+
+// modelName returns the configured model of the selected provider ("" for its default)
+func (s *CommitService) modelName() string {
+	if s.config == nil {
+		return ""
+	}
+	return s.config.AI.Providers[s.providerName()].Model
+}
+
+// repositoryPath returns the root of the worktree, "" when unknown
+func (s *CommitService) repositoryPath(ctx context.Context) string {
+	if s.gitRepo == nil {
+		return ""
+	}
+	dir, err := s.gitRepo.WorkTreeDir(ctx)
+	if err != nil {
+		utils.Logger.Debug().Err(err).Msg("Failed to get worktree directory")
+		return ""
+	}
+	return dir
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golgoth31/gitcomm/internal/config"
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/repository"
+	"github.com/golgoth31/gitcomm/internal/store"
+	"github.com/golgoth31/gitcomm/internal/ui"
+)
+
+func TestCreateCommit_RecordsHistory(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"choices": [{"message": {"content": "feat(api): add health endpoint"}}]}`))
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{}
+	cfg.AI.Providers = map[string]model.AIProviderConfig{"local": {Endpoint: server.URL, Model: "tiny"}}
+	ui.SetNonInteractive(true)
+	defer ui.SetNonInteractive(false)
+
+	tests := []struct {
+		name        string
+		message     string
+		wantCommits int
+		wantUsage   int
+	}{
+		{"AI message", "", 1, 1},
+		{"message written by hand", "fix(api): handle errors", 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fixture := initMessageRepo(t, true)
+			gitRepo, err := repository.NewGitRepository(fixture.Dir, true, true)
+			if err != nil {
+				t.Fatalf("NewGitRepository() error = %v", err)
+			}
+			history := store.NewMemory()
+			options := &model.CommitOptions{AIProvider: "local", Message: tt.message, NoSignoff: true}
+			service := NewCommitService(gitRepo, options, cfg)
+			service.SetStore(history)
+
+			if err := service.CreateCommit(context.Background()); err != nil {
+				t.Fatalf("CreateCommit() error = %v", err)
+			}
+
+			ctx := context.Background()
+			commits, _ := history.Commits(ctx, time.Time{})
+			if len(commits) != tt.wantCommits {
+				t.Fatalf("recorded commits = %+v, want %d", commits, tt.wantCommits)
+			}
+			totals, _ := history.UsageTotals(ctx, time.Time{})
+			requests := 0
+			for _, total := range totals {
+				requests += total.Requests
+			}
+			if requests != tt.wantUsage {
+				t.Errorf("recorded requests = %+v, want %d", totals, tt.wantUsage)
+			}
+			if tt.wantCommits == 0 {
+				return
+			}
+
+			root, _ := gitRepo.WorkTreeDir(ctx)
+			commit := commits[0]
+			if commit.Hash != strings.TrimSpace(fixture.Git("rev-parse", "HEAD")) || commit.Subject != "feat(api): add health endpoint" ||
+				commit.Provider != "local" || commit.Model != "tiny" || commit.Repository != root {
+				t.Errorf("recorded commit = %+v, want HEAD of %s generated by local/tiny", commit, root)
+			}
+			if totals[0].PromptTokens == 0 || totals[0].AnswerTokens == 0 {
+				t.Errorf("recorded usage = %+v, want the tokens of the request and the answer", totals[0])
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"cmp"
+	"context"
+
+	"github.com/golgoth31/gitcomm/internal/hooks"
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/ui"
+	"github.com/golgoth31/gitcomm/internal/utils"
+)
+
+// hookRunner returns the runner of the configured hook scripts (nil without configuration)
+func (s *CommitService) hookRunner() *hooks.Runner {
+	if s.config == nil {
+		return nil
+	}
+	h := s.config.Hooks
+	return hooks.NewRunner(map[hooks.Event][]string{
+		hooks.PreGenerate:  h.PreGenerate,
+		hooks.PostGenerate: h.PostGenerate,
+		hooks.PreCommit:    h.PreCommit,
+		hooks.PostCommit:   h.PostCommit,
+	}, h.Timeout)
+}
+
+// runHook runs the scripts of payload.Event. Failures of pre- scripts are returned to stop
+// the workflow; failures of post- scripts are only reported.
+func (s *CommitService) runHook(ctx context.Context, payload hooks.Payload) error {
+	err := s.hookRunner().Run(ctx, payload)
+	if err == nil || payload.Event.Blocking() {
+		return err
+	}
+	ui.PrintError("hook failed", err)
+	return nil
+}
+
+// commitHookPayload describes the commit of message for the pre-commit and post-commit hooks
+func (s *CommitService) commitHookPayload(event hooks.Event, message *model.CommitMessage) hooks.Payload {
+	return hooks.Payload{
+		Event:   event,
+		State:   hookState(&model.RepositoryState{StagedFiles: s.staged, Branch: cmp.Or(s.targetBranch(), s.branch)}),
+		Message: s.formatter.Format(message),
+	}
+}
+
+// notifyCommit runs the post-commit hooks with the result of the commit of revision
+func (s *CommitService) notifyCommit(ctx context.Context, message *model.CommitMessage, revision string, commitErr error) {
+	if !s.hookRunner().Has(hooks.PostCommit) {
+		return
+	}
+
+	payload := s.commitHookPayload(hooks.PostCommit, message)
+	result := &hooks.Result{Success: commitErr == nil, Branch: cmp.Or(s.targetBranch(), s.branch)}
+	if commitErr != nil {
+		result.Error = commitErr.Error()
+	} else if commits, err := s.gitRepo.ListCommits(ctx, revision, 1); err == nil && len(commits) == 1 {
+		result.Commit = commits[0].Hash
+	} else {
+		utils.Logger.Debug().Err(err).Str("revision", revision).Msg("Failed to resolve commit for post-commit hooks")
+	}
+	payload.Result = result
+
+	_ = s.runHook(ctx, payload)
+}
+
+// hookState describes state for hook scripts: paths and statuses of the staged files only
+func hookState(state *model.RepositoryState) *hooks.State {
+	files := make([]hooks.File, len(state.StagedFiles))
+	for i, file := range state.StagedFiles {
+		files[i] = hooks.File{Path: file.Path, Status: file.Status}
+	}
+	return &hooks.State{Branch: state.Branch, Files: files}
+}
@@ -0,0 +1,27 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+)
+
+func TestHookState_OmitsDiffs(t *testing.T) {
+	state := &model.RepositoryState{
+		Branch: "main",
+		StagedFiles: []model.FileChange{
+			{Path: "main.go", Status: "modified", Diff: "+secret"},
+		},
+		UnstagedFiles: []model.FileChange{{Path: "notes.txt", Status: "modified"}},
+	}
+
+	got := hookState(state)
+	if got.Branch != "main" || len(got.Files) != 1 || got.Files[0].Path != "main.go" || got.Files[0].Status != "modified" {
+		t.Errorf("hookState() = %+v, want the staged main.go on main", got)
+	}
+
+	// Commits without staged files still list an (empty) array
+	if got := hookState(&model.RepositoryState{}); got.Files == nil {
+		t.Error("hookState() Files = nil, want empty slice")
+	}
+}
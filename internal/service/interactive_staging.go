@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/ui"
+	"github.com/golgoth31/gitcomm/internal/utils"
+)
+
+// stageInteractively lets the user pick the unstaged files to stage and, optionally, the
+// hunks to stage within modified files, instead of auto-staging
+func (s *CommitService) stageInteractively(ctx context.Context) (*model.AutoStagingResult, error) {
+	startTime := time.Now()
+	result := &model.AutoStagingResult{
+		StagedFiles: []string{},
+		FailedFiles: []model.StagingFailure{},
+		Success:     true,
+	}
+
+	state, err := s.gitRepo.GetRepositoryState(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(state.UnstagedFiles) == 0 {
+		result.Duration = time.Since(startTime)
+		return result, nil
+	}
+
+	selected, err := ui.PromptFilesToStage(s.reader, state.UnstagedFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	// Hunks can only be picked in tracked files changed in place
+	modified := make(map[string]bool)
+	for _, file := range state.UnstagedFiles {
+		if file.Status == "modified" && slices.Contains(selected, file.Path) {
+			modified[file.Path] = true
+		}
+	}
+	pickHunks := false
+	if len(modified) > 0 {
+		if pickHunks, err = ui.PromptConfirm(s.reader, "Pick individual hunks in the modified files?", false); err != nil {
+			return nil, err
+		}
+	}
+
+	var wholeFiles []string
+	for _, path := range selected {
+		if !pickHunks || !modified[path] {
+			wholeFiles = append(wholeFiles, path)
+			continue
+		}
+
+		hunks, err := s.gitRepo.UnstagedHunks(ctx, path)
+		if err != nil {
+			return nil, err
+		}
+		if len(hunks) < 2 {
+			wholeFiles = append(wholeFiles, path)
+			continue
+		}
+		indexes, err := ui.PromptHunksToStage(s.reader, path, hunks)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case len(indexes) == 0:
+			continue
+		case len(indexes) == len(hunks):
+			wholeFiles = append(wholeFiles, path)
+			continue
+		}
+
+		chosen := make([]model.Hunk, 0, len(indexes))
+		for _, i := range indexes {
+			chosen = append(chosen, hunks[i])
+		}
+		if err := s.gitRepo.StageHunks(ctx, path, chosen); err != nil {
+			return nil, err
+		}
+		result.StagedFiles = append(result.StagedFiles, path)
+	}
+
+	if err := s.gitRepo.StageFiles(ctx, wholeFiles); err != nil {
+		return nil, err
+	}
+	result.StagedFiles = append(result.StagedFiles, wholeFiles...)
+	result.Duration = time.Since(startTime)
+
+	utils.Logger.Debug().Int("staged_count", len(result.StagedFiles)).Msg("Files staged interactively")
+	return result, nil
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/golgoth31/gitcomm/internal/issues"
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/ui"
+	"github.com/golgoth31/gitcomm/internal/utils"
+)
+
+// issueLookup looks up referenced issues (implemented by issues.Verifier)
+type issueLookup interface {
+	Supports(kind issues.Kind) bool
+	Lookup(ctx context.Context, ref issues.Reference) (*issues.Issue, error)
+}
+
+// verifyIssueReferences warns about footer references to missing or closed issues when
+// issues.verify is enabled, and offers to fix the footer before the commit is created.
+// Trackers that cannot be reached never block the commit.
+func (s *CommitService) verifyIssueReferences(ctx context.Context, message *model.CommitMessage) error {
+	if s.config == nil || !s.config.Issues.Verify {
+		return nil
+	}
+	verifier := issues.NewVerifier(s.issuesConfig(ctx))
+
+	for message.FooterText() != "" {
+		problems := checkIssueReferences(ctx, verifier, message.FooterText(), s.config.Issues.JiraProjects)
+		if len(problems) == 0 {
+			return nil
+		}
+
+		fmt.Fprintln(ui.Out(), "\nIssue references:")
+		for _, problem := range problems {
+			fmt.Fprintf(ui.Out(), "  - %s\n", problem)
+		}
+		// Without prompts the problems are warnings only
+		if ui.NonInteractive() {
+			return nil
+		}
+		edit, err := ui.PromptConfirm(s.reader, "Edit the footer?", true)
+		if err != nil {
+			return fmt.Errorf("failed to prompt for confirmation: %w", err)
+		}
+		if !edit {
+			return nil
+		}
+		footer, err := ui.PromptFooterWithDefault(s.reader, message.FooterText())
+		if err != nil {
+			return fmt.Errorf("failed to prompt for footer: %w", err)
+		}
+		message.SetFooter(footer)
+	}
+	return nil
+}
+
+// checkIssueReferences returns a warning for each reference in footer to a missing or closed issue.
+// References without a configured tracker are ignored and lookup failures are only logged.
+func checkIssueReferences(ctx context.Context, lookup issueLookup, footer string, jiraProjects []string) []string {
+	var problems []string
+	for _, ref := range issues.ParseReferences(footer, jiraProjects) {
+		if !lookup.Supports(ref.Kind) {
+			continue
+		}
+		issue, err := lookup.Lookup(ctx, ref)
+		switch {
+		case errors.Is(err, issues.ErrNotFound):
+			problems = append(problems, fmt.Sprintf("%s does not exist", ref))
+		case err != nil:
+			utils.Logger.Debug().Err(err).Str("reference", ref.String()).Msg("Cannot verify issue reference")
+			fmt.Fprintf(ui.Out(), "Could not verify %s, skipping\n", ref)
+		case !issue.Open:
+			problems = append(problems, fmt.Sprintf("%s is closed: %s", ref, issue.Title))
+		}
+	}
+	return problems
+}
+
+// issuesConfig builds the tracker settings, using the origin remote for #123 references
+func (s *CommitService) issuesConfig(ctx context.Context) issues.Config {
+	settings := s.config.Issues
+	cfg := issues.Config{
+		GitHubToken:  settings.GitHubToken,
+		GitLabToken:  settings.GitLabToken,
+		JiraURL:      settings.JiraURL,
+		JiraUser:     settings.JiraUser,
+		JiraToken:    settings.JiraToken,
+		JiraProjects: settings.JiraProjects,
+	}
+
+	// Issues live in the upstream repository in triangular workflows
+	for _, name := range []string{"upstream", "origin"} {
+		repo, err := s.remoteRepository(ctx, name)
+		if err == nil {
+			cfg.Repository = repo
+			break
+		}
+		utils.Logger.Debug().Err(err).Str("remote", name).Msg("Remote not usable for issue references")
+	}
+	return cfg
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/golgoth31/gitcomm/internal/issues"
+)
+
+// fakeIssueLookup answers lookups from a fixed set of issues
+type fakeIssueLookup struct {
+	jira   bool
+	issues map[string]*issues.Issue
+	err    error
+}
+
+func (f *fakeIssueLookup) Supports(kind issues.Kind) bool {
+	return kind == issues.KindForge || f.jira
+}
+
+func (f *fakeIssueLookup) Lookup(_ context.Context, ref issues.Reference) (*issues.Issue, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	if issue, ok := f.issues[ref.String()]; ok {
+		return issue, nil
+	}
+	return nil, issues.ErrNotFound
+}
+
+func TestCheckIssueReferences(t *testing.T) {
+	known := map[string]*issues.Issue{
+		"#12":    {Title: "Crash on start", Open: true},
+		"#13":    {Title: "Old bug", Open: false},
+		"PROJ-1": {Title: "Release", Open: true},
+	}
+
+	tests := []struct {
+		name   string
+		lookup *fakeIssueLookup
+		footer string
+		want   []string
+	}{
+		{
+			name:   "open issues",
+			lookup: &fakeIssueLookup{jira: true, issues: known},
+			footer: "Closes #12\nRefs: PROJ-1",
+			want:   nil,
+		},
+		{
+			name:   "missing and closed issues",
+			lookup: &fakeIssueLookup{issues: known},
+			footer: "Closes #21, #13",
+			want:   []string{"#21 does not exist", "#13 is closed: Old bug"},
+		},
+		{
+			name:   "jira keys ignored without jira",
+			lookup: &fakeIssueLookup{issues: known},
+			footer: "Refs: PROJ-999",
+			want:   nil,
+		},
+		{
+			name:   "unreachable tracker does not block",
+			lookup: &fakeIssueLookup{err: errors.New("connection refused")},
+			footer: "Closes #21",
+			want:   nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := checkIssueReferences(context.Background(), tt.lookup, tt.footer, []string{"PROJ"})
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("checkIssueReferences() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
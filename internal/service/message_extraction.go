@@ -0,0 +1,102 @@
+package service
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+)
+
+// conventionalHeaderRegex matches a Conventional Commits header: type(scope)!: subject
+var conventionalHeaderRegex = regexp.MustCompile(`^[a-zA-Z]+(\([^()]*\))?!?: \S`)
+
+// extractCommitMessage isolates the commit message from a raw AI answer.
+// Models frequently wrap the message in ``` fences, add a preface such as
+// "Here's your commit message:" or decorate the header with markdown.
+// A header in the custom layout, when there is one, is found too. If no header can be
+// found, the trimmed input is returned.
+func extractCommitMessage(aiMessage string, layout *model.HeaderLayout) string {
+	text := strings.TrimSpace(strings.ReplaceAll(aiMessage, "\r\n", "\n"))
+
+	// Prefer the content of the first fenced block that contains a header
+	for _, block := range fencedBlocks(text) {
+		if msg, ok := fromFirstHeader(block, layout); ok {
+			return msg
+		}
+	}
+
+	if msg, ok := fromFirstHeader(text, layout); ok {
+		return msg
+	}
+	return text
+}
+
+// fencedBlocks returns the content of every ``` fenced block, in order.
+// An unterminated fence runs until the end of the text.
+func fencedBlocks(text string) []string {
+	var blocks []string
+	var current []string
+	inFence := false
+
+	for _, line := range strings.Split(text, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			if inFence {
+				blocks = append(blocks, strings.Join(current, "\n"))
+				current = nil
+			}
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			current = append(current, line)
+		}
+	}
+	if inFence && len(current) > 0 {
+		blocks = append(blocks, strings.Join(current, "\n"))
+	}
+	return blocks
+}
+
+// fromFirstHeader drops the preamble before the first Conventional Commits header line,
+// or header in layout. Stray fence lines after the header are removed.
+func fromFirstHeader(text string, layout *model.HeaderLayout) (string, bool) {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		header := cleanHeaderLine(line)
+		if !conventionalHeaderRegex.MatchString(header) && !matchesLayout(layout, header) {
+			continue
+		}
+
+		result := []string{header}
+		for _, rest := range lines[i+1:] {
+			if strings.HasPrefix(strings.TrimSpace(rest), "```") {
+				break // Anything after a closing fence is commentary
+			}
+			result = append(result, strings.TrimRight(rest, " \t"))
+		}
+		return strings.TrimSpace(strings.Join(result, "\n")), true
+	}
+	return "", false
+}
+
+// matchesLayout reports whether header follows the custom header layout, when there is one
+func matchesLayout(layout *model.HeaderLayout, header string) bool {
+	if layout == nil {
+		return false
+	}
+	_, ok := layout.Match(header)
+	return ok
+}
+
+// cleanHeaderLine strips markdown decoration and quotes commonly wrapped around a header
+func cleanHeaderLine(line string) string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimLeft(line, "#>")
+	line = strings.TrimSpace(line)
+	for _, wrapper := range []string{"**", "`", `"`, "'"} {
+		if len(line) > 2*len(wrapper) && strings.HasPrefix(line, wrapper) && strings.HasSuffix(line, wrapper) {
+			line = strings.TrimSpace(line[len(wrapper) : len(line)-len(wrapper)])
+		}
+	}
+	return line
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+)
+
+func TestExtractCommitMessage(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "plain message is unchanged",
+			input: "feat(cli): add pager\n\nLong previews are paged.",
+			want:  "feat(cli): add pager\n\nLong previews are paged.",
+		},
+		{
+			name:  "fenced message",
+			input: "```\nfix: handle nil config\n```",
+			want:  "fix: handle nil config",
+		},
+		{
+			name:  "fenced message with language tag",
+			input: "```text\nfix(api): handle timeout\n\nRequests no longer hang.\n```",
+			want:  "fix(api): handle timeout\n\nRequests no longer hang.",
+		},
+		{
+			name:  "preface and trailing commentary around fence",
+			input: "Here's your commit message:\n\n```\nfeat: add dco check\n\nSigned-off-by: Jane Doe <jane@example.com>\n```\n\nLet me know if you want changes!",
+			want:  "feat: add dco check\n\nSigned-off-by: Jane Doe <jane@example.com>",
+		},
+		{
+			name:  "preface without fence",
+			input: "Sure! Based on the diff, here is a commit message:\n\nrefactor(service): extract signoff helper\n\nRemoves duplication.",
+			want:  "refactor(service): extract signoff helper\n\nRemoves duplication.",
+		},
+		{
+			name:  "bold markdown header",
+			input: "**feat: add pager**\n\nBody text",
+			want:  "feat: add pager\n\nBody text",
+		},
+		{
+			name:  "backtick wrapped single line",
+			input: "`chore: bump deps`",
+			want:  "chore: bump deps",
+		},
+		{
+			name:  "breaking change marker",
+			input: "Commit:\nfeat(api)!: drop v1 endpoints",
+			want:  "feat(api)!: drop v1 endpoints",
+		},
+		{
+			name:  "windows line endings",
+			input: "```\r\nfix: trim input\r\n\r\nBody\r\n```",
+			want:  "fix: trim input\n\nBody",
+		},
+		{
+			name:  "unterminated fence",
+			input: "```\ndocs: update readme",
+			want:  "docs: update readme",
+		},
+		{
+			name:  "fence without header falls back to surrounding text",
+			input: "feat: add example\n\n```go\nfmt.Println(1)\n```",
+			want:  "feat: add example",
+		},
+		{
+			name:  "no header returns trimmed input",
+			input: "  I could not determine the change.  ",
+			want:  "I could not determine the change.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := extractCommitMessage(tt.input, nil); got != tt.want {
+				t.Errorf("extractCommitMessage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseAIMessage_HeaderLayout(t *testing.T) {
+	layout, err := model.ParseHeaderLayout("[{ticket}] {type}({scope}): {subject}")
+	if err != nil {
+		t.Fatalf("ParseHeaderLayout() error = %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		want  model.CommitMessage
+	}{
+		{
+			name:  "ticket in header",
+			input: "Here's the commit message:\n[PROJ-123] feat(auth): add login\n\nUse sessions.",
+			want:  model.CommitMessage{Type: "feat", Scope: "auth", Ticket: "PROJ-123", Subject: "add login", Body: "Use sessions.", Signoff: true, Layout: layout},
+		},
+		{
+			name:  "ticket missing from header",
+			input: "feat(auth): add login",
+			want:  model.CommitMessage{Type: "feat", Scope: "auth", Ticket: "PROJ-7", Subject: "add login", Signoff: true, Layout: layout},
+		},
+	}
+
+	s := &CommitService{layout: layout, ticket: "PROJ-7"}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.parseAIMessage(tt.input)
+			if err != nil {
+				t.Fatalf("parseAIMessage() error = %v", err)
+			}
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("parseAIMessage() = %+v, want %+v", *got, tt.want)
+			}
+			if want := "[" + tt.want.Ticket + "] feat(auth): add login"; got.Header() != want {
+				t.Errorf("Header() = %q, want %q", got.Header(), want)
+			}
+		})
+	}
+}
+
+func TestParseAIMessage_MalformedOutputs(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  model.CommitMessage
+	}{
+		{
+			name:  "fenced with preface",
+			input: "Here's the commit message:\n```\nfeat(ui): add pager\n\nPreviews are paged.\n```",
+			want:  model.CommitMessage{Type: "feat", Scope: "ui", Subject: "add pager", Body: "Previews are paged.", Signoff: true},
+		},
+		{
+			name:  "bold header with footer",
+			input: "**fix: handle nil config**\n\nAvoid a panic.\n\nFixes #12",
+			want:  model.CommitMessage{Type: "fix", Subject: "handle nil config", Body: "Avoid a panic.", Footer: "Fixes #12", Signoff: true},
+		},
+	}
+
+	s := &CommitService{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.parseAIMessage(tt.input)
+			if err != nil {
+				t.Fatalf("parseAIMessage() error = %v", err)
+			}
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("parseAIMessage() = %+v, want %+v", *got, tt.want)
+			}
+
+			prefilled := s.parseAIMessageToPrefilled(tt.input)
+			if prefilled.Type != tt.want.Type || prefilled.Subject != tt.want.Subject || prefilled.Body != tt.want.Body {
+				t.Errorf("parseAIMessageToPrefilled() = %+v, want type/subject/body of %+v", prefilled, tt.want)
+			}
+		})
+	}
+}
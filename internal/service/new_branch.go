@@ -0,0 +1,97 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/golgoth31/gitcomm/internal/config"
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/ui"
+)
+
+// maxBranchSubjectLength bounds the slug of the subject in branch names
+const maxBranchSubjectLength = 50
+
+// branchNameFields are the fields of commit.branch_template
+type branchNameFields struct {
+	Type    string // slugified
+	Scope   string // slugified, may be empty
+	Subject string // slugified, at most maxBranchSubjectLength characters
+	Ticket  string // as written in the header or found in the branch name, may be empty
+}
+
+// slugify lowercases text and joins its runs of letters and digits with hyphens, cut
+// after a whole word to at most maxLen characters (0: no limit)
+func slugify(text string, maxLen int) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	slug := strings.Join(words, "-")
+	if maxLen <= 0 || len(slug) <= maxLen {
+		return slug
+	}
+	if cut := strings.LastIndex(slug[:maxLen+1], "-"); cut > 0 {
+		return slug[:cut]
+	}
+	return slug[:maxLen]
+}
+
+// renderBranchName renders the branch template with the fields of message
+func renderBranchName(tmpl string, message *model.CommitMessage, ticket string) (string, error) {
+	t, err := template.New("branch").Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("invalid branch template: %w", err)
+	}
+	fields := branchNameFields{
+		Type:    slugify(message.Type, 0),
+		Scope:   slugify(message.Scope, 0),
+		Subject: slugify(message.Subject, maxBranchSubjectLength),
+		Ticket:  ticket,
+	}
+	var sb strings.Builder
+	if err := t.Execute(&sb, fields); err != nil {
+		return "", fmt.Errorf("failed to render branch name: %w", err)
+	}
+	name := strings.Trim(strings.TrimSpace(sb.String()), "-/")
+	if name == "" {
+		return "", fmt.Errorf("branch template %q renders an empty name", tmpl)
+	}
+	return name, nil
+}
+
+// newBranch reports whether the commit goes to a new branch named after its message (--new-branch)
+func (s *CommitService) newBranch() bool {
+	return s.options != nil && s.options.NewBranch
+}
+
+// newBranchName returns the name of the branch --new-branch creates for message
+func (s *CommitService) newBranchName(message *model.CommitMessage) (string, error) {
+	tmpl := config.DefaultBranchTemplate
+	if s.config != nil && s.config.Commit.BranchTemplate != "" {
+		tmpl = s.config.Commit.BranchTemplate
+	}
+	return renderBranchName(tmpl, message, cmp.Or(message.Ticket, s.ticket))
+}
+
+// switchToNewBranch creates the branch named after message and switches to it, once: a
+// retried commit stays on the branch created the first time
+func (s *CommitService) switchToNewBranch(ctx context.Context, message *model.CommitMessage) error {
+	if s.createdBranch != "" {
+		return nil
+	}
+	name, err := s.newBranchName(message)
+	if err != nil {
+		return err
+	}
+	if err := s.gitRepo.SwitchToNewBranch(ctx, name); err != nil {
+		return err
+	}
+	s.createdBranch = name
+	s.branch = name
+	fmt.Fprintf(ui.Out(), "✓ Switched to new branch %s\n", name)
+	return nil
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/golgoth31/gitcomm/internal/config"
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/repository"
+	"github.com/golgoth31/gitcomm/internal/ui"
+	"github.com/golgoth31/gitcomm/pkg/testutil"
+)
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		text   string
+		maxLen int
+		want   string
+	}{
+		{text: "Add the login page", want: "add-the-login-page"},
+		{text: "  handle `nil` configs (again)!  ", want: "handle-nil-configs-again"},
+		{text: "support UTF-8 in v2.1", want: "support-utf-8-in-v2-1"},
+		{text: "add the login page", maxLen: 13, want: "add-the-login"},
+		{text: "add the login page", maxLen: 12, want: "add-the"},
+		{text: "internationalization", maxLen: 5, want: "inter"},
+		{text: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := slugify(tt.text, tt.maxLen); got != tt.want {
+			t.Errorf("slugify(%q, %d) = %q, want %q", tt.text, tt.maxLen, got, tt.want)
+		}
+	}
+}
+
+func TestRenderBranchName(t *testing.T) {
+	tests := []struct {
+		name    string
+		tmpl    string
+		message *model.CommitMessage
+		ticket  string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "default with scope",
+			tmpl:    config.DefaultBranchTemplate,
+			message: &model.CommitMessage{Type: "feat", Scope: "Auth", Subject: "add the login page"},
+			want:    "feat/auth-add-the-login-page",
+		},
+		{
+			name:    "default without scope",
+			tmpl:    config.DefaultBranchTemplate,
+			message: &model.CommitMessage{Type: "fix", Subject: "handle nil configs"},
+			want:    "fix/handle-nil-configs",
+		},
+		{
+			name:    "ticket",
+			tmpl:    "{{.Type}}/{{with .Ticket}}{{.}}-{{end}}{{.Subject}}",
+			message: &model.CommitMessage{Type: "feat", Subject: "add login"},
+			ticket:  "PROJ-123",
+			want:    "feat/PROJ-123-add-login",
+		},
+		{
+			name:    "unknown field",
+			tmpl:    "{{.Author}}/{{.Subject}}",
+			message: &model.CommitMessage{Type: "feat", Subject: "add login"},
+			wantErr: true,
+		},
+		{
+			name:    "empty name",
+			tmpl:    "{{.Scope}}",
+			message: &model.CommitMessage{Type: "feat", Subject: "add login"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := renderBranchName(tt.tmpl, tt.message, tt.ticket)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("renderBranchName() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("renderBranchName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateCommit_NewBranch(t *testing.T) {
+	ui.SetNonInteractive(true)
+	defer ui.SetNonInteractive(false)
+
+	fixture := testutil.NewRepoWithCommit(t, map[string]string{"api.go": "package api\n"})
+	fixture.WriteFile("api.go", "package api\n\nfunc Health() {}\n")
+	fixture.Stage("api.go")
+	gitRepo, err := repository.NewGitRepository(fixture.Dir, true, true)
+	if err != nil {
+		t.Fatalf("NewGitRepository() error = %v", err)
+	}
+
+	options := &model.CommitOptions{Message: "feat(api): add the health check", NewBranch: true, NoSignoff: true}
+	if err := NewCommitService(gitRepo, options, &config.Config{}).CreateCommit(context.Background()); err != nil {
+		t.Fatalf("CreateCommit() error = %v", err)
+	}
+
+	if branch := strings.TrimSpace(fixture.Git("branch", "--show-current")); branch != "feat/api-add-the-health-check" {
+		t.Errorf("current branch = %q, want feat/api-add-the-health-check", branch)
+	}
+	if subject := strings.TrimSpace(fixture.Git("log", "-1", "--format=%s")); subject != "feat(api): add the health check" {
+		t.Errorf("new branch HEAD = %q, want the new commit", subject)
+	}
+	if count := strings.TrimSpace(fixture.Git("rev-list", "--count", testutil.DefaultBranch)); count != "1" {
+		t.Errorf("%s has %s commits, want it left at the initial commit", testutil.DefaultBranch, count)
+	}
+
+	// The same message again: the branch exists, nothing is committed
+	fixture.Git("switch", "-q", testutil.DefaultBranch)
+	fixture.WriteFile("api.go", "package api\n\nfunc Ready() {}\n")
+	fixture.Stage("api.go")
+	err = NewCommitService(gitRepo, options, &config.Config{}).CreateCommit(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("CreateCommit() error = %v, want the branch to exist", err)
+	}
+}
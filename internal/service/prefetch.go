@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/repository"
+	"github.com/golgoth31/gitcomm/internal/utils"
+)
+
+// DefaultPrefetchInterval is the minimum time between two background computations of the
+// repository state, so a burst of git add does not run git diff on every change
+const DefaultPrefetchInterval = 2 * time.Second
+
+// prefetchDebounce groups the writes of one git command to the index
+const prefetchDebounce = 100 * time.Millisecond
+
+// indexStamp identifies the content of the index file. Its modification time is not
+// enough: git rewrites the same index on every status while entries are racily clean.
+type indexStamp [sha256.Size]byte
+
+// StatePrefetcher keeps the repository state ready for long-running modes (serve): the
+// state is computed in the background when the index changes, at most once per interval,
+// so a message request only waits for the provider. The diffs are read with the limits
+// (repository.DiffLimitsKey) of the last State call.
+type StatePrefetcher struct {
+	gitRepo  repository.GitRepository
+	interval time.Duration
+
+	mu        sync.Mutex
+	indexPath string
+	state     *model.RepositoryState
+	stamp     indexStamp // index the state was computed from, zero when unknown
+	limits    any        // diff limits of the last State call, used by background refreshes
+	computed  any        // diff limits the state was computed with
+	lastRun   time.Time  // end of the last computation
+	onRefresh func(*model.RepositoryState)
+}
+
+// NewStatePrefetcher creates a prefetcher for gitRepo refreshing at most once per interval
+// (DefaultPrefetchInterval if 0)
+func NewStatePrefetcher(gitRepo repository.GitRepository, interval time.Duration) *StatePrefetcher {
+	if interval <= 0 {
+		interval = DefaultPrefetchInterval
+	}
+	return &StatePrefetcher{
+		gitRepo:  gitRepo,
+		interval: interval,
+	}
+}
+
+// OnRefresh registers a callback invoked with the state after each background refresh
+func (p *StatePrefetcher) OnRefresh(fn func(*model.RepositoryState)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.onRefresh = fn
+}
+
+// State returns the prefetched state when the index did not change since it was computed,
+// and computes it otherwise, as well as when the diff limits set on ctx differ from the
+// ones of the prefetched state. The returned state is a copy the caller may modify.
+func (p *StatePrefetcher) State(ctx context.Context) (*model.RepositoryState, error) {
+	limits := ctx.Value(repository.DiffLimitsKey)
+	p.mu.Lock()
+	p.limits = limits
+	p.mu.Unlock()
+
+	state, fresh, err := p.cached(ctx, limits)
+	if err != nil {
+		return nil, err
+	}
+	if !fresh {
+		if state, err = p.compute(ctx, limits); err != nil {
+			return nil, err
+		}
+	}
+	copied := *state
+	copied.StagedFiles = slices.Clone(state.StagedFiles)
+	copied.UnstagedFiles = slices.Clone(state.UnstagedFiles)
+	return &copied, nil
+}
+
+// Run computes the state, then watches the index until ctx is cancelled.
+// The index directory is watched because git replaces the index by renaming index.lock.
+func (p *StatePrefetcher) Run(ctx context.Context) error {
+	indexPath, err := p.index(ctx)
+	if err != nil {
+		return err
+	}
+
+	fsWatcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create index watcher: %w", err)
+	}
+	defer fsWatcher.Close()
+
+	if err := fsWatcher.Add(filepath.Dir(indexPath)); err != nil {
+		return fmt.Errorf("failed to watch index directory: %w", err)
+	}
+	utils.Logger.Debug().Str("path", indexPath).Msg("Watching index for changes")
+
+	refresh := time.After(0)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case event, ok := <-fsWatcher.Events:
+			if !ok {
+				return nil
+			}
+			if filepath.Clean(event.Name) == filepath.Clean(indexPath) && refresh == nil {
+				refresh = time.After(p.delay())
+			}
+
+		case err, ok := <-fsWatcher.Errors:
+			if !ok {
+				return nil
+			}
+			utils.Logger.Debug().Err(err).Msg("Index watcher error")
+
+		case <-refresh:
+			refresh = nil
+			p.mu.Lock()
+			limits := p.limits
+			p.mu.Unlock()
+			// Computing the state may refresh the index itself: nothing to do then
+			if _, fresh, err := p.cached(ctx, limits); err == nil && fresh {
+				continue
+			}
+			state, err := p.compute(ctx, limits)
+			if err != nil {
+				utils.Logger.Debug().Err(err).Msg("Failed to prefetch repository state")
+				continue
+			}
+			p.mu.Lock()
+			onRefresh := p.onRefresh
+			p.mu.Unlock()
+			if onRefresh != nil {
+				onRefresh(state)
+			}
+		}
+	}
+}
+
+// delay returns how long to wait before the next background refresh
+func (p *StatePrefetcher) delay() time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return max(prefetchDebounce, p.interval-time.Since(p.lastRun))
+}
+
+// cached returns the cached state and whether the index is unchanged since it was computed
+// with limits
+func (p *StatePrefetcher) cached(ctx context.Context, limits any) (*model.RepositoryState, bool, error) {
+	indexPath, err := p.index(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	stamp := stampIndex(indexPath)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	fresh := p.state != nil && p.stamp != (indexStamp{}) && p.stamp == stamp && p.computed == limits
+	return p.state, fresh, nil
+}
+
+// compute gets the repository state with the diff limits limits (nil: the defaults) and
+// caches it. When the index changed during the computation (git add running meanwhile),
+// the state is kept but not considered fresh.
+func (p *StatePrefetcher) compute(ctx context.Context, limits any) (*model.RepositoryState, error) {
+	indexPath, err := p.index(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if limits != nil {
+		ctx = context.WithValue(ctx, repository.DiffLimitsKey, limits)
+	}
+
+	before := stampIndex(indexPath)
+	state, err := p.gitRepo.GetRepositoryState(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repository state: %w", err)
+	}
+	stamp := stampIndex(indexPath)
+	if stamp != before {
+		stamp = indexStamp{}
+	}
+
+	p.mu.Lock()
+	p.state, p.stamp, p.computed, p.lastRun = state, stamp, limits, time.Now()
+	p.mu.Unlock()
+	return state, nil
+}
+
+// index returns the path of the index file, located once
+func (p *StatePrefetcher) index(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.indexPath == "" {
+		indexPath, err := p.gitRepo.IndexPath(ctx)
+		if err != nil {
+			return "", err
+		}
+		p.indexPath = indexPath
+	}
+	return p.indexPath, nil
+}
+
+// stampIndex returns the version of the index file, zero when it cannot be read
+func stampIndex(path string) indexStamp {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return indexStamp{}
+	}
+	return sha256.Sum256(data)
+}
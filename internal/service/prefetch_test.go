@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/repository"
+	"github.com/golgoth31/gitcomm/pkg/testutil"
+)
+
+// countingRepository counts the repository state computations
+type countingRepository struct {
+	repository.GitRepository
+	calls atomic.Int32
+}
+
+func (r *countingRepository) GetRepositoryState(ctx context.Context) (*model.RepositoryState, error) {
+	r.calls.Add(1)
+	return r.GitRepository.GetRepositoryState(ctx)
+}
+
+func TestStatePrefetcher(t *testing.T) {
+	fixture := testutil.NewRepoWithCommit(t, map[string]string{"main.go": "package main\n"})
+	gitRepo, err := repository.NewGitRepository(fixture.Dir, true, true)
+	if err != nil {
+		t.Fatalf("NewGitRepository() error = %v", err)
+	}
+	counting := &countingRepository{GitRepository: gitRepo}
+
+	interval := 500 * time.Millisecond
+	prefetcher := NewStatePrefetcher(counting, interval)
+	refreshed := make(chan time.Time, 10)
+	prefetcher.OnRefresh(func(*model.RepositoryState) { refreshed <- time.Now() })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		if err := prefetcher.Run(ctx); err != nil {
+			t.Errorf("Run() error = %v", err)
+		}
+	}()
+	waitRefresh := func() time.Time {
+		t.Helper()
+		select {
+		case at := <-refreshed:
+			return at
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for a refresh")
+			return time.Time{}
+		}
+	}
+
+	// The state is computed on start and served from the cache
+	first := waitRefresh()
+	calls := counting.calls.Load()
+	state, err := prefetcher.State(ctx)
+	if err != nil {
+		t.Fatalf("State() error = %v", err)
+	}
+	if len(state.StagedFiles) != 0 || counting.calls.Load() != calls {
+		t.Fatalf("State() = %d staged files after %d computations, want 0 from the cache", len(state.StagedFiles), counting.calls.Load()-calls)
+	}
+
+	// Staging refreshes the state, no sooner than the interval after the previous refresh
+	fixture.WriteFile("main.go", "package main\n\nfunc main() {}\n")
+	fixture.Stage("main.go")
+	second := waitRefresh()
+	calls = counting.calls.Load()
+	if elapsed := second.Sub(first); elapsed < interval {
+		t.Errorf("refreshed %s after the previous refresh, want at least %s", elapsed, interval)
+	}
+	state, err = prefetcher.State(ctx)
+	if err != nil {
+		t.Fatalf("State() error = %v", err)
+	}
+	if len(state.StagedFiles) != 1 || state.StagedFiles[0].Path != "main.go" {
+		t.Errorf("State() staged files = %+v, want main.go", state.StagedFiles)
+	}
+	if counting.calls.Load() != calls {
+		t.Errorf("State() computed the state again, want it from the cache")
+	}
+}
+
+func TestStatePrefetcher_DiffLimits(t *testing.T) {
+	fixture := testutil.NewRepoWithCommit(t, map[string]string{"main.go": "package main\n"})
+	fixture.WriteFile("main.go", "package main\n\nfunc main() {}\n")
+	fixture.Stage("main.go")
+	gitRepo, err := repository.NewGitRepository(fixture.Dir, true, true)
+	if err != nil {
+		t.Fatalf("NewGitRepository() error = %v", err)
+	}
+	counting := &countingRepository{GitRepository: gitRepo}
+	prefetcher := NewStatePrefetcher(counting, 0)
+
+	narrow := context.WithValue(context.Background(), repository.DiffLimitsKey, model.DiffLimits{MaxSize: 5000})
+	wide := context.WithValue(context.Background(), repository.DiffLimitsKey, model.DiffLimits{Context: 3, MaxSize: 5000})
+	for _, ctx := range []context.Context{narrow, narrow, wide} {
+		state, err := prefetcher.State(ctx)
+		if err != nil {
+			t.Fatalf("State() error = %v", err)
+		}
+		// The caller may modify the files of its copy
+		state.StagedFiles[0].Diff = ""
+	}
+	if calls := counting.calls.Load(); calls != 2 {
+		t.Errorf("state computed %d times, want once per diff limits", calls)
+	}
+	state, err := prefetcher.State(wide)
+	if err != nil {
+		t.Fatalf("State() error = %v", err)
+	}
+	if state.StagedFiles[0].Diff == "" {
+		t.Error("State() diff emptied by a previous caller, want an independent copy")
+	}
+}
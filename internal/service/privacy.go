@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/utils"
+)
+
+// privacyGitKey is the git config key overriding ai.privacy for one repository
+const privacyGitKey = "gitcomm.privacy"
+
+// privacyLevel returns the privacy level of the repository: git config gitcomm.privacy,
+// then ai.privacy, then full-diff. An invalid value is an error rather than a fallback,
+// so content is never sent by mistake.
+func (s *CommitService) privacyLevel(ctx context.Context) (model.PrivacyLevel, error) {
+	if values, err := s.gitRepo.GetConfigValues(ctx, privacyGitKey); err != nil {
+		utils.Logger.Debug().Err(err).Msg("Failed to read git config " + privacyGitKey)
+	} else if len(values) > 0 {
+		// The last value wins, like git config --get
+		level, err := model.ParsePrivacyLevel(values[len(values)-1])
+		if err != nil {
+			return "", fmt.Errorf("git config %s: %w", privacyGitKey, err)
+		}
+		return level, nil
+	}
+
+	if s.config == nil {
+		return model.PrivacyFullDiff, nil
+	}
+	level, err := model.ParsePrivacyLevel(s.config.AI.Privacy)
+	if err != nil {
+		return "", fmt.Errorf("ai.privacy: %w", err)
+	}
+	return level, nil
+}
+
+// sharedState returns the part of repoState the privacy level allows to send to the provider
+func (s *CommitService) sharedState(ctx context.Context, repoState *model.RepositoryState) (*model.RepositoryState, error) {
+	level, err := s.privacyLevel(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if level != model.PrivacyFullDiff {
+		utils.Logger.Debug().Str("privacy", string(level)).Msg("Reducing repository state sent to the provider")
+	}
+	return repoState.WithPrivacy(level), nil
+}
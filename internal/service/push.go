@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/golgoth31/gitcomm/internal/ui"
+	"github.com/golgoth31/gitcomm/internal/utils"
+	"github.com/golgoth31/gitcomm/pkg/git/remote"
+)
+
+// push pushes the committed branch to its push remote (the fork in triangular workflows)
+// and prints the pull request creation URL when push.compare_url is enabled
+func (s *CommitService) push(ctx context.Context) error {
+	branch := s.targetBranch()
+	if branch == "" {
+		branch = s.branch
+	}
+
+	target, err := s.gitRepo.ResolvePushTarget(ctx, branch)
+	if err != nil {
+		return err
+	}
+	utils.Logger.Debug().
+		Str("remote", target.Remote).
+		Str("remote_branch", target.RemoteBranch).
+		Bool("triangular", target.Triangular).
+		Bool("set_upstream", target.SetUpstream).
+		Msg("Push target")
+
+	if err := s.gitRepo.Push(ctx, target); err != nil {
+		return err
+	}
+	fmt.Fprintf(ui.Out(), "✓ Pushed %s to %s/%s\n", target.LocalBranch, target.Remote, target.RemoteBranch)
+
+	if s.config != nil && s.config.Push.CompareURL {
+		if url := s.compareURL(ctx, target); url != "" {
+			fmt.Fprintf(ui.Out(), "Create a pull request: %s\n", url)
+		}
+	}
+	return nil
+}
+
+// compareURL returns the pull/merge request creation URL of target, or "" if the remotes
+// are not hosted on GitHub or GitLab or the default branch itself was pushed
+func (s *CommitService) compareURL(ctx context.Context, target *remote.Target) string {
+	if !target.Triangular {
+		defaultBranch, err := s.gitRepo.DefaultBranch(ctx, target.Remote)
+		if err != nil {
+			utils.Logger.Debug().Err(err).Msg("Cannot resolve the default branch")
+		} else if target.RemoteBranch == defaultBranch {
+			utils.Logger.Debug().Str("branch", defaultBranch).Msg("Default branch pushed, no pull request to create")
+			return ""
+		}
+	}
+
+	head, err := s.remoteRepository(ctx, target.Remote)
+	if err != nil {
+		utils.Logger.Debug().Err(err).Msg("Cannot build compare URL")
+		return ""
+	}
+	base := head
+	if target.BaseRemote != target.Remote {
+		if base, err = s.remoteRepository(ctx, target.BaseRemote); err != nil {
+			utils.Logger.Debug().Err(err).Msg("Cannot build compare URL")
+			return ""
+		}
+	}
+	return remote.CompareURL(base, head, target.BaseBranch, target.RemoteBranch)
+}
+
+// remoteRepository returns the hosted repository behind a remote's fetch URL
+func (s *CommitService) remoteRepository(ctx context.Context, name string) (*remote.Repository, error) {
+	urls, err := s.gitRepo.GetConfigValues(ctx, "remote."+name+".url")
+	if err != nil {
+		return nil, err
+	}
+	if len(urls) == 0 {
+		return nil, fmt.Errorf("remote %s has no URL", name)
+	}
+	return remote.ParseURL(urls[0])
+}
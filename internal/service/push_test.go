@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golgoth31/gitcomm/internal/config"
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/repository"
+	"github.com/golgoth31/gitcomm/pkg/git/remote"
+	"github.com/golgoth31/gitcomm/pkg/testutil"
+)
+
+func TestCompareURL_DefaultBranch(t *testing.T) {
+	fixture := testutil.NewRepoWithCommit(t, map[string]string{"a.txt": "a\n"})
+	fixture.Git("remote", "add", "origin", "https://github.com/acme/app.git")
+	fixture.Git("update-ref", "refs/remotes/origin/trunk", "HEAD")
+	fixture.Git("symbolic-ref", "refs/remotes/origin/HEAD", "refs/remotes/origin/trunk")
+
+	gitRepo, err := repository.NewGitRepository(fixture.Dir, true, true)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	s := NewCommitService(gitRepo, &model.CommitOptions{}, &config.Config{})
+
+	tests := []struct {
+		name   string
+		branch string
+		want   string
+	}{
+		{name: "feature branch", branch: "feat/login", want: "https://github.com/acme/app/compare/feat/login?expand=1"},
+		{name: "default branch of the remote", branch: "trunk", want: ""},
+		{name: "local main is not the default", branch: "main", want: "https://github.com/acme/app/compare/main?expand=1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := &remote.Target{LocalBranch: tt.branch, Remote: "origin", RemoteBranch: tt.branch, BaseRemote: "origin"}
+			if got := s.compareURL(context.Background(), target); got != tt.want {
+				t.Errorf("compareURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/golgoth31/gitcomm/internal/ai"
+	"github.com/golgoth31/gitcomm/internal/config"
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/repository"
+	"github.com/golgoth31/gitcomm/internal/ui"
+	"github.com/golgoth31/gitcomm/internal/utils"
+	"github.com/golgoth31/gitcomm/pkg/ai/prompt"
+)
+
+// QualityResult is the assessment of one existing commit
+type QualityResult struct {
+	Commit model.CommitInfo
+	// Report is nil when the commit could not be evaluated
+	Report *prompt.QualityReport
+	// Err explains why the commit could not be evaluated
+	Err error
+}
+
+// QualitySummary aggregates the results of a quality check
+type QualitySummary struct {
+	// Evaluated is the number of commits with a report
+	Evaluated int
+	// Failed is the number of commits that could not be evaluated
+	Failed int
+	// AverageScore is the mean score of the evaluated commits
+	AverageScore float64
+	// Describes counts the evaluated commits by DESCRIBES verdict (yes, partially, no)
+	Describes map[string]int
+	// WithMissingFiles is the number of evaluated commits missing important files
+	WithMissingFiles int
+}
+
+// QualityService grades existing commit messages against their diffs with the AI provider
+type QualityService struct {
+	gitRepo repository.GitRepository
+	commits *CommitService
+}
+
+// NewQualityService creates a new quality service
+func NewQualityService(gitRepo repository.GitRepository, options *model.CommitOptions, cfg *config.Config) *QualityService {
+	return &QualityService{
+		gitRepo: gitRepo,
+		commits: NewCommitService(gitRepo, options, cfg),
+	}
+}
+
+// Evaluate grades the last limit non-merge commits of revisionRange (e.g. "main..HEAD"), newest first,
+// running up to jobs provider calls at once (the provider's own limits still apply).
+// Commits whose diff cannot be read or whose answer cannot be parsed are reported with Err;
+// an unreachable provider aborts the check.
+func (s *QualityService) Evaluate(ctx context.Context, revisionRange string, limit, jobs int) ([]QualityResult, error) {
+	commits, err := s.gitRepo.ListCommits(ctx, revisionRange, limit)
+	if err != nil {
+		return nil, err
+	}
+	if len(commits) == 0 {
+		return nil, nil
+	}
+
+	provider, err := s.commits.newAIProvider()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		abortErr error
+		results  = make([]*QualityResult, len(commits))
+		indexes  = make(chan int)
+	)
+	for range max(1, min(jobs, len(commits))) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				commit := commits[i]
+				fmt.Fprintln(ui.Out(), ui.TruncateEnd(fmt.Sprintf("Evaluating %s %s", commit.ShortHash(), commit.Subject()), ui.TerminalWidth()))
+				report, err := s.evaluate(ctx, provider, commit)
+
+				mu.Lock()
+				switch {
+				case ctx.Err() != nil:
+					if abortErr == nil {
+						abortErr = ctx.Err()
+					}
+				case errors.Is(err, utils.ErrAIProviderUnavailable):
+					if abortErr == nil {
+						abortErr = err
+						cancel()
+					}
+				default:
+					results[i] = &QualityResult{Commit: commit, Report: report, Err: err}
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+feed:
+	for i := range commits {
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	if abortErr == nil {
+		abortErr = ctx.Err()
+	}
+	evaluated := make([]QualityResult, 0, len(commits))
+	for _, result := range results {
+		if result != nil {
+			evaluated = append(evaluated, *result)
+		}
+	}
+	return evaluated, abortErr
+}
+
+// evaluate asks the provider to grade the message of commit against its diff
+func (s *QualityService) evaluate(ctx context.Context, provider ai.AIProvider, commit model.CommitInfo) (*prompt.QualityReport, error) {
+	state, err := s.gitRepo.GetCommitState(s.commits.withDiffLimits(ctx), commit.Hash)
+	if err != nil {
+		return nil, err
+	}
+
+	shared, err := s.commits.sharedState(ctx, state)
+	if err != nil {
+		return nil, err
+	}
+
+	userMsg, err := prompt.QualityUserMessage(commit.Message, shared)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate user message: %w", err)
+	}
+
+	answer, err := provider.Complete(ctx, prompt.QualitySystemMessage(), userMsg)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", utils.ErrAIProviderUnavailable, err)
+	}
+
+	report, err := prompt.ParseQualityReport(answer)
+	if err != nil {
+		utils.Logger.Debug().Str("answer", answer).Msg("Unparseable quality answer")
+		return nil, err
+	}
+	return report, nil
+}
+
+// SummarizeQuality aggregates quality results
+func SummarizeQuality(results []QualityResult) QualitySummary {
+	summary := QualitySummary{Describes: make(map[string]int)}
+	total := 0
+	for _, result := range results {
+		if result.Report == nil {
+			summary.Failed++
+			continue
+		}
+		summary.Evaluated++
+		total += result.Report.Score
+		if result.Report.Describes != "" {
+			summary.Describes[result.Report.Describes]++
+		}
+		if len(result.Report.Missing) > 0 {
+			summary.WithMissingFiles++
+		}
+	}
+	if summary.Evaluated > 0 {
+		summary.AverageScore = float64(total) / float64(summary.Evaluated)
+	}
+	return summary
+}
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/pkg/ai/prompt"
+)
+
+func TestSummarizeQuality(t *testing.T) {
+	results := []QualityResult{
+		{Commit: model.CommitInfo{Hash: "a"}, Report: &prompt.QualityReport{Score: 8, Describes: "yes"}},
+		{Commit: model.CommitInfo{Hash: "b"}, Report: &prompt.QualityReport{Score: 5, Describes: "partially", Missing: []string{"go.mod"}}},
+		{Commit: model.CommitInfo{Hash: "c"}, Report: &prompt.QualityReport{Score: 2, Describes: "no"}},
+		{Commit: model.CommitInfo{Hash: "d"}, Err: errors.New("unparseable answer")},
+	}
+
+	summary := SummarizeQuality(results)
+
+	if summary.Evaluated != 3 || summary.Failed != 1 {
+		t.Errorf("Evaluated/Failed = %d/%d, want 3/1", summary.Evaluated, summary.Failed)
+	}
+	if summary.AverageScore != 5 {
+		t.Errorf("AverageScore = %v, want 5", summary.AverageScore)
+	}
+	if summary.Describes["yes"] != 1 || summary.Describes["partially"] != 1 || summary.Describes["no"] != 1 {
+		t.Errorf("Describes = %v, want one of each", summary.Describes)
+	}
+	if summary.WithMissingFiles != 1 {
+		t.Errorf("WithMissingFiles = %d, want 1", summary.WithMissingFiles)
+	}
+
+	if empty := SummarizeQuality(nil); empty.Evaluated != 0 || empty.AverageScore != 0 {
+		t.Errorf("SummarizeQuality(nil) = %+v, want zero summary", empty)
+	}
+}
@@ -0,0 +1,241 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/golgoth31/gitcomm/internal/config"
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/queue"
+	"github.com/golgoth31/gitcomm/internal/repository"
+	"github.com/golgoth31/gitcomm/internal/ui"
+	"github.com/golgoth31/gitcomm/internal/utils"
+)
+
+// placeholderMessage returns the message of commits queued while the AI provider is unreachable
+func placeholderMessage() *model.CommitMessage {
+	return &model.CommitMessage{
+		Type:    "chore",
+		Subject: "queued commit awaiting message",
+		Body:    "Created while the AI provider was unreachable.\nRun `gitcomm queue flush` to generate the final message.",
+	}
+}
+
+// canQueue reports whether the commit can be queued: only normal commits on the current
+// branch can be reworded later. An amend would replace the message of HEAD with the placeholder.
+func (s *CommitService) canQueue() bool {
+	return s.options == nil || (s.options.Branch == "" && !s.options.PatchOnly && s.options.Fixup == "" && !s.options.DryRun && !s.options.Copy && !s.options.NewBranch && !s.options.Amend)
+}
+
+// queueCommit offers to commit the staged snapshot with a placeholder message and record it
+// in the commit queue. Returns true if the commit was created.
+func (s *CommitService) queueCommit(ctx context.Context) (bool, error) {
+	confirm, err := ui.PromptConfirm(s.reader, "Queue this commit with a placeholder message and generate the message later (gitcomm queue flush)?", false)
+	if err != nil {
+		return false, fmt.Errorf("failed to prompt for queueing: %w", err)
+	}
+	if !confirm {
+		return false, nil
+	}
+
+	message := placeholderMessage()
+	s.applySignoff(message)
+	if err := s.createCommit(ctx, message); err != nil {
+		return false, fmt.Errorf("failed to create queued commit: %w", err)
+	}
+
+	// The commit exists: a failure to record it only loses the automatic rewording
+	if err := s.recordQueuedCommit(ctx); err != nil {
+		ui.PrintError("failed to record queued commit", err)
+		return true, nil
+	}
+	fmt.Fprintln(ui.Out(), "✓ Commit queued with a placeholder message, run `gitcomm queue flush` when the AI provider is reachable")
+	return true, nil
+}
+
+// recordQueuedCommit adds HEAD to the commit queue
+func (s *CommitService) recordQueuedCommit(ctx context.Context) error {
+	head, err := s.gitRepo.ListCommits(ctx, "HEAD", 1)
+	if err != nil {
+		return err
+	}
+	if len(head) == 0 {
+		return fmt.Errorf("no commit found at HEAD")
+	}
+	gitDir, err := s.gitRepo.GitDir(ctx)
+	if err != nil {
+		return err
+	}
+
+	branch := ""
+	if state, err := s.gitRepo.GetRepositoryState(ctx); err == nil {
+		branch = state.Branch
+	}
+	return queue.NewStore(gitDir).Add(queue.Entry{Hash: head[0].Hash, Branch: branch, QueuedAt: time.Now()})
+}
+
+// QueueService regenerates the messages of queued placeholder commits
+type QueueService struct {
+	gitRepo repository.GitRepository
+	commits *CommitService
+}
+
+// NewQueueService creates a new queue service
+func NewQueueService(gitRepo repository.GitRepository, options *model.CommitOptions, cfg *config.Config) *QueueService {
+	return &QueueService{
+		gitRepo: gitRepo,
+		commits: NewCommitService(gitRepo, options, cfg),
+	}
+}
+
+// List returns the queued entries, oldest first
+func (s *QueueService) List(ctx context.Context) ([]queue.Entry, error) {
+	store, err := s.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return store.Load()
+}
+
+// Flush generates a message for every queued commit still on the current branch and not pushed,
+// asks for confirmation, then rewords the accepted commits in one history rewrite.
+// Pushed commits are dropped from the queue; declined or foreign-branch commits stay queued.
+// If a generation fails, the commits accepted so far are still reworded, the failed one and
+// the next ones stay queued, and the error is returned with the number of reworded commits.
+// In non-interactive mode, confirmation must name the branch or the confirmation token
+// (see ConfirmationToken) before any message is generated.
+// Returns the number of reworded commits.
+func (s *QueueService) Flush(ctx context.Context, confirmation string) (int, error) {
+	store, err := s.store(ctx)
+	if err != nil {
+		return 0, err
+	}
+	entries, err := store.Load()
+	if err != nil {
+		return 0, err
+	}
+	if len(entries) == 0 {
+		return 0, nil
+	}
+
+	onBranch := make(map[string]bool)
+	branchCommits, err := s.gitRepo.ListCommits(ctx, "HEAD", 0)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read branch history: %w", err)
+	}
+	for _, commit := range branchCommits {
+		onBranch[commit.Hash] = true
+	}
+
+	dropped := make(map[string]bool)
+	var pending []queue.Entry
+	for _, entry := range entries {
+		if !onBranch[entry.Hash] {
+			// Other branch, or already rewritten by hand: keep for a later flush from that branch
+			fmt.Fprintf(ui.Out(), "Skipping %s: not on the current branch\n", shortHash(entry.Hash))
+			continue
+		}
+
+		pushed, err := s.gitRepo.IsPushed(ctx, entry.Hash)
+		if err != nil {
+			return 0, err
+		}
+		if pushed {
+			fmt.Fprintf(ui.Out(), "Dropping %s from the queue: already pushed, reword it manually\n", shortHash(entry.Hash))
+			dropped[entry.Hash] = true
+			continue
+		}
+		pending = append(pending, entry)
+	}
+
+	if len(pending) > 0 {
+		if err := requireConfirmation(ctx, s.gitRepo, "flush", &branchCommits[0], confirmation); err != nil {
+			return 0, err
+		}
+	}
+
+	messages := make(map[string]*model.CommitMessage)
+	var generateErr error
+	for _, entry := range pending {
+		message, err := s.generate(ctx, entry.Hash)
+		if err != nil {
+			// Offline again: stop asking, but keep the messages already accepted
+			generateErr = fmt.Errorf("failed to generate message for %s: %w", shortHash(entry.Hash), err)
+			break
+		}
+		if message != nil {
+			messages[entry.Hash] = message
+		}
+	}
+
+	var remaining []queue.Entry
+	for _, entry := range entries {
+		if !dropped[entry.Hash] && messages[entry.Hash] == nil {
+			remaining = append(remaining, entry)
+		}
+	}
+
+	if len(messages) > 0 {
+		printRecovery("--soft", &branchCommits[0])
+		rewritten, err := s.gitRepo.RewordCommits(ctx, messages)
+		if err != nil {
+			return 0, err
+		}
+		// Later queued commits were recreated too: follow their new hashes
+		for i := range remaining {
+			if newHash, ok := rewritten[remaining[i].Hash]; ok {
+				remaining[i].Hash = newHash
+			}
+		}
+	}
+
+	if err := store.Save(remaining); err != nil {
+		return len(messages), err
+	}
+	return len(messages), generateErr
+}
+
+// generate asks the AI provider for a message for commit and lets the user accept it.
+// Returns nil if the user declines.
+func (s *QueueService) generate(ctx context.Context, commit string) (*model.CommitMessage, error) {
+	state, err := s.gitRepo.GetCommitState(s.commits.withDiffLimits(ctx), commit)
+	if err != nil {
+		return nil, err
+	}
+
+	message, _, err := s.commits.requestAIMessage(ctx, state)
+	if err != nil {
+		return nil, err
+	}
+	message = s.commits.withAITrailer(message)
+
+	if err := ui.PrintPaged(fmt.Sprintf("\n--- Message for %s ---\n%s\n---", shortHash(commit), ui.DisplayCommitMessage(message))); err != nil {
+		utils.Logger.Debug().Err(err).Msg("Failed to display generated message")
+	}
+	confirm, err := ui.PromptConfirm(s.commits.reader, "Use this message?", true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prompt for confirmation: %w", err)
+	}
+	if !confirm {
+		return nil, nil
+	}
+
+	s.commits.applySignoff(message)
+	return message, nil
+}
+
+// store returns the queue store of the repository
+func (s *QueueService) store(ctx context.Context) (*queue.Store, error) {
+	gitDir, err := s.gitRepo.GitDir(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return queue.NewStore(gitDir), nil
+}
+
+// shortHash abbreviates a full commit hash for display
+func shortHash(hash string) string {
+	info := model.CommitInfo{Hash: hash}
+	return info.ShortHash()
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golgoth31/gitcomm/internal/config"
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/queue"
+	"github.com/golgoth31/gitcomm/internal/repository"
+	"github.com/golgoth31/gitcomm/internal/ui"
+	"github.com/golgoth31/gitcomm/internal/utils"
+	"github.com/golgoth31/gitcomm/pkg/testutil"
+)
+
+func TestCanQueue(t *testing.T) {
+	tests := []struct {
+		name    string
+		options *model.CommitOptions
+		want    bool
+	}{
+		{name: "no options", want: true},
+		{name: "normal commit", options: &model.CommitOptions{}, want: true},
+		{name: "amend", options: &model.CommitOptions{Amend: true}},
+		{name: "other branch", options: &model.CommitOptions{Branch: "release"}},
+		{name: "fixup", options: &model.CommitOptions{Fixup: "HEAD~1"}},
+		{name: "dry run", options: &model.CommitOptions{DryRun: true}},
+		{name: "copy", options: &model.CommitOptions{Copy: true}},
+		{name: "new branch", options: &model.CommitOptions{NewBranch: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &CommitService{options: tt.options}
+			if got := s.canQueue(); got != tt.want {
+				t.Errorf("canQueue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFlush_KeepsAcceptedMessagesWhenGenerationFails(t *testing.T) {
+	utils.InitLogger(false)
+	ui.SetNonInteractive(true)
+	defer ui.SetNonInteractive(false)
+
+	const answer = "feat(api): add the health endpoint"
+	server := testutil.NewProviderServer(t, "local", answer)
+	// The first message is generated, the provider is unreachable again for the second
+	server.FailWith(http.StatusOK, http.StatusUnauthorized)
+
+	// Three queued placeholder commits
+	fixture := testutil.NewRepoWithCommit(t, map[string]string{"README.md": "# api\n"})
+	placeholder := placeholderMessage()
+	subject := placeholder.Type + ": " + placeholder.Subject
+	store := queue.NewStore(filepath.Join(fixture.Dir, ".git"))
+	for _, name := range []string{"health.go", "ready.go", "live.go"} {
+		hash := fixture.CommitFiles(subject, map[string]string{name: "package api\n"})
+		if err := store.Add(queue.Entry{Hash: hash, Branch: testutil.DefaultBranch, QueuedAt: time.Now()}); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+	}
+
+	gitRepo, err := repository.NewGitRepository(fixture.Dir, true, true)
+	if err != nil {
+		t.Fatalf("NewGitRepository() error = %v", err)
+	}
+	cfg := &config.Config{}
+	cfg.AI.DefaultProvider = "local"
+	cfg.AI.Providers = map[string]model.AIProviderConfig{"local": {Endpoint: server.Endpoint()}}
+	s := NewQueueService(gitRepo, &model.CommitOptions{NoSignoff: true}, cfg)
+
+	count, err := s.Flush(context.Background(), testutil.DefaultBranch)
+	if !errors.Is(err, utils.ErrAIProviderUnavailable) {
+		t.Fatalf("Flush() error = %v, want ErrAIProviderUnavailable", err)
+	}
+	if count != 1 {
+		t.Errorf("Flush() reworded %d commits, want 1", count)
+	}
+	if got := len(server.Requests()); got != 2 {
+		t.Errorf("provider received %d requests, want 2 (no request after the failure)", got)
+	}
+
+	// The accepted message is applied, the later commits keep their placeholder
+	subjects := strings.Split(strings.TrimSpace(fixture.Git("log", "--format=%s", "-3", "--reverse")), "\n")
+	want := []string{answer, subject, subject}
+	if strings.Join(subjects, "\n") != strings.Join(want, "\n") {
+		t.Errorf("subjects = %q, want %q", subjects, want)
+	}
+
+	// Only the failed and later commits stay queued, under their rewritten hashes
+	entries, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	wantHashes := []string{strings.TrimSpace(fixture.Git("rev-parse", "HEAD~1")), fixture.Head()}
+	if len(entries) != len(wantHashes) {
+		t.Fatalf("queue has %d entries, want %d", len(entries), len(wantHashes))
+	}
+	for i, entry := range entries {
+		if entry.Hash != wantHashes[i] {
+			t.Errorf("entry %d hash = %s, want %s", i, entry.Hash, wantHashes[i])
+		}
+	}
+
+	// A later flush only generates the messages still missing
+	count, err = s.Flush(context.Background(), testutil.DefaultBranch)
+	if err != nil {
+		t.Fatalf("second Flush() error = %v", err)
+	}
+	if count != 2 {
+		t.Errorf("second Flush() reworded %d commits, want 2", count)
+	}
+	if got := len(server.Requests()); got != 4 {
+		t.Errorf("provider received %d requests in total, want 4", got)
+	}
+	if entries, _ := store.Load(); len(entries) != 0 {
+		t.Errorf("queue has %d entries after the second flush, want none", len(entries))
+	}
+}
@@ -0,0 +1,163 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/golgoth31/gitcomm/internal/config"
+	"github.com/golgoth31/gitcomm/internal/repository"
+	"github.com/golgoth31/gitcomm/internal/ui"
+	"github.com/golgoth31/gitcomm/pkg/release"
+)
+
+// ReleasePlan is the outcome of the commit analysis since the last release
+type ReleasePlan struct {
+	// PreviousTag is the tag of the last release ("" before the first release)
+	PreviousTag string
+	// Previous is the version of the last release
+	Previous release.Version
+	// Bump is the increment called for by the commits since the last release
+	Bump release.Bump
+	// Next is the version to release (Previous when Bump is none)
+	Next release.Version
+	// NextTag is the tag name of Next
+	NextTag string
+	// Commits is the number of commits analyzed
+	Commits int
+	// Subjects are the subjects of the analyzed commits, newest first
+	Subjects []string
+	// Log are the analyzed commits, newest first
+	Log []release.Commit
+}
+
+// HasRelease reports whether the commits call for a new release
+func (p *ReleasePlan) HasRelease() bool {
+	return p.Bump != release.None
+}
+
+// ReleaseService computes versions from the commit history and tags releases
+type ReleaseService struct {
+	gitRepo   repository.GitRepository
+	tagPrefix string
+	reader    *bufio.Reader
+}
+
+// NewReleaseService creates a new release service
+func NewReleaseService(gitRepo repository.GitRepository, cfg *config.Config) *ReleaseService {
+	tagPrefix := config.DefaultTagPrefix
+	if cfg != nil {
+		tagPrefix = cfg.Release.TagPrefix
+	}
+	return &ReleaseService{gitRepo: gitRepo, tagPrefix: tagPrefix, reader: bufio.NewReader(ui.Stdin())}
+}
+
+// Plan analyzes the commits since the last release tag reachable from HEAD, like
+// semantic-release, and returns the next version. Without a release tag, all commits
+// are analyzed and the first release is release.FirstVersion.
+func (s *ReleaseService) Plan(ctx context.Context) (*ReleasePlan, error) {
+	tags, err := s.gitRepo.ListTags(ctx, "HEAD")
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &ReleasePlan{}
+	revision := "HEAD"
+	if version, tag, ok := release.LatestTag(tags, s.tagPrefix); ok {
+		plan.Previous, plan.PreviousTag = version, tag
+		revision = tag + "..HEAD"
+	}
+
+	commits, err := s.gitRepo.ListCommits(ctx, revision, 0)
+	if err != nil {
+		return nil, err
+	}
+	messages := make([]string, len(commits))
+	plan.Subjects = make([]string, len(commits))
+	plan.Log = make([]release.Commit, len(commits))
+	for i, commit := range commits {
+		messages[i] = commit.Message
+		plan.Subjects[i] = commit.Subject()
+		plan.Log[i] = release.Commit{ShortHash: commit.ShortHash(), Message: commit.Message}
+	}
+	plan.Commits = len(commits)
+	plan.Bump = release.AnalyzeAll(messages)
+
+	switch {
+	case !plan.HasRelease():
+		plan.Next = plan.Previous
+	case plan.PreviousTag == "":
+		plan.Next = release.FirstVersion
+	default:
+		plan.Next = plan.Previous.Next(plan.Bump)
+	}
+	if plan.HasRelease() || plan.PreviousTag != "" {
+		plan.NextTag = s.tagPrefix + plan.Next.String()
+	}
+	return plan, nil
+}
+
+// String describes the plan in one line
+func (p *ReleasePlan) String() string {
+	since := "since " + p.PreviousTag
+	if p.PreviousTag == "" {
+		since = "and no previous release"
+	}
+	if !p.HasRelease() {
+		return fmt.Sprintf("No release: %d commit(s) %s, none is a feature, fix or breaking change", p.Commits, since)
+	}
+	return fmt.Sprintf("%s release %s: %d commit(s) %s", p.Bump, p.NextTag, p.Commits, since)
+}
+
+// Tag plans the next release and, after confirmation, creates its annotated tag on HEAD
+// with release notes dated date. Reports whether the tag was signed; when the commits
+// call for no release, no tag is created.
+func (s *ReleaseService) Tag(ctx context.Context, date string) (*ReleasePlan, bool, error) {
+	plan, err := s.Plan(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	if !plan.HasRelease() {
+		return plan, false, nil
+	}
+
+	confirm, err := ui.PromptConfirm(s.reader, fmt.Sprintf("Create tag %s?", plan.NextTag), true)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to prompt for confirmation: %w", err)
+	}
+	if !confirm {
+		return nil, false, fmt.Errorf("tag cancelled by user")
+	}
+
+	signed, err := s.gitRepo.CreateTag(ctx, plan.NextTag, "HEAD", plan.Notes(date))
+	if err != nil {
+		return nil, false, err
+	}
+	return plan, signed, nil
+}
+
+// Notes returns the annotation of the release tag: the tag and date, then the subjects
+// of the released commits
+func (p *ReleasePlan) Notes(date string) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s (%s)\n", p.NextTag, date))
+	if len(p.Subjects) > 0 {
+		sb.WriteString("\n")
+	}
+	for _, subject := range p.Subjects {
+		sb.WriteString("- " + subject + "\n")
+	}
+	return sb.String()
+}
+
+// Changelog returns the markdown release notes of the analyzed commits (see
+// release.Changelog), titled with the next tag and date, or "Unreleased" when the commits
+// call for no release. all lists the commits outside the release sections too.
+func (p *ReleasePlan) Changelog(date string, all bool) string {
+	title := "Unreleased"
+	if p.HasRelease() {
+		title = fmt.Sprintf("%s (%s)", p.NextTag, date)
+	}
+	return release.Changelog(title, p.Log, all)
+}
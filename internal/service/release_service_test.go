@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/golgoth31/gitcomm/internal/config"
+	"github.com/golgoth31/gitcomm/internal/repository"
+	"github.com/golgoth31/gitcomm/internal/ui"
+	"github.com/golgoth31/gitcomm/pkg/release"
+	"github.com/golgoth31/gitcomm/pkg/testutil"
+)
+
+func TestReleaseService_Plan(t *testing.T) {
+	tests := []struct {
+		name      string
+		tagPrefix string
+		history   [][]string
+		wantBump  release.Bump
+		wantTag   string
+		wantCount int
+	}{
+		{
+			name:      "first release",
+			tagPrefix: "v",
+			history:   [][]string{{"commit", "-m", "fix: a"}, {"commit", "-m", "docs: b"}},
+			wantBump:  release.Patch,
+			wantTag:   "v1.0.0",
+			wantCount: 2,
+		},
+		{
+			name:      "commits since the last release",
+			tagPrefix: "v",
+			history: [][]string{
+				{"commit", "-m", "feat!: a"}, {"tag", "v1.2.3"}, {"tag", "v0.9.0"},
+				{"commit", "-m", "fix: b"}, {"commit", "-m", "feat(api): c"},
+			},
+			wantBump:  release.Minor,
+			wantTag:   "v1.3.0",
+			wantCount: 2,
+		},
+		{
+			name:      "no release",
+			tagPrefix: "v",
+			history:   [][]string{{"commit", "-m", "feat: a"}, {"tag", "v1.0.0"}, {"commit", "-m", "chore: b"}},
+			wantBump:  release.None,
+			wantTag:   "v1.0.0",
+			wantCount: 1,
+		},
+		{
+			name:      "tag prefix",
+			tagPrefix: "",
+			history:   [][]string{{"commit", "-m", "feat: a"}, {"tag", "v5.0.0"}, {"tag", "2.0.0"}, {"commit", "-m", "fix: b\n\nBREAKING CHANGE: c"}},
+			wantBump:  release.Major,
+			wantTag:   "3.0.0",
+			wantCount: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fixture := testutil.NewRepo(t)
+			for _, args := range tt.history {
+				if args[0] == "commit" {
+					args = append(args, "--allow-empty", "-q")
+				}
+				fixture.Git(args...)
+			}
+
+			gitRepo, err := repository.NewGitRepository(fixture.Dir, true, true)
+			if err != nil {
+				t.Fatalf("NewGitRepository() error = %v", err)
+			}
+			cfg := &config.Config{Release: config.ReleaseConfig{TagPrefix: tt.tagPrefix}}
+
+			plan, err := NewReleaseService(gitRepo, cfg).Plan(context.Background())
+			if err != nil {
+				t.Fatalf("Plan() error = %v", err)
+			}
+			if plan.Bump != tt.wantBump || plan.NextTag != tt.wantTag || plan.Commits != tt.wantCount {
+				t.Errorf("Plan() = %s bump to %q over %d commit(s), want %s to %q over %d",
+					plan.Bump, plan.NextTag, plan.Commits, tt.wantBump, tt.wantTag, tt.wantCount)
+			}
+		})
+	}
+}
+
+func TestReleaseService_Tag(t *testing.T) {
+	ui.SetNonInteractive(true)
+	defer ui.SetNonInteractive(false)
+
+	fixture := testutil.NewRepo(t)
+	fixture.Commit("feat: a")
+	fixture.Git("tag", "v1.0.0")
+	fixture.Commit("fix(api): b")
+	fixture.Commit("feat: c")
+
+	gitRepo, err := repository.NewGitRepository(fixture.Dir, true, true)
+	if err != nil {
+		t.Fatalf("NewGitRepository() error = %v", err)
+	}
+	releases := NewReleaseService(gitRepo, &config.Config{Release: config.ReleaseConfig{TagPrefix: "v"}})
+
+	plan, signed, err := releases.Tag(context.Background(), "2026-10-16")
+	if err != nil {
+		t.Fatalf("Tag() error = %v", err)
+	}
+	if plan.NextTag != "v1.1.0" || signed {
+		t.Errorf("Tag() = %s, signed %v, want unsigned v1.1.0", plan.NextTag, signed)
+	}
+	if got := fixture.Git("tag", "--list", "--format=%(objecttype) %(*objectname)", "v1.1.0"); got != "tag "+fixture.Head()+"\n" {
+		t.Errorf("v1.1.0 = %q, want an annotated tag of HEAD", got)
+	}
+	if got := fixture.Git("tag", "--list", "--format=%(contents)", "v1.1.0"); got != "v1.1.0 (2026-10-16)\n\n- feat: c\n- fix(api): b\n\n" {
+		t.Errorf("v1.1.0 annotation = %q", got)
+	}
+
+	// Nothing to release once tagged
+	plan, _, err = releases.Tag(context.Background(), "2026-10-16")
+	if err != nil || plan.HasRelease() {
+		t.Errorf("Tag() after release = %v, %v, want no release", plan, err)
+	}
+}
+
+func TestReleasePlan_Changelog(t *testing.T) {
+	fixture := testutil.NewRepo(t)
+	fixture.Commit("feat: a")
+	fixture.Git("tag", "v1.0.0")
+	fixture.Commit("docs: b")
+
+	gitRepo, err := repository.NewGitRepository(fixture.Dir, true, true)
+	if err != nil {
+		t.Fatalf("NewGitRepository() error = %v", err)
+	}
+	releases := NewReleaseService(gitRepo, &config.Config{Release: config.ReleaseConfig{TagPrefix: "v"}})
+
+	plan, err := releases.Plan(context.Background())
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+	if got := plan.Changelog("2026-10-16", false); got != "" {
+		t.Errorf("Changelog() without release commits = %q, want empty", got)
+	}
+	if got, want := plan.Changelog("2026-10-16", true), "## Unreleased\n\n### Other Changes\n\n- docs: b ("; !strings.HasPrefix(got, want) {
+		t.Errorf("Changelog(all) = %q, want prefix %q", got, want)
+	}
+
+	fixture.Commit("fix(api): c")
+	if plan, err = releases.Plan(context.Background()); err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+	if got, want := plan.Changelog("2026-10-16", false), "## v1.0.1 (2026-10-16)\n\n### Bug Fixes\n\n- **api:** c ("; !strings.HasPrefix(got, want) {
+		t.Errorf("Changelog() = %q, want prefix %q", got, want)
+	}
+}
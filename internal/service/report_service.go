@@ -0,0 +1,139 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golgoth31/gitcomm/internal/config"
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/repository"
+	"github.com/golgoth31/gitcomm/pkg/ai/prompt"
+	"github.com/golgoth31/gitcomm/pkg/conventional"
+)
+
+// brokenRulePenalty is the score a message loses for each validation rule it breaks
+const brokenRulePenalty = 3
+
+// ReportService summarizes the commits of the git user across repositories
+type ReportService struct {
+	// commits parses and validates messages in the configured header layout
+	commits *CommitService
+}
+
+// NewReportService creates a new report service
+func NewReportService(cfg *config.Config) *ReportService {
+	return &ReportService{commits: NewCommitService(nil, nil, cfg)}
+}
+
+// Build reports the commits made on any local branch of repositories (the current
+// repository when empty) between since and now. A repository that cannot be read is
+// listed with its error and does not stop the report.
+func (s *ReportService) Build(ctx context.Context, repositories []string, since, now time.Time) *model.ActivityReport {
+	if len(repositories) == 0 {
+		repositories = []string{""}
+	}
+	report := &model.ActivityReport{
+		Since:    since,
+		Until:    now,
+		ByType:   make(map[string]int),
+		MaxScore: prompt.MaxQualityScore,
+	}
+
+	days := map[string]bool{}
+	total := 0
+	for _, path := range repositories {
+		activity, commits := s.readRepository(ctx, path, since)
+		report.Repositories = append(report.Repositories, activity)
+		for _, commit := range commits {
+			commitType, score := s.rate(commit.Message)
+			report.ByType[commitType]++
+			report.Commits++
+			total += score
+			days[commit.Date.In(now.Location()).Format(time.DateOnly)] = true
+		}
+	}
+
+	slices.SortStableFunc(report.Repositories, func(a, b model.RepositoryActivity) int {
+		return cmp.Compare(b.Commits, a.Commits)
+	})
+	if report.Commits > 0 {
+		report.AverageScore = float64(total) / float64(report.Commits)
+	}
+	report.ActiveDays = len(days)
+	report.Streak = streak(days, now)
+	return report
+}
+
+// readRepository returns the commits of the git user since since in the repository at path
+func (s *ReportService) readRepository(ctx context.Context, path string, since time.Time) (model.RepositoryActivity, []model.CommitInfo) {
+	activity := model.RepositoryActivity{Path: path}
+	gitRepo, err := repository.NewGitRepository(path, true, true)
+	if err != nil {
+		activity.Err = err
+		return activity, nil
+	}
+	if root, err := gitRepo.WorkTreeDir(ctx); err == nil {
+		activity.Path = root
+	}
+	commits, err := gitRepo.AuthorCommits(ctx, since)
+	if err != nil {
+		activity.Err = err
+		return activity, nil
+	}
+	activity.Commits = len(commits)
+	return activity, commits
+}
+
+// rate returns the type of a commit message (model.OtherType when it has no Conventional
+// Commits type) and its offline score: full marks for a valid Conventional Commit,
+// brokenRulePenalty points less per broken rule, and none without a type
+func (s *ReportService) rate(text string) (string, int) {
+	message := s.commits.parseAnyMessage(strings.TrimSpace(text))
+	if !slices.Contains(conventional.NewValidator().GetValidTypes(), message.Type) {
+		return model.OtherType, 0
+	}
+	_, violations := s.commits.validator.Validate(message)
+	return message.Type, max(0, prompt.MaxQualityScore-brokenRulePenalty*len(violations))
+}
+
+// streak counts the consecutive days of days (dates formatted as time.DateOnly) up to
+// today, or up to yesterday when today is not one of them
+func streak(days map[string]bool, now time.Time) int {
+	day := now
+	if !days[day.Format(time.DateOnly)] {
+		day = day.AddDate(0, 0, -1)
+	}
+	count := 0
+	for days[day.Format(time.DateOnly)] {
+		count++
+		day = day.AddDate(0, 0, -1)
+	}
+	return count
+}
+
+// ParseSince returns the start of a report period given as a duration back from now
+// (90m, 12h, 3d, 2w) or as a date (2006-01-02, midnight local time)
+func ParseSince(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if date, err := time.ParseInLocation(time.DateOnly, value, now.Location()); err == nil {
+		return date, nil
+	}
+
+	if last := len(value) - 1; last > 0 && (value[last] == 'd' || value[last] == 'w') {
+		if days, err := strconv.Atoi(value[:last]); err == nil && days > 0 {
+			if value[last] == 'w' {
+				days *= 7
+			}
+			return now.AddDate(0, 0, -days), nil
+		}
+	}
+	if duration, err := time.ParseDuration(value); err == nil && duration > 0 {
+		return now.Add(-duration), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid period %q: use a duration such as 12h, 3d or 2w, or a date such as 2006-01-02", value)
+}
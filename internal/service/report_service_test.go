@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golgoth31/gitcomm/internal/config"
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/pkg/testutil"
+)
+
+func TestReportService_Build(t *testing.T) {
+	active := testutil.NewRepoWithCommit(t, map[string]string{"README.md": "# test\n"})
+	active.Commit("feat(api): add the health endpoint")
+	active.Commit("fix(api server): handle empty bodies")
+	active.Git("commit", "-q", "--allow-empty", "-m", "feat: someone else's work", "--author", "Other <other@example.com>")
+	active.Git("switch", "-q", "-c", "feature")
+	active.Commit("feat(ui): add the dark theme")
+
+	quiet := testutil.NewRepoWithCommit(t, map[string]string{"README.md": "# quiet\n"})
+	missing := t.TempDir()
+
+	// testutil commits are made on 2024-01-01 from 12:00 UTC
+	since := time.Date(2024, time.January, 1, 12, 1, 0, 0, time.UTC)
+	now := time.Date(2024, time.January, 2, 9, 0, 0, 0, time.UTC)
+	report := NewReportService(&config.Config{}).Build(context.Background(), []string{quiet.Dir, missing, active.Dir}, since, now)
+
+	if report.Commits != 3 {
+		t.Errorf("Commits = %d, want 3 (user's commits on all branches, after since)", report.Commits)
+	}
+	if want := map[string]int{"feat": 2, "fix": 1}; !reflect.DeepEqual(report.ByType, want) {
+		t.Errorf("ByType = %v, want %v", report.ByType, want)
+	}
+	if len(report.Repositories) != 3 || report.Repositories[0].Commits != 3 {
+		t.Fatalf("Repositories = %+v, want the active repository first", report.Repositories)
+	}
+	if report.TouchedRepositories() != 1 {
+		t.Errorf("TouchedRepositories() = %d, want 1", report.TouchedRepositories())
+	}
+	failed := 0
+	for _, repository := range report.Repositories {
+		if repository.Err != nil {
+			failed++
+			if repository.Path != missing {
+				t.Errorf("repository %s error = %v, want none", repository.Path, repository.Err)
+			}
+		}
+	}
+	if failed != 1 {
+		t.Errorf("Repositories = %+v, want an error for the directory without repository", report.Repositories)
+	}
+	// the scope of the fix breaks a rule
+	if want := (10 + 10 + 7) / 3.0; report.AverageScore != want {
+		t.Errorf("AverageScore = %v, want %v", report.AverageScore, want)
+	}
+	if report.ActiveDays != 1 || report.Streak != 1 {
+		t.Errorf("ActiveDays, Streak = %d, %d, want 1, 1", report.ActiveDays, report.Streak)
+	}
+}
+
+func TestReportService_Rate(t *testing.T) {
+	s := NewReportService(&config.Config{})
+	tests := []struct {
+		message   string
+		wantType  string
+		wantScore int
+	}{
+		{"feat(api): add the health endpoint\n\nUsed by the load balancer.", "feat", 10},
+		{"fix(api server): handle empty bodies", "fix", 7},
+		{"docs(read me): " + strings.Repeat("long ", 20), "docs", 4},
+		{"Update README", model.OtherType, 0},
+		{"feature: add login", model.OtherType, 0},
+		{"[PROJ-1] fix: something", model.OtherType, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.message, func(t *testing.T) {
+			gotType, gotScore := s.rate(tt.message)
+			if gotType != tt.wantType || gotScore != tt.wantScore {
+				t.Errorf("rate() = %q, %d, want %q, %d", gotType, gotScore, tt.wantType, tt.wantScore)
+			}
+		})
+	}
+}
+
+func TestStreak(t *testing.T) {
+	now := time.Date(2024, time.March, 10, 18, 0, 0, 0, time.UTC)
+	days := func(dates ...string) map[string]bool {
+		set := map[string]bool{}
+		for _, date := range dates {
+			set[date] = true
+		}
+		return set
+	}
+
+	tests := []struct {
+		name string
+		days map[string]bool
+		want int
+	}{
+		{"none", days(), 0},
+		{"today", days("2024-03-10", "2024-03-09", "2024-03-07"), 2},
+		{"up to yesterday", days("2024-03-09", "2024-03-08", "2024-03-07"), 3},
+		{"broken two days ago", days("2024-03-08", "2024-03-07"), 0},
+		{"across months", days("2024-03-10", "2024-03-01", "2024-02-29"), 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := streak(tt.days, now); got != tt.want {
+				t.Errorf("streak() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2024, time.March, 10, 18, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{value: "1w", want: time.Date(2024, time.March, 3, 18, 0, 0, 0, time.UTC)},
+		{value: "3d", want: time.Date(2024, time.March, 7, 18, 0, 0, 0, time.UTC)},
+		{value: "12h", want: time.Date(2024, time.March, 10, 6, 0, 0, 0, time.UTC)},
+		{value: "90m", want: time.Date(2024, time.March, 10, 16, 30, 0, 0, time.UTC)},
+		{value: "2024-01-15", want: time.Date(2024, time.January, 15, 0, 0, 0, 0, time.UTC)},
+		{value: "0d", wantErr: true},
+		{value: "-1w", wantErr: true},
+		{value: "w", wantErr: true},
+		{value: "3x", wantErr: true},
+		{value: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseSince(tt.value, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSince() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !got.Equal(tt.want) {
+				t.Errorf("ParseSince() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"context"
+
+	"github.com/golgoth31/gitcomm/internal/utils"
+)
+
+// separateStreams reports whether output.separate_streams is set: the results of the
+// workflow are then kept for the caller to print on stdout
+func (s *CommitService) separateStreams() bool {
+	return s.config != nil && s.config.Output.SeparateStreams
+}
+
+// Results returns the machine-readable results of the workflow, kept when
+// output.separate_streams is set: the full hash of each commit created (the commit object
+// with --patch-only), or the message of a dry run
+func (s *CommitService) Results() []string {
+	return s.results
+}
+
+// recordResult keeps result when output.separate_streams is set
+func (s *CommitService) recordResult(result string) {
+	if s.separateStreams() {
+		s.results = append(s.results, result)
+	}
+}
+
+// recordCommit keeps the hash of the commit just created at revision when
+// output.separate_streams is set
+func (s *CommitService) recordCommit(ctx context.Context, revision string) {
+	if !s.separateStreams() {
+		return
+	}
+	commits, err := s.gitRepo.ListCommits(ctx, revision, 1)
+	if err != nil || len(commits) == 0 {
+		utils.Logger.Debug().Err(err).Str("revision", revision).Msg("Failed to resolve the created commit")
+		return
+	}
+	s.recordResult(commits[0].Hash)
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/golgoth31/gitcomm/internal/config"
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/repository"
+	"github.com/golgoth31/gitcomm/internal/ui"
+)
+
+func TestCreateCommit_Results(t *testing.T) {
+	ui.SetNonInteractive(true)
+	defer ui.SetNonInteractive(false)
+
+	const message = "feat(api): add the api"
+	tests := []struct {
+		name     string
+		separate bool
+		dryRun   bool
+		want     func(head string) []string
+	}{
+		{"commit", true, false, func(head string) []string { return []string{head} }},
+		{"dry run", true, true, func(string) []string { return []string{message} }},
+		{"streams not separated", false, false, func(string) []string { return nil }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fixture := initMessageRepo(t, true)
+			gitRepo, err := repository.NewGitRepository(fixture.Dir, true, true)
+			if err != nil {
+				t.Fatalf("NewGitRepository() error = %v", err)
+			}
+			cfg := &config.Config{Output: config.OutputConfig{SeparateStreams: tt.separate}}
+			options := &model.CommitOptions{Message: message, NoSignoff: true, DryRun: tt.dryRun}
+
+			service := NewCommitService(gitRepo, options, cfg)
+			if err := service.CreateCommit(context.Background()); err != nil {
+				t.Fatalf("CreateCommit() error = %v", err)
+			}
+
+			head := ""
+			if !tt.dryRun {
+				head = strings.TrimSpace(fixture.Git("rev-parse", "HEAD"))
+			}
+			got, want := service.Results(), tt.want(head)
+			if strings.Join(got, "\n") != strings.Join(want, "\n") || len(got) != len(want) {
+				t.Errorf("Results() = %q, want %q", got, want)
+			}
+		})
+	}
+}
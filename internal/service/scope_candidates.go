@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"sort"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/utils"
+	"github.com/golgoth31/gitcomm/pkg/conventional"
+)
+
+// scopeHistoryDepth is the number of recent commits scanned for previously used scopes
+const scopeHistoryDepth = 200
+
+// scopeCandidates returns the scope suggestions for the scope selection: scopes inferred
+// from the staged directories, then configured scopes, then scopes used in history
+// (most used first), without duplicates
+func (s *CommitService) scopeCandidates(ctx context.Context, state *model.RepositoryState) []string {
+	paths := make([]string, 0, len(state.StagedFiles))
+	for _, file := range state.StagedFiles {
+		paths = append(paths, file.Path)
+	}
+
+	var configured []string
+	if s.config != nil {
+		configured = s.config.Commit.Scopes
+	}
+
+	commits, err := s.gitRepo.ListCommits(ctx, "HEAD", scopeHistoryDepth)
+	if err != nil {
+		// No history yet (new repository)
+		utils.Logger.Debug().Err(err).Msg("Failed to list commits for scope suggestions")
+	}
+
+	return mergeScopes(conventional.InferScopes(paths), configured, historyScopes(commits))
+}
+
+// historyScopes returns the scopes used in commits, most used first
+func historyScopes(commits []model.CommitInfo) []string {
+	counts := make(map[string]int)
+	var scopes []string
+	for i := range commits {
+		scope := conventional.ParseScope(commits[i].Subject())
+		if scope == "" {
+			continue
+		}
+		if counts[scope] == 0 {
+			scopes = append(scopes, scope)
+		}
+		counts[scope]++
+	}
+
+	sort.SliceStable(scopes, func(i, j int) bool { return counts[scopes[i]] > counts[scopes[j]] })
+	return scopes
+}
+
+// mergeScopes concatenates scope lists in order, dropping empty and duplicate scopes
+func mergeScopes(lists ...[]string) []string {
+	seen := make(map[string]bool)
+	var merged []string
+	for _, list := range lists {
+		for _, scope := range list {
+			if scope == "" || seen[scope] {
+				continue
+			}
+			seen[scope] = true
+			merged = append(merged, scope)
+		}
+	}
+	return merged
+}
@@ -0,0 +1,30 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+)
+
+func TestHistoryScopes(t *testing.T) {
+	commits := []model.CommitInfo{
+		{Message: "feat(ui): add pager"},
+		{Message: "fix(repository): handle detached HEAD"},
+		{Message: "chore: bump deps"},
+		{Message: "fix(ui): wrap body\n\nBody"},
+	}
+
+	want := []string{"ui", "repository"}
+	if got := historyScopes(commits); !reflect.DeepEqual(got, want) {
+		t.Errorf("historyScopes() = %v, want %v", got, want)
+	}
+}
+
+func TestMergeScopes(t *testing.T) {
+	got := mergeScopes([]string{"ui"}, []string{"api", "ui", ""}, []string{"cli", "api"})
+	want := []string{"ui", "api", "cli"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("mergeScopes() = %v, want %v", got, want)
+	}
+}
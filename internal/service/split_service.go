@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/golgoth31/gitcomm/internal/config"
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/repository"
+	"github.com/golgoth31/gitcomm/internal/telemetry"
+	"github.com/golgoth31/gitcomm/internal/ui"
+	"github.com/golgoth31/gitcomm/internal/utils"
+	"github.com/golgoth31/gitcomm/pkg/ai/prompt"
+	"github.com/golgoth31/gitcomm/pkg/split"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// restoreIndexTimeout bounds the index restoration after an interrupted split
+const restoreIndexTimeout = 3 * time.Second
+
+// SplitService commits the staged changes as several commits, one per group of files
+type SplitService struct {
+	gitRepo repository.GitRepository
+	commits *CommitService
+}
+
+// NewSplitService creates a new split service
+func NewSplitService(gitRepo repository.GitRepository, options *model.CommitOptions, cfg *config.Config) *SplitService {
+	return &SplitService{
+		gitRepo: gitRepo,
+		commits: NewCommitService(gitRepo, options, cfg),
+	}
+}
+
+// Split proposes groups of the staged files (from the AI provider when aiGroups is set,
+// by directory otherwise), lets the user edit them, then creates one commit per group in
+// order, each with its own message. Files left out of the plan, or not committed because
+// of an error, stay staged.
+func (s *SplitService) Split(ctx context.Context, aiGroups bool) error {
+	state, err := s.commits.RepositoryState(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get repository state: %w", err)
+	}
+	if len(state.StagedFiles) == 0 {
+		return utils.ErrNoChanges
+	}
+	staged := make([]string, len(state.StagedFiles))
+	for i, file := range state.StagedFiles {
+		staged[i] = file.Path
+	}
+
+	groups := s.propose(ctx, state, staged, aiGroups)
+	if !ui.NonInteractive() {
+		if groups, err = s.editPlan(groups, staged); err != nil {
+			return err
+		}
+	}
+	if len(groups) == 0 {
+		return utils.ErrNoChanges
+	}
+
+	snapshot, err := s.gitRepo.SnapshotIndex(ctx)
+	if err != nil {
+		return err
+	}
+	// Bring back what is left of the snapshot, whatever happens: committed files are no
+	// longer staged, the others are staged as before
+	defer func() {
+		restoreCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), restoreIndexTimeout)
+		defer cancel()
+		if err := s.gitRepo.RestoreIndex(restoreCtx, snapshot); err != nil {
+			ui.PrintError("failed to restore the staged files", err)
+		}
+	}()
+
+	for i, group := range groups {
+		fmt.Fprintf(ui.Out(), "\n=== Commit %d/%d: %s (%d file(s)) ===\n", i+1, len(groups), group.Name, len(group.Files))
+		if err := s.commitGroup(ctx, snapshot, group); err != nil {
+			return fmt.Errorf("commit %d/%d (%s): %w", i+1, len(groups), group.Name, err)
+		}
+	}
+	return nil
+}
+
+// Results returns the full hash of each commit created, kept when output.separate_streams
+// is set
+func (s *SplitService) Results() []string {
+	return s.commits.Results()
+}
+
+// propose groups staged by directory, or with the AI provider when aiGroups is set.
+// Files the provider leaves out get a group of their own; unusable answers fall back to
+// the grouping by directory.
+func (s *SplitService) propose(ctx context.Context, state *model.RepositoryState, staged []string, aiGroups bool) []model.FileGroup {
+	if !aiGroups {
+		return split.ByDirectory(staged)
+	}
+
+	groups, err := s.proposeWithAI(ctx, state, staged)
+	if err != nil {
+		ui.PrintError("AI grouping failed, grouping files by directory", err)
+		return split.ByDirectory(staged)
+	}
+	if unlisted := split.Unlisted(groups, staged); len(unlisted) > 0 {
+		groups = append(groups, model.FileGroup{Name: "other changes", Files: unlisted})
+	}
+	return groups
+}
+
+// proposeWithAI asks the AI provider to group staged, sharing what the privacy level allows
+func (s *SplitService) proposeWithAI(ctx context.Context, state *model.RepositoryState, staged []string) ([]model.FileGroup, error) {
+	ctx, span := telemetry.Start(ctx, "propose groups", attribute.String("gitcomm.ai.provider", s.commits.providerName()))
+	groups, err := func() ([]model.FileGroup, error) {
+		shared, err := s.commits.sharedState(ctx, state)
+		if err != nil {
+			return nil, err
+		}
+		if shared.Privacy == model.PrivacyStatsOnly {
+			return nil, fmt.Errorf("file names are private (%s)", model.PrivacyStatsOnly)
+		}
+
+		provider, err := s.commits.newAIProvider()
+		if err != nil {
+			return nil, err
+		}
+		userMsg, err := prompt.SplitUserMessage(shared)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate user message: %w", err)
+		}
+		answer, err := provider.Complete(ctx, prompt.SplitSystemMessage(), userMsg)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", utils.ErrAIProviderUnavailable, err)
+		}
+
+		groups, err := split.ParsePlan(answer, staged)
+		if err != nil {
+			utils.Logger.Debug().Str("answer", answer).Msg("Unusable split answer")
+		}
+		return groups, err
+	}()
+	telemetry.End(span, err)
+	return groups, err
+}
+
+// editPlan shows the plan of groups for editing and returns the edited groups
+func (s *SplitService) editPlan(groups []model.FileGroup, staged []string) ([]model.FileGroup, error) {
+	validate := func(text string) error {
+		_, err := split.ParsePlan(text, staged)
+		return err
+	}
+	text, err := ui.PromptSplitPlan(s.commits.reader, split.FormatPlan(groups), validate)
+	if err != nil {
+		return nil, err
+	}
+	if groups, err = split.ParsePlan(text, staged); err != nil {
+		return nil, err
+	}
+
+	if unlisted := split.Unlisted(groups, staged); len(unlisted) > 0 {
+		fmt.Fprintf(ui.Out(), "%d file(s) not in the plan will stay staged\n", len(unlisted))
+	}
+	confirm, err := ui.PromptConfirm(s.commits.reader, fmt.Sprintf("Create %d commit(s)?", len(groups)), true)
+	if err != nil {
+		return nil, err
+	}
+	if !confirm {
+		return nil, fmt.Errorf("split cancelled by user")
+	}
+	return groups, nil
+}
+
+// commitGroup stages the files of group as they are in snapshot, then commits them with
+// a message of their own
+func (s *SplitService) commitGroup(ctx context.Context, snapshot string, group model.FileGroup) error {
+	if err := s.gitRepo.StageFromSnapshot(ctx, snapshot, group.Files); err != nil {
+		return err
+	}
+	state, err := s.commits.RepositoryState(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get repository state: %w", err)
+	}
+	state.UnstagedFiles = nil
+	if len(state.StagedFiles) == 0 {
+		return utils.ErrNoChanges
+	}
+
+	message, err := s.commits.composeMessage(ctx, state, true)
+	if err != nil {
+		return err
+	}
+	s.commits.applySignoff(message)
+	return s.commits.createCommit(ctx, message)
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golgoth31/gitcomm/internal/config"
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/repository"
+	"github.com/golgoth31/gitcomm/internal/ui"
+)
+
+func TestSplit_CreatesOneCommitPerGroup(t *testing.T) {
+	// The provider groups api.go and README.md and leaves notes.txt out; messages name
+	// the first file of the diff they describe
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		content := `feat: describe notes`
+		switch {
+		case strings.Contains(string(body), "small, logical commits"):
+			content = `commit api\napi.go\n\ncommit documentation\nREADME.md`
+		case strings.Contains(string(body), "api.go"):
+			content = `feat(api): add the api`
+		case strings.Contains(string(body), "README.md"):
+			content = `docs: document the api`
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"choices": [{"message": {"content": "` + content + `"}}]}`))
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{}
+	cfg.AI.Providers = map[string]model.AIProviderConfig{"local": {Endpoint: server.URL}}
+	ui.SetNonInteractive(true)
+	defer ui.SetNonInteractive(false)
+
+	tests := []struct {
+		name     string
+		aiGroups bool
+		want     string
+	}{
+		{"grouped by directory", false, "docs: document the api\nfeat(api): add the api\n"},
+		{"grouped by the provider", true, "feat: describe notes\ndocs: document the api\nfeat(api): add the api\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fixture := initMessageRepo(t, true)
+			fixture.WriteFile("README.md", "# api\n")
+			fixture.Stage("README.md", "notes.txt")
+			// An unstaged change is neither committed nor lost
+			fixture.WriteFile("api.go", "package api\n\nfunc Unstaged() {}\n")
+
+			gitRepo, err := repository.NewGitRepository(fixture.Dir, true, true)
+			if err != nil {
+				t.Fatalf("NewGitRepository() error = %v", err)
+			}
+			options := &model.CommitOptions{AIProvider: "local", NoSignoff: true}
+			if err := NewSplitService(gitRepo, options, cfg).Split(context.Background(), tt.aiGroups); err != nil {
+				t.Fatalf("Split() error = %v", err)
+			}
+
+			if got := fixture.Git("log", "--format=%s"); got != tt.want {
+				t.Errorf("commits =\n%s\nwant\n%s", got, tt.want)
+			}
+			if got := fixture.Git("diff", "--cached", "--name-only"); got != "" {
+				t.Errorf("files still staged: %q", got)
+			}
+			if got := fixture.Git("diff", "--name-only"); got != "api.go\n" {
+				t.Errorf("unstaged files = %q, want api.go", got)
+			}
+		})
+	}
+}
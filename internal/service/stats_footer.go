@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/ui"
+)
+
+// renderStatsFooter renders the metrics footer template with stats
+func renderStatsFooter(tmpl string, stats *model.DiffStats) (string, error) {
+	t, err := template.New("stats_footer").Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("invalid stats footer template: %w", err)
+	}
+	var sb strings.Builder
+	if err := t.Execute(&sb, stats); err != nil {
+		return "", fmt.Errorf("failed to render stats footer: %w", err)
+	}
+	return strings.TrimSpace(sb.String()), nil
+}
+
+// withStatsFooter returns a copy of message with the metrics footers computed from the
+// staged diff appended, when commit.stats_footer is enabled. The message itself is not
+// modified so a retried commit does not get the footers twice. Failures are reported and
+// the message is committed without footers.
+func (s *CommitService) withStatsFooter(ctx context.Context, message *model.CommitMessage) *model.CommitMessage {
+	// fixup! messages are discarded when squashed
+	if s.config == nil || !s.config.Commit.StatsFooter || message.Fixup != nil {
+		return message
+	}
+
+	stats, err := s.gitRepo.StagedDiffStats(ctx)
+	if err != nil {
+		ui.PrintError("stats footer skipped", err)
+		return message
+	}
+	footer, err := renderStatsFooter(s.config.Commit.StatsFooterTemplate, stats)
+	if err != nil {
+		ui.PrintError("stats footer skipped", err)
+		return message
+	}
+	if footer == "" {
+		return message
+	}
+
+	return withFooter(message, footer)
+}
+
+// withFooter returns a copy of message with footer appended after its footers
+func withFooter(message *model.CommitMessage, footer string) *model.CommitMessage {
+	copied := *message
+	copied.AppendFooter(footer)
+	return &copied
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/golgoth31/gitcomm/internal/config"
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/repository"
+	"github.com/golgoth31/gitcomm/pkg/testutil"
+)
+
+func TestRenderStatsFooter(t *testing.T) {
+	stats := &model.DiffStats{FilesChanged: 4, LinesAdded: 120, LinesRemoved: 35}
+
+	tests := []struct {
+		name    string
+		tmpl    string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "default template",
+			tmpl: config.DefaultStatsFooterTemplate,
+			want: "Lines-Added: 120\nLines-Removed: 35\nFiles-Changed: 4",
+		},
+		{
+			name: "custom template",
+			tmpl: "Change-Size: +{{.LinesAdded}}/-{{.LinesRemoved}} in {{.FilesChanged}} files\n",
+			want: "Change-Size: +120/-35 in 4 files",
+		},
+		{
+			name:    "unknown field",
+			tmpl:    "Churn: {{.Churn}}",
+			wantErr: true,
+		},
+		{
+			name:    "invalid syntax",
+			tmpl:    "Lines-Added: {{.LinesAdded",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := renderStatsFooter(tt.tmpl, stats)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("renderStatsFooter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("renderStatsFooter() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithFooters_AppliedOnce(t *testing.T) {
+	fixture := testutil.NewRepoWithCommit(t, map[string]string{"a.txt": "a\n"})
+	fixture.WriteFile("a.txt", "a\nb\n")
+	fixture.Stage("a.txt")
+	gitRepo, err := repository.NewGitRepository(fixture.Dir, true, true)
+	if err != nil {
+		t.Fatalf("NewGitRepository() error = %v", err)
+	}
+	cfg := &config.Config{Commit: config.CommitConfig{StatsFooter: true, StatsFooterTemplate: "Lines-Added: {{.LinesAdded}}"}}
+	s := NewCommitService(gitRepo, &model.CommitOptions{}, cfg)
+	message := &model.CommitMessage{Type: "feat", Subject: "add b"}
+
+	// The previewed message is the committed one: footers are not added a second time
+	previewed := s.withFooters(context.Background(), message)
+	committed := s.withFooters(context.Background(), previewed)
+	if got := committed.FooterText(); got != "Lines-Added: 1" {
+		t.Errorf("footer = %q, want the stats footer once", got)
+	}
+	if message.FootersApplied || strings.TrimSpace(message.FooterText()) != "" {
+		t.Errorf("original message modified: %+v", message)
+	}
+}
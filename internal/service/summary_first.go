@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+
+	"github.com/golgoth31/gitcomm/internal/ai"
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/repository"
+	"github.com/golgoth31/gitcomm/internal/telemetry"
+	"github.com/golgoth31/gitcomm/internal/utils"
+	"github.com/golgoth31/gitcomm/pkg/ai/prompt"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// promptLayout returns the prompt layout of the selected provider, with the summary-first
+// mode forced by --summary-first
+func (s *CommitService) promptLayout() model.PromptLayout {
+	var layout model.PromptLayout
+	if s.config != nil {
+		layout = s.config.AI.Context
+		if provider, ok := s.config.AI.Providers[s.providerName()]; ok {
+			layout = provider.Prompt
+		}
+	}
+	if s.options != nil && s.options.SummaryFirst {
+		layout.SummaryFirst = prompt.SummaryFirstAlways
+	}
+	return layout
+}
+
+// withDiffLimits sets the diff limits of the selected provider on ctx, for the diffs read
+// from the repository
+func (s *CommitService) withDiffLimits(ctx context.Context) context.Context {
+	if s.config == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, repository.DiffLimitsKey, s.promptLayout().Diff)
+}
+
+// selectDiffs runs the first round of the summary-first mode: the provider receives the
+// staged files with their line counts and names the files it needs, and the returned
+// state keeps the diffs of those files only. shared is returned as is when summary-first
+// does not apply, or when the provider does not name any staged file.
+func (s *CommitService) selectDiffs(ctx context.Context, provider ai.AIProvider, shared *model.RepositoryState) *model.RepositoryState {
+	if !prompt.NeedsSummaryFirst(s.promptLayout(), shared) {
+		return shared
+	}
+
+	staged := make([]string, len(shared.StagedFiles))
+	for i, file := range shared.StagedFiles {
+		staged[i] = file.Path
+	}
+	ctx, span := telemetry.Start(ctx, "select diffs", attribute.Int("gitcomm.staged_files", len(staged)))
+	selected, err := func() ([]string, error) {
+		userMsg, err := prompt.SelectionUserMessage(shared)
+		if err != nil {
+			return nil, err
+		}
+		answer, err := ai.CompleteRecorded(ctx, provider, prompt.SelectionSystemMessage(), userMsg)
+		if err != nil {
+			return nil, err
+		}
+		selected := prompt.ParseSelection(answer, staged)
+		if len(selected) == 0 {
+			utils.Logger.Debug().Str("answer", answer).Msg("No staged file selected")
+		}
+		return selected, nil
+	}()
+	telemetry.End(span, err)
+	if err != nil {
+		// The full request reports the provider error, if it persists
+		utils.Logger.Debug().Err(err).Msg("Summary-first selection failed, sending the diffs in one request")
+		return shared
+	}
+	if len(selected) == 0 {
+		return shared
+	}
+	utils.Logger.Debug().Strs("files", selected).Int("staged", len(staged)).Msg("Sending the diffs of the selected files")
+	return withDiffsOf(shared, selected)
+}
+
+// withDiffsOf returns a copy of state where the staged files missing from paths are listed
+// with their line counts instead of their diffs
+func withDiffsOf(state *model.RepositoryState, paths []string) *model.RepositoryState {
+	keep := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		keep[path] = true
+	}
+
+	reduced := *state
+	reduced.StagedFiles = make([]model.FileChange, len(state.StagedFiles))
+	for i, file := range state.StagedFiles {
+		if !keep[file.Path] && file.Diff != "" {
+			added, removed := model.CountDiffLines(file.Diff)
+			file.LinesAdded = max(added, file.LinesAdded)
+			file.LinesRemoved = max(removed, file.LinesRemoved)
+			file.Diff = ""
+		}
+		reduced.StagedFiles[i] = file
+	}
+	return &reduced
+}
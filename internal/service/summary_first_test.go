@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/golgoth31/gitcomm/internal/config"
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/pkg/ai/prompt"
+)
+
+// selectionProvider answers the selection request of the summary-first mode
+type selectionProvider struct {
+	answer  string
+	err     error
+	userMsg string
+}
+
+func (p *selectionProvider) GenerateCommitMessage(ctx context.Context, repoState *model.RepositoryState) (string, error) {
+	return "", errors.New("unexpected commit message request")
+}
+
+func (p *selectionProvider) Complete(ctx context.Context, systemMsg, userMsg string) (string, error) {
+	p.userMsg = userMsg
+	return p.answer, p.err
+}
+
+func TestCommitService_SelectDiffs(t *testing.T) {
+	state := &model.RepositoryState{StagedFiles: []model.FileChange{
+		{Path: "go.sum", Status: "modified", Diff: "+example.com/retry v1.0.0 h1:abc\n+example.com/retry v1.0.0/go.mod h1:def\n"},
+		{Path: "internal/client/retry.go", Status: "added", Diff: "+func Retry() {}\n"},
+		{Path: "logo.png", Status: "added"},
+	}}
+
+	tests := []struct {
+		name      string
+		mode      string
+		flag      bool
+		provider  *selectionProvider
+		wantAsked bool
+		wantDiffs []string // staged files keeping their diff
+	}{
+		{
+			name:      "selected files",
+			mode:      prompt.SummaryFirstAlways,
+			provider:  &selectionProvider{answer: "internal/client/retry.go\n"},
+			wantAsked: true,
+			wantDiffs: []string{"internal/client/retry.go"},
+		},
+		{
+			name:      "forced by the flag",
+			mode:      prompt.SummaryFirstOff,
+			flag:      true,
+			provider:  &selectionProvider{answer: "- go.sum"},
+			wantAsked: true,
+			wantDiffs: []string{"go.sum"},
+		},
+		{
+			name:      "off",
+			provider:  &selectionProvider{answer: "internal/client/retry.go\n"},
+			wantDiffs: []string{"go.sum", "internal/client/retry.go"},
+		},
+		{
+			name:      "no file selected",
+			mode:      prompt.SummaryFirstAlways,
+			provider:  &selectionProvider{answer: "I need every file."},
+			wantAsked: true,
+			wantDiffs: []string{"go.sum", "internal/client/retry.go"},
+		},
+		{
+			name:      "provider error",
+			mode:      prompt.SummaryFirstAlways,
+			provider:  &selectionProvider{err: errors.New("rate limited")},
+			wantAsked: true,
+			wantDiffs: []string{"go.sum", "internal/client/retry.go"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{}
+			cfg.AI.DefaultProvider = "local"
+			cfg.AI.Providers = map[string]model.AIProviderConfig{
+				"local": {Name: "local", Prompt: model.PromptLayout{SummaryFirst: tt.mode}},
+			}
+			s := NewCommitService(nil, &model.CommitOptions{SummaryFirst: tt.flag}, cfg)
+
+			got := s.selectDiffs(context.Background(), tt.provider, state)
+
+			if asked := tt.provider.userMsg != ""; asked != tt.wantAsked {
+				t.Errorf("selection asked = %v, want %v", asked, tt.wantAsked)
+			}
+			if tt.wantAsked && strings.Contains(tt.provider.userMsg, "func Retry") {
+				t.Errorf("selection request holds a diff:\n%s", tt.provider.userMsg)
+			}
+			var diffs []string
+			for _, file := range got.StagedFiles {
+				if file.Diff != "" {
+					diffs = append(diffs, file.Path)
+				}
+			}
+			if strings.Join(diffs, ",") != strings.Join(tt.wantDiffs, ",") {
+				t.Errorf("files with diffs = %v, want %v", diffs, tt.wantDiffs)
+			}
+			if len(got.StagedFiles) != len(state.StagedFiles) {
+				t.Errorf("got %d staged files, want every file listed", len(got.StagedFiles))
+			}
+		})
+	}
+
+	if state.StagedFiles[0].Diff == "" {
+		t.Error("selectDiffs changed the state it was given")
+	}
+}
+
+func TestWithDiffsOf(t *testing.T) {
+	state := &model.RepositoryState{StagedFiles: []model.FileChange{
+		{Path: "a.go", Status: "modified", Diff: "@@ -1 +1,2 @@\n-old\n+new\n+more\n"},
+		{Path: "b.go", Status: "modified", Diff: "+b\n"},
+	}}
+
+	got := withDiffsOf(state, []string{"b.go"})
+	if a := got.StagedFiles[0]; a.Diff != "" || a.LinesAdded != 2 || a.LinesRemoved != 1 {
+		t.Errorf("a.go = %+v, want its line counts without diff", a)
+	}
+	if b := got.StagedFiles[1]; b.Diff != "+b\n" {
+		t.Errorf("b.go = %+v, want its diff kept", b)
+	}
+}
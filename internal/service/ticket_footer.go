@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/golgoth31/gitcomm/internal/config"
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/ui"
+)
+
+// ticketFromBranch returns the first ticket found in branch by patterns, the first capture
+// group of the matching pattern when it has one. Invalid patterns are skipped (reported by
+// config validation).
+func ticketFromBranch(branch string, patterns []string) string {
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			continue
+		}
+		match := re.FindStringSubmatch(branch)
+		switch {
+		case match == nil:
+			continue
+		case len(match) > 1 && match[1] != "":
+			return match[1]
+		case match[0] != "":
+			return match[0]
+		}
+	}
+	return ""
+}
+
+// branchTickets reports whether tickets are extracted from branch names: for the
+// footer (commit.branch_tickets) or the headers of a layout with a {ticket} placeholder
+func (s *CommitService) branchTickets() bool {
+	return s.config != nil && (s.config.Commit.BranchTickets || s.layout.HasTicket())
+}
+
+// branchTicket returns the ticket of branch, or "" when branch tickets are disabled
+func (s *CommitService) branchTicket(branch string) string {
+	if !s.branchTickets() || branch == "" {
+		return ""
+	}
+	patterns := s.config.Commit.TicketPatterns
+	if len(patterns) == 0 {
+		patterns = config.DefaultTicketPatterns
+	}
+	return ticketFromBranch(branch, patterns)
+}
+
+// withTicketFooter returns message with a "Refs: <ticket>" footer for the ticket of the
+// branch committed to, unless the header or the footer already references it
+func (s *CommitService) withTicketFooter(ctx context.Context, message *model.CommitMessage) *model.CommitMessage {
+	// fixup! messages are discarded when squashed
+	if s.config == nil || !s.config.Commit.BranchTickets || message.Fixup != nil {
+		return message
+	}
+
+	branch := s.targetBranch()
+	if branch == "" {
+		current, err := s.gitRepo.GetCurrentBranch(ctx)
+		if err != nil {
+			ui.PrintError("ticket footer skipped", err)
+			return message
+		}
+		branch = current
+	}
+	ticket := s.branchTicket(branch)
+	if ticket == "" || message.Ticket == ticket || strings.Contains(message.FooterText(), ticket) {
+		return message
+	}
+	return withFooter(message, "Refs: "+ticket)
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golgoth31/gitcomm/internal/config"
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/repository"
+	"github.com/golgoth31/gitcomm/pkg/testutil"
+)
+
+func TestTicketFromBranch(t *testing.T) {
+	tests := []struct {
+		name     string
+		branch   string
+		patterns []string
+		want     string
+	}{
+		{name: "jira key", branch: "feature/JIRA-1234-add-login", patterns: config.DefaultTicketPatterns, want: "JIRA-1234"},
+		{name: "no ticket", branch: "main", patterns: config.DefaultTicketPatterns, want: ""},
+		{name: "lowercase key", branch: "feature/jira-1234-add-login", patterns: config.DefaultTicketPatterns, want: ""},
+		{name: "capture group", branch: "fix/42-empty-responses", patterns: []string{`^[a-z]+/(\d+)-`}, want: "42"},
+		{name: "first matching pattern wins", branch: "GH-7/OPS-12", patterns: []string{`OPS-\d+`, `GH-\d+`}, want: "OPS-12"},
+		{name: "invalid pattern skipped", branch: "feature/OPS-12", patterns: []string{`(`, `OPS-\d+`}, want: "OPS-12"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ticketFromBranch(tt.branch, tt.patterns); got != tt.want {
+				t.Errorf("ticketFromBranch(%q) = %q, want %q", tt.branch, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithTicketFooter(t *testing.T) {
+	fixture := testutil.NewRepoWithCommit(t, map[string]string{"login.go": "package login\n"})
+	fixture.Git("checkout", "-q", "-b", "feature/JIRA-1234-add-login")
+	gitRepo, err := repository.NewGitRepository(fixture.Dir, true, true)
+	if err != nil {
+		t.Fatalf("NewGitRepository() error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		enabled    bool
+		options    *model.CommitOptions
+		message    *model.CommitMessage
+		wantFooter string
+	}{
+		{
+			name:       "disabled",
+			message:    &model.CommitMessage{Type: "feat", Subject: "add login"},
+			wantFooter: "",
+		},
+		{
+			name:       "current branch",
+			enabled:    true,
+			message:    &model.CommitMessage{Type: "feat", Subject: "add login", Footer: "Closes #3"},
+			wantFooter: "Closes #3\nRefs: JIRA-1234",
+		},
+		{
+			name:       "already referenced",
+			enabled:    true,
+			message:    &model.CommitMessage{Type: "feat", Subject: "add login", Footer: "Refs: JIRA-1234"},
+			wantFooter: "Refs: JIRA-1234",
+		},
+		{
+			name:       "ticket in header",
+			enabled:    true,
+			message:    &model.CommitMessage{Type: "feat", Ticket: "JIRA-1234", Subject: "add login"},
+			wantFooter: "",
+		},
+		{
+			name:       "target branch",
+			enabled:    true,
+			options:    &model.CommitOptions{Branch: "fix/OPS-7-logout"},
+			message:    &model.CommitMessage{Type: "fix", Subject: "log out"},
+			wantFooter: "Refs: OPS-7",
+		},
+		{
+			name:       "fixup",
+			enabled:    true,
+			message:    &model.CommitMessage{Fixup: &model.CommitInfo{Hash: "abc1234"}},
+			wantFooter: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{Commit: config.CommitConfig{BranchTickets: tt.enabled}}
+			got := NewCommitService(gitRepo, tt.options, cfg).withTicketFooter(context.Background(), tt.message)
+			if got.FooterText() != tt.wantFooter {
+				t.Errorf("footer = %q, want %q", got.FooterText(), tt.wantFooter)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/timer"
+	"github.com/golgoth31/gitcomm/internal/ui"
+)
+
+// withTimeFooter returns a copy of message with a Time-spent footer for the running timer
+// of the configured tracker. The timer is kept to be stopped or annotated after the commit.
+// Tracker failures are reported and the message is committed without the footer.
+func (s *CommitService) withTimeFooter(ctx context.Context, message *model.CommitMessage) *model.CommitMessage {
+	// fixup! messages are discarded when squashed
+	if s.config == nil || s.config.Timer.Tracker == "" || message.Fixup != nil {
+		return message
+	}
+
+	tracker, err := timer.NewTracker(timer.Config{Tracker: s.config.Timer.Tracker, TogglToken: s.config.Timer.TogglToken})
+	if err != nil {
+		ui.PrintError("time footer skipped", err)
+		return message
+	}
+	running, err := tracker.Active(ctx)
+	if err != nil {
+		ui.PrintError("time footer skipped", err)
+		return message
+	}
+	if running == nil {
+		return message
+	}
+
+	s.tracker, s.activeTimer = tracker, running
+	return withFooter(message, timer.Footer(running, time.Now()))
+}
+
+// finishTimer stops or annotates the running timer with the commit subject, per timer.after_commit.
+// The commit exists: failures are only reported.
+func (s *CommitService) finishTimer(ctx context.Context, message *model.CommitMessage) {
+	if s.activeTimer == nil {
+		return
+	}
+	running := s.activeTimer
+	s.activeTimer = nil
+
+	var err error
+	switch s.config.Timer.AfterCommit {
+	case timer.ActionAnnotate:
+		err = s.tracker.Annotate(ctx, running, message.Header())
+	case timer.ActionStop:
+		err = s.tracker.Stop(ctx, running, message.Header())
+	}
+	if err != nil {
+		ui.PrintError("timer update failed", err)
+	}
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/golgoth31/gitcomm/internal/config"
+	"github.com/golgoth31/gitcomm/internal/model"
+)
+
+func TestWithTimeFooter(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("WATSON_DIR", dir)
+	start := time.Now().Add(-95 * time.Minute).Unix()
+	state := []byte(`{"project": "gitcomm", "start": ` + strconv.FormatInt(start, 10) + `}`)
+	if err := os.WriteFile(filepath.Join(dir, "state"), state, 0600); err != nil {
+		t.Fatalf("Failed to write watson state: %v", err)
+	}
+
+	s := &CommitService{config: &config.Config{Timer: config.TimerConfig{Tracker: "watson"}}}
+	message := &model.CommitMessage{Type: "fix", Subject: "handle empty input", Footer: "Refs: #12"}
+
+	got := s.withTimeFooter(context.Background(), message)
+	if !regexp.MustCompile(`^Refs: #12\nTime-spent: 1h3[45]m$`).MatchString(got.FooterText()) {
+		t.Errorf("Footer = %q, want the issue reference then Time-spent: 1h35m", got.FooterText())
+	}
+	if message.FooterText() != "Refs: #12" {
+		t.Errorf("original message modified: %q", message.FooterText())
+	}
+	if s.activeTimer == nil {
+		t.Error("running timer not kept for after the commit")
+	}
+
+	// Fixup commits and stopped timers get no footer
+	fixup := &model.CommitMessage{Fixup: &model.CommitInfo{Message: "fix: parser"}}
+	if got := s.withTimeFooter(context.Background(), fixup); got.FooterText() != "" {
+		t.Errorf("fixup Footer = %q, want none", got.FooterText())
+	}
+	if err := os.WriteFile(filepath.Join(dir, "state"), []byte("{}"), 0600); err != nil {
+		t.Fatalf("Failed to write watson state: %v", err)
+	}
+	if got := s.withTimeFooter(context.Background(), message); got != message {
+		t.Errorf("Footer without running timer = %q, want message unchanged", got.FooterText())
+	}
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/repository"
+	"github.com/golgoth31/gitcomm/internal/ui"
+	"github.com/golgoth31/gitcomm/internal/utils"
+)
+
+// UndoService removes the last commit of the current branch
+type UndoService struct {
+	gitRepo repository.GitRepository
+	reader  *bufio.Reader
+}
+
+// NewUndoService creates a new undo service
+func NewUndoService(gitRepo repository.GitRepository) *UndoService {
+	return &UndoService{
+		gitRepo: gitRepo,
+		reader:  bufio.NewReader(ui.Stdin()),
+	}
+}
+
+// Undo removes the HEAD commit after confirmation and returns it. Its changes stay
+// staged, unless hard is set: they are then discarded with the uncommitted changes.
+// Commits reachable from a remote-tracking branch are refused unless force is set, and
+// merge commits are always refused. In non-interactive mode, hard also requires
+// confirmation to name the branch or the confirmation token (see ConfirmationToken).
+func (s *UndoService) Undo(ctx context.Context, hard, force bool, confirmation string) (*model.CommitInfo, error) {
+	if _, err := s.gitRepo.ListCommits(ctx, "HEAD", 1); err != nil {
+		return nil, fmt.Errorf("nothing to undo: no commit on HEAD")
+	}
+	// HEAD^! is HEAD alone: empty when HEAD is a merge, as merges are not listed
+	commits, err := s.gitRepo.ListCommits(ctx, "HEAD^!", 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(commits) == 0 {
+		return nil, fmt.Errorf("HEAD is a merge commit: undo it with git reset")
+	}
+	head := commits[0]
+
+	if !force {
+		pushed, err := s.gitRepo.IsPushed(ctx, head.Hash)
+		if err != nil {
+			return nil, err
+		}
+		if pushed {
+			return nil, fmt.Errorf("%w: %s %s", utils.ErrCommitPublished, head.ShortHash(), head.Subject())
+		}
+	}
+
+	mode := "--soft"
+	if hard {
+		mode = "--hard"
+		if err := requireConfirmation(ctx, s.gitRepo, "undo", &head, confirmation); err != nil {
+			return nil, err
+		}
+	}
+	printRecovery(mode, &head)
+
+	question := fmt.Sprintf("Undo commit %s %q? Its changes stay staged.", head.ShortHash(), head.Subject())
+	if hard {
+		question = fmt.Sprintf("Undo commit %s %q and discard its changes and all uncommitted changes?", head.ShortHash(), head.Subject())
+	}
+	// Discarding changes defaults to no, except with --yes where nobody can answer
+	confirm, err := ui.PromptConfirm(s.reader, question, !hard || ui.NonInteractive())
+	if err != nil {
+		return nil, fmt.Errorf("failed to prompt for confirmation: %w", err)
+	}
+	if !confirm {
+		return nil, fmt.Errorf("undo cancelled by user")
+	}
+
+	if err := s.gitRepo.UndoLastCommit(ctx, !hard); err != nil {
+		return nil, err
+	}
+	return &head, nil
+}
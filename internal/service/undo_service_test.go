@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/repository"
+	"github.com/golgoth31/gitcomm/internal/ui"
+	"github.com/golgoth31/gitcomm/internal/utils"
+	"github.com/golgoth31/gitcomm/pkg/testutil"
+)
+
+func TestUndo(t *testing.T) {
+	ui.SetNonInteractive(true)
+	defer ui.SetNonInteractive(false)
+
+	tests := []struct {
+		name        string
+		hard        bool
+		published   bool
+		force       bool
+		merge       bool
+		confirm     string // "token" stands for the confirmation token of HEAD
+		wantErr     error
+		wantStaged  string
+		wantContent string
+	}{
+		{name: "soft keeps the changes staged", wantStaged: "M\tapi.go\n", wantContent: "package api\n\nfunc Health() {}\n"},
+		{name: "hard discards the changes", hard: true, confirm: testutil.DefaultBranch, wantContent: "package api\n"},
+		{name: "hard confirmed by the token", hard: true, confirm: "token", wantContent: "package api\n"},
+		{name: "hard without confirmation", hard: true, wantErr: utils.ErrConfirmationRequired},
+		{name: "hard with a stale token", hard: true, confirm: "undo-0000000", wantErr: utils.ErrConfirmationRequired},
+		{name: "published commit", published: true, wantErr: utils.ErrCommitPublished},
+		{name: "published commit with force", published: true, force: true, wantStaged: "M\tapi.go\n", wantContent: "package api\n\nfunc Health() {}\n"},
+		{name: "merge commit", merge: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fixture := testutil.NewRepoWithCommit(t, map[string]string{"api.go": "package api\n"})
+			first := fixture.Head()
+			if tt.merge {
+				fixture.Git("checkout", "-q", "-b", "feature")
+				fixture.CommitFiles("feat: add notes", map[string]string{"notes.txt": "notes\n"})
+				fixture.Git("checkout", "-q", testutil.DefaultBranch)
+				fixture.CommitFiles("feat(api): add health", map[string]string{"api.go": "package api\n\nfunc Health() {}\n"})
+				fixture.Git("merge", "-q", "--no-edit", "feature")
+			} else {
+				fixture.CommitFiles("feat(api): add health", map[string]string{"api.go": "package api\n\nfunc Health() {}\n"})
+			}
+			head := fixture.Head()
+			if tt.published {
+				fixture.Git("update-ref", "refs/remotes/origin/main", "HEAD")
+			}
+
+			gitRepo, err := repository.NewGitRepository(fixture.Dir, true, true)
+			if err != nil {
+				t.Fatalf("NewGitRepository() error = %v", err)
+			}
+			confirm := tt.confirm
+			if confirm == "token" {
+				confirm = ConfirmationToken("undo", &model.CommitInfo{Hash: head})
+			}
+			undone, err := NewUndoService(gitRepo).Undo(context.Background(), tt.hard, tt.force, confirm)
+			if tt.wantErr != nil || tt.merge {
+				if err == nil || (tt.wantErr != nil && !errors.Is(err, tt.wantErr)) {
+					t.Fatalf("Undo() error = %v, want %v", err, tt.wantErr)
+				}
+				if got := fixture.Head(); got != head {
+					t.Errorf("HEAD = %s, want unchanged %s", got, head)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Undo() error = %v", err)
+			}
+
+			if undone.Hash != head {
+				t.Errorf("Undo() = %s, want %s", undone.Hash, head)
+			}
+			if got := fixture.Head(); got != first {
+				t.Errorf("HEAD = %s, want the first commit %s", got, first)
+			}
+			if got := fixture.Git("diff", "--cached", "--name-status"); got != tt.wantStaged {
+				t.Errorf("staged = %q, want %q", got, tt.wantStaged)
+			}
+			if got := fixture.Git("show", ":api.go"); got != tt.wantContent {
+				t.Errorf("api.go in the index = %q, want %q", got, tt.wantContent)
+			}
+
+			// The printed reflog entry restores the undone commit
+			mode := "--soft"
+			if tt.hard {
+				mode = "--hard"
+			}
+			fixture.Git("reset", "-q", mode, "HEAD@{1}")
+			if got := fixture.Head(); got != head {
+				t.Errorf("HEAD after recovery = %s, want %s", got, head)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/golgoth31/gitcomm/internal/config"
+	"github.com/golgoth31/gitcomm/internal/ui"
+	"github.com/golgoth31/gitcomm/internal/utils"
+)
+
+// upstreamFetchTimeout bounds the fetch of commit.upstream_fetch, so that an unreachable
+// remote does not hold the commit
+const upstreamFetchTimeout = 15 * time.Second
+
+// upstreamCheck returns the commit.upstream_check mode, warn by default
+func (s *CommitService) upstreamCheck() string {
+	if s.config == nil || s.config.Commit.UpstreamCheck == "" {
+		return config.UpstreamCheckWarn
+	}
+	return s.config.Commit.UpstreamCheck
+}
+
+// checkUpstream warns when the current branch is behind its upstream, so that the commit
+// is not made on stale history, and in prompt mode asks whether to commit anyway.
+// Commits made elsewhere than on the current branch are not checked.
+func (s *CommitService) checkUpstream(ctx context.Context) error {
+	mode := s.upstreamCheck()
+	if mode == config.UpstreamCheckOff || s.copyOnly() || s.targetBranch() != "" || (s.options != nil && s.options.PatchOnly) {
+		return nil
+	}
+
+	if s.config != nil && s.config.Commit.UpstreamFetch {
+		fetchCtx, cancel := context.WithTimeout(ctx, upstreamFetchTimeout)
+		err := s.gitRepo.FetchUpstream(fetchCtx)
+		cancel()
+		if err != nil {
+			fmt.Fprintf(ui.Out(), "Warning: %v; comparing with the last fetched upstream\n", err)
+		}
+	}
+
+	upstream, err := s.gitRepo.Upstream(ctx)
+	if err != nil || upstream == "" {
+		if err != nil {
+			utils.Logger.Debug().Err(err).Msg("Failed to resolve upstream")
+		}
+		return nil
+	}
+	ahead, behind, err := s.gitRepo.AheadBehind(ctx, upstream)
+	if err != nil {
+		utils.Logger.Debug().Err(err).Msg("Failed to compare with upstream")
+		return nil
+	}
+	if behind == 0 {
+		return nil
+	}
+
+	if ahead > 0 {
+		fmt.Fprintf(ui.Out(), "Warning: the branch and %s have diverged: %d local and %d remote commit(s)\n", upstream, ahead, behind)
+	} else {
+		fmt.Fprintf(ui.Out(), "Warning: the branch is behind %s by %d commit(s)\n", upstream, behind)
+	}
+	fmt.Fprintln(ui.Out(), "Run git pull --rebase first to commit on top of them.")
+
+	if mode != config.UpstreamCheckPrompt || s.dryRun() {
+		return nil
+	}
+	confirm, err := ui.PromptConfirm(s.reader, "Commit anyway?", false)
+	if err != nil {
+		return fmt.Errorf("failed to prompt for upstream check: %w", err)
+	}
+	if !confirm {
+		return utils.ErrBranchBehind
+	}
+	return nil
+}
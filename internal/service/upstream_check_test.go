@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golgoth31/gitcomm/internal/config"
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/repository"
+	"github.com/golgoth31/gitcomm/internal/ui"
+	"github.com/golgoth31/gitcomm/internal/utils"
+	"github.com/golgoth31/gitcomm/pkg/testutil"
+)
+
+func TestCheckUpstream(t *testing.T) {
+	ui.SetNonInteractive(true)
+	defer ui.SetNonInteractive(false)
+
+	tests := []struct {
+		name    string
+		mode    string
+		behind  bool
+		options *model.CommitOptions
+		wantErr error
+	}{
+		{name: "up to date, prompt", mode: config.UpstreamCheckPrompt},
+		{name: "behind, default warns", behind: true},
+		{name: "behind, warn", mode: config.UpstreamCheckWarn, behind: true},
+		{name: "behind, off", mode: config.UpstreamCheckOff, behind: true},
+		{name: "behind, prompt declined", mode: config.UpstreamCheckPrompt, behind: true, wantErr: utils.ErrBranchBehind},
+		{name: "behind, prompt on dry run", mode: config.UpstreamCheckPrompt, behind: true, options: &model.CommitOptions{DryRun: true}},
+		{name: "behind, prompt on another branch", mode: config.UpstreamCheckPrompt, behind: true, options: &model.CommitOptions{Branch: "feature"}},
+		{name: "behind, prompt for a patch", mode: config.UpstreamCheckPrompt, behind: true, options: &model.CommitOptions{PatchOnly: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fixture := testutil.NewRepoWithCommit(t, map[string]string{"README.md": "# test\n"})
+			fixture.Git("remote", "add", "origin", fixture.Dir)
+			fixture.Git("update-ref", "refs/remotes/origin/"+testutil.DefaultBranch, "HEAD")
+			fixture.Git("branch", "-q", "--set-upstream-to=origin/"+testutil.DefaultBranch)
+			if tt.behind {
+				fixture.Commit("feat: remote change")
+				fixture.Git("update-ref", "refs/remotes/origin/"+testutil.DefaultBranch, "HEAD")
+				fixture.Git("reset", "-q", "--hard", "HEAD~1")
+			}
+
+			gitRepo, err := repository.NewGitRepository(fixture.Dir, true, true)
+			if err != nil {
+				t.Fatalf("NewGitRepository() error = %v", err)
+			}
+			cfg := &config.Config{}
+			cfg.Commit.UpstreamCheck = tt.mode
+			err = NewCommitService(gitRepo, tt.options, cfg).checkUpstream(context.Background())
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("checkUpstream() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
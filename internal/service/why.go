@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/telemetry"
+	"github.com/golgoth31/gitcomm/internal/ui"
+	"github.com/golgoth31/gitcomm/internal/utils"
+	"github.com/golgoth31/gitcomm/pkg/ai/prompt"
+	"github.com/golgoth31/gitcomm/pkg/conventional"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// WhyReport explains why a commit message fails validation
+type WhyReport struct {
+	// Explanations teach the rules the message breaks, none when it is valid
+	Explanations []conventional.Explanation
+	// Corrected is the whole message corrected, "" when the message is valid
+	Corrected string
+	// FromAI tells whether Corrected was written by the AI provider rather than offline
+	FromAI bool
+}
+
+// ExplainMessage validates text as a commit message and explains each rule it breaks,
+// with the message corrected. Unless offline or SkipAI is set, the AI provider writes
+// the corrected message; when it fails, or its answer breaks the rules too, the message
+// is corrected offline.
+func (s *CommitService) ExplainMessage(ctx context.Context, text string, offline bool) (*WhyReport, error) {
+	text = strings.TrimSpace(strings.ReplaceAll(text, "\r\n", "\n"))
+	if text == "" {
+		return nil, fmt.Errorf("empty commit message")
+	}
+	message := s.parseAnyMessage(text)
+
+	valid, violations := s.validator.Validate(message)
+	if valid {
+		return &WhyReport{}, nil
+	}
+	report := &WhyReport{
+		Explanations: conventional.Explain(message, violations),
+		Corrected:    s.formatter.Format(conventional.Correct(message)),
+	}
+
+	if offline || (s.options != nil && s.options.SkipAI) {
+		return report, nil
+	}
+	corrected, err := s.correctWithAI(ctx, text, violations)
+	if err != nil {
+		utils.Logger.Debug().Err(err).Msg("AI correction failed, using the offline correction")
+		return report, nil
+	}
+	report.Corrected = corrected
+	report.FromAI = true
+	return report, nil
+}
+
+// parseAnyMessage parses text like a supplied message; a header without "type:" gives a
+// message with no type, so that the missing type is what gets explained
+func (s *CommitService) parseAnyMessage(text string) *model.CommitMessage {
+	if message, err := s.parseAIMessage(text); err == nil {
+		return message
+	}
+	header, body, _ := strings.Cut(text, "\n")
+	return s.withLayout(&model.CommitMessage{Subject: strings.TrimSpace(header), Body: strings.TrimSpace(body)})
+}
+
+// correctWithAI asks the AI provider to fix text, and returns its answer formatted when it
+// follows the rules
+func (s *CommitService) correctWithAI(ctx context.Context, text string, violations []conventional.ValidationError) (string, error) {
+	ctx, span := telemetry.Start(ctx, "correct message", attribute.String("gitcomm.ai.provider", s.providerName()))
+	corrected, err := func() (string, error) {
+		provider, err := s.newAIProvider()
+		if err != nil {
+			return "", err
+		}
+		systemMsg, err := prompt.CorrectionSystemMessage(conventional.NewValidator(), s.layout)
+		if err != nil {
+			return "", err
+		}
+		answer, err := provider.Complete(ctx, systemMsg, prompt.CorrectionUserMessage(text, violations))
+		if err != nil {
+			return "", fmt.Errorf("%w: %v", utils.ErrAIProviderUnavailable, err)
+		}
+		if answer, err = s.postProcessAIMessage(answer); err != nil {
+			return "", err
+		}
+
+		message, err := s.parseAIMessage(answer)
+		if err != nil {
+			return "", fmt.Errorf("unusable answer: %w", err)
+		}
+		if valid, errs := s.validator.Validate(message); !valid {
+			return "", fmt.Errorf("the corrected message breaks the rules too (%s: %s)", errs[0].Field, errs[0].Message)
+		}
+		return s.formatter.Format(message), nil
+	}()
+	telemetry.End(span, err)
+	return corrected, err
+}
+
+// printValidationErrors lists the rules message breaks, each with the message corrected
+func printValidationErrors(message *model.CommitMessage, violations []conventional.ValidationError) {
+	fmt.Fprintln(ui.Out(), "\nValidation errors:")
+	for _, explanation := range conventional.Explain(message, violations) {
+		fmt.Fprintf(ui.Out(), "  - %s: %s\n", explanation.Field, explanation.Rule)
+		if example, _, _ := strings.Cut(explanation.Example, "\n"); example != "" {
+			fmt.Fprintf(ui.Out(), "    e.g. %s\n", example)
+		}
+	}
+	fmt.Fprintln(ui.Out(), "  Run gitcomm why \"<message>\" to see why these rules exist.")
+}
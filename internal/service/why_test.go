@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golgoth31/gitcomm/internal/config"
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/pkg/testutil"
+)
+
+func TestCommitService_ExplainMessage(t *testing.T) {
+	tests := []struct {
+		name       string
+		text       string
+		answer     string
+		offline    bool
+		wantFields []string
+		want       string
+		wantAI     bool
+	}{
+		{
+			name: "valid message",
+			text: "feat(api): add jobs endpoint",
+		},
+		{
+			name:       "corrected by the provider",
+			text:       "feature(api/v2): add jobs endpoint",
+			answer:     "feat(api-v2): add the jobs endpoint",
+			wantFields: []string{"type", "scope"},
+			want:       "feat(api-v2): add the jobs endpoint",
+			wantAI:     true,
+		},
+		{
+			name:       "provider answer breaks the rules",
+			text:       "feature: add jobs endpoint",
+			answer:     "features: add jobs endpoint",
+			wantFields: []string{"type"},
+			want:       "feat: add jobs endpoint",
+		},
+		{
+			name:       "offline",
+			text:       "Fixed login bug",
+			answer:     "fix: handle the expired session",
+			offline:    true,
+			wantFields: []string{"type"},
+			want:       "fix: Fixed login bug",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := testutil.NewProviderServer(t, "local", tt.answer)
+			cfg := &config.Config{}
+			cfg.AI.Providers = map[string]model.AIProviderConfig{"local": {Endpoint: server.Endpoint(), RetryAttempts: 1}}
+			service := NewCommitService(nil, &model.CommitOptions{AIProvider: "local"}, cfg)
+
+			report, err := service.ExplainMessage(context.Background(), tt.text, tt.offline)
+			if err != nil {
+				t.Fatalf("ExplainMessage() error = %v", err)
+			}
+			var fields []string
+			for _, explanation := range report.Explanations {
+				fields = append(fields, explanation.Field)
+			}
+			if len(fields) != len(tt.wantFields) {
+				t.Fatalf("explained fields = %v, want %v", fields, tt.wantFields)
+			}
+			for i := range fields {
+				if fields[i] != tt.wantFields[i] {
+					t.Errorf("explained fields = %v, want %v", fields, tt.wantFields)
+				}
+			}
+			if report.Corrected != tt.want || report.FromAI != tt.wantAI {
+				t.Errorf("Corrected = %q (AI %v), want %q (AI %v)", report.Corrected, report.FromAI, tt.want, tt.wantAI)
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package service
+
+import (
+	"context"
+
+	"github.com/golgoth31/gitcomm/internal/ui"
+	"github.com/golgoth31/gitcomm/internal/utils"
+)
+
+// printWorkspaceSummary prints where the commit is about to be made, before any prompt
+// or staging (commit.workspace_summary). Without prompts there is nobody to check it.
+func (s *CommitService) printWorkspaceSummary(ctx context.Context) {
+	if ui.NonInteractive() || (s.config != nil && !s.config.Commit.WorkspaceSummary) {
+		return
+	}
+	summary, err := s.gitRepo.GetWorkspaceSummary(ctx)
+	if err != nil {
+		utils.Logger.Debug().Err(err).Msg("Failed to get workspace summary")
+		return
+	}
+	ui.PrintWorkspaceSummary(summary)
+}
@@ -0,0 +1,103 @@
+package store
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Memory is a store kept in memory, for tests
+type Memory struct {
+	mu      sync.Mutex
+	commits []Commit
+	usage   []Usage
+	cache   map[string][]byte
+}
+
+var _ Store = (*Memory)(nil)
+
+// NewMemory creates an empty in-memory store
+func NewMemory() *Memory {
+	return &Memory{cache: make(map[string][]byte)}
+}
+
+// RecordCommit adds a generated commit to the history
+func (m *Memory) RecordCommit(_ context.Context, commit Commit) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.commits = append(m.commits, commit)
+	return nil
+}
+
+// RecordUsage adds the token count of a provider request
+func (m *Memory) RecordUsage(_ context.Context, usage Usage) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.usage = append(m.usage, usage)
+	return nil
+}
+
+// Commits returns the commits created since since, newest first
+func (m *Memory) Commits(_ context.Context, since time.Time) ([]Commit, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var commits []Commit
+	for i := len(m.commits) - 1; i >= 0; i-- {
+		if !m.commits[i].CreatedAt.Before(since) {
+			commits = append(commits, m.commits[i])
+		}
+	}
+	slices.SortStableFunc(commits, func(a, b Commit) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	return commits, nil
+}
+
+// UsageTotals returns the usage since since by provider and model
+func (m *Memory) UsageTotals(_ context.Context, since time.Time) ([]UsageTotal, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var totals []UsageTotal
+	for _, usage := range m.usage {
+		if usage.CreatedAt.Before(since) {
+			continue
+		}
+		i := slices.IndexFunc(totals, func(total UsageTotal) bool {
+			return total.Provider == usage.Provider && total.Model == usage.Model
+		})
+		if i < 0 {
+			totals = append(totals, UsageTotal{Provider: usage.Provider, Model: usage.Model})
+			i = len(totals) - 1
+		}
+		totals[i].Requests++
+		totals[i].PromptTokens += usage.PromptTokens
+		totals[i].AnswerTokens += usage.AnswerTokens
+	}
+	slices.SortFunc(totals, func(a, b UsageTotal) int {
+		return cmp.Or(cmp.Compare(a.Provider, b.Provider), cmp.Compare(a.Model, b.Model))
+	})
+	return totals, nil
+}
+
+// GetCache returns the value cached under key, and whether there is one
+func (m *Memory) GetCache(_ context.Context, key string) ([]byte, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	value, ok := m.cache[key]
+	return slices.Clone(value), ok, nil
+}
+
+// PutCache caches value under key, replacing the previous value
+func (m *Memory) PutCache(_ context.Context, key string, value []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cache[key] = slices.Clone(value)
+	return nil
+}
+
+// Close does nothing: the store lives as long as it is referenced
+func (m *Memory) Close() error {
+	return nil
+}
@@ -0,0 +1,226 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	// Pure Go driver: release builds stay CGO_ENABLED=0
+	_ "modernc.org/sqlite"
+)
+
+// migrations are the schema changes, applied in order once each. Version i+1 is
+// migrations[i]: append new migrations, never edit released ones.
+var migrations = [][]string{
+	{
+		`CREATE TABLE commits (
+			id INTEGER PRIMARY KEY,
+			repository TEXT NOT NULL,
+			hash TEXT NOT NULL,
+			subject TEXT NOT NULL,
+			provider TEXT NOT NULL,
+			model TEXT NOT NULL,
+			created_at INTEGER NOT NULL
+		)`,
+		`CREATE INDEX commits_created_at ON commits (created_at)`,
+		`CREATE TABLE usage (
+			id INTEGER PRIMARY KEY,
+			repository TEXT NOT NULL,
+			provider TEXT NOT NULL,
+			model TEXT NOT NULL,
+			prompt_tokens INTEGER NOT NULL,
+			answer_tokens INTEGER NOT NULL,
+			created_at INTEGER NOT NULL
+		)`,
+		`CREATE INDEX usage_created_at ON usage (created_at)`,
+		`CREATE TABLE cache (
+			key TEXT PRIMARY KEY,
+			value BLOB NOT NULL,
+			updated_at INTEGER NOT NULL
+		)`,
+	},
+}
+
+// SQLite is the store kept in an SQLite database file
+type SQLite struct {
+	db *sql.DB
+}
+
+var _ Store = (*SQLite)(nil)
+
+// Open opens the database at path, creating it with 0600 permissions if needed, and
+// applies the pending migrations
+func Open(ctx context.Context, path string) (*SQLite, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create store directory: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_RDONLY|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create store: %w", err)
+	}
+	file.Close()
+
+	// Several gitcomm processes may share the database: wait for the other writers, and
+	// take the write lock when a transaction starts so migrations run one at a time
+	dsn := "file:" + path + "?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_txlock=immediate"
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open store: %w", err)
+	}
+	if err := migrate(ctx, db); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &SQLite{db: db}, nil
+}
+
+// migrate applies the migrations newer than the schema version of db, each one in a
+// transaction recording it in schema_migrations
+func migrate(ctx context.Context, db *sql.DB) error {
+	if _, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER PRIMARY KEY,
+		applied_at INTEGER NOT NULL
+	)`); err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+	for i, statements := range migrations {
+		if err := applyMigration(ctx, db, i+1, statements); err != nil {
+			return fmt.Errorf("failed to apply store migration %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// applyMigration runs statements as migration version unless it was already applied
+func applyMigration(ctx context.Context, db *sql.DB, version int, statements []string) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Checked inside the transaction: another process may have applied it meanwhile
+	var applied int
+	err = tx.QueryRowContext(ctx, `SELECT version FROM schema_migrations WHERE version = ?`, version).Scan(&applied)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	for _, statement := range statements {
+		if _, err := tx.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version, applied_at) VALUES (?, ?)`, version, time.Now().Unix()); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// RecordCommit adds a generated commit to the history
+func (s *SQLite) RecordCommit(ctx context.Context, commit Commit) error {
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO commits (repository, hash, subject, provider, model, created_at) VALUES (?, ?, ?, ?, ?, ?)`,
+		commit.Repository, commit.Hash, commit.Subject, commit.Provider, commit.Model, commit.CreatedAt.UnixNano())
+	if err != nil {
+		return fmt.Errorf("failed to record commit: %w", err)
+	}
+	return nil
+}
+
+// RecordUsage adds the token count of a provider request
+func (s *SQLite) RecordUsage(ctx context.Context, usage Usage) error {
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO usage (repository, provider, model, prompt_tokens, answer_tokens, created_at) VALUES (?, ?, ?, ?, ?, ?)`,
+		usage.Repository, usage.Provider, usage.Model, usage.PromptTokens, usage.AnswerTokens, usage.CreatedAt.UnixNano())
+	if err != nil {
+		return fmt.Errorf("failed to record usage: %w", err)
+	}
+	return nil
+}
+
+// Commits returns the commits created since since, newest first
+func (s *SQLite) Commits(ctx context.Context, since time.Time) ([]Commit, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT repository, hash, subject, provider, model, created_at FROM commits
+		WHERE created_at >= ? ORDER BY created_at DESC, id DESC`, since.UnixNano())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read commit history: %w", err)
+	}
+	defer rows.Close()
+
+	var commits []Commit
+	for rows.Next() {
+		var commit Commit
+		var createdAt int64
+		if err := rows.Scan(&commit.Repository, &commit.Hash, &commit.Subject, &commit.Provider, &commit.Model, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to read commit history: %w", err)
+		}
+		commit.CreatedAt = time.Unix(0, createdAt)
+		commits = append(commits, commit)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read commit history: %w", err)
+	}
+	return commits, nil
+}
+
+// UsageTotals returns the usage since since by provider and model
+func (s *SQLite) UsageTotals(ctx context.Context, since time.Time) ([]UsageTotal, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT provider, model, COUNT(*), SUM(prompt_tokens), SUM(answer_tokens) FROM usage
+		WHERE created_at >= ? GROUP BY provider, model ORDER BY provider, model`, since.UnixNano())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read usage: %w", err)
+	}
+	defer rows.Close()
+
+	var totals []UsageTotal
+	for rows.Next() {
+		var total UsageTotal
+		if err := rows.Scan(&total.Provider, &total.Model, &total.Requests, &total.PromptTokens, &total.AnswerTokens); err != nil {
+			return nil, fmt.Errorf("failed to read usage: %w", err)
+		}
+		totals = append(totals, total)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read usage: %w", err)
+	}
+	return totals, nil
+}
+
+// GetCache returns the value cached under key, and whether there is one
+func (s *SQLite) GetCache(ctx context.Context, key string) ([]byte, bool, error) {
+	var value []byte
+	err := s.db.QueryRowContext(ctx, `SELECT value FROM cache WHERE key = ?`, key).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read cache %s: %w", key, err)
+	}
+	return value, true, nil
+}
+
+// PutCache caches value under key, replacing the previous value
+func (s *SQLite) PutCache(ctx context.Context, key string, value []byte) error {
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO cache (key, value, updated_at) VALUES (?, ?, ?)
+		ON CONFLICT (key) DO UPDATE SET value = excluded.value, updated_at = excluded.updated_at`,
+		key, value, time.Now().Unix())
+	if err != nil {
+		return fmt.Errorf("failed to write cache %s: %w", key, err)
+	}
+	return nil
+}
+
+// Close closes the database
+func (s *SQLite) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,101 @@
+// Package store keeps what gitcomm records across repositories in a small SQLite
+// database under ~/.gitcomm: the commits it generated, the tokens sent to AI providers,
+// and cached values such as the last update check.
+package store
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// FileName is the database file name in the gitcomm home directory (~/.gitcomm)
+const FileName = "gitcomm.db"
+
+// Commit is a commit created with an AI generated message
+type Commit struct {
+	// Repository is the root of the worktree the commit was created in
+	Repository string
+
+	// Hash is the full hash of the commit
+	Hash string
+
+	// Subject is the first line of the commit message
+	Subject string
+
+	// Provider is the AI provider that generated the message
+	Provider string
+
+	// Model is the model of the provider ("" when the provider default was used)
+	Model string
+
+	// CreatedAt is when the commit was created
+	CreatedAt time.Time
+}
+
+// Usage is the token count of one request sent to an AI provider
+type Usage struct {
+	// Repository is the root of the worktree the request was made for
+	Repository string
+
+	// Provider is the AI provider the request was sent to
+	Provider string
+
+	// Model is the model of the provider ("" when the provider default was used)
+	Model string
+
+	// PromptTokens is the estimated number of tokens of the request
+	PromptTokens int
+
+	// AnswerTokens is the estimated number of tokens of the answer
+	AnswerTokens int
+
+	// CreatedAt is when the request was made
+	CreatedAt time.Time
+}
+
+// UsageTotal sums the usage of a provider model
+type UsageTotal struct {
+	Provider     string
+	Model        string
+	Requests     int
+	PromptTokens int
+	AnswerTokens int
+}
+
+// Store records the commits and usage of every repository. Implementations are safe for
+// concurrent use.
+type Store interface {
+	// RecordCommit adds a generated commit to the history
+	RecordCommit(ctx context.Context, commit Commit) error
+
+	// RecordUsage adds the token count of a provider request
+	RecordUsage(ctx context.Context, usage Usage) error
+
+	// Commits returns the commits created since since, newest first
+	Commits(ctx context.Context, since time.Time) ([]Commit, error)
+
+	// UsageTotals returns the usage since since by provider and model, sorted by
+	// provider then model
+	UsageTotals(ctx context.Context, since time.Time) ([]UsageTotal, error)
+
+	// GetCache returns the value cached under key, and whether there is one
+	GetCache(ctx context.Context, key string) ([]byte, bool, error)
+
+	// PutCache caches value under key, replacing the previous value
+	PutCache(ctx context.Context, key string, value []byte) error
+
+	// Close releases the store
+	Close() error
+}
+
+// DefaultPath returns the path of the database (~/.gitcomm/gitcomm.db)
+func DefaultPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".gitcomm", FileName), nil
+}
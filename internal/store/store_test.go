@@ -0,0 +1,148 @@
+package store
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// stores returns the implementations under test, each one empty
+func stores(t *testing.T) map[string]Store {
+	t.Helper()
+	sqlite, err := Open(context.Background(), filepath.Join(t.TempDir(), FileName))
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	t.Cleanup(func() { sqlite.Close() })
+	return map[string]Store{"sqlite": sqlite, "memory": NewMemory()}
+}
+
+func TestStore_Commits(t *testing.T) {
+	ctx := context.Background()
+	week := time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC)
+	old := Commit{Repository: "/src/a", Hash: "1111", Subject: "feat: old", Provider: "openai", CreatedAt: week.Add(-time.Hour)}
+	first := Commit{Repository: "/src/a", Hash: "2222", Subject: "fix: first", Provider: "openai", Model: "gpt-4o", CreatedAt: week.Add(time.Hour)}
+	second := Commit{Repository: "/src/b", Hash: "3333", Subject: "docs: second", Provider: "ollama", CreatedAt: week.Add(2 * time.Hour)}
+
+	for name, store := range stores(t) {
+		t.Run(name, func(t *testing.T) {
+			for _, commit := range []Commit{old, first, second} {
+				if err := store.RecordCommit(ctx, commit); err != nil {
+					t.Fatalf("RecordCommit() error = %v", err)
+				}
+			}
+			commits, err := store.Commits(ctx, week)
+			if err != nil {
+				t.Fatalf("Commits() error = %v", err)
+			}
+			want := []Commit{second, first}
+			if len(commits) != len(want) {
+				t.Fatalf("Commits() = %+v, want %+v", commits, want)
+			}
+			for i := range want {
+				if !commits[i].CreatedAt.Equal(want[i].CreatedAt) {
+					t.Errorf("Commits()[%d].CreatedAt = %v, want %v", i, commits[i].CreatedAt, want[i].CreatedAt)
+				}
+				commits[i].CreatedAt = want[i].CreatedAt
+			}
+			if !reflect.DeepEqual(commits, want) {
+				t.Errorf("Commits() = %+v, want %+v", commits, want)
+			}
+		})
+	}
+}
+
+func TestStore_UsageTotals(t *testing.T) {
+	ctx := context.Background()
+	since := time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC)
+	usages := []Usage{
+		{Provider: "openai", Model: "gpt-4o", PromptTokens: 100, AnswerTokens: 10, CreatedAt: since.Add(-time.Hour)},
+		{Provider: "openai", Model: "gpt-4o", PromptTokens: 200, AnswerTokens: 20, CreatedAt: since.Add(time.Hour)},
+		{Provider: "openai", Model: "gpt-4o", PromptTokens: 300, AnswerTokens: 30, CreatedAt: since.Add(2 * time.Hour)},
+		{Provider: "anthropic", PromptTokens: 50, AnswerTokens: 5, CreatedAt: since.Add(time.Hour)},
+	}
+	want := []UsageTotal{
+		{Provider: "anthropic", Requests: 1, PromptTokens: 50, AnswerTokens: 5},
+		{Provider: "openai", Model: "gpt-4o", Requests: 2, PromptTokens: 500, AnswerTokens: 50},
+	}
+
+	for name, store := range stores(t) {
+		t.Run(name, func(t *testing.T) {
+			for _, usage := range usages {
+				if err := store.RecordUsage(ctx, usage); err != nil {
+					t.Fatalf("RecordUsage() error = %v", err)
+				}
+			}
+			totals, err := store.UsageTotals(ctx, since)
+			if err != nil {
+				t.Fatalf("UsageTotals() error = %v", err)
+			}
+			if !reflect.DeepEqual(totals, want) {
+				t.Errorf("UsageTotals() = %+v, want %+v", totals, want)
+			}
+		})
+	}
+}
+
+func TestStore_Cache(t *testing.T) {
+	ctx := context.Background()
+	for name, store := range stores(t) {
+		t.Run(name, func(t *testing.T) {
+			if _, ok, err := store.GetCache(ctx, "update-check"); ok || err != nil {
+				t.Fatalf("GetCache() on empty store = %v, %v; want false, nil", ok, err)
+			}
+			for _, value := range []string{"first", "second"} {
+				if err := store.PutCache(ctx, "update-check", []byte(value)); err != nil {
+					t.Fatalf("PutCache() error = %v", err)
+				}
+			}
+			value, ok, err := store.GetCache(ctx, "update-check")
+			if err != nil || !ok || string(value) != "second" {
+				t.Errorf("GetCache() = %q, %v, %v; want the last value", value, ok, err)
+			}
+		})
+	}
+}
+
+func TestOpen_Migrations(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "home", FileName)
+
+	store, err := Open(ctx, path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if err := store.RecordCommit(ctx, Commit{Hash: "1111", CreatedAt: time.Now()}); err != nil {
+		t.Fatalf("RecordCommit() error = %v", err)
+	}
+	store.Close()
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("database not created: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("database permissions = %o, want 600", perm)
+	}
+
+	// Reopening applies no migration twice and keeps the data
+	store, err = Open(ctx, path)
+	if err != nil {
+		t.Fatalf("Open() existing store error = %v", err)
+	}
+	defer store.Close()
+	var versions int
+	if err := store.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM schema_migrations`).Scan(&versions); err != nil {
+		t.Fatalf("failed to read schema_migrations: %v", err)
+	}
+	if versions != len(migrations) {
+		t.Errorf("schema_migrations has %d versions, want %d", versions, len(migrations))
+	}
+	commits, err := store.Commits(ctx, time.Time{})
+	if err != nil || len(commits) != 1 {
+		t.Errorf("Commits() after reopening = %+v, %v; want the recorded commit", commits, err)
+	}
+}
@@ -0,0 +1,113 @@
+// Package telemetry traces the commit workflow with OpenTelemetry. Spans are exported to an
+// OTLP/HTTP endpoint when one is configured; otherwise the global no-op tracer is used and
+// tracing costs nothing.
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ShutdownTimeout bounds the export of the remaining spans at exit, so an unreachable
+// collector does not hold the command
+const ShutdownTimeout = 3 * time.Second
+
+// defaultTracesPath is the OTLP/HTTP traces path of collectors
+const defaultTracesPath = "/v1/traces"
+
+// tracerName is the instrumentation scope of gitcomm spans
+const tracerName = "github.com/golgoth31/gitcomm"
+
+// Options configures the span exporter
+type Options struct {
+	// Endpoint is the OTLP/HTTP collector URL, such as http://localhost:4318 (/v1/traces is
+	// added when there is no path). When empty, the standard OTEL_EXPORTER_OTLP_ENDPOINT
+	// variables are used if set.
+	Endpoint string
+	// Headers are sent with each export (e.g. authentication)
+	Headers map[string]string
+	// ServiceVersion is the gitcomm version recorded on the spans
+	ServiceVersion string
+}
+
+// Enabled reports whether spans are exported: an endpoint is configured or set in the
+// environment
+func (o Options) Enabled() bool {
+	return o.Endpoint != "" ||
+		os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" ||
+		os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") != ""
+}
+
+// Setup installs the global tracer provider exporting to the OTLP endpoint. The returned
+// function flushes the pending spans and must be called before exiting. When tracing is
+// not enabled, nothing is installed and the function does nothing.
+func Setup(ctx context.Context, opts Options) (func(context.Context) error, error) {
+	if !opts.Enabled() {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporterOptions := []otlptracehttp.Option{}
+	if opts.Endpoint != "" {
+		exporterOptions = append(exporterOptions, otlptracehttp.WithEndpointURL(tracesURL(opts.Endpoint)))
+	}
+	if len(opts.Headers) > 0 {
+		exporterOptions = append(exporterOptions, otlptracehttp.WithHeaders(opts.Headers))
+	}
+	exporter, err := otlptracehttp.New(ctx, exporterOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName("gitcomm"),
+		semconv.ServiceVersion(opts.ServiceVersion),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe the tracing resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// tracesURL returns the traces URL of endpoint: like OTEL_EXPORTER_OTLP_ENDPOINT, a
+// collector URL without path gets the default /v1/traces path
+func tracesURL(endpoint string) string {
+	u, err := url.Parse(endpoint)
+	if err != nil || strings.Trim(u.Path, "/") != "" {
+		return endpoint
+	}
+	u.Path = defaultTracesPath
+	return u.String()
+}
+
+// Start starts a span of the workflow as a child of the span in ctx
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End ends span, recording err as its error status when not nil
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
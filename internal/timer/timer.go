@@ -0,0 +1,135 @@
+// Package timer reads the running timer of time trackers (Watson, Timewarrior, Toggl)
+// to report the time spent in a commit footer, and stops or annotates it afterwards.
+package timer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// FooterKey is the footer reporting the time spent on a commit
+const FooterKey = "Time-spent"
+
+// Supported trackers
+const (
+	Watson      = "watson"
+	Timewarrior = "timewarrior"
+	Toggl       = "toggl"
+)
+
+// After-commit actions
+const (
+	// ActionNone leaves the timer running
+	ActionNone = "none"
+	// ActionAnnotate adds the commit subject to the running entry
+	ActionAnnotate = "annotate"
+	// ActionStop stops the timer, with the commit subject as note
+	ActionStop = "stop"
+)
+
+// commandTimeout bounds each tracker command or request
+const commandTimeout = 10 * time.Second
+
+// Timer is a running time entry
+type Timer struct {
+	// Start is when the timer was started
+	Start time.Time
+	// Project is the project or first tag of the entry ("" if none)
+	Project string
+
+	// id and workspace identify Toggl entries
+	id        int64
+	workspace int64
+}
+
+// Elapsed returns the time spent since the timer started, at now
+func (t *Timer) Elapsed(now time.Time) time.Duration {
+	if now.Before(t.Start) {
+		return 0
+	}
+	return now.Sub(t.Start)
+}
+
+// Tracker is a time tracker with a running timer
+type Tracker interface {
+	// Active returns the running timer, or nil when none is running
+	Active(ctx context.Context) (*Timer, error)
+	// Annotate adds note to the running timer
+	Annotate(ctx context.Context, timer *Timer, note string) error
+	// Stop stops the running timer, adding note to it
+	Stop(ctx context.Context, timer *Timer, note string) error
+}
+
+// Config selects and configures the tracker
+type Config struct {
+	// Tracker is watson, timewarrior or toggl
+	Tracker string
+	// TogglToken is the Toggl Track API token
+	TogglToken string
+}
+
+// NewTracker returns the configured tracker
+func NewTracker(config Config) (Tracker, error) {
+	switch config.Tracker {
+	case Watson:
+		return &watsonTracker{run: runCommand, stateDir: watsonDir()}, nil
+	case Timewarrior:
+		return &timewarriorTracker{run: runCommand}, nil
+	case Toggl:
+		if config.TogglToken == "" {
+			return nil, fmt.Errorf("toggl requires an API token")
+		}
+		return &togglTracker{token: config.TogglToken, apiURL: togglAPIURL}, nil
+	default:
+		return nil, fmt.Errorf("unsupported tracker %q (supported: %s, %s, %s)", config.Tracker, Timewarrior, Toggl, Watson)
+	}
+}
+
+// ValidateAction checks an after-commit action ("" means none)
+func ValidateAction(action string) error {
+	switch action {
+	case "", ActionNone, ActionAnnotate, ActionStop:
+		return nil
+	default:
+		return fmt.Errorf("unsupported action %q (supported: %s, %s, %s)", action, ActionNone, ActionAnnotate, ActionStop)
+	}
+}
+
+// FormatDuration formats d for the footer, to the minute (e.g. "45m", "1h05m")
+func FormatDuration(d time.Duration) string {
+	minutes := int(d.Round(time.Minute) / time.Minute)
+	if minutes < 60 {
+		return fmt.Sprintf("%dm", minutes)
+	}
+	return fmt.Sprintf("%dh%02dm", minutes/60, minutes%60)
+}
+
+// Footer returns the footer line reporting the time spent on timer at now
+func Footer(timer *Timer, now time.Time) string {
+	return fmt.Sprintf("%s: %s", FooterKey, FormatDuration(timer.Elapsed(now)))
+}
+
+// commandRunner runs a tracker command and returns its output (replaced in tests)
+type commandRunner func(ctx context.Context, name string, args ...string) (string, error)
+
+// runCommand runs name with args
+func runCommand(ctx context.Context, name string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, commandTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return stdout.String(), fmt.Errorf("%s %s: %w: %s", name, strings.Join(args, " "), err, msg)
+		}
+		return stdout.String(), fmt.Errorf("%s %s: %w", name, strings.Join(args, " "), err)
+	}
+	return stdout.String(), nil
+}
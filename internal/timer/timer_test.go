@@ -0,0 +1,197 @@
+package timer
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "0m"},
+		{29 * time.Second, "0m"},
+		{45 * time.Minute, "45m"},
+		{65*time.Minute + 40*time.Second, "1h06m"},
+		{10 * time.Hour, "10h00m"},
+	}
+	for _, tt := range tests {
+		if got := FormatDuration(tt.d); got != tt.want {
+			t.Errorf("FormatDuration(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestFooter(t *testing.T) {
+	start := time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC)
+	timer := &Timer{Start: start}
+	if got := Footer(timer, start.Add(90*time.Minute)); got != "Time-spent: 1h30m" {
+		t.Errorf("Footer() = %q", got)
+	}
+	// Clock skew never gives a negative duration
+	if got := Footer(timer, start.Add(-time.Minute)); got != "Time-spent: 0m" {
+		t.Errorf("Footer() before start = %q", got)
+	}
+}
+
+func TestNewTracker(t *testing.T) {
+	for _, name := range []string{Watson, Timewarrior} {
+		if _, err := NewTracker(Config{Tracker: name}); err != nil {
+			t.Errorf("NewTracker(%s) error = %v", name, err)
+		}
+	}
+	if _, err := NewTracker(Config{Tracker: Toggl}); err == nil {
+		t.Error("NewTracker(toggl) without token: want error")
+	}
+	if _, err := NewTracker(Config{Tracker: "harvest"}); err == nil {
+		t.Error("NewTracker(harvest): want error")
+	}
+}
+
+func TestWatsonTracker(t *testing.T) {
+	dir := t.TempDir()
+	var calls []string
+	w := &watsonTracker{stateDir: dir, run: func(ctx context.Context, name string, args ...string) (string, error) {
+		calls = append(calls, name+" "+strings.Join(args, " "))
+		return "", nil
+	}}
+	ctx := context.Background()
+
+	// No state file, then a stopped state: no timer
+	if timer, err := w.Active(ctx); err != nil || timer != nil {
+		t.Errorf("Active() without state = %v, %v, want nil", timer, err)
+	}
+	writeFile(t, filepath.Join(dir, "state"), "{}")
+	if timer, err := w.Active(ctx); err != nil || timer != nil {
+		t.Errorf("Active() stopped = %v, %v, want nil", timer, err)
+	}
+
+	writeFile(t, filepath.Join(dir, "state"), `{"project": "gitcomm", "start": 1741000000.5, "tags": ["cli"]}`)
+	timer, err := w.Active(ctx)
+	if err != nil || timer == nil {
+		t.Fatalf("Active() = %v, %v, want running timer", timer, err)
+	}
+	if timer.Project != "gitcomm" || timer.Start.Unix() != 1741000000 {
+		t.Errorf("Active() = %+v", timer)
+	}
+
+	if err := w.Annotate(ctx, timer, "fix: parser"); err == nil {
+		t.Error("Annotate() error = nil, want unsupported")
+	}
+	if err := w.Stop(ctx, timer, "fix: parser"); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	if strings.Join(calls, "|") != "watson stop --note fix: parser" {
+		t.Errorf("commands = %q", calls)
+	}
+}
+
+func TestTimewarriorTracker(t *testing.T) {
+	var calls []string
+	outputs := map[string]string{
+		"get dom.active":       "1\n",
+		"get dom.active.start": "2025-03-03T09:15:00\n",
+		"get dom.active.tag.1": "gitcomm\n",
+	}
+	tw := &timewarriorTracker{run: func(ctx context.Context, name string, args ...string) (string, error) {
+		cmd := strings.Join(args, " ")
+		calls = append(calls, cmd)
+		return outputs[cmd], nil
+	}}
+	ctx := context.Background()
+
+	timer, err := tw.Active(ctx)
+	if err != nil || timer == nil {
+		t.Fatalf("Active() = %v, %v, want running timer", timer, err)
+	}
+	want := time.Date(2025, 3, 3, 9, 15, 0, 0, time.Local)
+	if !timer.Start.Equal(want) || timer.Project != "gitcomm" {
+		t.Errorf("Active() = %+v, want start %v and project gitcomm", timer, want)
+	}
+
+	calls = nil
+	if err := tw.Stop(ctx, timer, "fix: parser"); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	if strings.Join(calls, "|") != "stop|annotate @1 fix: parser" {
+		t.Errorf("commands = %q", calls)
+	}
+
+	outputs["get dom.active"] = "0\n"
+	if timer, err := tw.Active(ctx); err != nil || timer != nil {
+		t.Errorf("Active() inactive = %v, %v, want nil", timer, err)
+	}
+}
+
+func TestTogglTracker(t *testing.T) {
+	var requests []string
+	var description string
+	current := `{"id": 42, "workspace_id": 7, "start": "2025-03-03T09:00:00Z", "description": "Sprint 12"}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, _ := r.BasicAuth(); user != "secret" || pass != "api_token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch {
+		case r.URL.Path == "/me/time_entries/current":
+			io.WriteString(w, current)
+		case r.Method == http.MethodPut:
+			var body map[string]string
+			json.NewDecoder(r.Body).Decode(&body)
+			description = body["description"]
+			io.WriteString(w, "{}")
+		default:
+			io.WriteString(w, "{}")
+		}
+	}))
+	defer server.Close()
+
+	tr := &togglTracker{token: "secret", apiURL: server.URL}
+	ctx := context.Background()
+
+	timer, err := tr.Active(ctx)
+	if err != nil || timer == nil {
+		t.Fatalf("Active() = %v, %v, want running timer", timer, err)
+	}
+	if !timer.Start.Equal(time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC)) || timer.Project != "Sprint 12" {
+		t.Errorf("Active() = %+v", timer)
+	}
+
+	if err := tr.Stop(ctx, timer, "fix: parser"); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	if description != "Sprint 12 - fix: parser" {
+		t.Errorf("description = %q", description)
+	}
+	want := "GET /me/time_entries/current|PUT /workspaces/7/time_entries/42|PATCH /workspaces/7/time_entries/42/stop"
+	if strings.Join(requests, "|") != want {
+		t.Errorf("requests = %q, want %q", requests, want)
+	}
+
+	current = "null"
+	if timer, err := tr.Active(ctx); err != nil || timer != nil {
+		t.Errorf("Active() without entry = %v, %v, want nil", timer, err)
+	}
+
+	tr.token = "wrong"
+	if _, err := tr.Active(ctx); err == nil {
+		t.Error("Active() with a wrong token: want error")
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+}
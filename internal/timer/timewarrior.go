@@ -0,0 +1,55 @@
+package timer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// timewarriorLayout is the layout of dom.active.start (local time)
+const timewarriorLayout = "2006-01-02T15:04:05"
+
+// timewarriorTracker queries and updates the active interval with the timew CLI
+type timewarriorTracker struct {
+	run commandRunner
+}
+
+func (t *timewarriorTracker) Active(ctx context.Context) (*Timer, error) {
+	active, err := t.run(ctx, "timew", "get", "dom.active")
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(active) != "1" {
+		return nil, nil
+	}
+
+	startOut, err := t.run(ctx, "timew", "get", "dom.active.start")
+	if err != nil {
+		return nil, err
+	}
+	start, err := time.ParseInLocation(timewarriorLayout, strings.TrimSpace(startOut), time.Local)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse timewarrior start %q: %w", strings.TrimSpace(startOut), err)
+	}
+
+	// The first tag usually names the project; intervals may have no tag
+	project := ""
+	if tag, err := t.run(ctx, "timew", "get", "dom.active.tag.1"); err == nil {
+		project = strings.TrimSpace(tag)
+	}
+	return &Timer{Start: start, Project: project}, nil
+}
+
+func (t *timewarriorTracker) Annotate(ctx context.Context, timer *Timer, note string) error {
+	// @1 is the latest interval: the active one, or the one just stopped
+	_, err := t.run(ctx, "timew", "annotate", "@1", note)
+	return err
+}
+
+func (t *timewarriorTracker) Stop(ctx context.Context, timer *Timer, note string) error {
+	if _, err := t.run(ctx, "timew", "stop"); err != nil {
+		return err
+	}
+	return t.Annotate(ctx, timer, note)
+}
@@ -0,0 +1,103 @@
+package timer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// togglAPIURL is the Toggl Track API v9 base URL
+const togglAPIURL = "https://api.track.toggl.com/api/v9"
+
+// togglTracker uses the Toggl Track API, authenticated with an API token
+type togglTracker struct {
+	token  string
+	apiURL string
+}
+
+// togglEntry is a Toggl time entry
+type togglEntry struct {
+	ID          int64     `json:"id"`
+	WorkspaceID int64     `json:"workspace_id"`
+	Start       time.Time `json:"start"`
+	Description string    `json:"description"`
+}
+
+func (t *togglTracker) Active(ctx context.Context) (*Timer, error) {
+	var entry *togglEntry
+	if err := t.do(ctx, http.MethodGet, "/me/time_entries/current", nil, &entry); err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, nil
+	}
+	return &Timer{Start: entry.Start, Project: entry.Description, id: entry.ID, workspace: entry.WorkspaceID}, nil
+}
+
+// Annotate appends note to the entry description
+func (t *togglTracker) Annotate(ctx context.Context, timer *Timer, note string) error {
+	description := note
+	if timer.Project != "" {
+		description = timer.Project + " - " + note
+	}
+	body := map[string]string{"description": description}
+	return t.do(ctx, http.MethodPut, t.entryPath(timer), body, nil)
+}
+
+func (t *togglTracker) Stop(ctx context.Context, timer *Timer, note string) error {
+	if err := t.Annotate(ctx, timer, note); err != nil {
+		return err
+	}
+	return t.do(ctx, http.MethodPatch, t.entryPath(timer)+"/stop", nil, nil)
+}
+
+// entryPath returns the API path of the timer entry
+func (t *togglTracker) entryPath(timer *Timer) string {
+	return fmt.Sprintf("/workspaces/%d/time_entries/%d", timer.workspace, timer.id)
+}
+
+// do sends an API request with an optional JSON body and decodes the JSON answer into out
+func (t *togglTracker) do(ctx context.Context, method, path string, body, out any) error {
+	ctx, cancel := context.WithTimeout(ctx, commandTimeout)
+	defer cancel()
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode toggl request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, t.apiURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create toggl request: %w", err)
+	}
+	req.SetBasicAuth(t.token, "api_token")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach toggl: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("toggl returned %s: %s", resp.Status, bytes.TrimSpace(msg))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode toggl response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package timer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// watsonTracker reads the Watson state file and stops frames with the watson CLI
+type watsonTracker struct {
+	run      commandRunner
+	stateDir string
+}
+
+// watsonState is the content of the Watson state file ({} when no frame is running)
+type watsonState struct {
+	Project string   `json:"project"`
+	Start   *float64 `json:"start"`
+	Tags    []string `json:"tags"`
+}
+
+// watsonDir returns the Watson configuration directory ($WATSON_DIR, or the user config directory)
+func watsonDir() string {
+	if dir := os.Getenv("WATSON_DIR"); dir != "" {
+		return dir
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "watson")
+}
+
+func (w *watsonTracker) Active(ctx context.Context) (*Timer, error) {
+	data, err := os.ReadFile(filepath.Join(w.stateDir, "state"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read watson state: %w", err)
+	}
+
+	var state watsonState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse watson state: %w", err)
+	}
+	if state.Start == nil {
+		return nil, nil
+	}
+	sec := int64(*state.Start)
+	return &Timer{Start: time.Unix(sec, 0), Project: state.Project}, nil
+}
+
+// Annotate is not possible: Watson only takes a note when the frame stops
+func (w *watsonTracker) Annotate(ctx context.Context, timer *Timer, note string) error {
+	return fmt.Errorf("watson can only add a note when stopping the frame (use the %q action)", ActionStop)
+}
+
+func (w *watsonTracker) Stop(ctx context.Context, timer *Timer, note string) error {
+	_, err := w.run(ctx, "watson", "stop", "--note", note)
+	return err
+}
@@ -0,0 +1,19 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/atotto/clipboard"
+)
+
+// CopyToClipboard places text on the system clipboard: pbcopy on macOS, the Windows
+// clipboard API, and wl-copy (Wayland), xclip or xsel (X11) on Linux and BSD
+func CopyToClipboard(text string) error {
+	if clipboard.Unsupported {
+		return fmt.Errorf("no clipboard available: install wl-clipboard (Wayland), xclip or xsel (X11)")
+	}
+	if err := clipboard.WriteAll(text); err != nil {
+		return fmt.Errorf("failed to copy to the clipboard: %w", err)
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package ui
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/golgoth31/gitcomm/internal/model"
@@ -13,36 +14,125 @@ func DisplayCommitMessage(message *model.CommitMessage) string {
 	var lines []string
 
 	// Format header
-	header := message.Type
-	if message.Scope != "" {
-		header = fmt.Sprintf("%s(%s)", header, message.Scope)
-	}
-	header = fmt.Sprintf("%s: %s", header, message.Subject)
-	lines = append(lines, header)
+	lines = append(lines, message.Header())
 
 	// Add body if present
 	if message.Body != "" {
 		lines = append(lines, "")
-		// Wrap body at 72 characters
-		wrappedBody := wrapText(message.Body, 72)
+		// Wrap body at 72 characters, or the terminal width if narrower
+		wrappedBody := wrapText(message.Body, min(72, TerminalWidth()))
 		lines = append(lines, wrappedBody)
 	}
 
 	// Add footer if present
-	if message.Footer != "" {
+	if footer := message.FooterText(); footer != "" {
 		lines = append(lines, "")
-		lines = append(lines, message.Footer)
+		lines = append(lines, footer)
 	}
 
 	// Add signoff indicator if enabled
 	if message.Signoff {
 		lines = append(lines, "")
-		lines = append(lines, "(Signed-off-by will be added)")
+		if message.SignoffIdentity != nil {
+			lines = append(lines, fmt.Sprintf("(Signed-off-by: %s will be added)", message.SignoffIdentity))
+		} else {
+			lines = append(lines, "(Signed-off-by will be added)")
+		}
 	}
 
 	return strings.Join(lines, "\n")
 }
 
+// FormatCommitTarget describes where the commit will be created for the confirmation screen,
+// e.g. "Branch: main (ahead 1 of origin/main) · after: fix: previous change".
+// targetBranch overrides the current branch (--branch); returns "" if nothing is known.
+// Long branch names and subjects are shortened to fit the terminal width.
+func FormatCommitTarget(state *model.RepositoryState, targetBranch string) string {
+	return formatCommitTarget(state, targetBranch, TerminalWidth())
+}
+
+// formatCommitTarget implements FormatCommitTarget for a given terminal width
+func formatCommitTarget(state *model.RepositoryState, targetBranch string, width int) string {
+	if state == nil {
+		return ""
+	}
+
+	var parts []string
+	switch {
+	case targetBranch != "":
+		parts = append(parts, "Branch: "+TruncateMiddle(targetBranch, width/2))
+	case state.Branch != "":
+		branch := "Branch: " + TruncateMiddle(state.Branch, width/2)
+		if tracking := state.TrackingStatus(); tracking != "" {
+			branch += " (" + tracking + ")"
+		}
+		parts = append(parts, branch)
+	default:
+		parts = append(parts, "Branch: (detached HEAD)")
+	}
+
+	if state.LastCommitSubject != "" && targetBranch == "" {
+		parts = append(parts, "after: "+state.LastCommitSubject)
+	}
+	return TruncateEnd(strings.Join(parts, " · "), width)
+}
+
+// PrintWorkspaceSummary prints the repository, branch, file counts and last commit age
+// before any prompt, so users can check where they are before anything is staged
+func PrintWorkspaceSummary(summary *model.WorkspaceSummary) {
+	fmt.Fprintln(Out(), formatWorkspaceSummary(summary, time.Now(), TerminalWidth()))
+}
+
+// formatWorkspaceSummary implements PrintWorkspaceSummary for a given time and terminal
+// width, e.g.:
+//
+//	Repository: /src/gitcomm · Branch: main (ahead 1 of origin/main)
+//	Changes: 2 staged, 1 modified, 3 untracked · last commit 2 hours ago
+func formatWorkspaceSummary(summary *model.WorkspaceSummary, now time.Time, width int) string {
+	location := "Repository: " + TruncateMiddle(summary.Root, width/2)
+	switch {
+	case summary.Branch == "":
+		location += " · Branch: (detached HEAD)"
+	case summary.TrackingStatus() != "":
+		location += fmt.Sprintf(" · Branch: %s (%s)", summary.Branch, summary.TrackingStatus())
+	default:
+		location += " · Branch: " + summary.Branch
+	}
+
+	changes := fmt.Sprintf("Changes: %d staged, %d modified, %d untracked", summary.Staged, summary.Modified, summary.Untracked)
+	if summary.LastCommit.IsZero() {
+		changes += " · no commits yet"
+	} else {
+		changes += " · last commit " + formatAge(now.Sub(summary.LastCommit))
+	}
+	return TruncateEnd(location, width) + "\n" + TruncateEnd(changes, width)
+}
+
+// formatAge returns a rounded-down duration in words: "just now", "5 minutes ago", "3 days ago"
+func formatAge(age time.Duration) string {
+	const day = 24 * time.Hour
+	var count int
+	var unit string
+	switch {
+	case age < time.Minute:
+		return "just now"
+	case age < time.Hour:
+		count, unit = int(age/time.Minute), "minute"
+	case age < day:
+		count, unit = int(age/time.Hour), "hour"
+	case age < 30*day:
+		count, unit = int(age/day), "day"
+	case age < 365*day:
+		count, unit = int(age/(30*day)), "month"
+	default:
+		count, unit = int(age/(365*day)), "year"
+	}
+	if count > 1 {
+		unit += "s"
+	}
+	return fmt.Sprintf("%d %s ago", count, unit)
+}
+
 // GetVisualIndicator returns the visual indicator character for the given prompt state
 // with appropriate lipgloss styling applied
 func GetVisualIndicator(state PromptState) string {
@@ -135,5 +225,5 @@ func FormatPostValidationSummary(title string, value interface{}) string {
 // printPostValidationSummary prints a post-validation summary line with green checkmark
 // Format: "✓ <title>: <value>"
 func printPostValidationSummary(title string, value interface{}) {
-	fmt.Println(FormatPostValidationSummary(title, value))
+	fmt.Fprintln(Out(), TruncateEnd(FormatPostValidationSummary(title, value), TerminalWidth()))
 }
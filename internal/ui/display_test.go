@@ -3,6 +3,9 @@ package ui
 import (
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/golgoth31/gitcomm/internal/model"
 )
 
 func TestGetVisualIndicator(t *testing.T) {
@@ -188,3 +191,103 @@ func TestFormatPostValidationSummary(t *testing.T) {
 		})
 	}
 }
+
+func TestFormatCommitTarget(t *testing.T) {
+	tests := []struct {
+		name         string
+		state        *model.RepositoryState
+		targetBranch string
+		want         string
+	}{
+		{
+			name:  "nil state",
+			state: nil,
+			want:  "",
+		},
+		{
+			name:  "branch with upstream and previous commit",
+			state: &model.RepositoryState{Branch: "main", Upstream: "origin/main", Ahead: 1, Behind: 2, LastCommitSubject: "fix: previous"},
+			want:  "Branch: main (ahead 1, behind 2 of origin/main) · after: fix: previous",
+		},
+		{
+			name:  "branch without upstream",
+			state: &model.RepositoryState{Branch: "feature/x"},
+			want:  "Branch: feature/x",
+		},
+		{
+			name:  "detached HEAD",
+			state: &model.RepositoryState{LastCommitSubject: "chore: release"},
+			want:  "Branch: (detached HEAD) · after: chore: release",
+		},
+		{
+			name:         "target branch override",
+			state:        &model.RepositoryState{Branch: "main", Upstream: "origin/main", LastCommitSubject: "fix: previous"},
+			targetBranch: "wip/checkpoint",
+			want:         "Branch: wip/checkpoint",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatCommitTarget(tt.state, tt.targetBranch); got != tt.want {
+				t.Errorf("FormatCommitTarget() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatWorkspaceSummary(t *testing.T) {
+	now := time.Date(2025, 3, 3, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		summary *model.WorkspaceSummary
+		want    string
+	}{
+		{
+			name: "branch with upstream",
+			summary: &model.WorkspaceSummary{
+				Root: "/src/gitcomm", Branch: "main", Upstream: "origin/main", Ahead: 1,
+				Staged: 2, Modified: 1, Untracked: 3, LastCommit: now.Add(-2*time.Hour - 10*time.Minute),
+			},
+			want: "Repository: /src/gitcomm · Branch: main (ahead 1 of origin/main)\nChanges: 2 staged, 1 modified, 3 untracked · last commit 2 hours ago",
+		},
+		{
+			name:    "new repository",
+			summary: &model.WorkspaceSummary{Root: "/src/new", Branch: "main", Untracked: 1},
+			want:    "Repository: /src/new · Branch: main\nChanges: 0 staged, 0 modified, 1 untracked · no commits yet",
+		},
+		{
+			name:    "detached HEAD",
+			summary: &model.WorkspaceSummary{Root: "/src/gitcomm", Modified: 1, LastCommit: now.Add(-time.Minute)},
+			want:    "Repository: /src/gitcomm · Branch: (detached HEAD)\nChanges: 0 staged, 1 modified, 0 untracked · last commit 1 minute ago",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatWorkspaceSummary(tt.summary, now, 120); got != tt.want {
+				t.Errorf("formatWorkspaceSummary() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatAge(t *testing.T) {
+	tests := []struct {
+		age  time.Duration
+		want string
+	}{
+		{30 * time.Second, "just now"},
+		{59 * time.Minute, "59 minutes ago"},
+		{time.Hour, "1 hour ago"},
+		{3 * 24 * time.Hour, "3 days ago"},
+		{65 * 24 * time.Hour, "2 months ago"},
+		{800 * 24 * time.Hour, "2 years ago"},
+	}
+
+	for _, tt := range tests {
+		if got := formatAge(tt.age); got != tt.want {
+			t.Errorf("formatAge(%v) = %q, want %q", tt.age, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// defaultEditor is used when neither VISUAL nor EDITOR is set (same default as git)
+const defaultEditor = "vi"
+
+// ResolveEditor returns the editor command: VISUAL, then EDITOR, then vi
+func ResolveEditor() string {
+	for _, name := range []string{"VISUAL", "EDITOR"} {
+		if editor := strings.TrimSpace(os.Getenv(name)); editor != "" {
+			return editor
+		}
+	}
+	return defaultEditor
+}
+
+// OpenEditor opens path in the editor and waits for it to exit. The editor command runs
+// through the shell so that it may carry arguments ("code --wait").
+func OpenEditor(path string) error {
+	editor := ResolveEditor()
+	cmd := exec.Command("sh", "-c", editor+` "$1"`, "sh", path)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("editor %q failed: %w", editor, err)
+	}
+	return nil
+}
@@ -0,0 +1,199 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/golgoth31/gitcomm/internal/diagnostics"
+	"github.com/golgoth31/gitcomm/internal/repository"
+	"github.com/golgoth31/gitcomm/internal/utils"
+)
+
+// configReference points to the configuration documentation
+const configReference = "https://github.com/golgoth31/gitcomm#ai-configuration (config: ~/.gitcomm/config.yaml)"
+
+// ErrorScreen is a structured description of a failure with remediation steps
+type ErrorScreen struct {
+	// Title is the short description of what failed
+	Title string
+
+	// Details is the underlying error message
+	Details string
+
+	// Remediation lists the steps that usually fix the failure
+	Remediation []string
+
+	// References lists documentation or configuration pointers
+	References []string
+}
+
+// errorRule maps a known failure to its remediation
+type errorRule struct {
+	matches     func(err error, msg string) bool
+	title       string
+	remediation []string
+	references  []string
+}
+
+// knownErrors is evaluated in order; the first matching rule wins
+var knownErrors = []errorRule{
+	{
+		matches: func(err error, _ string) bool { return errors.Is(err, utils.ErrNotGitRepository) },
+		title:   "Not a git repository",
+		remediation: []string{
+			"Run gitcomm from inside a git working tree",
+			"Initialize a repository with 'git init' if this is a new project",
+		},
+	},
+	{
+		matches: func(err error, _ string) bool { return errors.Is(err, utils.ErrRepositoryDisabled) },
+		title:   "gitcomm is disabled in this repository",
+		remediation: []string{
+			"Write the commit message yourself with 'git commit'",
+			"Ask the repository maintainers before removing the .gitcomm-disable file or the gitcomm.enabled setting",
+		},
+		references: []string{"marker file: .gitcomm-disable at the root of the worktree; git config: gitcomm.enabled"},
+	},
+	{
+		matches: func(err error, _ string) bool { return errors.Is(err, repository.ErrGitNotFound) },
+		title:   "git executable not found",
+		remediation: []string{
+			"Install git 2.34.0 or later",
+			"Make sure the git binary is in your PATH",
+		},
+	},
+	{
+		matches: func(_ error, msg string) bool {
+			return strings.Contains(msg, "head detached") || strings.Contains(msg, "detached head") ||
+				strings.Contains(msg, "not currently on a branch")
+		},
+		title: "HEAD is detached",
+		remediation: []string{
+			"Switch to a branch with 'git switch <branch>'",
+			"Or create a branch from the current commit with 'git switch -c <new-branch>'",
+		},
+	},
+	{
+		matches: func(err error, msg string) bool {
+			return errors.Is(err, repository.ErrGitSigningFailed) || strings.Contains(msg, "signingkey") ||
+				strings.Contains(msg, "signing key")
+		},
+		title: "Commit signing failed",
+		remediation: []string{
+			"Check that 'git config user.signingkey' points to an existing key",
+			"For SSH signing, make sure the private key next to the .pub file exists and is loaded in ssh-agent",
+			"For GPG signing, check that 'gpg --list-secret-keys <key>' finds the key and that gpg-agent can unlock it",
+			"Use --no-sign to create an unsigned commit",
+		},
+		references: []string{"git config: gpg.format, gpg.program, user.signingkey, commit.gpgsign"},
+	},
+	{
+		matches: func(_ error, msg string) bool {
+			return strings.Contains(msg, "model") && (strings.Contains(msg, "not found") ||
+				strings.Contains(msg, "does not exist") || strings.Contains(msg, "invalid model") ||
+				strings.Contains(msg, "unknown model"))
+		},
+		title: "Invalid AI model",
+		remediation: []string{
+			"Check the 'model' value of the provider in the config file",
+			"Make sure your account has access to this model",
+		},
+		references: []string{configReference},
+	},
+	{
+		matches: func(_ error, msg string) bool {
+			return strings.Contains(msg, "api key not configured") || strings.Contains(msg, "api key invalid") ||
+				strings.Contains(msg, "401") || strings.Contains(msg, "unauthorized")
+		},
+		title: "Missing or invalid AI provider API key",
+		remediation: []string{
+			"Set the 'api_key' of the provider in the config file, e.g. api_key: ${OPENAI_API_KEY}",
+			"Export the referenced environment variable before running gitcomm",
+			"Use --skip-ai to write the message manually",
+		},
+		references: []string{configReference},
+	},
+	{
+		matches: func(_ error, msg string) bool {
+			return strings.Contains(msg, "provider") && strings.Contains(msg, "not configured")
+		},
+		title: "AI provider not configured",
+		remediation: []string{
+			"Add the provider under 'ai.providers' in the config file",
+			"Or select a configured provider with --provider <name>",
+		},
+		references: []string{configReference},
+	},
+	{
+		matches: func(err error, _ string) bool { return errors.Is(err, repository.ErrGitPermissionDenied) },
+		title:   "Permission denied",
+		remediation: []string{
+			"Check that you own the repository files and the .git directory",
+			"Remove a stale .git/index.lock if no other git process is running",
+		},
+	},
+}
+
+// NewErrorScreen builds an error screen for err, adding remediation steps for known failures.
+// context describes the operation that failed (e.g. "commit failed").
+func NewErrorScreen(context string, err error) ErrorScreen {
+	screen := ErrorScreen{
+		Title:   context,
+		Details: repository.FormatErrorForDisplay(err),
+	}
+
+	msg := strings.ToLower(screen.Details)
+	for _, rule := range knownErrors {
+		if rule.matches(err, msg) {
+			screen.Title = fmt.Sprintf("%s: %s", context, rule.title)
+			screen.Remediation = rule.remediation
+			screen.References = rule.references
+			break
+		}
+	}
+	return screen
+}
+
+// Render returns the styled error screen
+func (e ErrorScreen) Render() string {
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("1"))
+	headingStyle := lipgloss.NewStyle().Bold(true)
+
+	var sb strings.Builder
+	sb.WriteString(titleStyle.Render("✗ Error: " + e.Title))
+	sb.WriteString("\n")
+	if e.Details != "" {
+		sb.WriteString("\n  " + strings.ReplaceAll(e.Details, "\n", "\n  ") + "\n")
+	}
+
+	if len(e.Remediation) > 0 {
+		sb.WriteString("\n  " + headingStyle.Render("How to fix:") + "\n")
+		for i, step := range e.Remediation {
+			sb.WriteString(fmt.Sprintf("    %d. %s\n", i+1, step))
+		}
+	}
+
+	if len(e.References) > 0 {
+		sb.WriteString("\n  " + headingStyle.Render("See:") + "\n")
+		for _, ref := range e.References {
+			sb.WriteString("    - " + ref + "\n")
+		}
+	}
+	return sb.String()
+}
+
+// PrintError writes the error screen for err to stderr and records it in the error log
+func PrintError(context string, err error) {
+	printErrorTo(os.Stderr, context, err)
+	// Kept for "gitcomm debug-bundle"
+	diagnostics.RecordError(context, err)
+}
+
+// printErrorTo implements PrintError with an injectable writer for testing
+func printErrorTo(w io.Writer, context string, err error) {
+	fmt.Fprint(w, NewErrorScreen(context, err).Render())
+}
@@ -0,0 +1,95 @@
+package ui
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/golgoth31/gitcomm/internal/repository"
+	"github.com/golgoth31/gitcomm/internal/utils"
+)
+
+func TestNewErrorScreen(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		wantTitle   string
+		wantRemedy  string
+		wantNoSteps bool
+	}{
+		{
+			name:       "not a git repository",
+			err:        fmt.Errorf("failed to open: %w", utils.ErrNotGitRepository),
+			wantTitle:  "Not a git repository",
+			wantRemedy: "git init",
+		},
+		{
+			name:       "repository disabled",
+			err:        fmt.Errorf("%w: .gitcomm-disable found", utils.ErrRepositoryDisabled),
+			wantTitle:  "gitcomm is disabled in this repository",
+			wantRemedy: "git commit",
+		},
+		{
+			name:       "missing API key",
+			err:        fmt.Errorf("%w: OpenAI API key not configured", utils.ErrAIProviderUnavailable),
+			wantTitle:  "Missing or invalid AI provider API key",
+			wantRemedy: "--skip-ai",
+		},
+		{
+			name:       "invalid model",
+			err:        fmt.Errorf("%w: model gpt-99 does not exist", utils.ErrAIProviderUnavailable),
+			wantTitle:  "Invalid AI model",
+			wantRemedy: "'model' value",
+		},
+		{
+			name:       "detached HEAD",
+			err:        &repository.ErrGitCommandFailed{Command: "commit", ExitCode: 1, Stderr: "fatal: You are not currently on a branch."},
+			wantTitle:  "HEAD is detached",
+			wantRemedy: "git switch",
+		},
+		{
+			name:       "signing key missing",
+			err:        fmt.Errorf("%w: error: Couldn't load public key", repository.ErrGitSigningFailed),
+			wantTitle:  "Commit signing failed",
+			wantRemedy: "--no-sign",
+		},
+		{
+			name:        "unknown error keeps context only",
+			err:         errors.New("something odd"),
+			wantTitle:   "commit failed",
+			wantNoSteps: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			screen := NewErrorScreen("commit failed", tt.err)
+			if !strings.Contains(screen.Title, tt.wantTitle) {
+				t.Errorf("Title = %q, want to contain %q", screen.Title, tt.wantTitle)
+			}
+			if tt.wantNoSteps {
+				if len(screen.Remediation) != 0 {
+					t.Errorf("Remediation = %v, want none", screen.Remediation)
+				}
+				return
+			}
+			if !strings.Contains(strings.Join(screen.Remediation, "\n"), tt.wantRemedy) {
+				t.Errorf("Remediation = %v, want to contain %q", screen.Remediation, tt.wantRemedy)
+			}
+		})
+	}
+}
+
+func TestPrintErrorTo(t *testing.T) {
+	var buf bytes.Buffer
+	printErrorTo(&buf, "AI generation failed", fmt.Errorf("%w: OpenAI API key not configured", utils.ErrAIProviderUnavailable))
+
+	out := buf.String()
+	for _, want := range []string{"Error: AI generation failed", "API key not configured", "How to fix:", "1. ", "See:"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
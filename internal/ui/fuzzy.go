@@ -0,0 +1,69 @@
+package ui
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// fuzzyScore scores how well query matches candidate as a case-insensitive subsequence.
+// Consecutive matches and matches at the start of the candidate or of a word
+// (after '-', '_', '/' or '.') score higher. ok is false when query is not a subsequence.
+func fuzzyScore(query, candidate string) (score int, ok bool) {
+	query = strings.ToLower(query)
+	candidate = strings.ToLower(candidate)
+	if query == "" {
+		return 0, true
+	}
+
+	qi := 0
+	prevMatch := -2
+	prev := rune(0)
+	for ci, c := range candidate {
+		if qi >= len(query) {
+			break
+		}
+		q, size := utf8.DecodeRuneInString(query[qi:])
+		if c == q {
+			score++
+			if ci == prevMatch+utf8.RuneLen(prev) {
+				score += 3 // consecutive
+			}
+			if ci == 0 || strings.ContainsRune("-_/.", prev) {
+				score += 2 // start of candidate or word
+			}
+			prevMatch = ci
+			qi += size
+		}
+		prev = c
+	}
+	if qi < len(query) {
+		return 0, false
+	}
+	// Prefer shorter candidates for equal matches
+	return score*100 - len(candidate), true
+}
+
+// fuzzyFilter returns the candidates matching query, best match first.
+// Candidates with equal scores keep their original order.
+func fuzzyFilter(query string, candidates []string) []string {
+	type match struct {
+		value string
+		score int
+	}
+	var matches []match
+	for _, candidate := range candidates {
+		if score, ok := fuzzyScore(query, candidate); ok {
+			matches = append(matches, match{candidate, score})
+		}
+	}
+	if query != "" {
+		sort.SliceStable(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+	}
+
+	result := make([]string, len(matches))
+	for i, m := range matches {
+		result[i] = m.value
+	}
+	return result
+}
@@ -0,0 +1,66 @@
+package ui
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFuzzyScore(t *testing.T) {
+	tests := []struct {
+		query     string
+		candidate string
+		wantOK    bool
+	}{
+		{"", "anything", true},
+		{"cfg", "config", true},
+		{"CFG", "config", true},
+		{"rpo", "repository", true},
+		{"xyz", "config", false},
+		{"configs", "config", false},
+	}
+
+	for _, tt := range tests {
+		if _, ok := fuzzyScore(tt.query, tt.candidate); ok != tt.wantOK {
+			t.Errorf("fuzzyScore(%q, %q) ok = %v, want %v", tt.query, tt.candidate, ok, tt.wantOK)
+		}
+	}
+}
+
+func TestFuzzyFilter(t *testing.T) {
+	candidates := []string{"service", "ui", "cli", "ai-postprocess", "api"}
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{
+			name:  "empty query keeps order",
+			query: "",
+			want:  candidates,
+		},
+		{
+			name:  "consecutive match ranks first",
+			query: "ap",
+			want:  []string{"api", "ai-postprocess"},
+		},
+		{
+			name:  "word start match",
+			query: "pp",
+			want:  []string{"ai-postprocess"},
+		},
+		{
+			name:  "no match",
+			query: "zzz",
+			want:  []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fuzzyFilter(tt.query, candidates); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("fuzzyFilter(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/golgoth31/gitcomm/internal/store"
+	"github.com/golgoth31/gitcomm/internal/utils"
+	"github.com/golgoth31/gitcomm/pkg/datefmt"
+)
+
+// PrintHistory prints the commits generated across repositories since since, newest
+// first, and the provider usage of the period, through the pager when it does not fit in
+// the terminal
+func PrintHistory(commits []store.Commit, totals []store.UsageTotal, since time.Time, dates *datefmt.Formatter) {
+	if err := PrintPaged(formatHistory(commits, totals, since, dates, TerminalWidth())); err != nil {
+		utils.Logger.Debug().Err(err).Msg("Failed to display history")
+	}
+}
+
+// formatHistory implements PrintHistory for a given terminal width
+func formatHistory(commits []store.Commit, totals []store.UsageTotal, since time.Time, dates *datefmt.Formatter, width int) string {
+	heading := lipgloss.NewStyle().Bold(true)
+	var sb strings.Builder
+	sb.WriteString(heading.Render(TruncateEnd(fmt.Sprintf("Commits generated since %s", dates.Format(since)), width)) + "\n")
+	if len(commits) == 0 {
+		sb.WriteString("  none\n")
+	}
+
+	rows := make([][3]string, len(commits))
+	dateWidth, repositoryWidth := 0, 0
+	for i, commit := range commits {
+		rows[i] = [3]string{dates.Format(commit.CreatedAt), TruncatePath(commit.Repository, width/4), commitProvider(commit.Provider, commit.Model)}
+		dateWidth = max(dateWidth, lipgloss.Width(rows[i][0]))
+		repositoryWidth = max(repositoryWidth, lipgloss.Width(rows[i][1]))
+	}
+	for i, commit := range commits {
+		hash := commit.Hash[:min(7, len(commit.Hash))]
+		line := fmt.Sprintf("  %s  %s  %s  %s (%s)", padRight(rows[i][0], dateWidth), padRight(rows[i][1], repositoryWidth), hash, commit.Subject, rows[i][2])
+		sb.WriteString(TruncateEnd(line, width) + "\n")
+	}
+
+	sb.WriteString("\n" + heading.Render("Provider usage (estimated tokens)") + "\n")
+	if len(totals) == 0 {
+		sb.WriteString("  none\n")
+	}
+	providerWidth := 0
+	for _, total := range totals {
+		providerWidth = max(providerWidth, lipgloss.Width(commitProvider(total.Provider, total.Model)))
+	}
+	for _, total := range totals {
+		line := fmt.Sprintf("  %s  %d requests, %d prompt tokens, %d answer tokens",
+			padRight(commitProvider(total.Provider, total.Model), providerWidth), total.Requests, total.PromptTokens, total.AnswerTokens)
+		sb.WriteString(TruncateEnd(line, width) + "\n")
+	}
+	return sb.String()
+}
+
+// commitProvider returns "provider/model", or the provider alone for its default model
+func commitProvider(provider, model string) string {
+	if model == "" {
+		return provider
+	}
+	return provider + "/" + model
+}
@@ -0,0 +1,45 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golgoth31/gitcomm/internal/store"
+	"github.com/golgoth31/gitcomm/pkg/datefmt"
+)
+
+func TestFormatHistory(t *testing.T) {
+	dates, err := datefmt.NewFormatter(time.DateOnly, "UTC", "")
+	if err != nil {
+		t.Fatalf("NewFormatter() error = %v", err)
+	}
+	since := time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC)
+	commits := []store.Commit{
+		{Repository: "/src/gitcomm", Hash: "a1b2c3d4e5f6", Subject: "feat(api): add health endpoint", Provider: "openai", Model: "gpt-4o", CreatedAt: since.Add(50 * time.Hour)},
+		{Repository: "/src/web", Hash: "0123456789ab", Subject: "fix: handle nil config", Provider: "ollama", CreatedAt: since.Add(time.Hour)},
+	}
+	totals := []store.UsageTotal{
+		{Provider: "ollama", Requests: 1, PromptTokens: 800, AnswerTokens: 12},
+		{Provider: "openai", Model: "gpt-4o", Requests: 3, PromptTokens: 4200, AnswerTokens: 90},
+	}
+
+	got := formatHistory(commits, totals, since, dates, 120)
+	for _, want := range []string{
+		"Commits generated since 2026-10-12",
+		"  2026-10-14  /src/gitcomm  a1b2c3d  feat(api): add health endpoint (openai/gpt-4o)\n",
+		"  2026-10-12  /src/web      0123456  fix: handle nil config (ollama)\n",
+		"Provider usage (estimated tokens)",
+		"  ollama         1 requests, 800 prompt tokens, 12 answer tokens\n",
+		"  openai/gpt-4o  3 requests, 4200 prompt tokens, 90 answer tokens\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("history missing %q:\n%s", want, got)
+		}
+	}
+
+	empty := formatHistory(nil, nil, since, dates, 120)
+	if strings.Count(empty, "  none\n") != 2 {
+		t.Errorf("empty history should say none twice:\n%s", empty)
+	}
+}
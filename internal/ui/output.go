@@ -0,0 +1,27 @@
+package ui
+
+import "os"
+
+// separated sends the prompts, progress and decorations to stderr (see SetSeparateStreams)
+var separated bool
+
+// SetSeparateStreams sends the prompts, progress and decorations to stderr when enabled,
+// leaving stdout to the results of the command (output.separate_streams, or commands
+// whose stdout is read by another program)
+func SetSeparateStreams(enabled bool) {
+	separated = enabled
+}
+
+// SeparateStreams reports whether the prompts, progress and decorations go to stderr
+func SeparateStreams() bool {
+	return separated
+}
+
+// Out returns where the prompts, progress and decorations are printed: stdout, or stderr
+// when the streams are separated
+func Out() *os.File {
+	if separated {
+		return os.Stderr
+	}
+	return os.Stdout
+}
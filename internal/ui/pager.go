@@ -0,0 +1,86 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/charmbracelet/x/term"
+)
+
+// defaultPager is used when neither GIT_PAGER nor PAGER is set (same default as git)
+const defaultPager = "less -R"
+
+// ResolvePager returns the pager command to use for long previews.
+// Lookup order follows git: GIT_PAGER, then PAGER, then "less -R".
+// An empty string means paging is disabled (e.g. GIT_PAGER=cat or GIT_PAGER="").
+func ResolvePager() string {
+	pager, ok := os.LookupEnv("GIT_PAGER")
+	if !ok {
+		pager, ok = os.LookupEnv("PAGER")
+	}
+	if !ok {
+		pager = defaultPager
+	}
+
+	pager = strings.TrimSpace(pager)
+	if pager == "cat" {
+		return ""
+	}
+	return pager
+}
+
+// PrintPaged prints content to Out, piping it through the configured pager
+// when Out is a terminal and the content does not fit in the terminal height.
+// Short content, non-terminal output, non-interactive mode and pager failures all fall
+// back to a plain print.
+func PrintPaged(content string) error {
+	out := Out()
+	isTTY := term.IsTerminal(out.Fd()) && !nonInteractive
+	height := 0
+	if isTTY {
+		if _, h, err := term.GetSize(out.Fd()); err == nil {
+			height = h
+		}
+	}
+	return pageTo(out, content, isTTY, height, ResolvePager())
+}
+
+// pageTo implements PrintPaged with injectable terminal state for testing
+func pageTo(w io.Writer, content string, isTTY bool, height int, pager string) error {
+	if !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+
+	if !needsPaging(content, isTTY, height, pager) {
+		_, err := io.WriteString(w, content)
+		return err
+	}
+
+	cmd := exec.Command("sh", "-c", pager)
+	cmd.Stdin = strings.NewReader(content)
+	cmd.Stdout = w
+	cmd.Stderr = os.Stderr
+	// Mirror git: let less quit on short input, keep colors, and not clear the screen
+	if _, ok := os.LookupEnv("LESS"); !ok {
+		cmd.Env = append(os.Environ(), "LESS=FRX")
+	}
+
+	if err := cmd.Run(); err != nil {
+		// Pager missing or crashed - never lose the preview
+		if _, writeErr := io.WriteString(w, content); writeErr != nil {
+			return fmt.Errorf("pager %q failed (%v) and fallback print failed: %w", pager, err, writeErr)
+		}
+	}
+	return nil
+}
+
+// needsPaging returns true if the content is taller than the terminal and a pager is configured
+func needsPaging(content string, isTTY bool, height int, pager string) bool {
+	if !isTTY || pager == "" || height <= 0 {
+		return false
+	}
+	return strings.Count(content, "\n") >= height
+}
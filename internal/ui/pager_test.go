@@ -0,0 +1,131 @@
+package ui
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestResolvePager(t *testing.T) {
+	tests := []struct {
+		name     string
+		gitPager *string
+		pager    *string
+		want     string
+	}{
+		{
+			name: "defaults to less",
+			want: "less -R",
+		},
+		{
+			name:  "PAGER is used when GIT_PAGER is unset",
+			pager: strPtr("more"),
+			want:  "more",
+		},
+		{
+			name:     "GIT_PAGER takes precedence over PAGER",
+			gitPager: strPtr("delta"),
+			pager:    strPtr("more"),
+			want:     "delta",
+		},
+		{
+			name:     "cat disables paging",
+			gitPager: strPtr("cat"),
+			want:     "",
+		},
+		{
+			name:     "empty GIT_PAGER disables paging",
+			gitPager: strPtr(""),
+			want:     "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			unsetEnv(t, "GIT_PAGER")
+			unsetEnv(t, "PAGER")
+			if tt.gitPager != nil {
+				t.Setenv("GIT_PAGER", *tt.gitPager)
+			}
+			if tt.pager != nil {
+				t.Setenv("PAGER", *tt.pager)
+			}
+
+			if got := ResolvePager(); got != tt.want {
+				t.Errorf("ResolvePager() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPageTo(t *testing.T) {
+	long := strings.Repeat("line\n", 50)
+
+	tests := []struct {
+		name    string
+		content string
+		isTTY   bool
+		height  int
+		pager   string
+		want    string
+	}{
+		{
+			name:    "short content is printed directly",
+			content: "feat: add pager",
+			isTTY:   true,
+			height:  24,
+			pager:   "sed s/^/paged:/",
+			want:    "feat: add pager\n",
+		},
+		{
+			name:    "non-terminal output is never paged",
+			content: long,
+			isTTY:   false,
+			height:  24,
+			pager:   "sed s/^/paged:/",
+			want:    long,
+		},
+		{
+			name:    "long content goes through the pager",
+			content: "a\nb\nc\n",
+			isTTY:   true,
+			height:  2,
+			pager:   "sed s/^/paged:/",
+			want:    "paged:a\npaged:b\npaged:c\n",
+		},
+		{
+			name:    "failing pager falls back to plain output",
+			content: "a\nb\nc\n",
+			isTTY:   true,
+			height:  2,
+			pager:   "exit 3",
+			want:    "a\nb\nc\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := pageTo(&buf, tt.content, tt.isTTY, tt.height, tt.pager); err != nil {
+				t.Fatalf("pageTo() error = %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("pageTo() output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+// unsetEnv unsets an environment variable for the duration of the test
+func unsetEnv(t *testing.T, key string) {
+	t.Helper()
+	t.Setenv(key, "") // registers restoration of the original value
+	if err := os.Unsetenv(key); err != nil {
+		t.Fatalf("failed to unset %s: %v", key, err)
+	}
+}
@@ -0,0 +1,45 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/x/term"
+)
+
+// clearLine moves the cursor to the start of the line and erases it
+const clearLine = "\r\033[K"
+
+// StreamProgress shows an answer while it is streamed, as a single line on stderr
+// rewritten with the last line received. report is called with the answer received so
+// far; done erases the line. Nothing is shown when stderr is not a terminal or prompts
+// are disabled.
+func StreamProgress(label string) (report func(received string), done func()) {
+	if nonInteractive || !term.IsTerminal(os.Stderr.Fd()) {
+		return func(string) {}, func() {}
+	}
+	width := 0
+	if w, _, err := term.GetSize(os.Stderr.Fd()); err == nil {
+		width = w
+	}
+	return streamProgressTo(os.Stderr, label, terminalWidth(true, width, os.Getenv("COLUMNS")))
+}
+
+// streamProgressTo implements StreamProgress on w for a terminal of width columns
+func streamProgressTo(w io.Writer, label string, width int) (report func(received string), done func()) {
+	shown := false
+	report = func(received string) {
+		received = strings.TrimRight(received, "\n")
+		last := received[strings.LastIndex(received, "\n")+1:]
+		fmt.Fprint(w, clearLine+TruncateEnd(label+" "+last, width-1))
+		shown = true
+	}
+	done = func() {
+		if shown {
+			fmt.Fprint(w, clearLine)
+		}
+	}
+	return report, done
+}
@@ -0,0 +1,29 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStreamProgress(t *testing.T) {
+	var out strings.Builder
+	report, done := streamProgressTo(&out, "Generating:", 30)
+
+	report("feat(api): add")
+	report("feat(api): add health endpoint\n\nServe the status of the dependencies")
+	done()
+
+	want := clearLine + "Generating: feat(api): add" +
+		clearLine + "Generating: Serve the status…" +
+		clearLine
+	if out.String() != want {
+		t.Errorf("output = %q, want %q", out.String(), want)
+	}
+
+	var silent strings.Builder
+	_, done = streamProgressTo(&silent, "Generating:", 30)
+	done()
+	if silent.String() != "" {
+		t.Errorf("done without progress wrote %q, want nothing", silent.String())
+	}
+}
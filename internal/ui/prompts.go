@@ -3,9 +3,11 @@ package ui
 import (
 	"bufio"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/charmbracelet/huh"
+	"github.com/golgoth31/gitcomm/internal/model"
 )
 
 // AIMessageAcceptance represents the user's choice when presented with an AI-generated commit message
@@ -18,6 +20,8 @@ const (
 	AcceptAndEdit
 	// Reject indicates the user wants to reject the AI message and start over
 	Reject
+	// AcceptAndEditInline indicates the user wants to edit the whole AI message in a single form
+	AcceptAndEditInline
 )
 
 // String returns a human-readable string representation of the acceptance value
@@ -29,6 +33,8 @@ func (a AIMessageAcceptance) String() string {
 		return "accept and edit"
 	case Reject:
 		return "reject"
+	case AcceptAndEditInline:
+		return "accept and edit inline"
 	default:
 		return "unknown"
 	}
@@ -42,6 +48,12 @@ type PrefilledCommitMessage struct {
 	Subject string // Pre-filled subject from AI message
 	Body    string // Pre-filled body from AI message (may be empty)
 	Footer  string // Pre-filled footer from AI message (may be empty)
+	Ticket  string // Pre-filled ticket of a custom header layout (may be empty)
+	// Breaking is true when the header marks a breaking change with "!"
+	Breaking bool
+
+	// TypeConfirmed is true when the type was confirmed by local inference, so type selection is skipped
+	TypeConfirmed bool
 }
 
 // PromptScopeWithDefault prompts the user for commit scope with a default value
@@ -56,7 +68,7 @@ func PromptScopeWithDefault(reader *bufio.Reader, defaultValue string) (string,
 		),
 	)
 
-	if err := form.Run(); err != nil {
+	if err := runForm(form); err != nil {
 		return "", fmt.Errorf("scope input cancelled: %w", err)
 	}
 
@@ -71,6 +83,70 @@ func PromptScopeWithDefault(reader *bufio.Reader, defaultValue string) (string,
 	return scope, nil
 }
 
+// scopeOther and scopeNone are the special options of the scope select
+const (
+	scopeOther = "\x00other"
+	scopeNone  = "\x00none"
+)
+
+// PromptScopeWithSuggestions prompts the user for commit scope with a fuzzy-searchable
+// select over candidates (best matches of the search text first). "other…" switches
+// to free-text input. Falls back to PromptScopeWithDefault when there are no candidates.
+func PromptScopeWithSuggestions(reader *bufio.Reader, defaultValue string, candidates []string) (string, error) {
+	if len(candidates) == 0 {
+		return PromptScopeWithDefault(reader, defaultValue)
+	}
+
+	// The default (e.g. AI scope) is always offered first
+	if defaultValue != "" && !slices.Contains(candidates, defaultValue) {
+		candidates = append([]string{defaultValue}, candidates...)
+	}
+
+	var query string
+	scope := defaultValue
+	if scope == "" {
+		scope = scopeNone
+	}
+
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title("Scope").
+				Description("Type to search, enter to pick from the list").
+				Value(&query),
+			huh.NewSelect[string]().
+				OptionsFunc(func() []huh.Option[string] {
+					var options []huh.Option[string]
+					for _, candidate := range fuzzyFilter(query, candidates) {
+						options = append(options, huh.NewOption(candidate, candidate))
+					}
+					options = append(options,
+						huh.NewOption("other…", scopeOther),
+						huh.NewOption("(no scope)", scopeNone),
+					)
+					return options
+				}, &query).
+				Value(&scope),
+		),
+	)
+
+	if err := runForm(form); err != nil {
+		return "", fmt.Errorf("scope input cancelled: %w", err)
+	}
+
+	switch scope {
+	case scopeNone:
+		scope = ""
+	case scopeOther:
+		// Free text, pre-filled with the search text
+		return PromptScopeWithDefault(reader, strings.TrimSpace(query))
+	}
+
+	printPostValidationSummary("Scope", scope)
+
+	return scope, nil
+}
+
 // PromptSubjectWithDefault prompts the user for commit subject with a default value
 func PromptSubjectWithDefault(reader *bufio.Reader, defaultValue string) (string, error) {
 	subject := defaultValue
@@ -92,7 +168,7 @@ func PromptSubjectWithDefault(reader *bufio.Reader, defaultValue string) (string
 		),
 	)
 
-	if err := form.Run(); err != nil {
+	if err := runForm(form); err != nil {
 		return "", fmt.Errorf("subject input cancelled: %w", err)
 	}
 
@@ -108,6 +184,47 @@ func PromptSubjectWithDefault(reader *bufio.Reader, defaultValue string) (string
 	return subject, nil
 }
 
+// PromptTicketWithDefault prompts the user for the ticket written in the header by a
+// custom header layout, with a default value pre-populated
+func PromptTicketWithDefault(reader *bufio.Reader, defaultValue string) (string, error) {
+	ticket := defaultValue
+
+	validator := func(value string) error {
+		trimmed := strings.TrimSpace(value)
+		if trimmed == "" && defaultValue == "" {
+			return fmt.Errorf("ticket cannot be empty")
+		}
+		if strings.ContainsAny(trimmed, " \t") {
+			return fmt.Errorf("ticket cannot contain spaces")
+		}
+		return nil
+	}
+
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title("Ticket").
+				Value(&ticket).
+				Validate(validator),
+		),
+	)
+
+	if err := runForm(form); err != nil {
+		return "", fmt.Errorf("ticket input cancelled: %w", err)
+	}
+
+	ticket = strings.TrimSpace(ticket)
+	// If empty and default exists, return default
+	if ticket == "" && defaultValue != "" {
+		ticket = defaultValue
+	}
+
+	// Print post-validation summary line
+	printPostValidationSummary("Ticket", ticket)
+
+	return ticket, nil
+}
+
 // PromptBodyWithDefault prompts the user for commit body with a default value pre-populated
 func PromptBodyWithDefault(reader *bufio.Reader, defaultValue string) (string, error) {
 	body := defaultValue
@@ -120,13 +237,13 @@ func PromptBodyWithDefault(reader *bufio.Reader, defaultValue string) (string, e
 		),
 	)
 
-	if err := form.Run(); err != nil {
+	if err := runForm(form); err != nil {
 		return "", fmt.Errorf("body input cancelled: %w", err)
 	}
 
 	// Warn if body is too long (optional validation)
 	if len(body) > 320 {
-		fmt.Printf("Warning: Body is %d characters (recommended: ≤320). Continue? (y/n): ", len(body))
+		fmt.Fprintf(Out(), "Warning: Body is %d characters (recommended: ≤320). Continue? (y/n): ", len(body))
 		confirm, _ := reader.ReadString('\n')
 		if strings.TrimSpace(strings.ToLower(confirm)) != "y" {
 			return "", fmt.Errorf("body too long, user cancelled")
@@ -151,7 +268,7 @@ func PromptFooterWithDefault(reader *bufio.Reader, defaultValue string) (string,
 		),
 	)
 
-	if err := form.Run(); err != nil {
+	if err := runForm(form); err != nil {
 		return "", fmt.Errorf("footer input cancelled: %w", err)
 	}
 
@@ -165,6 +282,11 @@ func PromptFooterWithDefault(reader *bufio.Reader, defaultValue string) (string,
 func PromptEmptyCommit(reader *bufio.Reader) (bool, error) {
 	var confirm bool
 
+	if nonInteractive {
+		printPostValidationSummary("No changes detected. Create an empty commit?", confirm)
+		return confirm, nil
+	}
+
 	form := huh.NewForm(
 		huh.NewGroup(
 			huh.NewConfirm().
@@ -173,7 +295,7 @@ func PromptEmptyCommit(reader *bufio.Reader) (bool, error) {
 		),
 	)
 
-	if err := form.Run(); err != nil {
+	if err := runForm(form); err != nil {
 		return false, fmt.Errorf("empty commit prompt cancelled: %w", err)
 	}
 
@@ -185,6 +307,11 @@ func PromptEmptyCommit(reader *bufio.Reader) (bool, error) {
 
 // PromptConfirm prompts the user to confirm an action
 func PromptConfirm(reader *bufio.Reader, message string, defaultValue bool) (bool, error) {
+	if nonInteractive {
+		printPostValidationSummary(message, defaultValue)
+		return defaultValue, nil
+	}
+
 	form := huh.NewForm(
 		huh.NewGroup(
 			huh.NewConfirm().
@@ -193,7 +320,7 @@ func PromptConfirm(reader *bufio.Reader, message string, defaultValue bool) (boo
 		),
 	)
 
-	if err := form.Run(); err != nil {
+	if err := runForm(form); err != nil {
 		return false, fmt.Errorf("confirm prompt cancelled: %w", err)
 	}
 
@@ -235,7 +362,7 @@ func PromptCommitTypeWithPreselection(reader *bufio.Reader, preselectedType stri
 		),
 	)
 
-	if err := form.Run(); err != nil {
+	if err := runForm(form); err != nil {
 		return "", fmt.Errorf("commit type selection cancelled: %w", err)
 	}
 
@@ -245,29 +372,51 @@ func PromptCommitTypeWithPreselection(reader *bufio.Reader, preselectedType stri
 	return commitType, nil
 }
 
-// PromptAIUsage prompts the user to choose whether to use AI
-func PromptAIUsage(reader *bufio.Reader, tokenCount int) (bool, error) {
+// PrintConfirmedType prints the single summary line shown instead of the type selection
+// when the AI type matches the locally inferred type
+func PrintConfirmedType(commitType string) {
+	printPostValidationSummary("Choose a type", commitType+" (matches changed files)")
+}
+
+// PromptAIUsage prompts the user to choose whether to use AI, showing the estimated tokens
+// of the prompt and the files sent without their diff to fit the context budget
+func PromptAIUsage(reader *bufio.Reader, plan model.ContextPlan) (bool, error) {
 	var useAI bool = true // Default to "yes" (true) for AI usage
 
-	estimatedTokens := fmt.Sprintf("Estimated tokens: %d", tokenCount)
 	message := "Use AI to generate commit message?"
+	aiOutputMessage := fmt.Sprintf("Use AI to generate commit message for %d tokens?", plan.Tokens)
+	report := plan.Report()
 
+	if nonInteractive {
+		printPostValidationSummary(aiOutputMessage, true)
+		if report != "" {
+			fmt.Fprintln(Out(), report)
+		}
+		return true, nil
+	}
+
+	note := huh.NewNote().Title(plan.Estimate())
+	if report != "" {
+		note = note.Description(report)
+	}
 	form := huh.NewForm(
 		huh.NewGroup(
-			huh.NewNote().Title(estimatedTokens),
+			note,
 			huh.NewConfirm().
 				Title(message).
 				Value(&useAI),
 		),
 	)
 
-	if err := form.Run(); err != nil {
+	if err := runForm(form); err != nil {
 		return false, fmt.Errorf("AI usage prompt cancelled: %w", err)
 	}
 
-	aiOutputMessage := fmt.Sprintf("Use AI to generate commit message for %d tokens?", tokenCount)
-	// Print post-validation summary line
+	// Print post-validation summary line, and the files left without diff
 	printPostValidationSummary(aiOutputMessage, useAI)
+	if useAI && report != "" {
+		fmt.Fprintln(Out(), report)
+	}
 
 	return useAI, nil
 }
@@ -287,11 +436,11 @@ func PromptAIUsage(reader *bufio.Reader, tokenCount int) (bool, error) {
 // 	return response == "" || response == "y" || response == "yes", nil
 // }
 
-// PromptAIMessageAcceptanceOptions prompts the user to choose from three options when presented with an AI-generated commit message
+// PromptAIMessageAcceptanceOptions prompts the user to choose from four options when presented with an AI-generated commit message
 func PromptAIMessageAcceptanceOptions(reader *bufio.Reader, message string) (AIMessageAcceptance, error) {
-	fmt.Println("\n--- AI Generated Message ---")
-	fmt.Println(message)
-	fmt.Println("---")
+	if err := PrintPaged("\n--- AI Generated Message ---\n" + message + "\n---"); err != nil {
+		return 0, fmt.Errorf("failed to display AI message: %w", err)
+	}
 
 	var choice string
 
@@ -302,13 +451,14 @@ func PromptAIMessageAcceptanceOptions(reader *bufio.Reader, message string) (AIM
 				Options(
 					huh.NewOption("Accept and commit directly", "accept-commit"),
 					huh.NewOption("Accept and edit", "accept-edit"),
+					huh.NewOption("Accept and edit inline", "accept-edit-inline"),
 					huh.NewOption("Reject", "reject"),
 				).
 				Value(&choice),
 		),
 	)
 
-	if err := form.Run(); err != nil {
+	if err := runForm(form); err != nil {
 		return 0, fmt.Errorf("AI message acceptance prompt cancelled: %w", err)
 	}
 
@@ -318,6 +468,8 @@ func PromptAIMessageAcceptanceOptions(reader *bufio.Reader, message string) (AIM
 		acceptance = AcceptAndCommit
 	case "accept-edit":
 		acceptance = AcceptAndEdit
+	case "accept-edit-inline":
+		acceptance = AcceptAndEditInline
 	case "reject":
 		acceptance = Reject
 	default:
@@ -331,6 +483,8 @@ func PromptAIMessageAcceptanceOptions(reader *bufio.Reader, message string) (AIM
 		choiceStr = "Accept and commit directly"
 	case AcceptAndEdit:
 		choiceStr = "Accept and edit"
+	case AcceptAndEditInline:
+		choiceStr = "Accept and edit inline"
 	case Reject:
 		choiceStr = "Reject"
 	}
@@ -339,6 +493,37 @@ func PromptAIMessageAcceptanceOptions(reader *bufio.Reader, message string) (AIM
 	return acceptance, nil
 }
 
+// maxInlineEditLines caps the height of the inline message editor
+const maxInlineEditLines = 15
+
+// PromptMessageInline lets the user edit a whole formatted commit message in a single
+// multi-line field. validate runs on submit: the form stays open until it returns nil.
+func PromptMessageInline(reader *bufio.Reader, message string, validate func(string) error) (string, error) {
+	edited := message
+
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewText().
+				Title("Commit message").
+				Lines(min(strings.Count(message, "\n")+2, maxInlineEditLines)).
+				CharLimit(0).
+				Value(&edited).
+				Validate(validate),
+		),
+	)
+
+	if err := runForm(form); err != nil {
+		return "", fmt.Errorf("message edit cancelled: %w", err)
+	}
+
+	edited = strings.TrimSpace(edited)
+
+	// Print post-validation summary line (truncated for multiline)
+	printPostValidationSummary("Commit message", edited)
+
+	return edited, nil
+}
+
 // PromptAIMessageEdit prompts the user to edit or use AI message with warning
 func PromptAIMessageEdit(reader *bufio.Reader, errors []string) (bool, error) {
 	var edit bool = true // Default to "yes" (edit) when there are validation errors
@@ -358,7 +543,7 @@ func PromptAIMessageEdit(reader *bufio.Reader, errors []string) (bool, error) {
 		),
 	)
 
-	if err := form.Run(); err != nil {
+	if err := runForm(form); err != nil {
 		return false, fmt.Errorf("AI message edit prompt cancelled: %w", err)
 	}
 
@@ -398,7 +583,7 @@ func PromptCommitFailureChoice(reader *bufio.Reader) (CommitFailureChoice, error
 		),
 	)
 
-	if err := form.Run(); err != nil {
+	if err := runForm(form); err != nil {
 		return 0, fmt.Errorf("commit failure choice prompt cancelled: %w", err)
 	}
 
@@ -441,7 +626,7 @@ func PromptRejectChoice(reader *bufio.Reader) (bool, error) {
 		),
 	)
 
-	if err := form.Run(); err != nil {
+	if err := runForm(form); err != nil {
 		return false, fmt.Errorf("reject choice prompt cancelled: %w", err)
 	}
 
@@ -147,6 +147,11 @@ func TestAIMessageAcceptance_String(t *testing.T) {
 			value:    Reject,
 			expected: "reject",
 		},
+		{
+			name:     "AcceptAndEditInline",
+			value:    AcceptAndEditInline,
+			expected: "accept and edit inline",
+		},
 		{
 			name:     "Unknown value",
 			value:    AIMessageAcceptance(99),
@@ -175,6 +180,9 @@ func TestAIMessageAcceptance_Constants(t *testing.T) {
 	if Reject != 2 {
 		t.Errorf("Reject should be 2, got %d", Reject)
 	}
+	if AcceptAndEditInline != 3 {
+		t.Errorf("AcceptAndEditInline should be 3, got %d", AcceptAndEditInline)
+	}
 }
 
 // TestPrefilledCommitMessage_Fields tests that PrefilledCommitMessage has all required fields
@@ -247,6 +255,11 @@ func TestPromptAIMessageAcceptanceOptions_Format(t *testing.T) {
 			acceptance: AcceptAndEdit,
 			expected:   "✓ Options: Accept and edit",
 		},
+		{
+			name:       "AcceptAndEditInline",
+			acceptance: AcceptAndEditInline,
+			expected:   "✓ Options: Accept and edit inline",
+		},
 		{
 			name:       "Reject",
 			acceptance: Reject,
@@ -262,6 +275,8 @@ func TestPromptAIMessageAcceptanceOptions_Format(t *testing.T) {
 				choiceStr = "Accept and commit directly"
 			case AcceptAndEdit:
 				choiceStr = "Accept and edit"
+			case AcceptAndEditInline:
+				choiceStr = "Accept and edit inline"
 			case Reject:
 				choiceStr = "Reject"
 			}
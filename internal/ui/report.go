@@ -0,0 +1,133 @@
+package ui
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/utils"
+	"github.com/golgoth31/gitcomm/pkg/datefmt"
+)
+
+// PrintActivityReport prints an activity report as a dashboard: tiles with the totals,
+// then bar charts of the commits by type and by repository, through the pager when it does
+// not fit in the terminal
+func PrintActivityReport(report *model.ActivityReport, dates *datefmt.Formatter) {
+	if err := PrintPaged(formatActivityReport(report, dates, TerminalWidth())); err != nil {
+		utils.Logger.Debug().Err(err).Msg("Failed to display activity report")
+	}
+}
+
+// formatActivityReport implements PrintActivityReport for a given terminal width
+func formatActivityReport(report *model.ActivityReport, dates *datefmt.Formatter, width int) string {
+	heading := lipgloss.NewStyle().Bold(true)
+	var sb strings.Builder
+	sb.WriteString(heading.Render(TruncateEnd(fmt.Sprintf("Your commits from %s to %s", dates.Format(report.Since), dates.Format(report.Until)), width)))
+	sb.WriteString("\n\n")
+
+	score := "-"
+	if report.Commits > 0 {
+		score = fmt.Sprintf("%.1f/%d", report.AverageScore, report.MaxScore)
+	}
+	sb.WriteString(formatTiles([][2]string{
+		{fmt.Sprint(report.Commits), "commits"},
+		{fmt.Sprintf("%d/%d", report.TouchedRepositories(), len(report.Repositories)), "repositories"},
+		{fmt.Sprint(report.ActiveDays), "active days"},
+		{formatDays(report.Streak), "streak"},
+		{score, "message score"},
+	}, width))
+
+	if report.Commits > 0 {
+		types := slices.SortedFunc(maps.Keys(report.ByType), func(a, b string) int {
+			return cmp.Or(cmp.Compare(report.ByType[b], report.ByType[a]), cmp.Compare(a, b))
+		})
+		rows := make([]barRow, len(types))
+		for i, commitType := range types {
+			rows[i] = barRow{label: TruncateEnd(commitType, width/3), count: report.ByType[commitType]}
+		}
+		sb.WriteString("\n\n" + heading.Render("By type") + "\n")
+		sb.WriteString(formatBars(rows, width))
+	}
+
+	rows := make([]barRow, len(report.Repositories))
+	for i, repository := range report.Repositories {
+		rows[i] = barRow{label: TruncatePath(cmp.Or(repository.Path, "."), width/3), count: repository.Commits, err: repository.Err}
+	}
+	sb.WriteString("\n\n" + heading.Render("Repositories") + "\n")
+	sb.WriteString(formatBars(rows, width))
+	return sb.String()
+}
+
+// formatTiles renders value and caption pairs as bordered tiles side by side, wrapped to
+// the terminal width
+func formatTiles(tiles [][2]string, width int) string {
+	style := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Padding(0, 1)
+	value := lipgloss.NewStyle().Bold(true)
+
+	var rows, row []string
+	rowWidth := 0
+	for _, tile := range tiles {
+		rendered := style.Render(value.Render(tile[0]) + "\n" + tile[1])
+		if len(row) > 0 && rowWidth+lipgloss.Width(rendered) > width {
+			rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, row...))
+			row, rowWidth = nil, 0
+		}
+		row = append(row, rendered)
+		rowWidth += lipgloss.Width(rendered)
+	}
+	rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, row...))
+	return lipgloss.JoinVertical(lipgloss.Left, rows...)
+}
+
+// barRow is one line of a bar chart
+type barRow struct {
+	label string
+	count int
+	// err replaces the bar when set
+	err error
+}
+
+// formatBars renders rows as a horizontal bar chart scaled to the terminal width:
+// the label, the count and a bar proportional to it
+func formatBars(rows []barRow, width int) string {
+	labelWidth, countWidth, most := 0, 1, 0
+	for _, row := range rows {
+		labelWidth = max(labelWidth, lipgloss.Width(row.label))
+		countWidth = max(countWidth, len(fmt.Sprint(row.count)))
+		most = max(most, row.count)
+	}
+	barWidth := max(1, width-labelWidth-countWidth-6)
+
+	bar := lipgloss.NewStyle().Foreground(lipgloss.Color("4"))
+	lines := make([]string, len(rows))
+	for i, row := range rows {
+		line := fmt.Sprintf("  %s  ", padRight(row.label, labelWidth))
+		switch {
+		case row.err != nil:
+			line = TruncateEnd(line+"error: "+row.err.Error(), width)
+		case row.count > 0:
+			line += fmt.Sprintf("%*d  ", countWidth, row.count) + bar.Render(strings.Repeat("█", max(1, row.count*barWidth/most)))
+		default:
+			line += fmt.Sprintf("%*d", countWidth, 0)
+		}
+		lines[i] = line
+	}
+	return strings.Join(lines, "\n")
+}
+
+// padRight pads s with spaces to width display columns
+func padRight(s string, width int) string {
+	return s + strings.Repeat(" ", max(0, width-lipgloss.Width(s)))
+}
+
+// formatDays returns a number of days in words: "1 day", "3 days"
+func formatDays(days int) string {
+	if days == 1 {
+		return "1 day"
+	}
+	return fmt.Sprintf("%d days", days)
+}
@@ -0,0 +1,82 @@
+package ui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/pkg/datefmt"
+)
+
+func TestFormatActivityReport(t *testing.T) {
+	dates, err := datefmt.NewFormatter(time.DateOnly, "UTC", "")
+	if err != nil {
+		t.Fatalf("NewFormatter() error = %v", err)
+	}
+	report := &model.ActivityReport{
+		Since:   time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC),
+		Until:   time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC),
+		Commits: 12,
+		ByType:  map[string]int{"fix": 4, "feat": 6, "docs": 1, model.OtherType: 1},
+		Repositories: []model.RepositoryActivity{
+			{Path: "/src/gitcomm", Commits: 12},
+			{Path: "/src/quiet"},
+			{Path: "/src/gone", Err: errors.New("not a git repository")},
+		},
+		AverageScore: 8.4,
+		MaxScore:     10,
+		ActiveDays:   5,
+		Streak:       3,
+	}
+
+	got := formatActivityReport(report, dates, 80)
+	for _, want := range []string{
+		"Your commits from 2025-03-03 to 2025-03-10",
+		"12", "commits", "1/3", "repositories", "5", "active days", "3 days", "streak", "8.4/10", "message score",
+		"/src/quiet     0\n",
+		"/src/gone     error: not a git repository",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("formatActivityReport() = \n%s\nwant it to contain %q", got, want)
+		}
+	}
+
+	// Types are sorted by count, then name; the longest bar is for the most commits
+	feat, fix, docs := strings.Index(got, "  feat "), strings.Index(got, "  fix "), strings.Index(got, "  docs ")
+	if feat < 0 || fix < feat || docs < fix || strings.Index(got, "  other ") < docs {
+		t.Errorf("formatActivityReport() = \n%s\nwant types by count: feat, fix, docs, other", got)
+	}
+	for _, line := range strings.Split(got, "\n") {
+		if width := lipgloss.Width(line); width > 80 {
+			t.Errorf("line %q is %d columns wide, want at most 80", line, width)
+		}
+	}
+}
+
+func TestFormatActivityReport_NoCommits(t *testing.T) {
+	dates, err := datefmt.NewFormatter(time.DateOnly, "UTC", "")
+	if err != nil {
+		t.Fatalf("NewFormatter() error = %v", err)
+	}
+	report := &model.ActivityReport{
+		ByType:       map[string]int{},
+		Repositories: []model.RepositoryActivity{{Path: "/src/gitcomm"}},
+		MaxScore:     10,
+	}
+
+	got := formatActivityReport(report, dates, 40)
+	if strings.Contains(got, "By type") {
+		t.Errorf("formatActivityReport() = \n%s\nwant no type chart without commits", got)
+	}
+	if !strings.Contains(got, "0 days") || !strings.Contains(got, "-") {
+		t.Errorf("formatActivityReport() = \n%s\nwant an empty streak and no score", got)
+	}
+	for _, line := range strings.Split(got, "\n") {
+		if width := lipgloss.Width(line); width > 40 {
+			t.Errorf("line %q is %d columns wide, want at most 40", line, width)
+		}
+	}
+}
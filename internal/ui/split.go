@@ -0,0 +1,35 @@
+package ui
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+)
+
+// maxPlanLines is the height of the split plan editor; longer plans scroll
+const maxPlanLines = 20
+
+// PromptSplitPlan lets the user edit plan, the text of a split plan (ctrl+e opens it in
+// $EDITOR). The edited plan is checked with validate before it is accepted.
+func PromptSplitPlan(reader *bufio.Reader, plan string, validate func(string) error) (string, error) {
+	lines := strings.Count(plan, "\n") + 1
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewText().
+				Title("Commits").
+				Description("edit the plan, ctrl+e opens your editor").
+				Lines(min(lines, maxPlanLines)).
+				CharLimit(0).
+				EditorExtension("txt").
+				Validate(validate).
+				Value(&plan),
+		),
+	)
+
+	if err := runForm(form); err != nil {
+		return "", fmt.Errorf("split plan cancelled: %w", err)
+	}
+	return plan, nil
+}
@@ -0,0 +1,91 @@
+package ui
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+)
+
+// PromptFilesToStage lets the user check the unstaged files to stage. Modified and deleted
+// files are checked by default, as auto-staging would stage them; untracked files are not.
+func PromptFilesToStage(reader *bufio.Reader, files []model.FileChange) ([]string, error) {
+	options := make([]huh.Option[string], len(files))
+	for i, file := range files {
+		options[i] = huh.NewOption(formatStagingOption(file), file.Path).Selected(file.Status != "added")
+	}
+
+	var selected []string
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewMultiSelect[string]().
+				Title("Files to stage").
+				Description("space to toggle, enter to confirm").
+				Options(options...).
+				Value(&selected),
+		),
+	)
+
+	if err := runForm(form); err != nil {
+		return nil, fmt.Errorf("file selection cancelled: %w", err)
+	}
+
+	printPostValidationSummary("Files to stage", fmt.Sprintf("%d of %d", len(selected), len(files)))
+
+	return selected, nil
+}
+
+// PromptHunksToStage shows the hunks of path and lets the user check the ones to stage
+// (all by default). Returns the indexes of the selected hunks.
+func PromptHunksToStage(reader *bufio.Reader, path string, hunks []model.Hunk) ([]int, error) {
+	fmt.Fprintf(Out(), "\n--- %s ---\n", path)
+	options := make([]huh.Option[int], len(hunks))
+	for i, hunk := range hunks {
+		fmt.Fprintf(Out(), "Hunk %d/%d\n%s", i+1, len(hunks), hunk.String())
+		options[i] = huh.NewOption(FormatHunkOption(i, len(hunks), hunk), i).Selected(true)
+	}
+
+	var selected []int
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewMultiSelect[int]().
+				Title("Hunks of " + path + " to stage").
+				Options(options...).
+				Value(&selected),
+		),
+	)
+
+	if err := runForm(form); err != nil {
+		return nil, fmt.Errorf("hunk selection cancelled: %w", err)
+	}
+
+	printPostValidationSummary("Hunks of "+path, fmt.Sprintf("%d of %d", len(selected), len(hunks)))
+
+	return selected, nil
+}
+
+// FormatHunkOption returns the one-line label of hunk i of n: its position, range and
+// changed line counts
+func FormatHunkOption(i, n int, hunk model.Hunk) string {
+	added, removed := hunk.Changes()
+	// "@@ -1,3 +1,4 @@ func name": keep the range, drop the section heading
+	lineRange := hunk.Header
+	if rest, ok := strings.CutPrefix(hunk.Header, "@@"); ok {
+		if end := strings.Index(rest, "@@"); end >= 0 {
+			lineRange = "@@" + rest[:end+2]
+		}
+	}
+	return fmt.Sprintf("Hunk %d/%d %s (+%d -%d)", i+1, n, lineRange, added, removed)
+}
+
+// formatStagingOption returns the label of an unstaged file: its status and path
+func formatStagingOption(file model.FileChange) string {
+	status := file.Status
+	if status == "added" {
+		status = "untracked"
+	}
+	return fmt.Sprintf("%-9s %s", status, TruncatePath(file.Path, TerminalWidth()-16))
+}
@@ -0,0 +1,34 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+)
+
+func TestFormatHunkOption(t *testing.T) {
+	tests := []struct {
+		name string
+		hunk model.Hunk
+		want string
+	}{
+		{
+			name: "section heading dropped",
+			hunk: model.Hunk{Header: "@@ -10,4 +10,5 @@ func main() {", Lines: []string{" a", "-b", "+c", "+d", " e"}},
+			want: "Hunk 2/3 @@ -10,4 +10,5 @@ (+2 -1)",
+		},
+		{
+			name: "no heading",
+			hunk: model.Hunk{Header: "@@ -1 +1 @@", Lines: []string{"-x", "+y"}},
+			want: "Hunk 2/3 @@ -1 +1 @@ (+1 -1)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatHunkOption(1, 3, tt.hunk); got != tt.want {
+				t.Errorf("FormatHunkOption() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
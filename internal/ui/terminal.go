@@ -0,0 +1,131 @@
+package ui
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/x/term"
+	"github.com/golgoth31/gitcomm/internal/utils"
+)
+
+var (
+	// interactive reports whether full-screen prompts can be rendered (replaced in tests)
+	interactive = Interactive
+
+	// plainInput feeds plain prompts from stdin
+	plainInput = newLineReader(os.Stdin)
+
+	// nonInteractive answers prompts with their defaults without reading input (--yes)
+	nonInteractive bool
+)
+
+// SetPlain forces plain line-based prompts, even on a terminal (--plain)
+func SetPlain(plain bool) {
+	if plain {
+		interactive = func() bool { return false }
+		return
+	}
+	interactive = Interactive
+}
+
+// SetNonInteractive disables all prompts (--yes, --non-interactive): confirmations take
+// their default answer and questions without a default fail with utils.ErrNonInteractive
+func SetNonInteractive(enabled bool) {
+	nonInteractive = enabled
+}
+
+// NonInteractive reports whether prompts are disabled
+func NonInteractive() bool {
+	return nonInteractive
+}
+
+// Interactive reports whether stdin and stderr (where prompts are drawn) are terminals.
+// When they are not (piped input, IDE terminals without a PTY), prompts fall back to
+// plain line-based questions instead of full-screen forms.
+func Interactive() bool {
+	return term.IsTerminal(os.Stdin.Fd()) && term.IsTerminal(os.Stderr.Fd())
+}
+
+// Stdin returns stdin for line-based reads, sharing its buffering with plain prompts
+func Stdin() io.Reader {
+	return plainInput
+}
+
+// runForm runs form as a full-screen prompt on a terminal, or as plain questions
+// answered one line of stdin at a time otherwise. An empty line keeps the default value;
+// when stdin is exhausted utils.ErrNoInput is returned instead of hanging or guessing.
+// In non-interactive mode, nothing is asked and utils.ErrNonInteractive is returned.
+func runForm(form *huh.Form) error {
+	if nonInteractive {
+		return utils.ErrNonInteractive
+	}
+	if interactive() {
+		return form.Run()
+	}
+
+	plainInput.reset()
+	if err := form.WithAccessible(true).WithInput(plainInput).WithOutput(os.Stderr).Run(); err != nil {
+		return err
+	}
+	if plainInput.exhausted() {
+		return utils.ErrNoInput
+	}
+	return nil
+}
+
+// lineReader hands out its input at most one line per Read, so each plain prompt
+// (which scans with its own buffer) only consumes the line it answers
+type lineReader struct {
+	mu   sync.Mutex
+	r    *bufio.Reader
+	read int  // bytes returned since the last reset
+	eof  bool // end of input reached since the last reset
+}
+
+func newLineReader(r io.Reader) *lineReader {
+	return &lineReader{r: bufio.NewReader(r)}
+}
+
+// Read implements io.Reader, stopping after the first newline
+func (l *lineReader) Read(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	n := 0
+	for n < len(p) {
+		b, err := l.r.ReadByte()
+		if err != nil {
+			if n > 0 {
+				break
+			}
+			if err == io.EOF {
+				l.eof = true
+			}
+			return 0, err
+		}
+		p[n] = b
+		n++
+		if b == '\n' {
+			break
+		}
+	}
+	l.read += n
+	return n, nil
+}
+
+// reset starts a new prompt
+func (l *lineReader) reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.read, l.eof = 0, false
+}
+
+// exhausted reports whether the last prompt hit the end of input without reading an answer
+func (l *lineReader) exhausted() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.eof && l.read == 0
+}
@@ -0,0 +1,101 @@
+package ui
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/utils"
+)
+
+// usePlainPrompts answers prompts from input in plain mode for the duration of the test
+func usePlainPrompts(t *testing.T, input string) {
+	t.Helper()
+	previousInteractive, previousInput := interactive, plainInput
+	interactive = func() bool { return false }
+	plainInput = newLineReader(strings.NewReader(input))
+	t.Cleanup(func() {
+		interactive, plainInput = previousInteractive, previousInput
+	})
+}
+
+func TestPlainPrompts(t *testing.T) {
+	usePlainPrompts(t, "n\nfix parser crash\n\n")
+
+	confirm, err := PromptConfirm(nil, "Create commit?", true)
+	if err != nil || confirm {
+		t.Errorf("PromptConfirm() = %v, %v, want false", confirm, err)
+	}
+
+	subject, err := PromptSubjectWithDefault(nil, "")
+	if err != nil || subject != "fix parser crash" {
+		t.Errorf("PromptSubjectWithDefault() = %q, %v, want %q", subject, err, "fix parser crash")
+	}
+
+	// An empty line keeps the default
+	scope, err := PromptScopeWithDefault(nil, "parser")
+	if err != nil || scope != "parser" {
+		t.Errorf("PromptScopeWithDefault() = %q, %v, want default %q", scope, err, "parser")
+	}
+
+	// No more input: fail instead of hanging or silently accepting
+	if _, err := PromptConfirm(nil, "Push?", true); !errors.Is(err, utils.ErrNoInput) {
+		t.Errorf("PromptConfirm() after end of input error = %v, want ErrNoInput", err)
+	}
+}
+
+func TestLineReader_OneLinePerRead(t *testing.T) {
+	r := newLineReader(strings.NewReader("first\nsecond\nlast"))
+	buf := make([]byte, 64)
+
+	var lines []string
+	for {
+		n, err := r.Read(buf)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Read() error = %v", err)
+		}
+		lines = append(lines, string(buf[:n]))
+	}
+
+	want := []string{"first\n", "second\n", "last"}
+	if strings.Join(lines, "|") != strings.Join(want, "|") {
+		t.Errorf("Read() chunks = %q, want %q", lines, want)
+	}
+	if r.exhausted() {
+		t.Error("exhausted() = true although lines were read")
+	}
+	r.reset()
+	if _, err := r.Read(buf); err != io.EOF || !r.exhausted() {
+		t.Errorf("Read() at end of input = %v, exhausted() = %v, want EOF and true", err, r.exhausted())
+	}
+}
+
+func TestSetNonInteractive(t *testing.T) {
+	usePlainPrompts(t, "unused answer\n")
+	SetNonInteractive(true)
+	t.Cleanup(func() { SetNonInteractive(false) })
+
+	// Confirmations take their default answer
+	if confirm, err := PromptConfirm(nil, "Create commit?", true); err != nil || !confirm {
+		t.Errorf("PromptConfirm(default yes) = %v, %v, want true", confirm, err)
+	}
+	if confirm, err := PromptConfirm(nil, "Continue anyway?", false); err != nil || confirm {
+		t.Errorf("PromptConfirm(default no) = %v, %v, want false", confirm, err)
+	}
+	if useAI, err := PromptAIUsage(nil, model.ContextPlan{Tokens: 100}); err != nil || !useAI {
+		t.Errorf("PromptAIUsage() = %v, %v, want true", useAI, err)
+	}
+
+	// Questions without a default fail instead of reading input
+	if _, err := PromptSubjectWithDefault(nil, ""); !errors.Is(err, utils.ErrNonInteractive) {
+		t.Errorf("PromptSubjectWithDefault() error = %v, want ErrNonInteractive", err)
+	}
+	if plainInput.read != 0 {
+		t.Error("input was read in non-interactive mode")
+	}
+}
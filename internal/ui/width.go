@@ -0,0 +1,125 @@
+package ui
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/term"
+)
+
+const (
+	// defaultTerminalWidth is used when the width cannot be detected (not a terminal, no COLUMNS)
+	defaultTerminalWidth = 80
+
+	// minTerminalWidth keeps layouts usable on absurdly narrow terminals
+	minTerminalWidth = 40
+
+	// ellipsis marks truncated text
+	ellipsis = "…"
+)
+
+// TerminalWidth returns the width of the terminal Out prints on, the COLUMNS environment
+// variable when it is not a terminal, or 80 columns
+func TerminalWidth() int {
+	out := Out()
+	isTTY := term.IsTerminal(out.Fd())
+	size := 0
+	if isTTY {
+		if w, _, err := term.GetSize(out.Fd()); err == nil {
+			size = w
+		}
+	}
+	return terminalWidth(isTTY, size, os.Getenv("COLUMNS"))
+}
+
+// terminalWidth implements TerminalWidth with injectable terminal state for testing
+func terminalWidth(isTTY bool, size int, columns string) int {
+	width := 0
+	switch {
+	case isTTY && size > 0:
+		width = size
+	case columns != "":
+		if n, err := strconv.Atoi(strings.TrimSpace(columns)); err == nil {
+			width = n
+		}
+	}
+	if width <= 0 {
+		return defaultTerminalWidth
+	}
+	return max(width, minTerminalWidth)
+}
+
+// TruncateEnd shortens s to width display columns, replacing the end with "…"
+func TruncateEnd(s string, width int) string {
+	if lipgloss.Width(s) <= width {
+		return s
+	}
+	if width <= 0 {
+		return ""
+	}
+	return takeWidth(s, width-1) + ellipsis
+}
+
+// TruncateMiddle shortens s to width display columns, replacing the middle with "…"
+// so both the beginning and the end stay readable
+func TruncateMiddle(s string, width int) string {
+	if lipgloss.Width(s) <= width {
+		return s
+	}
+	if width <= 0 {
+		return ""
+	}
+	tailWidth := (width - 1) / 2
+	headWidth := width - 1 - tailWidth
+	return takeWidth(s, headWidth) + ellipsis + takeLastWidth(s, tailWidth)
+}
+
+// TruncatePath shortens a file path to width display columns. The file name is kept
+// whenever it fits and the directories are shortened in the middle
+// (e.g. "internal/…/service/commit_service.go").
+func TruncatePath(path string, width int) string {
+	if lipgloss.Width(path) <= width {
+		return path
+	}
+	i := strings.LastIndex(path, "/")
+	if i < 0 {
+		return TruncateMiddle(path, width)
+	}
+	dir, base := path[:i], path[i:]
+
+	// Keep at least a few characters of the directories next to the file name
+	dirWidth := width - lipgloss.Width(base)
+	if dirWidth < 4 {
+		return TruncateMiddle(path, width)
+	}
+	return TruncateMiddle(dir, dirWidth) + base
+}
+
+// takeWidth returns the longest prefix of s fitting in width display columns
+func takeWidth(s string, width int) string {
+	used := 0
+	for i, r := range s {
+		w := lipgloss.Width(string(r))
+		if used+w > width {
+			return s[:i]
+		}
+		used += w
+	}
+	return s
+}
+
+// takeLastWidth returns the longest suffix of s fitting in width display columns
+func takeLastWidth(s string, width int) string {
+	runes := []rune(s)
+	used := 0
+	for i := len(runes) - 1; i >= 0; i-- {
+		w := lipgloss.Width(string(runes[i]))
+		if used+w > width {
+			return string(runes[i+1:])
+		}
+		used += w
+	}
+	return s
+}
@@ -0,0 +1,78 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/golgoth31/gitcomm/internal/model"
+)
+
+func TestTerminalWidth(t *testing.T) {
+	tests := []struct {
+		name    string
+		isTTY   bool
+		size    int
+		columns string
+		want    int
+	}{
+		{name: "terminal size", isTTY: true, size: 120, want: 120},
+		{name: "terminal size wins over COLUMNS", isTTY: true, size: 100, columns: "60", want: 100},
+		{name: "COLUMNS when not a terminal", columns: "90", want: 90},
+		{name: "invalid COLUMNS", columns: "wide", want: defaultTerminalWidth},
+		{name: "nothing known", want: defaultTerminalWidth},
+		{name: "very narrow terminal", isTTY: true, size: 20, want: minTerminalWidth},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := terminalWidth(tt.isTTY, tt.size, tt.columns); got != tt.want {
+				t.Errorf("terminalWidth() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		name  string
+		fn    func(string, int) string
+		input string
+		width int
+		want  string
+	}{
+		{name: "end fits", fn: TruncateEnd, input: "feat: short", width: 20, want: "feat: short"},
+		{name: "end truncated", fn: TruncateEnd, input: "feat: add a very long subject", width: 12, want: "feat: add a…"},
+		{name: "middle truncated", fn: TruncateMiddle, input: "feature/very-long-branch-name", width: 11, want: "featu…-name"},
+		{name: "middle wide runes", fn: TruncateMiddle, input: "日本語のブランチ名", width: 9, want: "日本…チ名"},
+		{name: "path keeps file name", fn: TruncatePath, input: "internal/repository/git_repository_impl.go", width: 35, want: "intern…itory/git_repository_impl.go"},
+		{name: "path fits", fn: TruncatePath, input: "cmd/main.go", width: 20, want: "cmd/main.go"},
+		{name: "path with long file name", fn: TruncatePath, input: "a/an_extremely_long_generated_file_name.pb.go", width: 20, want: "a/an_extre…ame.pb.go"},
+		{name: "zero width", fn: TruncateEnd, input: "feat: x", width: 0, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.fn(tt.input, tt.width)
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+			if w := lipgloss.Width(got); w > tt.width {
+				t.Errorf("width of %q = %d, exceeds %d", got, w, tt.width)
+			}
+		})
+	}
+}
+
+func TestFormatCommitTarget_FitsWidth(t *testing.T) {
+	state := &model.RepositoryState{
+		Branch:            "feature/a-branch-name-that-goes-on-and-on-forever",
+		Upstream:          "origin/feature/a-branch-name-that-goes-on-and-on-forever",
+		Ahead:             1,
+		LastCommitSubject: "refactor(repository): split the git command runner into smaller helpers",
+	}
+
+	got := formatCommitTarget(state, "", 80)
+	if w := lipgloss.Width(got); w > 80 {
+		t.Errorf("formatCommitTarget() width = %d, want at most 80: %q", w, got)
+	}
+}
@@ -0,0 +1,155 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/term"
+)
+
+// maxWordDiffTokens bounds the messages compared word by word: longer ones are shown
+// as a whole removal and addition
+const maxWordDiffTokens = 2000
+
+// PrintMessageChanges prints the word diff between the previous and the regenerated
+// candidate message, so what changed stands out
+func PrintMessageChanges(previous, current string) {
+	fmt.Fprintln(Out(), "\n--- Changes from the previous message ---")
+	fmt.Fprintln(Out(), WordDiff(previous, current))
+}
+
+// WordDiff returns current with the words that changed since previous marked: removed
+// words in red and struck through, added words in green. Without a color terminal (or
+// with NO_COLOR) the markers of git diff --word-diff=plain are used: [-removed-]{+added+}.
+func WordDiff(previous, current string) string {
+	color := term.IsTerminal(Out().Fd()) && os.Getenv("NO_COLOR") == ""
+	return formatWordDiff(previous, current, color)
+}
+
+// formatWordDiff implements WordDiff with the color choice injected for testing
+func formatWordDiff(previous, current string, color bool) string {
+	removed := func(s string) string { return "[-" + s + "-]" }
+	added := func(s string) string { return "{+" + s + "+}" }
+	if color {
+		removedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("1")).Strikethrough(true)
+		addedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("2"))
+		removed = func(s string) string { return renderLines(removedStyle, s) }
+		added = func(s string) string { return renderLines(addedStyle, s) }
+	}
+
+	var sb strings.Builder
+	var deletions, insertions []string
+	flush := func() {
+		if len(deletions) > 0 {
+			sb.WriteString(removed(strings.Join(deletions, "")))
+		}
+		if len(insertions) > 0 {
+			sb.WriteString(added(strings.Join(insertions, "")))
+		}
+		deletions, insertions = nil, nil
+	}
+	for _, op := range diffTokens(tokenizeWords(previous), tokenizeWords(current)) {
+		switch op.kind {
+		case '-':
+			deletions = append(deletions, op.token)
+		case '+':
+			insertions = append(insertions, op.token)
+		default:
+			flush()
+			sb.WriteString(op.token)
+		}
+	}
+	flush()
+	return sb.String()
+}
+
+// renderLines styles each line of s on its own: lipgloss pads multi-line blocks
+func renderLines(style lipgloss.Style, s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = style.Render(line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// tokenizeWords splits text into alternating runs of spaces and words, so that joining
+// the tokens gives the text back
+func tokenizeWords(text string) []string {
+	var tokens []string
+	start, space := 0, false
+	for i, r := range text {
+		if i > start && unicode.IsSpace(r) != space {
+			tokens = append(tokens, text[start:i])
+			start = i
+		}
+		space = unicode.IsSpace(r)
+	}
+	if start < len(text) {
+		tokens = append(tokens, text[start:])
+	}
+	return tokens
+}
+
+// tokenOp is one step of a token diff: kept (' '), removed ('-') or added ('+')
+type tokenOp struct {
+	kind  byte
+	token string
+}
+
+// diffTokens returns the steps turning a into b along their longest common subsequence
+func diffTokens(a, b []string) []tokenOp {
+	if len(a) > maxWordDiffTokens || len(b) > maxWordDiffTokens {
+		var ops []tokenOp
+		for _, token := range a {
+			ops = append(ops, tokenOp{'-', token})
+		}
+		for _, token := range b {
+			ops = append(ops, tokenOp{'+', token})
+		}
+		return ops
+	}
+
+	// common[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	common := make([][]int, len(a)+1)
+	for i := range common {
+		common[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				common[i][j] = common[i+1][j+1] + 1
+			} else {
+				common[i][j] = max(common[i+1][j], common[i][j+1])
+			}
+		}
+	}
+
+	var ops []tokenOp
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, tokenOp{' ', a[i]})
+			i++
+			j++
+		case common[i+1][j] >= common[i][j+1]:
+			ops = append(ops, tokenOp{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, tokenOp{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		ops = append(ops, tokenOp{'-', a[i]})
+	}
+	for ; j < len(b); j++ {
+		ops = append(ops, tokenOp{'+', b[j]})
+	}
+	return ops
+}
@@ -0,0 +1,74 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormatWordDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		previous string
+		current  string
+		want     string
+	}{
+		{
+			name:     "identical messages",
+			previous: "fix(api): handle empty responses",
+			current:  "fix(api): handle empty responses",
+			want:     "fix(api): handle empty responses",
+		},
+		{
+			name:     "replaced word",
+			previous: "fix(api): handle empty responses",
+			current:  "fix(api): handle missing responses",
+			want:     "fix(api): handle [-empty-]{+missing+} responses",
+		},
+		{
+			name:     "added words",
+			previous: "feat: add login",
+			current:  "feat: add login and logout",
+			want:     "feat: add login{+ and logout+}",
+		},
+		{
+			name:     "removed body",
+			previous: "feat: add login\n\nUse JWT.",
+			current:  "feat: add login",
+			want:     "feat: add login[-\n\nUse JWT.-]",
+		},
+		{
+			name:     "changed type and body line",
+			previous: "feat(auth): add login\n\nUse JWT tokens.",
+			current:  "fix(auth): add login\n\nUse session tokens.",
+			want:     "[-feat(auth):-]{+fix(auth):+} add login\n\nUse [-JWT-]{+session+} tokens.",
+		},
+		{
+			name:     "from empty",
+			previous: "",
+			current:  "docs: update readme",
+			want:     "{+docs: update readme+}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatWordDiff(tt.previous, tt.current, false); got != tt.want {
+				t.Errorf("formatWordDiff() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTokenizeWords(t *testing.T) {
+	for _, text := range []string{"", "word", "  leading and trailing  ", "feat: é accents\n\nbody\tline"} {
+		if got := strings.Join(tokenizeWords(text), ""); got != text {
+			t.Errorf("tokenizeWords(%q) joined = %q", text, got)
+		}
+	}
+
+	got := tokenizeWords("fix: a  b")
+	want := []string{"fix:", " ", "a", "  ", "b"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("tokenizeWords() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,279 @@
+// Package update checks whether a newer gitcomm release is available.
+package update
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// ReleaseURL is the GitHub API endpoint returning the latest release
+	ReleaseURL = "https://api.github.com/repos/golgoth31/gitcomm/releases/latest"
+
+	// requestTimeout bounds the release lookup so it never delays the CLI noticeably
+	requestTimeout = 3 * time.Second
+
+	// cacheKey is the key of the last lookup in Cache
+	cacheKey = "update-check"
+)
+
+// Cache keeps the last lookup instead of StatePath (see store.Store)
+type Cache interface {
+	GetCache(ctx context.Context, key string) ([]byte, bool, error)
+	PutCache(ctx context.Context, key string, value []byte) error
+}
+
+// Checker looks up the latest release at most once per Interval.
+// The last result is cached in Cache, or StatePath without one, so runs in between cost nothing.
+type Checker struct {
+	// Current is the running version (e.g. "v1.2.0")
+	Current string
+	// Interval is the minimum time between two release lookups
+	Interval time.Duration
+	// StatePath is the file caching the last lookup when Cache is nil
+	StatePath string
+	// Cache caches the last lookup (default: StatePath)
+	Cache Cache
+	// URL is the release endpoint (default: ReleaseURL)
+	URL string
+	// Client performs the request (default: http.Client with a short timeout)
+	Client *http.Client
+	// Now returns the current time (default: time.Now)
+	Now func() time.Time
+}
+
+// Notice describes an available upgrade
+type Notice struct {
+	Current string
+	Latest  string
+	// Command is the upgrade command for the detected install method
+	Command string
+}
+
+// String returns the one-line upgrade hint
+func (n *Notice) String() string {
+	return fmt.Sprintf("A new version of gitcomm is available: %s → %s (upgrade with: %s)", n.Current, n.Latest, n.Command)
+}
+
+// state is the cached result of the last lookup
+type state struct {
+	CheckedAt time.Time `json:"checked_at"`
+	Latest    string    `json:"latest"`
+}
+
+// NewChecker creates a checker for the running version, caching results in ~/.gitcomm
+func NewChecker(current string, interval time.Duration) (*Checker, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return &Checker{
+		Current:   current,
+		Interval:  interval,
+		StatePath: filepath.Join(homeDir, ".gitcomm", "update-check.json"),
+		URL:       ReleaseURL,
+		Client:    &http.Client{Timeout: requestTimeout},
+		Now:       time.Now,
+	}, nil
+}
+
+// Disabled reports why the check must not run, or "" when it may.
+// The check is opt-in and always skipped on CI or with GITCOMM_NO_UPDATE_CHECK set.
+func Disabled(enabled bool, current string) string {
+	switch {
+	case !enabled:
+		return "update.check is disabled"
+	case os.Getenv("GITCOMM_NO_UPDATE_CHECK") != "":
+		return "GITCOMM_NO_UPDATE_CHECK is set"
+	case os.Getenv("CI") != "":
+		return "running on CI"
+	case !IsRelease(current):
+		return "development build"
+	}
+	return ""
+}
+
+// Check returns a notice when a newer release exists, or nil when up to date.
+// The release endpoint is only queried when the cached result is older than Interval.
+func (c *Checker) Check(ctx context.Context) (*Notice, error) {
+	now := c.Now()
+	cached, _ := c.loadState(ctx)
+
+	latest := cached.Latest
+	if now.Sub(cached.CheckedAt) >= c.Interval {
+		fetched, err := c.fetchLatest(ctx)
+		if err != nil {
+			// Record the attempt so an unreachable endpoint is not retried on every run
+			_ = c.saveState(ctx, state{CheckedAt: now, Latest: cached.Latest})
+			return nil, err
+		}
+		if err := c.saveState(ctx, state{CheckedAt: now, Latest: fetched}); err != nil {
+			return nil, err
+		}
+		latest = fetched
+	}
+
+	if !newer(latest, c.Current) {
+		return nil, nil
+	}
+	return &Notice{
+		Current: c.Current,
+		Latest:  latest,
+		Command: UpgradeCommand(executablePath()),
+	}, nil
+}
+
+// fetchLatest returns the tag of the latest release
+func (c *Checker) fetchLatest(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.URL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create release request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch latest release: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to fetch latest release: unexpected status %s", resp.Status)
+	}
+
+	var release struct {
+		TagName string `json:"tag_name"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return "", fmt.Errorf("failed to decode latest release: %w", err)
+	}
+	if release.TagName == "" {
+		return "", errors.New("latest release has no tag")
+	}
+	return release.TagName, nil
+}
+
+// loadState reads the cached lookup; a missing or corrupt one yields an empty state
+func (c *Checker) loadState(ctx context.Context) (state, error) {
+	var s state
+	data, err := c.readState(ctx)
+	if err != nil {
+		return s, err
+	}
+	if err := json.Unmarshal(data, &s); err != nil {
+		return state{}, err
+	}
+	return s, nil
+}
+
+// readState returns the encoded cached lookup
+func (c *Checker) readState(ctx context.Context) ([]byte, error) {
+	if c.Cache == nil {
+		return os.ReadFile(c.StatePath)
+	}
+	data, ok, err := c.Cache.GetCache(ctx, cacheKey)
+	if err == nil && !ok {
+		err = errors.New("no cached update check")
+	}
+	return data, err
+}
+
+// saveState writes the cached lookup
+func (c *Checker) saveState(ctx context.Context, s state) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("failed to encode update state: %w", err)
+	}
+	if c.Cache != nil {
+		return c.Cache.PutCache(ctx, cacheKey, data)
+	}
+	if err := os.MkdirAll(filepath.Dir(c.StatePath), 0755); err != nil {
+		return fmt.Errorf("failed to create update state directory: %w", err)
+	}
+	if err := os.WriteFile(c.StatePath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write update state: %w", err)
+	}
+	return nil
+}
+
+// UpgradeCommand returns the upgrade command matching how the binary at path was installed
+func UpgradeCommand(path string) string {
+	p := strings.ToLower(filepath.ToSlash(path))
+	switch {
+	case strings.Contains(p, "/cellar/") || strings.Contains(p, "/homebrew/") || strings.Contains(p, "/linuxbrew/"):
+		return "brew upgrade gitcomm"
+	case strings.Contains(p, "/scoop/"):
+		return "scoop update gitcomm"
+	case strings.Contains(p, "/go/bin/"):
+		return "go install github.com/golgoth31/gitcomm/cmd/gitcomm@latest"
+	default:
+		return "download it from https://github.com/golgoth31/gitcomm/releases/latest"
+	}
+}
+
+// executablePath returns the resolved path of the running binary, or "" if unknown
+func executablePath() string {
+	path, err := os.Executable()
+	if err != nil {
+		return ""
+	}
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+	return path
+}
+
+// IsRelease reports whether v is a release version (as set by goreleaser from the tag)
+func IsRelease(v string) bool {
+	_, ok := parseVersion(v)
+	return ok
+}
+
+// newer reports whether latest is a higher version than current.
+// Pre-releases of the latest tag are never offered.
+func newer(latest, current string) bool {
+	l, ok := parseVersion(latest)
+	if !ok || strings.Contains(latest, "-") {
+		return false
+	}
+	c, ok := parseVersion(current)
+	if !ok {
+		return false
+	}
+	for i := range l {
+		if l[i] != c[i] {
+			return l[i] > c[i]
+		}
+	}
+	// Same version: a release is newer than a pre-release of it
+	return strings.Contains(current, "-")
+}
+
+// parseVersion parses "vMAJOR.MINOR.PATCH[-pre][+build]" into its numeric parts
+func parseVersion(v string) ([3]int, bool) {
+	var parts [3]int
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+	fields := strings.Split(v, ".")
+	if len(fields) != 3 {
+		return parts, false
+	}
+	for i, field := range fields {
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 0 {
+			return parts, false
+		}
+		parts[i] = n
+	}
+	return parts, true
+}
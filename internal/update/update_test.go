@@ -0,0 +1,194 @@
+package update
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golgoth31/gitcomm/internal/store"
+)
+
+func TestNewer(t *testing.T) {
+	tests := []struct {
+		latest  string
+		current string
+		want    bool
+	}{
+		{"v1.3.0", "v1.2.0", true},
+		{"v1.2.1", "v1.2.0", true},
+		{"v2.0.0", "v1.9.9", true},
+		{"v1.2.0", "v1.2.0", false},
+		{"v1.1.0", "v1.2.0", false},
+		{"v1.2.0", "v1.2.0-rc.1", true},
+		{"v1.3.0-rc.1", "v1.2.0", false},
+		{"v1.10.0", "v1.9.0", true},
+		{"latest", "v1.2.0", false},
+		{"v1.3.0", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.latest+"_vs_"+tt.current, func(t *testing.T) {
+			if got := newer(tt.latest, tt.current); got != tt.want {
+				t.Errorf("newer(%q, %q) = %v, want %v", tt.latest, tt.current, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpgradeCommand(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		want string
+	}{
+		{"homebrew macos", "/opt/homebrew/Cellar/gitcomm/1.2.0/bin/gitcomm", "brew upgrade gitcomm"},
+		{"linuxbrew", "/home/linuxbrew/.linuxbrew/bin/gitcomm", "brew upgrade gitcomm"},
+		{"scoop", "C:/Users/jane/scoop/apps/gitcomm/current/gitcomm.exe", "scoop update gitcomm"},
+		{"go install", "/home/jane/go/bin/gitcomm", "go install github.com/golgoth31/gitcomm/cmd/gitcomm@latest"},
+		{"manual", "/usr/local/bin/gitcomm", "download it from https://github.com/golgoth31/gitcomm/releases/latest"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := UpgradeCommand(tt.path); got != tt.want {
+				t.Errorf("UpgradeCommand(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDisabled(t *testing.T) {
+	t.Setenv("GITCOMM_NO_UPDATE_CHECK", "")
+	t.Setenv("CI", "")
+
+	if reason := Disabled(false, "v1.0.0"); reason == "" {
+		t.Error("Disabled() should skip the check when not enabled")
+	}
+	if reason := Disabled(true, ""); reason == "" {
+		t.Error("Disabled() should skip the check for development builds")
+	}
+	if reason := Disabled(true, "v1.0.0"); reason != "" {
+		t.Errorf("Disabled() = %q, want check enabled", reason)
+	}
+
+	t.Setenv("CI", "true")
+	if reason := Disabled(true, "v1.0.0"); reason == "" {
+		t.Error("Disabled() should skip the check on CI")
+	}
+}
+
+func TestChecker_Check_RateLimited(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		_, _ = w.Write([]byte(`{"tag_name": "v1.3.0"}`))
+	}))
+	defer server.Close()
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	checker := &Checker{
+		Current:   "v1.2.0",
+		Interval:  24 * time.Hour,
+		StatePath: filepath.Join(t.TempDir(), "update-check.json"),
+		URL:       server.URL,
+		Client:    server.Client(),
+		Now:       func() time.Time { return now },
+	}
+
+	notice, err := checker.Check(context.Background())
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if notice == nil || notice.Latest != "v1.3.0" {
+		t.Fatalf("Check() notice = %+v, want latest v1.3.0", notice)
+	}
+
+	// Within the interval the cached result is used
+	now = now.Add(time.Hour)
+	notice, err = checker.Check(context.Background())
+	if err != nil || notice == nil {
+		t.Fatalf("Check() = %+v, %v; want cached notice", notice, err)
+	}
+	if requests != 1 {
+		t.Errorf("release endpoint queried %d times, want 1", requests)
+	}
+
+	// After the interval the endpoint is queried again
+	now = now.Add(24 * time.Hour)
+	if _, err := checker.Check(context.Background()); err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("release endpoint queried %d times, want 2", requests)
+	}
+}
+
+func TestChecker_Check_FailureIsRateLimited(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	checker := &Checker{
+		Current:   "v1.2.0",
+		Interval:  24 * time.Hour,
+		StatePath: filepath.Join(t.TempDir(), "update-check.json"),
+		URL:       server.URL,
+		Client:    server.Client(),
+		Now:       time.Now,
+	}
+
+	if _, err := checker.Check(context.Background()); err == nil {
+		t.Fatal("Check() should fail on an error status")
+	}
+	notice, err := checker.Check(context.Background())
+	if err != nil || notice != nil {
+		t.Errorf("Check() = %+v, %v; want no notice and no error within the interval", notice, err)
+	}
+	if requests != 1 {
+		t.Errorf("release endpoint queried %d times, want 1", requests)
+	}
+}
+
+func TestChecker_Check_Cache(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		_, _ = w.Write([]byte(`{"tag_name": "v1.3.0"}`))
+	}))
+	defer server.Close()
+
+	cache := store.NewMemory()
+	statePath := filepath.Join(t.TempDir(), "update-check.json")
+	newChecker := func() *Checker {
+		return &Checker{
+			Current:   "v1.2.0",
+			Interval:  24 * time.Hour,
+			StatePath: statePath,
+			Cache:     cache,
+			URL:       server.URL,
+			Client:    server.Client(),
+			Now:       time.Now,
+		}
+	}
+
+	// A later run reads the lookup of the first one from the cache
+	for range 2 {
+		notice, err := newChecker().Check(context.Background())
+		if err != nil || notice == nil || notice.Latest != "v1.3.0" {
+			t.Fatalf("Check() = %+v, %v; want latest v1.3.0", notice, err)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("release endpoint queried %d times, want 1", requests)
+	}
+	if _, err := os.Stat(statePath); !os.IsNotExist(err) {
+		t.Errorf("state file written with a cache, stat error = %v", err)
+	}
+}
@@ -20,6 +20,9 @@ var (
 	// ErrAIProviderUnavailable indicates the AI provider is unavailable or returned an error
 	ErrAIProviderUnavailable = errors.New("AI provider unavailable: check API key and network connection")
 
+	// ErrCircuitOpen indicates the AI provider is not called after repeated failures, until its cooldown ends
+	ErrCircuitOpen = errors.New("AI provider skipped after repeated failures")
+
 	// ErrEmptySubject indicates the commit message subject is empty
 	ErrEmptySubject = errors.New("commit message subject cannot be empty: subject is required")
 
@@ -41,9 +44,32 @@ var (
 	// ErrInterruptedDuringStaging indicates CLI was interrupted while staging was in progress
 	ErrInterruptedDuringStaging = errors.New("interrupted during staging: CLI was interrupted while staging was in progress. Staging state has been restored")
 
+	// ErrNoInput indicates a prompt could not be answered: stdin is not a terminal and has no more lines
+	ErrNoInput = errors.New("no input available: stdin is not a terminal and has no more lines to answer prompts")
+
+	// ErrNonInteractive indicates a prompt needs an answer that cannot be defaulted in non-interactive mode
+	ErrNonInteractive = errors.New("input required: gitcomm runs non-interactively (--yes) and this question has no default answer")
+
 	// ErrCommitAlreadyCreated indicates the commit was already created (e.g., via AcceptAndCommit)
 	// This is a sentinel error that should be handled by skipping further commit processing
 	ErrCommitAlreadyCreated = errors.New("commit already created")
+
+	// ErrCommitPublished indicates the commit to amend or undo is on a remote-tracking branch
+	ErrCommitPublished = errors.New("commit already pushed: replacing it rewrites published history, use --force to proceed anyway")
+
+	// ErrRepositoryDisabled indicates the repository opted out of gitcomm
+	// (.gitcomm-disable marker file or git config gitcomm.enabled=false)
+	ErrRepositoryDisabled = errors.New("gitcomm is disabled in this repository")
+
+	// ErrForeignHook indicates a git hook of the same name, not installed by gitcomm, is in the way
+	ErrForeignHook = errors.New("git hook not installed by gitcomm: use --force to replace it")
+
+	// ErrBranchBehind indicates the commit was declined because the branch is behind its upstream
+	ErrBranchBehind = errors.New("branch is behind its upstream: pull or rebase first")
+
+	// ErrConfirmationRequired indicates a history rewrite was refused in non-interactive mode
+	// because --confirm did not name the branch or the confirmation token
+	ErrConfirmationRequired = errors.New("confirmation required: this rewrites history and runs non-interactively")
 )
 
 // WrapError wraps an error with additional context
@@ -6,6 +6,7 @@ import (
 	"io"
 	"os"
 	"runtime"
+	"runtime/debug"
 )
 
 var (
@@ -14,8 +15,52 @@ var (
 	gitBranch      string
 	lastCommitSHA  string
 	lastCommitTime string
+	builtBy        string
 )
 
+// Info describes the gitcomm binary
+type Info struct {
+	Version    string `json:"version"`
+	Commit     string `json:"commit"`
+	CommitTime string `json:"commit_time"`
+	Branch     string `json:"branch"`
+	// BuiltBy is the release tool that produced the binary (e.g. goreleaser), empty for local builds
+	BuiltBy   string `json:"built_by"`
+	Checksum  string `json:"sha256"`
+	GoVersion string `json:"go_version"`
+	Platform  string `json:"platform"`
+}
+
+// Details returns the build information of the binary. Without -ldflags (go install, go build),
+// the commit and its time are read from the VCS information embedded by the Go toolchain.
+func Details() Info {
+	info := Info{
+		Version:    version,
+		Commit:     lastCommitSHA,
+		CommitTime: lastCommitTime,
+		Branch:     gitBranch,
+		BuiltBy:    builtBy,
+		Checksum:   fmt.Sprintf("%x", ExecutableChecksum()),
+		GoVersion:  runtime.Version(),
+		Platform:   runtime.GOOS + "/" + runtime.GOARCH,
+	}
+
+	if build, ok := debug.ReadBuildInfo(); ok {
+		if info.Version == "" && build.Main.Version != "(devel)" {
+			info.Version = build.Main.Version
+		}
+		for _, setting := range build.Settings {
+			switch {
+			case setting.Key == "vcs.revision" && info.Commit == "":
+				info.Commit = setting.Value
+			case setting.Key == "vcs.time" && info.CommitTime == "":
+				info.CommitTime = setting.Value
+			}
+		}
+	}
+	return info
+}
+
 // BuildDetails returns a string containing details about the gitcomm binary.
 func BuildDetails() string {
 	licenseInfo := `Licensed under the MIT License`
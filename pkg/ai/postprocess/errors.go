@@ -0,0 +1,8 @@
+package postprocess
+
+import "errors"
+
+var (
+	// ErrUnknownProcessor is returned when a pipeline references a post-processor that does not exist
+	ErrUnknownProcessor = errors.New("unknown post-processor")
+)
@@ -0,0 +1,150 @@
+package postprocess
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Post-processor names accepted in the ai.post_processors configuration list
+const (
+	TrimCodeFences          = "trim-code-fences"
+	StripQuotes             = "strip-quotes"
+	EnforceLowercaseSubject = "enforce-lowercase-subject"
+	RemoveTrailingPeriod    = "remove-trailing-period"
+	CollapseBlankLines      = "collapse-blank-lines"
+)
+
+// Processor transforms raw AI provider output
+type Processor func(string) string
+
+// processors maps configuration names to their implementation
+var processors = map[string]Processor{
+	TrimCodeFences:          trimCodeFences,
+	StripQuotes:             stripQuotes,
+	EnforceLowercaseSubject: enforceLowercaseSubject,
+	RemoveTrailingPeriod:    removeTrailingPeriod,
+	CollapseBlankLines:      collapseBlankLines,
+}
+
+// Names returns the sorted list of available post-processor names
+func Names() []string {
+	names := make([]string, 0, len(processors))
+	for name := range processors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Pipeline applies an ordered list of post-processors to AI output
+type Pipeline struct {
+	steps []Processor
+}
+
+// NewPipeline creates a pipeline from post-processor names, in the given order.
+// An empty list yields a pipeline that returns its input unchanged.
+func NewPipeline(names []string) (*Pipeline, error) {
+	pipeline := &Pipeline{}
+	for _, name := range names {
+		processor, ok := processors[strings.TrimSpace(name)]
+		if !ok {
+			return nil, fmt.Errorf("%w: %q (available: %s)", ErrUnknownProcessor, name, strings.Join(Names(), ", "))
+		}
+		pipeline.steps = append(pipeline.steps, processor)
+	}
+	return pipeline, nil
+}
+
+// Apply runs every post-processor in order on the given text
+func (p *Pipeline) Apply(text string) string {
+	for _, step := range p.steps {
+		text = step(text)
+	}
+	return text
+}
+
+// trimCodeFences removes a markdown code fence wrapping the answer (``` or ```lang)
+func trimCodeFences(text string) string {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	if len(lines) < 2 || !strings.HasPrefix(strings.TrimSpace(lines[0]), "```") {
+		return text
+	}
+	lines = lines[1:]
+	if last := len(lines) - 1; strings.TrimSpace(lines[last]) == "```" {
+		lines = lines[:last]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// stripQuotes removes matching quotes or backticks wrapping the whole answer
+func stripQuotes(text string) string {
+	trimmed := strings.TrimSpace(text)
+	for _, quote := range []string{`"""`, `"`, `'`, "`"} {
+		if len(trimmed) > 2*len(quote) && strings.HasPrefix(trimmed, quote) && strings.HasSuffix(trimmed, quote) {
+			return strings.TrimSpace(trimmed[len(quote) : len(trimmed)-len(quote)])
+		}
+	}
+	return text
+}
+
+// enforceLowercaseSubject lowercases the first letter of the subject in the header line.
+// Acronyms (e.g. "API", "HTTP") are left untouched.
+func enforceLowercaseSubject(text string) string {
+	return mapHeader(text, func(header string) string {
+		idx := strings.Index(header, ": ")
+		if idx < 0 {
+			return header
+		}
+		prefix, subject := header[:idx+2], header[idx+2:]
+
+		first, size := utf8.DecodeRuneInString(subject)
+		if !unicode.IsUpper(first) {
+			return header
+		}
+		if next, _ := utf8.DecodeRuneInString(subject[size:]); unicode.IsUpper(next) {
+			return header // Acronym
+		}
+		return prefix + string(unicode.ToLower(first)) + subject[size:]
+	})
+}
+
+// removeTrailingPeriod removes trailing periods from the header line
+func removeTrailingPeriod(text string) string {
+	return mapHeader(text, func(header string) string {
+		return strings.TrimRight(strings.TrimRightFunc(header, unicode.IsSpace), ".")
+	})
+}
+
+// collapseBlankLines trims trailing whitespace and collapses runs of blank lines into one
+func collapseBlankLines(text string) string {
+	var result []string
+	blank := false
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		line = strings.TrimRightFunc(line, unicode.IsSpace)
+		if line == "" {
+			if blank {
+				continue
+			}
+			blank = true
+		} else {
+			blank = false
+		}
+		result = append(result, line)
+	}
+	return strings.Join(result, "\n")
+}
+
+// mapHeader applies fn to the first non-blank line of text
+func mapHeader(text string, fn func(string) string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			lines[i] = fn(line)
+			break
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,94 @@
+package postprocess
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPipeline_Apply(t *testing.T) {
+	tests := []struct {
+		name       string
+		processors []string
+		input      string
+		want       string
+	}{
+		{
+			name:  "empty pipeline returns input unchanged",
+			input: "```\nfeat: Add thing.\n```",
+			want:  "```\nfeat: Add thing.\n```",
+		},
+		{
+			name:       "trim code fences with language",
+			processors: []string{TrimCodeFences},
+			input:      "```text\nfeat: add thing\n\nbody\n```",
+			want:       "feat: add thing\n\nbody",
+		},
+		{
+			name:       "trim code fences leaves unfenced text",
+			processors: []string{TrimCodeFences},
+			input:      "feat: add thing",
+			want:       "feat: add thing",
+		},
+		{
+			name:       "strip double quotes",
+			processors: []string{StripQuotes},
+			input:      "\"feat: add thing\"",
+			want:       "feat: add thing",
+		},
+		{
+			name:       "strip backticks",
+			processors: []string{StripQuotes},
+			input:      "`feat: add thing`",
+			want:       "feat: add thing",
+		},
+		{
+			name:       "enforce lowercase subject",
+			processors: []string{EnforceLowercaseSubject},
+			input:      "feat(api): Add thing\n\nBody stays",
+			want:       "feat(api): add thing\n\nBody stays",
+		},
+		{
+			name:       "enforce lowercase subject keeps acronyms",
+			processors: []string{EnforceLowercaseSubject},
+			input:      "fix: HTTP client timeout",
+			want:       "fix: HTTP client timeout",
+		},
+		{
+			name:       "remove trailing period from header only",
+			processors: []string{RemoveTrailingPeriod},
+			input:      "feat: add thing.\n\nBody sentence.",
+			want:       "feat: add thing\n\nBody sentence.",
+		},
+		{
+			name:       "collapse blank lines",
+			processors: []string{CollapseBlankLines},
+			input:      "feat: add thing  \n\n\n\nbody\n\n\nfooter\n\n",
+			want:       "feat: add thing\n\nbody\n\nfooter",
+		},
+		{
+			name:       "processors run in order",
+			processors: []string{TrimCodeFences, StripQuotes, EnforceLowercaseSubject, RemoveTrailingPeriod, CollapseBlankLines},
+			input:      "```\n\"feat: Add thing.\n\n\n\nbody\"\n```",
+			want:       "feat: add thing\n\nbody",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pipeline, err := NewPipeline(tt.processors)
+			if err != nil {
+				t.Fatalf("NewPipeline() error = %v", err)
+			}
+			if got := pipeline.Apply(tt.input); got != tt.want {
+				t.Errorf("Apply() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewPipeline_UnknownProcessor(t *testing.T) {
+	_, err := NewPipeline([]string{TrimCodeFences, "make-it-better"})
+	if !errors.Is(err, ErrUnknownProcessor) {
+		t.Errorf("NewPipeline() error = %v, want ErrUnknownProcessor", err)
+	}
+}
@@ -0,0 +1,39 @@
+package prompt
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/pkg/conventional"
+)
+
+// CorrectionSystemMessage returns the system message asking the model to fix a commit
+// message breaking the rules of validator, in the header layout (nil: type(scope): subject)
+func CorrectionSystemMessage(validator conventional.MessageValidator, header *model.HeaderLayout) (string, error) {
+	if validator == nil {
+		return "", ErrNilValidator
+	}
+
+	var sb strings.Builder
+	sb.WriteString("You fix git commit messages that break the Conventional Commits rules below.")
+	sb.WriteString(" Keep the meaning and the wording of the message: change only what the broken rules require")
+	sb.WriteString(" (pick the closest type, shorten the subject or the body, fix the scope).\n\n")
+	sb.WriteString("Answer with the corrected message only, without markdown or explanations.\n\n")
+	sb.WriteString(fmt.Sprintf("Format: %s\n\nbody\n\nfooter\n\n", header.Template()))
+	writeValidationRules(&sb, validator, header)
+	return sb.String(), nil
+}
+
+// CorrectionUserMessage returns the user message with the commit message to fix and the
+// rules it breaks
+func CorrectionUserMessage(message string, violations []conventional.ValidationError) string {
+	var sb strings.Builder
+	sb.WriteString("Commit message:\n")
+	sb.WriteString(strings.TrimSpace(message))
+	sb.WriteString("\n\nBroken rules:\n")
+	for _, violation := range violations {
+		sb.WriteString(fmt.Sprintf("- %s: %s\n", violation.Field, violation.Message))
+	}
+	return sb.String()
+}
@@ -0,0 +1,33 @@
+package prompt
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/golgoth31/gitcomm/pkg/conventional"
+)
+
+func TestCorrectionMessages(t *testing.T) {
+	if _, err := CorrectionSystemMessage(nil, nil); !errors.Is(err, ErrNilValidator) {
+		t.Errorf("CorrectionSystemMessage(nil) error = %v, want ErrNilValidator", err)
+	}
+
+	system, err := CorrectionSystemMessage(conventional.NewValidator(), nil)
+	if err != nil {
+		t.Fatalf("CorrectionSystemMessage() error = %v", err)
+	}
+	for _, want := range []string{"Keep the meaning and the wording", "type(scope): subject", "72"} {
+		if !strings.Contains(system, want) {
+			t.Errorf("system message lacks %q:\n%s", want, system)
+		}
+	}
+
+	user := CorrectionUserMessage("  feature: add login\n", []conventional.ValidationError{
+		{Field: "type", Message: "type must be one of: feat, fix"},
+	})
+	want := "Commit message:\nfeature: add login\n\nBroken rules:\n- type: type must be one of: feat, fix\n"
+	if user != want {
+		t.Errorf("CorrectionUserMessage() = %q, want %q", user, want)
+	}
+}
@@ -0,0 +1,122 @@
+package prompt
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+)
+
+// minRepeatedChange is the number of files making the same edit from which only one of
+// their diffs is sent: mass renames and license header updates otherwise fill the context
+// with copies of one hunk
+const minRepeatedChange = 3
+
+// editWords splits the lines of a hunk into identifiers and punctuation, so that
+// "a.OldName(x)" and "b.OldName(y)" share the word OldName
+var editWords = regexp.MustCompile(`[\p{L}\p{N}_]+|[^\s\p{L}\p{N}_]`)
+
+// repeatedChanges finds the staged files making the same edit as an earlier file, returning
+// the index of each copy mapped to the index of the first file of its group. Groups smaller
+// than minRepeatedChange are left alone.
+func repeatedChanges(files []model.FileChange) map[int]int {
+	groups := make(map[string][]int)
+	var signatures []string
+	for i, file := range files {
+		if file.Diff == "" || file.Conflict != nil {
+			continue
+		}
+		signature := editSignature(file.Diff)
+		if signature == "" {
+			continue
+		}
+		if _, ok := groups[signature]; !ok {
+			signatures = append(signatures, signature)
+		}
+		groups[signature] = append(groups[signature], i)
+	}
+
+	copies := make(map[int]int)
+	for _, signature := range signatures {
+		group := groups[signature]
+		if len(group) < minRepeatedChange {
+			continue
+		}
+		for _, i := range group[1:] {
+			copies[i] = group[0]
+		}
+	}
+	return copies
+}
+
+// editSignature describes the edit of a diff independently of where it is made: for each
+// hunk, the words it removes and the words it adds, so that a rename touching different
+// lines of different files gives the same signature. Empty for a diff without hunks.
+func editSignature(diff string) string {
+	var hunks []string
+	var removed, added []string
+	inHunk := false
+	flush := func() {
+		if !inHunk || (len(removed) == 0 && len(added) == 0) {
+			return
+		}
+		hunk := hunkEdit(removed, added)
+		if !slices.Contains(hunks, hunk) {
+			hunks = append(hunks, hunk)
+		}
+		removed, added = nil, nil
+	}
+
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "@@"):
+			flush()
+			inHunk = true
+		case !inHunk:
+			// diff --git, index, --- and +++ headers
+		case strings.HasPrefix(line, "-"):
+			removed = append(removed, line[1:])
+		case strings.HasPrefix(line, "+"):
+			added = append(added, line[1:])
+		}
+	}
+	flush()
+	return strings.Join(hunks, "\x00")
+}
+
+// hunkEdit returns the words removed and added by a hunk, in order; a hunk only moving
+// words or changing whitespace is described by its lines
+func hunkEdit(removed, added []string) string {
+	oldWords := editWords.FindAllString(strings.Join(removed, "\n"), -1)
+	newWords := editWords.FindAllString(strings.Join(added, "\n"), -1)
+	// Drop the words both sides share, keeping what the edit changes in order
+	remaining := make(map[string]int)
+	for _, word := range newWords {
+		remaining[word]++
+	}
+	var gone []string
+	for _, word := range oldWords {
+		if remaining[word] > 0 {
+			remaining[word]--
+			continue
+		}
+		gone = append(gone, word)
+	}
+	kept := make(map[string]int)
+	for _, word := range oldWords {
+		kept[word]++
+	}
+	var come []string
+	for _, word := range newWords {
+		if kept[word] > 0 {
+			kept[word]--
+			continue
+		}
+		come = append(come, word)
+	}
+	if len(gone) == 0 && len(come) == 0 {
+		return "-" + strings.Join(removed, "\n") + "\x01+" + strings.Join(added, "\n")
+	}
+	return "-" + strings.Join(gone, " ") + "\x01+" + strings.Join(come, " ")
+}
@@ -0,0 +1,129 @@
+package prompt
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+	"testing"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/pkg/tokenization"
+)
+
+// fileDiff returns the diff of path with one hunk removing and adding these lines
+func fileDiff(path string, removed, added []string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "diff --git a/%s b/%s\nindex 1111111..2222222 100644\n--- a/%s\n+++ b/%s\n", path, path, path, path)
+	fmt.Fprintf(&sb, "@@ -1,%d +1,%d @@\n", len(removed), len(added))
+	for _, line := range removed {
+		sb.WriteString("-" + line + "\n")
+	}
+	for _, line := range added {
+		sb.WriteString("+" + line + "\n")
+	}
+	return sb.String()
+}
+
+func TestRepeatedChanges(t *testing.T) {
+	header := []string{"// Copyright 2026 The gitcomm Authors", "// SPDX-License-Identifier: MIT"}
+	tests := []struct {
+		name  string
+		files []model.FileChange
+		want  map[int]int
+	}{
+		{
+			name: "license header added to every file",
+			files: []model.FileChange{
+				{Path: "a.go", Diff: fileDiff("a.go", nil, header)},
+				{Path: "b.go", Diff: fileDiff("b.go", nil, header)},
+				{Path: "c.go", Diff: fileDiff("c.go", nil, header)},
+			},
+			want: map[int]int{1: 0, 2: 0},
+		},
+		{
+			name: "rename on different lines of different files",
+			files: []model.FileChange{
+				{Path: "a.go", Diff: fileDiff("a.go", []string{"\tx := api.OldName(ctx)"}, []string{"\tx := api.NewName(ctx)"})},
+				{Path: "notes.md", Diff: fileDiff("notes.md", []string{"# Notes"}, []string{"# Release notes"})},
+				{Path: "b.go", Diff: fileDiff("b.go", []string{"return client.OldName(req, opts)"}, []string{"return client.NewName(req, opts)"})},
+				{Path: "c.go", Diff: fileDiff("c.go", []string{"defer OldName()"}, []string{"defer NewName()"})},
+			},
+			want: map[int]int{2: 0, 3: 0},
+		},
+		{
+			name: "two copies are sent whole",
+			files: []model.FileChange{
+				{Path: "a.go", Diff: fileDiff("a.go", nil, header)},
+				{Path: "b.go", Diff: fileDiff("b.go", nil, header)},
+			},
+			want: map[int]int{},
+		},
+		{
+			name: "different edits",
+			files: []model.FileChange{
+				{Path: "a.go", Diff: fileDiff("a.go", []string{"x := 1"}, []string{"x := 2"})},
+				{Path: "b.go", Diff: fileDiff("b.go", []string{"y := 1"}, []string{"y := 3"})},
+				{Path: "c.go", Diff: fileDiff("c.go", []string{"z := 1"}, []string{"z := 4"})},
+			},
+			want: map[int]int{},
+		},
+		{
+			name: "reordered words are compared by line",
+			files: []model.FileChange{
+				{Path: "a.go", Diff: fileDiff("a.go", []string{"f(a, b)"}, []string{"f(b, a)"})},
+				{Path: "b.go", Diff: fileDiff("b.go", []string{"g(c, d)"}, []string{"g(d, c)"})},
+				{Path: "c.go", Diff: fileDiff("c.go", []string{"h(e, f)"}, []string{"h(f, e)"})},
+			},
+			want: map[int]int{},
+		},
+		{
+			name: "files without hunks",
+			files: []model.FileChange{
+				{Path: "a.bin", Diff: "diff --git a/a.bin b/a.bin\nBinary files differ\n"},
+				{Path: "b.bin", Diff: "diff --git a/b.bin b/b.bin\nBinary files differ\n"},
+				{Path: "c.bin", Diff: "diff --git a/c.bin b/c.bin\nBinary files differ\n"},
+			},
+			want: map[int]int{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := repeatedChanges(tt.files); !maps.Equal(got, tt.want) {
+				t.Errorf("repeatedChanges() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPackStagedFiles_RepeatedChanges(t *testing.T) {
+	header := []string{"// Copyright 2026 The gitcomm Authors"}
+	state := &model.RepositoryState{StagedFiles: []model.FileChange{
+		{Path: "a.go", Status: "modified", LinesAdded: 1, Diff: fileDiff("a.go", nil, header)},
+		{Path: "b.go", Status: "modified", LinesAdded: 1, Diff: fileDiff("b.go", nil, header)},
+		{Path: "c.go", Status: "modified", LinesAdded: 1, Diff: fileDiff("c.go", nil, header)},
+		{Path: "main.go", Status: "modified", LinesAdded: 1, Diff: fileDiff("main.go", nil, []string{"func main() {}"})},
+	}}
+
+	got, _ := packStagedFiles(state, tokenization.NewBudget(0))
+	for _, want := range []string{
+		"+++ b/a.go",
+		"(the same change is applied to 2 other files)",
+		"- b.go (modified)\n(same change as a.go)",
+		"- c.go (modified)\n(same change as a.go)",
+		"+func main() {}",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("packStagedFiles() does not contain %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "+++ b/b.go") || strings.Contains(got, "+++ b/c.go") {
+		t.Errorf("packStagedFiles() repeats the diff of a.go:\n%s", got)
+	}
+
+	// When the first diff is left out, its copies are listed with their line counts
+	got, _ = packStagedFiles(state, &tokenization.Budget{Limit: 1, Used: 1})
+	if strings.Contains(got, "same change") || !strings.Contains(got, "- b.go (modified, +1 -0)") {
+		t.Errorf("packStagedFiles() without budget:\n%s", got)
+	}
+}
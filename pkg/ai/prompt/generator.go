@@ -6,6 +6,7 @@ import (
 
 	"github.com/golgoth31/gitcomm/internal/model"
 	"github.com/golgoth31/gitcomm/pkg/conventional"
+	"github.com/golgoth31/gitcomm/pkg/generated"
 )
 
 // PromptGenerator defines the interface for generating unified AI prompts
@@ -19,26 +20,32 @@ type PromptGenerator interface {
 	GenerateUserMessage(repoState *model.RepositoryState) (string, error)
 }
 
+// userMessageHeader opens the user message
+const userMessageHeader = "Generate a commit message for the following changes:\n\n"
+
 // UnifiedPromptGenerator implements PromptGenerator for unified prompt generation
-type UnifiedPromptGenerator struct{}
+type UnifiedPromptGenerator struct {
+	layout model.PromptLayout
+}
 
-// NewUnifiedPromptGenerator creates a new unified prompt generator
+// NewUnifiedPromptGenerator creates a new unified prompt generator sending every section
+// in the default order, without budget
 func NewUnifiedPromptGenerator() PromptGenerator {
 	return &UnifiedPromptGenerator{}
 }
 
+// NewUnifiedPromptGeneratorWithLayout creates a unified prompt generator packing the
+// sections in the context window as configured by layout
+func NewUnifiedPromptGeneratorWithLayout(layout model.PromptLayout) PromptGenerator {
+	return &UnifiedPromptGenerator{layout: layout}
+}
+
 // GenerateSystemMessage generates the system message with validation rules
 func (g *UnifiedPromptGenerator) GenerateSystemMessage(validator conventional.MessageValidator) (string, error) {
 	if validator == nil {
 		return "", ErrNilValidator
 	}
 
-	// Extract validation rules from validator
-	validTypes := validator.GetValidTypes()
-	subjectMaxLength := validator.GetSubjectMaxLength()
-	bodyMaxLength := validator.GetBodyMaxLength()
-	scopeFormatDesc := validator.GetScopeFormatDescription()
-
 	// Build system message with structured bullet points
 	var sb strings.Builder
 
@@ -48,7 +55,21 @@ func (g *UnifiedPromptGenerator) GenerateSystemMessage(validator conventional.Me
 	sb.WriteString("Exclude the go.sum file if it is present.\n\n")
 	sb.WriteString("Do not use markdown format for the output.\n\n")
 	sb.WriteString("If there are no changes abort.\n\n")
-	sb.WriteString("Format: type(scope): subject\n\nbody\n\nfooter\n\n")
+	header := headerLayout(g.layout)
+	sb.WriteString(fmt.Sprintf("Format: %s\n\nbody\n\nfooter\n\n", header.Template()))
+	writeValidationRules(&sb, validator, header)
+
+	return sb.String(), nil
+}
+
+// writeValidationRules lists the rules of validator, and the ticket rule of header
+func writeValidationRules(sb *strings.Builder, validator conventional.MessageValidator, header *model.HeaderLayout) {
+	// Extract validation rules from validator
+	validTypes := validator.GetValidTypes()
+	subjectMaxLength := validator.GetSubjectMaxLength()
+	bodyMaxLength := validator.GetBodyMaxLength()
+	scopeFormatDesc := validator.GetScopeFormatDescription()
+
 	sb.WriteString("Validation Rules:\n")
 
 	// Type constraint
@@ -63,58 +84,120 @@ func (g *UnifiedPromptGenerator) GenerateSystemMessage(validator conventional.Me
 	// Scope format constraint
 	sb.WriteString(fmt.Sprintf("• Scope must be a valid identifier (%s)\n", scopeFormatDesc))
 
-	return sb.String(), nil
+	// Ticket of a custom header layout
+	if header.HasTicket() {
+		sb.WriteString("• Ticket is the ticket reference of the change (e.g. PROJ-123), written where the format shows TICKET\n")
+	}
 }
 
 // GenerateUserMessage generates the user message with repository state.
 // When RawDiff is available (rtk mode), it is used directly instead of per-file diffs.
+// States reduced by a privacy level get the filenames-only or stats-only variant.
+// Sections that do not fit in the layout budget are dropped, least important first.
 func (g *UnifiedPromptGenerator) GenerateUserMessage(repoState *model.RepositoryState) (string, error) {
 	if repoState == nil {
 		return "", ErrNilRepositoryState
 	}
 
-	var sb strings.Builder
+	message, _ := packSections(g.layout, userMessageHeader, repoState)
+	return message, nil
+}
 
-	sb.WriteString("Generate a commit message for the following changes:\n\n")
+// PlanContext returns how GenerateUserMessage packs repoState in the budget of layout:
+// the estimated tokens of the prompt and the files sent with or without their diff
+func PlanContext(layout model.PromptLayout, repoState *model.RepositoryState) model.ContextPlan {
+	if repoState == nil {
+		return model.ContextPlan{Budget: max(layout.Budget, 0)}
+	}
+	_, plan := packSections(layout, userMessageHeader, repoState)
+	return plan
+}
 
-	// When RawDiff is available (rtk condensed output), use it directly
-	if repoState.RawDiff != "" {
-		sb.WriteString(repoState.RawDiff)
-		if !strings.HasSuffix(repoState.RawDiff, "\n") {
-			sb.WriteString("\n")
+// headerLayout returns the header layout the model writes, nil for type(scope): subject.
+// An invalid format falls back to the default (reported by config validation).
+func headerLayout(layout model.PromptLayout) *model.HeaderLayout {
+	if layout.HeaderFormat == "" {
+		return nil
+	}
+	header, err := model.ParseHeaderLayout(layout.HeaderFormat)
+	if err != nil {
+		return nil
+	}
+	return header
+}
+
+// writeGeneratedNote lists the staged files generated from other staged files
+func writeGeneratedNote(sb *strings.Builder, repoState *model.RepositoryState) {
+	paths := make([]string, 0, len(repoState.StagedFiles))
+	for _, file := range repoState.StagedFiles {
+		if file.Status != "deleted" {
+			paths = append(paths, file.Path)
 		}
-		return sb.String(), nil
+	}
+	pairs := generated.Find(paths)
+	if len(pairs) == 0 {
+		return
 	}
 
-	// Standard mode: build prompt from structured file changes
-	if len(repoState.StagedFiles) > 0 {
-		sb.WriteString("Staged files:\n")
-		for _, file := range repoState.StagedFiles {
-			sb.WriteString(fmt.Sprintf("- %s (%s)\n", file.Path, file.Status))
-			if file.Diff != "" {
-				sb.WriteString(file.Diff)
-				if !strings.HasSuffix(file.Diff, "\n") {
-					sb.WriteString("\n")
-				}
+	sb.WriteString("Generated files (built from staged sources: describe the source changes, not these outputs):\n")
+	for _, pair := range pairs {
+		sb.WriteString(fmt.Sprintf("- %s (from %s)\n", pair.Generated, pair.Source))
+	}
+}
+
+// writeFileList writes one line per file followed by its diff, or by its line counts
+// when the diff is not shared
+func writeFileList(sb *strings.Builder, title string, files []model.FileChange) {
+	sb.WriteString(title)
+	sb.WriteString("\n")
+	for _, file := range files {
+		if file.Diff == "" && file.Conflict != nil {
+			sb.WriteString(fmt.Sprintf("- %s (%s: %s)\n", file.DisplayPath(), file.Status, file.Conflict.Summary()))
+			continue
+		}
+		if file.Diff == "" && (file.LinesAdded > 0 || file.LinesRemoved > 0) {
+			sb.WriteString(fmt.Sprintf("- %s (%s, +%d -%d)\n", file.DisplayPath(), file.StatusDetail(), file.LinesAdded, file.LinesRemoved))
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("- %s (%s)\n", file.DisplayPath(), file.StatusDetail()))
+		if file.Diff != "" {
+			sb.WriteString(file.Diff)
+			if !strings.HasSuffix(file.Diff, "\n") {
+				sb.WriteString("\n")
 			}
 		}
 	}
+}
 
-	if len(repoState.UnstagedFiles) > 0 {
-		if len(repoState.StagedFiles) > 0 {
-			sb.WriteString("\n")
+// formatChangeCounts summarizes the changes by status, for states shared without paths
+func formatChangeCounts(repoState *model.RepositoryState) string {
+	var sb strings.Builder
+	for _, group := range []struct {
+		label string
+		files []model.FileChange
+	}{
+		{"Staged", repoState.StagedFiles},
+		{"Unstaged", repoState.UnstagedFiles},
+	} {
+		if len(group.files) == 0 {
+			continue
 		}
-		sb.WriteString("Unstaged files:\n")
-		for _, file := range repoState.UnstagedFiles {
-			sb.WriteString(fmt.Sprintf("- %s (%s)\n", file.Path, file.Status))
-			if file.Diff != "" {
-				sb.WriteString(file.Diff)
-				if !strings.HasSuffix(file.Diff, "\n") {
-					sb.WriteString("\n")
-				}
+		counts := make(map[string]int)
+		var statuses []string
+		added, removed := 0, 0
+		for _, file := range group.files {
+			if counts[file.Status] == 0 {
+				statuses = append(statuses, file.Status)
 			}
+			counts[file.Status]++
+			added += file.LinesAdded
+			removed += file.LinesRemoved
+		}
+		parts := make([]string, len(statuses))
+		for i, status := range statuses {
+			parts[i] = fmt.Sprintf("%d %s", counts[status], status)
 		}
+		sb.WriteString(fmt.Sprintf("- %s files: %d (%s), +%d -%d lines\n", group.label, len(group.files), strings.Join(parts, ", "), added, removed))
 	}
-
-	return sb.String(), nil
+	return sb.String()
 }
@@ -41,6 +41,28 @@ func TestPromptGenerator_GenerateSystemMessage(t *testing.T) {
 		}
 	})
 
+	t.Run("custom header layout", func(t *testing.T) {
+		generator := NewUnifiedPromptGeneratorWithLayout(model.PromptLayout{HeaderFormat: "[{ticket}] {type}({scope}): {subject}"})
+		systemMsg, err := generator.GenerateSystemMessage(validator)
+		if err != nil {
+			t.Fatalf("GenerateSystemMessage() error = %v, want nil", err)
+		}
+		if !strings.Contains(systemMsg, "Format: [TICKET] type(scope): subject") {
+			t.Errorf("GenerateSystemMessage() should contain the custom header format:\n%s", systemMsg)
+		}
+		if !strings.Contains(systemMsg, "Ticket is the ticket reference") {
+			t.Error("GenerateSystemMessage() should describe the ticket")
+		}
+
+		userMsg, err := generator.GenerateUserMessage(&model.RepositoryState{Branch: "feature/PROJ-123-login", Ticket: "PROJ-123"})
+		if err != nil {
+			t.Fatalf("GenerateUserMessage() error = %v, want nil", err)
+		}
+		if !strings.Contains(userMsg, "Ticket: PROJ-123 (write it in the header)") {
+			t.Errorf("GenerateUserMessage() should ask for the ticket in the header:\n%s", userMsg)
+		}
+	})
+
 	t.Run("nil validator", func(t *testing.T) {
 		systemMsg, err := generator.GenerateSystemMessage(nil)
 		if err == nil {
@@ -99,6 +121,166 @@ func TestPromptGenerator_GenerateUserMessage(t *testing.T) {
 		}
 	})
 
+	t.Run("renamed file", func(t *testing.T) {
+		repoState := &model.RepositoryState{
+			StagedFiles: []model.FileChange{
+				{Path: "pkg/store/cache.go", Status: "renamed", OldPath: "internal/cache.go", Similarity: 92, Diff: "@@ -1 +1 @@\n-package internal\n+package store\n"},
+			},
+		}
+
+		userMsg, err := generator.GenerateUserMessage(repoState)
+		if err != nil {
+			t.Fatalf("GenerateUserMessage() error = %v, want nil", err)
+		}
+		if !strings.Contains(userMsg, "- internal/cache.go → pkg/store/cache.go (renamed, 92% similar)\n@@") {
+			t.Errorf("GenerateUserMessage() should show the rename with its similarity and delta:\n%s", userMsg)
+		}
+	})
+
+	t.Run("stats-only privacy", func(t *testing.T) {
+		full := &model.RepositoryState{
+			StagedFiles: []model.FileChange{
+				{Path: "secret/keys.go", Status: "modified", Diff: "+// gitcomm: mention rotation\n+key := 1\n"},
+				{Path: "secret/vault.go", Status: "added", Diff: "+vault\n"},
+				{Path: "secret/old.go", Status: "modified", Diff: "-old\n"},
+			},
+		}
+
+		userMsg, err := generator.GenerateUserMessage(full.WithPrivacy(model.PrivacyStatsOnly))
+		if err != nil {
+			t.Fatalf("GenerateUserMessage() error = %v, want nil", err)
+		}
+
+		if strings.Contains(userMsg, "secret/") || strings.Contains(userMsg, "key") || strings.Contains(userMsg, "rotation") {
+			t.Errorf("GenerateUserMessage() leaked paths or content:\n%s", userMsg)
+		}
+		if !strings.Contains(userMsg, "- Staged files: 3 (2 modified, 1 added), +3 -1 lines\n") {
+			t.Errorf("GenerateUserMessage() should contain the change counts, got:\n%s", userMsg)
+		}
+	})
+
+	t.Run("filenames-only privacy", func(t *testing.T) {
+		full := &model.RepositoryState{
+			StagedFiles: []model.FileChange{
+				{Path: "internal/auth/login.go", Status: "modified", Diff: "--- a/internal/auth/login.go\n+++ b/internal/auth/login.go\n+token := secret\n-old := 1\n+new := 2\n"},
+				{Path: "docs/login.md", Status: "added"},
+				{Path: "go.mod", Status: "unmerged", Conflict: &model.Conflict{Kind: "both modified", Hunks: []model.ConflictHunk{
+					{Ours: []string{"require secret v1"}, OursLines: 1, Theirs: []string{"require secret v2"}, TheirsLines: 1},
+				}}},
+			},
+		}
+
+		userMsg, err := generator.GenerateUserMessage(full.WithPrivacy(model.PrivacyFilenamesOnly))
+		if err != nil {
+			t.Fatalf("GenerateUserMessage() error = %v, want nil", err)
+		}
+
+		if strings.Contains(userMsg, "token") || strings.Contains(userMsg, "secret") {
+			t.Errorf("GenerateUserMessage() leaked content:\n%s", userMsg)
+		}
+		for _, want := range []string{"File contents are private", "- internal/auth/login.go (modified, +2 -1)\n", "- docs/login.md (added)\n", "- go.mod (unmerged: both modified, 1 conflicting hunk)\n"} {
+			if !strings.Contains(userMsg, want) {
+				t.Errorf("GenerateUserMessage() should contain %q, got:\n%s", want, userMsg)
+			}
+		}
+	})
+
+	t.Run("branch context", func(t *testing.T) {
+		repoState := &model.RepositoryState{
+			StagedFiles:       []model.FileChange{{Path: "README.md", Status: "modified"}},
+			Branch:            "feature/pager",
+			LastCommitSubject: "feat(ui): add pager",
+		}
+
+		userMsg, err := generator.GenerateUserMessage(repoState)
+		if err != nil {
+			t.Fatalf("GenerateUserMessage() error = %v, want nil", err)
+		}
+
+		if !strings.Contains(userMsg, "Branch: feature/pager\n") {
+			t.Error("GenerateUserMessage() should contain the branch name")
+		}
+		if !strings.Contains(userMsg, "Previous commit: feat(ui): add pager\n") {
+			t.Error("GenerateUserMessage() should contain the previous commit subject")
+		}
+	})
+
+	t.Run("branch ticket", func(t *testing.T) {
+		repoState := &model.RepositoryState{
+			StagedFiles: []model.FileChange{{Path: "login.go", Status: "added"}},
+			Branch:      "feature/JIRA-1234-add-login",
+			Ticket:      "JIRA-1234",
+		}
+
+		userMsg, err := generator.GenerateUserMessage(repoState)
+		if err != nil {
+			t.Fatalf("GenerateUserMessage() error = %v, want nil", err)
+		}
+
+		if !strings.Contains(userMsg, "Ticket: JIRA-1234 (a \"Refs: JIRA-1234\" footer is added automatically") {
+			t.Errorf("GenerateUserMessage() should contain the ticket, got:\n%s", userMsg)
+		}
+	})
+
+	t.Run("session context", func(t *testing.T) {
+		repoState := &model.RepositoryState{
+			StagedFiles: []model.FileChange{{Path: "internal/ui/pager.go", Status: "modified"}},
+			RelatedCommit: &model.CommitInfo{
+				Hash:    "abc1234def5678abc1234def5678abc1234def56",
+				Message: "refactor(ui): extract pager\n\nBody",
+			},
+		}
+
+		userMsg, err := generator.GenerateUserMessage(repoState)
+		if err != nil {
+			t.Fatalf("GenerateUserMessage() error = %v, want nil", err)
+		}
+
+		if !strings.Contains(userMsg, "Previous commit by the same author on these files: abc1234 refactor(ui): extract pager\n") {
+			t.Error("GenerateUserMessage() should contain the related commit")
+		}
+		if !strings.Contains(userMsg, "started in abc1234") {
+			t.Error("GenerateUserMessage() should suggest referencing the related commit")
+		}
+	})
+
+	t.Run("go api summary", func(t *testing.T) {
+		repoState := &model.RepositoryState{
+			StagedFiles: []model.FileChange{{
+				Path:   "pkg/client/client.go",
+				Status: "modified",
+				Diff:   "@@ -12,0 +13 @@ type Client struct {\n+\tRetry int\n",
+			}},
+		}
+
+		userMsg, err := generator.GenerateUserMessage(repoState)
+		if err != nil {
+			t.Fatalf("GenerateUserMessage() error = %v, want nil", err)
+		}
+
+		if !strings.Contains(userMsg, "Exported Go API changes:\n- pkg/client: added Client.Retry\n") {
+			t.Errorf("GenerateUserMessage() should contain the Go API summary, got:\n%s", userMsg)
+		}
+	})
+
+	t.Run("generated files", func(t *testing.T) {
+		repoState := &model.RepositoryState{
+			StagedFiles: []model.FileChange{
+				{Path: "api/user.proto", Status: "modified", Diff: "+string email = 2;\n"},
+				{Path: "api/user.pb.go", Status: "modified", Diff: "+Email string\n"},
+			},
+		}
+
+		userMsg, err := generator.GenerateUserMessage(repoState)
+		if err != nil {
+			t.Fatalf("GenerateUserMessage() error = %v, want nil", err)
+		}
+
+		if !strings.Contains(userMsg, "describe the source changes, not these outputs):\n- api/user.pb.go (from api/user.proto)\n") {
+			t.Errorf("GenerateUserMessage() should note the generated file, got:\n%s", userMsg)
+		}
+	})
+
 	t.Run("nil repository state", func(t *testing.T) {
 		userMsg, err := generator.GenerateUserMessage(nil)
 		if err == nil {
@@ -0,0 +1,85 @@
+package prompt
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+)
+
+// hintPattern matches a "gitcomm:" magic comment in the common comment syntaxes
+// (//, #, --, ;, /* */, <!-- -->) and captures the hint text
+var hintPattern = regexp.MustCompile(`(?://|#|--|;|/\*|<!--)\s*gitcomm:\s*(.*?)\s*(?:\*/|-->)?\s*$`)
+
+// Hint is an explicit instruction for the commit message, written by the developer
+// as a "gitcomm:" comment in an added line
+type Hint struct {
+	// Path is the file containing the comment ("" if unknown)
+	Path string
+	// Text is the instruction (e.g. "mention rate limiting fix")
+	Text string
+}
+
+// ExtractHints returns the hints found in the added lines of the staged changes, in file order.
+// Comments are only read: they stay in the code and in the diff.
+func ExtractHints(repoState *model.RepositoryState) []Hint {
+	if repoState == nil {
+		return nil
+	}
+	if repoState.RawDiff != "" {
+		return hintsFromDiff("", repoState.RawDiff)
+	}
+
+	var hints []Hint
+	for _, file := range repoState.StagedFiles {
+		hints = append(hints, hintsFromDiff(file.Path, file.Diff)...)
+	}
+	return hints
+}
+
+// hintsFromDiff scans the added lines of a unified diff. path is used for every hint
+// unless the diff has "+++ b/<path>" headers (multi-file diffs).
+func hintsFromDiff(path, diff string) []Hint {
+	var hints []Hint
+	seen := make(map[Hint]bool)
+	for _, line := range strings.Split(diff, "\n") {
+		if name, ok := strings.CutPrefix(line, "+++ "); ok {
+			if name != "/dev/null" {
+				path = strings.TrimPrefix(name, "b/")
+			}
+			continue
+		}
+		added, ok := strings.CutPrefix(line, "+")
+		if !ok {
+			continue
+		}
+		match := hintPattern.FindStringSubmatch(added)
+		if match == nil || match[1] == "" {
+			continue
+		}
+		hint := Hint{Path: path, Text: match[1]}
+		if !seen[hint] {
+			seen[hint] = true
+			hints = append(hints, hint)
+		}
+	}
+	return hints
+}
+
+// formatHints renders hints as a prompt section, or "" when there are none
+func formatHints(hints []Hint) string {
+	if len(hints) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("Developer hints (from gitcomm: comments in the changes), follow them in the message:\n")
+	for _, hint := range hints {
+		if hint.Path != "" {
+			sb.WriteString(fmt.Sprintf("- %s: %s\n", hint.Path, hint.Text))
+		} else {
+			sb.WriteString(fmt.Sprintf("- %s\n", hint.Text))
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,84 @@
+package prompt
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+)
+
+func TestExtractHints(t *testing.T) {
+	tests := []struct {
+		name  string
+		state *model.RepositoryState
+		want  []Hint
+	}{
+		{
+			name: "comment syntaxes in added lines",
+			state: &model.RepositoryState{StagedFiles: []model.FileChange{
+				{Path: "client.go", Diff: "@@ -1 +1,2 @@\n+\t// gitcomm: mention rate limiting fix\n+\tlimiter.Wait(ctx)\n"},
+				{Path: "deploy.sh", Diff: "@@ -3 +3 @@\n+# gitcomm: note the new default region\n"},
+				{Path: "style.css", Diff: "@@ -1 +1 @@\n+/* gitcomm: dark mode only */\n"},
+				{Path: "index.html", Diff: "@@ -1 +1 @@\n+<!-- gitcomm: accessibility fix -->\n"},
+				{Path: "schema.sql", Diff: "@@ -1 +1 @@\n+-- gitcomm: breaking change for reporting jobs\n"},
+			}},
+			want: []Hint{
+				{Path: "client.go", Text: "mention rate limiting fix"},
+				{Path: "deploy.sh", Text: "note the new default region"},
+				{Path: "style.css", Text: "dark mode only"},
+				{Path: "index.html", Text: "accessibility fix"},
+				{Path: "schema.sql", Text: "breaking change for reporting jobs"},
+			},
+		},
+		{
+			name: "removed and context lines are ignored",
+			state: &model.RepositoryState{StagedFiles: []model.FileChange{
+				{Path: "a.go", Diff: "@@ -1,2 +1 @@\n-// gitcomm: old hint\n // gitcomm: unchanged hint\n+x := 1 // not a hint\n"},
+			}},
+		},
+		{
+			name: "empty hint and duplicates",
+			state: &model.RepositoryState{StagedFiles: []model.FileChange{
+				{Path: "a.go", Diff: "+// gitcomm:\n+// gitcomm: explain retry\n+// gitcomm: explain retry\n"},
+			}},
+			want: []Hint{{Path: "a.go", Text: "explain retry"}},
+		},
+		{
+			name: "raw diff with file headers",
+			state: &model.RepositoryState{RawDiff: "diff --git a/a.py b/a.py\n--- a/a.py\n+++ b/a.py\n@@ -1 +1 @@\n+# gitcomm: python hint\n" +
+				"diff --git a/b.go b/b.go\n--- /dev/null\n+++ b/b.go\n@@ -0,0 +1 @@\n+// gitcomm: go hint\n"},
+			want: []Hint{{Path: "a.py", Text: "python hint"}, {Path: "b.go", Text: "go hint"}},
+		},
+		{
+			name:  "nil state",
+			state: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExtractHints(tt.state); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExtractHints() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateUserMessage_IncludesHints(t *testing.T) {
+	state := &model.RepositoryState{StagedFiles: []model.FileChange{
+		{Path: "client.go", Status: "modified", Diff: "@@ -1 +1 @@\n+// gitcomm: mention rate limiting fix\n"},
+	}}
+
+	userMsg, err := NewUnifiedPromptGenerator().GenerateUserMessage(state)
+	if err != nil {
+		t.Fatalf("GenerateUserMessage() error = %v", err)
+	}
+	if !strings.Contains(userMsg, "Developer hints (from gitcomm: comments in the changes), follow them in the message:\n- client.go: mention rate limiting fix\n") {
+		t.Errorf("GenerateUserMessage() should contain the hints, got:\n%s", userMsg)
+	}
+	// The comment stays in the diff
+	if !strings.Contains(userMsg, "+// gitcomm: mention rate limiting fix\n") {
+		t.Error("GenerateUserMessage() should keep the comment in the diff")
+	}
+}
@@ -0,0 +1,276 @@
+package prompt
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/pkg/goapi"
+	"github.com/golgoth31/gitcomm/pkg/tokenization"
+)
+
+// Prompt sections, as named in the ai.context configuration
+const (
+	SectionBranch        = "branch"
+	SectionRecentCommits = "recent_commits"
+	SectionGenerated     = "generated"
+	SectionHints         = "hints"
+	SectionGoAPI         = "go_api"
+	SectionStagedDiffs   = "staged_diffs"
+	SectionUnstagedFiles = "unstaged_files"
+)
+
+// Sections lists every prompt section in the default order
+var Sections = []string{
+	SectionBranch,
+	SectionRecentCommits,
+	SectionGenerated,
+	SectionHints,
+	SectionGoAPI,
+	SectionStagedDiffs,
+	SectionUnstagedFiles,
+}
+
+// DefaultPriority keeps the diffs and the developer hints first, the unstaged files are
+// the first to go when the budget is tight
+var DefaultPriority = []string{
+	SectionStagedDiffs,
+	SectionHints,
+	SectionGenerated,
+	SectionBranch,
+	SectionGoAPI,
+	SectionRecentCommits,
+	SectionUnstagedFiles,
+}
+
+// ValidateSections returns an error when names holds an unknown or repeated section
+func ValidateSections(names []string) error {
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		if !slices.Contains(Sections, name) {
+			return fmt.Errorf("unknown prompt section %q (expected one of %s)", name, strings.Join(Sections, ", "))
+		}
+		if seen[name] {
+			return fmt.Errorf("prompt section %q is listed twice", name)
+		}
+		seen[name] = true
+	}
+	return nil
+}
+
+// packSections selects the sections fitting in the budget by priority and returns them
+// in the layout order, with the plan of the staged files. The staged files are always
+// listed: when their diffs do not all fit, the lowest ranked ones (see rankFiles) are
+// replaced by their line counts.
+func packSections(layout model.PromptLayout, header string, repoState *model.RepositoryState) (string, model.ContextPlan) {
+	order := layout.Order
+	if len(order) == 0 {
+		order = Sections
+	}
+	priority := layout.Priority
+	if len(priority) == 0 {
+		priority = DefaultPriority
+	}
+	// Sections missing from the priority list are the least important, in layout order
+	for _, name := range order {
+		if !slices.Contains(priority, name) {
+			priority = append(slices.Clone(priority), name)
+		}
+	}
+
+	budget := tokenization.NewBudgetWith(tokenization.ForTokenizer(layout.Tokenizer), layout.Budget)
+	budget.Spend(budget.Count(header))
+
+	var plan model.ContextPlan
+	kept := make(map[string]string, len(order))
+	for _, name := range priority {
+		if !slices.Contains(order, name) {
+			continue
+		}
+		var text string
+		if name == SectionStagedDiffs {
+			text, plan = packStagedFiles(repoState, budget)
+		} else {
+			text = renderSection(layout, name, repoState)
+			if text == "" || !budget.Fits(budget.Count(text)) {
+				continue
+			}
+		}
+		kept[name] = text
+		budget.Spend(budget.Count(text))
+	}
+
+	var sb strings.Builder
+	sb.WriteString(header)
+	first := true
+	for _, name := range order {
+		if kept[name] == "" {
+			continue
+		}
+		if !first {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(kept[name])
+		first = false
+	}
+	plan.Tokens = budget.Used
+	plan.Budget = budget.Limit
+	plan.Exact = budget.Exact()
+	return sb.String(), plan
+}
+
+// renderSection renders a section other than the staged files, or "" when it is empty
+// or not shared at the privacy level of the state
+func renderSection(layout model.PromptLayout, name string, repoState *model.RepositoryState) string {
+	var sb strings.Builder
+	switch name {
+	case SectionBranch:
+		if repoState.Branch != "" {
+			sb.WriteString(fmt.Sprintf("Branch: %s\n", repoState.Branch))
+		}
+		if repoState.Ticket != "" && headerLayout(layout).HasTicket() {
+			sb.WriteString(fmt.Sprintf("Ticket: %s (write it in the header)\n", repoState.Ticket))
+		} else if repoState.Ticket != "" {
+			sb.WriteString(fmt.Sprintf("Ticket: %s (a \"Refs: %s\" footer is added automatically, do not write it)\n", repoState.Ticket, repoState.Ticket))
+		}
+	case SectionRecentCommits:
+		if repoState.LastCommitSubject != "" {
+			sb.WriteString(fmt.Sprintf("Previous commit: %s\n", repoState.LastCommitSubject))
+		}
+		if repoState.RelatedCommit != nil {
+			sb.WriteString(fmt.Sprintf("Previous commit by the same author on these files: %s %s\n",
+				repoState.RelatedCommit.ShortHash(), repoState.RelatedCommit.Subject()))
+			sb.WriteString(fmt.Sprintf("If this change continues that work, say so in the body (e.g. \"continues refactor started in %s\").\n",
+				repoState.RelatedCommit.ShortHash()))
+		}
+	case SectionGenerated:
+		// Generated files repeat their sources: the message should describe the sources
+		if repoState.Privacy != model.PrivacyStatsOnly {
+			writeGeneratedNote(&sb, repoState)
+		}
+	case SectionHints:
+		// Explicit instructions left by the developer as "gitcomm:" comments
+		if sharesDiffs(repoState) {
+			sb.WriteString(formatHints(ExtractHints(repoState)))
+		}
+	case SectionGoAPI:
+		// For Go code, a summary of exported API changes helps write precise subjects
+		if sharesDiffs(repoState) && repoState.RawDiff == "" {
+			sb.WriteString(goapi.Summary(goapi.Analyze(repoState.StagedFiles)))
+		}
+	case SectionUnstagedFiles:
+		// The rtk condensed output only covers the staged changes
+		if repoState.Privacy != model.PrivacyStatsOnly && repoState.RawDiff == "" && len(repoState.UnstagedFiles) > 0 {
+			writeFileList(&sb, "Unstaged files:", repoState.UnstagedFiles)
+		}
+	}
+	return sb.String()
+}
+
+// sharesDiffs reports whether the privacy level of the state allows sharing the diffs
+func sharesDiffs(repoState *model.RepositoryState) bool {
+	return repoState.Privacy == "" || repoState.Privacy == model.PrivacyFullDiff
+}
+
+// packStagedFiles renders the staged changes with as many diffs as fit in what is left of
+// budget, without spending it: the files are always listed in their order, and the diffs
+// are picked by rank (see rankFiles). Files whose diff does not fit are listed with their
+// line counts only, and files making the same edit as an earlier one (repeatedChanges)
+// point to its diff.
+func packStagedFiles(repoState *model.RepositoryState, budget *tokenization.Budget) (string, model.ContextPlan) {
+	var sb strings.Builder
+	var plan model.ContextPlan
+	switch repoState.Privacy {
+	case model.PrivacyStatsOnly:
+		sb.WriteString("File names and contents are private: only change counts are available.\n")
+		sb.WriteString("Write a short, general message (e.g. \"chore: update sources\") and do not invent details.\n\n")
+		sb.WriteString(formatChangeCounts(repoState))
+		return sb.String(), plan
+	case model.PrivacyFilenamesOnly:
+		sb.WriteString("File contents are private: only paths, statuses and changed line counts are available.\n")
+		sb.WriteString("Infer the purpose of the change from the file names and keep the message general rather than inventing details.\n")
+		if len(repoState.StagedFiles) > 0 {
+			sb.WriteString("\n")
+			writeFileList(&sb, "Staged files:", repoState.StagedFiles)
+		}
+		return sb.String(), plan
+	}
+
+	// Without diffs, the files are listed with their line counts
+	files := repoState.WithPrivacy(model.PrivacyFilenamesOnly).StagedFiles
+	render := func(omitted bool) string {
+		var sb strings.Builder
+		if omitted {
+			sb.WriteString("Some diffs were left out to fit the context: infer the rest of the change from the file names and line counts.\n\n")
+		}
+		if len(files) > 0 {
+			writeFileList(&sb, "Staged files:", files)
+		}
+		return sb.String()
+	}
+
+	// When RawDiff is available (rtk condensed output), it is sent whole or not at all
+	if repoState.RawDiff != "" {
+		sb.WriteString(repoState.RawDiff)
+		if !strings.HasSuffix(repoState.RawDiff, "\n") {
+			sb.WriteString("\n")
+		}
+		if budget.Fits(budget.Count(sb.String())) {
+			return sb.String(), plan
+		}
+		return render(true), plan
+	}
+
+	// Otherwise the diffs are added by rank while they fit; a file repeating the edit of an
+	// earlier one refers to it instead of sending the same hunks again
+	copies := repeatedChanges(repoState.StagedFiles)
+	repeats := make(map[int]int)
+	for _, first := range copies {
+		repeats[first]++
+	}
+	diffs := make([]string, len(files))
+	costs := make([]int, len(files))
+	for i, file := range repoState.StagedFiles {
+		diffs[i] = file.Diff
+		if n := repeats[i]; n > 0 {
+			diffs[i] = strings.TrimRight(file.Diff, "\n") + fmt.Sprintf("\n(the same change is applied to %d other files)\n", n)
+		}
+		costs[i] = budget.Count(diffs[i])
+	}
+
+	order, kinds := rankFiles(repoState.StagedFiles, costs)
+	tokens := budget.Count(render(true))
+	omitted := false
+	include := func(i int) {
+		if !budget.Fits(tokens + costs[i]) {
+			omitted = true
+			plan.Summarized = append(plan.Summarized, model.SummarizedFile{Path: files[i].Path, Kind: kinds[i], Tokens: costs[i]})
+			return
+		}
+		files[i].Diff = diffs[i]
+		tokens += costs[i]
+		plan.Included = append(plan.Included, files[i].Path)
+	}
+	for _, i := range order {
+		if _, ok := copies[i]; !ok && diffs[i] != "" {
+			include(i)
+		}
+	}
+	// Copies go last, as they only make sense when the diff they refer to is sent
+	for _, i := range order {
+		first, ok := copies[i]
+		if !ok || diffs[i] == "" {
+			continue
+		}
+		if files[first].Diff == "" {
+			omitted = true
+			plan.Summarized = append(plan.Summarized, model.SummarizedFile{Path: files[i].Path, Kind: kinds[i], Tokens: costs[i]})
+			continue
+		}
+		diffs[i] = fmt.Sprintf("(same change as %s)\n", files[first].Path)
+		costs[i] = budget.Count(diffs[i])
+		include(i)
+	}
+	return render(omitted), plan
+}
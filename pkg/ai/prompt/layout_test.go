@@ -0,0 +1,109 @@
+package prompt
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+)
+
+func TestGenerateUserMessage_Layout(t *testing.T) {
+	bigDiff := "+" + strings.Repeat("x", 4000) + "\n"
+	state := &model.RepositoryState{
+		Branch:            "feature/pack",
+		LastCommitSubject: "feat: previous work",
+		StagedFiles: []model.FileChange{
+			{Path: "small.go", Status: "modified", Diff: "+small change\n"},
+			{Path: "big.go", Status: "modified", Diff: bigDiff},
+		},
+		UnstagedFiles: []model.FileChange{{Path: "notes.txt", Status: "modified"}},
+	}
+
+	tests := []struct {
+		name      string
+		layout    model.PromptLayout
+		contains  []string
+		excludes  []string
+		wantOrder []string
+	}{
+		{
+			name:      "unlimited budget keeps everything in the default order",
+			contains:  []string{"Branch: feature/pack", "Previous commit: feat: previous work", bigDiff, "Unstaged files:"},
+			excludes:  []string{"left out"},
+			wantOrder: []string{"Branch:", "Previous commit:", "Staged files:", "Unstaged files:"},
+		},
+		{
+			name:      "custom order",
+			layout:    model.PromptLayout{Order: []string{SectionStagedDiffs, SectionBranch}},
+			contains:  []string{"Branch: feature/pack", bigDiff},
+			excludes:  []string{"Previous commit:", "Unstaged files:"},
+			wantOrder: []string{"Staged files:", "Branch:"},
+		},
+		{
+			name:     "tight budget drops the least important sections and large diffs",
+			layout:   model.PromptLayout{Budget: 70},
+			contains: []string{"Branch: feature/pack", "+small change", "- big.go (modified, +1 -0)", "left out"},
+			excludes: []string{bigDiff, "Unstaged files:", "Previous commit:"},
+		},
+		{
+			name:     "priority keeps the recent commits over the branch",
+			layout:   model.PromptLayout{Budget: 72, Priority: []string{SectionStagedDiffs, SectionRecentCommits}},
+			contains: []string{"Previous commit: feat: previous work", "- big.go (modified, +1 -0)"},
+			excludes: []string{"Branch:", bigDiff},
+		},
+		{
+			name:     "staged files are listed even over the budget",
+			layout:   model.PromptLayout{Budget: 1},
+			contains: []string{"- small.go (modified, +1 -0)", "- big.go (modified, +1 -0)"},
+			excludes: []string{"Branch:", "+small change"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userMsg, err := NewUnifiedPromptGeneratorWithLayout(tt.layout).GenerateUserMessage(state)
+			if err != nil {
+				t.Fatalf("GenerateUserMessage() error = %v", err)
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(userMsg, want) {
+					t.Errorf("GenerateUserMessage() missing %q in:\n%s", want, userMsg)
+				}
+			}
+			for _, unwanted := range tt.excludes {
+				if strings.Contains(userMsg, unwanted) {
+					t.Errorf("GenerateUserMessage() contains %q in:\n%s", unwanted, userMsg)
+				}
+			}
+			last := -1
+			for _, want := range tt.wantOrder {
+				index := strings.Index(userMsg, want)
+				if index < last {
+					t.Errorf("GenerateUserMessage() has %q out of order in:\n%s", want, userMsg)
+				}
+				last = index
+			}
+		})
+	}
+}
+
+func TestValidateSections(t *testing.T) {
+	tests := []struct {
+		name    string
+		names   []string
+		wantErr bool
+	}{
+		{"empty", nil, false},
+		{"all sections", Sections, false},
+		{"unknown section", []string{"staged_diffs", "readme"}, true},
+		{"repeated section", []string{"branch", "branch"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateSections(tt.names); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateSections() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package prompt
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+)
+
+// MaxQualityScore is the best score of a quality report
+const MaxQualityScore = 10
+
+// ErrInvalidQualityReport is returned when a quality answer does not follow the requested format
+var ErrInvalidQualityReport = errors.New("invalid quality report")
+
+// QualityReport is the model's assessment of an existing commit message against its diff
+type QualityReport struct {
+	// Score rates the message from 0 to MaxQualityScore
+	Score int
+	// Describes tells whether the message describes the change: yes, partially or no
+	Describes string
+	// Missing lists important changed files the message does not account for
+	Missing []string
+	// Notes is a short justification
+	Notes string
+}
+
+// QualitySystemMessage returns the system message asking the model to grade a commit message
+func QualitySystemMessage() string {
+	var sb strings.Builder
+	sb.WriteString("You review git commit messages. You receive a commit message and the diff of the commit.\n\n")
+	sb.WriteString("Judge whether the message accurately describes the change and explains why it was made, ")
+	sb.WriteString("and list the important changed files it does not account for (ignore lock files such as go.sum).\n\n")
+	sb.WriteString("Answer with exactly these four lines and nothing else, without markdown:\n")
+	sb.WriteString(fmt.Sprintf("SCORE: <integer from 0 to %d>\n", MaxQualityScore))
+	sb.WriteString("DESCRIBES: <yes|partially|no>\n")
+	sb.WriteString("MISSING: <comma-separated file paths, or none>\n")
+	sb.WriteString("NOTES: <one sentence>\n")
+	return sb.String()
+}
+
+// QualityUserMessage returns the user message with the commit message and the files it changed
+func QualityUserMessage(message string, repoState *model.RepositoryState) (string, error) {
+	if repoState == nil {
+		return "", ErrNilRepositoryState
+	}
+
+	var sb strings.Builder
+	sb.WriteString("Commit message:\n")
+	sb.WriteString(strings.TrimSpace(message))
+	sb.WriteString("\n\n")
+	if repoState.Privacy == model.PrivacyStatsOnly {
+		sb.WriteString(formatChangeCounts(repoState))
+		return sb.String(), nil
+	}
+	writeFileList(&sb, "Changed files:", repoState.StagedFiles)
+	return sb.String(), nil
+}
+
+// ParseQualityReport parses the answer to a quality prompt.
+// Field names are case-insensitive and lines around them are ignored; SCORE is required.
+func ParseQualityReport(answer string) (*QualityReport, error) {
+	report := &QualityReport{}
+	hasScore := false
+
+	for _, line := range strings.Split(answer, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+
+		switch strings.ToUpper(strings.Trim(strings.TrimSpace(key), "*-# ")) {
+		case "SCORE":
+			// Accept "7" as well as "7/10"
+			value, _, _ = strings.Cut(value, "/")
+			score, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil || score < 0 || score > MaxQualityScore {
+				return nil, fmt.Errorf("%w: score %q", ErrInvalidQualityReport, value)
+			}
+			report.Score = score
+			hasScore = true
+		case "DESCRIBES":
+			report.Describes = strings.ToLower(value)
+		case "MISSING":
+			report.Missing = parseMissing(value)
+		case "NOTES":
+			report.Notes = value
+		}
+	}
+
+	if !hasScore {
+		return nil, fmt.Errorf("%w: missing score", ErrInvalidQualityReport)
+	}
+	return report, nil
+}
+
+// parseMissing splits the MISSING field into file paths; "none" yields nil
+func parseMissing(value string) []string {
+	if strings.EqualFold(value, "none") || value == "" {
+		return nil
+	}
+	var paths []string
+	for _, path := range strings.Split(value, ",") {
+		path = strings.Trim(strings.TrimSpace(path), "`")
+		if path != "" && !strings.EqualFold(path, "none") {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
@@ -0,0 +1,85 @@
+package prompt
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+)
+
+func TestParseQualityReport(t *testing.T) {
+	tests := []struct {
+		name    string
+		answer  string
+		want    *QualityReport
+		wantErr bool
+	}{
+		{
+			name:   "complete answer",
+			answer: "SCORE: 7\nDESCRIBES: partially\nMISSING: internal/cmd/root.go, README.md\nNOTES: The flag is not mentioned.",
+			want: &QualityReport{
+				Score:     7,
+				Describes: "partially",
+				Missing:   []string{"internal/cmd/root.go", "README.md"},
+				Notes:     "The flag is not mentioned.",
+			},
+		},
+		{
+			name:   "nothing missing, score out of ten and markdown noise",
+			answer: "Here is my review:\n**SCORE**: 9/10\nDescribes: Yes\nMISSING: none\nNOTES: Accurate.",
+			want:   &QualityReport{Score: 9, Describes: "yes", Notes: "Accurate."},
+		},
+		{
+			name:    "missing score",
+			answer:  "DESCRIBES: yes\nNOTES: fine",
+			wantErr: true,
+		},
+		{
+			name:    "score out of range",
+			answer:  "SCORE: 12\nDESCRIBES: yes",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseQualityReport(tt.answer)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidQualityReport) {
+					t.Fatalf("ParseQualityReport() error = %v, want ErrInvalidQualityReport", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseQualityReport() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseQualityReport() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQualityUserMessage(t *testing.T) {
+	state := &model.RepositoryState{
+		StagedFiles: []model.FileChange{
+			{Path: "main.go", Status: "modified", Diff: "+func main() {}"},
+		},
+	}
+
+	msg, err := QualityUserMessage("feat: add entry point\n\nBody.\n", state)
+	if err != nil {
+		t.Fatalf("QualityUserMessage() error = %v", err)
+	}
+	for _, want := range []string{"Commit message:\nfeat: add entry point\n\nBody.\n", "- main.go (modified)\n+func main() {}\n"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("QualityUserMessage() = %q, want it to contain %q", msg, want)
+		}
+	}
+
+	if _, err := QualityUserMessage("feat: x", nil); !errors.Is(err, ErrNilRepositoryState) {
+		t.Errorf("QualityUserMessage(nil) error = %v, want ErrNilRepositoryState", err)
+	}
+}
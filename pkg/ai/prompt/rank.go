@@ -0,0 +1,123 @@
+package prompt
+
+import (
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/pkg/generated"
+)
+
+// Kinds of the files whose diffs are packed after the source files
+const (
+	KindTest      = "test"
+	KindDocs      = "docs"
+	KindGenerated = "generated"
+	KindVendored  = "vendored"
+	KindMinified  = "minified"
+	KindLockFile  = "lock file"
+)
+
+// kindRanks orders the kinds: source files (no kind) first, lock files last, as their
+// diffs rarely explain a change
+var kindRanks = map[string]int{
+	"":            0,
+	KindTest:      1,
+	KindDocs:      1,
+	KindGenerated: 2,
+	KindVendored:  2,
+	KindMinified:  2,
+	KindLockFile:  3,
+}
+
+// lockFiles are the dependency lock files of the common package managers
+var lockFiles = map[string]bool{
+	"go.sum":              true,
+	"go.work.sum":         true,
+	"package-lock.json":   true,
+	"npm-shrinkwrap.json": true,
+	"yarn.lock":           true,
+	"pnpm-lock.yaml":      true,
+	"bun.lockb":           true,
+	"Cargo.lock":          true,
+	"Gemfile.lock":        true,
+	"composer.lock":       true,
+	"poetry.lock":         true,
+	"Pipfile.lock":        true,
+	"uv.lock":             true,
+	"flake.lock":          true,
+	"mix.lock":            true,
+	"pubspec.lock":        true,
+	"Podfile.lock":        true,
+	"packages.lock.json":  true,
+	"gradle.lockfile":     true,
+}
+
+// vendorDirs hold third-party code copied into the repository
+var vendorDirs = []string{"vendor", "node_modules", "third_party", "bower_components"}
+
+// fileKind returns the kind of a staged file from its path, "" for a source file.
+// generatedFiles holds the paths generated from other staged files.
+func fileKind(p string, generatedFiles map[string]bool) string {
+	name := path.Base(p)
+	switch {
+	case lockFiles[name]:
+		return KindLockFile
+	case generatedFiles[p]:
+		return KindGenerated
+	case strings.Contains(name, ".min."):
+		return KindMinified
+	}
+	dirs := strings.Split(path.Dir(p), "/")
+	for _, dir := range dirs {
+		if slices.Contains(vendorDirs, dir) {
+			return KindVendored
+		}
+	}
+	for _, dir := range dirs {
+		switch dir {
+		case "test", "tests", "testdata", "__tests__", "spec":
+			return KindTest
+		case "doc", "docs":
+			return KindDocs
+		}
+	}
+	switch {
+	case strings.HasSuffix(name, "_test.go"), strings.Contains(name, ".test."), strings.Contains(name, ".spec."):
+		return KindTest
+	case slices.Contains([]string{".md", ".rst", ".adoc"}, path.Ext(name)):
+		return KindDocs
+	}
+	return ""
+}
+
+// rankFiles returns the indexes of files in the order their diffs are packed: by kind
+// (source files first, lock files last), then from the smallest diff up so that as many
+// files as possible keep their diff. costs holds the tokens of each diff.
+func rankFiles(files []model.FileChange, costs []int) (order []int, kinds []string) {
+	paths := make([]string, 0, len(files))
+	for _, file := range files {
+		if file.Status != "deleted" {
+			paths = append(paths, file.Path)
+		}
+	}
+	generatedFiles := make(map[string]bool)
+	for _, pair := range generated.Find(paths) {
+		generatedFiles[pair.Generated] = true
+	}
+
+	kinds = make([]string, len(files))
+	order = make([]int, len(files))
+	for i, file := range files {
+		kinds[i] = fileKind(file.Path, generatedFiles)
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		if rank := kindRanks[kinds[a]] - kindRanks[kinds[b]]; rank != 0 {
+			return rank
+		}
+		return costs[a] - costs[b]
+	})
+	return order, kinds
+}
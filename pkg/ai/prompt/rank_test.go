@@ -0,0 +1,128 @@
+package prompt
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+)
+
+func TestFileKind(t *testing.T) {
+	generatedFiles := map[string]bool{"api/user.pb.go": true}
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "internal/service/commit.go", want: ""},
+		{path: "go.sum", want: KindLockFile},
+		{path: "web/package-lock.json", want: KindLockFile},
+		{path: "Cargo.lock", want: KindLockFile},
+		{path: "api/user.pb.go", want: KindGenerated},
+		{path: "static/app.min.js", want: KindMinified},
+		{path: "vendor/github.com/pkg/errors/errors.go", want: KindVendored},
+		{path: "web/node_modules/left-pad/index.js", want: KindVendored},
+		{path: "internal/service/commit_test.go", want: KindTest},
+		{path: "web/src/app.spec.ts", want: KindTest},
+		{path: "test/integration/flow.go", want: KindTest},
+		{path: "README.md", want: KindDocs},
+		{path: "docs/guide.html", want: KindDocs},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := fileKind(tt.path, generatedFiles); got != tt.want {
+				t.Errorf("fileKind(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRankFiles(t *testing.T) {
+	files := []model.FileChange{
+		{Path: "go.sum", Status: "modified"},
+		{Path: "README.md", Status: "modified"},
+		{Path: "big.go", Status: "modified"},
+		{Path: "api/user.proto", Status: "modified"},
+		{Path: "api/user.pb.go", Status: "modified"},
+		{Path: "small.go", Status: "modified"},
+	}
+	costs := []int{10, 5, 900, 50, 400, 20}
+
+	order, kinds := rankFiles(files, costs)
+	var got []string
+	for _, i := range order {
+		got = append(got, files[i].Path)
+	}
+	want := []string{"small.go", "api/user.proto", "big.go", "README.md", "api/user.pb.go", "go.sum"}
+	if !slices.Equal(got, want) {
+		t.Errorf("rankFiles() = %v, want %v", got, want)
+	}
+	if wantKinds := []string{KindLockFile, KindDocs, "", "", KindGenerated, ""}; !slices.Equal(kinds, wantKinds) {
+		t.Errorf("rankFiles() kinds = %v, want %v", kinds, wantKinds)
+	}
+}
+
+func TestPlanContext(t *testing.T) {
+	lockDiff := "+" + strings.Repeat("lock ", 400) + "\n"
+	bigDiff := "+" + strings.Repeat("big ", 2000) + "\n"
+	state := &model.RepositoryState{StagedFiles: []model.FileChange{
+		{Path: "package-lock.json", Status: "modified", Diff: lockDiff},
+		{Path: "handler.go", Status: "modified", Diff: "+func Handle() {}\n"},
+		{Path: "big.go", Status: "modified", Diff: bigDiff},
+		{Path: "notes.md", Status: "modified", Diff: "+Usage.\n"},
+	}}
+
+	tests := []struct {
+		name           string
+		budget         int
+		wantIncluded   []string
+		wantSummarized []string
+	}{
+		{name: "unlimited", wantIncluded: []string{"handler.go", "big.go", "notes.md", "package-lock.json"}},
+		{
+			// The lock file is smaller than big.go but ranked after every other file
+			name:           "source files first",
+			budget:         2300,
+			wantIncluded:   []string{"handler.go", "big.go", "notes.md"},
+			wantSummarized: []string{"package-lock.json"},
+		},
+		{
+			name:           "smallest diffs first",
+			budget:         700,
+			wantIncluded:   []string{"handler.go", "notes.md", "package-lock.json"},
+			wantSummarized: []string{"big.go"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			layout := model.PromptLayout{Budget: tt.budget}
+			plan := PlanContext(layout, state)
+			if !slices.Equal(plan.Included, tt.wantIncluded) {
+				t.Errorf("Included = %v, want %v", plan.Included, tt.wantIncluded)
+			}
+			var summarized []string
+			for _, file := range plan.Summarized {
+				summarized = append(summarized, file.Path)
+			}
+			if !slices.Equal(summarized, tt.wantSummarized) {
+				t.Errorf("Summarized = %v, want %v", summarized, tt.wantSummarized)
+			}
+			if tt.budget > 0 && plan.Tokens > tt.budget {
+				t.Errorf("Tokens = %d, want at most %d", plan.Tokens, tt.budget)
+			}
+
+			// The prompt sends exactly the diffs of the plan
+			userMsg, err := NewUnifiedPromptGeneratorWithLayout(layout).GenerateUserMessage(state)
+			if err != nil {
+				t.Fatalf("GenerateUserMessage() error = %v", err)
+			}
+			for _, file := range state.StagedFiles {
+				if sent := strings.Contains(userMsg, file.Diff); sent != slices.Contains(tt.wantIncluded, file.Path) {
+					t.Errorf("diff of %s sent = %v, want %v", file.Path, sent, !sent)
+				}
+			}
+		})
+	}
+}
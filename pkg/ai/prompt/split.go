@@ -0,0 +1,36 @@
+package prompt
+
+import (
+	"strings"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+)
+
+// SplitSystemMessage returns the system message asking the model to group staged files
+// into several commits, answered in the split plan format (see pkg/split)
+func SplitSystemMessage() string {
+	var sb strings.Builder
+	sb.WriteString("You organize staged git changes into small, logical commits. You receive the staged files")
+	sb.WriteString(" and, when available, their diffs.\n\n")
+	sb.WriteString("Group files that belong to the same change (a feature with its tests and documentation,")
+	sb.WriteString(" a refactoring, a dependency update...). Order the commits so that each one builds on the previous ones.")
+	sb.WriteString(" Prefer fewer commits when changes are related; a single commit is fine.\n\n")
+	sb.WriteString("Answer with the plan only, without markdown or explanations. Start each commit with a line")
+	sb.WriteString(" \"commit <short name>\" followed by its file paths, one per line, exactly as given.")
+	sb.WriteString(" List every staged file exactly once.\n\n")
+	sb.WriteString("Example:\n")
+	sb.WriteString("commit go dependencies\ngo.mod\ngo.sum\n\ncommit retry failed requests\ninternal/client/retry.go\ninternal/client/retry_test.go\n")
+	return sb.String()
+}
+
+// SplitUserMessage returns the user message listing the staged files to group, with their
+// diffs when repoState shares them
+func SplitUserMessage(repoState *model.RepositoryState) (string, error) {
+	if repoState == nil {
+		return "", ErrNilRepositoryState
+	}
+
+	var sb strings.Builder
+	writeFileList(&sb, "Staged files:", repoState.StagedFiles)
+	return sb.String(), nil
+}
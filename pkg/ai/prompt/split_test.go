@@ -0,0 +1,32 @@
+package prompt
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+)
+
+func TestSplitUserMessage(t *testing.T) {
+	state := &model.RepositoryState{
+		StagedFiles: []model.FileChange{
+			{Path: "go.mod", Status: "modified", Diff: "+require example.com/retry v1.0.0"},
+			{Path: "internal/client/retry.go", Status: "added", LinesAdded: 40},
+		},
+	}
+
+	got, err := SplitUserMessage(state)
+	if err != nil {
+		t.Fatalf("SplitUserMessage() error = %v", err)
+	}
+	for _, want := range []string{"- go.mod (modified)\n+require example.com/retry", "- internal/client/retry.go (added, +40 -0)"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("SplitUserMessage() = %q, want it to contain %q", got, want)
+		}
+	}
+
+	if _, err := SplitUserMessage(nil); !errors.Is(err, ErrNilRepositoryState) {
+		t.Errorf("SplitUserMessage(nil) error = %v, want ErrNilRepositoryState", err)
+	}
+}
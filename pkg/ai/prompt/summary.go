@@ -0,0 +1,120 @@
+package prompt
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/pkg/tokenization"
+)
+
+// Summary-first modes, as set by ai.context.summary_first: the model first receives the
+// staged files with their line counts only, and names the files whose diffs it needs
+const (
+	// SummaryFirstOff sends the diffs in a single request (default)
+	SummaryFirstOff = "off"
+	// SummaryFirstAuto asks for the files first when the staged diffs exceed the budget
+	SummaryFirstAuto = "auto"
+	// SummaryFirstAlways always asks for the files first
+	SummaryFirstAlways = "always"
+)
+
+// SummaryFirstModes lists the summary-first modes
+var SummaryFirstModes = []string{SummaryFirstOff, SummaryFirstAuto, SummaryFirstAlways}
+
+// ValidateSummaryFirst returns an error when mode is not a summary-first mode ("" is off)
+func ValidateSummaryFirst(mode string) error {
+	if mode != "" && !slices.Contains(SummaryFirstModes, mode) {
+		return fmt.Errorf("unknown summary-first mode %q (expected one of %s)", mode, strings.Join(SummaryFirstModes, ", "))
+	}
+	return nil
+}
+
+// NeedsSummaryFirst reports whether the commit message of repoState is requested in two
+// rounds under layout. Only full per-file diffs can be selected: states reduced by a
+// privacy level, or holding the rtk condensed output, are sent in one request.
+func NeedsSummaryFirst(layout model.PromptLayout, repoState *model.RepositoryState) bool {
+	if repoState == nil || !sharesDiffs(repoState) || repoState.RawDiff != "" {
+		return false
+	}
+	calc := tokenization.ForTokenizer(layout.Tokenizer)
+	tokens, diffs := 0, 0
+	for _, file := range repoState.StagedFiles {
+		if file.Diff != "" {
+			tokens += calc.Calculate(file.Diff)
+			diffs++
+		}
+	}
+	if diffs == 0 {
+		return false
+	}
+
+	switch layout.SummaryFirst {
+	case SummaryFirstAlways:
+		return true
+	case SummaryFirstAuto:
+		return layout.Budget > 0 && tokens > layout.Budget
+	default:
+		return false
+	}
+}
+
+// SelectionSystemMessage returns the system message asking the model which staged files
+// it needs the diffs of, answered with one path per line
+func SelectionSystemMessage() string {
+	var sb strings.Builder
+	sb.WriteString("You prepare a git commit message for a large change. You first receive the staged files")
+	sb.WriteString(" with their status and changed line counts, without their diffs.\n\n")
+	sb.WriteString("Name the files whose full diff you need to understand the purpose of the change, most important first.")
+	sb.WriteString(" Ask for as few files as possible: skip lock files, generated files, and files whose change is obvious")
+	sb.WriteString(" from their name and line counts.\n\n")
+	sb.WriteString("Answer with the file paths only, one per line, exactly as given, without markdown or explanations.\n")
+	return sb.String()
+}
+
+// SelectionUserMessage returns the user message listing the staged files of repoState with
+// their line counts, for the first round of the summary-first mode
+func SelectionUserMessage(repoState *model.RepositoryState) (string, error) {
+	if repoState == nil {
+		return "", ErrNilRepositoryState
+	}
+
+	var sb strings.Builder
+	if repoState.Branch != "" {
+		sb.WriteString(fmt.Sprintf("Branch: %s\n\n", repoState.Branch))
+	}
+	sb.WriteString(formatChangeCounts(&model.RepositoryState{StagedFiles: repoState.StagedFiles}))
+	sb.WriteString("\n")
+	writeFileList(&sb, "Staged files:", repoState.WithPrivacy(model.PrivacyFilenamesOnly).StagedFiles)
+	return sb.String(), nil
+}
+
+// listMarker matches the bullet or number starting a line of a list
+var listMarker = regexp.MustCompile(`^(?:[-*•]|\d+[.)])\s+`)
+
+// ParseSelection returns the paths of staged named in the answer to a selection prompt,
+// in the order of staged. List markers, quotes and unknown paths are ignored.
+func ParseSelection(answer string, staged []string) []string {
+	named := make(map[string]bool)
+	for _, line := range strings.Split(answer, "\n") {
+		path := listMarker.ReplaceAllString(strings.TrimSpace(line), "")
+		path = strings.Trim(path, "`\"' ")
+		// "path (modified, +3 -1)" as listed in the question
+		if i := strings.Index(path, " ("); i > 0 {
+			path = path[:i]
+		}
+		if path != "" {
+			named[path] = true
+		}
+	}
+
+	var selected []string
+	for _, path := range staged {
+		if named[path] {
+			selected = append(selected, path)
+		}
+	}
+	return selected
+}
@@ -0,0 +1,102 @@
+package prompt
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+)
+
+func TestNeedsSummaryFirst(t *testing.T) {
+	big := &model.RepositoryState{StagedFiles: []model.FileChange{
+		{Path: "a.go", Status: "modified", Diff: strings.Repeat("+line of code\n", 200)},
+	}}
+	small := &model.RepositoryState{StagedFiles: []model.FileChange{{Path: "a.go", Status: "modified", Diff: "+x\n"}}}
+
+	tests := []struct {
+		name   string
+		layout model.PromptLayout
+		state  *model.RepositoryState
+		want   bool
+	}{
+		{"off", model.PromptLayout{Budget: 10}, big, false},
+		{"auto over budget", model.PromptLayout{Budget: 10, SummaryFirst: SummaryFirstAuto}, big, true},
+		{"auto within budget", model.PromptLayout{Budget: 10000, SummaryFirst: SummaryFirstAuto}, big, false},
+		{"auto without budget", model.PromptLayout{SummaryFirst: SummaryFirstAuto}, big, false},
+		{"always", model.PromptLayout{SummaryFirst: SummaryFirstAlways}, small, true},
+		{"filenames only", model.PromptLayout{SummaryFirst: SummaryFirstAlways}, big.WithPrivacy(model.PrivacyFilenamesOnly), false},
+		{"rtk output", model.PromptLayout{SummaryFirst: SummaryFirstAlways}, &model.RepositoryState{RawDiff: "+x", StagedFiles: small.StagedFiles}, false},
+		{"no diffs", model.PromptLayout{SummaryFirst: SummaryFirstAlways}, &model.RepositoryState{StagedFiles: []model.FileChange{{Path: "a.bin", Status: "added"}}}, false},
+		{"nil state", model.PromptLayout{SummaryFirst: SummaryFirstAlways}, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NeedsSummaryFirst(tt.layout, tt.state); got != tt.want {
+				t.Errorf("NeedsSummaryFirst() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateSummaryFirst(t *testing.T) {
+	for _, mode := range []string{"", SummaryFirstOff, SummaryFirstAuto, SummaryFirstAlways} {
+		if err := ValidateSummaryFirst(mode); err != nil {
+			t.Errorf("ValidateSummaryFirst(%q) error = %v", mode, err)
+		}
+	}
+	if err := ValidateSummaryFirst("sometimes"); err == nil {
+		t.Error("ValidateSummaryFirst(sometimes) should fail")
+	}
+}
+
+func TestSelectionUserMessage(t *testing.T) {
+	state := &model.RepositoryState{
+		Branch: "feature/retry",
+		StagedFiles: []model.FileChange{
+			{Path: "internal/client/retry.go", Status: "added", Diff: "+func Retry() {}\n+// backoff\n"},
+			{Path: "go.sum", Status: "modified", LinesAdded: 4, LinesRemoved: 2},
+		},
+	}
+
+	got, err := SelectionUserMessage(state)
+	if err != nil {
+		t.Fatalf("SelectionUserMessage() error = %v", err)
+	}
+	for _, want := range []string{"Branch: feature/retry", "- internal/client/retry.go (added, +2 -0)", "- go.sum (modified, +4 -2)", "Staged files: 2"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("SelectionUserMessage() = %q, want it to contain %q", got, want)
+		}
+	}
+	if strings.Contains(got, "func Retry") {
+		t.Errorf("SelectionUserMessage() = %q, want no diff", got)
+	}
+
+	if _, err := SelectionUserMessage(nil); !errors.Is(err, ErrNilRepositoryState) {
+		t.Errorf("SelectionUserMessage(nil) error = %v, want ErrNilRepositoryState", err)
+	}
+}
+
+func TestParseSelection(t *testing.T) {
+	staged := []string{"go.sum", "2fa/login.go", "internal/client/retry.go", "README.md"}
+
+	tests := []struct {
+		name   string
+		answer string
+		want   []string
+	}{
+		{"plain", "internal/client/retry.go\n2fa/login.go\n", []string{"2fa/login.go", "internal/client/retry.go"}},
+		{"list markers", "- `internal/client/retry.go`\n1. README.md\n* \"2fa/login.go\"", []string{"2fa/login.go", "internal/client/retry.go", "README.md"}},
+		{"with counts", "internal/client/retry.go (added, +2 -0)", []string{"internal/client/retry.go"}},
+		{"unknown paths", "Here are the files:\nmain.go\n", nil},
+		{"empty", "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseSelection(tt.answer, staged); !slices.Equal(got, tt.want) {
+				t.Errorf("ParseSelection() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
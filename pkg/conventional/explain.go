@@ -0,0 +1,202 @@
+package conventional
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+)
+
+// Explanation teaches one rule a commit message breaks, with the message corrected
+type Explanation struct {
+	// Field is the part of the message breaking the rule, as in ValidationError
+	Field string
+	// Rule is the rule, as reported by Validate
+	Rule string
+	// Problem says what is wrong in this message
+	Problem string
+	// Why says what the rule is for
+	Why string
+	// Example is the part of the message corrected offline (header or body)
+	Example string
+}
+
+// typeAliases maps words commonly used as commit types to the valid type they stand for
+var typeAliases = map[string]string{
+	"feature":       "feat",
+	"features":      "feat",
+	"add":           "feat",
+	"new":           "feat",
+	"bug":           "fix",
+	"bugfix":        "fix",
+	"hotfix":        "fix",
+	"fixes":         "fix",
+	"fixed":         "fix",
+	"doc":           "docs",
+	"documentation": "docs",
+	"readme":        "docs",
+	"tests":         "test",
+	"testing":       "test",
+	"refacto":       "refactor",
+	"refactoring":   "refactor",
+	"perf":          "refactor",
+	"cleanup":       "refactor",
+	"format":        "style",
+	"lint":          "style",
+	"build":         "chore",
+	"ci":            "chore",
+	"deps":          "chore",
+	"release":       "version",
+	"bump":          "version",
+}
+
+// ruleReasons says what each rule is for, by field
+var ruleReasons = map[string]string{
+	"type":    "The type tells readers and tools (changelogs, semantic versioning) what kind of change this is; only the listed types are recognized.",
+	"subject": "The subject is the line shown by git log --oneline, in pull request lists and in email subjects, where long lines are cut off; details belong in the body.",
+	"body":    "The body explains why the change was made in a few lines that stay readable in git log; long discussions belong in the pull request or the issue.",
+	"scope":   "The scope names the part of the project changed, and is matched by changelog tools and filters: spaces, slashes or dots break them.",
+	"ticket":  "The header format of this repository carries a ticket, so every commit can be traced back to its issue.",
+	"header":  "The header format of this repository is what changelog and tracking tools parse.",
+}
+
+// Explain returns an explanation of each violation of message (see Validate), with the
+// part of the message corrected offline by Correct
+func Explain(message *model.CommitMessage, violations []ValidationError) []Explanation {
+	corrected := Correct(message)
+	explanations := make([]Explanation, 0, len(violations))
+	for _, violation := range violations {
+		explanation := Explanation{
+			Field:   violation.Field,
+			Rule:    violation.Message,
+			Why:     ruleReasons[violation.Field],
+			Example: corrected.Header(),
+		}
+		switch violation.Field {
+		case "type":
+			if message.Type == "" {
+				explanation.Problem = "the header has no type before the colon"
+			} else {
+				explanation.Problem = fmt.Sprintf("%q is not a commit type", message.Type)
+			}
+		case "subject":
+			if message.Subject == "" {
+				explanation.Problem = "the header has no subject after the colon"
+			} else {
+				explanation.Problem = fmt.Sprintf("the subject is %d characters long, %d over the limit", len(message.Subject), len(message.Subject)-subjectMaxLength)
+			}
+		case "body":
+			explanation.Problem = fmt.Sprintf("the body is %d characters long, %d over the limit", len(message.Body), len(message.Body)-bodyMaxLength)
+			explanation.Example = corrected.Body
+		case "scope":
+			explanation.Problem = fmt.Sprintf("the scope %q contains other characters", message.Scope)
+		case "ticket":
+			explanation.Problem = "the header has no ticket"
+		case "header":
+			explanation.Problem = fmt.Sprintf("%q does not follow %s", message.Header(), message.Layout)
+		}
+		explanations = append(explanations, explanation)
+	}
+	return explanations
+}
+
+// Correct returns a copy of message with its violations fixed where it can be done
+// without understanding the change: the closest valid type, a scope made of valid
+// characters, and a subject and body shortened at a word or sentence boundary. A
+// missing ticket is written as the PROJ-123 placeholder.
+func Correct(message *model.CommitMessage) *model.CommitMessage {
+	corrected := *message
+
+	if !isValidType(corrected.Type) {
+		corrected.Type = closestType(corrected.Type, corrected.Subject)
+	}
+	if !isValidScope(corrected.Scope) {
+		corrected.Scope = cleanScope(corrected.Scope)
+	}
+
+	corrected.Subject = strings.TrimRight(strings.TrimSpace(corrected.Subject), ".")
+	if corrected.Subject == "" {
+		// The first line of the body usually says what the change does
+		first, _, _ := strings.Cut(strings.TrimSpace(corrected.Body), "\n")
+		corrected.Subject = strings.TrimRight(strings.TrimSpace(first), ".")
+	}
+	if corrected.Subject == "" {
+		corrected.Subject = "describe what the change does"
+	}
+	if len(corrected.Subject) > subjectMaxLength {
+		corrected.Subject = strings.TrimRight(shorten(corrected.Subject, subjectMaxLength, false), " ,;:")
+	}
+
+	if len(corrected.Body) > bodyMaxLength {
+		corrected.Body = shorten(corrected.Body, bodyMaxLength, true)
+	}
+	if corrected.Layout.HasTicket() && corrected.Ticket == "" {
+		corrected.Ticket = "PROJ-123"
+	}
+	return &corrected
+}
+
+// closestType returns the valid type written, the type an alias stands for, or the
+// type suggested by the first word of the subject ("chore" when nothing matches)
+func closestType(written, subject string) string {
+	word := strings.ToLower(strings.TrimSpace(written))
+	if word == "" {
+		word, _, _ = strings.Cut(strings.ToLower(strings.TrimSpace(subject)), " ")
+	}
+	word = strings.TrimFunc(word, func(r rune) bool { return !unicode.IsLetter(r) })
+	if isValidType(word) {
+		return word
+	}
+	if alias, ok := typeAliases[word]; ok {
+		return alias
+	}
+	// "features", "fixing", "docs/", "refactored"...
+	for _, valid := range validTypes {
+		if len(word) >= 3 && (strings.HasPrefix(word, valid) || strings.HasPrefix(valid, word)) {
+			return valid
+		}
+	}
+	for _, prefix := range slices.Sorted(maps.Keys(typeAliases)) {
+		if len(prefix) >= 3 && strings.HasPrefix(word, prefix) {
+			return typeAliases[prefix]
+		}
+	}
+	return "chore"
+}
+
+// cleanScope replaces the runs of characters not allowed in a scope by a hyphen
+func cleanScope(scope string) string {
+	var sb strings.Builder
+	hyphen := false
+	for _, r := range strings.TrimSpace(scope) {
+		if isValidScope(string(r)) {
+			sb.WriteRune(r)
+			hyphen = false
+		} else if !hyphen && sb.Len() > 0 {
+			sb.WriteByte('-')
+			hyphen = true
+		}
+	}
+	return strings.TrimRight(sb.String(), "-")
+}
+
+// shorten cuts text to at most limit bytes: after the last sentence that fits when
+// sentences is set, else (or when no sentence fits) at the last word boundary
+func shorten(text string, limit int, sentences bool) string {
+	if len(text) <= limit {
+		return text
+	}
+	cut := text[:limit]
+	if sentences {
+		if i := strings.LastIndexAny(cut, ".!?"); i > 0 {
+			return cut[:i+1]
+		}
+	}
+	if i := strings.LastIndexAny(cut, " \n"); i > 0 {
+		return strings.TrimSpace(cut[:i])
+	}
+	return cut
+}
@@ -0,0 +1,105 @@
+package conventional
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+)
+
+func TestCorrect(t *testing.T) {
+	longSubject := "add the endpoint that lets clients fetch the detailed status of every job in one call"
+	longBody := strings.Repeat("Clients polled each job separately. ", 12)
+
+	tests := []struct {
+		name    string
+		message *model.CommitMessage
+		want    *model.CommitMessage
+	}{
+		{
+			name:    "alias type",
+			message: &model.CommitMessage{Type: "feature", Subject: "add login"},
+			want:    &model.CommitMessage{Type: "feat", Subject: "add login"},
+		},
+		{
+			name:    "prefixed type",
+			message: &model.CommitMessage{Type: "fixing", Subject: "handle nil config"},
+			want:    &model.CommitMessage{Type: "fix", Subject: "handle nil config"},
+		},
+		{
+			name:    "unknown type",
+			message: &model.CommitMessage{Type: "misc", Subject: "tidy up"},
+			want:    &model.CommitMessage{Type: "chore", Subject: "tidy up"},
+		},
+		{
+			name:    "type from the subject",
+			message: &model.CommitMessage{Subject: "Fixed login bug."},
+			want:    &model.CommitMessage{Type: "fix", Subject: "Fixed login bug"},
+		},
+		{
+			name:    "scope with a slash",
+			message: &model.CommitMessage{Type: "feat", Scope: "api/v2", Subject: "add jobs"},
+			want:    &model.CommitMessage{Type: "feat", Scope: "api-v2", Subject: "add jobs"},
+		},
+		{
+			name:    "long subject cut at a word",
+			message: &model.CommitMessage{Type: "feat", Subject: longSubject},
+			want:    &model.CommitMessage{Type: "feat", Subject: "add the endpoint that lets clients fetch the detailed status of every"},
+		},
+		{
+			name:    "empty subject from the body",
+			message: &model.CommitMessage{Type: "docs", Body: "Document the cache.\nIt was undocumented."},
+			want:    &model.CommitMessage{Type: "docs", Subject: "Document the cache", Body: "Document the cache.\nIt was undocumented."},
+		},
+		{
+			name:    "long body cut at a sentence",
+			message: &model.CommitMessage{Type: "fix", Subject: "batch job status", Body: longBody},
+			want:    &model.CommitMessage{Type: "fix", Subject: "batch job status", Body: strings.TrimSpace(strings.Repeat("Clients polled each job separately. ", 8))},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Correct(tt.message)
+			if got.Type != tt.want.Type || got.Scope != tt.want.Scope || got.Subject != tt.want.Subject || got.Body != tt.want.Body {
+				t.Errorf("Correct() = %+v, want %+v", got, tt.want)
+			}
+			if valid, errs := NewValidator().Validate(got); !valid {
+				t.Errorf("Correct() = %+v breaks the rules: %v", got, errs)
+			}
+		})
+	}
+}
+
+func TestExplain(t *testing.T) {
+	message := &model.CommitMessage{
+		Type:    "feature",
+		Scope:   "api/v2",
+		Subject: "add the endpoint that lets clients fetch the detailed status of every job in one call",
+	}
+	valid, violations := NewValidator().Validate(message)
+	if valid {
+		t.Fatal("Validate() = valid, want violations")
+	}
+
+	explanations := Explain(message, violations)
+	if len(explanations) != len(violations) {
+		t.Fatalf("Explain() = %d explanations, want %d", len(explanations), len(violations))
+	}
+	problems := map[string]string{
+		"type":    `"feature" is not a commit type`,
+		"subject": "characters long, 13 over the limit",
+		"scope":   `the scope "api/v2"`,
+	}
+	for _, explanation := range explanations {
+		if want := problems[explanation.Field]; !strings.Contains(explanation.Problem, want) {
+			t.Errorf("%s: Problem = %q, want it to contain %q", explanation.Field, explanation.Problem, want)
+		}
+		if explanation.Why == "" {
+			t.Errorf("%s: no Why", explanation.Field)
+		}
+		if !strings.HasPrefix(explanation.Example, "feat(api-v2): ") {
+			t.Errorf("%s: Example = %q, want the corrected header", explanation.Field, explanation.Example)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package conventional
+
+import (
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// HighConfidence is the minimum confidence for an inferred type to be trusted without asking
+const HighConfidence = 0.8
+
+// TypeInference is the result of inferring a commit type from changed file paths
+type TypeInference struct {
+	// Type is the inferred commit type (empty if no type could be inferred)
+	Type string
+
+	// Confidence is the share of changed files supporting the inferred type (0 to 1)
+	Confidence float64
+}
+
+// IsHighConfidence returns true if the inference can be trusted without user confirmation
+func (i TypeInference) IsHighConfidence() bool {
+	return i.Type != "" && i.Confidence >= HighConfidence
+}
+
+// InferType infers a commit type from changed file paths using local heuristics.
+// Only types that can be recognised from paths alone (docs, test, chore) are inferred;
+// source code changes are ambiguous (feat, fix, refactor...) and yield no type.
+func InferType(paths []string) TypeInference {
+	if len(paths) == 0 {
+		return TypeInference{}
+	}
+
+	counts := make(map[string]int)
+	for _, p := range paths {
+		counts[classifyPath(p)]++
+	}
+
+	best, bestCount := "", 0
+	for t, count := range counts {
+		if count > bestCount || (count == bestCount && t < best) {
+			best, bestCount = t, count
+		}
+	}
+	if best == "" {
+		return TypeInference{}
+	}
+
+	return TypeInference{
+		Type:       best,
+		Confidence: float64(bestCount) / float64(len(paths)),
+	}
+}
+
+// classifyPath returns the commit type suggested by a single file path (empty for source code)
+func classifyPath(p string) string {
+	p = strings.ToLower(path.Clean(strings.ReplaceAll(p, "\\", "/")))
+	base := path.Base(p)
+	ext := path.Ext(base)
+
+	switch {
+	case strings.HasSuffix(base, "_test.go"),
+		strings.Contains(base, ".test."), strings.Contains(base, ".spec."),
+		hasDir(p, "test"), hasDir(p, "tests"), hasDir(p, "testdata"):
+		return "test"
+	case ext == ".md", ext == ".rst", ext == ".adoc",
+		strings.HasPrefix(base, "license"), hasDir(p, "docs"), hasDir(p, "doc"):
+		return "docs"
+	case base == "go.mod", base == "go.sum", base == "makefile", base == "dockerfile",
+		base == ".gitignore", base == ".gitattributes", base == ".editorconfig",
+		base == "package-lock.json", base == "yarn.lock", base == "pnpm-lock.yaml",
+		strings.HasPrefix(base, ".goreleaser"), strings.HasPrefix(base, ".golangci"),
+		hasDir(p, ".github"), hasDir(p, ".gitlab"):
+		return "chore"
+	}
+	return ""
+}
+
+// hasDir returns true if dir is one of the directory components of p
+func hasDir(p, dir string) bool {
+	parts := strings.Split(p, "/")
+	for _, part := range parts[:len(parts)-1] {
+		if part == dir {
+			return true
+		}
+	}
+	return false
+}
+
+// genericDirs are directory names too broad to be useful as a scope
+var genericDirs = map[string]bool{
+	"internal": true, "pkg": true, "cmd": true, "src": true, "lib": true, "app": true,
+}
+
+// InferScopes suggests scopes from changed file paths: the directory containing each file
+// (e.g. "ui" for internal/ui/prompts.go), most frequent first. Files at the repository
+// root or directly in a generic directory (internal, pkg, cmd...) suggest no scope.
+func InferScopes(paths []string) []string {
+	counts := make(map[string]int)
+	var order []string
+	for _, p := range paths {
+		dir := path.Dir(path.Clean(strings.ReplaceAll(p, "\\", "/")))
+		scope := strings.ToLower(path.Base(dir))
+		if dir == "." || genericDirs[scope] || !isValidScope(scope) {
+			continue
+		}
+		if counts[scope] == 0 {
+			order = append(order, scope)
+		}
+		counts[scope]++
+	}
+
+	sort.SliceStable(order, func(i, j int) bool { return counts[order[i]] > counts[order[j]] })
+	return order
+}
+
+// scopeHeaderRegex captures the scope of a Conventional Commits header: type(scope)!: subject
+var scopeHeaderRegex = regexp.MustCompile(`^[a-zA-Z]+\(([^()]+)\)!?: `)
+
+// ParseScope returns the scope of a Conventional Commits header, or "" if it has none
+func ParseScope(header string) string {
+	if m := scopeHeaderRegex.FindStringSubmatch(strings.TrimSpace(header)); m != nil {
+		return strings.TrimSpace(m[1])
+	}
+	return ""
+}
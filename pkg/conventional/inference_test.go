@@ -0,0 +1,116 @@
+package conventional
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInferType(t *testing.T) {
+	tests := []struct {
+		name     string
+		paths    []string
+		wantType string
+		wantHigh bool
+	}{
+		{
+			name:     "no files",
+			paths:    nil,
+			wantType: "",
+		},
+		{
+			name:     "documentation only",
+			paths:    []string{"README.md", "docs/usage.txt"},
+			wantType: "docs",
+			wantHigh: true,
+		},
+		{
+			name:     "tests only",
+			paths:    []string{"internal/service/commit_service_test.go", "test/integration/cli_test.go"},
+			wantType: "test",
+			wantHigh: true,
+		},
+		{
+			name:     "build and ci files",
+			paths:    []string{"go.mod", "go.sum", ".github/workflows/ci.yml"},
+			wantType: "chore",
+			wantHigh: true,
+		},
+		{
+			name:     "source code is ambiguous",
+			paths:    []string{"internal/service/commit_service.go"},
+			wantType: "",
+		},
+		{
+			name:     "mixed changes have low confidence",
+			paths:    []string{"README.md", "internal/ui/pager.go", "internal/ui/pager_test.go"},
+			wantType: "",
+		},
+		{
+			name:     "mostly docs is still low confidence",
+			paths:    []string{"README.md", "CHANGELOG.md", "docs/a.md", "internal/cmd/root.go"},
+			wantType: "docs",
+			wantHigh: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := InferType(tt.paths)
+			if got.Type != tt.wantType {
+				t.Errorf("InferType() type = %q, want %q", got.Type, tt.wantType)
+			}
+			if got.IsHighConfidence() != tt.wantHigh {
+				t.Errorf("InferType() high confidence = %v (%.2f), want %v", got.IsHighConfidence(), got.Confidence, tt.wantHigh)
+			}
+		})
+	}
+}
+
+func TestInferScopes(t *testing.T) {
+	tests := []struct {
+		name  string
+		paths []string
+		want  []string
+	}{
+		{
+			name:  "most frequent directory first",
+			paths: []string{"internal/service/commit_service.go", "internal/ui/prompts.go", "internal/ui/fuzzy.go"},
+			want:  []string{"ui", "service"},
+		},
+		{
+			name:  "root and generic directories are skipped",
+			paths: []string{"README.md", "internal/doc.go", "cmd/gitcomm/main.go"},
+			want:  []string{"gitcomm"},
+		},
+		{
+			name:  "invalid scope characters are skipped",
+			paths: []string{"docs/my dir/a.md"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := InferScopes(tt.paths); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("InferScopes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseScope(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"feat(ui): add pager", "ui"},
+		{"fix(api)!: drop v1", "api"},
+		{"chore: bump deps", ""},
+		{"Merge branch 'main'", ""},
+	}
+
+	for _, tt := range tests {
+		if got := ParseScope(tt.header); got != tt.want {
+			t.Errorf("ParseScope(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
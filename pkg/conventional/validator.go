@@ -1,6 +1,21 @@
 package conventional
 
-import "github.com/golgoth31/gitcomm/internal/model"
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+)
+
+// validTypes lists the valid commit types
+var validTypes = []string{"feat", "fix", "docs", "style", "refactor", "test", "chore", "version"}
+
+// Length limits of the subject and the body
+const (
+	subjectMaxLength = 72
+	bodyMaxLength    = 320
+)
 
 // MessageValidator defines the interface for validating Conventional Commits messages
 type MessageValidator interface {
@@ -38,7 +53,7 @@ func (v *Validator) Validate(message *model.CommitMessage) (bool, []ValidationEr
 	if !isValidType(message.Type) {
 		errors = append(errors, ValidationError{
 			Field:   "type",
-			Message: "type must be one of: feat, fix, docs, style, refactor, test, chore, version",
+			Message: "type must be one of: " + strings.Join(validTypes, ", "),
 		})
 	}
 
@@ -48,18 +63,18 @@ func (v *Validator) Validate(message *model.CommitMessage) (bool, []ValidationEr
 			Field:   "subject",
 			Message: "subject cannot be empty",
 		})
-	} else if len(message.Subject) > 72 {
+	} else if len(message.Subject) > subjectMaxLength {
 		errors = append(errors, ValidationError{
 			Field:   "subject",
-			Message: "subject must be ≤72 characters",
+			Message: fmt.Sprintf("subject must be ≤%d characters", subjectMaxLength),
 		})
 	}
 
 	// Validate body
-	if message.Body != "" && len(message.Body) > 320 {
+	if message.Body != "" && len(message.Body) > bodyMaxLength {
 		errors = append(errors, ValidationError{
 			Field:   "body",
-			Message: "body must be ≤320 characters",
+			Message: fmt.Sprintf("body must be ≤%d characters", bodyMaxLength),
 		})
 	}
 
@@ -71,18 +86,37 @@ func (v *Validator) Validate(message *model.CommitMessage) (bool, []ValidationEr
 		})
 	}
 
+	// Validate trailers, which must be read back as "Key: value" footers
+	for _, trailer := range message.Trailers {
+		if !trailer.Valid() {
+			errors = append(errors, ValidationError{
+				Field:   "footer",
+				Message: fmt.Sprintf("trailer %q must have a key of letters, digits and hyphens, and a value", trailer.String()),
+			})
+		}
+	}
+
+	// Validate the header against a custom layout (commit.header_format)
+	if message.Layout.HasTicket() && message.Ticket == "" {
+		errors = append(errors, ValidationError{
+			Field:   "ticket",
+			Message: "ticket cannot be empty: the header format requires one",
+		})
+	} else if message.Layout != nil && len(errors) == 0 {
+		if _, ok := message.Layout.Match(message.Header()); !ok {
+			errors = append(errors, ValidationError{
+				Field:   "header",
+				Message: fmt.Sprintf("header must follow the format %s", message.Layout),
+			})
+		}
+	}
+
 	return len(errors) == 0, errors
 }
 
 // isValidType checks if the type is a valid Conventional Commits type
 func isValidType(t string) bool {
-	validTypes := []string{"feat", "fix", "docs", "style", "refactor", "test", "chore", "version"}
-	for _, vt := range validTypes {
-		if t == vt {
-			return true
-		}
-	}
-	return false
+	return slices.Contains(validTypes, t)
 }
 
 // isValidScope checks if the scope is a valid identifier
@@ -101,17 +135,17 @@ func isValidScope(scope string) bool {
 
 // GetValidTypes returns the list of valid commit types
 func (v *Validator) GetValidTypes() []string {
-	return []string{"feat", "fix", "docs", "style", "refactor", "test", "chore", "version"}
+	return slices.Clone(validTypes)
 }
 
 // GetSubjectMaxLength returns the maximum allowed length for commit message subject
 func (v *Validator) GetSubjectMaxLength() int {
-	return 72
+	return subjectMaxLength
 }
 
 // GetBodyMaxLength returns the maximum allowed length for commit message body
 func (v *Validator) GetBodyMaxLength() int {
-	return 320
+	return bodyMaxLength
 }
 
 // GetScopeFormatDescription returns a human-readable description of valid scope format
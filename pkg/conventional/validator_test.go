@@ -73,6 +73,28 @@ func TestValidator_Validate(t *testing.T) {
 			wantValid:  false,
 			wantErrors: 1,
 		},
+		{
+			name: "breaking change with trailers",
+			message: &model.CommitMessage{
+				Type:                "feat",
+				Subject:             "drop v1 routes",
+				Breaking:            true,
+				BreakingDescription: "the /v1 routes are gone",
+				Trailers:            []model.Trailer{{Key: "Refs", Value: "#12"}},
+			},
+			wantValid:  true,
+			wantErrors: 0,
+		},
+		{
+			name: "invalid trailers",
+			message: &model.CommitMessage{
+				Type:     "feat",
+				Subject:  "add feature",
+				Trailers: []model.Trailer{{Key: "Reviewed by", Value: "Ann"}, {Key: "Refs", Value: " "}},
+			},
+			wantValid:  false,
+			wantErrors: 2,
+		},
 		{
 			name: "valid with empty scope",
 			message: &model.CommitMessage{
@@ -98,6 +120,47 @@ func TestValidator_Validate(t *testing.T) {
 	}
 }
 
+func TestValidator_Validate_HeaderLayout(t *testing.T) {
+	validator := NewValidator()
+	layout, err := model.ParseHeaderLayout("[{ticket}] {type}({scope}): {subject}")
+	if err != nil {
+		t.Fatalf("ParseHeaderLayout() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		message   *model.CommitMessage
+		wantField string // "" when valid
+	}{
+		{
+			name:    "ticket in header",
+			message: &model.CommitMessage{Type: "feat", Ticket: "PROJ-123", Subject: "add login", Layout: layout},
+		},
+		{
+			name:      "missing ticket",
+			message:   &model.CommitMessage{Type: "feat", Subject: "add login", Layout: layout},
+			wantField: "ticket",
+		},
+		{
+			name:      "ticket with spaces",
+			message:   &model.CommitMessage{Type: "feat", Ticket: "PROJ 123", Subject: "add login", Layout: layout},
+			wantField: "header",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			valid, errors := validator.Validate(tt.message)
+			if valid != (tt.wantField == "") {
+				t.Fatalf("Validator.Validate() valid = %v, errors = %v", valid, errors)
+			}
+			if tt.wantField != "" && (len(errors) != 1 || errors[0].Field != tt.wantField) {
+				t.Errorf("Validator.Validate() errors = %v, want one %s error", errors, tt.wantField)
+			}
+		})
+	}
+}
+
 func TestValidator_Validate_AllTypes(t *testing.T) {
 	validator := NewValidator()
 	validTypes := []string{"feat", "fix", "docs", "style", "refactor", "test", "chore", "version"}
@@ -0,0 +1,171 @@
+// Package datefmt formats timestamps for humans with a configurable layout, time zone and locale,
+// so stamps written by a release team are the same whatever the machine settings.
+package datefmt
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	// Time zones must resolve on machines without a zoneinfo database (Windows)
+	_ "time/tzdata"
+)
+
+// DefaultLayout is the Go layout used when none is configured
+const DefaultLayout = "2006-01-02 15:04"
+
+// names holds the localized month and weekday names of a locale
+type names struct {
+	months      [12]string
+	shortMonths [12]string
+	days        [7]string // Sunday first, like time.Weekday
+	shortDays   [7]string
+}
+
+// locales are the supported locales, by language code
+var locales = map[string]names{
+	"en": {
+		months:      [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+		shortMonths: [12]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+		days:        [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+		shortDays:   [7]string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"},
+	},
+	"fr": {
+		months:      [12]string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+		shortMonths: [12]string{"janv.", "févr.", "mars", "avr.", "mai", "juin", "juil.", "août", "sept.", "oct.", "nov.", "déc."},
+		days:        [7]string{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"},
+		shortDays:   [7]string{"dim.", "lun.", "mar.", "mer.", "jeu.", "ven.", "sam."},
+	},
+	"de": {
+		months:      [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+		shortMonths: [12]string{"Jan.", "Feb.", "März", "Apr.", "Mai", "Juni", "Juli", "Aug.", "Sept.", "Okt.", "Nov.", "Dez."},
+		days:        [7]string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+		shortDays:   [7]string{"So.", "Mo.", "Di.", "Mi.", "Do.", "Fr.", "Sa."},
+	},
+	"es": {
+		months:      [12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+		shortMonths: [12]string{"ene", "feb", "mar", "abr", "may", "jun", "jul", "ago", "sept", "oct", "nov", "dic"},
+		days:        [7]string{"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"},
+		shortDays:   [7]string{"dom", "lun", "mar", "mié", "jue", "vie", "sáb"},
+	},
+	"it": {
+		months:      [12]string{"gennaio", "febbraio", "marzo", "aprile", "maggio", "giugno", "luglio", "agosto", "settembre", "ottobre", "novembre", "dicembre"},
+		shortMonths: [12]string{"gen", "feb", "mar", "apr", "mag", "giu", "lug", "ago", "set", "ott", "nov", "dic"},
+		days:        [7]string{"domenica", "lunedì", "martedì", "mercoledì", "giovedì", "venerdì", "sabato"},
+		shortDays:   [7]string{"dom", "lun", "mar", "mer", "gio", "ven", "sab"},
+	},
+	"pt": {
+		months:      [12]string{"janeiro", "fevereiro", "março", "abril", "maio", "junho", "julho", "agosto", "setembro", "outubro", "novembro", "dezembro"},
+		shortMonths: [12]string{"jan", "fev", "mar", "abr", "mai", "jun", "jul", "ago", "set", "out", "nov", "dez"},
+		days:        [7]string{"domingo", "segunda-feira", "terça-feira", "quarta-feira", "quinta-feira", "sexta-feira", "sábado"},
+		shortDays:   [7]string{"dom", "seg", "ter", "qua", "qui", "sex", "sáb"},
+	},
+	"nl": {
+		months:      [12]string{"januari", "februari", "maart", "april", "mei", "juni", "juli", "augustus", "september", "oktober", "november", "december"},
+		shortMonths: [12]string{"jan", "feb", "mrt", "apr", "mei", "jun", "jul", "aug", "sep", "okt", "nov", "dec"},
+		days:        [7]string{"zondag", "maandag", "dinsdag", "woensdag", "donderdag", "vrijdag", "zaterdag"},
+		shortDays:   [7]string{"zo", "ma", "di", "wo", "do", "vr", "za"},
+	},
+}
+
+// nameTokens are the layout elements replaced by localized names, longest first
+var nameTokens = []string{"January", "Monday", "Jan", "Mon"}
+
+// Formatter formats timestamps with a layout, time zone and locale
+type Formatter struct {
+	layout   string
+	location *time.Location
+	names    names
+}
+
+// NewFormatter creates a formatter. layout is a Go time layout (default: DefaultLayout),
+// timezone an IANA name such as "Europe/Paris" or "UTC" (default: local time) and
+// locale a language code such as "fr" or "de-CH" (default: en).
+func NewFormatter(layout, timezone, locale string) (*Formatter, error) {
+	f := &Formatter{layout: layout, location: time.Local}
+	if f.layout == "" {
+		f.layout = DefaultLayout
+	}
+
+	if timezone != "" {
+		location, err := time.LoadLocation(timezone)
+		if err != nil {
+			return nil, fmt.Errorf("unknown time zone %q: %w", timezone, err)
+		}
+		f.location = location
+	}
+
+	n, err := lookupLocale(locale)
+	if err != nil {
+		return nil, err
+	}
+	f.names = n
+	return f, nil
+}
+
+// Format formats t with the configured layout
+func (f *Formatter) Format(t time.Time) string {
+	return f.FormatLayout(t, f.layout)
+}
+
+// FormatLayout formats t with another layout, in the configured time zone and locale
+func (f *Formatter) FormatLayout(t time.Time, layout string) string {
+	t = t.In(f.location)
+
+	// Names are written directly rather than substituted in the layout: localized names
+	// may contain layout elements (e.g. "Montag" starts with "Mon")
+	var sb strings.Builder
+	for layout != "" {
+		i, token := nextNameToken(layout)
+		if i < 0 {
+			sb.WriteString(t.Format(layout))
+			break
+		}
+		sb.WriteString(t.Format(layout[:i]))
+		sb.WriteString(f.name(t, token))
+		layout = layout[i+len(token):]
+	}
+	return sb.String()
+}
+
+// name returns the localized name of t for a name token
+func (f *Formatter) name(t time.Time, token string) string {
+	switch token {
+	case "January":
+		return f.names.months[t.Month()-1]
+	case "Jan":
+		return f.names.shortMonths[t.Month()-1]
+	case "Monday":
+		return f.names.days[t.Weekday()]
+	default:
+		return f.names.shortDays[t.Weekday()]
+	}
+}
+
+// nextNameToken returns the position and value of the first name token in layout, or -1
+func nextNameToken(layout string) (int, string) {
+	best, bestToken := -1, ""
+	for _, token := range nameTokens {
+		i := strings.Index(layout, token)
+		// Longer tokens come first, so a tie keeps "January" over "Jan"
+		if i >= 0 && (best < 0 || i < best) {
+			best, bestToken = i, token
+		}
+	}
+	return best, bestToken
+}
+
+// lookupLocale returns the names of locale; regions and encodings ("de_CH.UTF-8") are ignored
+func lookupLocale(locale string) (names, error) {
+	if locale == "" {
+		return locales["en"], nil
+	}
+	lang := strings.ToLower(locale)
+	if i := strings.IndexAny(lang, "-_."); i >= 0 {
+		lang = lang[:i]
+	}
+	n, ok := locales[lang]
+	if !ok {
+		return names{}, fmt.Errorf("unsupported locale %q (supported: de, en, es, fr, it, nl, pt)", locale)
+	}
+	return n, nil
+}
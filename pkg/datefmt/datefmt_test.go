@@ -0,0 +1,47 @@
+package datefmt
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatter_Format(t *testing.T) {
+	// Monday 3 March 2025, 23:30 UTC
+	stamp := time.Date(2025, time.March, 3, 23, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		layout   string
+		timezone string
+		locale   string
+		want     string
+	}{
+		{"default layout in UTC", "", "UTC", "", "2025-03-03 23:30"},
+		{"time zone changes the day", "2006-01-02 15:04 MST", "Europe/Paris", "", "2025-03-04 00:30 CET"},
+		{"english names", "Monday 2 January 2006", "UTC", "en", "Monday 3 March 2025"},
+		{"french names", "Monday 2 January 2006", "UTC", "fr", "lundi 3 mars 2025"},
+		{"german name containing a layout element", "Monday, 2. January 2006", "UTC", "de_DE.UTF-8", "Montag, 3. März 2025"},
+		{"short names", "Mon 2 Jan", "Asia/Tokyo", "es-ES", "mar 4 mar"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := NewFormatter(tt.layout, tt.timezone, tt.locale)
+			if err != nil {
+				t.Fatalf("NewFormatter() error = %v", err)
+			}
+			if got := f.Format(stamp); got != tt.want {
+				t.Errorf("Format() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewFormatter_Errors(t *testing.T) {
+	if _, err := NewFormatter("", "Mars/Olympus_Mons", ""); err == nil {
+		t.Error("NewFormatter() with unknown time zone: want error")
+	}
+	if _, err := NewFormatter("", "", "tlh"); err == nil {
+		t.Error("NewFormatter() with unsupported locale: want error")
+	}
+}
@@ -0,0 +1,76 @@
+// Package generated recognizes files generated from other files (compiled TypeScript,
+// protobuf stubs, compiled stylesheets...) by their names
+package generated
+
+import (
+	"path"
+	"strings"
+)
+
+// Pair is a source file and a file generated from it
+type Pair struct {
+	// Source is the path of the source file (e.g. api/user.proto)
+	Source string
+	// Generated is the path of the generated file (e.g. api/user.pb.go)
+	Generated string
+}
+
+// rule maps a source suffix to the suffixes of the files generated from it
+type rule struct {
+	source    string
+	generated []string
+}
+
+// rules are matched in order; the first one matching the source suffix applies
+var rules = []rule{
+	// Declaration files are outputs of the compiler, not sources
+	{".d.ts", nil},
+	{".ts", []string{".js", ".js.map", ".d.ts"}},
+	{".tsx", []string{".js", ".jsx", ".js.map", ".d.ts"}},
+	{".coffee", []string{".js"}},
+	{".proto", []string{".pb.go", "_grpc.pb.go", ".pb.gw.go", "_pb2.py", "_pb2_grpc.py", "_pb.js", "_pb.d.ts", ".pb.cc", ".pb.h"}},
+	{".scss", []string{".css", ".css.map"}},
+	{".sass", []string{".css", ".css.map"}},
+	{".less", []string{".css", ".css.map"}},
+	{".templ", []string{"_templ.go"}},
+}
+
+// Find returns the pairs of paths where one file is generated from another one. A
+// generated file matches a source with the same base name (minus the suffixes) in any
+// directory, as build outputs often go to another one (dist/, gen/...). Pairs are in the
+// order of their sources in paths; a generated file is paired with its first source only.
+func Find(paths []string) []Pair {
+	byName := make(map[string][]string, len(paths))
+	for _, p := range paths {
+		name := path.Base(filepathToSlash(p))
+		byName[name] = append(byName[name], p)
+	}
+
+	var pairs []Pair
+	paired := make(map[string]bool)
+	for _, source := range paths {
+		name := path.Base(filepathToSlash(source))
+		for _, r := range rules {
+			stem, ok := strings.CutSuffix(name, r.source)
+			if !ok || stem == "" {
+				continue
+			}
+			for _, suffix := range r.generated {
+				for _, candidate := range byName[stem+suffix] {
+					if candidate == source || paired[candidate] {
+						continue
+					}
+					paired[candidate] = true
+					pairs = append(pairs, Pair{Source: source, Generated: candidate})
+				}
+			}
+			break
+		}
+	}
+	return pairs
+}
+
+// filepathToSlash normalizes Windows separators, git paths use forward slashes
+func filepathToSlash(p string) string {
+	return strings.ReplaceAll(p, "\\", "/")
+}
@@ -0,0 +1,58 @@
+package generated
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFind(t *testing.T) {
+	tests := []struct {
+		name  string
+		paths []string
+		want  []Pair
+	}{
+		{
+			name:  "typescript compiled next to its source",
+			paths: []string{"src/app.ts", "src/app.js", "src/app.js.map"},
+			want:  []Pair{{"src/app.ts", "src/app.js"}, {"src/app.ts", "src/app.js.map"}},
+		},
+		{
+			name:  "output in another directory",
+			paths: []string{"dist/app.js", "src/app.ts"},
+			want:  []Pair{{"src/app.ts", "dist/app.js"}},
+		},
+		{
+			name:  "protobuf stubs",
+			paths: []string{"api/user.proto", "api/user.pb.go", "api/user_grpc.pb.go", "api/order.pb.go"},
+			want:  []Pair{{"api/user.proto", "api/user.pb.go"}, {"api/user.proto", "api/user_grpc.pb.go"}},
+		},
+		{
+			name:  "declaration file is not a source",
+			paths: []string{"types/index.d.ts", "types/index.d.js"},
+			want:  nil,
+		},
+		{
+			name:  "stylesheets",
+			paths: []string{"styles/site.scss", "public/site.css"},
+			want:  []Pair{{"styles/site.scss", "public/site.css"}},
+		},
+		{
+			name:  "source alone",
+			paths: []string{"src/app.ts", "README.md"},
+			want:  nil,
+		},
+		{
+			name:  "generated file paired once",
+			paths: []string{"a/app.ts", "b/app.ts", "dist/app.js"},
+			want:  []Pair{{"a/app.ts", "dist/app.js"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Find(tt.paths); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Find() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package config
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+// GitSignatureNamespace is the SSHSIG namespace git uses for commit and tag signatures
+const GitSignatureNamespace = "git"
+
+// ErrKeyNotInAgent indicates the signing key is not loaded in ssh-agent
+var ErrKeyNotInAgent = errors.New("signing key not loaded in ssh-agent")
+
+// AgentSigner signs with an SSH key held by ssh-agent, producing the armored SSHSIG
+// signatures of "ssh-keygen -Y sign". Private keys are never read from disk.
+type AgentSigner struct {
+	conn   net.Conn
+	signer ssh.Signer
+}
+
+// NewAgentSigner connects to the agent at SSH_AUTH_SOCK and selects the key matching
+// signingKey, the user.signingkey value: a public key file or a "key::" literal.
+// The signer must be closed after use.
+func NewAgentSigner(signingKey string) (*AgentSigner, error) {
+	publicKey, err := ParseSigningKey(signingKey)
+	if err != nil {
+		return nil, err
+	}
+
+	socket := os.Getenv("SSH_AUTH_SOCK")
+	if socket == "" {
+		return nil, fmt.Errorf("%w: SSH_AUTH_SOCK is not set", ErrKeyNotInAgent)
+	}
+	conn, err := net.Dial("unix", socket)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to ssh-agent: %w", err)
+	}
+
+	signers, err := agent.NewClient(conn).Signers()
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to list ssh-agent keys: %w", err)
+	}
+	wanted := publicKey.Marshal()
+	for _, signer := range signers {
+		if bytes.Equal(signer.PublicKey().Marshal(), wanted) {
+			return &AgentSigner{conn: conn, signer: signer}, nil
+		}
+	}
+	conn.Close()
+	return nil, fmt.Errorf("%w: %s", ErrKeyNotInAgent, ssh.FingerprintSHA256(publicKey))
+}
+
+// ParseSigningKey returns the public key named by a user.signingkey value for SSH signing:
+// a "key::" literal or the path of a public key file ("~/" is expanded)
+func ParseSigningKey(signingKey string) (ssh.PublicKey, error) {
+	var data []byte
+	if literal, ok := strings.CutPrefix(signingKey, "key::"); ok {
+		data = []byte(literal)
+	} else {
+		path := signingKey
+		if rest, ok := strings.CutPrefix(path, "~/"); ok {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return nil, fmt.Errorf("failed to expand %s: %w", signingKey, err)
+			}
+			path = filepath.Join(home, rest)
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read signing key: %w", err)
+		}
+		data = content
+	}
+
+	publicKey, _, _, _, err := ssh.ParseAuthorizedKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SSH signing key %q: %w", signingKey, err)
+	}
+	return publicKey, nil
+}
+
+// PublicKey returns the signing key
+func (s *AgentSigner) PublicKey() ssh.PublicKey {
+	return s.signer.PublicKey()
+}
+
+// Sign signs message in namespace (GitSignatureNamespace for git objects) and returns the
+// armored signature, as "ssh-keygen -Y sign -n <namespace>" does
+func (s *AgentSigner) Sign(message []byte, namespace string) ([]byte, error) {
+	hash := sha512.Sum512(message)
+	signedData := sshsigBlob{
+		Namespace:     namespace,
+		HashAlgorithm: "sha512",
+		Hash:          string(hash[:]),
+	}
+	toSign := append([]byte(sshsigMagic), ssh.Marshal(signedData)...)
+
+	var signature *ssh.Signature
+	var err error
+	if algorithmSigner, ok := s.signer.(ssh.AlgorithmSigner); ok && s.signer.PublicKey().Type() == ssh.KeyAlgoRSA {
+		// ssh-keygen signs with SHA-512 for RSA keys
+		signature, err = algorithmSigner.SignWithAlgorithm(nil, toSign, ssh.KeyAlgoRSASHA512)
+	} else {
+		signature, err = s.signer.Sign(nil, toSign)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ssh-agent failed to sign: %w", err)
+	}
+
+	blob := append([]byte(sshsigMagic), ssh.Marshal(sshsigSignature{
+		Version:       1,
+		PublicKey:     string(s.signer.PublicKey().Marshal()),
+		Namespace:     namespace,
+		HashAlgorithm: "sha512",
+		Signature:     string(ssh.Marshal(signature)),
+	})...)
+	return armorSignature(blob), nil
+}
+
+// Close closes the agent connection
+func (s *AgentSigner) Close() error {
+	return s.conn.Close()
+}
+
+// sshsigMagic starts SSHSIG signed data and signatures (see OpenSSH PROTOCOL.sshsig)
+const sshsigMagic = "SSHSIG"
+
+// sshsigBlob is the data signed by the key, after the magic
+type sshsigBlob struct {
+	Namespace     string
+	Reserved      string
+	HashAlgorithm string
+	Hash          string
+}
+
+// sshsigSignature is the signature blob, after the magic
+type sshsigSignature struct {
+	Version       uint32
+	PublicKey     string
+	Namespace     string
+	Reserved      string
+	HashAlgorithm string
+	Signature     string
+}
+
+// armorSignature wraps blob in the SSH SIGNATURE armor with 70-column lines, like ssh-keygen
+func armorSignature(blob []byte) []byte {
+	encoded := base64.StdEncoding.EncodeToString(blob)
+	var buf bytes.Buffer
+	buf.WriteString("-----BEGIN SSH SIGNATURE-----\n")
+	for len(encoded) > 70 {
+		buf.WriteString(encoded[:70])
+		buf.WriteByte('\n')
+		encoded = encoded[70:]
+	}
+	buf.WriteString(encoded)
+	buf.WriteString("\n-----END SSH SIGNATURE-----\n")
+	return buf.Bytes()
+}
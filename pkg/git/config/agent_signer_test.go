@@ -0,0 +1,119 @@
+package config
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/golgoth31/gitcomm/pkg/testutil"
+	"golang.org/x/crypto/ssh"
+)
+
+func TestAgentSigner_Sign(t *testing.T) {
+	publicKey := testutil.StartSSHAgent(t)
+	signingKey := "key::" + string(ssh.MarshalAuthorizedKey(publicKey))
+
+	signer, err := NewAgentSigner(signingKey)
+	if err != nil {
+		t.Fatalf("NewAgentSigner() error = %v", err)
+	}
+	defer signer.Close()
+
+	message := []byte("tree 4b825dc642cb6eb9a060e54bf8d69288fbee4904\n\nfeat: sign with the agent\n")
+	signature, err := signer.Sign(message, GitSignatureNamespace)
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+
+	if _, err := exec.LookPath("ssh-keygen"); err != nil {
+		t.Skip("ssh-keygen not installed")
+	}
+	dir := t.TempDir()
+	allowed := filepath.Join(dir, "allowed_signers")
+	if err := os.WriteFile(allowed, append([]byte("jane@example.com "), ssh.MarshalAuthorizedKey(publicKey)...), 0600); err != nil {
+		t.Fatalf("Failed to write allowed signers: %v", err)
+	}
+	sigPath := filepath.Join(dir, "message.sig")
+	if err := os.WriteFile(sigPath, signature, 0600); err != nil {
+		t.Fatalf("Failed to write signature: %v", err)
+	}
+
+	verify := exec.Command("ssh-keygen", "-Y", "verify", "-f", allowed, "-I", "jane@example.com", "-n", "git", "-s", sigPath)
+	verify.Stdin = bytes.NewReader(message)
+	if out, err := verify.CombinedOutput(); err != nil {
+		t.Errorf("ssh-keygen -Y verify failed: %v\n%s\n%s", err, out, signature)
+	}
+}
+
+func TestNewAgentSigner_KeyNotLoaded(t *testing.T) {
+	testutil.StartSSHAgent(t)
+
+	other, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	otherKey, err := ssh.NewPublicKey(other)
+	if err != nil {
+		t.Fatalf("Failed to convert key: %v", err)
+	}
+
+	_, err = NewAgentSigner("key::" + string(ssh.MarshalAuthorizedKey(otherKey)))
+	if !errors.Is(err, ErrKeyNotInAgent) {
+		t.Errorf("NewAgentSigner() error = %v, want ErrKeyNotInAgent", err)
+	}
+
+	t.Setenv("SSH_AUTH_SOCK", "")
+	if _, err := NewAgentSigner("key::" + string(ssh.MarshalAuthorizedKey(otherKey))); !errors.Is(err, ErrKeyNotInAgent) {
+		t.Errorf("NewAgentSigner() without agent error = %v, want ErrKeyNotInAgent", err)
+	}
+}
+
+func TestParseSigningKey(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	public, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	key, err := ssh.NewPublicKey(public)
+	if err != nil {
+		t.Fatalf("Failed to convert key: %v", err)
+	}
+	authorized := ssh.MarshalAuthorizedKey(key)
+	if err := os.MkdirAll(filepath.Join(home, ".ssh"), 0700); err != nil {
+		t.Fatalf("Failed to create .ssh: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(home, ".ssh", "id_ed25519.pub"), authorized, 0644); err != nil {
+		t.Fatalf("Failed to write key: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		signingKey string
+		wantErr    bool
+	}{
+		{"file in home", "~/.ssh/id_ed25519.pub", false},
+		{"absolute path", filepath.Join(home, ".ssh", "id_ed25519.pub"), false},
+		{"literal", "key::" + string(authorized), false},
+		{"missing file", "~/.ssh/missing.pub", true},
+		{"invalid literal", "key::not a key", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSigningKey(tt.signingKey)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSigningKey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && ssh.FingerprintSHA256(got) != ssh.FingerprintSHA256(key) {
+				t.Errorf("ParseSigningKey() = %s, want %s", ssh.FingerprintSHA256(got), ssh.FingerprintSHA256(key))
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// AllowedPrincipals returns the principals the allowed signers file at path trusts
+// publicKey for when verifying git signatures, as "ssh-keygen -Y find-principals" does:
+// lines of "<principals> [options] <keytype> <key>", principals being comma-separated
+// patterns. Lines restricted to other namespaces than "git" and certificate authorities
+// are skipped. Returns nil when the key is not listed.
+func AllowedPrincipals(path string, publicKey ssh.PublicKey) ([]string, error) {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("failed to expand %s: %w", path, err)
+		}
+		path = filepath.Join(home, rest)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read allowed signers file: %w", err)
+	}
+	defer file.Close()
+
+	wanted := publicKey.Marshal()
+	var principals []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns, rest := cutPrincipals(line)
+		key, _, options, _, err := ssh.ParseAuthorizedKey([]byte(rest))
+		if err != nil || !bytes.Equal(key.Marshal(), wanted) || !signsForGit(options) {
+			continue
+		}
+		principals = append(principals, strings.Split(patterns, ",")...)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read allowed signers file: %w", err)
+	}
+	return principals, nil
+}
+
+// MatchPrincipal reports whether email matches one of principals: patterns where "*"
+// matches any run of characters and "?" a single one, negated by a leading "!"
+func MatchPrincipal(principals []string, email string) bool {
+	matched := false
+	for _, principal := range principals {
+		if pattern, negated := strings.CutPrefix(principal, "!"); negated {
+			if matchPattern(pattern, email) {
+				return false
+			}
+			continue
+		}
+		matched = matched || matchPattern(principal, email)
+	}
+	return matched
+}
+
+// cutPrincipals splits an allowed signers line after its principals, which may be quoted
+func cutPrincipals(line string) (principals, rest string) {
+	if quoted, ok := strings.CutPrefix(line, `"`); ok {
+		if end := strings.Index(quoted, `"`); end >= 0 {
+			return quoted[:end], strings.TrimSpace(quoted[end+1:])
+		}
+	}
+	principals, rest, _ = strings.Cut(line, " ")
+	return principals, strings.TrimSpace(rest)
+}
+
+// signsForGit reports whether a key with options may sign git commits
+func signsForGit(options []string) bool {
+	for _, option := range options {
+		if strings.EqualFold(option, "cert-authority") {
+			return false
+		}
+		name, value, ok := strings.Cut(option, "=")
+		if ok && strings.EqualFold(name, "namespaces") {
+			if !slices.Contains(strings.Split(strings.Trim(value, `"`), ","), "git") {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// matchPattern matches text against an OpenSSH pattern
+func matchPattern(pattern, text string) bool {
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case '*':
+			for i := len(text); i >= 0; i-- {
+				if matchPattern(pattern[1:], text[i:]) {
+					return true
+				}
+			}
+			return false
+		case '?':
+			if text == "" {
+				return false
+			}
+		default:
+			if text == "" || text[0] != pattern[0] {
+				return false
+			}
+		}
+		pattern, text = pattern[1:], text[1:]
+	}
+	return text == ""
+}
@@ -0,0 +1,91 @@
+package config
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+)
+
+func TestAllowedPrincipals(t *testing.T) {
+	key, other := newTestPublicKey(t), newTestPublicKey(t)
+	authorized := func(k ssh.PublicKey) string {
+		return strings.TrimSpace(string(ssh.MarshalAuthorizedKey(k)))
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{name: "listed", content: "jane@example.com " + authorized(key) + " laptop\n", want: []string{"jane@example.com"}},
+		{name: "several principals", content: "jane@example.com,*@corp.example " + authorized(key), want: []string{"jane@example.com", "*@corp.example"}},
+		{name: "quoted principals", content: `"jane@example.com,j@example.com" ` + authorized(key), want: []string{"jane@example.com", "j@example.com"}},
+		{name: "several lines", content: "# team\n\njane@example.com " + authorized(key) + "\njohn@example.com " + authorized(other) + "\nj@example.com " + authorized(key), want: []string{"jane@example.com", "j@example.com"}},
+		{name: "git namespace", content: `jane@example.com namespaces="git,file" ` + authorized(key), want: []string{"jane@example.com"}},
+		{name: "other namespace", content: `jane@example.com namespaces="file" ` + authorized(key)},
+		{name: "certificate authority", content: "*@example.com cert-authority " + authorized(key)},
+		{name: "not listed", content: "john@example.com " + authorized(other)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "allowed_signers")
+			if err := os.WriteFile(path, []byte(tt.content), 0600); err != nil {
+				t.Fatalf("Failed to write allowed signers: %v", err)
+			}
+			got, err := AllowedPrincipals(path, key)
+			if err != nil {
+				t.Fatalf("AllowedPrincipals() error = %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("AllowedPrincipals() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := AllowedPrincipals(filepath.Join(t.TempDir(), "missing"), key); err == nil {
+		t.Error("AllowedPrincipals() of a missing file: expected an error")
+	}
+}
+
+func TestMatchPrincipal(t *testing.T) {
+	tests := []struct {
+		principals []string
+		email      string
+		want       bool
+	}{
+		{principals: []string{"jane@example.com"}, email: "jane@example.com", want: true},
+		{principals: []string{"jane@example.com"}, email: "jane@corp.example", want: false},
+		{principals: []string{"john@example.com", "*@corp.example"}, email: "jane@corp.example", want: true},
+		{principals: []string{"jane@example.co?"}, email: "jane@example.com", want: true},
+		{principals: []string{"*@example.com", "!bot@example.com"}, email: "bot@example.com", want: false},
+		{principals: []string{"Jane@example.com"}, email: "jane@example.com", want: false},
+		{principals: nil, email: "jane@example.com", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := MatchPrincipal(tt.principals, tt.email); got != tt.want {
+			t.Errorf("MatchPrincipal(%q, %q) = %v, want %v", tt.principals, tt.email, got, tt.want)
+		}
+	}
+}
+
+// newTestPublicKey returns a new ed25519 public key
+func newTestPublicKey(t *testing.T) ssh.PublicKey {
+	t.Helper()
+	public, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	key, err := ssh.NewPublicKey(public)
+	if err != nil {
+		t.Fatalf("failed to convert key: %v", err)
+	}
+	return key
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/go-git/gcfg/v2"
 	"github.com/golgoth31/gitcomm/internal/utils"
+	"github.com/golgoth31/gitcomm/pkg/git/gitdir"
 )
 
 // GitConfig represents extracted git configuration values from .git/config and ~/.gitconfig files
@@ -15,14 +16,28 @@ type GitConfig struct {
 	UserEmail     string
 	SigningKey    string
 	GPGFormat     string
+	GPGProgram    string
 	CommitGPGSign bool
+
+	// CommitGPGSignSet reports that commit.gpgsign is set, telling an explicit false
+	// from an unset value
+	CommitGPGSignSet bool
+
+	// SSHProgram is the program signing with SSH keys (gpg.ssh.program, default "ssh-keygen")
+	SSHProgram string
+
+	// SSHAllowedSignersFile maps principals (emails) to the SSH keys trusted for them
+	// (gpg.ssh.allowedSignersFile)
+	SSHAllowedSignersFile string
 }
 
 // CommitSigner represents the configured commit signer extracted from git config
 type CommitSigner struct {
 	PrivateKeyPath string // Path to private key (for env var setup)
 	PublicKeyPath  string // Path to public key (user.signingkey)
-	Format         string // Signing format ("ssh", "gpg")
+	KeyID          string // OpenPGP key ID or fingerprint (user.signingkey)
+	Program        string // Signing program (gpg.program, default "gpg"; gpg.ssh.program for SSH)
+	Format         string // Signing format ("ssh", "openpgp")
 	Enabled        bool   // Whether signing is enabled
 }
 
@@ -41,8 +56,11 @@ type gitConfigFile struct {
 		Email      string
 		SigningKey string
 	}
-	GPG struct {
-		Format string
+	// GPG holds [gpg] under the "" key and [gpg "ssh"] under "ssh"
+	GPG map[string]*struct {
+		Format             string
+		Program            string
+		AllowedSignersFile string
 	}
 	Commit struct {
 		GPGSign string
@@ -57,7 +75,7 @@ func NewFileConfigExtractor() ConfigExtractor {
 	return &FileConfigExtractor{}
 }
 
-// Extract reads git configuration from .git/config and ~/.gitconfig
+// Extract reads git configuration from the repository config file and ~/.gitconfig
 // Returns extracted config values, with local config taking precedence
 func (e *FileConfigExtractor) Extract(repoPath string) *GitConfig {
 	config := &GitConfig{
@@ -69,7 +87,7 @@ func (e *FileConfigExtractor) Extract(repoPath string) *GitConfig {
 	}
 
 	// Try to read local config first
-	localConfigPath := filepath.Join(repoPath, ".git", "config")
+	localConfigPath := localConfigPath(repoPath)
 	if err := e.readConfigFile(localConfigPath, config, true); err != nil {
 		utils.Logger.Debug().Err(err).Str("path", localConfigPath).Msg("Failed to read local git config, will try global config")
 	}
@@ -89,6 +107,16 @@ func (e *FileConfigExtractor) Extract(repoPath string) *GitConfig {
 	return config
 }
 
+// localConfigPath returns the config file of the repository at repoPath, in the git
+// directory shared by its worktrees: linked worktrees and submodules reach it through
+// their .git file, bare repositories hold it directly
+func localConfigPath(repoPath string) string {
+	if location, err := gitdir.Find(repoPath); err == nil {
+		return filepath.Join(location.CommonDir, "config")
+	}
+	return filepath.Join(repoPath, ".git", "config")
+}
+
 // readConfigFile reads a git config file and merges values into config
 // If isLocal is true, values override existing config (precedence)
 // If isLocal is false, values only fill in missing fields
@@ -131,26 +159,34 @@ func (e *FileConfigExtractor) readConfigFile(path string, config *GitConfig, isL
 			config.SigningKey = cfg.User.SigningKey
 		}
 	}
-	if isLocal || config.GPGFormat == "" {
-		if cfg.GPG.Format != "" {
-			config.GPGFormat = cfg.GPG.Format
+	if gpg := cfg.GPG[""]; gpg != nil {
+		if isLocal || config.GPGFormat == "" {
+			if gpg.Format != "" {
+				config.GPGFormat = gpg.Format
+			}
+		}
+		if isLocal || config.GPGProgram == "" {
+			if gpg.Program != "" {
+				config.GPGProgram = gpg.Program
+			}
 		}
 	}
-	// For commit.gpgsign: local config takes precedence
-	// Only update if isLocal (local config) or if global and value hasn't been set by local
-	// Since we can't track if local set it, we use a simpler approach:
-	// - Local: always update
-	// - Global: only update if we're reading global AND local config file didn't exist
-	//   But we don't have that info here. For now, we'll only update from global in the gcfg path
-	//   if local didn't set it. In manual path, we'll track it differently.
-	if isLocal {
-		// Local config: always update (takes precedence)
-		if cfg.Commit.GPGSign != "" {
-			config.CommitGPGSign = strings.ToLower(cfg.Commit.GPGSign) == "true"
+	if gpgSSH := cfg.GPG["ssh"]; gpgSSH != nil {
+		if isLocal || config.SSHProgram == "" {
+			if gpgSSH.Program != "" {
+				config.SSHProgram = gpgSSH.Program
+			}
+		}
+		if isLocal || config.SSHAllowedSignersFile == "" {
+			if gpgSSH.AllowedSignersFile != "" {
+				config.SSHAllowedSignersFile = gpgSSH.AllowedSignersFile
+			}
 		}
 	}
-	// Note: We don't update commit.gpgsign from global config here to avoid overwriting local values
-	// Global commit.gpgsign will be handled in the manual parser with proper precedence tracking
+	// commit.gpgsign: the local value takes precedence over the global one
+	if cfg.Commit.GPGSign != "" {
+		setCommitGPGSign(config, cfg.Commit.GPGSign, isLocal)
+	}
 
 	return nil
 }
@@ -165,7 +201,7 @@ func (e *FileConfigExtractor) readConfigFileManual(path string, config *GitConfi
 
 	lines := strings.Split(string(data), "\n")
 	var currentSection string
-	var inUserSection, inGPGSection, inCommitSection bool
+	var inUserSection, inGPGSection, inGPGSSHSection, inCommitSection bool
 
 	for _, line := range lines {
 		line = strings.TrimSpace(line)
@@ -177,9 +213,10 @@ func (e *FileConfigExtractor) readConfigFileManual(path string, config *GitConfi
 
 		// Check for section headers
 		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
-			currentSection = strings.ToLower(strings.Trim(line, "[]"))
+			currentSection = strings.ToLower(strings.Join(strings.Fields(strings.Trim(line, "[]")), " "))
 			inUserSection = currentSection == "user"
 			inGPGSection = currentSection == "gpg"
+			inGPGSSHSection = currentSection == `gpg "ssh"`
 			inCommitSection = currentSection == "commit"
 			continue
 		}
@@ -204,19 +241,18 @@ func (e *FileConfigExtractor) readConfigFileManual(path string, config *GitConfi
 			} else if inGPGSection {
 				if key == "format" && (isLocal || config.GPGFormat == "") {
 					config.GPGFormat = value
+				} else if key == "program" && (isLocal || config.GPGProgram == "") {
+					config.GPGProgram = value
+				}
+			} else if inGPGSSHSection {
+				if key == "program" && (isLocal || config.SSHProgram == "") {
+					config.SSHProgram = value
+				} else if key == "allowedsignersfile" && (isLocal || config.SSHAllowedSignersFile == "") {
+					config.SSHAllowedSignersFile = value
 				}
 			} else if inCommitSection {
 				if key == "gpgsign" {
-					// Parse commit.gpgsign (can be "true", "false", or empty)
-					// Local config always takes precedence
-					if isLocal {
-						lowerValue := strings.ToLower(value)
-						if lowerValue == "true" {
-							config.CommitGPGSign = true
-						}
-					}
-					// Don't update from global config here - it will be handled by
-					// readCommitGPGSignFromLocal() to ensure local precedence
+					setCommitGPGSign(config, value, isLocal)
 				}
 			}
 		}
@@ -224,3 +260,20 @@ func (e *FileConfigExtractor) readConfigFileManual(path string, config *GitConfi
 
 	return nil
 }
+
+// setCommitGPGSign merges the commit.gpgsign value read from a config file: a local value
+// always wins, a global one only applies when the local config does not set it
+func setCommitGPGSign(config *GitConfig, value string, isLocal bool) {
+	if !isLocal && config.CommitGPGSignSet {
+		return
+	}
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "true", "yes", "on", "1":
+		config.CommitGPGSign = true
+	case "false", "no", "off", "0", "":
+		config.CommitGPGSign = false
+	default:
+		return
+	}
+	config.CommitGPGSignSet = true
+}
@@ -274,6 +274,82 @@ func TestFileConfigExtractor_Extract_SSHSigningConfiguration(t *testing.T) {
 	}
 }
 
+func TestFileConfigExtractor_Extract_SSHSubsection(t *testing.T) {
+	// Setup: Initialize logger for debug messages
+	utils.InitLogger(true)
+
+	// The unknown [core] section makes gcfg fail: both parsers must find the values
+	for _, extra := range []string{"", "[core]\n\tbare = false\n"} {
+		tmpDir := t.TempDir()
+		gitDir := filepath.Join(tmpDir, ".git")
+		os.MkdirAll(gitDir, 0755)
+
+		configContent := `[user]
+	email = test@example.com
+	signingkey = ~/.ssh/id_ed25519.pub
+[gpg]
+	format = ssh
+[gpg "ssh"]
+	program = /opt/1password/op-ssh-sign
+	allowedSignersFile = ~/.config/git/allowed_signers
+` + extra
+		configPath := filepath.Join(gitDir, "config")
+		os.WriteFile(configPath, []byte(configContent), 0644)
+
+		extractor := NewFileConfigExtractor()
+		config := extractor.Extract(tmpDir)
+
+		if config.GPGFormat != "ssh" {
+			t.Errorf("Expected GPGFormat 'ssh', got '%s'", config.GPGFormat)
+		}
+		if config.GPGProgram != "" {
+			t.Errorf("Expected no GPGProgram, got '%s'", config.GPGProgram)
+		}
+		if config.SSHProgram != "/opt/1password/op-ssh-sign" {
+			t.Errorf("Expected SSHProgram '/opt/1password/op-ssh-sign', got '%s'", config.SSHProgram)
+		}
+		if config.SSHAllowedSignersFile != "~/.config/git/allowed_signers" {
+			t.Errorf("Expected SSHAllowedSignersFile '~/.config/git/allowed_signers', got '%s'", config.SSHAllowedSignersFile)
+		}
+	}
+}
+
+func TestFileConfigExtractor_Extract_OpenPGPSigningConfiguration(t *testing.T) {
+	// Setup: Initialize logger for debug messages
+	utils.InitLogger(true)
+
+	// The program of the [gpg "x509"] subsection must not override the one of [gpg]
+	for _, extra := range []string{"", "[gpg \"x509\"]\n\tprogram = gpgsm\n"} {
+		tmpDir := t.TempDir()
+		gitDir := filepath.Join(tmpDir, ".git")
+		os.MkdirAll(gitDir, 0755)
+
+		configContent := `[user]
+	name = Test User
+	email = test@example.com
+	signingkey = 3AA5C34371567BD2
+[gpg]
+	format = openpgp
+	program = /usr/local/bin/gpg2
+` + extra
+		configPath := filepath.Join(gitDir, "config")
+		os.WriteFile(configPath, []byte(configContent), 0644)
+
+		extractor := NewFileConfigExtractor()
+		config := extractor.Extract(tmpDir)
+
+		if config.SigningKey != "3AA5C34371567BD2" {
+			t.Errorf("Expected SigningKey '3AA5C34371567BD2', got '%s'", config.SigningKey)
+		}
+		if config.GPGFormat != "openpgp" {
+			t.Errorf("Expected GPGFormat 'openpgp', got '%s'", config.GPGFormat)
+		}
+		if config.GPGProgram != "/usr/local/bin/gpg2" {
+			t.Errorf("Expected GPGProgram '/usr/local/bin/gpg2', got '%s'", config.GPGProgram)
+		}
+	}
+}
+
 func TestFileConfigExtractor_Extract_CommitGPGSignFalse(t *testing.T) {
 	// Setup: Initialize logger for debug messages
 	utils.InitLogger(true)
@@ -302,3 +378,39 @@ func TestFileConfigExtractor_Extract_CommitGPGSignFalse(t *testing.T) {
 		t.Errorf("Expected CommitGPGSign false, got %v", config.CommitGPGSign)
 	}
 }
+
+func TestFileConfigExtractor_Extract_CommitGPGSignPrecedence(t *testing.T) {
+	utils.InitLogger(true)
+
+	tests := []struct {
+		name    string
+		local   string
+		global  string
+		want    bool
+		wantSet bool
+	}{
+		{name: "unset", want: false, wantSet: false},
+		{name: "global only", global: "[commit]\n\tgpgsign = true\n", want: true, wantSet: true},
+		{name: "local false over global true", local: "[commit]\n\tgpgsign = false\n", global: "[commit]\n\tgpgsign = true\n", want: false, wantSet: true},
+		{name: "local yes", local: "[commit]\n\tgpgsign = yes\n", want: true, wantSet: true},
+		{name: "local off, unknown section", local: "[core]\n\tbare = false\n[commit]\n\tgpgsign = off\n", global: "[commit]\n\tgpgsign = true\n", want: false, wantSet: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			homeDir := t.TempDir()
+			t.Setenv("HOME", homeDir)
+			if tt.global != "" {
+				os.WriteFile(filepath.Join(homeDir, ".gitconfig"), []byte(tt.global), 0644)
+			}
+			tmpDir := t.TempDir()
+			os.MkdirAll(filepath.Join(tmpDir, ".git"), 0755)
+			os.WriteFile(filepath.Join(tmpDir, ".git", "config"), []byte("[user]\n\temail = test@example.com\n"+tt.local), 0644)
+
+			config := NewFileConfigExtractor().Extract(tmpDir)
+			if config.CommitGPGSign != tt.want || config.CommitGPGSignSet != tt.wantSet {
+				t.Errorf("CommitGPGSign = %v (set %v), want %v (set %v)", config.CommitGPGSign, config.CommitGPGSignSet, tt.want, tt.wantSet)
+			}
+		})
+	}
+}
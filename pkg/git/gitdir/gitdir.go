@@ -0,0 +1,134 @@
+// Package gitdir locates the git directory of a repository like git does: a .git
+// directory, a .git file pointing to the git directory of a linked worktree or submodule
+// ("gitdir: <path>"), a bare repository, or the GIT_DIR and GIT_WORK_TREE variables.
+package gitdir
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrNotFound indicates that no repository contains the directory
+var ErrNotFound = errors.New("not a git repository")
+
+// Location is where a repository was found
+type Location struct {
+	// WorkTree is the top-level directory of the worktree ("" for a bare repository)
+	WorkTree string
+	// GitDir is the git directory of the worktree (.git, or .git/worktrees/<name> for a
+	// linked worktree)
+	GitDir string
+	// CommonDir is the git directory shared by all worktrees, holding the config file
+	CommonDir string
+}
+
+// Bare reports whether the repository has no worktree
+func (l *Location) Bare() bool {
+	return l.WorkTree == ""
+}
+
+// Find returns the repository containing dir, looking at dir and its parents like git
+// does. GIT_DIR, when set, names the git directory, with GIT_WORK_TREE as the worktree
+// (dir when unset). Paths are absolute.
+func Find(dir string) (*Location, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if env := os.Getenv("GIT_DIR"); env != "" {
+		gitDir, err := filepath.Abs(env)
+		if err != nil {
+			return nil, err
+		}
+		if !IsGitDir(gitDir) {
+			return nil, fmt.Errorf("%w: GIT_DIR=%s", ErrNotFound, env)
+		}
+		workTree := dir
+		if env := os.Getenv("GIT_WORK_TREE"); env != "" {
+			if workTree, err = filepath.Abs(env); err != nil {
+				return nil, err
+			}
+		}
+		return newLocation(workTree, gitDir), nil
+	}
+
+	for current := dir; ; current = filepath.Dir(current) {
+		dotGit := filepath.Join(current, ".git")
+		if info, err := os.Stat(dotGit); err == nil {
+			gitDir := dotGit
+			if !info.IsDir() {
+				if gitDir, err = Read(dotGit); err != nil {
+					return nil, err
+				}
+			}
+			if IsGitDir(gitDir) {
+				return newLocation(current, gitDir), nil
+			}
+		}
+		// A bare repository, or the inside of a .git directory
+		if IsGitDir(current) {
+			return newLocation("", current), nil
+		}
+		if parent := filepath.Dir(current); parent == current {
+			return nil, ErrNotFound
+		}
+	}
+}
+
+// newLocation returns the location of gitDir, with its common directory
+func newLocation(workTree, gitDir string) *Location {
+	return &Location{WorkTree: workTree, GitDir: gitDir, CommonDir: CommonDir(gitDir)}
+}
+
+// Read returns the git directory named by a .git file ("gitdir: <path>"), relative paths
+// being relative to the directory of the file
+func Read(dotGit string) (string, error) {
+	content, err := os.ReadFile(dotGit)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", dotGit, err)
+	}
+	path, ok := strings.CutPrefix(strings.TrimSpace(string(content)), "gitdir:")
+	if path = strings.TrimSpace(path); !ok || path == "" {
+		return "", fmt.Errorf("invalid gitfile format: %s", dotGit)
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(filepath.Dir(dotGit), path)
+	}
+	return filepath.Clean(path), nil
+}
+
+// CommonDir returns the git directory shared by the worktrees of gitDir: the one named by
+// its commondir file in a linked worktree, gitDir itself otherwise
+func CommonDir(gitDir string) string {
+	content, err := os.ReadFile(filepath.Join(gitDir, "commondir"))
+	if err != nil {
+		return gitDir
+	}
+	path := strings.TrimSpace(string(content))
+	if path == "" {
+		return gitDir
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(gitDir, path)
+	}
+	return filepath.Clean(path)
+}
+
+// IsGitDir reports whether dir looks like a git directory: a HEAD file with objects and
+// refs directories, in dir or in its common directory
+func IsGitDir(dir string) bool {
+	if info, err := os.Stat(filepath.Join(dir, "HEAD")); err != nil || info.IsDir() {
+		return false
+	}
+	common := CommonDir(dir)
+	for _, name := range []string{"objects", "refs"} {
+		if info, err := os.Stat(filepath.Join(common, name)); err != nil || !info.IsDir() {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,168 @@
+package gitdir
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golgoth31/gitcomm/pkg/testutil"
+)
+
+// realPath resolves symbolic links in path (macOS temporary directories)
+func realPath(t *testing.T, path string) string {
+	t.Helper()
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		t.Fatalf("EvalSymlinks(%s) error = %v", path, err)
+	}
+	return resolved
+}
+
+// unsetGitEnv unsets GIT_DIR and GIT_WORK_TREE for the test
+func unsetGitEnv(t *testing.T) {
+	t.Helper()
+	for _, name := range []string{"GIT_DIR", "GIT_WORK_TREE"} {
+		t.Setenv(name, "") // restored after the test
+		os.Unsetenv(name)
+	}
+}
+
+func TestFind(t *testing.T) {
+	unsetGitEnv(t)
+
+	main := testutil.NewRepoWithCommit(t, map[string]string{"src/main.go": "package main\n"})
+	main.AddSubmodule("vendor/lib")
+	mainDir := realPath(t, main.Dir)
+	gitDir := filepath.Join(mainDir, ".git")
+
+	linked := filepath.Join(realPath(t, t.TempDir()), "linked")
+	main.Git("worktree", "add", "-q", linked)
+	bare := filepath.Join(realPath(t, t.TempDir()), "bare.git")
+	main.Git("clone", "-q", "--bare", main.Dir, bare)
+
+	tests := []struct {
+		name string
+		dir  string
+		want Location
+	}{
+		{
+			name: "worktree root",
+			dir:  mainDir,
+			want: Location{WorkTree: mainDir, GitDir: gitDir, CommonDir: gitDir},
+		},
+		{
+			name: "worktree subdirectory",
+			dir:  filepath.Join(mainDir, "src"),
+			want: Location{WorkTree: mainDir, GitDir: gitDir, CommonDir: gitDir},
+		},
+		{
+			name: "linked worktree",
+			dir:  linked,
+			want: Location{WorkTree: linked, GitDir: filepath.Join(gitDir, "worktrees", "linked"), CommonDir: gitDir},
+		},
+		{
+			name: "submodule",
+			dir:  filepath.Join(mainDir, "vendor", "lib"),
+			want: Location{
+				WorkTree:  filepath.Join(mainDir, "vendor", "lib"),
+				GitDir:    filepath.Join(gitDir, "modules", "vendor", "lib"),
+				CommonDir: filepath.Join(gitDir, "modules", "vendor", "lib"),
+			},
+		},
+		{
+			name: "bare repository",
+			dir:  filepath.Join(bare, "refs", "heads"),
+			want: Location{GitDir: bare, CommonDir: bare},
+		},
+		{
+			name: "inside the git directory",
+			dir:  filepath.Join(gitDir, "hooks"),
+			want: Location{GitDir: gitDir, CommonDir: gitDir},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Find(tt.dir)
+			if err != nil {
+				t.Fatalf("Find() error = %v", err)
+			}
+			if *got != tt.want {
+				t.Errorf("Find() = %+v, want %+v", *got, tt.want)
+			}
+			if got.Bare() != (tt.want.WorkTree == "") {
+				t.Errorf("Bare() = %v", got.Bare())
+			}
+		})
+	}
+}
+
+func TestFind_Environment(t *testing.T) {
+	unsetGitEnv(t)
+	main := testutil.NewRepoWithCommit(t, map[string]string{"README.md": "# test\n"})
+	gitDir := filepath.Join(main.Dir, ".git")
+	elsewhere := t.TempDir()
+
+	t.Setenv("GIT_DIR", gitDir)
+	got, err := Find(elsewhere)
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+	if got.GitDir != gitDir || got.WorkTree != elsewhere {
+		t.Errorf("Find() with GIT_DIR = %+v, want git directory %s and worktree %s", got, gitDir, elsewhere)
+	}
+
+	t.Setenv("GIT_WORK_TREE", main.Dir)
+	if got, err = Find(elsewhere); err != nil || got.WorkTree != main.Dir {
+		t.Errorf("Find() with GIT_WORK_TREE = %+v, %v, want worktree %s", got, err, main.Dir)
+	}
+
+	t.Setenv("GIT_DIR", elsewhere)
+	if _, err := Find(main.Dir); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Find() with GIT_DIR naming no repository error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestFind_NotFound(t *testing.T) {
+	unsetGitEnv(t)
+	dir := t.TempDir()
+	// a .git file pointing nowhere does not make a repository
+	if err := os.WriteFile(filepath.Join(dir, ".git"), []byte("gitdir: missing\n"), 0644); err != nil {
+		t.Fatalf("failed to write .git file: %v", err)
+	}
+	if _, err := Find(dir); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Find() error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestRead(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		wantErr bool
+	}{
+		{name: "relative", content: "gitdir: ../.git/worktrees/api\n", want: filepath.Join(filepath.Dir(dir), ".git", "worktrees", "api")},
+		{name: "absolute", content: "gitdir: /src/app/.git/modules/lib", want: filepath.FromSlash("/src/app/.git/modules/lib")},
+		{name: "empty path", content: "gitdir:\n", wantErr: true},
+		{name: "not a gitfile", content: "ref: refs/heads/main\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, ".git")
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatalf("failed to write .git file: %v", err)
+			}
+			got, err := Read(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Read() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Read() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+// Package remote resolves where a branch is pushed in centralized and triangular (fork) workflows
+// and builds the compare / pull request creation URL of hosted remotes.
+package remote
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// upstreamRemote is the conventional name of the original repository in fork workflows
+const upstreamRemote = "upstream"
+
+// Target describes where a local branch is pushed
+type Target struct {
+	// LocalBranch is the branch being pushed
+	LocalBranch string
+	// Remote is the remote receiving the push
+	Remote string
+	// RemoteBranch is the branch name on Remote
+	RemoteBranch string
+	// SetUpstream is true when the branch has no upstream yet and the push should set it
+	SetUpstream bool
+	// Triangular is true when changes are pushed to a different remote than the one pulled from
+	// (branch.<name>.pushRemote, remote.pushDefault or a fork next to an "upstream" remote)
+	Triangular bool
+	// BaseRemote is the remote a pull request targets
+	BaseRemote string
+	// BaseBranch is the branch a pull request targets ("" for the remote's default branch)
+	BaseBranch string
+}
+
+// Lookup returns the value of a git config key, or "" when unset
+type Lookup func(key string) string
+
+// Resolve computes the push target of branch following git's rules:
+// branch.<name>.pushRemote, then remote.pushDefault, then branch.<name>.remote, then origin
+// (or the only remote). remotes lists the configured remote names.
+func Resolve(branch string, config Lookup, remotes []string) (*Target, error) {
+	if branch == "" {
+		return nil, fmt.Errorf("cannot push a detached HEAD")
+	}
+	if len(remotes) == 0 {
+		return nil, fmt.Errorf("no remote configured")
+	}
+
+	upstream := config("branch." + branch + ".remote")
+	merge := strings.TrimPrefix(config("branch."+branch+".merge"), "refs/heads/")
+
+	pushRemote := firstNonEmpty(
+		config("branch."+branch+".pushRemote"),
+		config("remote.pushDefault"),
+		upstream,
+	)
+	if pushRemote == "" {
+		switch {
+		case slices.Contains(remotes, "origin"):
+			pushRemote = "origin"
+		case len(remotes) == 1:
+			pushRemote = remotes[0]
+		default:
+			return nil, fmt.Errorf("cannot choose between remotes %s: set remote.pushDefault", strings.Join(remotes, ", "))
+		}
+	}
+	if !slices.Contains(remotes, pushRemote) {
+		return nil, fmt.Errorf("push remote %q is not configured", pushRemote)
+	}
+
+	target := &Target{
+		LocalBranch:  branch,
+		Remote:       pushRemote,
+		RemoteBranch: branch,
+		SetUpstream:  upstream == "",
+		BaseRemote:   pushRemote,
+	}
+
+	switch {
+	case upstream != "" && upstream != pushRemote:
+		// Pull from upstream, push to a fork: the pull request targets the tracked branch
+		target.Triangular = true
+		target.BaseRemote = upstream
+		target.BaseBranch = merge
+	case upstream == "" && pushRemote != upstreamRemote && slices.Contains(remotes, upstreamRemote):
+		// Fork checkout without tracking: the pull request targets the upstream default branch
+		target.Triangular = true
+		target.BaseRemote = upstreamRemote
+	case merge != "":
+		// Centralized workflow: push back to the tracked branch
+		target.RemoteBranch = merge
+	}
+
+	return target, nil
+}
+
+// Repository identifies a hosted repository
+type Repository struct {
+	// Host is the server name (e.g. github.com)
+	Host string
+	// Path is the repository path without .git (e.g. golgoth31/gitcomm)
+	Path string
+}
+
+// Owner returns the first path element (user, organization or top-level group)
+func (r Repository) Owner() string {
+	owner, _, _ := strings.Cut(r.Path, "/")
+	return owner
+}
+
+// ParseURL parses https, ssh and scp-like ("git@host:owner/repo.git") remote URLs
+func ParseURL(raw string) (*Repository, error) {
+	raw = strings.TrimSpace(raw)
+	var host, path string
+
+	if strings.Contains(raw, "://") {
+		u, err := url.Parse(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid remote URL %q: %w", raw, err)
+		}
+		host, path = u.Hostname(), u.Path
+	} else if at, rest, ok := strings.Cut(raw, ":"); ok && !strings.Contains(at, "/") {
+		// scp-like syntax: [user@]host:path
+		if i := strings.LastIndex(at, "@"); i >= 0 {
+			at = at[i+1:]
+		}
+		host, path = at, rest
+	} else {
+		return nil, fmt.Errorf("unsupported remote URL %q", raw)
+	}
+
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	if host == "" || !strings.Contains(path, "/") {
+		return nil, fmt.Errorf("unsupported remote URL %q", raw)
+	}
+	return &Repository{Host: strings.ToLower(host), Path: path}, nil
+}
+
+// CompareURL returns the pull (GitHub) or merge (GitLab) request creation URL for pushing
+// headBranch of head against baseBranch of base, or "" for other hosts.
+// An empty baseBranch targets the default branch.
+func CompareURL(base, head *Repository, baseBranch, headBranch string) string {
+	switch {
+	case strings.Contains(base.Host, "github"):
+		ref := headBranch
+		if head.Path != base.Path {
+			ref = head.Owner() + ":" + headBranch
+		}
+		if baseBranch != "" {
+			ref = baseBranch + "..." + ref
+		}
+		return fmt.Sprintf("https://%s/%s/compare/%s?expand=1", base.Host, base.Path, escapeRef(ref))
+	case strings.Contains(head.Host, "gitlab"):
+		// Merge requests are created from the source project; GitLab preselects its fork parent
+		query := url.Values{}
+		query.Set("merge_request[source_branch]", headBranch)
+		if baseBranch != "" {
+			query.Set("merge_request[target_branch]", baseBranch)
+		}
+		return fmt.Sprintf("https://%s/%s/-/merge_requests/new?%s", head.Host, head.Path, query.Encode())
+	default:
+		return ""
+	}
+}
+
+// escapeRef escapes a compare ref for a URL path, keeping the slashes of branch names
+func escapeRef(ref string) string {
+	return strings.ReplaceAll(url.PathEscape(ref), "%2F", "/")
+}
+
+// firstNonEmpty returns the first non-empty value
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,196 @@
+// Package goapi detects changes to the exported API of Go packages from unified diffs.
+package goapi
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+)
+
+var (
+	// funcDeclRegex matches function and method declarations: func Name( / func (r *T) Name(
+	funcDeclRegex = regexp.MustCompile(`^func\s+(?:\(\s*\w*\s*\*?\s*(\w+)(?:\[[^\]]*\])?\s*\)\s*)?(\w+)\s*[\[(]`)
+
+	// declRegex matches single type, var and const declarations
+	declRegex = regexp.MustCompile(`^(?:type|var|const)\s+(\w+)`)
+
+	// memberRegex matches a struct field, interface method or grouped var/const/type spec
+	// indented by exactly one tab
+	memberRegex = regexp.MustCompile(`^\t(\w+)(?:\s*\(|\s+[\w*\[\]{}.]|\s*=)`)
+
+	// scopeRegex matches the opening line of a struct/interface type or of a grouped declaration
+	scopeRegex = regexp.MustCompile(`^(?:type\s+(\w+)\s+(?:struct|interface)\s*\{|(?:const|var|type)\s*\()`)
+)
+
+// groupScope marks a grouped const/var/type declaration, whose members are not qualified
+const groupScope = "("
+
+// PackageChange lists the exported identifiers added, removed or changed in one package
+type PackageChange struct {
+	// Package is the package directory relative to the repository root ("." for the root)
+	Package string
+
+	// Added, Removed and Changed are sorted exported identifiers (e.g. "Client", "Client.Retry")
+	Added   []string
+	Removed []string
+	Changed []string
+}
+
+// Analyze returns the exported API changes found in the diffs of non-test Go files,
+// sorted by package. Identifiers both removed and added are reported as changed.
+func Analyze(files []model.FileChange) []PackageChange {
+	type idSets struct{ added, removed map[string]bool }
+	byPackage := make(map[string]*idSets)
+
+	for _, file := range files {
+		if !strings.HasSuffix(file.Path, ".go") || strings.HasSuffix(file.Path, "_test.go") || file.Diff == "" {
+			continue
+		}
+		pkg := path.Dir(file.Path)
+		sets, ok := byPackage[pkg]
+		if !ok {
+			sets = &idSets{added: make(map[string]bool), removed: make(map[string]bool)}
+			byPackage[pkg] = sets
+		}
+
+		// scope is the enclosing struct/interface (or groupScope) of the current line.
+		// git puts the enclosing top-level line in the hunk header, e.g. "@@ -1 +1 @@ type T struct {"
+		scope := ""
+		for _, line := range strings.Split(file.Diff, "\n") {
+			if strings.HasPrefix(line, "@@") {
+				scope = ""
+				if _, context, ok := strings.Cut(strings.TrimPrefix(line, "@@"), "@@"); ok {
+					scope = openedScope(strings.TrimSpace(context), scope)
+				}
+				continue
+			}
+			if strings.HasPrefix(line, "+++") || strings.HasPrefix(line, "---") || line == "" {
+				continue
+			}
+
+			target := sets.added
+			switch line[0] {
+			case '+':
+			case '-':
+				target = sets.removed
+			default:
+				continue
+			}
+			code := line[1:]
+
+			if id := exportedIdentifier(code, scope); id != "" {
+				target[id] = true
+			}
+			scope = openedScope(code, scope)
+		}
+	}
+
+	var changes []PackageChange
+	for pkg, sets := range byPackage {
+		change := PackageChange{Package: pkg}
+		for id := range sets.added {
+			if sets.removed[id] {
+				change.Changed = append(change.Changed, id)
+			} else {
+				change.Added = append(change.Added, id)
+			}
+		}
+		for id := range sets.removed {
+			if !sets.added[id] {
+				change.Removed = append(change.Removed, id)
+			}
+		}
+		if len(change.Added)+len(change.Removed)+len(change.Changed) == 0 {
+			continue
+		}
+		sort.Strings(change.Added)
+		sort.Strings(change.Removed)
+		sort.Strings(change.Changed)
+		changes = append(changes, change)
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Package < changes[j].Package })
+	return changes
+}
+
+// openedScope returns the scope after code: the type name or groupScope if code opens
+// a struct/interface or grouped declaration, "" if it closes one, scope otherwise
+func openedScope(code, scope string) string {
+	if m := scopeRegex.FindStringSubmatch(code); m != nil {
+		if m[1] != "" {
+			return m[1]
+		}
+		return groupScope
+	}
+	if code == "}" || code == ")" {
+		return ""
+	}
+	return scope
+}
+
+// exportedIdentifier returns the exported identifier declared by a line of Go code, or "".
+// Members (one-tab indented lines) are only recognised inside a scope, and members of a
+// struct or interface are qualified with its name.
+func exportedIdentifier(code, scope string) string {
+	if m := funcDeclRegex.FindStringSubmatch(code); m != nil {
+		receiver, name := m[1], m[2]
+		if !isExported(name) || (receiver != "" && !isExported(receiver)) {
+			return ""
+		}
+		if receiver != "" {
+			return receiver + "." + name
+		}
+		return name
+	}
+	if m := declRegex.FindStringSubmatch(code); m != nil {
+		if isExported(m[1]) {
+			return m[1]
+		}
+		return ""
+	}
+	if scope == "" {
+		return ""
+	}
+	if m := memberRegex.FindStringSubmatch(code); m != nil && isExported(m[1]) {
+		if scope == groupScope {
+			return m[1]
+		}
+		if isExported(scope) {
+			return scope + "." + m[1]
+		}
+	}
+	return ""
+}
+
+// isExported reports whether name starts with an upper-case ASCII letter
+func isExported(name string) bool {
+	return name != "" && name[0] >= 'A' && name[0] <= 'Z'
+}
+
+// Summary formats changes as a short bullet list for the AI prompt, or "" if there are none
+func Summary(changes []PackageChange) string {
+	if len(changes) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("Exported Go API changes:\n")
+	for _, change := range changes {
+		var parts []string
+		if len(change.Added) > 0 {
+			parts = append(parts, "added "+strings.Join(change.Added, ", "))
+		}
+		if len(change.Changed) > 0 {
+			parts = append(parts, "changed "+strings.Join(change.Changed, ", "))
+		}
+		if len(change.Removed) > 0 {
+			parts = append(parts, "removed "+strings.Join(change.Removed, ", "))
+		}
+		sb.WriteString(fmt.Sprintf("- %s: %s\n", change.Package, strings.Join(parts, "; ")))
+	}
+	return sb.String()
+}
@@ -0,0 +1,106 @@
+package goapi
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+)
+
+func TestAnalyze(t *testing.T) {
+	tests := []struct {
+		name  string
+		files []model.FileChange
+		want  []PackageChange
+	}{
+		{
+			name: "added struct field and option function",
+			files: []model.FileChange{{
+				Path: "pkg/client/client.go",
+				Diff: "diff --git a/pkg/client/client.go b/pkg/client/client.go\n" +
+					"--- a/pkg/client/client.go\n" +
+					"+++ b/pkg/client/client.go\n" +
+					"@@ -12,0 +13 @@ type Client struct {\n" +
+					"+\tRetry int\n" +
+					"+\tbackoff time.Duration\n" +
+					"@@ -40,0 +42,4 @@ func New(opts ...Option) *Client {\n" +
+					"+func WithRetry(n int) Option {\n" +
+					"+\tReturn(n)\n" +
+					"+}\n",
+			}},
+			want: []PackageChange{{Package: "pkg/client", Added: []string{"Client.Retry", "WithRetry"}}},
+		},
+		{
+			name: "changed signature and removed method",
+			files: []model.FileChange{{
+				Path: "internal/repository/git.go",
+				Diff: "@@ -10 +10 @@ package repository\n" +
+					"-func ListCommits(ctx context.Context) error {\n" +
+					"+func ListCommits(ctx context.Context, limit int) error {\n" +
+					"@@ -30,3 +30,0 @@ func ListCommits(ctx context.Context) error {\n" +
+					"-func (r *Repo) Close() error {\n" +
+					"-\treturn nil\n" +
+					"-}\n",
+			}},
+			want: []PackageChange{{Package: "internal/repository", Removed: []string{"Repo.Close"}, Changed: []string{"ListCommits"}}},
+		},
+		{
+			name: "new type with fields and grouped constants",
+			files: []model.FileChange{{
+				Path: "main.go",
+				Diff: "@@ -5,0 +6,8 @@ import (\n" +
+					"+type Options struct {\n" +
+					"+\tVerbose bool\n" +
+					"+}\n" +
+					"+const (\n" +
+					"+\tModeFast = iota\n" +
+					"+\tmodeSlow\n" +
+					"+)\n",
+			}},
+			want: []PackageChange{{Package: ".", Added: []string{"ModeFast", "Options", "Options.Verbose"}}},
+		},
+		{
+			name: "unexported identifiers, tests and non-Go files are ignored",
+			files: []model.FileChange{
+				{Path: "pkg/a/a.go", Diff: "@@ -1 +1 @@\n+func helper() {}\n+func (c *client) Do() {}\n"},
+				{Path: "pkg/a/a_test.go", Diff: "@@ -1 +1 @@\n+func TestA(t *testing.T) {}\n"},
+				{Path: "README.md", Diff: "@@ -1 +1 @@\n+type Foo struct\n"},
+			},
+		},
+		{
+			name: "generic function",
+			files: []model.FileChange{{
+				Path: "pkg/slices/slices.go",
+				Diff: "@@ -1 +1 @@\n+func Map[T, U any](s []T, f func(T) U) []U {\n",
+			}},
+			want: []PackageChange{{Package: "pkg/slices", Added: []string{"Map"}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Analyze(tt.files)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Analyze() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSummary(t *testing.T) {
+	changes := []PackageChange{
+		{Package: "pkg/client", Added: []string{"Client.Retry"}, Removed: []string{"Client.Timeout"}},
+		{Package: "internal/cmd", Changed: []string{"Execute"}},
+	}
+
+	want := "Exported Go API changes:\n" +
+		"- pkg/client: added Client.Retry; removed Client.Timeout\n" +
+		"- internal/cmd: changed Execute\n"
+	if got := Summary(changes); got != want {
+		t.Errorf("Summary() = %q, want %q", got, want)
+	}
+
+	if got := Summary(nil); got != "" {
+		t.Errorf("Summary(nil) = %q, want empty", got)
+	}
+}
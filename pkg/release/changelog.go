@@ -0,0 +1,122 @@
+package release
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Commit is a commit listed in a changelog
+type Commit struct {
+	// ShortHash is the abbreviated hash shown after the subject
+	ShortHash string
+	// Message is the full commit message
+	Message string
+}
+
+// Changelog sections, in the order they are rendered
+const (
+	SectionBreaking = "⚠ BREAKING CHANGES"
+	SectionFeatures = "Features"
+	SectionFixes    = "Bug Fixes"
+	SectionPerf     = "Performance Improvements"
+	SectionReverts  = "Reverts"
+	SectionOther    = "Other Changes"
+)
+
+var sections = []string{SectionBreaking, SectionFeatures, SectionFixes, SectionPerf, SectionReverts, SectionOther}
+
+// breakingNotePattern captures the note of a BREAKING CHANGE footer, up to the end of
+// its paragraph
+var breakingNotePattern = regexp.MustCompile(`(?ms)^BREAKING[ -]CHANGE:\s*(.+?)\s*(?:\n\n|\z)`)
+
+// Changelog renders the markdown release notes of commits under title, grouped like the
+// conventional-changelog preset of semantic-release: breaking changes, features, fixes,
+// performance improvements and reverts. The other commits are listed under "Other
+// Changes" with all only; commits skipped by the analysis ([skip release]) never are.
+// Entries keep the order of commits. Returns "" when no commit is listed.
+func Changelog(title string, commits []Commit, all bool) string {
+	grouped := make(map[string][]string)
+	for _, commit := range commits {
+		for section, entry := range changelogEntries(commit) {
+			if section == SectionOther && !all {
+				continue
+			}
+			grouped[section] = append(grouped[section], entry)
+		}
+	}
+	if len(grouped) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("## " + title + "\n")
+	for _, section := range sections {
+		if len(grouped[section]) == 0 {
+			continue
+		}
+		sb.WriteString("\n### " + section + "\n\n")
+		for _, entry := range grouped[section] {
+			sb.WriteString("- " + entry + "\n")
+		}
+	}
+	return sb.String()
+}
+
+// changelogEntries returns the changelog lines of commit by section: its type section,
+// plus the breaking changes section for a breaking change
+func changelogEntries(commit Commit) map[string]string {
+	message := strings.TrimSpace(commit.Message)
+	if skipPattern.MatchString(message) {
+		return nil
+	}
+	header, _, _ := strings.Cut(message, "\n")
+	suffix := ""
+	if commit.ShortHash != "" {
+		suffix = " (" + commit.ShortHash + ")"
+	}
+
+	match := headerPattern.FindStringSubmatch(header)
+	if match == nil {
+		section := SectionOther
+		if revertPattern.MatchString(message) {
+			section = SectionReverts
+		}
+		return map[string]string{section: header + suffix}
+	}
+
+	// Other changes keep their type, the section does not tell it
+	section := typeSection(match[1])
+	entries := map[string]string{section: header + suffix}
+	prefix := ""
+	if match[2] != "" {
+		prefix = fmt.Sprintf("**%s:** ", match[2])
+	}
+	if section != SectionOther {
+		entries[section] = prefix + match[4] + suffix
+	}
+	if match[3] == "!" || breakingPattern.MatchString(message) {
+		note := match[4]
+		if found := breakingNotePattern.FindStringSubmatch(message); found != nil {
+			note = strings.Join(strings.Fields(found[1]), " ")
+		}
+		entries[SectionBreaking] = prefix + note + suffix
+	}
+	return entries
+}
+
+// typeSection returns the changelog section of a commit type
+func typeSection(commitType string) string {
+	switch commitType {
+	case "feat":
+		return SectionFeatures
+	case "fix":
+		return SectionFixes
+	case "perf":
+		return SectionPerf
+	case "revert":
+		return SectionReverts
+	default:
+		return SectionOther
+	}
+}
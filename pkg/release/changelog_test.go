@@ -0,0 +1,51 @@
+package release
+
+import "testing"
+
+func TestChangelog(t *testing.T) {
+	commits := []Commit{
+		{ShortHash: "5555555", Message: "docs: explain the changelog"},
+		{ShortHash: "4444444", Message: "feat(api)!: drop the v0 endpoints\n\nBREAKING CHANGE: the v0 endpoints\nare gone, use v1"},
+		{ShortHash: "3333333", Message: "fix(cli): keep the staged files\n\nDetails."},
+		{ShortHash: "2222222", Message: "perf: cache the diff [skip release]"},
+		{ShortHash: "1111111", Message: "Revert \"feat: b\"\n\nThis reverts commit 0123abcd."},
+		{ShortHash: "0000000", Message: "feat: add the changelog"},
+	}
+
+	tests := []struct {
+		name string
+		all  bool
+		want string
+	}{
+		{
+			name: "release sections",
+			want: "## v2.0.0 (2026-10-16)\n" +
+				"\n### ⚠ BREAKING CHANGES\n\n- **api:** the v0 endpoints are gone, use v1 (4444444)\n" +
+				"\n### Features\n\n- **api:** drop the v0 endpoints (4444444)\n- add the changelog (0000000)\n" +
+				"\n### Bug Fixes\n\n- **cli:** keep the staged files (3333333)\n" +
+				"\n### Reverts\n\n- Revert \"feat: b\" (1111111)\n",
+		},
+		{
+			name: "all commits",
+			all:  true,
+			want: "## v2.0.0 (2026-10-16)\n" +
+				"\n### ⚠ BREAKING CHANGES\n\n- **api:** the v0 endpoints are gone, use v1 (4444444)\n" +
+				"\n### Features\n\n- **api:** drop the v0 endpoints (4444444)\n- add the changelog (0000000)\n" +
+				"\n### Bug Fixes\n\n- **cli:** keep the staged files (3333333)\n" +
+				"\n### Reverts\n\n- Revert \"feat: b\" (1111111)\n" +
+				"\n### Other Changes\n\n- docs: explain the changelog (5555555)\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Changelog("v2.0.0 (2026-10-16)", commits, tt.all); got != tt.want {
+				t.Errorf("Changelog() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+
+	if got := Changelog("Unreleased", []Commit{{Message: "chore: tidy"}}, false); got != "" {
+		t.Errorf("Changelog() without release commits = %q, want empty", got)
+	}
+}
@@ -0,0 +1,165 @@
+// Package release computes the next semantic version from commit messages with the
+// rules of semantic-release's default commit analyzer, so gitcomm and release
+// pipelines agree on versioning.
+package release
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Bump is the version increment a commit (or a set of commits) calls for
+type Bump int
+
+const (
+	// None means no release
+	None Bump = iota
+	// Patch releases fixes (fix, perf, reverts)
+	Patch
+	// Minor releases features (feat)
+	Minor
+	// Major releases breaking changes (BREAKING CHANGE footer or "!" after the type)
+	Major
+)
+
+// String returns the bump name (none, patch, minor or major)
+func (b Bump) String() string {
+	switch b {
+	case Patch:
+		return "patch"
+	case Minor:
+		return "minor"
+	case Major:
+		return "major"
+	default:
+		return "none"
+	}
+}
+
+var (
+	// headerPattern matches "type(scope)!: subject"
+	headerPattern = regexp.MustCompile(`^(\w*)(?:\((.*)\))?(!)?: (.*)$`)
+	// breakingPattern matches a breaking change note at the start of a body or footer line
+	breakingPattern = regexp.MustCompile(`(?m)^BREAKING[ -]CHANGE:`)
+	// revertPattern matches git revert messages and "revert:" commits
+	revertPattern = regexp.MustCompile(`(?is)^(?:Revert|revert:)\s"?(.+?)"?\s*This reverts commit (\w*)\.`)
+	// skipPattern marks commits excluded from the analysis
+	skipPattern = regexp.MustCompile(`(?i)\[skip\s+release\]|\[release\s+skip\]`)
+)
+
+// Analyze returns the bump called for by one commit message
+func Analyze(message string) Bump {
+	message = strings.TrimSpace(message)
+	if skipPattern.MatchString(message) {
+		return None
+	}
+
+	header, _, _ := strings.Cut(message, "\n")
+	match := headerPattern.FindStringSubmatch(header)
+	if (match != nil && match[3] == "!") || breakingPattern.MatchString(message) {
+		return Major
+	}
+	if revertPattern.MatchString(message) {
+		return Patch
+	}
+	if match == nil {
+		return None
+	}
+
+	switch match[1] {
+	case "feat":
+		return Minor
+	case "fix", "perf":
+		return Patch
+	default:
+		return None
+	}
+}
+
+// AnalyzeAll returns the highest bump called for by messages
+func AnalyzeAll(messages []string) Bump {
+	bump := None
+	for _, message := range messages {
+		bump = max(bump, Analyze(message))
+		if bump == Major {
+			break
+		}
+	}
+	return bump
+}
+
+// Version is a released semantic version (pre-release versions are not tracked)
+type Version struct {
+	Major, Minor, Patch int
+}
+
+// FirstVersion is the version of the first release
+var FirstVersion = Version{Major: 1}
+
+// ParseVersion parses "MAJOR.MINOR.PATCH" without pre-release or build metadata
+func ParseVersion(s string) (Version, error) {
+	parts := strings.Split(s, ".")
+	if len(parts) != 3 {
+		return Version{}, fmt.Errorf("invalid version %q: want MAJOR.MINOR.PATCH", s)
+	}
+	var numbers [3]int
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 || (len(part) > 1 && part[0] == '0') {
+			return Version{}, fmt.Errorf("invalid version %q: %q is not a version number", s, part)
+		}
+		numbers[i] = n
+	}
+	return Version{Major: numbers[0], Minor: numbers[1], Patch: numbers[2]}, nil
+}
+
+// Less reports whether v precedes other
+func (v Version) Less(other Version) bool {
+	if v.Major != other.Major {
+		return v.Major < other.Major
+	}
+	if v.Minor != other.Minor {
+		return v.Minor < other.Minor
+	}
+	return v.Patch < other.Patch
+}
+
+// Next returns the version following v for bump (v itself for None)
+func (v Version) Next(bump Bump) Version {
+	switch bump {
+	case Major:
+		return Version{Major: v.Major + 1}
+	case Minor:
+		return Version{Major: v.Major, Minor: v.Minor + 1}
+	case Patch:
+		return Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch + 1}
+	default:
+		return v
+	}
+}
+
+// String returns "MAJOR.MINOR.PATCH"
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// LatestTag returns the highest version among tags named prefix+version, with its tag.
+// ok is false when no tag matches.
+func LatestTag(tags []string, prefix string) (version Version, tag string, ok bool) {
+	for _, name := range tags {
+		raw, found := strings.CutPrefix(name, prefix)
+		if !found {
+			continue
+		}
+		v, err := ParseVersion(raw)
+		if err != nil {
+			continue
+		}
+		if !ok || version.Less(v) {
+			version, tag, ok = v, name, true
+		}
+	}
+	return version, tag, ok
+}
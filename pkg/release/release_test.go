@@ -0,0 +1,119 @@
+package release
+
+import "testing"
+
+func TestAnalyze(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    Bump
+	}{
+		{"feature", "feat(api): add health endpoint", Minor},
+		{"fix", "fix: handle empty input", Patch},
+		{"performance", "perf(diff): cache blob reads", Patch},
+		{"other type", "docs: describe hooks", None},
+		{"breaking footer", "refactor: rename options\n\nBREAKING CHANGE: --config is now --config-file", Major},
+		{"breaking footer with dash", "fix: drop v1 API\n\nBREAKING-CHANGE: v1 is gone", Major},
+		{"breaking bang", "feat(cli)!: remove mow.cli flags", Major},
+		{"breaking bang on other type", "chore!: require Go 1.25", Major},
+		{"breaking mention in body", "fix: parser\n\nThis is not a BREAKING CHANGE: it is in the middle of a line", Patch},
+		{"git revert", "Revert \"feat: add cache\"\n\nThis reverts commit 0123abcd.", Patch},
+		{"revert type", "revert: feat: add cache\n\nThis reverts commit 0123abcd.", Patch},
+		{"skipped", "feat: add cache [skip release]", None},
+		{"not conventional", "Update README", None},
+		{"uppercase type", "Feat: add cache", None},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Analyze(tt.message); got != tt.want {
+				t.Errorf("Analyze(%q) = %s, want %s", tt.message, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyzeAll(t *testing.T) {
+	tests := []struct {
+		name     string
+		messages []string
+		want     Bump
+	}{
+		{"no commits", nil, None},
+		{"highest wins", []string{"fix: a", "feat: b", "docs: c"}, Minor},
+		{"breaking", []string{"feat: a", "fix!: b"}, Major},
+		{"nothing to release", []string{"chore: a", "ci: b"}, None},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AnalyzeAll(tt.messages); got != tt.want {
+				t.Errorf("AnalyzeAll() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVersion_Next(t *testing.T) {
+	v := Version{Major: 1, Minor: 4, Patch: 2}
+	tests := []struct {
+		bump Bump
+		want string
+	}{
+		{None, "1.4.2"},
+		{Patch, "1.4.3"},
+		{Minor, "1.5.0"},
+		{Major, "2.0.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.bump.String(), func(t *testing.T) {
+			if got := v.Next(tt.bump).String(); got != tt.want {
+				t.Errorf("Next(%s) = %s, want %s", tt.bump, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Version
+		wantErr bool
+	}{
+		{"1.2.3", Version{1, 2, 3}, false},
+		{"0.10.0", Version{0, 10, 0}, false},
+		{"1.2", Version{}, true},
+		{"1.2.3-rc.1", Version{}, true},
+		{"01.2.3", Version{}, true},
+		{"a.b.c", Version{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseVersion(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseVersion(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseVersion(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLatestTag(t *testing.T) {
+	tags := []string{"v1.9.0", "v1.10.0", "v2.0.0-rc.1", "release-3.0.0", "latest"}
+
+	version, tag, ok := LatestTag(tags, "v")
+	if !ok || tag != "v1.10.0" || version != (Version{1, 10, 0}) {
+		t.Errorf("LatestTag() = %v, %q, %v, want 1.10.0, v1.10.0", version, tag, ok)
+	}
+
+	if _, tag, ok := LatestTag(tags, "release-"); !ok || tag != "release-3.0.0" {
+		t.Errorf("LatestTag(release-) = %q, %v, want release-3.0.0", tag, ok)
+	}
+	if _, _, ok := LatestTag([]string{"latest"}, "v"); ok {
+		t.Error("LatestTag() found a version among non-version tags")
+	}
+}
@@ -0,0 +1,156 @@
+// Package split groups staged files into several logical commits and reads and writes
+// the editable plan of those commits
+package split
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/pkg/conventional"
+)
+
+// ErrInvalidPlan is returned when a plan does not follow the plan format
+var ErrInvalidPlan = errors.New("invalid split plan")
+
+// commitKeyword starts a commit in a plan: "commit <name>"
+const commitKeyword = "commit"
+
+// Group names of the files that are not grouped by directory
+const (
+	BuildGroup = "build"
+	DocsGroup  = "docs"
+	RootGroup  = "root"
+)
+
+// planHeader explains the plan format at the top of FormatPlan's output
+const planHeader = `# Each "commit <name>" line starts a commit, followed by its files (one per line).
+# Commits are created from top to bottom: reorder the blocks, rename them, move files
+# between them or delete lines. Files left out stay staged. Lines starting with # are ignored.
+`
+
+// ByDirectory proposes groups for paths using local heuristics: build files (go.mod,
+// CI configuration...) first, then one group per source directory with its tests, then
+// the documentation. Tests whose directory has no other change get a group of their own.
+func ByDirectory(paths []string) []model.FileGroup {
+	var build, docs []string
+	byDir := make(map[string][]string)
+	for _, p := range paths {
+		switch conventional.InferType([]string{p}).Type {
+		case "chore":
+			build = append(build, p)
+		case "docs":
+			docs = append(docs, p)
+		default:
+			dir := path.Dir(strings.ReplaceAll(p, "\\", "/"))
+			byDir[dir] = append(byDir[dir], p)
+		}
+	}
+
+	var groups []model.FileGroup
+	if len(build) > 0 {
+		groups = append(groups, model.FileGroup{Name: BuildGroup, Files: build})
+	}
+	dirs := make([]string, 0, len(byDir))
+	for dir := range byDir {
+		dirs = append(dirs, dir)
+	}
+	slices.Sort(dirs)
+	for _, dir := range dirs {
+		name := dir
+		if dir == "." {
+			name = RootGroup
+		}
+		groups = append(groups, model.FileGroup{Name: name, Files: byDir[dir]})
+	}
+	if len(docs) > 0 {
+		groups = append(groups, model.FileGroup{Name: DocsGroup, Files: docs})
+	}
+	return groups
+}
+
+// FormatPlan writes groups in the plan format read by ParsePlan, with a header
+// explaining how to edit it
+func FormatPlan(groups []model.FileGroup) string {
+	var sb strings.Builder
+	sb.WriteString(planHeader)
+	for _, group := range groups {
+		sb.WriteString("\n")
+		sb.WriteString(commitKeyword + " " + group.Name + "\n")
+		for _, file := range group.Files {
+			sb.WriteString(file + "\n")
+		}
+	}
+	return sb.String()
+}
+
+// ParsePlan reads a plan written by FormatPlan, possibly edited, or by the AI provider.
+// Every file must be one of staged and appear once; blank lines, "#" comments and
+// markdown fences are ignored, as are "- " list markers. Commits without files are dropped.
+func ParsePlan(text string, staged []string) ([]model.FileGroup, error) {
+	known := make(map[string]bool, len(staged))
+	for _, p := range staged {
+		known[p] = true
+	}
+	seen := make(map[string]bool)
+
+	var groups []model.FileGroup
+	for i, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "```") {
+			continue
+		}
+
+		if name, ok := cutKeyword(line); ok {
+			if name == "" {
+				return nil, fmt.Errorf("%w: line %d: commit without a name", ErrInvalidPlan, i+1)
+			}
+			groups = append(groups, model.FileGroup{Name: name})
+			continue
+		}
+
+		file := strings.Trim(strings.TrimSpace(strings.TrimPrefix(line, "- ")), "`")
+		switch {
+		case len(groups) == 0:
+			return nil, fmt.Errorf("%w: line %d: %s is listed before the first commit line", ErrInvalidPlan, i+1, file)
+		case !known[file]:
+			return nil, fmt.Errorf("%w: line %d: %s is not staged", ErrInvalidPlan, i+1, file)
+		case seen[file]:
+			return nil, fmt.Errorf("%w: line %d: %s is listed twice", ErrInvalidPlan, i+1, file)
+		}
+		seen[file] = true
+		last := &groups[len(groups)-1]
+		last.Files = append(last.Files, file)
+	}
+
+	return slices.DeleteFunc(groups, func(g model.FileGroup) bool { return len(g.Files) == 0 }), nil
+}
+
+// Unlisted returns the paths of staged that are in none of groups, in order
+func Unlisted(groups []model.FileGroup, staged []string) []string {
+	listed := make(map[string]bool)
+	for _, group := range groups {
+		for _, file := range group.Files {
+			listed[file] = true
+		}
+	}
+	var unlisted []string
+	for _, p := range staged {
+		if !listed[p] {
+			unlisted = append(unlisted, p)
+		}
+	}
+	return unlisted
+}
+
+// cutKeyword returns the name of a "commit <name>" line (case-insensitive keyword)
+func cutKeyword(line string) (string, bool) {
+	keyword, name, _ := strings.Cut(line, " ")
+	if !strings.EqualFold(strings.TrimSuffix(keyword, ":"), commitKeyword) {
+		return "", false
+	}
+	return strings.TrimSpace(name), true
+}
@@ -0,0 +1,108 @@
+package split
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+)
+
+func TestByDirectory(t *testing.T) {
+	paths := []string{
+		"README.md",
+		"internal/ui/split.go",
+		"go.mod",
+		"internal/ui/split_test.go",
+		"main.go",
+		"internal/cmd/split.go",
+		"test/integration/split_test.go",
+		"docs/split.md",
+		"go.sum",
+	}
+
+	want := []model.FileGroup{
+		{Name: BuildGroup, Files: []string{"go.mod", "go.sum"}},
+		{Name: RootGroup, Files: []string{"main.go"}},
+		{Name: "internal/cmd", Files: []string{"internal/cmd/split.go"}},
+		{Name: "internal/ui", Files: []string{"internal/ui/split.go", "internal/ui/split_test.go"}},
+		{Name: "test/integration", Files: []string{"test/integration/split_test.go"}},
+		{Name: DocsGroup, Files: []string{"README.md", "docs/split.md"}},
+	}
+	if got := ByDirectory(paths); !reflect.DeepEqual(got, want) {
+		t.Errorf("ByDirectory() = %+v, want %+v", got, want)
+	}
+
+	if got := ByDirectory(nil); got != nil {
+		t.Errorf("ByDirectory(nil) = %+v, want nil", got)
+	}
+}
+
+func TestParsePlan(t *testing.T) {
+	staged := []string{"go.mod", "go.sum", "internal/ui/split.go", "README.md"}
+
+	tests := []struct {
+		name    string
+		text    string
+		want    []model.FileGroup
+		wantErr bool
+	}{
+		{
+			name: "formatted plan",
+			text: FormatPlan([]model.FileGroup{
+				{Name: "build", Files: []string{"go.mod", "go.sum"}},
+				{Name: "internal/ui", Files: []string{"internal/ui/split.go", "README.md"}},
+			}),
+			want: []model.FileGroup{
+				{Name: "build", Files: []string{"go.mod", "go.sum"}},
+				{Name: "internal/ui", Files: []string{"internal/ui/split.go", "README.md"}},
+			},
+		},
+		{
+			name: "edited plan with an empty commit and a file left out",
+			text: "commit split editor\ninternal/ui/split.go\n# a comment\n\ncommit nothing\n\nCOMMIT dependencies\ngo.mod\n",
+			want: []model.FileGroup{
+				{Name: "split editor", Files: []string{"internal/ui/split.go"}},
+				{Name: "dependencies", Files: []string{"go.mod"}},
+			},
+		},
+		{
+			name: "AI answer with markdown",
+			text: "```\ncommit: dependencies\n- `go.mod`\n- go.sum\n```",
+			want: []model.FileGroup{{Name: "dependencies", Files: []string{"go.mod", "go.sum"}}},
+		},
+		{name: "file before any commit", text: "go.mod\ncommit build\n", wantErr: true},
+		{name: "unknown file", text: "commit build\nMakefile\n", wantErr: true},
+		{name: "file listed twice", text: "commit a\ngo.mod\ncommit b\ngo.mod\n", wantErr: true},
+		{name: "commit without a name", text: "commit\ngo.mod\n", wantErr: true},
+		{name: "empty plan", text: "# nothing\n", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePlan(tt.text, staged)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidPlan) {
+					t.Fatalf("ParsePlan() error = %v, want ErrInvalidPlan", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParsePlan() error = %v", err)
+			}
+			if len(got) != 0 || len(tt.want) != 0 {
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("ParsePlan() = %+v, want %+v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestUnlisted(t *testing.T) {
+	groups := []model.FileGroup{{Name: "build", Files: []string{"go.sum"}}}
+	got := Unlisted(groups, []string{"go.mod", "go.sum", "main.go"})
+	if want := []string{"go.mod", "main.go"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Unlisted() = %v, want %v", got, want)
+	}
+}
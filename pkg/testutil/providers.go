@@ -0,0 +1,157 @@
+package testutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// Providers are the AI providers a ProviderServer can stand in for
+var Providers = []string{"openai", "anthropic", "mistral", "ollama", "local"}
+
+// providerPaths are the API paths each provider posts its requests to, relative to its endpoint
+var providerPaths = map[string]string{
+	"openai":    "/responses",
+	"anthropic": "/v1/messages",
+	"mistral":   "/v1/chat/completions",
+	"ollama":    "/api/chat",
+	"local":     "/v1/chat/completions",
+}
+
+// ProviderServer is a fake AI provider API answering every request with the same commit
+// message, in the JSON shape of the real API, so workflows run without network access
+type ProviderServer struct {
+	// Provider is the provider the server stands in for
+	Provider string
+
+	server *httptest.Server
+	answer string
+
+	mu       sync.Mutex
+	failures []int    // statuses of the next requests, before answering again
+	requests []string // bodies of the requests received
+}
+
+// NewProviderServer starts a fake API of provider (one of Providers) answering with
+// answer, stopped with the test
+func NewProviderServer(t testing.TB, provider, answer string) *ProviderServer {
+	t.Helper()
+	if _, ok := providerPaths[provider]; !ok {
+		t.Fatalf("unknown provider %q, want one of %v", provider, Providers)
+	}
+
+	s := &ProviderServer{Provider: provider, answer: answer}
+	s.server = httptest.NewServer(http.HandlerFunc(s.handle))
+	t.Cleanup(s.server.Close)
+	return s
+}
+
+// Endpoint returns the value of the endpoint setting pointing the provider at the server
+func (s *ProviderServer) Endpoint() string {
+	if s.Provider == "local" {
+		// The local provider posts to the endpoint itself
+		return s.server.URL + providerPaths["local"]
+	}
+	return s.server.URL
+}
+
+// FailWith makes the next requests fail with these HTTP statuses, one per request
+func (s *ProviderServer) FailWith(statuses ...int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = append(s.failures, statuses...)
+}
+
+// Requests returns the bodies of the requests received so far
+func (s *ProviderServer) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+// handle records the request, then fails it or answers in the provider's shape
+func (s *ProviderServer) handle(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	s.mu.Lock()
+	s.requests = append(s.requests, string(body))
+	status := http.StatusOK
+	if len(s.failures) > 0 {
+		status, s.failures = s.failures[0], s.failures[1:]
+	}
+	s.mu.Unlock()
+
+	if r.Method != http.MethodPost || r.URL.Path != providerPaths[s.Provider] {
+		http.Error(w, fmt.Sprintf(`{"error": {"message": "unexpected %s %s"}}`, r.Method, r.URL.Path), http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if status != http.StatusOK {
+		w.WriteHeader(status)
+		_, _ = fmt.Fprintf(w, `{"error": {"type": "mock_error", "message": "mock failure %d"}}`, status)
+		return
+	}
+
+	if s.Provider == "ollama" {
+		// Streamed: one JSON object per line until the one marked done
+		for _, chunk := range []map[string]any{
+			{"message": map[string]string{"role": "assistant", "content": s.answer}, "done": false},
+			{"message": map[string]string{"role": "assistant", "content": ""}, "done": true},
+		} {
+			_ = json.NewEncoder(w).Encode(chunk)
+		}
+		return
+	}
+	_ = json.NewEncoder(w).Encode(s.response())
+}
+
+// response returns the answer in the JSON shape of the provider's API
+func (s *ProviderServer) response() any {
+	switch s.Provider {
+	case "openai":
+		// Responses API
+		return map[string]any{
+			"id":         "resp_mock",
+			"object":     "response",
+			"created_at": 0,
+			"status":     "completed",
+			"model":      "mock",
+			"output": []any{map[string]any{
+				"type":    "message",
+				"id":      "msg_mock",
+				"status":  "completed",
+				"role":    "assistant",
+				"content": []any{map[string]any{"type": "output_text", "text": s.answer, "annotations": []any{}}},
+			}},
+		}
+	case "anthropic":
+		// Messages API
+		return map[string]any{
+			"id":            "msg_mock",
+			"type":          "message",
+			"role":          "assistant",
+			"model":         "mock",
+			"content":       []any{map[string]any{"type": "text", "text": s.answer}},
+			"stop_reason":   "end_turn",
+			"stop_sequence": nil,
+			"usage":         map[string]int{"input_tokens": 1, "output_tokens": 1},
+		}
+	default:
+		// Chat completions (Mistral and OpenAI compatible local servers)
+		return map[string]any{
+			"id":      "chatcmpl_mock",
+			"object":  "chat.completion",
+			"created": 0,
+			"model":   "mock",
+			"choices": []any{map[string]any{
+				"index":         0,
+				"message":       map[string]string{"role": "assistant", "content": s.answer},
+				"finish_reason": "stop",
+			}},
+			"usage": map[string]int{"prompt_tokens": 1, "completion_tokens": 1, "total_tokens": 2},
+		}
+	}
+}
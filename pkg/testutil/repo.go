@@ -0,0 +1,234 @@
+// Package testutil builds git repositories in known states for tests: empty, with
+// history, partially staged, with renames, merge conflicts or submodules. Commits use a
+// fixed identity and clock, so their hashes are the same from one run to the next.
+package testutil
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// DefaultBranch is the branch new repositories start on
+const DefaultBranch = "main"
+
+// Identity of the commits, also set as user.name and user.email in the repository config
+const (
+	UserName  = "Test User"
+	UserEmail = "test@example.com"
+)
+
+// epoch is the date of the first commit of a repository; each commit is a minute later
+var epoch = time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+// Repo is a git repository in a temporary directory removed with the test.
+// Methods fail the test on error.
+type Repo struct {
+	// Dir is the repository worktree
+	Dir string
+
+	t        testing.TB
+	commits  int
+	branches int
+}
+
+// NewRepo creates an empty repository (no commit) on DefaultBranch. Its config holds
+// the test identity and disables signing; system and global git config are ignored.
+func NewRepo(t testing.TB) *Repo {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	r := &Repo{Dir: t.TempDir(), t: t}
+	r.Git("init", "-q", "-b", DefaultBranch)
+	r.Git("config", "user.name", UserName)
+	r.Git("config", "user.email", UserEmail)
+	r.Git("config", "commit.gpgsign", "false")
+	r.Git("config", "tag.gpgsign", "false")
+	return r
+}
+
+// NewRepoWithCommit creates a repository whose first commit holds files (path to content)
+func NewRepoWithCommit(t testing.TB, files map[string]string) *Repo {
+	t.Helper()
+	r := NewRepo(t)
+	r.CommitFiles("initial commit", files)
+	return r
+}
+
+// Git runs git in the repository and returns its output
+func (r *Repo) Git(args ...string) string {
+	r.t.Helper()
+	out, err := r.TryGit(args...)
+	if err != nil {
+		r.t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return out
+}
+
+// TryGit runs git in the repository and returns its combined output and error, for
+// commands expected to fail
+func (r *Repo) TryGit(args ...string) (string, error) {
+	return r.tryGitEnv(nil, args...)
+}
+
+// GitEnv runs git in the repository with extra environment variables (KEY=value) and
+// returns its output. They override the fixed identity and clock, e.g. to commit as
+// another author (see Identity) or at the current time.
+func (r *Repo) GitEnv(env []string, args ...string) string {
+	r.t.Helper()
+	out, err := r.tryGitEnv(env, args...)
+	if err != nil {
+		r.t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return out
+}
+
+// tryGitEnv implements TryGit and GitEnv
+func (r *Repo) tryGitEnv(env []string, args ...string) (string, error) {
+	date := epoch.Add(time.Duration(r.commits) * time.Minute).Format(time.RFC3339)
+	cmd := exec.Command("git", args...)
+	cmd.Dir = r.Dir
+	cmd.Env = append(os.Environ(),
+		"GIT_CONFIG_NOSYSTEM=1",
+		"GIT_CONFIG_GLOBAL="+os.DevNull,
+		"GIT_AUTHOR_NAME="+UserName, "GIT_AUTHOR_EMAIL="+UserEmail, "GIT_AUTHOR_DATE="+date,
+		"GIT_COMMITTER_NAME="+UserName, "GIT_COMMITTER_EMAIL="+UserEmail, "GIT_COMMITTER_DATE="+date,
+	)
+	// The last value of a variable wins
+	cmd.Env = append(cmd.Env, env...)
+	out, err := cmd.CombinedOutput()
+	return string(out), err
+}
+
+// Identity returns the environment variables of GitEnv committing as name and email
+func Identity(name, email string) []string {
+	return []string{
+		"GIT_AUTHOR_NAME=" + name, "GIT_AUTHOR_EMAIL=" + email,
+		"GIT_COMMITTER_NAME=" + name, "GIT_COMMITTER_EMAIL=" + email,
+	}
+}
+
+// Now returns the environment variables of GitEnv dating commits at the current time,
+// for code comparing commit dates with the clock
+func Now() []string {
+	date := time.Now().Format(time.RFC3339)
+	return []string{"GIT_AUTHOR_DATE=" + date, "GIT_COMMITTER_DATE=" + date}
+}
+
+// Path returns the absolute path of name, relative to the worktree
+func (r *Repo) Path(name string) string {
+	return filepath.Join(r.Dir, filepath.FromSlash(name))
+}
+
+// WriteFile writes content to name, creating its directories, without staging it
+func (r *Repo) WriteFile(name, content string) {
+	r.t.Helper()
+	path := r.Path(name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		r.t.Fatalf("Failed to create directory of %s: %v", name, err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		r.t.Fatalf("Failed to write %s: %v", name, err)
+	}
+}
+
+// RemoveFile deletes name from the worktree, without staging the deletion
+func (r *Repo) RemoveFile(name string) {
+	r.t.Helper()
+	if err := os.Remove(r.Path(name)); err != nil {
+		r.t.Fatalf("Failed to remove %s: %v", name, err)
+	}
+}
+
+// Stage stages the changes of paths, deletions included
+func (r *Repo) Stage(paths ...string) {
+	r.t.Helper()
+	r.Git(append([]string{"add", "-A", "--"}, paths...)...)
+}
+
+// Commit commits the staged changes and returns the commit hash
+func (r *Repo) Commit(message string) string {
+	r.t.Helper()
+	r.Git("commit", "-q", "--allow-empty", "-m", message)
+	r.commits++
+	return r.Head()
+}
+
+// CommitFiles writes files (path to content), stages them and commits them
+func (r *Repo) CommitFiles(message string, files map[string]string) string {
+	r.t.Helper()
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		r.WriteFile(name, files[name])
+	}
+	if len(names) > 0 {
+		r.Stage(names...)
+	}
+	return r.Commit(message)
+}
+
+// Head returns the HEAD commit hash
+func (r *Repo) Head() string {
+	r.t.Helper()
+	return strings.TrimSpace(r.Git("rev-parse", "HEAD"))
+}
+
+// StagePartially stages staged as the content of name, then writes unstaged to the
+// worktree: the file shows both staged and unstaged changes
+func (r *Repo) StagePartially(name, staged, unstaged string) {
+	r.t.Helper()
+	r.WriteFile(name, staged)
+	r.Stage(name)
+	r.WriteFile(name, unstaged)
+}
+
+// Rename renames from to to with git mv, staging the rename
+func (r *Repo) Rename(from, to string) {
+	r.t.Helper()
+	if err := os.MkdirAll(filepath.Dir(r.Path(to)), 0755); err != nil {
+		r.t.Fatalf("Failed to create directory of %s: %v", to, err)
+	}
+	r.Git("mv", from, to)
+}
+
+// Conflict leaves the repository in the middle of a merge where name conflicts: the
+// current branch sets it to ours, a branch forked from HEAD sets it to theirs, and that
+// branch is merged. Returns the name of the merged branch.
+func (r *Repo) Conflict(name, ours, theirs string) string {
+	r.t.Helper()
+	current := strings.TrimSpace(r.Git("symbolic-ref", "--short", "HEAD"))
+	r.branches++
+	branch := fmt.Sprintf("theirs-%d", r.branches)
+
+	r.Git("checkout", "-q", "-b", branch)
+	r.CommitFiles("theirs: change "+name, map[string]string{name: theirs})
+	r.Git("checkout", "-q", current)
+	r.CommitFiles("ours: change "+name, map[string]string{name: ours})
+
+	if out, err := r.TryGit("merge", "--no-edit", branch); err == nil {
+		r.t.Fatalf("git merge %s succeeded, want a conflict on %s:\n%s", branch, name, out)
+	}
+	return branch
+}
+
+// AddSubmodule adds, at path, a submodule cloned from a new repository with one commit
+// and stages it. Returns the repository the submodule was cloned from (its temporary
+// path is recorded in .gitmodules).
+func (r *Repo) AddSubmodule(path string) *Repo {
+	r.t.Helper()
+	source := NewRepoWithCommit(r.t, map[string]string{"README.md": "# " + filepath.Base(path) + "\n"})
+	// Local clones are refused by default since git 2.38.1
+	r.Git("-c", "protocol.file.allow=always", "submodule", "--quiet", "add", source.Dir, path)
+	return source
+}
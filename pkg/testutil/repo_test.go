@@ -0,0 +1,97 @@
+package testutil
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewRepo_Empty(t *testing.T) {
+	r := NewRepo(t)
+
+	if _, err := r.TryGit("rev-parse", "--verify", "HEAD"); err == nil {
+		t.Error("new repository has a HEAD commit, want none")
+	}
+	if got := strings.TrimSpace(r.Git("symbolic-ref", "--short", "HEAD")); got != DefaultBranch {
+		t.Errorf("branch = %q, want %q", got, DefaultBranch)
+	}
+	if got := strings.TrimSpace(r.Git("config", "user.email")); got != UserEmail {
+		t.Errorf("user.email = %q, want %q", got, UserEmail)
+	}
+}
+
+func TestCommitFiles_DeterministicHashes(t *testing.T) {
+	files := map[string]string{"README.md": "# demo\n", "cmd/main.go": "package main\n"}
+	first := NewRepoWithCommit(t, files)
+	second := NewRepoWithCommit(t, files)
+
+	if first.Head() != second.Head() {
+		t.Errorf("same commits have different hashes: %s and %s", first.Head(), second.Head())
+	}
+	if got := first.Git("log", "--format=%an <%ae> %aI"); got != "Test User <test@example.com> 2024-01-01T12:00:00+00:00\n" {
+		t.Errorf("commit identity and date = %q", got)
+	}
+
+	first.CommitFiles("second", map[string]string{"README.md": "# demo v2\n"})
+	if got := first.Git("log", "-1", "--format=%cI"); got != "2024-01-01T12:01:00+00:00\n" {
+		t.Errorf("second commit date = %q, want one minute after the first", got)
+	}
+}
+
+func TestGitEnv(t *testing.T) {
+	r := NewRepo(t)
+
+	r.GitEnv(Identity("Jane Doe", "jane@example.com"), "commit", "-q", "--allow-empty", "-m", "as jane")
+	if got := r.Git("log", "-1", "--format=%an <%ae> %cn <%ce>"); got != "Jane Doe <jane@example.com> Jane Doe <jane@example.com>\n" {
+		t.Errorf("identity = %q, want Jane Doe as author and committer", got)
+	}
+
+	r.GitEnv(Now(), "commit", "-q", "--allow-empty", "-m", "now")
+	if got := r.Git("log", "-1", "--since=1 hour ago", "--format=%an"); got != UserName+"\n" {
+		t.Errorf("commit dated now not found in the last hour, got %q", got)
+	}
+}
+
+func TestScenarios(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(r *Repo)
+		// status is the expected git status --porcelain output
+		status string
+	}{
+		{
+			name:   "partial staging",
+			setup:  func(r *Repo) { r.StagePartially("a.txt", "staged\n", "unstaged\n") },
+			status: "MM a.txt\n",
+		},
+		{
+			name:   "rename",
+			setup:  func(r *Repo) { r.Rename("a.txt", "docs/b.txt") },
+			status: "R  a.txt -> docs/b.txt\n",
+		},
+		{
+			name:   "deletion",
+			setup:  func(r *Repo) { r.RemoveFile("a.txt"); r.Stage("a.txt") },
+			status: "D  a.txt\n",
+		},
+		{
+			name:   "conflict",
+			setup:  func(r *Repo) { r.Conflict("a.txt", "ours\n", "theirs\n") },
+			status: "UU a.txt\n",
+		},
+		{
+			name:   "submodule",
+			setup:  func(r *Repo) { r.AddSubmodule("vendor/lib") },
+			status: "A  .gitmodules\nA  vendor/lib\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRepoWithCommit(t, map[string]string{"a.txt": strings.Repeat("line\n", 10)})
+			tt.setup(r)
+			if got := r.Git("status", "--porcelain"); got != tt.status {
+				t.Errorf("git status = %q, want %q", got, tt.status)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package testutil
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+// StartSSHAgent serves an in-memory ssh-agent holding a new ed25519 key on SSH_AUTH_SOCK,
+// stopped with the test, and returns the key
+func StartSSHAgent(t testing.TB) ssh.PublicKey {
+	t.Helper()
+
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	keyring := agent.NewKeyring()
+	if err := keyring.Add(agent.AddedKey{PrivateKey: private}); err != nil {
+		t.Fatalf("Failed to add key to agent: %v", err)
+	}
+
+	// Unix socket paths are limited in length: stay out of the long test temp dirs
+	dir, err := os.MkdirTemp("", "agent")
+	if err != nil {
+		t.Fatalf("Failed to create socket dir: %v", err)
+	}
+	socket := filepath.Join(dir, "agent.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	t.Cleanup(func() {
+		listener.Close()
+		os.RemoveAll(dir)
+	})
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				_ = agent.ServeAgent(keyring, conn)
+			}()
+		}
+	}()
+	t.Setenv("SSH_AUTH_SOCK", socket)
+
+	signers, err := keyring.Signers()
+	if err != nil || len(signers) != 1 {
+		t.Fatalf("Failed to read agent keys: %v", err)
+	}
+	return signers[0].PublicKey()
+}
@@ -0,0 +1,219 @@
+//go:build unix
+
+package testutil
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/creack/pty"
+)
+
+// Keys sent to a Terminal as a user would type them
+const (
+	KeyEnter     = "\r"
+	KeyTab       = "\t"
+	KeyBackspace = "\x7f"
+	KeyEscape    = "\x1b"
+	KeyCtrlC     = "\x03"
+	KeyUp        = "\x1b[A"
+	KeyDown      = "\x1b[B"
+	KeyRight     = "\x1b[C"
+	KeyLeft      = "\x1b[D"
+)
+
+// queries are the terminal queries a command may block on, with the replies of a dark
+// 120x40 terminal whose cursor is at the top left
+var queries = []struct{ query, reply string }{
+	{"\x1b]11;?\x1b\\", "\x1b]11;rgb:0000/0000/0000\x1b\\"}, // background color
+	{"\x1b]11;?\a", "\x1b]11;rgb:0000/0000/0000\a"},
+	{"\x1b[6n", "\x1b[1;1R"},   // cursor position
+	{"\x1b[c", "\x1b[?62;22c"}, // device attributes
+}
+
+// DefaultTimeout is how long Expect and Wait wait before failing the test
+var DefaultTimeout = 10 * time.Second
+
+// Terminal runs a command attached to a pseudo-terminal, so interactive prompts render
+// and read keys as they do for a user. Methods fail the test on error or timeout.
+type Terminal struct {
+	t   testing.TB
+	cmd *exec.Cmd
+	pty *os.File
+
+	mu       sync.Mutex
+	output   bytes.Buffer // raw output, escape sequences included
+	answered int          // length of the raw output whose queries were answered
+	matched  int          // length of the plain output consumed by Expect
+	changed  chan struct{}
+	exited   chan struct{}
+	err      error // exit error, set once exited is closed
+}
+
+// Build compiles the main package pkg into dir and returns the path of the binary,
+// typically once in TestMain
+func Build(pkg, dir string) (string, error) {
+	binary := filepath.Join(dir, path.Base(pkg))
+	out, err := exec.Command("go", "build", "-o", binary, pkg).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("go build %s: %w\n%s", pkg, err, out)
+	}
+	return binary, nil
+}
+
+// StartTerminal runs name with args in dir, on an 120x40 pseudo-terminal. env is added
+// to the environment, after the test identity and an isolated git config.
+func StartTerminal(t testing.TB, dir string, env []string, name string, args ...string) *Terminal {
+	t.Helper()
+
+	cmd := exec.Command(name, args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(),
+		"TERM=xterm-256color",
+		"GIT_CONFIG_NOSYSTEM=1",
+		"GIT_CONFIG_GLOBAL="+os.DevNull,
+		"GIT_AUTHOR_NAME="+UserName, "GIT_AUTHOR_EMAIL="+UserEmail,
+		"GIT_COMMITTER_NAME="+UserName, "GIT_COMMITTER_EMAIL="+UserEmail,
+	)
+	cmd.Env = append(cmd.Env, env...)
+
+	f, err := pty.StartWithSize(cmd, &pty.Winsize{Cols: 120, Rows: 40})
+	if err != nil {
+		t.Fatalf("Failed to start %s on a terminal: %v", name, err)
+	}
+
+	term := &Terminal{
+		t:       t,
+		cmd:     cmd,
+		pty:     f,
+		changed: make(chan struct{}, 1),
+		exited:  make(chan struct{}),
+	}
+	go term.read()
+	t.Cleanup(term.close)
+	return term
+}
+
+// read copies the terminal output until the command exits
+func (term *Terminal) read() {
+	buf := make([]byte, 4096)
+	for {
+		n, err := term.pty.Read(buf)
+		if n > 0 {
+			term.mu.Lock()
+			term.output.Write(buf[:n])
+			term.answerQueries()
+			term.mu.Unlock()
+			select {
+			case term.changed <- struct{}{}:
+			default:
+			}
+		}
+		if err != nil {
+			// Linux reports EIO once the command has exited and closed the terminal
+			break
+		}
+	}
+	term.err = term.cmd.Wait()
+	close(term.exited)
+}
+
+// answerQueries replies to the terminal queries printed since the last call, as a real
+// terminal would: prompts wait for the replies before rendering
+func (term *Terminal) answerQueries() {
+	for {
+		pending := term.output.Bytes()[term.answered:]
+		first, length, reply := -1, 0, ""
+		for _, q := range queries {
+			if index := bytes.Index(pending, []byte(q.query)); index >= 0 && (first < 0 || index < first) {
+				first, length, reply = index, len(q.query), q.reply
+			}
+		}
+		if first < 0 {
+			return
+		}
+		term.answered += first + length
+		_, _ = term.pty.WriteString(reply)
+	}
+}
+
+// Output returns everything the command printed so far, without escape sequences
+func (term *Terminal) Output() string {
+	term.mu.Lock()
+	defer term.mu.Unlock()
+	return ansi.Strip(term.output.String())
+}
+
+// Expect waits until text is printed after the previous match
+func (term *Terminal) Expect(text string) {
+	term.t.Helper()
+	deadline := time.After(DefaultTimeout)
+	for {
+		output := term.Output()
+		if index := strings.Index(output[term.matched:], text); index >= 0 {
+			term.matched += index + len(text)
+			return
+		}
+		select {
+		case <-term.changed:
+		case <-term.exited:
+			if !strings.Contains(term.Output()[term.matched:], text) {
+				term.t.Fatalf("command exited before printing %q; output:\n%s", text, term.Output())
+			}
+		case <-deadline:
+			term.t.Fatalf("timed out waiting for %q; output:\n%s", text, term.Output())
+		}
+	}
+}
+
+// Send types keys, as text or Key constants
+func (term *Terminal) Send(keys ...string) {
+	term.t.Helper()
+	for _, key := range keys {
+		if _, err := term.pty.WriteString(key); err != nil {
+			term.t.Fatalf("Failed to send %q: %v", key, err)
+		}
+		// Prompts read escape sequences as one key only when they arrive together
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+// Wait waits for the command to exit and returns its exit code
+func (term *Terminal) Wait() int {
+	term.t.Helper()
+	select {
+	case <-term.exited:
+	case <-time.After(DefaultTimeout):
+		term.t.Fatalf("timed out waiting for the command to exit; output:\n%s", term.Output())
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(term.err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	if term.err != nil {
+		term.t.Fatalf("command failed: %v", term.err)
+	}
+	return 0
+}
+
+// close kills the command if it is still running and releases the terminal
+func (term *Terminal) close() {
+	select {
+	case <-term.exited:
+	default:
+		_ = term.cmd.Process.Kill()
+		<-term.exited
+	}
+	term.pty.Close()
+}
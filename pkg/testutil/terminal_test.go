@@ -0,0 +1,25 @@
+//go:build unix
+
+package testutil
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTerminal(t *testing.T) {
+	// The script queries the cursor position like full-screen prompts do; the reply is
+	// read before the typed name
+	script := `printf 'Name? \033[6n'; read -r line; echo "hello ${line#*R}"; exit 3`
+	term := StartTerminal(t, t.TempDir(), nil, "sh", "-c", script)
+
+	term.Expect("Name?")
+	term.Send("gopher", KeyEnter)
+	term.Expect("hello gopher")
+	if code := term.Wait(); code != 3 {
+		t.Errorf("Wait() = %d, want 3", code)
+	}
+	if strings.Contains(term.Output(), "\x1b") {
+		t.Errorf("Output() kept escape sequences: %q", term.Output())
+	}
+}
@@ -12,12 +12,12 @@ func NewAnthropicTokenCalculator() TokenCalculator {
 	return &AnthropicTokenCalculator{}
 }
 
-// Calculate estimates tokens for Anthropic (custom tokenization)
-// Anthropic uses a different tokenization method than OpenAI
-// Approximation: ~3.5 characters per token
+// Calculate estimates tokens for Anthropic. The Claude tokenizer is not published: the
+// text is pre-tokenized like cl100k_base, with long pieces split every 3 bytes, as Claude
+// models produce more tokens than OpenAI ones for the same code. The count is always an
+// estimate: exact counts come from the count_tokens endpoint (ai.TokenCounter).
 func (a *AnthropicTokenCalculator) Calculate(text string) int {
-	// Rough approximation for Anthropic tokenization
-	return len(text) * 10 / 35 // Approximately 3.5 chars per token
+	return estimatePieces(splitPieces(cl100kPattern, text), 3)
 }
 
 // CalculateForRepositoryState estimates tokens for repository state
@@ -0,0 +1,119 @@
+package tokenization
+
+import (
+	"bufio"
+	"encoding/base64"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/golgoth31/gitcomm/internal/utils"
+)
+
+// VocabularyDirEnv overrides the directory the BPE vocabularies are read from
+const VocabularyDirEnv = "GITCOMM_TOKENIZER_DIR"
+
+// maxBPEPiece is the piece length above which merges are estimated instead of computed,
+// as merging is quadratic in the piece length (minified code, base64 blobs...)
+const maxBPEPiece = 512
+
+var (
+	vocabulariesMu sync.Mutex
+	vocabularies   = make(map[string]map[string]int)
+)
+
+// VocabularyDir returns the directory holding the <encoding>.tiktoken vocabularies:
+// $GITCOMM_TOKENIZER_DIR, or gitcomm/tokenizers in the user cache directory
+func VocabularyDir() string {
+	if dir := os.Getenv(VocabularyDirEnv); dir != "" {
+		return dir
+	}
+	cache, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(cache, "gitcomm", "tokenizers")
+}
+
+// loadVocabulary returns the merge ranks of encoding, nil when its vocabulary file is
+// missing or invalid. Files are read once per process and directory.
+func loadVocabulary(encoding string) map[string]int {
+	dir := VocabularyDir()
+	if dir == "" {
+		return nil
+	}
+	path := filepath.Join(dir, encoding+".tiktoken")
+
+	vocabulariesMu.Lock()
+	defer vocabulariesMu.Unlock()
+	if ranks, ok := vocabularies[path]; ok {
+		return ranks
+	}
+	ranks, err := readVocabulary(path)
+	if err != nil && !os.IsNotExist(err) {
+		utils.Logger.Debug().Err(err).Str("path", path).Msg("Invalid tokenizer vocabulary, estimating tokens")
+	}
+	vocabularies[path] = ranks
+	return ranks
+}
+
+// readVocabulary parses a tiktoken vocabulary: one "<base64 token> <rank>" per line
+func readVocabulary(path string) (map[string]int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	ranks := make(map[string]int)
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		token, rank, ok := strings.Cut(strings.TrimSpace(scanner.Text()), " ")
+		if !ok {
+			return nil, fmt.Errorf("%s:%d: expected a token and a rank", path, line)
+		}
+		decoded, err := base64.StdEncoding.DecodeString(token)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: invalid token: %w", path, line, err)
+		}
+		value, err := strconv.Atoi(rank)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: invalid rank: %w", path, line, err)
+		}
+		ranks[string(decoded)] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return ranks, nil
+}
+
+// bpeCount returns the number of tokens of piece: its bytes are merged pairwise, lowest
+// rank first, until no adjacent pair is in the vocabulary
+func bpeCount(ranks map[string]int, piece string) int {
+	if _, ok := ranks[piece]; ok {
+		return 1
+	}
+	parts := make([]string, len(piece))
+	for i := 0; i < len(piece); i++ {
+		parts[i] = piece[i : i+1]
+	}
+	for len(parts) > 1 {
+		best, bestRank := -1, math.MaxInt
+		for i := 0; i+1 < len(parts); i++ {
+			if rank, ok := ranks[parts[i]+parts[i+1]]; ok && rank < bestRank {
+				best, bestRank = i, rank
+			}
+		}
+		if best < 0 {
+			break
+		}
+		parts[best] += parts[best+1]
+		parts = append(parts[:best+1], parts[best+2:]...)
+	}
+	return len(parts)
+}
@@ -0,0 +1,115 @@
+package tokenization
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSplitPieces(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{name: "words", text: "Hello world", want: []string{"Hello", " world"}},
+		{name: "contraction", text: "it's", want: []string{"it", "'s"}},
+		{name: "numbers by three digits", text: "12345", want: []string{"123", "45"}},
+		{name: "indentation gives its last space to the word", text: "if x {\n    return\n}", want: []string{"if", " x", " {\n", "   ", " return", "\n", "}"}},
+		{name: "code", text: "a := b(c)", want: []string{"a", " :=", " b", "(c", ")"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := splitPieces(cl100kPattern, tt.text)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("splitPieces(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+			if strings.Join(got, "") != tt.text {
+				t.Errorf("splitPieces(%q) lost characters: %q", tt.text, got)
+			}
+		})
+	}
+}
+
+func TestTikTokenCalculator_Vocabulary(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(VocabularyDirEnv, dir)
+
+	var vocabulary strings.Builder
+	for rank, token := range []string{"a", "b", "c", " ", "ab", "abc", " abc"} {
+		fmt.Fprintf(&vocabulary, "%s %d\n", base64.StdEncoding.EncodeToString([]byte(token)), rank)
+	}
+	if err := os.WriteFile(filepath.Join(dir, EncodingO200K+".tiktoken"), []byte(vocabulary.String()), 0644); err != nil {
+		t.Fatalf("Failed to write vocabulary: %v", err)
+	}
+
+	calc := NewTikTokenCalculatorForModel("gpt-4o").(*TikTokenCalculator)
+	if !calc.Exact() {
+		t.Fatal("Exact() = false with the o200k_base vocabulary available")
+	}
+	if !IsExact(calc) || !NewBudgetWith(calc, 0).Exact() {
+		t.Error("IsExact() = false with the o200k_base vocabulary available")
+	}
+	// "abcab" merges into abc + ab, " abc" is a single token
+	if got := calc.Calculate("abcab abc"); got != 3 {
+		t.Errorf("Calculate() = %d, want 3", got)
+	}
+
+	// The cl100k_base vocabulary is missing: tokens are estimated
+	if NewTikTokenCalculatorForModel("gpt-4").(*TikTokenCalculator).Exact() {
+		t.Error("Exact() = true without the cl100k_base vocabulary")
+	}
+	if IsExact(NewMistralTokenCalculator()) {
+		t.Error("IsExact() = true for the Mistral estimate")
+	}
+}
+
+func TestEncodingForModel(t *testing.T) {
+	tests := map[string]string{
+		"":              EncodingO200K,
+		"gpt-4o-mini":   EncodingO200K,
+		"gpt-4.1-nano":  EncodingO200K,
+		"o3-mini":       EncodingO200K,
+		"gpt-4":         EncodingCL100K,
+		"gpt-3.5-turbo": EncodingCL100K,
+	}
+	for model, want := range tests {
+		if got := EncodingForModel(model); got != want {
+			t.Errorf("EncodingForModel(%q) = %q, want %q", model, got, want)
+		}
+	}
+}
+
+func TestForTokenizer(t *testing.T) {
+	tests := []struct {
+		tokenizer    string
+		wantEncoding string
+		wantType     string
+	}{
+		{tokenizer: "", wantEncoding: EncodingO200K},
+		{tokenizer: "openai/gpt-4", wantEncoding: EncodingCL100K},
+		{tokenizer: "anthropic/claude-sonnet-4", wantType: "*tokenization.AnthropicTokenCalculator"},
+		{tokenizer: "mistral", wantType: "*tokenization.MistralTokenCalculator"},
+		{tokenizer: "ollama/qwen2.5-coder:7b", wantType: "*tokenization.FallbackTokenCalculator"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tokenizer, func(t *testing.T) {
+			calc := ForTokenizer(tt.tokenizer)
+			if tt.wantEncoding != "" {
+				tiktoken, ok := calc.(*TikTokenCalculator)
+				if !ok || tiktoken.Encoding() != tt.wantEncoding {
+					t.Errorf("ForTokenizer(%q) = %T, want tiktoken %s", tt.tokenizer, calc, tt.wantEncoding)
+				}
+				return
+			}
+			if got := fmt.Sprintf("%T", calc); got != tt.wantType {
+				t.Errorf("ForTokenizer(%q) = %s, want %s", tt.tokenizer, got, tt.wantType)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package tokenization
+
+// Budget tracks the tokens spent against a limit
+type Budget struct {
+	// Limit is the maximum number of tokens (0: unlimited)
+	Limit int
+
+	// Used is the number of tokens spent so far
+	Used int
+
+	calc TokenCalculator
+}
+
+// NewBudget creates a budget of limit tokens counted with the default provider
+// tokenizer, unlimited when limit is 0 or less
+func NewBudget(limit int) *Budget {
+	return NewBudgetWith(NewTokenCalculator(DefaultProvider), limit)
+}
+
+// NewBudgetWith creates a budget of limit tokens counted with calc
+func NewBudgetWith(calc TokenCalculator, limit int) *Budget {
+	return &Budget{Limit: max(limit, 0), calc: calc}
+}
+
+// Count returns the tokens of text for the tokenizer of the budget
+func (b *Budget) Count(text string) int {
+	if b.calc == nil {
+		return CountTokens(text)
+	}
+	return b.calc.Calculate(text)
+}
+
+// Exact reports whether the tokens are counted exactly rather than estimated (see IsExact)
+func (b *Budget) Exact() bool {
+	return b.calc != nil && IsExact(b.calc)
+}
+
+// Fits reports whether tokens more fit in the budget
+func (b *Budget) Fits(tokens int) bool {
+	return b.Limit == 0 || b.Used+tokens <= b.Limit
+}
+
+// Spend adds tokens to the budget whether they fit or not
+func (b *Budget) Spend(tokens int) {
+	b.Used += tokens
+}
+
+// TrySpend spends tokens when they fit and reports whether they did
+func (b *Budget) TrySpend(tokens int) bool {
+	if !b.Fits(tokens) {
+		return false
+	}
+	b.Used += tokens
+	return true
+}
@@ -0,0 +1,22 @@
+package tokenization
+
+import "testing"
+
+func TestBudget(t *testing.T) {
+	budget := NewBudget(100)
+	budget.Spend(60)
+	if !budget.Fits(40) || budget.Fits(41) {
+		t.Errorf("Fits() with %d of %d used is wrong", budget.Used, budget.Limit)
+	}
+	if budget.TrySpend(50) {
+		t.Error("TrySpend(50) = true over the limit")
+	}
+	if !budget.TrySpend(40) || budget.Used != 100 {
+		t.Errorf("TrySpend(40) left %d used, want 100", budget.Used)
+	}
+
+	unlimited := NewBudget(-1)
+	if unlimited.Limit != 0 || !unlimited.TrySpend(1_000_000) {
+		t.Errorf("NewBudget(-1) = %+v, want an unlimited budget", unlimited)
+	}
+}
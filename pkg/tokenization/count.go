@@ -0,0 +1,40 @@
+package tokenization
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// DefaultProvider is the provider whose tokenizer is used when none is specified
+const DefaultProvider = "openai"
+
+// CountTokens estimates the number of tokens in text using the default provider tokenizer
+func CountTokens(text string) int {
+	return NewTokenCalculator(DefaultProvider).Calculate(text)
+}
+
+// CountFile estimates the number of tokens in the file at path using the default provider tokenizer
+func CountFile(path string) (int, error) {
+	return CountFileWith(NewTokenCalculator(DefaultProvider), path)
+}
+
+// CountFileWith estimates the number of tokens in the file at path using calc
+func CountFileWith(calc TokenCalculator, path string) (int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+
+	return CountReaderWith(calc, file)
+}
+
+// CountReaderWith estimates the number of tokens read from r using calc
+func CountReaderWith(calc TokenCalculator, r io.Reader) (int, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read content: %w", err)
+	}
+	return calc.Calculate(string(content)), nil
+}
@@ -0,0 +1,46 @@
+package tokenization
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCountTokens(t *testing.T) {
+	text := strings.Repeat("abcd", 100)
+	if got, want := CountTokens(text), NewTokenCalculator(DefaultProvider).Calculate(text); got != want {
+		t.Errorf("CountTokens() = %d, want %d", got, want)
+	}
+	if got := CountTokens(""); got != 0 {
+		t.Errorf("CountTokens(\"\") = %d, want 0", got)
+	}
+}
+
+func TestCountFile(t *testing.T) {
+	text := strings.Repeat("diff --git a/x b/x\n", 20)
+	path := filepath.Join(t.TempDir(), "change.diff")
+	if err := os.WriteFile(path, []byte(text), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	got, err := CountFile(path)
+	if err != nil {
+		t.Fatalf("CountFile() error = %v", err)
+	}
+	if want := CountTokens(text); got != want {
+		t.Errorf("CountFile() = %d, want %d", got, want)
+	}
+
+	anthropic, err := CountFileWith(NewTokenCalculator("anthropic"), path)
+	if err != nil {
+		t.Fatalf("CountFileWith() error = %v", err)
+	}
+	if want := NewTokenCalculator("anthropic").Calculate(text); anthropic != want {
+		t.Errorf("CountFileWith(anthropic) = %d, want %d", anthropic, want)
+	}
+
+	if _, err := CountFile(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("CountFile() on missing file should fail")
+	}
+}
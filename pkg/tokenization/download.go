@@ -0,0 +1,90 @@
+package tokenization
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// VocabularyURL is where OpenAI publishes the tiktoken vocabularies
+const VocabularyURL = "https://openaipublic.blob.core.windows.net/encodings/"
+
+// vocabularyHashes are the SHA-256 of the published vocabularies, as pinned by tiktoken
+var vocabularyHashes = map[string]string{
+	EncodingCL100K: "223921b76ee99bde995b7ff738513eef100fb51d18c93597a113bcffe865b2a7",
+	EncodingO200K:  "446a9538cb6c348e3516120d7c08b09f57c36495e2acfffe59a5bf8b0cfb1a2d",
+}
+
+// Encodings returns the encodings of the OpenAI models, whose vocabularies
+// DownloadVocabulary fetches
+func Encodings() []string {
+	return []string{EncodingO200K, EncodingCL100K}
+}
+
+// HasVocabulary reports whether the vocabulary of encoding is in VocabularyDir, so its
+// tokens are counted exactly
+func HasVocabulary(encoding string) bool {
+	return loadVocabulary(encoding) != nil
+}
+
+// DownloadVocabulary downloads the vocabulary of encoding from baseURL (VocabularyURL)
+// into VocabularyDir and returns its path. The file is only written when its SHA-256
+// matches the published one.
+func DownloadVocabulary(ctx context.Context, client *http.Client, baseURL, encoding string) (string, error) {
+	want, ok := vocabularyHashes[encoding]
+	if !ok {
+		return "", fmt.Errorf("unknown encoding %q", encoding)
+	}
+	dir := VocabularyDir()
+	if dir == "" {
+		return "", fmt.Errorf("no tokenizer directory: set %s", VocabularyDirEnv)
+	}
+
+	url := strings.TrimSuffix(baseURL, "/") + "/" + encoding + ".tiktoken"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create vocabulary request: %w", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to download %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to download %s: unexpected status %s", url, resp.Status)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to download %s: %w", url, err)
+	}
+	sum := sha256.Sum256(data)
+	if got := hex.EncodeToString(sum[:]); got != want {
+		return "", fmt.Errorf("checksum mismatch for %s: got %s, want %s", url, got, want)
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create tokenizer directory: %w", err)
+	}
+	path := filepath.Join(dir, encoding+".tiktoken")
+	// Write atomically so an interrupted download never leaves a truncated vocabulary
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write vocabulary: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return "", fmt.Errorf("failed to write vocabulary: %w", err)
+	}
+
+	// The next calculators read the new file
+	vocabulariesMu.Lock()
+	delete(vocabularies, path)
+	vocabulariesMu.Unlock()
+	return path, nil
+}
@@ -0,0 +1,63 @@
+package tokenization
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDownloadVocabulary(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(VocabularyDirEnv, dir)
+
+	vocabulary := base64.StdEncoding.EncodeToString([]byte("ab")) + " 0\n"
+	served := vocabulary
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/test_base.tiktoken" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(served))
+	}))
+	defer server.Close()
+
+	sum := sha256.Sum256([]byte(vocabulary))
+	vocabularyHashes["test_base"] = hex.EncodeToString(sum[:])
+	defer delete(vocabularyHashes, "test_base")
+	path := filepath.Join(dir, "test_base.tiktoken")
+
+	if HasVocabulary("test_base") {
+		t.Fatal("HasVocabulary() = true before the download")
+	}
+
+	// A tampered file is never written
+	served = vocabulary + "Yw== 1\n"
+	if _, err := DownloadVocabulary(context.Background(), server.Client(), server.URL, "test_base"); err == nil {
+		t.Fatal("DownloadVocabulary() should fail on a checksum mismatch")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("vocabulary written despite the checksum mismatch, stat error = %v", err)
+	}
+
+	served = vocabulary
+	got, err := DownloadVocabulary(context.Background(), server.Client(), server.URL+"/", "test_base")
+	if err != nil {
+		t.Fatalf("DownloadVocabulary() error = %v", err)
+	}
+	if got != path {
+		t.Errorf("DownloadVocabulary() = %q, want %q", got, path)
+	}
+	if !HasVocabulary("test_base") {
+		t.Error("HasVocabulary() = false after the download")
+	}
+
+	if _, err := DownloadVocabulary(context.Background(), server.Client(), server.URL, "p50k_base"); err == nil {
+		t.Error("DownloadVocabulary() should refuse an encoding without a known checksum")
+	}
+}
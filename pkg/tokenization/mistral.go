@@ -0,0 +1,53 @@
+package tokenization
+
+import (
+	"unicode"
+
+	"github.com/golgoth31/gitcomm/internal/model"
+)
+
+// MistralTokenCalculator implements tokenization for Mistral
+type MistralTokenCalculator struct{}
+
+// NewMistralTokenCalculator creates a new Mistral token calculator
+func NewMistralTokenCalculator() TokenCalculator {
+	return &MistralTokenCalculator{}
+}
+
+// Calculate estimates tokens for Mistral. Its SentencePiece vocabularies are not
+// shipped: the text is pre-tokenized like cl100k_base, with long pieces split every 3
+// bytes, and numbers count one token per digit as SentencePiece splits digits. The count
+// is always an estimate: no SentencePiece tokenizer is implemented.
+func (m *MistralTokenCalculator) Calculate(text string) int {
+	total := 0
+	for _, piece := range splitPieces(cl100kPattern, text) {
+		digits := 0
+		for _, r := range piece {
+			if unicode.IsDigit(r) {
+				digits++
+			}
+		}
+		if digits > 0 && digits == len(piece) {
+			total += digits
+			continue
+		}
+		total += estimatePieces([]string{piece}, 3)
+	}
+	return total
+}
+
+// CalculateForRepositoryState estimates tokens for repository state
+func (m *MistralTokenCalculator) CalculateForRepositoryState(state *model.RepositoryState) (int, error) {
+	var text string
+	if state.RawDiff != "" {
+		text = state.RawDiff
+	} else {
+		for _, file := range state.StagedFiles {
+			text += file.Path + " " + file.Status + " " + file.Diff + "\n"
+		}
+		for _, file := range state.UnstagedFiles {
+			text += file.Path + " " + file.Status + " " + file.Diff + "\n"
+		}
+	}
+	return m.Calculate(text), nil
+}
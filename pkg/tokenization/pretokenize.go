@@ -0,0 +1,62 @@
+package tokenization
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Pre-tokenization patterns of the tiktoken encodings. RE2 has no lookahead: the
+// "\s+(?!\S)" alternative, which leaves the last space of a run to the next word, is
+// applied by splitPieces.
+var (
+	cl100kPattern = regexp.MustCompile(`(?i:'s|'t|'re|'ve|'m|'ll|'d)|[^\r\n\p{L}\p{N}]?\p{L}+|\p{N}{1,3}| ?[^\s\p{L}\p{N}]+[\r\n]*|\s*[\r\n]+|\s+`)
+	o200kPattern  = regexp.MustCompile(`[^\r\n\p{L}\p{N}]?[\p{Lu}\p{Lt}\p{Lm}\p{Lo}\p{M}]*[\p{Ll}\p{Lm}\p{Lo}\p{M}]+(?i:'s|'t|'re|'ve|'m|'ll|'d)?|[^\r\n\p{L}\p{N}]?[\p{Lu}\p{Lt}\p{Lm}\p{Lo}\p{M}]+[\p{Ll}\p{Lm}\p{Lo}\p{M}]*(?i:'s|'t|'re|'ve|'m|'ll|'d)?|\p{N}{1,3}| ?[^\s\p{L}\p{N}]+[\r\n/]*|\s*[\r\n]+|\s+`)
+)
+
+// splitPieces splits text into the pieces BPE merges are applied to, as tiktoken does
+func splitPieces(pattern *regexp.Regexp, text string) []string {
+	var pieces []string
+	for start := 0; start < len(text); {
+		loc := pattern.FindStringIndex(text[start:])
+		if loc == nil {
+			pieces = append(pieces, text[start:])
+			break
+		}
+		end := start + loc[1]
+		piece := text[start:end]
+		// A run of spaces followed by a word gives its last space to the word
+		if end < len(text) && strings.TrimSpace(piece) == "" && !strings.ContainsAny(piece, "\r\n") {
+			next, _ := utf8.DecodeRuneInString(text[end:])
+			if _, size := utf8.DecodeLastRuneInString(piece); !unicode.IsSpace(next) && size < len(piece) {
+				end -= size
+				piece = text[start:end]
+			}
+		}
+		if piece == "" {
+			// Never loop on an empty match
+			_, size := utf8.DecodeRuneInString(text[start:])
+			end = start + size
+			piece = text[start:end]
+		}
+		pieces = append(pieces, piece)
+		start = end
+	}
+	return pieces
+}
+
+// estimatePieces estimates the tokens of pre-tokenized text without a vocabulary: short
+// pieces (common words, operators, indentation) are single tokens, longer ones are split
+// every charsPerToken bytes
+func estimatePieces(pieces []string, charsPerToken int) int {
+	total := 0
+	for _, piece := range pieces {
+		if len(piece) <= 2*charsPerToken || strings.TrimSpace(piece) == "" {
+			total++
+			continue
+		}
+		total += (len(piece) + charsPerToken - 1) / charsPerToken
+	}
+	return total
+}
@@ -1,25 +1,87 @@
 package tokenization
 
 import (
+	"regexp"
+	"strings"
+
 	"github.com/golgoth31/gitcomm/internal/model"
 	"github.com/golgoth31/gitcomm/internal/utils"
 )
 
-// TikTokenCalculator implements tokenization for OpenAI using tiktoken
-type TikTokenCalculator struct{}
+// Encodings of the OpenAI models
+const (
+	EncodingCL100K = "cl100k_base"
+	EncodingO200K  = "o200k_base"
+)
+
+// o200kModels are the prefixes of the OpenAI models using o200k_base, the encoding of
+// the default model (gpt-4.1-nano)
+var o200kModels = []string{"gpt-4o", "chatgpt-4o", "gpt-4.1", "gpt-4.5", "gpt-5", "o1", "o3", "o4"}
+
+// EncodingForModel returns the tiktoken encoding of an OpenAI model, o200k_base for the
+// default model ("")
+func EncodingForModel(model string) string {
+	name := strings.ToLower(model)
+	if name == "" {
+		return EncodingO200K
+	}
+	for _, prefix := range o200kModels {
+		if strings.HasPrefix(name, prefix) {
+			return EncodingO200K
+		}
+	}
+	return EncodingCL100K
+}
+
+// TikTokenCalculator implements tokenization for OpenAI using tiktoken's byte pair
+// encoding, with the vocabulary of the encoding read from VocabularyDir. Without it,
+// tokens are estimated from the same pre-tokenization.
+type TikTokenCalculator struct {
+	encoding string
+	pattern  *regexp.Regexp
+	ranks    map[string]int
+}
 
-// NewTikTokenCalculator creates a new OpenAI token calculator
+// NewTikTokenCalculator creates a new OpenAI token calculator for the default model
 func NewTikTokenCalculator() TokenCalculator {
-	return &TikTokenCalculator{}
+	return NewTikTokenCalculatorForModel("")
 }
 
-// Calculate estimates tokens using OpenAI's tiktoken (approximation)
-// TODO: Integrate actual tiktoken library when Go bindings are available
-// For now, using a character-based approximation: ~4 characters per token
+// NewTikTokenCalculatorForModel creates an OpenAI token calculator using the encoding of model
+func NewTikTokenCalculatorForModel(model string) TokenCalculator {
+	encoding := EncodingForModel(model)
+	pattern := cl100kPattern
+	if encoding == EncodingO200K {
+		pattern = o200kPattern
+	}
+	return &TikTokenCalculator{encoding: encoding, pattern: pattern, ranks: loadVocabulary(encoding)}
+}
+
+// Encoding returns the name of the encoding
+func (t *TikTokenCalculator) Encoding() string {
+	return t.encoding
+}
+
+// Exact reports whether tokens are counted with the vocabulary rather than estimated
+func (t *TikTokenCalculator) Exact() bool {
+	return t.ranks != nil
+}
+
+// Calculate counts the tokens of text, exactly when the vocabulary is available
 func (t *TikTokenCalculator) Calculate(text string) int {
-	// Rough approximation: OpenAI tokens are typically ~4 characters
-	// This is a simplified version - actual tiktoken would be more accurate
-	return len(text) / 4
+	pieces := splitPieces(t.pattern, text)
+	if t.ranks == nil {
+		return estimatePieces(pieces, 4)
+	}
+	total := 0
+	for _, piece := range pieces {
+		if len(piece) > maxBPEPiece {
+			total += estimatePieces([]string{piece}, 4)
+			continue
+		}
+		total += bpeCount(t.ranks, piece)
+	}
+	return total
 }
 
 // CalculateForRepositoryState estimates tokens for repository state
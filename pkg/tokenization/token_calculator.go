@@ -1,6 +1,8 @@
 package tokenization
 
 import (
+	"strings"
+
 	"github.com/golgoth31/gitcomm/internal/model"
 )
 
@@ -13,16 +15,39 @@ type TokenCalculator interface {
 	CalculateForRepositoryState(state *model.RepositoryState) (int, error)
 }
 
-// NewTokenCalculator creates a token calculator for the specified provider
+// IsExact reports whether calc counts tokens with the tokenizer of the model (OpenAI
+// models with their vocabulary available) rather than estimating them
+func IsExact(calc TokenCalculator) bool {
+	exact, ok := calc.(interface{ Exact() bool })
+	return ok && exact.Exact()
+}
+
+// NewTokenCalculator creates a token calculator for the specified provider, with the
+// encoding of its default model
 func NewTokenCalculator(provider string) TokenCalculator {
+	return NewTokenCalculatorForModel(provider, "")
+}
+
+// NewTokenCalculatorForModel creates a token calculator for a model of the specified provider
+func NewTokenCalculatorForModel(provider, modelName string) TokenCalculator {
 	switch provider {
 	case "openai":
-		return NewTikTokenCalculator()
+		return NewTikTokenCalculatorForModel(modelName)
 	case "anthropic":
 		return NewAnthropicTokenCalculator()
 	case "mistral":
-		return NewFallbackTokenCalculator()
+		return NewMistralTokenCalculator()
 	default:
 		return NewFallbackTokenCalculator()
 	}
 }
+
+// ForTokenizer creates the token calculator named "provider" or "provider/model" (e.g.
+// openai/gpt-4o), the default provider's for ""
+func ForTokenizer(tokenizer string) TokenCalculator {
+	if tokenizer == "" {
+		return NewTokenCalculator(DefaultProvider)
+	}
+	provider, modelName, _ := strings.Cut(tokenizer, "/")
+	return NewTokenCalculatorForModel(provider, modelName)
+}
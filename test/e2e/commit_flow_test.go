@@ -0,0 +1,150 @@
+//go:build unix
+
+// Package e2e drives the gitcomm binary on a pseudo-terminal, through the same
+// interactive prompts as a user
+package e2e
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golgoth31/gitcomm/pkg/testutil"
+)
+
+// binary is the gitcomm binary built for the tests
+var binary string
+
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "gitcomm-e2e-*")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create build directory: %v\n", err)
+		os.Exit(1)
+	}
+	binary, err = testutil.Build("github.com/golgoth31/gitcomm/cmd/gitcomm", dir)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.RemoveAll(dir)
+		os.Exit(1)
+	}
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// startGitcomm runs gitcomm with manual input in repo, with an empty config and home
+func startGitcomm(t *testing.T, repo *testutil.Repo, args ...string) *testutil.Terminal {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("Skipping end-to-end test in short mode")
+	}
+
+	home := t.TempDir()
+	configPath := filepath.Join(home, "config.yaml")
+	if err := os.WriteFile(configPath, nil, 0600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	args = append([]string{"--skip-ai", "--no-sign", "--config", configPath}, args...)
+	return testutil.StartTerminal(t, repo.Dir, []string{"HOME=" + home}, binary, args...)
+}
+
+// newModifiedRepo returns a repository with one commit and an unstaged modification
+func newModifiedRepo(t *testing.T) *testutil.Repo {
+	t.Helper()
+	repo := testutil.NewRepoWithCommit(t, map[string]string{"main.go": "package main\n"})
+	repo.WriteFile("main.go", "package main\n\nfunc main() {}\n")
+	return repo
+}
+
+func TestCommitFlow_ManualMessage(t *testing.T) {
+	repo := newModifiedRepo(t)
+	term := startGitcomm(t, repo)
+
+	term.Expect("Choose a type")
+	term.Send(testutil.KeyDown, testutil.KeyEnter) // fix
+	term.Expect("Scope")
+	term.Send("cli", testutil.KeyEnter)
+	term.Expect("Subject")
+	term.Send("handle empty input", testutil.KeyEnter)
+	term.Expect("Body")
+	term.Send("Exit cleanly when nothing is typed.", testutil.KeyEnter)
+	term.Expect("Footer")
+	term.Send(testutil.KeyEnter)
+	term.Expect("Create commit with this message?")
+	term.Send("y")
+	term.Expect("Commit created successfully")
+
+	if code := term.Wait(); code != 0 {
+		t.Fatalf("gitcomm exit code = %d, want 0; output:\n%s", code, term.Output())
+	}
+	want := "fix(cli): handle empty input\n\nExit cleanly when nothing is typed.\n\nSigned-off-by: Test User <test@example.com>\n"
+	if got := repo.Git("log", "-1", "--format=%B"); got != want+"\n" {
+		t.Errorf("commit message = %q, want %q", got, want)
+	}
+	if status := repo.Git("status", "--porcelain"); status != "" {
+		t.Errorf("worktree not clean after commit: %q", status)
+	}
+}
+
+func TestCommitFlow_Cancellation(t *testing.T) {
+	tests := []struct {
+		name   string
+		cancel func(term *testutil.Terminal)
+	}{
+		{
+			name: "ctrl+c at the type selection",
+			cancel: func(term *testutil.Terminal) {
+				term.Expect("Choose a type")
+				term.Send(testutil.KeyCtrlC)
+			},
+		},
+		{
+			name: "ctrl+c while typing the subject",
+			cancel: func(term *testutil.Terminal) {
+				term.Expect("Choose a type")
+				term.Send(testutil.KeyEnter)
+				term.Expect("Scope")
+				term.Send(testutil.KeyEnter)
+				term.Expect("Subject")
+				term.Send("half a subj", testutil.KeyCtrlC)
+			},
+		},
+		{
+			name: "commit declined",
+			cancel: func(term *testutil.Terminal) {
+				term.Expect("Choose a type")
+				term.Send(testutil.KeyEnter)
+				term.Expect("Scope")
+				term.Send(testutil.KeyEnter)
+				term.Expect("Subject")
+				term.Send("add main", testutil.KeyEnter)
+				term.Expect("Body")
+				term.Send(testutil.KeyEnter)
+				term.Expect("Footer")
+				term.Send(testutil.KeyEnter)
+				term.Expect("Create commit with this message?")
+				term.Send("n")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newModifiedRepo(t)
+			head := repo.Head()
+			term := startGitcomm(t, repo)
+
+			tt.cancel(term)
+			term.Wait()
+
+			if got := repo.Head(); got != head {
+				t.Errorf("HEAD = %s after cancellation, want %s; output:\n%s", got, head, term.Output())
+			}
+			// The modification staged on launch is unstaged again
+			if status := repo.Git("status", "--porcelain"); status != " M main.go\n" {
+				t.Errorf("status = %q after cancellation, want the unstaged modification", status)
+			}
+		})
+	}
+}
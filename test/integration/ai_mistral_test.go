@@ -3,13 +3,13 @@ package integration
 import (
 	"context"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/golgoth31/gitcomm/internal/ai"
 	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/pkg/testutil"
 )
 
 // TestMistralProviderWorkflow tests the Mistral provider workflow
@@ -20,29 +20,8 @@ func TestMistralProviderWorkflow(t *testing.T) {
 		t.Skip("Skipping integration test in short mode")
 	}
 
-	// Create a temporary directory for the test repository
-	tmpDir, err := os.MkdirTemp("", "gitcomm-mistral-test-*")
-	if err != nil {
-		t.Fatalf("Failed to create temp directory: %v", err)
-	}
-	defer os.RemoveAll(tmpDir)
-
-	// Initialize git repository
-	cmd := exec.Command("git", "init", tmpDir)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to initialize git repository: %v", err)
-	}
-
-	// Configure git user
-	cmd = exec.Command("git", "-C", tmpDir, "config", "user.name", "Test User")
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to configure git user.name: %v", err)
-	}
-
-	cmd = exec.Command("git", "-C", tmpDir, "config", "user.email", "test@example.com")
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to configure git user.email: %v", err)
-	}
+	fixture := testutil.NewRepo(t)
+	tmpDir := fixture.Dir
 
 	// Create a test file
 	testFile := filepath.Join(tmpDir, "feature.go")
@@ -51,10 +30,7 @@ func TestMistralProviderWorkflow(t *testing.T) {
 	}
 
 	// Stage the file
-	cmd = exec.Command("git", "-C", tmpDir, "add", testFile)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to stage file: %v", err)
-	}
+	fixture.Git("add", testFile)
 
 	// Test repository state
 	state := &model.RepositoryState{
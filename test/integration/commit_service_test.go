@@ -3,12 +3,12 @@ package integration
 import (
 	"context"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"testing"
 
 	"github.com/golgoth31/gitcomm/internal/repository"
 	"github.com/golgoth31/gitcomm/internal/utils"
+	"github.com/golgoth31/gitcomm/pkg/testutil"
 )
 
 // TestCommitService_ExcludesNewFilesWithoutAddAllFlag verifies that when AutoStage is false,
@@ -21,29 +21,8 @@ func TestCommitService_ExcludesNewFilesWithoutAddAllFlag(t *testing.T) {
 	// Setup: Initialize logger
 	utils.InitLogger(false)
 
-	// Create temporary directory for test repository
-	tmpDir, err := os.MkdirTemp("", "gitcomm-exclude-new-*")
-	if err != nil {
-		t.Fatalf("Failed to create temp directory: %v", err)
-	}
-	defer os.RemoveAll(tmpDir)
-
-	// Initialize git repository
-	cmd := exec.Command("git", "init", tmpDir)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to initialize git repository: %v", err)
-	}
-
-	// Configure git user (required for commits)
-	cmd = exec.Command("git", "-C", tmpDir, "config", "user.name", "Test User")
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to configure git user.name: %v", err)
-	}
-
-	cmd = exec.Command("git", "-C", tmpDir, "config", "user.email", "test@example.com")
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to configure git user.email: %v", err)
-	}
+	fixture := testutil.NewRepo(t)
+	tmpDir := fixture.Dir
 
 	// Create and commit initial file
 	existingFile := filepath.Join(tmpDir, "existing.txt")
@@ -51,15 +30,9 @@ func TestCommitService_ExcludesNewFilesWithoutAddAllFlag(t *testing.T) {
 		t.Fatalf("Failed to create existing file: %v", err)
 	}
 
-	cmd = exec.Command("git", "-C", tmpDir, "add", existingFile)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to stage existing file: %v", err)
-	}
+	fixture.Git("add", existingFile)
 
-	cmd = exec.Command("git", "-C", tmpDir, "commit", "-m", "initial commit")
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to create initial commit: %v", err)
-	}
+	fixture.Git("commit", "-m", "initial commit")
 
 	// Modify existing file
 	if err := os.WriteFile(existingFile, []byte("modified content\n"), 0644); err != nil {
@@ -88,10 +61,7 @@ func TestCommitService_ExcludesNewFilesWithoutAddAllFlag(t *testing.T) {
 	// Verify new file is not in repository state (it's not staged, so it shouldn't be there anyway)
 	// But if it were staged, it should be excluded when AutoStage is false
 	// Since we're not auto-staging, let's manually stage both files to test the filtering
-	cmd = exec.Command("git", "-C", tmpDir, "add", existingFile, newFile)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to stage files: %v", err)
-	}
+	fixture.Git("add", existingFile, newFile)
 
 	// Now get repository state with includeNewFiles = false (simulating AutoStage = false)
 	// We need to set the context value manually since we're not going through CreateCommit
@@ -138,29 +108,8 @@ func TestCommitService_IncludesNewFilesWithAddAllFlag(t *testing.T) {
 	// Setup: Initialize logger
 	utils.InitLogger(false)
 
-	// Create temporary directory for test repository
-	tmpDir, err := os.MkdirTemp("", "gitcomm-include-new-*")
-	if err != nil {
-		t.Fatalf("Failed to create temp directory: %v", err)
-	}
-	defer os.RemoveAll(tmpDir)
-
-	// Initialize git repository
-	cmd := exec.Command("git", "init", tmpDir)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to initialize git repository: %v", err)
-	}
-
-	// Configure git user (required for commits)
-	cmd = exec.Command("git", "-C", tmpDir, "config", "user.name", "Test User")
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to configure git user.name: %v", err)
-	}
-
-	cmd = exec.Command("git", "-C", tmpDir, "config", "user.email", "test@example.com")
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to configure git user.email: %v", err)
-	}
+	fixture := testutil.NewRepo(t)
+	tmpDir := fixture.Dir
 
 	// Create and commit initial file
 	existingFile := filepath.Join(tmpDir, "existing.txt")
@@ -168,15 +117,9 @@ func TestCommitService_IncludesNewFilesWithAddAllFlag(t *testing.T) {
 		t.Fatalf("Failed to create existing file: %v", err)
 	}
 
-	cmd = exec.Command("git", "-C", tmpDir, "add", existingFile)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to stage existing file: %v", err)
-	}
+	fixture.Git("add", existingFile)
 
-	cmd = exec.Command("git", "-C", tmpDir, "commit", "-m", "initial commit")
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to create initial commit: %v", err)
-	}
+	fixture.Git("commit", "-m", "initial commit")
 
 	// Modify existing file
 	if err := os.WriteFile(existingFile, []byte("modified content\n"), 0644); err != nil {
@@ -190,10 +133,7 @@ func TestCommitService_IncludesNewFilesWithAddAllFlag(t *testing.T) {
 	}
 
 	// Manually stage both files to test filtering behavior
-	cmd = exec.Command("git", "-C", tmpDir, "add", existingFile, newFile)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to stage files: %v", err)
-	}
+	fixture.Git("add", existingFile, newFile)
 
 	// Create repository
 	gitRepo, err := repository.NewGitRepository(tmpDir, false, false)
@@ -2,9 +2,10 @@ package integration
 
 import (
 	"os"
-	"os/exec"
 	"path/filepath"
 	"testing"
+
+	"github.com/golgoth31/gitcomm/pkg/testutil"
 )
 
 // TestErrorScenarios tests various error scenarios
@@ -37,29 +38,8 @@ func TestErrorScenarios(t *testing.T) {
 	})
 
 	t.Run("no changes to commit", func(t *testing.T) {
-		// Create a temporary git repository
-		tmpDir, err := os.MkdirTemp("", "gitcomm-no-changes-*")
-		if err != nil {
-			t.Fatalf("Failed to create temp directory: %v", err)
-		}
-		defer os.RemoveAll(tmpDir)
-
-		// Initialize git repository
-		cmd := exec.Command("git", "init", tmpDir)
-		if err := cmd.Run(); err != nil {
-			t.Fatalf("Failed to initialize git repository: %v", err)
-		}
-
-		// Configure git user
-		cmd = exec.Command("git", "-C", tmpDir, "config", "user.name", "Test User")
-		if err := cmd.Run(); err != nil {
-			t.Fatalf("Failed to configure git user.name: %v", err)
-		}
-
-		cmd = exec.Command("git", "-C", tmpDir, "config", "user.email", "test@example.com")
-		if err := cmd.Run(); err != nil {
-			t.Fatalf("Failed to configure git user.email: %v", err)
-		}
+		fixture := testutil.NewRepo(t)
+		tmpDir := fixture.Dir
 
 		// TODO: Once CLI is fully implemented, test:
 		// 1. Change to tmpDir
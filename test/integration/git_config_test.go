@@ -2,7 +2,6 @@ package integration
 
 import (
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -10,6 +9,7 @@ import (
 	"github.com/golgoth31/gitcomm/internal/model"
 	"github.com/golgoth31/gitcomm/internal/repository"
 	"github.com/golgoth31/gitcomm/internal/utils"
+	"github.com/golgoth31/gitcomm/pkg/testutil"
 )
 
 func TestCommitAuthor_FromLocalConfig(t *testing.T) {
@@ -20,24 +20,13 @@ func TestCommitAuthor_FromLocalConfig(t *testing.T) {
 	utils.InitLogger(true)
 
 	// Create temporary directory
-	tmpDir := t.TempDir()
-
-	// Initialize git repository
-	cmd := exec.Command("git", "init", tmpDir)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to initialize git repository: %v", err)
-	}
+	fixture := testutil.NewRepo(t)
+	tmpDir := fixture.Dir
 
 	// Configure git user in local .git/config
-	cmd = exec.Command("git", "-C", tmpDir, "config", "user.name", "Local User")
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to configure git user.name: %v", err)
-	}
+	fixture.Git("config", "user.name", "Local User")
 
-	cmd = exec.Command("git", "-C", tmpDir, "config", "user.email", "local@example.com")
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to configure git user.email: %v", err)
-	}
+	fixture.Git("config", "user.email", "local@example.com")
 
 	// Create repository
 	repo, err := repository.NewGitRepository(tmpDir, false, false)
@@ -51,10 +40,7 @@ func TestCommitAuthor_FromLocalConfig(t *testing.T) {
 		t.Fatalf("Failed to create test file: %v", err)
 	}
 
-	cmd = exec.Command("git", "-C", tmpDir, "add", testFile)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to stage file: %v", err)
-	}
+	fixture.Git("add", testFile)
 
 	// Create commit
 	commitMsg := &model.CommitMessage{
@@ -66,13 +52,9 @@ func TestCommitAuthor_FromLocalConfig(t *testing.T) {
 	}
 
 	// Verify commit author
-	cmd = exec.Command("git", "-C", tmpDir, "log", "-1", "--format=%an <%ae>")
-	output, err := cmd.Output()
-	if err != nil {
-		t.Fatalf("Failed to get commit author: %v", err)
-	}
+	output := fixture.Git("log", "-1", "--format=%an <%ae>")
 
-	author := strings.TrimSpace(string(output))
+	author := strings.TrimSpace(output)
 	if author != "Local User <local@example.com>" {
 		t.Errorf("Expected author 'Local User <local@example.com>', got '%s'", author)
 	}
@@ -85,14 +67,11 @@ func TestCommitAuthor_FromGlobalConfig(t *testing.T) {
 
 	utils.InitLogger(true)
 
-	// Create temporary directory without .git/config
-	tmpDir := t.TempDir()
-
-	// Initialize git repository
-	cmd := exec.Command("git", "init", tmpDir)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to initialize git repository: %v", err)
-	}
+	// Create a repository without user in .git/config
+	fixture := testutil.NewRepo(t)
+	tmpDir := fixture.Dir
+	fixture.Git("config", "--unset", "user.name")
+	fixture.Git("config", "--unset", "user.email")
 
 	// Save original HOME
 	originalHome := os.Getenv("HOME")
@@ -123,10 +102,7 @@ func TestCommitAuthor_FromGlobalConfig(t *testing.T) {
 		t.Fatalf("Failed to create test file: %v", err)
 	}
 
-	cmd = exec.Command("git", "-C", tmpDir, "add", testFile)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to stage file: %v", err)
-	}
+	fixture.Git("add", testFile)
 
 	// Create commit
 	commitMsg := &model.CommitMessage{
@@ -138,13 +114,9 @@ func TestCommitAuthor_FromGlobalConfig(t *testing.T) {
 	}
 
 	// Verify commit author
-	cmd = exec.Command("git", "-C", tmpDir, "log", "-1", "--format=%an <%ae>")
-	output, err := cmd.Output()
-	if err != nil {
-		t.Fatalf("Failed to get commit author: %v", err)
-	}
+	output := fixture.Git("log", "-1", "--format=%an <%ae>")
 
-	author := strings.TrimSpace(string(output))
+	author := strings.TrimSpace(output)
 	if author != "Global User <global@example.com>" {
 		t.Errorf("Expected author 'Global User <global@example.com>', got '%s'", author)
 	}
@@ -158,24 +130,13 @@ func TestCommitAuthor_LocalTakesPrecedence(t *testing.T) {
 	utils.InitLogger(true)
 
 	// Create temporary directory
-	tmpDir := t.TempDir()
-
-	// Initialize git repository
-	cmd := exec.Command("git", "init", tmpDir)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to initialize git repository: %v", err)
-	}
+	fixture := testutil.NewRepo(t)
+	tmpDir := fixture.Dir
 
 	// Configure local config
-	cmd = exec.Command("git", "-C", tmpDir, "config", "user.name", "Local User")
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to configure git user.name: %v", err)
-	}
+	fixture.Git("config", "user.name", "Local User")
 
-	cmd = exec.Command("git", "-C", tmpDir, "config", "user.email", "local@example.com")
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to configure git user.email: %v", err)
-	}
+	fixture.Git("config", "user.email", "local@example.com")
 
 	// Save original HOME
 	originalHome := os.Getenv("HOME")
@@ -206,10 +167,7 @@ func TestCommitAuthor_LocalTakesPrecedence(t *testing.T) {
 		t.Fatalf("Failed to create test file: %v", err)
 	}
 
-	cmd = exec.Command("git", "-C", tmpDir, "add", testFile)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to stage file: %v", err)
-	}
+	fixture.Git("add", testFile)
 
 	// Create commit
 	commitMsg := &model.CommitMessage{
@@ -221,13 +179,9 @@ func TestCommitAuthor_LocalTakesPrecedence(t *testing.T) {
 	}
 
 	// Verify commit author uses local config (precedence)
-	cmd = exec.Command("git", "-C", tmpDir, "log", "-1", "--format=%an <%ae>")
-	output, err := cmd.Output()
-	if err != nil {
-		t.Fatalf("Failed to get commit author: %v", err)
-	}
+	output := fixture.Git("log", "-1", "--format=%an <%ae>")
 
-	author := strings.TrimSpace(string(output))
+	author := strings.TrimSpace(output)
 	if author != "Local User <local@example.com>" {
 		t.Errorf("Expected author 'Local User <local@example.com>' (local precedence), got '%s'", author)
 	}
@@ -241,15 +195,12 @@ func TestCommitAuthor_DefaultsWhenConfigMissing(t *testing.T) {
 	utils.InitLogger(true)
 
 	// Create temporary directory
-	tmpDir := t.TempDir()
-
-	// Initialize git repository
-	cmd := exec.Command("git", "init", tmpDir)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to initialize git repository: %v", err)
-	}
+	fixture := testutil.NewRepo(t)
+	tmpDir := fixture.Dir
 
 	// Don't configure git user - should use defaults
+	fixture.Git("config", "--unset", "user.name")
+	fixture.Git("config", "--unset", "user.email")
 
 	// Save original HOME
 	originalHome := os.Getenv("HOME")
@@ -271,10 +222,7 @@ func TestCommitAuthor_DefaultsWhenConfigMissing(t *testing.T) {
 		t.Fatalf("Failed to create test file: %v", err)
 	}
 
-	cmd = exec.Command("git", "-C", tmpDir, "add", testFile)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to stage file: %v", err)
-	}
+	fixture.Git("add", testFile)
 
 	// Create commit
 	commitMsg := &model.CommitMessage{
@@ -286,13 +234,9 @@ func TestCommitAuthor_DefaultsWhenConfigMissing(t *testing.T) {
 	}
 
 	// Verify commit author uses defaults
-	cmd = exec.Command("git", "-C", tmpDir, "log", "-1", "--format=%an <%ae>")
-	output, err := cmd.Output()
-	if err != nil {
-		t.Fatalf("Failed to get commit author: %v", err)
-	}
+	output := fixture.Git("log", "-1", "--format=%an <%ae>")
 
-	author := strings.TrimSpace(string(output))
+	author := strings.TrimSpace(output)
 	if author != "gitcomm <gitcomm@local>" {
 		t.Errorf("Expected default author 'gitcomm <gitcomm@local>', got '%s'", author)
 	}
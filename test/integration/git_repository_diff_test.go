@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -13,6 +12,7 @@ import (
 	"github.com/golgoth31/gitcomm/internal/model"
 	"github.com/golgoth31/gitcomm/internal/repository"
 	"github.com/golgoth31/gitcomm/internal/utils"
+	"github.com/golgoth31/gitcomm/pkg/testutil"
 )
 
 func TestGetRepositoryState_WithStagedModifiedFile(t *testing.T) {
@@ -23,13 +23,8 @@ func TestGetRepositoryState_WithStagedModifiedFile(t *testing.T) {
 	utils.InitLogger(true)
 
 	// Create temporary directory
-	tmpDir := t.TempDir()
-
-	// Initialize git repository
-	cmd := exec.Command("git", "init", tmpDir)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to initialize git repository: %v", err)
-	}
+	fixture := testutil.NewRepo(t)
+	tmpDir := fixture.Dir
 
 	// Create initial file and commit
 	testFile := filepath.Join(tmpDir, "test.txt")
@@ -37,25 +32,16 @@ func TestGetRepositoryState_WithStagedModifiedFile(t *testing.T) {
 		t.Fatalf("Failed to create test file: %v", err)
 	}
 
-	cmd = exec.Command("git", "-C", tmpDir, "add", testFile)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to stage file: %v", err)
-	}
+	fixture.Git("add", testFile)
 
-	cmd = exec.Command("git", "-C", tmpDir, "commit", "-m", "initial commit")
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to create initial commit: %v", err)
-	}
+	fixture.Git("commit", "-m", "initial commit")
 
 	// Modify file and stage it
 	if err := os.WriteFile(testFile, []byte("line 1\nline 2 modified\nline 3\nline 4\n"), 0644); err != nil {
 		t.Fatalf("Failed to modify test file: %v", err)
 	}
 
-	cmd = exec.Command("git", "-C", tmpDir, "add", testFile)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to stage modified file: %v", err)
-	}
+	fixture.Git("add", testFile)
 
 	// Get repository state
 	repo, err := repository.NewGitRepository(tmpDir, false, true)
@@ -100,13 +86,8 @@ func TestGetRepositoryState_WithStagedAddedFile(t *testing.T) {
 	utils.InitLogger(true)
 
 	// Create temporary directory
-	tmpDir := t.TempDir()
-
-	// Initialize git repository
-	cmd := exec.Command("git", "init", tmpDir)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to initialize git repository: %v", err)
-	}
+	fixture := testutil.NewRepo(t)
+	tmpDir := fixture.Dir
 
 	// Create new file and stage it
 	testFile := filepath.Join(tmpDir, "new.txt")
@@ -114,10 +95,7 @@ func TestGetRepositoryState_WithStagedAddedFile(t *testing.T) {
 		t.Fatalf("Failed to create test file: %v", err)
 	}
 
-	cmd = exec.Command("git", "-C", tmpDir, "add", testFile)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to stage file: %v", err)
-	}
+	fixture.Git("add", testFile)
 
 	// Get repository state
 	repo, err := repository.NewGitRepository(tmpDir, false, true)
@@ -158,13 +136,8 @@ func TestGetRepositoryState_WithStagedDeletedFile(t *testing.T) {
 	utils.InitLogger(true)
 
 	// Create temporary directory
-	tmpDir := t.TempDir()
-
-	// Initialize git repository
-	cmd := exec.Command("git", "init", tmpDir)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to initialize git repository: %v", err)
-	}
+	fixture := testutil.NewRepo(t)
+	tmpDir := fixture.Dir
 
 	// Create file and commit it
 	testFile := filepath.Join(tmpDir, "to_delete.txt")
@@ -172,25 +145,16 @@ func TestGetRepositoryState_WithStagedDeletedFile(t *testing.T) {
 		t.Fatalf("Failed to create test file: %v", err)
 	}
 
-	cmd = exec.Command("git", "-C", tmpDir, "add", testFile)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to stage file: %v", err)
-	}
+	fixture.Git("add", testFile)
 
-	cmd = exec.Command("git", "-C", tmpDir, "commit", "-m", "initial commit")
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to create initial commit: %v", err)
-	}
+	fixture.Git("commit", "-m", "initial commit")
 
 	// Delete file and stage deletion
 	if err := os.Remove(testFile); err != nil {
 		t.Fatalf("Failed to delete file: %v", err)
 	}
 
-	cmd = exec.Command("git", "-C", tmpDir, "rm", testFile)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to stage deletion: %v", err)
-	}
+	fixture.Git("rm", testFile)
 
 	// Get repository state
 	repo, err := repository.NewGitRepository(tmpDir, false, true)
@@ -234,40 +198,12 @@ func TestGetRepositoryState_WithStagedRenamedFile(t *testing.T) {
 
 	utils.InitLogger(true)
 
-	// Create temporary directory
-	tmpDir := t.TempDir()
-
-	// Initialize git repository
-	cmd := exec.Command("git", "init", tmpDir)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to initialize git repository: %v", err)
-	}
-
-	// Create file and commit it
-	oldFile := filepath.Join(tmpDir, "old.txt")
-	if err := os.WriteFile(oldFile, []byte("content\n"), 0644); err != nil {
-		t.Fatalf("Failed to create test file: %v", err)
-	}
-
-	cmd = exec.Command("git", "-C", tmpDir, "add", oldFile)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to stage file: %v", err)
-	}
-
-	cmd = exec.Command("git", "-C", tmpDir, "commit", "-m", "initial commit")
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to create initial commit: %v", err)
-	}
-
-	// Rename file using git mv
-	newFile := filepath.Join(tmpDir, "new.txt")
-	cmd = exec.Command("git", "-C", tmpDir, "mv", oldFile, newFile)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to rename file: %v", err)
-	}
+	// Commit a file, then rename it using git mv
+	fixture := testutil.NewRepoWithCommit(t, map[string]string{"old.txt": "content\n"})
+	fixture.Rename("old.txt", "new.txt")
 
 	// Get repository state
-	repo, err := repository.NewGitRepository(tmpDir, false, true)
+	repo, err := repository.NewGitRepository(fixture.Dir, false, true)
 	if err != nil {
 		t.Fatalf("Failed to create repository: %v", err)
 	}
@@ -313,13 +249,8 @@ func TestGetRepositoryState_WithStagedCopiedFile(t *testing.T) {
 	utils.InitLogger(true)
 
 	// Create temporary directory
-	tmpDir := t.TempDir()
-
-	// Initialize git repository
-	cmd := exec.Command("git", "init", tmpDir)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to initialize git repository: %v", err)
-	}
+	fixture := testutil.NewRepo(t)
+	tmpDir := fixture.Dir
 
 	// Create file and commit it
 	sourceFile := filepath.Join(tmpDir, "source.txt")
@@ -327,15 +258,9 @@ func TestGetRepositoryState_WithStagedCopiedFile(t *testing.T) {
 		t.Fatalf("Failed to create test file: %v", err)
 	}
 
-	cmd = exec.Command("git", "-C", tmpDir, "add", sourceFile)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to stage file: %v", err)
-	}
+	fixture.Git("add", sourceFile)
 
-	cmd = exec.Command("git", "-C", tmpDir, "commit", "-m", "initial commit")
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to create initial commit: %v", err)
-	}
+	fixture.Git("commit", "-m", "initial commit")
 
 	// Copy file using git add with --find-copies
 	destFile := filepath.Join(tmpDir, "dest.txt")
@@ -343,10 +268,7 @@ func TestGetRepositoryState_WithStagedCopiedFile(t *testing.T) {
 		t.Fatalf("Failed to create copied file: %v", err)
 	}
 
-	cmd = exec.Command("git", "-C", tmpDir, "add", destFile)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to stage copied file: %v", err)
-	}
+	fixture.Git("add", destFile)
 
 	// Get repository state
 	repo, err := repository.NewGitRepository(tmpDir, false, true)
@@ -397,13 +319,8 @@ func TestGetRepositoryState_WithNoStagedChanges(t *testing.T) {
 	utils.InitLogger(true)
 
 	// Create temporary directory
-	tmpDir := t.TempDir()
-
-	// Initialize git repository
-	cmd := exec.Command("git", "init", tmpDir)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to initialize git repository: %v", err)
-	}
+	fixture := testutil.NewRepo(t)
+	tmpDir := fixture.Dir
 
 	// Create file and commit it (no staged changes)
 	testFile := filepath.Join(tmpDir, "committed.txt")
@@ -411,15 +328,9 @@ func TestGetRepositoryState_WithNoStagedChanges(t *testing.T) {
 		t.Fatalf("Failed to create test file: %v", err)
 	}
 
-	cmd = exec.Command("git", "-C", tmpDir, "add", testFile)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to stage file: %v", err)
-	}
+	fixture.Git("add", testFile)
 
-	cmd = exec.Command("git", "-C", tmpDir, "commit", "-m", "initial commit")
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to create initial commit: %v", err)
-	}
+	fixture.Git("commit", "-m", "initial commit")
 
 	// Get repository state (no staged changes)
 	repo, err := repository.NewGitRepository(tmpDir, false, true)
@@ -446,13 +357,8 @@ func TestGetRepositoryState_WithUnstagedFiles(t *testing.T) {
 	utils.InitLogger(true)
 
 	// Create temporary directory
-	tmpDir := t.TempDir()
-
-	// Initialize git repository
-	cmd := exec.Command("git", "init", tmpDir)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to initialize git repository: %v", err)
-	}
+	fixture := testutil.NewRepo(t)
+	tmpDir := fixture.Dir
 
 	// Create file and commit it
 	testFile := filepath.Join(tmpDir, "test.txt")
@@ -460,15 +366,9 @@ func TestGetRepositoryState_WithUnstagedFiles(t *testing.T) {
 		t.Fatalf("Failed to create test file: %v", err)
 	}
 
-	cmd = exec.Command("git", "-C", tmpDir, "add", testFile)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to stage file: %v", err)
-	}
+	fixture.Git("add", testFile)
 
-	cmd = exec.Command("git", "-C", tmpDir, "commit", "-m", "initial commit")
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to create initial commit: %v", err)
-	}
+	fixture.Git("commit", "-m", "initial commit")
 
 	// Modify file but don't stage it (unstaged change)
 	if err := os.WriteFile(testFile, []byte("modified content\n"), 0644); err != nil {
@@ -509,13 +409,8 @@ func TestGetRepositoryState_DiffMatchesGitDiffCached(t *testing.T) {
 	utils.InitLogger(true)
 
 	// Create temporary directory
-	tmpDir := t.TempDir()
-
-	// Initialize git repository
-	cmd := exec.Command("git", "init", tmpDir)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to initialize git repository: %v", err)
-	}
+	fixture := testutil.NewRepo(t)
+	tmpDir := fixture.Dir
 
 	// Create initial file and commit
 	testFile := filepath.Join(tmpDir, "test.txt")
@@ -523,25 +418,16 @@ func TestGetRepositoryState_DiffMatchesGitDiffCached(t *testing.T) {
 		t.Fatalf("Failed to create test file: %v", err)
 	}
 
-	cmd = exec.Command("git", "-C", tmpDir, "add", testFile)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to stage file: %v", err)
-	}
+	fixture.Git("add", testFile)
 
-	cmd = exec.Command("git", "-C", tmpDir, "commit", "-m", "initial commit")
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to create initial commit: %v", err)
-	}
+	fixture.Git("commit", "-m", "initial commit")
 
 	// Make specific changes and stage
 	if err := os.WriteFile(testFile, []byte("line 1\nline 2 modified\nline 3\nline 4 added\n"), 0644); err != nil {
 		t.Fatalf("Failed to modify test file: %v", err)
 	}
 
-	cmd = exec.Command("git", "-C", tmpDir, "add", testFile)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to stage modified file: %v", err)
-	}
+	fixture.Git("add", testFile)
 
 	// Get repository state
 	repo, err := repository.NewGitRepository(tmpDir, false, true)
@@ -586,13 +472,8 @@ func TestGetRepositoryState_MultipleFilesComputedIndependently(t *testing.T) {
 	utils.InitLogger(true)
 
 	// Create temporary directory
-	tmpDir := t.TempDir()
-
-	// Initialize git repository
-	cmd := exec.Command("git", "init", tmpDir)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to initialize git repository: %v", err)
-	}
+	fixture := testutil.NewRepo(t)
+	tmpDir := fixture.Dir
 
 	// Create multiple files and commit
 	file1 := filepath.Join(tmpDir, "file1.txt")
@@ -604,15 +485,9 @@ func TestGetRepositoryState_MultipleFilesComputedIndependently(t *testing.T) {
 		t.Fatalf("Failed to create file2: %v", err)
 	}
 
-	cmd = exec.Command("git", "-C", tmpDir, "add", file1, file2)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to stage files: %v", err)
-	}
+	fixture.Git("add", file1, file2)
 
-	cmd = exec.Command("git", "-C", tmpDir, "commit", "-m", "initial commit")
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to create initial commit: %v", err)
-	}
+	fixture.Git("commit", "-m", "initial commit")
 
 	// Modify both files and stage
 	if err := os.WriteFile(file1, []byte("file1 modified\n"), 0644); err != nil {
@@ -622,10 +497,7 @@ func TestGetRepositoryState_MultipleFilesComputedIndependently(t *testing.T) {
 		t.Fatalf("Failed to modify file2: %v", err)
 	}
 
-	cmd = exec.Command("git", "-C", tmpDir, "add", file1, file2)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to stage modified files: %v", err)
-	}
+	fixture.Git("add", file1, file2)
 
 	// Get repository state
 	repo, err := repository.NewGitRepository(tmpDir, false, true)
@@ -661,48 +533,12 @@ func TestGetRepositoryState_StagedFileWithUnstagedModifications(t *testing.T) {
 
 	utils.InitLogger(true)
 
-	// Create temporary directory
-	tmpDir := t.TempDir()
-
-	// Initialize git repository
-	cmd := exec.Command("git", "init", tmpDir)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to initialize git repository: %v", err)
-	}
-
-	// Create file and commit
-	testFile := filepath.Join(tmpDir, "test.txt")
-	if err := os.WriteFile(testFile, []byte("initial\n"), 0644); err != nil {
-		t.Fatalf("Failed to create test file: %v", err)
-	}
-
-	cmd = exec.Command("git", "-C", tmpDir, "add", testFile)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to stage file: %v", err)
-	}
-
-	cmd = exec.Command("git", "-C", tmpDir, "commit", "-m", "initial commit")
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to create initial commit: %v", err)
-	}
-
-	// Make staged change
-	if err := os.WriteFile(testFile, []byte("staged change\n"), 0644); err != nil {
-		t.Fatalf("Failed to modify file: %v", err)
-	}
-
-	cmd = exec.Command("git", "-C", tmpDir, "add", testFile)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to stage file: %v", err)
-	}
-
-	// Make additional unstaged change (not staged)
-	if err := os.WriteFile(testFile, []byte("staged change\nunstaged addition\n"), 0644); err != nil {
-		t.Fatalf("Failed to add unstaged change: %v", err)
-	}
+	// Commit a file, stage a change and make an additional unstaged change
+	fixture := testutil.NewRepoWithCommit(t, map[string]string{"test.txt": "initial\n"})
+	fixture.StagePartially("test.txt", "staged change\n", "staged change\nunstaged addition\n")
 
 	// Get repository state
-	repo, err := repository.NewGitRepository(tmpDir, false, true)
+	repo, err := repository.NewGitRepository(fixture.Dir, false, true)
 	if err != nil {
 		t.Fatalf("Failed to create repository: %v", err)
 	}
@@ -742,7 +578,7 @@ func TestGetRepositoryState_StagedFileWithUnstagedModifications(t *testing.T) {
 	}
 }
 
-func TestGetRepositoryState_BinaryFilesHaveEmptyDiff(t *testing.T) {
+func TestGetRepositoryState_BinaryFilesAreSummarized(t *testing.T) {
 	if testing.Short() {
 		t.Skip("Skipping integration test in short mode")
 	}
@@ -750,13 +586,8 @@ func TestGetRepositoryState_BinaryFilesHaveEmptyDiff(t *testing.T) {
 	utils.InitLogger(true)
 
 	// Create temporary directory
-	tmpDir := t.TempDir()
-
-	// Initialize git repository
-	cmd := exec.Command("git", "init", tmpDir)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to initialize git repository: %v", err)
-	}
+	fixture := testutil.NewRepo(t)
+	tmpDir := fixture.Dir
 
 	// Create binary file (PNG header)
 	binaryFile := filepath.Join(tmpDir, "image.png")
@@ -765,10 +596,7 @@ func TestGetRepositoryState_BinaryFilesHaveEmptyDiff(t *testing.T) {
 		t.Fatalf("Failed to create binary file: %v", err)
 	}
 
-	cmd = exec.Command("git", "-C", tmpDir, "add", binaryFile)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to stage binary file: %v", err)
-	}
+	fixture.Git("add", binaryFile)
 
 	// Get repository state
 	repo, err := repository.NewGitRepository(tmpDir, false, true)
@@ -781,13 +609,14 @@ func TestGetRepositoryState_BinaryFilesHaveEmptyDiff(t *testing.T) {
 		t.Fatalf("Failed to get repository state: %v", err)
 	}
 
-	// Verify binary file has empty diff
+	// Verify binary file is summarized instead of diffed
 	if len(state.StagedFiles) != 1 {
 		t.Fatalf("Expected 1 staged file, got %d", len(state.StagedFiles))
 	}
 
-	if state.StagedFiles[0].Diff != "" {
-		t.Error("Expected binary file to have empty Diff field, got non-empty")
+	want := "file: image.png (binary)\ntype: png image\nsize: 8 bytes\nchanges: added"
+	if state.StagedFiles[0].Diff != want {
+		t.Errorf("Expected binary file summary %q, got %q", want, state.StagedFiles[0].Diff)
 	}
 }
 
@@ -798,83 +627,12 @@ func TestGetRepositoryState_HandlesUnmergedFiles(t *testing.T) {
 
 	utils.InitLogger(true)
 
-	// Create temporary directory
-	tmpDir := t.TempDir()
-
-	// Initialize git repository
-	cmd := exec.Command("git", "init", tmpDir)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to initialize git repository: %v", err)
-	}
-
-	// Configure git user
-	cmd = exec.Command("git", "-C", tmpDir, "config", "user.name", "Test User")
-	cmd.Run()
-	cmd = exec.Command("git", "-C", tmpDir, "config", "user.email", "test@example.com")
-	cmd.Run()
-
-	// Create file and commit
-	testFile := filepath.Join(tmpDir, "test.txt")
-	if err := os.WriteFile(testFile, []byte("initial\n"), 0644); err != nil {
-		t.Fatalf("Failed to create test file: %v", err)
-	}
-
-	cmd = exec.Command("git", "-C", tmpDir, "add", testFile)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to stage file: %v", err)
-	}
-
-	cmd = exec.Command("git", "-C", tmpDir, "commit", "-m", "initial")
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to create commit: %v", err)
-	}
-
-	// Create branch and make conflicting changes
-	cmd = exec.Command("git", "-C", tmpDir, "checkout", "-b", "feature")
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to create branch: %v", err)
-	}
-
-	if err := os.WriteFile(testFile, []byte("feature change\n"), 0644); err != nil {
-		t.Fatalf("Failed to modify file: %v", err)
-	}
-
-	cmd = exec.Command("git", "-C", tmpDir, "add", testFile)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to stage file: %v", err)
-	}
-
-	cmd = exec.Command("git", "-C", tmpDir, "commit", "-m", "feature")
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to create commit: %v", err)
-	}
-
-	// Switch back to main and make conflicting change
-	cmd = exec.Command("git", "-C", tmpDir, "checkout", "main")
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to checkout main: %v", err)
-	}
-
-	if err := os.WriteFile(testFile, []byte("main change\n"), 0644); err != nil {
-		t.Fatalf("Failed to modify file: %v", err)
-	}
-
-	cmd = exec.Command("git", "-C", tmpDir, "add", testFile)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to stage file: %v", err)
-	}
-
-	cmd = exec.Command("git", "-C", tmpDir, "commit", "-m", "main")
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to create commit: %v", err)
-	}
-
-	// Attempt merge (will create conflict)
-	cmd = exec.Command("git", "-C", tmpDir, "merge", "feature", "--no-edit")
-	cmd.Run() // Ignore error - merge will create conflict
+	// Merge a branch with a conflicting change (will create conflict)
+	fixture := testutil.NewRepoWithCommit(t, map[string]string{"test.txt": "initial\n"})
+	fixture.Conflict("test.txt", "main change\n", "feature change\n")
 
 	// Get repository state - should handle unmerged files gracefully
-	repo, err := repository.NewGitRepository(tmpDir, false, true)
+	repo, err := repository.NewGitRepository(fixture.Dir, false, true)
 	if err != nil {
 		t.Fatalf("Failed to create repository: %v", err)
 	}
@@ -901,13 +659,8 @@ func TestGetRepositoryState_HandlesEmptyRepository(t *testing.T) {
 	utils.InitLogger(true)
 
 	// Create temporary directory
-	tmpDir := t.TempDir()
-
-	// Initialize git repository (no commits yet - empty repository)
-	cmd := exec.Command("git", "init", tmpDir)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to initialize git repository: %v", err)
-	}
+	fixture := testutil.NewRepo(t)
+	tmpDir := fixture.Dir
 
 	// Create new file and stage it (no HEAD exists)
 	testFile := filepath.Join(tmpDir, "new.txt")
@@ -915,10 +668,7 @@ func TestGetRepositoryState_HandlesEmptyRepository(t *testing.T) {
 		t.Fatalf("Failed to create test file: %v", err)
 	}
 
-	cmd = exec.Command("git", "-C", tmpDir, "add", testFile)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to stage file: %v", err)
-	}
+	fixture.Git("add", testFile)
 
 	// Get repository state - should handle empty repository (no HEAD)
 	repo, err := repository.NewGitRepository(tmpDir, false, true)
@@ -959,13 +709,8 @@ func TestGetRepositoryState_PerformanceWithManyFiles(t *testing.T) {
 	utils.InitLogger(true)
 
 	// Create temporary directory
-	tmpDir := t.TempDir()
-
-	// Initialize git repository
-	cmd := exec.Command("git", "init", tmpDir)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to initialize git repository: %v", err)
-	}
+	fixture := testutil.NewRepo(t)
+	tmpDir := fixture.Dir
 
 	// Create initial commit with some files
 	for i := 0; i < 10; i++ {
@@ -975,15 +720,9 @@ func TestGetRepositoryState_PerformanceWithManyFiles(t *testing.T) {
 		}
 	}
 
-	cmd = exec.Command("git", "-C", tmpDir, "add", ".")
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to stage files: %v", err)
-	}
+	fixture.Git("add", ".")
 
-	cmd = exec.Command("git", "-C", tmpDir, "commit", "-m", "initial")
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to create commit: %v", err)
-	}
+	fixture.Git("commit", "-m", "initial")
 
 	// Modify and stage 100 files
 	for i := 0; i < 100; i++ {
@@ -993,10 +732,7 @@ func TestGetRepositoryState_PerformanceWithManyFiles(t *testing.T) {
 		}
 	}
 
-	cmd = exec.Command("git", "-C", tmpDir, "add", ".")
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to stage modified files: %v", err)
-	}
+	fixture.Git("add", ".")
 
 	// Measure performance
 	start := time.Now()
@@ -1034,13 +770,8 @@ func TestGetRepositoryState_ErrorRate(t *testing.T) {
 	utils.InitLogger(true)
 
 	// Create temporary directory
-	tmpDir := t.TempDir()
-
-	// Initialize git repository
-	cmd := exec.Command("git", "init", tmpDir)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to initialize git repository: %v", err)
-	}
+	fixture := testutil.NewRepo(t)
+	tmpDir := fixture.Dir
 
 	// Create initial commit
 	testFile := filepath.Join(tmpDir, "test.txt")
@@ -1048,15 +779,9 @@ func TestGetRepositoryState_ErrorRate(t *testing.T) {
 		t.Fatalf("Failed to create test file: %v", err)
 	}
 
-	cmd = exec.Command("git", "-C", tmpDir, "add", testFile)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to stage file: %v", err)
-	}
+	fixture.Git("add", testFile)
 
-	cmd = exec.Command("git", "-C", tmpDir, "commit", "-m", "initial")
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to create commit: %v", err)
-	}
+	fixture.Git("commit", "-m", "initial")
 
 	// Create multiple files and stage them
 	var stagedFiles []string
@@ -1068,10 +793,7 @@ func TestGetRepositoryState_ErrorRate(t *testing.T) {
 		stagedFiles = append(stagedFiles, file)
 	}
 
-	cmd = exec.Command("git", "-C", tmpDir, "add", ".")
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to stage files: %v", err)
-	}
+	fixture.Git("add", ".")
 
 	// Get repository state
 	repo, err := repository.NewGitRepository(tmpDir, false, true)
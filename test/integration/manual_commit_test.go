@@ -2,9 +2,10 @@ package integration
 
 import (
 	"os"
-	"os/exec"
 	"path/filepath"
 	"testing"
+
+	"github.com/golgoth31/gitcomm/pkg/testutil"
 )
 
 // TestManualCommitWorkflow tests the complete manual commit workflow
@@ -15,29 +16,8 @@ func TestManualCommitWorkflow(t *testing.T) {
 		t.Skip("Skipping integration test in short mode")
 	}
 
-	// Create a temporary directory for the test repository
-	tmpDir, err := os.MkdirTemp("", "gitcomm-test-*")
-	if err != nil {
-		t.Fatalf("Failed to create temp directory: %v", err)
-	}
-	defer os.RemoveAll(tmpDir)
-
-	// Initialize git repository
-	cmd := exec.Command("git", "init", tmpDir)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to initialize git repository: %v", err)
-	}
-
-	// Configure git user (required for commits)
-	cmd = exec.Command("git", "-C", tmpDir, "config", "user.name", "Test User")
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to configure git user.name: %v", err)
-	}
-
-	cmd = exec.Command("git", "-C", tmpDir, "config", "user.email", "test@example.com")
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to configure git user.email: %v", err)
-	}
+	fixture := testutil.NewRepo(t)
+	tmpDir := fixture.Dir
 
 	// Create a test file
 	testFile := filepath.Join(tmpDir, "test.txt")
@@ -46,10 +26,7 @@ func TestManualCommitWorkflow(t *testing.T) {
 	}
 
 	// Stage the file
-	cmd = exec.Command("git", "-C", tmpDir, "add", testFile)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to stage file: %v", err)
-	}
+	fixture.Git("add", testFile)
 
 	// TODO: Once CLI is implemented, test the actual workflow:
 	// 1. Run gitcomm CLI
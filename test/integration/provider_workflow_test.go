@@ -0,0 +1,125 @@
+package integration
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golgoth31/gitcomm/internal/ai"
+	"github.com/golgoth31/gitcomm/internal/config"
+	"github.com/golgoth31/gitcomm/internal/model"
+	"github.com/golgoth31/gitcomm/internal/repository"
+	"github.com/golgoth31/gitcomm/internal/service"
+	"github.com/golgoth31/gitcomm/internal/ui"
+	"github.com/golgoth31/gitcomm/internal/utils"
+	"github.com/golgoth31/gitcomm/pkg/testutil"
+)
+
+// TestCreateCommit_ProviderMatrix runs the whole commit workflow, from staging to the
+// commit, against a fake API of each provider: no network access and no prompts
+func TestCreateCommit_ProviderMatrix(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+	utils.InitLogger(false)
+	ui.SetNonInteractive(true)
+	defer ui.SetNonInteractive(false)
+
+	const answer = "feat(api): add the health endpoint\n\nServe the status of the dependencies."
+
+	scenarios := []struct {
+		name         string
+		failures     []int
+		wantRequests int
+		wantDelays   []time.Duration // waits between the attempts
+		wantErr      error           // nil when the commit is created
+	}{
+		{name: "answer", wantRequests: 1},
+		{name: "transient failures retried", failures: []int{http.StatusTooManyRequests, http.StatusServiceUnavailable}, wantRequests: 3, wantDelays: []time.Duration{time.Second, 2 * time.Second}},
+		{name: "permanent failure", failures: []int{http.StatusUnauthorized}, wantRequests: 1, wantErr: utils.ErrAIProviderUnavailable},
+	}
+
+	for _, provider := range testutil.Providers {
+		for _, scenario := range scenarios {
+			t.Run(provider+"/"+scenario.name, func(t *testing.T) {
+				server := testutil.NewProviderServer(t, provider, answer)
+				server.FailWith(scenario.failures...)
+
+				// Retries record their waits and go on at once: the attempts do not depend
+				// on how loaded the machine is
+				var delays []time.Duration
+				defer ai.SetRetryTimer(func(d time.Duration) <-chan time.Time {
+					delays = append(delays, d)
+					ready := make(chan time.Time, 1)
+					ready <- time.Now()
+					return ready
+				})()
+
+				// New files are only committed with --add-all: modify a tracked one
+				fixture := testutil.NewRepoWithCommit(t, map[string]string{"api.go": "package api\n"})
+				fixture.WriteFile("api.go", "package api\n\nfunc Health() string { return \"ok\" }\n")
+				fixture.Stage("api.go")
+				gitRepo, err := repository.NewGitRepository(fixture.Dir, true, true)
+				if err != nil {
+					t.Fatalf("NewGitRepository() error = %v", err)
+				}
+
+				cfg := &config.Config{}
+				cfg.AI.DefaultProvider = provider
+				cfg.AI.Providers = map[string]model.AIProviderConfig{provider: {
+					Name:            provider,
+					APIKey:          "test-key",
+					Model:           "mock",
+					Endpoint:        server.Endpoint(),
+					Timeout:         5 * time.Second,
+					RetryAttempts:   3,
+					RetryBackoff:    time.Second,
+					RetryMaxBackoff: 10 * time.Second,
+				}}
+				options := &model.CommitOptions{NoSignoff: true}
+
+				ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+				defer cancel()
+				err = service.NewCommitService(gitRepo, options, cfg).CreateCommit(ctx)
+
+				requests := server.Requests()
+				if len(requests) != scenario.wantRequests {
+					t.Errorf("provider received %d requests, want %d", len(requests), scenario.wantRequests)
+				}
+				if !slices.Equal(delays, scenario.wantDelays) {
+					t.Errorf("waits between attempts = %v, want %v", delays, scenario.wantDelays)
+				}
+				if len(requests) > 0 && !strings.Contains(requests[0], "api.go") {
+					t.Errorf("request does not describe the staged file:\n%s", requests[0])
+				}
+
+				if scenario.wantErr != nil {
+					if !errors.Is(err, scenario.wantErr) {
+						t.Fatalf("CreateCommit() error = %v, want %v", err, scenario.wantErr)
+					}
+					if got := fixture.Git("rev-list", "--count", "HEAD"); got != "1\n" {
+						t.Errorf("HEAD has %s commits after a provider failure, want 1", strings.TrimSpace(got))
+					}
+					if staged := fixture.Git("diff", "--cached", "--name-only"); staged != "api.go\n" {
+						t.Errorf("staged files = %q, want api.go still staged", staged)
+					}
+					return
+				}
+
+				if err != nil {
+					t.Fatalf("CreateCommit() error = %v", err)
+				}
+				if got := fixture.Git("log", "-1", "--format=%B"); got != answer+"\n\n" {
+					t.Errorf("commit message = %q, want %q", got, answer+"\n\n")
+				}
+				if staged := fixture.Git("diff", "--cached", "--name-only"); staged != "" {
+					t.Errorf("staged files after the commit = %q, want none", staged)
+				}
+			})
+		}
+	}
+}
@@ -3,7 +3,6 @@ package integration
 import (
 	"context"
 	"os"
-	"os/exec"
 	"os/signal"
 	"path/filepath"
 	"syscall"
@@ -11,6 +10,7 @@ import (
 	"time"
 
 	"github.com/golgoth31/gitcomm/internal/repository"
+	"github.com/golgoth31/gitcomm/pkg/testutil"
 )
 
 // TestSignalInterruptDuringStaging tests that staging state is restored when CLI is interrupted during staging
@@ -19,29 +19,8 @@ func TestSignalInterruptDuringStaging(t *testing.T) {
 		t.Skip("Skipping integration test in short mode")
 	}
 
-	// Create a temporary git repository
-	tmpDir, err := os.MkdirTemp("", "gitcomm-signal-test-*")
-	if err != nil {
-		t.Fatalf("Failed to create temp directory: %v", err)
-	}
-	defer os.RemoveAll(tmpDir)
-
-	// Initialize git repository
-	cmd := exec.Command("git", "init", tmpDir)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to initialize git repository: %v", err)
-	}
-
-	// Configure git user
-	cmd = exec.Command("git", "-C", tmpDir, "config", "user.name", "Test User")
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to configure git user.name: %v", err)
-	}
-
-	cmd = exec.Command("git", "-C", tmpDir, "config", "user.email", "test@example.com")
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to configure git user.email: %v", err)
-	}
+	fixture := testutil.NewRepo(t)
+	tmpDir := fixture.Dir
 
 	// Create and modify a file
 	testFile := filepath.Join(tmpDir, "test.txt")
@@ -49,15 +28,9 @@ func TestSignalInterruptDuringStaging(t *testing.T) {
 		t.Fatalf("Failed to create test file: %v", err)
 	}
 
-	cmd = exec.Command("git", "-C", tmpDir, "add", testFile)
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to stage initial file: %v", err)
-	}
+	fixture.Git("add", testFile)
 
-	cmd = exec.Command("git", "-C", tmpDir, "commit", "-m", "initial commit")
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to create initial commit: %v", err)
-	}
+	fixture.Git("commit", "-m", "initial commit")
 
 	// Modify the file (now it's modified but not staged)
 	if err := os.WriteFile(testFile, []byte("modified content"), 0644); err != nil {
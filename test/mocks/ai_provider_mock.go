@@ -10,6 +10,7 @@ import (
 // MockAIProvider is a mock implementation of AIProvider for testing
 type MockAIProvider struct {
 	GenerateFunc func(ctx context.Context, repoState *model.RepositoryState) (string, error)
+	CompleteFunc func(ctx context.Context, systemMsg, userMsg string) (string, error)
 	ShouldFail   bool
 	FailError    error
 	Response     string
@@ -35,3 +36,19 @@ func (m *MockAIProvider) GenerateCommitMessage(ctx context.Context, repoState *m
 	// Default response
 	return "feat: default commit message from mock", nil
 }
+
+// Complete implements the AIProvider interface
+func (m *MockAIProvider) Complete(ctx context.Context, systemMsg, userMsg string) (string, error) {
+	if m.ShouldFail {
+		if m.FailError != nil {
+			return "", m.FailError
+		}
+		return "", errors.New("mock AI provider error")
+	}
+
+	if m.CompleteFunc != nil {
+		return m.CompleteFunc(ctx, systemMsg, userMsg)
+	}
+
+	return m.Response, nil
+}